@@ -0,0 +1,52 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// adminConfigCmd represents the admin config command
+var adminConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Prints the server configuration with all secrets redacted",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.DumpConfig(context.Background(), &v1.DumpConfigRequest{})
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(resp.Yaml)
+		return nil
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminConfigCmd)
+}
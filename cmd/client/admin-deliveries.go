@@ -0,0 +1,151 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+const webhookDeliveriesTemplate = `ID	RECEIVED	EVENT	REPOSITORY	REF	JOBS	SKIPPED/ERROR
+{{- range .Deliveries }}
+{{ .Id }}	{{ .Received | toRFC3339 }}	{{ or .Event "-" }}	{{ or .Repository "-" }}	{{ or .Ref "-" }}	{{ if .Jobs }}{{ range $i, $j := .Jobs }}{{ if $i }},{{ end }}{{ $j }}{{ end }}{{ else }}-{{ end }}	{{ or .Error .SkipReason "-" -}}
+{{ end }}
+`
+
+const webhookDeliveryTemplate = `ID:            {{ .Id }}
+GitHub ID:     {{ or .DeliveryId "-" }}
+Received:      {{ .Received | toRFC3339 }}
+Event:         {{ or .Event "-" }}
+Repository:    {{ or .Repository "-" }}
+Ref:           {{ or .Ref "-" }}
+Verified:      {{ .Verified }}
+Digest:        {{ .Digest }}
+Payload size:  {{ .PayloadSize }} bytes
+Jobs:          {{ if .Jobs }}{{ range $i, $j := .Jobs }}{{ if $i }}, {{ end }}{{ $j }}{{ end }}{{ else }}-{{ end }}
+Skip reason:   {{ or .SkipReason "-" }}
+Error:         {{ or .Error "-" }}
+{{- if .RedeliveryOf }}
+Redelivery of: {{ .RedeliveryOf }}
+{{- end }}
+Headers:
+{{- range $k, $v := .Headers }}
+  {{ $k }}: {{ $v }}
+{{- end }}
+`
+
+// adminDeliveriesCmd represents the admin deliveries command
+var adminDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "Lists the webhooks werft received",
+	Long: `Lists the webhooks werft received within the retention window, newest first, and what became of them:
+the jobs they started, why they started none or why werft failed to process them. Use this to find out why a push
+did not start a job.`,
+	Example: `  werft admin deliveries --repo 32leaves/werft --without-jobs
+  werft admin deliveries show 4f1c2a9d8e7b6a50 --payload`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, _ := cmd.Flags().GetString("repo")
+		withoutJobs, _ := cmd.Flags().GetBool("without-jobs")
+		limit, _ := cmd.Flags().GetInt32("limit")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListWebhookDeliveries(context.Background(), &v1.ListWebhookDeliveriesRequest{
+			Repository:  repo,
+			WithoutJobs: withoutJobs,
+			Limit:       limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header:   true,
+			Template: webhookDeliveriesTemplate,
+			Rows:     ".deliveries",
+		})
+	},
+}
+
+// adminDeliveriesShowCmd represents the admin deliveries show command
+var adminDeliveriesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Describes a webhook delivery including its headers",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		payload, _ := cmd.Flags().GetBool("payload")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.GetWebhookDelivery(context.Background(), &v1.GetWebhookDeliveryRequest{Id: args[0]})
+		if err != nil {
+			return err
+		}
+		if payload {
+			_, err = os.Stdout.Write(resp.Payload)
+			return err
+		}
+
+		return prettyPrint(resp.Delivery, webhookDeliveryTemplate)
+	},
+}
+
+// adminDeliveriesRedeliverCmd represents the admin deliveries redeliver command
+var adminDeliveriesRedeliverCmd = &cobra.Command{
+	Use:   "redeliver <id>",
+	Short: "Processes a webhook delivery again",
+	Long: `Processes the payload of a webhook delivery again as if GitHub had just sent it, e.g. once a broken repo config
+is fixed. Unlike redeliveries from GitHub, this starts new jobs even if the delivery started jobs before.
+Only deliveries with a valid signature can be redelivered. The redelivery is recorded as a delivery of its own.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.RedeliverWebhook(context.Background(), &v1.RedeliverWebhookRequest{Id: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(&v1.ListWebhookDeliveriesResponse{Deliveries: []*v1.WebhookDelivery{resp.Delivery}}, printSpec{
+			Header:   true,
+			Template: webhookDeliveriesTemplate,
+			Rows:     ".deliveries",
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminDeliveriesCmd)
+	adminDeliveriesCmd.AddCommand(adminDeliveriesShowCmd)
+	adminDeliveriesCmd.AddCommand(adminDeliveriesRedeliverCmd)
+
+	adminDeliveriesCmd.Flags().String("repo", "", "only list the deliveries of a repository, e.g. 32leaves/werft")
+	adminDeliveriesCmd.Flags().Bool("without-jobs", false, "only list the deliveries which started no job")
+	adminDeliveriesCmd.Flags().Int32("limit", 50, "list at most this many deliveries, 0 for all")
+	adminDeliveriesShowCmd.Flags().Bool("payload", false, "print the payload instead of describing the delivery")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// adminDrainCmd represents the admin drain command
+var adminDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Makes werft stop accepting new jobs",
+	Long: `Makes werft stop accepting new jobs, e.g. prior to maintenance. Jobs which are already
+running are not affected. Use --off to accept new jobs again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		off, _ := cmd.Flags().GetBool("off")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.SetDrain(context.Background(), &v1.SetDrainRequest{Drain: !off})
+		if err != nil {
+			return err
+		}
+
+		if resp.Draining {
+			fmt.Println("werft is draining and no longer accepts new jobs")
+		} else {
+			fmt.Println("werft accepts new jobs")
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminDrainCmd)
+
+	adminDrainCmd.Flags().Bool("off", false, "stop draining and accept new jobs again")
+}
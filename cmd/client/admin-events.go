@@ -0,0 +1,98 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// adminEventsCmd represents the admin events command
+var adminEventsCmd = &cobra.Command{
+	Use:   "events [name]",
+	Short: "Lists the recorded event trace",
+	Long: `Lists the status updates werft saw for jobs, oldest first. If a name is given, only the events of that job are listed.
+The server must be configured to store the event trace.`,
+	Example: `  werft admin events my-job.42
+  werft admin events --since 2h --limit 100`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := &v1.ListEventsRequest{}
+		if len(args) > 0 {
+			req.Name = args[0]
+		}
+		limit, _ := cmd.Flags().GetInt32("limit")
+		req.Limit = limit
+
+		now := time.Now()
+		if s, _ := cmd.Flags().GetString("since"); s != "" {
+			since, err := parseSince(s, now)
+			if err != nil {
+				return err
+			}
+			req.Since, err = ptypes.TimestampProto(since)
+			if err != nil {
+				return err
+			}
+		}
+		if s, _ := cmd.Flags().GetString("until"); s != "" {
+			until, err := parseSince(s, now)
+			if err != nil {
+				return xerrors.Errorf("invalid --until value %s: expected e.g. 30d, 12h or an RFC3339 date", s)
+			}
+			req.Until, err = ptypes.TimestampProto(until)
+			if err != nil {
+				return err
+			}
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListEvents(context.Background(), req)
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `TIME	NAME	PHASE	SUCCESS	DETAILS
+{{- range .Events }}
+{{ .Time | toRFC3339 }}	{{ .Name }}	{{ .Status.Phase }}	{{ .Status.Conditions.Success }}	{{ or .Status.Details "-" -}}
+{{ end }}
+`,
+			Rows: ".events",
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminEventsCmd)
+
+	adminEventsCmd.Flags().String("since", "", "only list events since, e.g. 30d, 12h or 2020-01-02T15:04:05Z")
+	adminEventsCmd.Flags().String("until", "", "only list events until, e.g. 1h or 2020-01-02T15:04:05Z")
+	adminEventsCmd.Flags().Int32("limit", 0, "list at most this many events")
+}
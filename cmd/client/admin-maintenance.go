@@ -0,0 +1,92 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// adminMaintenanceCmd represents the admin maintenance command
+var adminMaintenanceCmd = &cobra.Command{
+	Use:   "maintenance <on|off>",
+	Short: "Starts or ends maintenance",
+	Long: `Starts or ends maintenance, e.g. for cluster upgrades. During maintenance werft does not start new jobs.
+Triggered jobs (e.g. from GitHub pushes) are rejected, unless --queue is set in which case they are
+started once maintenance ends. Use --stop-running to also stop all jobs which are currently running.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"on", "off"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var enabled bool
+		switch args[0] {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			return fmt.Errorf("unknown argument \"%s\": must be on or off", args[0])
+		}
+		message, _ := cmd.Flags().GetString("message")
+		queue, _ := cmd.Flags().GetBool("queue")
+		stopRunning, _ := cmd.Flags().GetBool("stop-running")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.SetMaintenance(context.Background(), &v1.SetMaintenanceRequest{
+			Enabled:       enabled,
+			Message:       message,
+			QueueTriggers: queue,
+			StopRunning:   stopRunning,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Mode != nil && resp.Mode.Enabled {
+			fmt.Println("werft is in maintenance and no longer starts new jobs")
+		} else {
+			fmt.Println("werft accepts new jobs")
+		}
+		if len(resp.Stopped) > 0 {
+			fmt.Printf("stopped %d jobs: %s\n", len(resp.Stopped), strings.Join(resp.Stopped, ", "))
+		}
+		if len(resp.Started) > 0 {
+			fmt.Printf("started %d queued jobs: %s\n", len(resp.Started), strings.Join(resp.Started, ", "))
+		}
+		if len(resp.Running) > 0 {
+			fmt.Printf("%d jobs are still running: %s\n", len(resp.Running), strings.Join(resp.Running, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminMaintenanceCmd)
+
+	adminMaintenanceCmd.Flags().StringP("message", "m", "", "message shown to users during maintenance")
+	adminMaintenanceCmd.Flags().Bool("queue", false, "queue triggered jobs and start them once maintenance ends, instead of rejecting them")
+	adminMaintenanceCmd.Flags().Bool("stop-running", false, "stop all running jobs")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+import (
+	"context"
+	"strconv"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+const numberGroupsTemplate = `NAME	LATEST
+{{- range .Groups }}
+{{ .Name }}	{{ .Latest -}}
+{{ end }}
+`
+
+// adminNumberGroupsCmd represents the admin number-groups command
+var adminNumberGroupsCmd = &cobra.Command{
+	Use:   "number-groups",
+	Short: "Lists the counters job names and build numbers are drawn from",
+	Long: `Lists the number groups and their latest number. Job names are numbered per repository, job spec and branch,
+e.g. werft-build-master.42. Groups starting with repo: count all jobs of a repository for their build number.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListNumberGroups(context.Background(), &v1.ListNumberGroupsRequest{})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header:   true,
+			Template: numberGroupsTemplate,
+			Rows:     ".groups",
+		})
+	},
+}
+
+// adminNumberGroupsResetCmd represents the admin number-groups reset command
+var adminNumberGroupsResetCmd = &cobra.Command{
+	Use:   "reset <name> <latest>",
+	Short: "Sets the latest number of a group",
+	Long: `Sets the latest number of a group, s.t. the next job gets latest+1. Use -1 to start from zero again.
+Resetting a group below the number of an existing job requires --force, as the next jobs would overwrite the existing ones.`,
+	Example: "  werft admin number-groups reset werft-build-master 99",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		latest, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return xerrors.Errorf("latest must be a number: %w", err)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ResetNumberGroup(context.Background(), &v1.ResetNumberGroupRequest{
+			Name:   args[0],
+			Latest: latest,
+			Force:  force,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(&v1.ListNumberGroupsResponse{Groups: []*v1.NumberGroup{resp.Group}}, printSpec{
+			Header:   true,
+			Template: numberGroupsTemplate,
+			Rows:     ".groups",
+		})
+	},
+}
+
+// adminNumberGroupsDeleteCmd represents the admin number-groups delete command
+var adminNumberGroupsDeleteCmd = &cobra.Command{
+	Use:   "delete [name...]",
+	Short: "Removes number groups",
+	Long: `Removes the named number groups. Without names, removes the groups no job refers to anymore, e.g. those
+of removed repositories or pruned branches. The next job of a removed group starts from zero again.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.DeleteNumberGroups(context.Background(), &v1.DeleteNumberGroupsRequest{
+			Names:  args,
+			DryRun: dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `NAME
+{{- range .Names }}
+{{ . -}}
+{{ end }}
+`,
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminNumberGroupsCmd)
+	adminNumberGroupsCmd.AddCommand(adminNumberGroupsResetCmd)
+	adminNumberGroupsCmd.AddCommand(adminNumberGroupsDeleteCmd)
+
+	adminNumberGroupsResetCmd.Flags().Bool("force", false, "reset the group even if there are jobs with a higher number")
+	adminNumberGroupsDeleteCmd.Flags().Bool("dry-run", false, "only list the groups which would be removed")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// adminPruneCmd represents the admin prune command
+var adminPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Removes old jobs and their logs",
+	Long: `Removes jobs which finished longer ago than --older-than, including their logs.
+This cannot be undone - use --dry-run to see which jobs would be removed. Pinned jobs
+are never removed (see werft job pin).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if olderThan <= 0 {
+			return xerrors.Errorf("--older-than is required")
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.Prune(context.Background(), &v1.PruneRequest{
+			OlderThan: ptypes.DurationProto(olderThan),
+			DryRun:    dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `NAME
+{{- range .Names }}
+{{ . -}}
+{{ end }}
+{{- if .Pinned }}
+Kept {{ len .Pinned }} pinned job(s)
+{{- end }}
+`,
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminPruneCmd)
+
+	adminPruneCmd.Flags().Duration("older-than", 0, "remove jobs which finished longer ago than this, e.g. 720h")
+	adminPruneCmd.Flags().Bool("dry-run", false, "only list the jobs which would be removed")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// adminRepositoriesCmd represents the admin repositories command
+var adminRepositoriesCmd = &cobra.Command{
+	Use:   "repositories",
+	Short: "Lists the registered repositories and their settings",
+	Long: `Lists the registered repositories and their settings. In operator mode repositories are
+registered using WerftRepository resources, in which case the source names the resource.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListRepositories(context.Background(), &v1.ListRepositoriesRequest{})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `REPOSITORY	SECRETS	REGISTRIES	ALLOWED REFS	ALLOWED TRIGGERS	SOURCE
+{{- range .Repositories }}
+{{ .Owner }}/{{ .Repo }}	{{ range $i, $s := .Secrets }}{{ if $i }},{{ end }}{{ $s.Secret }}{{ end }}	{{ range $i, $r := .Registries }}{{ if $i }},{{ end }}{{ $r.Registry }}{{ end }}	{{ with .Policy }}{{ range $i, $r := .AllowedRefs }}{{ if $i }},{{ end }}{{ $r }}{{ end }}{{ end }}	{{ with .Policy }}{{ range $i, $t := .AllowedTriggers }}{{ if $i }},{{ end }}{{ $t }}{{ end }}{{ end }}	{{ .Source -}}
+{{ end }}
+`,
+			Rows: ".repositories",
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminRepositoriesCmd)
+}
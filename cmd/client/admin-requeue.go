@@ -0,0 +1,78 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+)
+
+// adminRequeueCmd represents the admin requeue command
+var adminRequeueCmd = &cobra.Command{
+	Use:   "requeue",
+	Short: "Fails and restarts stuck jobs",
+	Long: `Finds jobs which have not finished but whose pod is gone, e.g. because werft
+was down while the job finished. Those jobs are marked as failed and, if they can be
+replayed, are started again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.RequeueStuckJobs(context.Background(), &v1.RequeueStuckJobsRequest{
+			OlderThan: ptypes.DurationProto(olderThan),
+			DryRun:    dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `NAME	PHASE	CREATED
+{{- range .Stuck }}
+{{ .Name }}	{{ .Phase }}	{{ .Metadata.Created | toRFC3339 -}}
+{{ end }}
+{{ if .Restarted }}
+RESTARTED AS
+{{- range .Restarted }}
+{{ . -}}
+{{ end }}
+{{ end -}}
+`,
+			Rows: ".stuck",
+		})
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminRequeueCmd)
+
+	adminRequeueCmd.Flags().Duration("older-than", 1*time.Hour, "only consider jobs started longer ago than this")
+	adminRequeueCmd.Flags().Bool("dry-run", false, "only list the stuck jobs")
+}
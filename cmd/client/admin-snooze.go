@@ -0,0 +1,125 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+const snoozesTemplate = `ID	UNTIL	REASON	FILTER
+{{- range .Snoozes }}
+{{ .Id }}	{{ .Until | toRFC3339 }}	{{ or .Reason "-" }}	{{ range .Filter }}{{ range $i, $t := .Terms }}{{ if $i }} or {{ end }}{{ if $t.Negate }}not {{ end }}{{ $t.Field }} {{ $t.Operation }} {{ $t.Value }}{{ end }}{{ else }}-{{ end -}}
+{{ end }}
+`
+
+// adminSnoozeCmd represents the admin snooze command
+var adminSnoozeCmd = &cobra.Command{
+	Use:   "snooze [filter...]",
+	Short: "Silences the notifications about jobs for a while",
+	Long: `Silences all notifications about jobs matching the filter until the snooze expires or is removed.
+A job matches the filter if it matches any of its terms. Without a filter, the notifications about all jobs are silenced. Snoozes do not survive a restart of the server.`,
+	Example: `  werft admin snooze repo.ref==refs/heads/master --for 2h --reason "deploying"
+  werft admin snooze --for 30m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filterterms, err := filterexpr.Parse(args)
+		if err != nil {
+			return err
+		}
+		var filter []*v1.FilterExpression
+		if len(filterterms) > 0 {
+			filter = append(filter, &v1.FilterExpression{Terms: filterterms})
+		}
+		d, _ := cmd.Flags().GetDuration("for")
+		if d <= 0 {
+			return xerrors.Errorf("--for must be a positive duration, e.g. 2h")
+		}
+		reason, _ := cmd.Flags().GetString("reason")
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.SnoozeNotifications(context.Background(), &v1.SnoozeNotificationsRequest{
+			Filter:   filter,
+			Duration: ptypes.DurationProto(d),
+			Reason:   reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(&v1.ListSnoozesResponse{Snoozes: []*v1.NotificationSnooze{resp.Snooze}}, printSpec{
+			Header:   true,
+			Template: snoozesTemplate,
+			Rows:     ".snoozes",
+		})
+	},
+}
+
+// adminSnoozesCmd represents the admin snoozes command
+var adminSnoozesCmd = &cobra.Command{
+	Use:   "snoozes",
+	Short: "Lists the notification snoozes which have not expired yet",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListSnoozes(context.Background(), &v1.ListSnoozesRequest{})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header:   true,
+			Template: snoozesTemplate,
+			Rows:     ".snoozes",
+		})
+	},
+}
+
+// adminUnsnoozeCmd represents the admin unsnooze command
+var adminUnsnoozeCmd = &cobra.Command{
+	Use:   "unsnooze <id>",
+	Short: "Removes a notification snooze before it expires",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		_, err := client.DeleteSnooze(context.Background(), &v1.DeleteSnoozeRequest{Id: args[0]})
+		return err
+	},
+}
+
+func init() {
+	adminCmd.AddCommand(adminSnoozeCmd)
+	adminCmd.AddCommand(adminSnoozesCmd)
+	adminCmd.AddCommand(adminUnsnoozeCmd)
+
+	adminSnoozeCmd.Flags().Duration("for", 0, "how long to silence the notifications, e.g. 2h")
+	adminSnoozeCmd.Flags().String("reason", "", "why the notifications are silenced")
+}
@@ -0,0 +1,258 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// completionCacheTTL is how long we complete using the jobs we got from the server before asking it again
+	completionCacheTTL = 30 * time.Second
+
+	// completionJobLimit is the number of recent jobs we complete job names, repositories and annotations from
+	completionJobLimit = 200
+
+	// completionTimeout is how long we wait for the server before we complete nothing
+	completionTimeout = 3 * time.Second
+)
+
+// bashCompletionFunc completes job names, repositories and annotation keys using werft __complete
+const bashCompletionFunc = `__werft_complete()
+{
+    local out
+    if out=$(werft __complete "$1" 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${out}" -- "$cur" ) )
+    fi
+}
+
+__werft_complete_annotations()
+{
+    __werft_complete annotations
+    if [[ $(type -t compopt) = "builtin" ]]; then
+        compopt -o nospace
+    fi
+}
+
+__werft_custom_func() {
+    case ${last_command} in
+        werft_job_get | werft_job_describe | werft_job_logs | werft_job_open | werft_job_provenance | werft_run_previous | werft_admin_events)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __werft_complete jobs
+            fi
+            return
+            ;;
+        werft_job_annotate)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __werft_complete jobs
+            else
+                __werft_complete_annotations
+            fi
+            return
+            ;;
+        werft_stats | werft_slo | werft_run_github)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __werft_complete repos
+            fi
+            return
+            ;;
+        *)
+            ;;
+    esac
+}
+`
+
+// completionData is what we complete from. We cache it briefly, s.t. pressing tab repeatedly does not query the server every time.
+type completionData struct {
+	Time        time.Time `json:"time"`
+	Jobs        []string  `json:"jobs"`
+	Repos       []string  `json:"repos"`
+	Annotations []string  `json:"annotations"`
+}
+
+// completionCachePath returns the file we cache the completion data of the current context in
+func completionCachePath(c *ClientContext) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(c.Name + "/" + c.Host))
+	return filepath.Join(dir, "werft", fmt.Sprintf("completion-%x.json", hash[:8])), nil
+}
+
+// getCompletionData returns the completion data of the current context, from the cache if it is recent enough
+func getCompletionData() (*completionData, error) {
+	c, err := currentContext()
+	if err != nil {
+		return nil, err
+	}
+	fn, err := completionCachePath(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var data completionData
+	if fc, err := ioutil.ReadFile(fn); err == nil {
+		if json.Unmarshal(fc, &data) == nil && time.Since(data.Time) < completionCacheTTL {
+			return &data, nil
+		}
+	}
+
+	jobs, err := listCompletionJobs(c)
+	if err != nil {
+		return nil, err
+	}
+	data = completionData{Time: time.Now()}
+	var (
+		repos       = make(map[string]struct{})
+		annotations = make(map[string]struct{})
+	)
+	for _, j := range jobs {
+		data.Jobs = append(data.Jobs, j.Name)
+		md := j.Metadata
+		if md == nil {
+			continue
+		}
+		if r := md.Repository; r != nil && r.Owner != "" && r.Repo != "" {
+			repos[r.Owner+"/"+r.Repo] = struct{}{}
+		}
+		for _, a := range md.Annotations {
+			annotations[a.Key] = struct{}{}
+		}
+	}
+	for r := range repos {
+		data.Repos = append(data.Repos, r)
+	}
+	sort.Strings(data.Repos)
+	for a := range annotations {
+		data.Annotations = append(data.Annotations, a+"=")
+	}
+	sort.Strings(data.Annotations)
+
+	// failing to cache must not fail the completion
+	if fc, err := json.Marshal(data); err == nil {
+		if os.MkdirAll(filepath.Dir(fn), 0700) == nil {
+			_ = ioutil.WriteFile(fn, fc, 0600)
+		}
+	}
+	return &data, nil
+}
+
+// listCompletionJobs lists the most recent jobs. Unlike dial it gives up quickly if the server is unavailable.
+func listCompletionJobs(c *ClientContext) ([]*v1.JobStatus, error) {
+	opts, err := c.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, c.Host, append(opts, grpc.WithBlock())...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := v1.NewWerftServiceClient(conn).ListJobs(ctx, &v1.ListJobsRequest{
+		Order: []*v1.OrderExpression{{Field: "created", Ascending: false}},
+		Limit: completionJobLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh>",
+	Short: "Prints the shell completion script",
+	Long: `Prints the shell completion script. To load the completion in bash, run
+
+  source <(werft completion bash)
+
+In bash werft completes job names, repositories and annotation keys using the jobs recently run
+on the werft server. Zsh completes commands and flags only.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletion(os.Stdout)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		default:
+			return xerrors.Errorf("unsupported shell: %s", args[0])
+		}
+	},
+}
+
+// completeCmd prints the candidates the completion script completes from
+var completeCmd = &cobra.Command{
+	Use:    "__complete <jobs|repos|annotations>",
+	Hidden: true,
+	// the completion script ignores failures, hence there is no point in explaining them
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.ExactArgs(1),
+	ValidArgs:     []string{"jobs", "repos", "annotations"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := getCompletionData()
+		if err != nil {
+			return err
+		}
+
+		var res []string
+		switch args[0] {
+		case "jobs":
+			res = data.Jobs
+		case "repos":
+			res = data.Repos
+		case "annotations":
+			res = data.Annotations
+		default:
+			return xerrors.Errorf("cannot complete %s", args[0])
+		}
+		for _, r := range res {
+			fmt.Println(r)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(completeCmd)
+	rootCmd.BashCompletionFunction = bashCompletionFunc
+}
@@ -0,0 +1,344 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// ClientConfig is the werft client configuration stored in ~/.werft/config.yaml
+type ClientConfig struct {
+	Current  string          `yaml:"current,omitempty"`
+	Contexts []ClientContext `yaml:"contexts,omitempty"`
+}
+
+// ClientContext is a named werft server endpoint
+type ClientContext struct {
+	Name  string    `yaml:"name"`
+	Host  string    `yaml:"host"`
+	Token string    `yaml:"token,omitempty"`
+	TLS   TLSConfig `yaml:"tls,omitempty"`
+}
+
+// TLSConfig configures how we connect to a werft server
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled,omitempty"`
+	CACert             string `yaml:"caCert,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
+}
+
+// Get returns the context with the given name or nil if there is none
+func (cfg *ClientConfig) Get(name string) *ClientContext {
+	for i, c := range cfg.Contexts {
+		if c.Name == name {
+			return &cfg.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// clientConfigPath returns the location of the client config file
+func clientConfigPath() (string, error) {
+	if fn := os.Getenv("WERFT_CONFIG"); fn != "" {
+		return fn, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".werft", "config.yaml"), nil
+}
+
+// loadClientConfig loads the client config file. If the file does not exist an empty config is returned.
+func loadClientConfig() (*ClientConfig, error) {
+	fn, err := clientConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg ClientConfig
+	fc, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(fc, &cfg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse %s: %w", fn, err)
+	}
+	return &cfg, nil
+}
+
+// save writes the client config file. As the file contains tokens, it's only readable by the current user.
+func (cfg *ClientConfig) save() error {
+	fn, err := clientConfigPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(fn), 0700)
+	if err != nil {
+		return err
+	}
+
+	fc, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fn, fc, 0600)
+}
+
+// currentContext determines the context the CLI talks to. In order of precedence this is
+// the --host flag, the --context flag, the WERFT_HOST env var, the current context of the config file and localhost:7777.
+func currentContext() (*ClientContext, error) {
+	if host != "" {
+		return &ClientContext{Host: host}, nil
+	}
+	if h := os.Getenv("WERFT_HOST"); h != "" && contextName == "" {
+		return envContext(h), nil
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	name := contextName
+	if name == "" {
+		name = cfg.Current
+	}
+	if name == "" {
+		return &ClientContext{Host: "localhost:7777"}, nil
+	}
+
+	c := cfg.Get(name)
+	if c == nil {
+		return nil, xerrors.Errorf("unknown context: %s", name)
+	}
+	return c, nil
+}
+
+// dialOptions produces the gRPC dial options for connecting to this context
+// envContext is the context the WERFT_ environment variables describe, e.g. the one CLI plugins run in
+func envContext(host string) *ClientContext {
+	c := &ClientContext{Host: host, Token: os.Getenv("WERFT_TOKEN")}
+	c.TLS.Enabled, _ = strconv.ParseBool(os.Getenv("WERFT_TLS"))
+	c.TLS.CACert = os.Getenv("WERFT_TLS_CA_CERT")
+	c.TLS.InsecureSkipVerify, _ = strconv.ParseBool(os.Getenv("WERFT_TLS_INSECURE_SKIP_VERIFY"))
+	if c.TLS.CACert != "" || c.TLS.InsecureSkipVerify {
+		c.TLS.Enabled = true
+	}
+	return c
+}
+
+func (c *ClientContext) dialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if !c.TLS.Enabled {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		tlsCfg := &tls.Config{
+			InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+		}
+		if c.TLS.CACert != "" {
+			pem, err := ioutil.ReadFile(c.TLS.CACert)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot read CA certificate: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, xerrors.Errorf("cannot parse CA certificate %s", c.TLS.CACert)
+			}
+			tlsCfg.RootCAs = pool
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	}
+
+	if c.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{
+			Token:      c.Token,
+			RequireTLS: c.TLS.Enabled,
+		}))
+	}
+	return opts, nil
+}
+
+// tokenCredentials sends the token of a context as bearer token with each request
+type tokenCredentials struct {
+	Token      string
+	RequireTLS bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + t.Token,
+	}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.RequireTLS
+}
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manages the werft servers this CLI talks to",
+}
+
+var contextAddCmd = &cobra.Command{
+	Use:   "add <name> <host>",
+	Short: "Adds a context or updates an existing one",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadClientConfig()
+		if err != nil {
+			return err
+		}
+
+		c := ClientContext{Name: args[0], Host: args[1]}
+		c.Token, _ = cmd.Flags().GetString("token")
+		if fn, _ := cmd.Flags().GetString("token-file"); fn != "" {
+			tkn, err := ioutil.ReadFile(fn)
+			if err != nil {
+				return xerrors.Errorf("cannot read token file: %w", err)
+			}
+			c.Token = strings.TrimSpace(string(tkn))
+		}
+		c.TLS.Enabled, _ = cmd.Flags().GetBool("tls")
+		c.TLS.CACert, _ = cmd.Flags().GetString("ca-cert")
+		c.TLS.InsecureSkipVerify, _ = cmd.Flags().GetBool("insecure-skip-verify")
+		if c.TLS.CACert != "" || c.TLS.InsecureSkipVerify {
+			c.TLS.Enabled = true
+		}
+		if c.TLS.CACert != "" {
+			c.TLS.CACert, err = filepath.Abs(c.TLS.CACert)
+			if err != nil {
+				return err
+			}
+		}
+
+		if existing := cfg.Get(c.Name); existing != nil {
+			*existing = c
+		} else {
+			cfg.Contexts = append(cfg.Contexts, c)
+		}
+		if cfg.Current == "" {
+			cfg.Current = c.Name
+		}
+
+		return cfg.save()
+	},
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Sets the context the CLI talks to by default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadClientConfig()
+		if err != nil {
+			return err
+		}
+		if cfg.Get(args[0]) == nil {
+			return xerrors.Errorf("unknown context: %s", args[0])
+		}
+
+		cfg.Current = args[0]
+		return cfg.save()
+	},
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all contexts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadClientConfig()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("CURRENT\tNAME\tHOST\tTLS\tTOKEN")
+		for _, c := range cfg.Contexts {
+			var current string
+			if c.Name == cfg.Current {
+				current = "*"
+			}
+			fmt.Printf("%s\t%s\t%s\t%v\t%v\n", current, c.Name, c.Host, c.TLS.Enabled, c.Token != "")
+		}
+		return nil
+	},
+}
+
+var contextRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Removes a context",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadClientConfig()
+		if err != nil {
+			return err
+		}
+
+		var found bool
+		for i, c := range cfg.Contexts {
+			if c.Name == args[0] {
+				cfg.Contexts = append(cfg.Contexts[:i], cfg.Contexts[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return xerrors.Errorf("unknown context: %s", args[0])
+		}
+		if cfg.Current == args[0] {
+			cfg.Current = ""
+		}
+		return cfg.save()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextAddCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextRemoveCmd)
+
+	contextAddCmd.Flags().String("token", "", "token to authenticate with")
+	contextAddCmd.Flags().String("token-file", "", "file containing the token to authenticate with")
+	contextAddCmd.Flags().Bool("tls", false, "connect using TLS")
+	contextAddCmd.Flags().String("ca-cert", "", "CA certificate to verify the server certificate with (implies --tls)")
+	contextAddCmd.Flags().Bool("insecure-skip-verify", false, "do not verify the server certificate (implies --tls)")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// deploymentsCmd represents the deployments command
+var deploymentsCmd = &cobra.Command{
+	Use:   "deployments [environment]",
+	Short: "Shows what's deployed where",
+	Long: `Shows what's currently deployed to each environment and which job deployed it.
+Jobs report deployments using "werft log deployment".`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var env string
+		if len(args) > 0 {
+			env = args[0]
+		}
+		history, _ := cmd.Flags().GetBool("history")
+		limit, _ := cmd.Flags().GetUint("limit")
+		if history && env == "" {
+			return xerrors.Errorf("--history requires an environment")
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.ListDeployments(context.Background(), &v1.ListDeploymentsRequest{
+			Environment: env,
+			History:     history,
+			Limit:       int32(limit),
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `ENVIRONMENT	VERSION	URL	JOB	DEPLOYED
+{{- range .Deployments }}
+{{ .Environment }}	{{ or .Version "-" }}	{{ or .Url "-" }}	{{ .Job }}	{{ .Deployed | toRFC3339 -}}
+{{ end }}
+`,
+			Rows: ".deployments",
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deploymentsCmd)
+
+	deploymentsCmd.Flags().Bool("history", false, "lists all deployments to the environment rather than the current one")
+	deploymentsCmd.Flags().Uint("limit", 50, "number of deployments to list with --history")
+	deploymentsCmd.Flags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template, custom-columns=HEADER:.path,...")
+	deploymentsCmd.Flags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+	deploymentsCmd.Flags().BoolVar(&noHeaders, "no-headers", false, "omits the header line of tabular output")
+}
@@ -0,0 +1,69 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobAnnotateCmd represents the annotate command
+var jobAnnotateCmd = &cobra.Command{
+	Use:   "annotate <name> <key=value>...",
+	Short: "Adds or updates annotations of a job",
+	Long: `Adds or updates annotations of a job, e.g. to attach a deployment URL or ticket ID
+after the job has started. Annotations of running jobs are stored on the job's pod and
+become visible with its next status update.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var annotations []*v1.Annotation
+		for _, arg := range args[1:] {
+			segs := strings.SplitN(arg, "=", 2)
+			if len(segs) != 2 || segs[0] == "" {
+				return xerrors.Errorf("annotation %s must be in the form of key=value", arg)
+			}
+			annotations = append(annotations, &v1.Annotation{Key: segs[0], Value: segs[1]})
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx := context.Background()
+		resp, err := client.AnnotateJob(ctx, &v1.AnnotateJobRequest{
+			Name:        args[0],
+			Annotations: annotations,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp.Status, jobGetTpl)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobAnnotateCmd)
+}
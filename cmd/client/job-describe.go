@@ -0,0 +1,79 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+var jobDescribeTpl = `{{- with .Status }}` + jobGetTpl + `{{- end }}
+{{- if .Timeline }}
+Timeline:
+{{- range .Timeline }}
+  {{ .Time | toRFC3339 }}	{{ .Phase }}	{{ .Details }}
+{{- end }}
+{{- end }}
+{{- if .Events }}
+Events:
+{{- range .Events }}
+  {{ .Time | toRFC3339 }}	{{ .Type }}	{{ .Reason }}{{ if gt .Count 1 }} (x{{ .Count }}){{ end }}	{{ .Source }}	{{ .Message }}
+{{- end }}
+{{- end }}
+{{- if .Links }}
+Links:
+{{- range .Links }}
+  {{ .Name }}:	{{ .Url }}
+{{- end }}
+{{- end }}
+`
+
+// jobDescribeCmd represents the job describe command
+var jobDescribeCmd = &cobra.Command{
+	Use:   "describe <name>",
+	Short: "Shows everything there is to know about a job",
+	Long: `Shows everything there is to know about a job: its metadata, annotations, the phases it went
+through, its results, the Kubernetes events of its pod and links to the job and its commit.
+Kubernetes keeps events for a limited time only, hence older jobs show none.
+
+Use -o json or -o yaml to get the description in a machine readable format.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.DescribeJob(context.Background(), &v1.DescribeJobRequest{
+			Name: args[0],
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, jobDescribeTpl)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobDescribeCmd)
+}
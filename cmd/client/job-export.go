@@ -0,0 +1,253 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// exportPageSize is the number of jobs we request at a time when exporting
+const exportPageSize = 100
+
+// jobExportCmd represents the job export command
+var jobExportCmd = &cobra.Command{
+	Use:   "export [filter]",
+	Short: "Exports the job history as CSV or JSON",
+	Long: `Exports the job history as CSV or JSON, e.g. to report CI metrics in spreadsheets.
+Jobs can be narrowed down using the same search expressions as "werft job list".
+
+Use --output-format csv (default) or --output-format json to choose the format.
+
+For example:
+  werft job export --repo 32leaves/werft --since 30d -o csv > jobs.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := outputFormat
+		if !cmd.Flags().Changed("output-format") {
+			format = "csv"
+		}
+		if format != "csv" && format != "json" {
+			return xerrors.Errorf("export supports csv and json only, not %s", format)
+		}
+
+		filterterms, err := filterexpr.Parse(args)
+		if err != nil {
+			return err
+		}
+		var filter []*v1.FilterExpression
+		if len(filterterms) > 0 {
+			filter = append(filter, &v1.FilterExpression{Terms: filterterms})
+		}
+		if repo, _ := cmd.Flags().GetString("repo"); repo != "" {
+			segs := strings.Split(repo, "/")
+			if len(segs) > 2 {
+				return xerrors.Errorf("--repo must be in the form of owner/repo or repo")
+			}
+			if len(segs) == 2 {
+				filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "repo.owner", Value: segs[0]}}})
+			}
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "repo.repo", Value: segs[len(segs)-1]}}})
+		}
+
+		var since time.Time
+		if s, _ := cmd.Flags().GetString("since"); s != "" {
+			since, err = parseSince(s, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		var records []exportRecord
+		ctx := context.Background()
+	pages:
+		for start := 0; ; start += exportPageSize {
+			resp, err := client.ListJobs(ctx, &v1.ListJobsRequest{
+				Filter: filter,
+				Order:  []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}},
+				Start:  int32(start),
+				Limit:  exportPageSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, j := range resp.Result {
+				rec := newExportRecord(j)
+				if !since.IsZero() && rec.Created.Before(since) {
+					// jobs are ordered by creation time, hence all remaining jobs are older
+					break pages
+				}
+				records = append(records, rec)
+			}
+			if len(resp.Result) < exportPageSize {
+				break
+			}
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(records)
+		}
+		return writeExportCSV(os.Stdout, records)
+	},
+}
+
+// exportRecord is a single job in the export
+type exportRecord struct {
+	Name     string     `json:"name"`
+	Owner    string     `json:"owner"`
+	Repo     string     `json:"repo"`
+	Ref      string     `json:"ref"`
+	Revision string     `json:"revision"`
+	Trigger  string     `json:"trigger"`
+	Phase    string     `json:"phase"`
+	Success  bool       `json:"success"`
+	Created  time.Time  `json:"created"`
+	Finished *time.Time `json:"finished,omitempty"`
+	// Duration is the job runtime in seconds, zero if the job hasn't finished yet
+	Duration float64 `json:"durationSeconds"`
+	// WorkspaceUsage is the disk space the workspace of the job used in bytes, zero if unknown
+	WorkspaceUsage int64    `json:"workspaceUsageBytes"`
+	Results        []string `json:"results,omitempty"`
+}
+
+func newExportRecord(j *v1.JobStatus) exportRecord {
+	rec := exportRecord{
+		Name:           j.Name,
+		Phase:          strings.ToLower(strings.TrimPrefix(j.Phase.String(), "PHASE_")),
+		WorkspaceUsage: j.WorkspaceUsageBytes,
+	}
+	if j.Conditions != nil {
+		rec.Success = j.Conditions.Success
+	}
+	if md := j.Metadata; md != nil {
+		rec.Owner = md.Owner
+		rec.Trigger = strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_"))
+		if md.Repository != nil {
+			rec.Repo = fmt.Sprintf("%s/%s", md.Repository.Owner, md.Repository.Repo)
+			rec.Ref = md.Repository.Ref
+			rec.Revision = md.Repository.Revision
+		}
+		if md.Created != nil {
+			rec.Created, _ = ptypes.Timestamp(md.Created)
+		}
+		if finished, err := ptypes.Timestamp(md.Finished); md.Finished != nil && err == nil {
+			rec.Finished = &finished
+			if !rec.Created.IsZero() && finished.After(rec.Created) {
+				rec.Duration = finished.Sub(rec.Created).Seconds()
+			}
+		}
+	}
+	for _, r := range j.Results {
+		rec.Results = append(rec.Results, fmt.Sprintf("%s: %s", r.Type, r.Payload))
+	}
+	return rec
+}
+
+func writeExportCSV(out io.Writer, records []exportRecord) error {
+	w := csv.NewWriter(out)
+	err := w.Write([]string{"name", "owner", "repo", "ref", "revision", "trigger", "phase", "success", "created", "finished", "duration_seconds", "workspace_usage_bytes", "results"})
+	if err != nil {
+		return err
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	for _, r := range records {
+		err = w.Write([]string{
+			r.Name,
+			r.Owner,
+			r.Repo,
+			r.Ref,
+			r.Revision,
+			r.Trigger,
+			r.Phase,
+			strconv.FormatBool(r.Success),
+			formatTime(&r.Created),
+			formatTime(r.Finished),
+			strconv.FormatFloat(r.Duration, 'f', 0, 64),
+			strconv.FormatInt(r.WorkspaceUsage, 10),
+			strings.Join(r.Results, "; "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// parseSince parses durations like 30d, 12h or 2w relative to now, or an RFC3339 date
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(s, "d"), "w"))
+		if err != nil || n < 0 {
+			return time.Time{}, xerrors.Errorf("invalid --since value %s: expected e.g. 30d, 12h or an RFC3339 date", s)
+		}
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("invalid --since value %s: expected e.g. 30d, 12h or an RFC3339 date", s)
+	}
+	return now.Add(-d), nil
+}
+
+func init() {
+	jobCmd.AddCommand(jobExportCmd)
+
+	jobExportCmd.Flags().String("repo", "", "only exports jobs of this repository (owner/repo or repo)")
+	jobExportCmd.Flags().String("since", "", "only exports jobs started since, e.g. 30d, 12h or 2020-01-02T15:04:05Z")
+}
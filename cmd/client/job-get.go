@@ -30,17 +30,79 @@ import (
 var jobGetTpl = `Name:	{{ .Name }}
 Phase:	{{ .Phase }}
 Success:	{{ .Conditions.Success }}
+{{- if .Conditions.FailureClass }}
+Failure:	{{ .Conditions.FailureClass }}
+{{- end }}
+{{- if .Conditions.LogTruncated }}
+Log:	truncated
+{{- end }}
+{{- if .Conditions.Archived }}
+Archived:	true
+{{- end }}
+{{- if .Conditions.Pinned }}
+Pinned:	true
+{{- end }}
 Metadata:
+{{- if .Metadata.Number }}
+  Number:	#{{ .Metadata.Number }}
+{{- end }}
   Owner:	{{ .Metadata.Owner }}
+{{- if .Metadata.TriggeredBy }}
+  Triggered by:	{{ .Metadata.TriggeredBy }}
+{{- end }}
   Trigger:	{{ .Metadata.Trigger }}
   Started:	{{ .Metadata.Created | toRFC3339 }}
   Finished:	{{ .Metadata.Finished | toRFC3339 }}
+{{- with .Timestamps }}
+Durations:
+  Queued:	{{ between .Queued .Preparing }}
+  Preparing:	{{ between .Preparing .Running }}
+  Running:	{{ between .Running .Finished }}
+{{- end }}
 Repository:
   Host:	{{ .Metadata.Repository.Host }}
   Owner:	{{ .Metadata.Repository.Owner }}
   Repo:	{{ .Metadata.Repository.Repo }}
   Ref:	{{ .Metadata.Repository.Ref }}
   Revision:	{{ .Metadata.Repository.Revision }}
+{{- if .Metadata.Annotations }}
+Annotations:
+{{- range .Metadata.Annotations }}
+  {{ .Key }}:	{{ .Value }}
+{{- end }}
+{{- end }}
+{{- if .EstimatedFinish }}
+Estimated finish:	{{ .EstimatedFinish | toRFC3339 }} ({{ .EstimatedFinish | remaining }})
+{{- end }}
+{{- if .WorkspaceUsageBytes }}
+Workspace:	{{ .WorkspaceUsageBytes | toBytes }}
+{{- end }}
+{{- with .Environment }}
+Environment:
+{{- if .Node }}
+  Node:	{{ .Node }} ({{ .OsImage }}, kernel {{ .KernelVersion }}, {{ .Architecture }})
+  Runtime:	{{ .ContainerRuntimeVersion }}, kubelet {{ .KubeletVersion }}
+  Kubernetes:	{{ .KubernetesVersion }}
+{{- end }}
+{{- range .Images }}
+  {{ .Container }}:	{{ .Image }} ({{ .ImageId }})
+{{- end }}
+{{- end }}
+{{- with .Cleanup }}
+Cleanup:	{{ .State }} after {{ .Attempts }} attempt(s){{ if .Node }} on {{ .Node }}{{ end }}
+{{- if .Details }}
+	{{ .Details }}
+{{- end }}
+{{- end }}
+{{- if .Steps }}
+Steps:
+{{- range .Steps }}
+  {{ .Name }}:	{{ if .Outcome }}{{ .Outcome }}{{ else }}-{{ end }}	{{ between .Started .Finished }}
+{{- end }}
+{{- end }}
+{{- if .Progress }}
+Progress:	{{ .Progress.Percent }}%{{ if .Progress.TotalSteps }} ({{ .Progress.Step }}/{{ .Progress.TotalSteps }}){{ end }} {{ .Progress.Description }}
+{{- end }}
 {{- if .Results }}
 Results:
 {{- range .Results }}
@@ -22,6 +22,7 @@ package cmd
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
@@ -44,6 +45,8 @@ Available keys are:
   repo.repo   name of the source repository
   repo.host   host of the source repository (e.g. github.com)
   repo.ref    source reference, i.e. branch name
+  repo.rev    commit SHA the job ran on
+  pr          number of the pull request the job ran for
   success     one of true, false
   created     time the job started as RFC3339 date
 
@@ -60,6 +63,13 @@ For example:
   owner!==webui              finds all jobs NOT owned by webui
   repo.repo|=werft           finds all jobs on repositories whose names begin with werft
   phase==done success==true  finds all successfully finished jobs
+
+--sha and --pr are shorthands for repo.rev|=<sha> and pr==<number>, e.g. to find
+the jobs which ran for a commit.
+
+Use --output-format to control the output, e.g. -o wide, -o json or
+-o custom-columns=NAME:.name,REF:.metadata.repository.ref, and --no-headers
+to omit the table header.
 		`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filterterms, err := filterexpr.Parse(args)
@@ -69,6 +79,12 @@ For example:
 		filter := []*v1.FilterExpression{
 			&v1.FilterExpression{Terms: filterterms},
 		}
+		if sha, _ := cmd.Flags().GetString("sha"); sha != "" {
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: "repo.rev", Value: strings.ToLower(sha), Operation: v1.FilterOp_OP_STARTS_WITH}}})
+		}
+		if pr, _ := cmd.Flags().GetUint("pr"); pr > 0 {
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: "pr", Value: strconv.FormatUint(uint64(pr), 10), Operation: v1.FilterOp_OP_EQUALS}}})
+		}
 
 		useLocalContext, _ := cmd.Flags().GetBool("local")
 		if useLocalContext {
@@ -105,11 +121,20 @@ For example:
 			return err
 		}
 
-		return prettyPrint(resp, `NAME	OWNER	REPO	PHASE	SUCCESS
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `NAME	OWNER	REPO	PHASE	SUCCESS
 {{- range .Result }}
 {{ .Name }}	{{ .Metadata.Owner }}	{{ .Metadata.Repository.Owner }}/{{ .Metadata.Repository.Repo }}	{{ .Phase }}	{{ .Conditions.Success -}}
 {{ end }}
-`)
+`,
+			WideTemplate: `NAME	OWNER	REPO	REF	REVISION	TRIGGER	PHASE	SUCCESS	STARTED	FINISHED
+{{- range .Result }}
+{{ .Name }}	{{ .Metadata.Owner }}	{{ .Metadata.Repository.Owner }}/{{ .Metadata.Repository.Repo }}	{{ .Metadata.Repository.Ref }}	{{ .Metadata.Repository.Revision }}	{{ .Metadata.Trigger }}	{{ .Phase }}	{{ .Conditions.Success }}	{{ .Metadata.Created | toRFC3339 }}	{{ if .Metadata.Finished }}{{ .Metadata.Finished | toRFC3339 }}{{ else }}-{{ end -}}
+{{ end }}
+`,
+			Rows: ".result",
+		})
 	},
 }
 
@@ -136,4 +161,6 @@ func init() {
 	jobListCmd.Flags().Uint("offset", 0, "return results starting later than zero")
 	jobListCmd.Flags().StringArray("order", []string{"name:desc"}, "order the result list by fields")
 	jobListCmd.Flags().BoolP("local", "l", false, "finds jobs matching the local Git context")
+	jobListCmd.Flags().String("sha", "", "finds jobs which ran on a commit, also accepts abbreviated SHAs")
+	jobListCmd.Flags().Uint("pr", 0, "finds jobs which ran for a pull request")
 }
@@ -23,11 +23,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logarchive"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // jobLogsCmd represents the list command
@@ -41,9 +48,29 @@ var jobLogsCmd = &cobra.Command{
 		client := v1.NewWerftServiceClient(conn)
 		ctx := context.Background()
 
+		raw, _ := cmd.Flags().GetBool("raw")
+		archive, _ := cmd.Flags().GetString("archive")
+		split, _ := cmd.Flags().GetBool("split")
+		output, _ := cmd.Flags().GetString("output")
+		if output != "" && !raw && archive == "" {
+			return xerrors.Errorf("--output requires --raw or --archive")
+		}
+		if split && archive == "" {
+			return xerrors.Errorf("--split requires --archive")
+		}
+		var format logarchive.Format
+		if archive != "" {
+			var err error
+			format, err = logarchive.ParseFormat(archive)
+			if err != nil {
+				return err
+			}
+		}
+
 		var name string
 		if len(args) == 0 {
-			name, err := getLocalContextLastJobName(ctx, client)
+			var err error
+			name, err = getLocalContextLastJobName(ctx, client)
 			if err != nil {
 				return err
 			}
@@ -51,46 +78,163 @@ var jobLogsCmd = &cobra.Command{
 				return xerrors.Errorf("no job found - please specify job name")
 			}
 
-			fmt.Printf("showing logs of \033[34m\033[1m%s\t\033\033[0m\n", name)
+			fmt.Fprintf(os.Stderr, "showing logs of \033[34m\033[1m%s\t\033\033[0m\n", name)
 		} else {
 			name = args[0]
 		}
 
-		resp, err := client.Listen(ctx, &v1.ListenRequest{
-			Name:    name,
-			Logs:    v1.ListenRequestLogs_LOGS_RAW,
-			Updates: true,
-		})
-		if err != nil {
-			return err
+		if archive != "" {
+			return downloadLogArchive(ctx, client, name, output, format, split)
+		}
+		if raw {
+			return downloadLog(ctx, client, name, output)
 		}
 
-		for {
-			msg, err := resp.Recv()
-			if err != nil {
-				return err
-			}
-			if msg == nil {
-				return nil
-			}
+		logs := v1.ListenRequestLogs_LOGS_RAW
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			logs = v1.ListenRequestLogs_LOGS_PLAIN
+		}
 
+		level, _ := cmd.Flags().GetString("level")
+		req := &v1.ListenRequest{
+			Name:    name,
+			Logs:    logs,
+			Updates: true,
+			Level:   level,
+			Status:  v1.ListenRequestStatus_STATUS_DISABLED,
+		}
+		return listenWithRetry(ctx, client, req, func(msg *v1.ListenResponse) error {
 			update := msg.GetUpdate()
 			if update != nil && update.Phase == v1.JobPhase_PHASE_DONE {
 				if !update.Conditions.Success {
 					os.Exit(-1)
 				}
 
-				return nil
+				return io.EOF
 			}
 			if update != nil {
-				continue
+				return nil
 			}
 
 			pringLogSlice(msg.GetSlice())
-		}
+			return nil
+		})
 	},
 }
 
+// downloadLog downloads the complete log of a job to a file, or stdout if fn is empty. If the file exists already,
+// we resume the download from its size. Interrupted downloads are resumed from the last byte received.
+func downloadLog(ctx context.Context, client v1.WerftServiceClient, name, fn string) error {
+	out := os.Stdout
+	var offset int64
+	if fn != "" {
+		f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return xerrors.Errorf("cannot open %s: %w", fn, err)
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			return xerrors.Errorf("cannot open %s: %w", fn, err)
+		}
+		offset = stat.Size()
+		if offset > 0 {
+			fmt.Fprintf(os.Stderr, "resuming download of %s at %d bytes\n", fn, offset)
+		}
+		out = f
+	}
+
+	err := streamLog(ctx, client, name, out, offset)
+	if status.Code(err) == codes.OutOfRange && fn != "" {
+		return xerrors.Errorf("%s is larger than the log of %s - remove it to download the log again", fn, name)
+	}
+	return err
+}
+
+// downloadLogArchive downloads the complete log of a job and writes it as compressed archive to a file. If fn is empty
+// the archive is named after the job.
+func downloadLogArchive(ctx context.Context, client v1.WerftServiceClient, name, fn string, format logarchive.Format, split bool) error {
+	if fn == "" {
+		fn = name + format.Extension(split)
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		return xerrors.Errorf("cannot create %s: %w", fn, err)
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(streamLog(ctx, client, name, pw, 0))
+	}()
+	err = logarchive.Write(f, name, pr, logarchive.Options{
+		Format:  format,
+		Split:   split,
+		ModTime: time.Now(),
+	})
+	pr.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "wrote log of %s to %s\n", name, fn)
+	return nil
+}
+
+// streamLog downloads the log of a job starting at offset and writes it to out. Interrupted downloads are resumed
+// from the last byte received.
+func streamLog(ctx context.Context, client v1.WerftServiceClient, name string, out io.Writer, offset int64) error {
+	var failures int
+	for {
+		err := downloadLogOnce(ctx, client, &v1.DownloadLogRequest{Name: name, Offset: offset}, func(msg *v1.DownloadLogResponse) error {
+			failures = 0
+			if msg.Offset != offset {
+				return xerrors.Errorf("received log chunk at offset %d, expected %d", msg.Offset, offset)
+			}
+			n, err := out.Write(msg.Data)
+			offset += int64(n)
+			return err
+		})
+		if err == io.EOF {
+			return nil
+		}
+		if !isTransient(err) || failures >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		log.WithError(err).WithField("offset", offset).Warn("lost connection to werft - resuming download")
+		if werr := wait(ctx, failures); werr != nil {
+			return err
+		}
+		failures++
+	}
+}
+
+func downloadLogOnce(ctx context.Context, client v1.WerftServiceClient, req *v1.DownloadLogRequest, handler func(*v1.DownloadLogResponse) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resp, err := client.DownloadLog(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := resp.Recv()
+		if err != nil {
+			return err
+		}
+
+		err = handler(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// logTimestamps enables printing the time each log line was written
+var logTimestamps bool
+
 func pringLogSlice(slice *v1.LogSliceEvent) {
 	if slice.Name == "werft:kubernetes" || slice.Name == "werft:status" {
 		return
@@ -100,15 +244,42 @@ func pringLogSlice(slice *v1.LogSliceEvent) {
 	switch slice.Type {
 	case v1.LogSliceType_SLICE_PHASE:
 		tpl = "\033[33m\033[1m{{ .Name }}\t\033[39m{{ .Payload }}\033[0m\n"
+	case v1.LogSliceType_SLICE_PROGRESS:
+		tpl = "\033[2m[{{ .Name }}]\033[0m \033[36m{{ .Payload }}\033[0m\n"
 	case v1.LogSliceType_SLICE_CONTENT:
-		tpl = "\033[2m[{{ .Name }}]\033[0m {{ .Payload }}\n"
+		tpl = "\033[2m[{{ .Name }}]\033[0m {{ if .Level }}\033[1m{{ .Level }}\033[0m {{ end }}{{ .Payload }}{{ range $k, $v := .Fields }} \033[2m{{ $k }}=\033[0m{{ $v }}{{ end }}\n"
 	}
 	if tpl == "" {
 		return
 	}
+	if logTimestamps {
+		tpl = "\033[2m{{ if .Time }}{{ .Time | toRFC3339 }}{{ end }}\033[0m " + tpl
+	}
 	prettyPrint(slice, tpl)
 }
 
+// logSliceTimestamp returns the formatted timestamp of a log slice followed by a space if --timestamps is enabled
+func logSliceTimestamp(slice *v1.LogSliceEvent) string {
+	if !logTimestamps || slice.Time == nil {
+		return ""
+	}
+
+	ts, err := ptypes.Timestamp(slice.Time)
+	if err != nil {
+		return ""
+	}
+	return ts.Format(time.RFC3339) + " "
+}
+
 func init() {
 	jobCmd.AddCommand(jobLogsCmd)
+
+	jobLogsCmd.Flags().BoolVar(&logTimestamps, "timestamps", false, "prints the time each log line was written")
+
+	jobLogsCmd.Flags().Bool("plain", false, "strips ANSI escape sequences (e.g. colors) from the log output")
+	jobLogsCmd.Flags().String("level", "", "only shows structured log lines of at least this level (debug, info, warn, error, fatal)")
+	jobLogsCmd.Flags().Bool("raw", false, "downloads the complete log as it is stored rather than listening to it")
+	jobLogsCmd.Flags().String("archive", "", "downloads the complete log as compressed archive (gzip or zstd)")
+	jobLogsCmd.Flags().Bool("split", false, "adds a file per log slice to the archive (requires --archive)")
+	jobLogsCmd.Flags().StringP("output", "o", "", "writes the raw log or archive to a file - an existing raw log is resumed (requires --raw or --archive)")
 }
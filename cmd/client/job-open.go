@@ -0,0 +1,93 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobOpenCmd represents the job open command
+var jobOpenCmd = &cobra.Command{
+	Use:   "open <name>",
+	Short: "Opens a job in the browser",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		printOnly, _ := cmd.Flags().GetBool("print")
+		return openJob(context.Background(), client, args[0], printOnly)
+	},
+}
+
+// openJob opens the UI page of a job in the browser, or prints its URL if printOnly is true
+func openJob(ctx context.Context, client v1.WerftServiceClient, name string, printOnly bool) error {
+	info, err := client.GetServerInfo(ctx, &v1.GetServerInfoRequest{})
+	if err != nil {
+		return xerrors.Errorf("cannot get server info: %w", err)
+	}
+	if info.BaseUrl == "" {
+		return xerrors.Errorf("the werft server does not expose its URL - is werft.baseURL configured?")
+	}
+
+	url := fmt.Sprintf("%s/job/%s", strings.TrimSuffix(info.BaseUrl, "/"), name)
+	if printOnly {
+		fmt.Println(url)
+		return nil
+	}
+
+	fmt.Printf("opening %s\n", url)
+	return openBrowser(url)
+}
+
+// openBrowser opens the URL in the default browser of the system
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	err := cmd.Start()
+	if err != nil {
+		return xerrors.Errorf("cannot open browser - use --print to print the URL instead: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	jobCmd.AddCommand(jobOpenCmd)
+
+	jobOpenCmd.Flags().BoolP("print", "p", false, "prints the URL instead of opening the browser")
+}
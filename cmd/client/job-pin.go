@@ -0,0 +1,71 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobPinCmd represents the pin command
+var jobPinCmd = &cobra.Command{
+	Use:   "pin <name>",
+	Short: "Exempts a job from pruning",
+	Long: `Pins a job, s.t. werft admin prune never removes it together with its job spec, log
+and provenance, e.g. for release builds which must remain auditable.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pinJob(args[0], true)
+	},
+}
+
+// jobUnpinCmd represents the unpin command
+var jobUnpinCmd = &cobra.Command{
+	Use:   "unpin <name>",
+	Short: "Lets pruning remove a pinned job again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pinJob(args[0], false)
+	},
+}
+
+func pinJob(name string, pinned bool) error {
+	conn := dial()
+	defer conn.Close()
+	client := v1.NewWerftServiceClient(conn)
+
+	resp, err := client.PinJob(context.Background(), &v1.PinJobRequest{
+		Name:   name,
+		Pinned: pinned,
+	})
+	if err != nil {
+		return err
+	}
+
+	return prettyPrint(resp.Status, jobGetTpl)
+}
+
+func init() {
+	jobCmd.AddCommand(jobPinCmd)
+	jobCmd.AddCommand(jobUnpinCmd)
+}
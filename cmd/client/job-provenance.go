@@ -0,0 +1,98 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobProvenanceCmd represents the job provenance command
+var jobProvenanceCmd = &cobra.Command{
+	Use:   "provenance <name>",
+	Short: "Downloads the signed provenance attestation of a job",
+	Long: `Downloads the signed provenance attestation of a successful job. The attestation is an in-toto statement
+with a SLSA provenance predicate, wrapped in a DSSE envelope. Its subjects are the results of the job.
+
+Use --verify to check the signature of the attestation. By default the public key of the werft server is used,
+--key verifies against a PEM encoded public key instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx := context.Background()
+		resp, err := client.GetJobProvenance(ctx, &v1.GetJobProvenanceRequest{
+			Name: args[0],
+		})
+		if err != nil {
+			return err
+		}
+
+		if verify, _ := cmd.Flags().GetBool("verify"); verify {
+			pemkey := []byte(resp.PublicKey)
+			if fn, _ := cmd.Flags().GetString("key"); fn != "" {
+				pemkey, err = ioutil.ReadFile(fn)
+				if err != nil {
+					return err
+				}
+			}
+			key, err := provenance.ParsePublicKey(pemkey)
+			if err != nil {
+				return err
+			}
+
+			var env provenance.Envelope
+			err = json.Unmarshal(resp.Envelope, &env)
+			if err != nil {
+				return xerrors.Errorf("invalid attestation: %w", err)
+			}
+			stmt, err := provenance.Verify(&env, key)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "verified provenance of %s with %d subjects\n", args[0], len(stmt.Subject))
+		}
+
+		if fn, _ := cmd.Flags().GetString("file"); fn != "" {
+			return ioutil.WriteFile(fn, resp.Envelope, 0644)
+		}
+		_, err = os.Stdout.Write(resp.Envelope)
+		return err
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobProvenanceCmd)
+
+	jobProvenanceCmd.Flags().Bool("verify", false, "verify the signature of the attestation")
+	jobProvenanceCmd.Flags().String("key", "", "PEM encoded public key to verify the attestation with instead of the server's key")
+	jobProvenanceCmd.Flags().StringP("file", "f", "", "write the attestation to this file instead of stdout")
+}
@@ -22,6 +22,7 @@ package cmd
 
 import (
 	"os"
+	"strings"
 
 	"github.com/32leaves/werft/pkg/prettyprint"
 	"github.com/gogo/protobuf/proto"
@@ -32,6 +33,7 @@ import (
 var (
 	outputFormat   string
 	outputTemplate string
+	noHeaders      bool
 )
 
 // jobCmd represents the job command
@@ -44,26 +46,59 @@ var jobCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(jobCmd)
 
-	jobCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	jobCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template, wide, custom-columns=HEADER:.path,...")
 	jobCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+	jobCmd.PersistentFlags().BoolVar(&noHeaders, "no-headers", false, "omits the header line of tabular output")
+}
+
+// printSpec describes how a command prints its output
+type printSpec struct {
+	// Template is the default template
+	Template string
+	// WideTemplate is used for --output-format wide. Falls back to Template if empty.
+	WideTemplate string
+	// Rows is the path of the list that holds the table rows, e.g. .result. Empty if the object itself is the only row.
+	Rows string
+	// Header is true if the templates start with a header row, which --no-headers omits
+	Header bool
 }
 
 func prettyPrint(obj proto.Message, defaultTpl string) error {
-	format := prettyprint.Format(outputFormat)
-	if !prettyprint.HasFormat(format) {
-		return xerrors.Errorf("format %s is not supported", format)
+	return prettyPrintWith(obj, printSpec{Template: defaultTpl})
+}
+
+func prettyPrintWith(obj proto.Message, spec printSpec) error {
+	ctnt := &prettyprint.Content{
+		Obj:       obj,
+		Writer:    os.Stdout,
+		Template:  spec.Template,
+		NoHeaders: noHeaders,
+		HeaderRow: spec.Header,
+		Rows:      spec.Rows,
 	}
 
-	tpl := outputTemplate
-	if tpl == "" {
-		tpl = defaultTpl
+	switch {
+	case outputFormat == "wide":
+		ctnt.Format = prettyprint.TemplateFormat
+		if spec.WideTemplate != "" {
+			ctnt.Template = spec.WideTemplate
+		}
+	case strings.HasPrefix(outputFormat, string(prettyprint.CustomColumnsFormat)+"="):
+		cols, err := prettyprint.ParseCustomColumns(strings.TrimPrefix(outputFormat, string(prettyprint.CustomColumnsFormat)+"="))
+		if err != nil {
+			return err
+		}
+		ctnt.Format = prettyprint.CustomColumnsFormat
+		ctnt.Columns = cols
+	default:
+		ctnt.Format = prettyprint.Format(outputFormat)
+		if !prettyprint.HasFormat(ctnt.Format) {
+			return xerrors.Errorf("format %s is not supported", ctnt.Format)
+		}
 	}
 
-	ctnt := &prettyprint.Content{
-		Obj:      obj,
-		Format:   format,
-		Writer:   os.Stdout,
-		Template: tpl,
+	if outputTemplate != "" {
+		ctnt.Template = outputTemplate
 	}
 	return ctnt.Print()
 }
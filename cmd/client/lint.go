@@ -0,0 +1,142 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [job.yaml ...]",
+	Short: "Checks job files for problems before they're pushed",
+	Long: `Checks job files for problems before they're pushed. Each job file is executed as template
+using the local Git context as job metadata, then checked for unknown or misplaced fields and
+validated as pod spec.
+
+Without arguments the job file the werft config of the current directory points to is checked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		triggerName, _ := cmd.Flags().GetString("trigger")
+		trigger, ok := v1.JobTrigger_value[fmt.Sprintf("TRIGGER_%s", strings.ToUpper(triggerName))]
+		if !ok {
+			return xerrors.Errorf("invalid value for --trigger: %s", triggerName)
+		}
+		md, err := getLocalJobContext(wd, v1.JobTrigger(trigger))
+		if err != nil {
+			log.WithError(err).Debug("cannot extract local job context - continuing with default")
+			md = &v1.JobMetadata{
+				Owner: "local",
+				Repository: &v1.Repository{
+					Host:  "unknown",
+					Owner: "none",
+					Repo:  "none",
+				},
+				Trigger: v1.JobTrigger(trigger),
+			}
+		}
+		annotations, _ := cmd.Flags().GetStringToString("annotations")
+		for k, v := range annotations {
+			md.Annotations = append(md.Annotations, &v1.Annotation{Key: k, Value: v})
+		}
+
+		files := args
+		if len(files) == 0 {
+			cfgPath, _ := cmd.Flags().GetString("config-file")
+			fn, err := defaultJobFile(cfgPath, md)
+			if err != nil {
+				return err
+			}
+			files = []string{fn}
+		}
+
+		var errors int
+		for _, fn := range files {
+			tpl, err := ioutil.ReadFile(fn)
+			if err != nil {
+				return xerrors.Errorf("cannot read job file: %w", err)
+			}
+
+			name := fmt.Sprintf("%s-lint.0", md.Repository.Repo)
+			for _, p := range repoconfig.LintJobSpec(tpl, name, md) {
+				if p.Severity == repoconfig.SeverityError {
+					errors++
+				}
+				if p.Line > 0 {
+					fmt.Printf("%s:%d: %s: %s\n", fn, p.Line, p.Severity, p.Message)
+				} else {
+					fmt.Printf("%s: %s: %s\n", fn, p.Severity, p.Message)
+				}
+			}
+		}
+		if errors > 0 {
+			os.Exit(1)
+		}
+
+		return nil
+	},
+}
+
+// defaultJobFile returns the job file a werft config points to. Paths in the config are relative to the repository root,
+// i.e. the directory containing .werft/.
+func defaultJobFile(cfgPath string, md *v1.JobMetadata) (string, error) {
+	fc, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", xerrors.Errorf("cannot read werft config: %w", err)
+	}
+
+	var cfg repoconfig.C
+	err = yaml.Unmarshal(fc, &cfg)
+	if err != nil {
+		return "", xerrors.Errorf("cannot unmarshal werft config %s: %w", cfgPath, err)
+	}
+
+	fn := cfg.TemplatePath(md)
+	if fn == "" {
+		return "", xerrors.Errorf("werft config %s does not start a job for this context", cfgPath)
+	}
+	root := filepath.Dir(filepath.Dir(cfgPath))
+	return filepath.Join(root, fn), nil
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().String("config-file", ".werft/config.yaml", "location of the werft config file used to find the default job")
+	lintCmd.Flags().String("trigger", "push", "job trigger to lint for. One of push, manual")
+	lintCmd.Flags().StringToStringP("annotations", "a", map[string]string{}, "adds an annotation to the job metadata")
+	lintCmd.Flags().SetAnnotation("annotations", cobra.BashCompCustom, []string{"__werft_complete_annotations"})
+}
@@ -0,0 +1,79 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var logDeploymentCmd = &cobra.Command{
+	Use:   "deployment <environment>",
+	Short: "logs a deployment result",
+	Long: `Logs a deployment result. Werft keeps track of deployments so that
+"werft deployments" can show what's deployed where.
+
+With --preview the environment is registered as preview environment instead. If the repo
+configures a preview teardown job, werft runs that job for the environment once the pull
+request of the branch is closed or merged.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		url, _ := cmd.Flags().GetString("url")
+		version, _ := cmd.Flags().GetString("version")
+		desc, _ := cmd.Flags().GetString("description")
+		channels, _ := cmd.Flags().GetStringArray("channels")
+		tpe := "deployment"
+		if preview, _ := cmd.Flags().GetBool("preview"); preview {
+			tpe = "preview"
+		}
+
+		payload, _ := json.Marshal(struct {
+			Environment string `json:"environment"`
+			URL         string `json:"url,omitempty"`
+			Version     string `json:"version,omitempty"`
+		}{args[0], url, version})
+
+		// the payload is JSON itself, hence we always have to use the structured result body
+		var body struct {
+			P string   `json:"payload"`
+			C []string `json:"channels,omitempty"`
+			D string   `json:"description,omitempty"`
+		}
+		body.P = string(payload)
+		body.C = channels
+		body.D = desc
+
+		msg, _ := json.Marshal(body)
+		fmt.Printf("[%s|RESULT] %s\n", tpe, string(msg))
+	},
+}
+
+func init() {
+	logCmd.AddCommand(logDeploymentCmd)
+
+	logDeploymentCmd.Flags().String("url", "", "URL of the deployment")
+	logDeploymentCmd.Flags().String("version", "", "version which was deployed")
+	logDeploymentCmd.Flags().Bool("preview", false, "registers a preview environment rather than a deployment")
+	logDeploymentCmd.Flags().StringP("description", "d", "", "result description")
+	logDeploymentCmd.Flags().StringArrayP("channels", "c", []string{}, "result channels - the repo config routes channels to GitHub, Slack, webhooks or the UI")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is prepended to the name of the executables which extend the CLI, e.g. werft-foo provides werft foo
+const pluginPrefix = "werft-"
+
+// findPlugin looks for the executable on the PATH which provides the command in args, e.g. werft-foo-bar for foo bar.
+// The longest match wins. It returns the path of the executable and the arguments it receives.
+func findPlugin(args []string) (path string, rest []string, ok bool) {
+	var names []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		names = append(names, a)
+	}
+	for i := len(names); i > 0; i-- {
+		path, err := exec.LookPath(pluginPrefix + strings.Join(names[:i], "-"))
+		if err == nil {
+			return path, args[i:], true
+		}
+	}
+	return "", nil, false
+}
+
+// runPlugin runs a plugin and exits with its exit code. The plugin finds the werft server the CLI talks to
+// in WERFT_HOST, WERFT_TOKEN and the WERFT_TLS variables, which the CLI itself honours, too.
+func runPlugin(path string, args []string) {
+	env := os.Environ()
+	if c, err := currentContext(); err == nil {
+		env = append(env,
+			"WERFT_HOST="+c.Host,
+			"WERFT_TOKEN="+c.Token,
+			"WERFT_TLS="+strconv.FormatBool(c.TLS.Enabled),
+			"WERFT_TLS_CA_CERT="+c.TLS.CACert,
+			"WERFT_TLS_INSECURE_SKIP_VERIFY="+strconv.FormatBool(c.TLS.InsecureSkipVerify),
+		)
+	}
+
+	plugin := exec.Command(path, args...)
+	plugin.Env = env
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	err := plugin.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// listPlugins returns the executables on the PATH which extend the CLI. Plugins which are shadowed
+// by another one earlier on the PATH are listed only once.
+func listPlugins() []string {
+	var (
+		res  []string
+		seen = make(map[string]struct{})
+	)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasPrefix(f.Name(), pluginPrefix) {
+				continue
+			}
+			if _, exists := seen[f.Name()]; exists {
+				continue
+			}
+			path := filepath.Join(dir, f.Name())
+			if _, err := exec.LookPath(path); err != nil {
+				// not executable
+				continue
+			}
+			seen[f.Name()] = struct{}{}
+			res = append(res, path)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return filepath.Base(res[i]) < filepath.Base(res[j]) })
+	return res
+}
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Works with the plugins which extend this CLI",
+	Long: `Plugins extend this CLI with commands of their own. A plugin is an executable on the PATH whose
+name starts with werft-, e.g. werft-release provides werft release and werft-release-notes provides
+werft release notes. Built-in commands take precedence over plugins.
+
+Plugins receive the werft server the CLI talks to in the WERFT_HOST, WERFT_TOKEN, WERFT_TLS,
+WERFT_TLS_CA_CERT and WERFT_TLS_INSECURE_SKIP_VERIFY environment variables.`,
+}
+
+// pluginListCmd represents the plugin list command
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the plugins on the PATH",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, p := range listPlugins() {
+			fmt.Println(p)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retries is the number of times we retry transient failures before giving up
+var retries int
+
+const (
+	retryInitialBackoff = 250 * time.Millisecond
+	retryMaxBackoff     = 10 * time.Second
+)
+
+// backoff returns how long to wait before the given (zero-based) retry attempt
+func backoff(attempt int) time.Duration {
+	d := retryMaxBackoff
+	if attempt < 16 {
+		d = retryInitialBackoff << uint(attempt)
+	}
+	if d > retryMaxBackoff {
+		d = retryMaxBackoff
+	}
+
+	// add up to 20% jitter so that clients which lost their connection at the same time don't reconnect in lockstep
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// isTransient returns true if the error is likely to go away when retrying, e.g. because the connection dropped
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait blocks for the backoff duration of an attempt, or until the context is done
+func wait(ctx context.Context, attempt int) error {
+	select {
+	case <-time.After(backoff(attempt)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryUnaryInterceptor retries unary calls which failed with a transient error using exponential backoff
+func retryUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if r, ok := req.(interface{ GetIdempotencyKey() string }); strings.Contains(method, "/Start") && (!ok || r.GetIdempotencyKey() == "") {
+		// starting jobs without idempotency key is not idempotent - we'd rather fail than start a job twice
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil || !isTransient(err) || attempt >= retries {
+			return err
+		}
+
+		log.WithError(err).WithField("method", method).Debug("retrying transient failure")
+		if werr := wait(ctx, attempt); werr != nil {
+			return err
+		}
+	}
+}
+
+// listenWithRetry listens to a job and passes all messages to the handler. If the stream drops, it reconnects and
+// resumes the log output from the last cursor received. The handler can return io.EOF to stop listening.
+func listenWithRetry(ctx context.Context, client v1.WerftServiceClient, req *v1.ListenRequest, handler func(*v1.ListenResponse) error) error {
+	req = proto.Clone(req).(*v1.ListenRequest)
+
+	var failures int
+	for {
+		err := listenOnce(ctx, client, req, func(msg *v1.ListenResponse) error {
+			failures = 0
+			if slice := msg.GetSlice(); slice != nil && slice.Cursor != "" {
+				req.Cursor = slice.Cursor
+			}
+			return handler(msg)
+		})
+		if err == io.EOF {
+			return nil
+		}
+		if !isTransient(err) || failures >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		log.WithError(err).Warn("lost connection to werft - reconnecting")
+		if werr := wait(ctx, failures); werr != nil {
+			return err
+		}
+		failures++
+	}
+}
+
+func listenOnce(ctx context.Context, client v1.WerftServiceClient, req *v1.ListenRequest, handler func(*v1.ListenResponse) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resp, err := client.Listen(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := resp.Recv()
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			return io.EOF
+		}
+
+		err = handler(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// idempotencyKey returns the idempotency key to start a job with. Unless the user chose one, every invocation
+// gets its own key which makes retrying the start call safe.
+func idempotencyKey(cmd *cobra.Command) string {
+	if key, _ := cmd.Flags().GetString("idempotency-key"); key != "" {
+		return key
+	}
+
+	buf := make([]byte, 16)
+	_, err := crand.Read(buf)
+	if err != nil {
+		// without key we just won't retry starting the job
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 5, "number of times transient failures (e.g. dropped connections) are retried, 0 disables retries")
+}
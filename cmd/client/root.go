@@ -30,8 +30,9 @@ import (
 )
 
 var (
-	verbose bool
-	host    string
+	verbose     bool
+	host        string
+	contextName string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -53,6 +54,15 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	if len(os.Args) > 1 {
+		// commands we do not know ourselves may be provided by a plugin
+		if cmd, _, err := rootCmd.Find(os.Args[1:]); err != nil || cmd == rootCmd {
+			if path, args, ok := findPlugin(os.Args[1:]); ok {
+				runPlugin(path, args)
+			}
+		}
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -60,17 +70,24 @@ func Execute() {
 }
 
 func init() {
-	werftHost := os.Getenv("WERFT_HOST")
-	if werftHost == "" {
-		werftHost = "localhost:7777"
-	}
-
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "en/disable verbose logging")
-	rootCmd.PersistentFlags().StringVar(&host, "host", werftHost, "werft host to talk to (defaults to WERFT_HOST env var)")
+	rootCmd.PersistentFlags().StringVar(&host, "host", "", "werft host to talk to (defaults to WERFT_HOST env var or the current context)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "context to use (see werft context)")
 }
 
 func dial() *grpc.ClientConn {
-	conn, err := grpc.Dial(host, grpc.WithInsecure())
+	c, err := currentContext()
+	if err != nil {
+		log.WithError(err).Fatal("cannot connect to werft server")
+	}
+	opts, err := c.dialOptions()
+	if err != nil {
+		log.WithError(err).Fatal("cannot connect to werft server")
+	}
+
+	opts = append(opts, grpc.WithUnaryInterceptor(retryUnaryInterceptor))
+
+	conn, err := grpc.Dial(c.Host, opts...)
 	if err != nil {
 		log.WithError(err).Fatal("cannot connect to werft server")
 	}
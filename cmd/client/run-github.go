@@ -46,7 +46,10 @@ const maxSideloadSizeBytes = 3 * 1024 * 1024
 var runGithubCmd = &cobra.Command{
 	Use:   "github [<owner>/<repo>(:ref | @revision)]",
 	Short: "starts a job from a remote repository",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Starts a job from a remote repository. Without arguments, the repository, ref and revision
+are taken from the Git remote origin and HEAD of the local checkout. The same happens if only
+<owner>/<repo> of the local checkout are given.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		flags := cmd.Parent().PersistentFlags()
 		cwd, _ := flags.GetString("cwd")
@@ -57,18 +60,32 @@ var runGithubCmd = &cobra.Command{
 		)
 		if len(args) == 0 {
 			md, err = getLocalJobContext(cwd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot determine repository from the local checkout - please specify <owner>/<repo>: %w", err)
+			}
+			if md.Repository.Host == "local" {
+				return xerrors.Errorf("cannot determine repository from the Git remote origin - please specify <owner>/<repo>")
+			}
+			warnAboutLocalChanges(cwd)
 		} else {
 			repo, err := reporef.Parse(args[0])
 			if err != nil {
 				return err
 			}
 			md = &v1.JobMetadata{
-				Owner:      repo.Owner,
 				Repository: repo,
 			}
-		}
-		if err != nil {
-			return err
+
+			// if the repository is the one we have checked out, we start the job for what we have checked out
+			if local, err := getLocalJobContext(cwd, v1.JobTrigger_TRIGGER_MANUAL); err == nil && repo.Ref == "" && repo.Revision == "" &&
+				local.Repository.Owner == repo.Owner && local.Repository.Repo == repo.Repo {
+				repo.Ref = local.Repository.Ref
+				repo.Revision = local.Repository.Revision
+				if repo.Host == "" {
+					repo.Host = local.Repository.Host
+				}
+				warnAboutLocalChanges(cwd)
+			}
 		}
 		addUserAnnotations(cmd, md)
 
@@ -86,8 +103,9 @@ var runGithubCmd = &cobra.Command{
 
 		token, _ := cmd.Flags().GetString("token")
 		req := &v1.StartGitHubJobRequest{
-			Metadata:    md,
-			GithubToken: token,
+			Metadata:       md,
+			GithubToken:    token,
+			IdempotencyKey: idempotencyKey(cmd),
 		}
 
 		req.JobPath, _ = cmd.Flags().GetString("remote-job-path")
@@ -59,7 +59,6 @@ var runLocalCmd = &cobra.Command{
 		if err != nil {
 			log.WithError(err).Warn("cannot extract local job context - continuing with default")
 			md = &v1.JobMetadata{
-				Owner: "local",
 				Repository: &v1.Repository{
 					Host:  "unknown",
 					Owner: "none",
@@ -46,9 +46,12 @@ var runPreviousJobCmd = &cobra.Command{
 		client := v1.NewWerftServiceClient(conn)
 
 		token, _ := cmd.Flags().GetString("token")
+		exact, _ := cmd.Flags().GetBool("exact")
 		req := &v1.StartFromPreviousJobRequest{
-			PreviousJob: args[0],
-			GithubToken: token,
+			PreviousJob:    args[0],
+			GithubToken:    token,
+			IdempotencyKey: idempotencyKey(cmd),
+			Exact:          exact,
 		}
 
 		ctx := context.Background()
@@ -75,4 +78,5 @@ func init() {
 	runCmd.AddCommand(runPreviousJobCmd)
 
 	runPreviousJobCmd.Flags().String("token", "", "Token to use for authorization against GitHub")
+	runPreviousJobCmd.Flags().Bool("exact", false, "run the job spec exactly as it was rendered for the old job, e.g. if it depends on the time")
 }
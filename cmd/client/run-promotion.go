@@ -0,0 +1,82 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// runPromotionCmd represents the promotion command
+var runPromotionCmd = &cobra.Command{
+	Use:   "promotion <job-name> <promotion>",
+	Short: "promotes what a successful job built without building it again",
+	Long: `Starts the promotion job configured in the repository's werft config, e.g. to re-tag the
+images a job built, copy its files to a release bucket or deploy them. The promotion job runs on the
+revision of the promoted job and finds it in the promotedJob annotation.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Parent().PersistentFlags()
+
+		annotations, _ := flags.GetStringToString("annotations")
+		keys := make([]string, 0, len(annotations))
+		for k := range annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		req := &v1.PromoteJobRequest{
+			Name:      args[0],
+			Promotion: args[1],
+		}
+		for _, k := range keys {
+			req.Annotations = append(req.Annotations, &v1.Annotation{Key: k, Value: annotations[k]})
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.PromoteJob(context.Background(), req)
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Status.Name)
+
+		follow, _ := flags.GetBool("follow")
+		withPrefix, _ := flags.GetString("follow-with-prefix")
+		if follow || withPrefix != "" {
+			err = followJob(client, resp.Status.Name, withPrefix)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	runCmd.AddCommand(runPromotionCmd)
+}
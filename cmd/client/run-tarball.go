@@ -0,0 +1,108 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// runTarballCmd represents the run tarball command
+var runTarballCmd = &cobra.Command{
+	Use:   "tarball <url>",
+	Short: "starts a job on the content of a tarball",
+	Long: `Starts a job on the content of a gzipped tarball available via HTTP(S), e.g. a release archive.
+s3://bucket/key URLs refer to publicly readable S3 objects - use a presigned URL for private ones.
+
+For example:
+  werft run tarball https://github.com/32leaves/werft/archive/v0.1.0.tar.gz --strip-components 1 --sha256 <checksum>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Parent().PersistentFlags()
+
+		md := &v1.JobMetadata{}
+		addUserAnnotations(cmd, md)
+
+		triggerName, _ := flags.GetString("trigger")
+		trigger, ok := v1.JobTrigger_value[fmt.Sprintf("TRIGGER_%s", strings.ToUpper(triggerName))]
+		if !ok {
+			return xerrors.Errorf("invalid value for --trigger: %s", triggerName)
+		}
+		md.Trigger = v1.JobTrigger(trigger)
+
+		req := &v1.StartTarballJobRequest{
+			Metadata:       md,
+			Url:            args[0],
+			IdempotencyKey: idempotencyKey(cmd),
+		}
+		req.Sha256, _ = cmd.Flags().GetString("sha256")
+		stripComponents, _ := cmd.Flags().GetInt("strip-components")
+		req.StripComponents = int32(stripComponents)
+		req.JobPath, _ = cmd.Flags().GetString("remote-job-path")
+		if fn, _ := flags.GetString("job-file"); fn != "" {
+			fc, err := ioutil.ReadFile(fn)
+			if err != nil {
+				return err
+			}
+
+			req.JobYaml = fc
+			if req.JobPath == "" {
+				req.JobPath = fn
+			}
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx := context.Background()
+		resp, err := client.StartTarballJob(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Status.Name)
+
+		follow, _ := flags.GetBool("follow")
+		withPrefix, _ := flags.GetString("follow-with-prefix")
+		if follow || withPrefix != "" {
+			err = followJob(client, resp.Status.Name, withPrefix)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	runCmd.AddCommand(runTarballCmd)
+
+	runTarballCmd.Flags().String("sha256", "", "SHA-256 checksum the tarball must match")
+	runTarballCmd.Flags().Int("strip-components", 0, "strip this many leading path components from the tarball content")
+	runTarballCmd.Flags().String("remote-job-path", "", "start the job at that path in the tarball (defaults to the default job of the werft config in the tarball)")
+}
@@ -21,18 +21,19 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"golang.org/x/xerrors"
 )
 
 // runCmd represents the run command
@@ -60,14 +61,13 @@ func getLocalJobContext(wd string, trigger v1.JobTrigger) (*v1.JobMetadata, erro
 		return nil, err
 	}
 	repo.Ref = strings.TrimSpace(string(ref))
-
-	cmd = exec.Command("git", "config", "--global", "user.name")
-	user, err := cmd.Output()
-	if err != nil {
-		return nil, xerrors.Errorf("cannot get global git user: %w", err)
+	if repo.Ref == "HEAD" {
+		// detached HEAD - the revision is all we have
+		repo.Ref = ""
 	}
 
 	cmd = exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = wd
 	origin, err := cmd.Output()
 	if err == nil {
 		err = configureRepoFromOrigin(&repo, strings.TrimSpace(string(origin)))
@@ -81,8 +81,8 @@ func getLocalJobContext(wd string, trigger v1.JobTrigger) (*v1.JobMetadata, erro
 		repo.Repo = filepath.Base(wd)
 	}
 
+	// the server makes whoever starts the job its owner
 	return &v1.JobMetadata{
-		Owner:      strings.TrimSpace(string(user)),
 		Repository: &repo,
 		Trigger:    trigger,
 	}, nil
@@ -131,16 +131,26 @@ func getLocalContextLastJobName(ctx context.Context, client v1.WerftServiceClien
 
 // configureRepoFromOrigin is very much geared towards GitHub origins in the form of:
 //     https://github.com/32leaves/werft.git
+//     git@github.com:32leaves/werft.git
 // It might work on others, but that's neither tested nor intended.
 func configureRepoFromOrigin(repo *v1.Repository, origin string) error {
-	ourl, err := url.Parse(strings.TrimSpace(string(origin)))
-	if err != nil {
-		return err
+	origin = strings.TrimSpace(origin)
+
+	var path string
+	if scp := scpLikeURL.FindStringSubmatch(origin); scp != nil {
+		// SSH remotes such as git@github.com:32leaves/werft.git
+		repo.Host = scp[1]
+		path = scp[2]
+	} else {
+		ourl, err := url.Parse(origin)
+		if err != nil {
+			return err
+		}
+		repo.Host = ourl.Hostname()
+		path = ourl.Path
 	}
 
-	repo.Host = ourl.Host
-
-	segs := strings.Split(strings.Trim(ourl.Path, "/"), "/")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
 	if len(segs) >= 2 {
 		repo.Owner = segs[0]
 		repo.Repo = strings.TrimSuffix(segs[1], ".git")
@@ -149,23 +159,39 @@ func configureRepoFromOrigin(repo *v1.Repository, origin string) error {
 	return nil
 }
 
+// scpLikeURL matches scp-like Git remotes, e.g. git@github.com:32leaves/werft.git
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):([^/].*)$`)
+
+// warnAboutLocalChanges warns if a job started for the local checkout would not contain everything that's checked out,
+// i.e. if there are uncommitted changes or HEAD was not pushed yet.
+func warnAboutLocalChanges(wd string) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = wd
+	out, err := cmd.Output()
+	if err != nil {
+		log.WithError(err).Debug("cannot check for uncommitted changes")
+	} else if len(bytes.TrimSpace(out)) > 0 {
+		log.Warn("the working tree has uncommitted changes - they will not be part of the job")
+	}
+
+	cmd = exec.Command("git", "branch", "--remotes", "--contains", "HEAD")
+	cmd.Dir = wd
+	out, err = cmd.Output()
+	if err != nil {
+		log.WithError(err).Debug("cannot check if HEAD was pushed")
+	} else if len(bytes.TrimSpace(out)) == 0 {
+		log.Warn("HEAD has not been pushed - the job will not be able to check out this revision")
+	}
+}
+
 func followJob(client v1.WerftServiceClient, name, prefix string) error {
-	ctx := context.Background()
-	logs, err := client.Listen(ctx, &v1.ListenRequest{
+	req := &v1.ListenRequest{
 		Name:    name,
 		Logs:    v1.ListenRequestLogs_LOGS_RAW,
 		Updates: true,
-	})
-	if err != nil {
-		return err
+		Status:  v1.ListenRequestStatus_STATUS_DISABLED,
 	}
-
-	for {
-		msg, err := logs.Recv()
-		if err != nil {
-			return err
-		}
-
+	return listenWithRetry(context.Background(), client, req, func(msg *v1.ListenResponse) error {
 		if update := msg.GetUpdate(); update != nil {
 			if update.Phase == v1.JobPhase_PHASE_DONE {
 				prettyPrint(update, jobGetTpl)
@@ -184,11 +210,16 @@ func followJob(client v1.WerftServiceClient, name, prefix string) error {
 				printLogSliceWithPrefix(prefix, data)
 			}
 		}
-	}
+		return nil
+	})
 }
 
-// adds the annotations from --annotation to the metadata
+// adds the annotations from --annotation and the owner from --on-behalf-of to the metadata
 func addUserAnnotations(cmd *cobra.Command, md *v1.JobMetadata) {
+	if owner, _ := runCmd.PersistentFlags().GetString("on-behalf-of"); owner != "" {
+		// the server sets triggered_by to the name of our token
+		md.Owner = owner
+	}
 	annotations, _ := runCmd.PersistentFlags().GetStringToString("annotations")
 	for k, v := range annotations {
 		md.Annotations = append(md.Annotations, &v1.Annotation{
@@ -196,6 +227,12 @@ func addUserAnnotations(cmd *cobra.Command, md *v1.JobMetadata) {
 			Value: v,
 		})
 	}
+	if prio, _ := runCmd.PersistentFlags().GetString("priority"); prio != "" {
+		md.Annotations = append(md.Annotations, &v1.Annotation{
+			Key:   "werft.sh/priority",
+			Value: prio,
+		})
+	}
 }
 
 func printLogSliceWithPrefix(prefix string, slice *v1.LogSliceEvent) {
@@ -203,17 +240,20 @@ func printLogSliceWithPrefix(prefix string, slice *v1.LogSliceEvent) {
 		return
 	}
 
+	ts := logSliceTimestamp(slice)
 	switch slice.Type {
 	case v1.LogSliceType_SLICE_PHASE:
-		fmt.Printf("[%s%s|PHASE] %s\n", prefix, slice.Name, slice.Payload)
+		fmt.Printf("[%s%s|PHASE] %s%s\n", prefix, slice.Name, ts, slice.Payload)
 	case v1.LogSliceType_SLICE_CONTENT:
-		fmt.Printf("[%s%s] %s\n", prefix, slice.Name, slice.Payload)
+		fmt.Printf("[%s%s] %s%s\n", prefix, slice.Name, ts, slice.Payload)
 	case v1.LogSliceType_SLICE_DONE:
-		fmt.Printf("[%s%s|DONE] %s\n", prefix, slice.Name, slice.Payload)
+		fmt.Printf("[%s%s|DONE] %s%s\n", prefix, slice.Name, ts, slice.Payload)
 	case v1.LogSliceType_SLICE_FAIL:
-		fmt.Printf("[%s%s|FAIL] %s\n", prefix, slice.Name, slice.Payload)
+		fmt.Printf("[%s%s|FAIL] %s%s\n", prefix, slice.Name, ts, slice.Payload)
 	case v1.LogSliceType_SLICE_RESULT:
 		fmt.Printf("[%s|RESULT] %s\n", slice.Name, slice.Payload)
+	case v1.LogSliceType_SLICE_PROGRESS:
+		fmt.Printf("[%s%s|PROGRESS] %s\n", prefix, slice.Name, slice.Payload)
 	}
 }
 
@@ -225,5 +265,10 @@ func init() {
 	runCmd.PersistentFlags().String("trigger", "manual", "job trigger. One of push, manual")
 	runCmd.PersistentFlags().BoolP("follow", "f", false, "follow the log output once the job is running")
 	runCmd.PersistentFlags().StringToStringP("annotations", "a", map[string]string{}, "adds an annotation to the job")
+	runCmd.PersistentFlags().SetAnnotation("annotations", cobra.BashCompCustom, []string{"__werft_complete_annotations"})
 	runCmd.PersistentFlags().String("follow-with-prefix", "", "prints the log output with a prefix and disbales colors - useful for starting jobs from within jobs")
+	runCmd.PersistentFlags().BoolVar(&logTimestamps, "timestamps", false, "prints the time each log line was written when following the log output")
+	runCmd.PersistentFlags().String("priority", "", "priority of the job. One of low, normal, high (defaults to what the server's priority rules decide). High requires a token with the priority scope")
+	runCmd.PersistentFlags().String("on-behalf-of", "", "starts the job on behalf of another user, who becomes its owner. Requires a token with the impersonate scope")
+	runCmd.PersistentFlags().String("idempotency-key", "", "starting the job again with the same key within an hour returns the previously started job (defaults to a random key)")
 }
@@ -0,0 +1,105 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// sloCmd represents the slo command
+var sloCmd = &cobra.Command{
+	Use:   "slo [owner/repo|owner]",
+	Short: "Shows the success rate, durations, queue latency and failure causes of each ref of a repository",
+	Long: `Shows the success rate, durations, queue latency and failure causes of each ref of a repository
+over a time window. Durations and queue latencies are estimates.
+
+If only an owner is given, all repositories of that owner are considered. If no repository
+is given, the repository of the current working directory is used.`,
+	Example: `  werft slo 32leaves/werft --ref refs/heads/master --since 7d`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, err := repoFromArgs(args)
+		if err != nil {
+			return err
+		}
+		ref, _ := cmd.Flags().GetString("ref")
+		req := &v1.GetRepoStatsRequest{
+			RepoOwner: owner,
+			RepoRepo:  repo,
+			Ref:       ref,
+		}
+
+		now := time.Now()
+		if s, _ := cmd.Flags().GetString("since"); s != "" {
+			since, err := parseSince(s, now)
+			if err != nil {
+				return err
+			}
+			req.Since, err = ptypes.TimestampProto(since)
+			if err != nil {
+				return err
+			}
+		}
+		if s, _ := cmd.Flags().GetString("until"); s != "" {
+			until, err := parseSince(s, now)
+			if err != nil {
+				return xerrors.Errorf("invalid --until value %s: expected e.g. 30d, 12h or an RFC3339 date", s)
+			}
+			req.Until, err = ptypes.TimestampProto(until)
+			if err != nil {
+				return err
+			}
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetRepoStats(context.Background(), req)
+		if err != nil {
+			return err
+		}
+
+		return prettyPrintWith(resp, printSpec{
+			Header: true,
+			Template: `REPOSITORY	REF	RUNS	SUCCESS RATE	P50 DURATION	P95 DURATION	P50 QUEUE	P95 QUEUE	P50 PREPARE	P50 RUN	FAILURE CAUSES
+{{- range .Stats }}
+{{ .Repository }}	{{ .Ref }}	{{ .Runs }}	{{ .SuccessRate | toPercent }}	{{ .P50Duration | toDuration }}	{{ .P95Duration | toDuration }}	{{ .P50QueueLatency | toDuration }}	{{ .P95QueueLatency | toDuration }}	{{ .P50PrepareDuration | toDuration }}	{{ .P50RunDuration | toDuration }}	{{ range $i, $c := .FailureCauses }}{{ if $i }}, {{ end }}{{ $c.FailureClass }}: {{ $c.Count }}{{ else }}-{{ end -}}
+{{ end }}
+`,
+			Rows: ".stats",
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sloCmd)
+
+	sloCmd.Flags().String("ref", "", "only show the statistics of this ref, e.g. refs/heads/master")
+	sloCmd.Flags().String("since", "30d", "only consider jobs which finished since, e.g. 30d, 12h or 2020-01-02T15:04:05Z")
+	sloCmd.Flags().String("until", "", "only consider jobs which finished until, e.g. 1d or 2020-01-02T15:04:05Z")
+}
@@ -0,0 +1,128 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [owner/repo|owner]",
+	Short: "Shows job duration, failure and cost statistics of a repository",
+	Long: `Shows job duration, failure and cost statistics of a repository.
+Runs of the same job on the same ref are considered the same job. A job's flakiness
+is the ratio of runs whose outcome differed from the previous run. The cost of jobs
+is shown per repository and month.
+
+If only an owner is given, all repositories of that owner are considered. If no repository
+is given, the repository of the current working directory is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, err := repoFromArgs(args)
+		if err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetUint("limit")
+		top, _ := cmd.Flags().GetUint("top")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx := context.Background()
+		resp, err := client.GetStats(ctx, &v1.GetStatsRequest{
+			RepoOwner: owner,
+			RepoRepo:  repo,
+			Limit:     int32(limit),
+			Top:       int32(top),
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `JOB	RUNS	FAILURE RATE	FLAKINESS	AVG DURATION	MAX DURATION
+{{- range .Jobs }}
+{{ .Job }}	{{ .Runs }}	{{ .FailureRate | toPercent }}	{{ .Flakiness | toPercent }}	{{ .AvgDuration | toDuration }}	{{ .MaxDuration | toDuration -}}
+{{ end }}
+{{ if .SlowestSteps }}
+SLOWEST STEPS
+JOB	STEP	RUNS	AVG DURATION	MAX DURATION
+{{- range .SlowestSteps }}
+{{ .Job }}	{{ .Step }}	{{ .Runs }}	{{ .AvgDuration | toDuration }}	{{ .MaxDuration | toDuration -}}
+{{ end }}
+{{ end -}}
+{{ if .FlakiestJobs }}
+FLAKIEST JOBS
+JOB	RUNS	FLAKINESS
+{{- range .FlakiestJobs }}
+{{ .Job }}	{{ .Runs }}	{{ .Flakiness | toPercent -}}
+{{ end }}
+{{ end -}}
+{{ if .Costs }}
+COSTS
+REPOSITORY	MONTH	RUNS	CPU HOURS	MEMORY GB HOURS	AMOUNT
+{{- range .Costs }}
+{{ .Repository }}	{{ .Month }}	{{ .Runs }}	{{ printf "%.2f" .CpuHours }}	{{ printf "%.2f" .MemoryGbHours }}	{{ printf "%.2f" .Amount }} {{ .Currency -}}
+{{ end }}
+{{ end -}}
+`)
+	},
+}
+
+// repoFromArgs returns the owner/repo or owner given as first argument, or the repository of the current working directory
+func repoFromArgs(args []string) (owner, repo string, err error) {
+	if len(args) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", "", err
+		}
+		md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+		if err != nil {
+			return "", "", xerrors.Errorf("cannot determine repository - please specify owner/repo: %w", err)
+		}
+		return md.Repository.Owner, md.Repository.Repo, nil
+	}
+
+	segs := strings.Split(args[0], "/")
+	if len(segs) > 2 || segs[0] == "" {
+		return "", "", xerrors.Errorf("repository must be in the form of owner/repo or owner")
+	}
+	owner = segs[0]
+	if len(segs) == 2 {
+		repo = segs[1]
+	}
+	return owner, repo, nil
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().Uint("limit", 500, "number of most recent jobs to analyse")
+	statsCmd.Flags().Uint("top", 10, "number of entries in the slowest steps and flakiest jobs lists")
+}
@@ -0,0 +1,207 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+const serviceAccountTokensTemplate = `ID	SERVICE ACCOUNT	SCOPES	CREATED	EXPIRES	LAST USED
+{{- range .Tokens }}
+{{ .Id }}	{{ .ServiceAccount }}	{{ range $i, $s := .Scopes }}{{ if $i }},{{ end }}{{ $s }}{{ end }}	{{ .Created | toRFC3339 }}	{{ if .Expires }}{{ .Expires | toRFC3339 }}{{ else }}-{{ end }}	{{ if .LastUsed }}{{ .LastUsed | toRFC3339 }}{{ else }}never{{ end -}}
+{{ end }}
+`
+
+const serviceAccountSecretTemplate = `ID:	{{ .Token.Id }}
+Service account:	{{ .Token.ServiceAccount }}
+Scopes:	{{ range $i, $s := .Token.Scopes }}{{ if $i }},{{ end }}{{ $s }}{{ end }}
+{{- if .Token.Expires }}
+Expires:	{{ .Token.Expires | toRFC3339 }}
+{{- end }}
+{{- if .Previous }}
+Previous token {{ .Previous.Id }} expires:	{{ .Previous.Expires | toRFC3339 }}
+{{- end }}
+Token:	{{ .Secret }}
+`
+
+// tokenCmd represents the token command
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manages the tokens of service accounts",
+	Long: `Manages long-lived tokens which belong to a service account, e.g. a deployment bot, rather than a person.
+Managing service account tokens requires a token with the admin scope. Service accounts can list and revoke their own
+tokens using --own without it.`,
+}
+
+// tokenCreateCmd represents the token create command
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Creates a token for a service account",
+	Long: `Creates a token for a service account. The token is printed only once - the server keeps just its hash.
+Tokens with a trigger:<owner>/<repo> scope may only start, stop and annotate jobs of that repository.`,
+	Example: `  werft token create --service-account deploy-bot --scopes trigger:repo/foo
+  werft token create --service-account ci --scopes trigger:32leaves/* --expires-in 2160h`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceAccount, _ := cmd.Flags().GetString("service-account")
+		if serviceAccount == "" {
+			return xerrors.Errorf("--service-account is required")
+		}
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		if len(scopes) == 0 {
+			return xerrors.Errorf("--scopes is required, e.g. trigger:owner/repo")
+		}
+		req := &v1.CreateServiceAccountTokenRequest{
+			ServiceAccount: serviceAccount,
+			Scopes:         scopes,
+		}
+		if d, _ := cmd.Flags().GetDuration("expires-in"); d > 0 {
+			req.ExpiresIn = ptypes.DurationProto(d)
+		} else if d < 0 {
+			return xerrors.Errorf("--expires-in must not be negative")
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.CreateServiceAccountToken(context.Background(), req)
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, serviceAccountSecretTemplate)
+	},
+}
+
+// tokenListCmd represents the token list command
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the tokens of service accounts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceAccount, _ := cmd.Flags().GetString("service-account")
+		own, _ := cmd.Flags().GetBool("own")
+		if own && serviceAccount != "" {
+			return xerrors.Errorf("--own and --service-account are mutually exclusive")
+		}
+
+		spec := printSpec{
+			Header:   true,
+			Template: serviceAccountTokensTemplate,
+			Rows:     ".tokens",
+		}
+		if own {
+			conn := dial()
+			defer conn.Close()
+
+			resp, err := v1.NewWerftServiceClient(conn).ListOwnTokens(context.Background(), &v1.ListOwnTokensRequest{})
+			if err != nil {
+				return err
+			}
+			return prettyPrintWith(resp, spec)
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.ListServiceAccountTokens(context.Background(), &v1.ListServiceAccountTokensRequest{
+			ServiceAccount: serviceAccount,
+		})
+		if err != nil {
+			return err
+		}
+		return prettyPrintWith(resp, spec)
+	},
+}
+
+// tokenRotateCmd represents the token rotate command
+var tokenRotateCmd = &cobra.Command{
+	Use:   "rotate <id>",
+	Short: "Replaces a service account token with a new one",
+	Long: `Creates a new token with the same service account and scopes. The old token keeps working for the grace period,
+s.t. automation can switch over to the new one.`,
+	Example: `  werft token rotate 3f2a9c01d4e5b6a7 --grace 24h`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		grace, _ := cmd.Flags().GetDuration("grace")
+		if grace < 0 {
+			return xerrors.Errorf("--grace must not be negative")
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		resp, err := client.RotateServiceAccountToken(context.Background(), &v1.RotateServiceAccountTokenRequest{
+			Id:          args[0],
+			GracePeriod: ptypes.DurationProto(grace),
+		})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, serviceAccountSecretTemplate)
+	},
+}
+
+// tokenRevokeCmd represents the token revoke command
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Makes a service account token stop working immediately",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if own, _ := cmd.Flags().GetBool("own"); own {
+			conn := dial()
+			defer conn.Close()
+
+			_, err := v1.NewWerftServiceClient(conn).RevokeOwnToken(context.Background(), &v1.RevokeOwnTokenRequest{Id: args[0]})
+			return err
+		}
+
+		conn, client := dialAdmin()
+		defer conn.Close()
+
+		_, err := client.RevokeServiceAccountToken(context.Background(), &v1.RevokeServiceAccountTokenRequest{Id: args[0]})
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCreateCmd.Flags().String("service-account", "", "name of the service account the token belongs to")
+	tokenCreateCmd.Flags().StringSlice("scopes", nil, "what the token may be used for, e.g. trigger:owner/repo, trigger, priority or admin")
+	tokenCreateCmd.Flags().Duration("expires-in", 0, "expire the token after this duration - tokens do not expire by default")
+
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenListCmd.Flags().String("service-account", "", "list only the tokens of this service account")
+	tokenListCmd.Flags().Bool("own", false, "list the tokens of the service account whose token you use")
+
+	tokenCmd.AddCommand(tokenRotateCmd)
+	tokenRotateCmd.Flags().Duration("grace", 1*time.Hour, "how long the old token keeps working")
+
+	tokenCmd.AddCommand(tokenRevokeCmd)
+	tokenRevokeCmd.Flags().Bool("own", false, "revoke a token of the service account whose token you use")
+}
@@ -0,0 +1,633 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/xerrors"
+)
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top [filter]",
+	Short: "Shows running jobs and their logs live",
+	Long: `Shows running and queued jobs live and streams the logs of the selected job.
+Jobs can be narrowed down using the same search expressions as "werft job list".
+
+Keys:
+  up/down, k/j   select a job
+  enter          stream the logs of the selected job
+  c              cancel the selected job
+  r              replay the selected job
+  q, ctrl+c      quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fd := int(os.Stdin.Fd())
+		if !terminal.IsTerminal(fd) {
+			return xerrors.Errorf("werft top requires an interactive terminal")
+		}
+
+		filterterms, err := filterexpr.Parse(args)
+		if err != nil {
+			return err
+		}
+		var filter []*v1.FilterExpression
+		if len(filterterms) > 0 {
+			filter = append(filter, &v1.FilterExpression{Terms: filterterms})
+		}
+		if local, _ := cmd.Flags().GetBool("local"); local {
+			lf, err := getLocalContextJobFilter()
+			if err != nil {
+				return xerrors.Errorf("--local requires the current working directory to be a Git repo: %w", err)
+			}
+			filter = append(filter, lf...)
+		}
+		keepFinished, _ := cmd.Flags().GetDuration("keep-finished")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		top := &topView{
+			Client:       client,
+			KeepFinished: keepFinished,
+			jobs:         make(map[string]*v1.JobStatus),
+			changed:      make(chan struct{}, 1),
+		}
+		err = top.loadJobs(ctx, filter)
+		if err != nil {
+			return err
+		}
+		sub, err := client.Subscribe(ctx, &v1.SubscribeRequest{Filter: filter})
+		if err != nil {
+			return err
+		}
+
+		oldState, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(fd, oldState)
+		// switch to the alternate screen and hide the cursor
+		fmt.Print("\033[?1049h\033[?25l")
+		defer fmt.Print("\033[?25h\033[?1049l")
+
+		errchan := make(chan error, 2)
+		// log output would garble the screen
+		log.SetOutput(ioutil.Discard)
+		defer log.SetOutput(os.Stderr)
+
+		go func() {
+			var failures int
+			for {
+				resp, err := sub.Recv()
+				if err == nil {
+					failures = 0
+					top.updateJob(resp.Result)
+					continue
+				}
+				if !isTransient(err) || failures >= retries {
+					errchan <- err
+					return
+				}
+
+				// the connection dropped - resubscribe and reload the jobs we might have missed in the meantime
+				top.setMessage("lost connection to werft - reconnecting")
+				if wait(ctx, failures) != nil {
+					errchan <- err
+					return
+				}
+				failures++
+				sub, err = client.Subscribe(ctx, &v1.SubscribeRequest{Filter: filter})
+				if err != nil {
+					continue
+				}
+				if top.loadJobs(ctx, filter) == nil {
+					top.setMessage("")
+				}
+			}
+		}()
+		keys := make(chan topKey)
+		go readKeys(os.Stdin, keys)
+
+		tick := time.NewTicker(1 * time.Second)
+		defer tick.Stop()
+		for {
+			width, height, err := terminal.GetSize(fd)
+			if err != nil {
+				return err
+			}
+			top.render(os.Stdout, width, height)
+
+			select {
+			case k := <-keys:
+				if k == keyQuit {
+					return nil
+				}
+				top.handleKey(ctx, k)
+			case <-top.changed:
+			case <-tick.C:
+			case err := <-errchan:
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+	},
+}
+
+// topKey is a key press relevant to werft top
+type topKey int
+
+const (
+	keyUnknown topKey = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyCancel
+	keyReplay
+	keyYes
+	keyNo
+	keyQuit
+)
+
+// escapeTimeout is how long we wait for the rest of an escape sequence before we take ESC as key of its own
+const escapeTimeout = 50 * time.Millisecond
+
+// readKeys translates raw terminal input to keys
+func readKeys(in io.Reader, keys chan<- topKey) {
+	input := make(chan byte)
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				close(input)
+				return
+			}
+			input <- b
+		}
+	}()
+
+	// next returns the next byte of input, or false if there's none until timeout (nil waits forever) or the input ended
+	var unread []byte
+	next := func(timeout <-chan time.Time) (byte, bool) {
+		if len(unread) > 0 {
+			b := unread[0]
+			unread = unread[1:]
+			return b, true
+		}
+		select {
+		case b, ok := <-input:
+			return b, ok
+		case <-timeout:
+			return 0, false
+		}
+	}
+
+	for {
+		b, ok := next(nil)
+		if !ok {
+			keys <- keyQuit
+			return
+		}
+
+		var k topKey
+		switch b {
+		case 'k':
+			k = keyUp
+		case 'j':
+			k = keyDown
+		case '\r', '\n':
+			k = keyEnter
+		case 'c':
+			k = keyCancel
+		case 'r':
+			k = keyReplay
+		case 'y', 'Y':
+			k = keyYes
+		case 'q', 3: // 3 is ctrl+c
+			k = keyQuit
+		case 0x1b:
+			// arrow keys are sent as ESC [ A/B, whereas nothing follows ESC pressed on its own
+			seq, ok := next(time.After(escapeTimeout))
+			if !ok {
+				k = keyNo
+				break
+			}
+			if seq != '[' {
+				unread = append(unread, seq)
+				k = keyNo
+				break
+			}
+			seq, _ = next(time.After(escapeTimeout))
+			switch seq {
+			case 'A':
+				k = keyUp
+			case 'B':
+				k = keyDown
+			}
+		default:
+			k = keyNo
+		}
+		keys <- k
+	}
+}
+
+// topLogLines is the number of log lines we keep for the selected job
+const topLogLines = 500
+
+// topView holds the state of werft top
+type topView struct {
+	Client       v1.WerftServiceClient
+	KeepFinished time.Duration
+
+	mu       sync.Mutex
+	jobs     map[string]*v1.JobStatus
+	selected string
+	pending  topKey
+	message  string
+
+	following    string
+	stopFollow   context.CancelFunc
+	logs         []string
+	logsFinished bool
+
+	changed chan struct{}
+}
+
+func (t *topView) notify() {
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (t *topView) loadJobs(ctx context.Context, filter []*v1.FilterExpression) error {
+	notDone, err := filterexpr.Parse([]string{"phase!==done"})
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.Client.ListJobs(ctx, &v1.ListJobsRequest{
+		Filter: append(filter, &v1.FilterExpression{Terms: notDone}),
+		Order:  []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}},
+		Limit:  100,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, j := range resp.Result {
+		t.updateJob(j)
+	}
+	return nil
+}
+
+func (t *topView) updateJob(j *v1.JobStatus) {
+	if j == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.jobs[j.Name] = j
+	if t.selected == "" {
+		t.selected = j.Name
+	}
+	t.mu.Unlock()
+	t.notify()
+}
+
+// visibleJobs returns the jobs shown in the list: all unfinished ones and those which finished recently.
+// Must be called with t.mu held.
+func (t *topView) visibleJobs() []*v1.JobStatus {
+	var res []*v1.JobStatus
+	for name, j := range t.jobs {
+		if j.Phase == v1.JobPhase_PHASE_DONE && j.Metadata != nil {
+			finished, err := ptypes.Timestamp(j.Metadata.Finished)
+			if err == nil && time.Since(finished) > t.KeepFinished && name != t.following {
+				delete(t.jobs, name)
+				continue
+			}
+		}
+		res = append(res, j)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		ci, cj := res[i].GetMetadata().GetCreated(), res[j].GetMetadata().GetCreated()
+		if ci.GetSeconds() != cj.GetSeconds() {
+			return ci.GetSeconds() > cj.GetSeconds()
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+func (t *topView) handleKey(ctx context.Context, k topKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending != keyUnknown {
+		action, name := t.pending, t.selected
+		t.pending = keyUnknown
+		t.message = ""
+		if k == keyYes {
+			go t.perform(ctx, action, name)
+		}
+		return
+	}
+
+	jobs := t.visibleJobs()
+	idx := -1
+	for i, j := range jobs {
+		if j.Name == t.selected {
+			idx = i
+		}
+	}
+
+	switch k {
+	case keyUp:
+		if idx > 0 {
+			t.selected = jobs[idx-1].Name
+		}
+	case keyDown:
+		if idx < len(jobs)-1 {
+			t.selected = jobs[idx+1].Name
+		}
+	case keyEnter:
+		if t.selected != "" {
+			t.follow(ctx, t.selected)
+		}
+	case keyCancel:
+		if t.selected != "" {
+			t.pending = k
+			t.message = fmt.Sprintf("cancel %s? [y/N]", t.selected)
+		}
+	case keyReplay:
+		if t.selected != "" {
+			t.pending = k
+			t.message = fmt.Sprintf("replay %s? [y/N]", t.selected)
+		}
+	}
+}
+
+// perform cancels or replays a job
+func (t *topView) perform(ctx context.Context, action topKey, name string) {
+	var msg string
+	switch action {
+	case keyCancel:
+		_, err := t.Client.StopJob(ctx, &v1.StopJobRequest{Name: name})
+		if err != nil {
+			msg = fmt.Sprintf("cannot cancel %s: %v", name, err)
+		} else {
+			msg = fmt.Sprintf("cancelled %s", name)
+		}
+	case keyReplay:
+		resp, err := t.Client.StartFromPreviousJob(ctx, &v1.StartFromPreviousJobRequest{PreviousJob: name})
+		if err != nil {
+			msg = fmt.Sprintf("cannot replay %s: %v", name, err)
+		} else {
+			msg = fmt.Sprintf("started %s", resp.Status.Name)
+			t.updateJob(resp.Status)
+		}
+	}
+
+	t.setMessage(msg)
+}
+
+// follow starts streaming the logs of a job. Must be called with t.mu held.
+func (t *topView) follow(ctx context.Context, name string) {
+	if t.stopFollow != nil {
+		t.stopFollow()
+	}
+
+	lctx, cancel := context.WithCancel(ctx)
+	t.following = name
+	t.stopFollow = cancel
+	t.logs = nil
+	t.logsFinished = false
+
+	go func() {
+		req := &v1.ListenRequest{
+			Name:   name,
+			Logs:   v1.ListenRequestLogs_LOGS_PLAIN,
+			Status: v1.ListenRequestStatus_STATUS_DISABLED,
+		}
+		err := listenWithRetry(lctx, t.Client, req, func(msg *v1.ListenResponse) error {
+			slice := msg.GetSlice()
+			if slice == nil || slice.Name == "werft:kubernetes" || slice.Name == "werft:status" {
+				return nil
+			}
+			switch slice.Type {
+			case v1.LogSliceType_SLICE_PHASE:
+				t.appendLog(name, fmt.Sprintf("\033[33m\033[1m%s\033[0m %s", slice.Name, slice.Payload))
+			case v1.LogSliceType_SLICE_CONTENT:
+				t.appendLog(name, fmt.Sprintf("\033[2m[%s]\033[0m %s", slice.Name, slice.Payload))
+			}
+			return nil
+		})
+		if err != nil && err != io.EOF {
+			if lctx.Err() == nil {
+				t.appendLog(name, fmt.Sprintf("cannot listen to logs: %v", err))
+			}
+			return
+		}
+
+		t.mu.Lock()
+		if t.following == name {
+			t.logsFinished = true
+		}
+		t.mu.Unlock()
+		t.notify()
+	}()
+}
+
+func (t *topView) setMessage(msg string) {
+	t.mu.Lock()
+	t.message = msg
+	t.mu.Unlock()
+	t.notify()
+}
+
+func (t *topView) appendLog(job, line string) {
+	t.mu.Lock()
+	if t.following == job {
+		t.logs = append(t.logs, strings.Split(line, "\n")...)
+		if len(t.logs) > topLogLines {
+			t.logs = t.logs[len(t.logs)-topLogLines:]
+		}
+	}
+	t.mu.Unlock()
+	t.notify()
+}
+
+// render draws the job list and log pane on the terminal
+func (t *topView) render(out io.Writer, width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var lines []string
+	jobs := t.visibleJobs()
+	lines = append(lines, fmt.Sprintf("\033[1mwerft top\033[0m - %d jobs - %s", len(jobs), time.Now().Format("15:04:05")))
+	lines = append(lines, fmt.Sprintf("\033[1m%-30s %-25s %-20s %-10s %s\033[0m", "NAME", "REPO", "BRANCH", "PHASE", "DURATION"))
+
+	// the job list takes at most half of the screen
+	maxJobs := height/2 - len(lines)
+	for i, j := range jobs {
+		if i >= maxJobs {
+			break
+		}
+
+		var repo, branch string
+		if md := j.Metadata; md != nil && md.Repository != nil {
+			repo = md.Repository.Owner + "/" + md.Repository.Repo
+			branch = strings.TrimPrefix(md.Repository.Ref, "refs/heads/")
+		}
+		phase := strings.ToLower(strings.TrimPrefix(j.Phase.String(), "PHASE_"))
+		if j.Phase == v1.JobPhase_PHASE_DONE {
+			if j.Conditions != nil && j.Conditions.Success {
+				phase = "success"
+			} else {
+				phase = "failed"
+			}
+		}
+
+		line := fmt.Sprintf("%-30s %-25s %-20s %-10s %s", truncate(j.Name, 30), truncate(repo, 25), truncate(branch, 20), phase, jobDuration(j))
+		line = truncate(line, width)
+		if j.Name == t.selected {
+			if pad := width - len([]rune(line)); pad > 0 {
+				line += strings.Repeat(" ", pad)
+			}
+			line = "\033[7m" + line + "\033[0m"
+		}
+		lines = append(lines, line)
+	}
+
+	title := " logs - press enter to follow the selected job "
+	if t.following != "" {
+		title = fmt.Sprintf(" logs of %s ", t.following)
+		if t.logsFinished {
+			title += "(done) "
+		}
+	}
+	lines = append(lines, "\033[2m"+truncate("──"+title+strings.Repeat("─", width), width)+"\033[0m")
+
+	// the remaining space minus the footer is used for the logs
+	logHeight := height - len(lines) - 1
+	logs := t.logs
+	if len(logs) > logHeight {
+		logs = logs[len(logs)-logHeight:]
+	}
+	for _, l := range logs {
+		lines = append(lines, truncate(l, width))
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	footer := "\033[2mq quit  ↑/↓ select  enter logs  c cancel  r replay\033[0m"
+	if t.message != "" {
+		footer = "\033[1m" + t.message + "\033[0m"
+	}
+	lines = append(lines, footer)
+
+	var buf strings.Builder
+	buf.WriteString("\033[H")
+	for i, l := range lines {
+		buf.WriteString(l)
+		buf.WriteString("\033[0m\033[K")
+		if i < len(lines)-1 {
+			// the terminal is in raw mode, hence we need to return the carriage ourselves
+			buf.WriteString("\r\n")
+		}
+	}
+	buf.WriteString("\033[J")
+	io.WriteString(out, buf.String())
+}
+
+// jobDuration returns how long a job has been running for, or took if it's done
+func jobDuration(j *v1.JobStatus) string {
+	if j.Metadata == nil || j.Metadata.Created == nil {
+		return "-"
+	}
+	created, err := ptypes.Timestamp(j.Metadata.Created)
+	if err != nil {
+		return "-"
+	}
+	end := time.Now()
+	if j.Metadata.Finished != nil {
+		if finished, err := ptypes.Timestamp(j.Metadata.Finished); err == nil && finished.After(created) {
+			end = finished
+		}
+	}
+	return end.Sub(created).Round(time.Second).String()
+}
+
+// truncate shortens s to at most n visible runes. ANSI escape sequences do not count towards the length.
+func truncate(s string, n int) string {
+	var (
+		res     strings.Builder
+		visible int
+		escape  bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			escape = true
+		case escape:
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				escape = false
+			}
+		default:
+			if visible >= n {
+				return res.String()
+			}
+			visible++
+		}
+		res.WriteRune(r)
+	}
+	return res.String()
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+
+	topCmd.Flags().BoolP("local", "l", false, "only shows jobs matching the local Git context")
+	topCmd.Flags().Duration("keep-finished", 1*time.Minute, "how long finished jobs remain in the list")
+}
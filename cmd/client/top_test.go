@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestReadKeys(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Input       string
+		Expectation []topKey
+	}{
+		{"letters", "kjcryq", []topKey{keyUp, keyDown, keyCancel, keyReplay, keyYes, keyQuit}},
+		{"enter", "\r\n", []topKey{keyEnter, keyEnter}},
+		{"arrow keys", "\x1b[A\x1b[B", []topKey{keyUp, keyDown}},
+		{"unknown escape sequence", "\x1b[C", []topKey{keyUnknown}},
+		{"escape followed by key", "\x1bk", []topKey{keyNo, keyUp}},
+		{"other keys", "n\x01", []topKey{keyNo, keyNo}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pr, pw := io.Pipe()
+			defer pw.Close()
+			keys := make(chan topKey)
+			go readKeys(pr, keys)
+			go io.WriteString(pw, test.Input)
+
+			var act []topKey
+			for len(act) < len(test.Expectation) {
+				select {
+				case k := <-keys:
+					act = append(act, k)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for keys - got %v so far", act)
+				}
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestReadKeysBareEscape(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	keys := make(chan topKey)
+	go readKeys(pr, keys)
+
+	// nothing follows the ESC, yet we must not wait for more input
+	go io.WriteString(pw, "\x1b")
+	select {
+	case k := <-keys:
+		if k != keyNo {
+			t.Errorf("expected %v, actual %v", keyNo, k)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ESC was not read as key")
+	}
+
+	pw.Close()
+	if k := <-keys; k != keyQuit {
+		t.Errorf("expected %v once the input ends, actual %v", keyQuit, k)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		Input       string
+		N           int
+		Expectation string
+	}{
+		{"hello world", 5, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 10, "hello"},
+		{"hello", 0, ""},
+		{"", 5, ""},
+		{"größer", 3, "grö"},
+		{"\033[1mbold\033[0m text", 4, "\033[1mbold\033[0m"},
+		{"\033[31mred\033[0m", 10, "\033[31mred\033[0m"},
+		{"ab\033[32mcd", 3, "ab\033[32mc"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			act := truncate(test.Input, test.N)
+			if act != test.Expectation {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestJobDuration(t *testing.T) {
+	ts := func(tme time.Time) *timestamp.Timestamp {
+		res, _ := ptypes.TimestampProto(tme)
+		return res
+	}
+	var (
+		t0  = time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+		now = time.Now()
+	)
+
+	tests := []struct {
+		Name        string
+		Job         *v1.JobStatus
+		Expectation string
+	}{
+		{"no metadata", &v1.JobStatus{}, "-"},
+		{"not created", &v1.JobStatus{Metadata: &v1.JobMetadata{}}, "-"},
+		{"invalid creation time", &v1.JobStatus{Metadata: &v1.JobMetadata{Created: &timestamp.Timestamp{Nanos: -1}}}, "-"},
+		{"finished", &v1.JobStatus{Metadata: &v1.JobMetadata{Created: ts(t0), Finished: ts(t0.Add(90*time.Second + 400*time.Millisecond))}}, "1m30s"},
+		{"running", &v1.JobStatus{Metadata: &v1.JobMetadata{Created: ts(now.Add(-10 * time.Minute))}}, "10m0s"},
+		{"finished before it was created", &v1.JobStatus{Metadata: &v1.JobMetadata{Created: ts(now.Add(-10 * time.Minute)), Finished: ts(now.Add(-time.Hour))}}, "10m0s"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := jobDuration(test.Job)
+			if act != test.Expectation {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestVisibleJobs(t *testing.T) {
+	ts := func(ago time.Duration) *timestamp.Timestamp {
+		res, _ := ptypes.TimestampProto(time.Now().Add(-ago))
+		return res
+	}
+	running := func(name string, created time.Duration) *v1.JobStatus {
+		return &v1.JobStatus{Name: name, Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Created: ts(created)}}
+	}
+	done := func(name string, created, finished time.Duration) *v1.JobStatus {
+		return &v1.JobStatus{Name: name, Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{Created: ts(created), Finished: ts(finished)}}
+	}
+
+	tests := []struct {
+		Name        string
+		Jobs        []*v1.JobStatus
+		Following   string
+		Expectation []string
+		Remaining   int
+	}{
+		{
+			Name:        "newest first",
+			Jobs:        []*v1.JobStatus{running("a.1", time.Hour), running("b.1", time.Minute), running("c.1", 10*time.Minute)},
+			Expectation: []string{"b.1", "c.1", "a.1"},
+			Remaining:   3,
+		},
+		{
+			Name:        "same creation time sorts by name",
+			Jobs:        []*v1.JobStatus{running("b.1", time.Hour), running("a.1", time.Hour)},
+			Expectation: []string{"a.1", "b.1"},
+			Remaining:   2,
+		},
+		{
+			Name:        "finished jobs expire",
+			Jobs:        []*v1.JobStatus{done("recent.1", time.Hour, 10*time.Second), done("old.1", time.Hour, 10*time.Minute), running("a.1", 2*time.Hour)},
+			Expectation: []string{"recent.1", "a.1"},
+			Remaining:   2,
+		},
+		{
+			Name:        "followed job does not expire",
+			Jobs:        []*v1.JobStatus{done("old.1", time.Hour, 10*time.Minute), done("older.1", 2*time.Hour, 20*time.Minute)},
+			Following:   "old.1",
+			Expectation: []string{"old.1"},
+			Remaining:   1,
+		},
+		{
+			Name:        "finished job without metadata",
+			Jobs:        []*v1.JobStatus{{Name: "a.1", Phase: v1.JobPhase_PHASE_DONE}},
+			Expectation: []string{"a.1"},
+			Remaining:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			top := &topView{
+				KeepFinished: time.Minute,
+				jobs:         make(map[string]*v1.JobStatus),
+				following:    test.Following,
+			}
+			for _, j := range test.Jobs {
+				top.jobs[j.Name] = j
+			}
+
+			var act []string
+			for _, j := range top.visibleJobs() {
+				act = append(act, j.Name)
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+			if len(top.jobs) != test.Remaining {
+				t.Errorf("expected %d remaining jobs, actual %d", test.Remaining, len(top.jobs))
+			}
+		})
+	}
+}
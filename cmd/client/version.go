@@ -19,8 +19,11 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -31,10 +34,36 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version of this binary",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(version)
+		if withServer, _ := cmd.Flags().GetBool("server"); !withServer {
+			fmt.Println(version)
+			return
+		}
+
+		fmt.Printf("client:\t%s (API %s)\n", version, v1.APIVersion)
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		info, err := client.GetServerInfo(ctx, &v1.GetServerInfoRequest{})
+		if err != nil {
+			fmt.Printf("server:\tunavailable (%v)\n", err)
+			return
+		}
+		fmt.Printf("server:\t%s (API %s)\n", info.Version, info.ApiVersion)
+
+		if info.ApiVersion != v1.APIVersion {
+			fmt.Printf("\033[33mwarning:\033[0m the server speaks API %s while this client speaks API %s\n", info.ApiVersion, v1.APIVersion)
+		} else if info.Version != version {
+			fmt.Printf("\033[33mwarning:\033[0m client and server versions differ - some features may not be available\n")
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().Bool("server", false, "also print the version of the server and warn if it does not match")
 }
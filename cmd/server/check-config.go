@@ -0,0 +1,67 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// checkConfigCmd represents the check-config command
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config <config.yaml>",
+	Short: "Validates a config file without starting the server",
+	Long: `Validates a config file without starting the server, e.g. before deploying a changed config map.
+
+All mistakes are listed at once, naming the offending field. Fields werft does not know are rejected as they are most
+likely typos. Referenced files, e.g. the GitHub App private key or log encryption keys, must be readable.
+Nothing is connected to: the database, the Kubernetes cluster and GitHub are not checked.
+
+With --dev the config is checked as "werft run --dev" would use it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfig(args)
+		if err != nil {
+			return err
+		}
+
+		dev, _ := cmd.Flags().GetBool("dev")
+		if dev {
+			cfg.applyDevDefaults()
+		}
+		err = cfg.validate(dev)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s is invalid:\n%s", args[0], describeConfigError(err))
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s is valid (config version %d)\n", args[0], cfg.effectiveVersion())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkConfigCmd)
+
+	checkConfigCmd.Flags().Bool("dev", false, "check the config for development mode")
+}
@@ -0,0 +1,301 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/gitcreds"
+	"github.com/32leaves/werft/pkg/operator"
+	plugin "github.com/32leaves/werft/pkg/plugin/host"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/store/postgres"
+	"github.com/32leaves/werft/pkg/werft"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion is the version of the config file format this werft understands.
+// Config files which do not state their version are version 1.
+const ConfigVersion = 1
+
+// Config configures the werft server
+type Config struct {
+	// Version is the version of the config file format, see ConfigVersion
+	Version int `yaml:"version,omitempty"`
+
+	Werft          werft.Config    `yaml:"werft"`
+	Service        ServiceConfig   `yaml:"service"`
+	Storage        StorageConfig   `yaml:"storage"`
+	Executor       executor.Config `yaml:"executor"`
+	Kubeconfig     string          `yaml:"kubeconfig,omitempty"`
+	GitHub         GitHubConfig    `yaml:"github"`
+	GitCredentials gitcreds.Config `yaml:"gitCredentials,omitempty"`
+	Plugins        plugin.Config   `yaml:"plugins"`
+	Operator       operator.Config `yaml:"operator,omitempty"`
+}
+
+// ServiceConfig configures the ports werft serves on
+type ServiceConfig struct {
+	WebPort  int `yaml:"webPort"`
+	GRPCPort int `yaml:"grpcPort"`
+	// JobSpecRepos are the repositories whose job specs the web UI offers to start, e.g. 32leaves/werft:master
+	JobSpecRepos []string `yaml:"jobSpecRepos"`
+}
+
+// StorageConfig configures where werft keeps its state
+type StorageConfig struct {
+	LogStore  string          `yaml:"logsPath"`
+	LogLimits store.LogLimits `yaml:"logLimits,omitempty"`
+	// LogEncryption encrypts logs at rest if a key is configured
+	LogEncryption store.LogEncryption `yaml:"logEncryption,omitempty"`
+	// ArchivePath is where old jobs are archived to, e.g. a mounted object storage bucket. See werft.archive.
+	ArchivePath string `yaml:"archivePath,omitempty"`
+	JobStore    string `yaml:"jobsConnectionString"`
+	// JobSpecs is where job specs are stored: with the jobs if empty, or archive to keep them in archivePath instead
+	JobSpecs string `yaml:"jobSpecs,omitempty"`
+	// JobResults is where job results are stored: with the jobs if empty, or archive to keep them in archivePath instead
+	JobResults string `yaml:"jobResults,omitempty"`
+	// Pool configures the connections to the database
+	Pool postgres.PoolConfig `yaml:"pool,omitempty"`
+	// EventTrace is where we store the event trace for querying: memory, postgres or nowhere if empty
+	EventTrace string `yaml:"eventTrace,omitempty"`
+}
+
+// GitHubConfig configures the GitHub App werft acts as
+type GitHubConfig struct {
+	WebhookSecret  string `yaml:"webhookSecret"`
+	PrivateKeyPath string `yaml:"privateKeyPath"`
+	InstallationID int64  `yaml:"installationID,omitempty"`
+	AppID          int64  `yaml:"appID"`
+}
+
+// storeInArchive keeps job specs or results in the archive rather than with the jobs
+const storeInArchive = "archive"
+
+// parseConfig parses a config file, rejecting fields we do not know - they are most likely typos
+func parseConfig(fc []byte) (Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(fc))
+	dec.KnownFields(true)
+	err := dec.Decode(&cfg)
+	if err == io.EOF {
+		// the file is empty
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Version > ConfigVersion {
+		return cfg, xerrors.Errorf("version: config version %d is newer than this werft understands (%d) - please upgrade werft", cfg.Version, ConfigVersion)
+	}
+	if cfg.Version < 0 {
+		return cfg, xerrors.Errorf("version: must not be negative")
+	}
+	return cfg, nil
+}
+
+// effectiveVersion returns the version of the config file format, defaulting to 1 for configs which do not state it
+func (cfg Config) effectiveVersion() int {
+	if cfg.Version == 0 {
+		return 1
+	}
+	return cfg.Version
+}
+
+// configErrors are all mistakes found in a config, at most one per section
+type configErrors []error
+
+func (e configErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// validate checks the config for mistakes, naming the offending field. In development mode werft needs neither
+// database nor GitHub App, hence we do not insist on their config.
+func (cfg Config) validate(dev bool) error {
+	var errs configErrors
+	for _, v := range []func(dev bool) error{
+		cfg.validateWerft,
+		cfg.validateService,
+		cfg.validateStorage,
+		cfg.validateExecutor,
+		cfg.validateGitHub,
+		cfg.validatePlugins,
+	} {
+		if err := v(dev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (cfg Config) validateWerft(dev bool) error {
+	err := cfg.Werft.Validate()
+	if err != nil {
+		return xerrors.Errorf("werft.%s", err.Error())
+	}
+	if cfg.Werft.Archive.AfterDays > 0 && cfg.Storage.ArchivePath == "" {
+		return xerrors.Errorf("werft.archive.afterDays: requires storage.archivePath")
+	}
+	return nil
+}
+
+func (cfg Config) validateService(dev bool) error {
+	ports := []struct {
+		field string
+		port  int
+	}{
+		{"webPort", cfg.Service.WebPort},
+		{"grpcPort", cfg.Service.GRPCPort},
+	}
+	for _, p := range ports {
+		if p.port == 0 && !dev {
+			return xerrors.Errorf("service.%s: required", p.field)
+		}
+		if p.port < 0 || p.port > 65535 {
+			return xerrors.Errorf("service.%s: %d is not a valid port", p.field, p.port)
+		}
+	}
+	if cfg.Service.WebPort != 0 && cfg.Service.WebPort == cfg.Service.GRPCPort {
+		return xerrors.Errorf("service.grpcPort: must differ from service.webPort")
+	}
+	for i, r := range cfg.Service.JobSpecRepos {
+		_, err := reporef.Parse(r)
+		if err != nil {
+			return xerrors.Errorf("service.jobSpecRepos[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (cfg Config) validateStorage(dev bool) error {
+	if !dev {
+		if cfg.Storage.LogStore == "" {
+			return xerrors.Errorf("storage.logsPath: required")
+		}
+		if cfg.Storage.JobStore == "" {
+			return xerrors.Errorf("storage.jobsConnectionString: required")
+		}
+	}
+	err := cfg.Storage.Pool.Validate()
+	if err != nil {
+		return xerrors.Errorf("storage.pool.%s", err.Error())
+	}
+	for _, f := range []struct{ field, where string }{{"jobSpecs", cfg.Storage.JobSpecs}, {"jobResults", cfg.Storage.JobResults}} {
+		field, where := f.field, f.where
+		switch where {
+		case "":
+		case storeInArchive:
+			if cfg.Storage.ArchivePath == "" {
+				return xerrors.Errorf("storage.%s: requires storage.archivePath", field)
+			}
+		default:
+			return xerrors.Errorf("storage.%s: unknown store \"%s\" - must be empty or %s", field, where, storeInArchive)
+		}
+	}
+	switch cfg.Storage.EventTrace {
+	case "", "memory", "postgres":
+	default:
+		return xerrors.Errorf("storage.eventTrace: unknown event trace storage \"%s\" - must be memory or postgres", cfg.Storage.EventTrace)
+	}
+	keys, err := cfg.Storage.LogEncryption.LoadKeys()
+	if err != nil {
+		return xerrors.Errorf("storage.logEncryption: %w", err)
+	}
+	if len(keys) > 0 {
+		_, err = store.NewEncryptedLogs(store.NewInMemoryLogStore(), keys)
+		if err != nil {
+			return xerrors.Errorf("storage.logEncryption: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cfg Config) validateExecutor(dev bool) error {
+	err := cfg.Executor.Validate()
+	if err != nil {
+		return xerrors.Errorf("executor.%s", err.Error())
+	}
+	if cfg.Executor.Backend == executor.BackendFake && cfg.Operator.Enabled {
+		return xerrors.Errorf("operator.enabled: operator mode needs a Kubernetes cluster and is not available with the fake executor")
+	}
+	if cfg.Kubeconfig != "" {
+		if _, err := os.Stat(cfg.Kubeconfig); err != nil {
+			return xerrors.Errorf("kubeconfig: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cfg Config) validateGitHub(dev bool) error {
+	// in development mode we use a GitHub App only if there is a private key
+	if !dev || cfg.GitHub.PrivateKeyPath != "" {
+		if cfg.GitHub.AppID == 0 {
+			return xerrors.Errorf("github.appID: required")
+		}
+		if cfg.GitHub.PrivateKeyPath == "" {
+			return xerrors.Errorf("github.privateKeyPath: required")
+		}
+		if _, err := os.Stat(cfg.GitHub.PrivateKeyPath); err != nil {
+			return xerrors.Errorf("github.privateKeyPath: %w", err)
+		}
+	}
+	err := cfg.GitCredentials.Validate()
+	if err != nil {
+		return xerrors.Errorf("gitCredentials.%s", err.Error())
+	}
+	return nil
+}
+
+func (cfg Config) validatePlugins(dev bool) error {
+	err := cfg.Plugins.Validate()
+	if err != nil {
+		return xerrors.Errorf("plugins%s", err.Error())
+	}
+	return nil
+}
+
+// describeConfigError produces a human readable list of the mistakes in a config
+func describeConfigError(err error) string {
+	errs, ok := err.(configErrors)
+	if !ok {
+		return err.Error()
+	}
+
+	var res strings.Builder
+	for _, e := range errs {
+		fmt.Fprintf(&res, "  - %s\n", e.Error())
+	}
+	return res.String()
+}
@@ -0,0 +1,155 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// debugReplayEventsCmd represents the replay-events command
+var debugReplayEventsCmd = &cobra.Command{
+	Use:   "replay-events <file.json>",
+	Short: "Computes the status of jobs from recorded pod events",
+	Long: `Computes the status of jobs from recorded pod events the way the executor does when it receives them,
+without a Kubernetes cluster. This reproduces how werft arrived at the status of a job, e.g. why a job was
+considered done too early or never failed.
+
+Events are recorded per job if executor.eventRecordingPath is configured. The event trace log
+(executor.eventTraceLog) can be replayed as well - use --job to pick a job from it.
+
+For each event the table lists the pod phase and the status werft reports for it now. Events which do not change
+the status are not reported and listed with --all only. Statuses derived from a pod which differ from the one
+recorded at the time are marked, i.e. the status derivation has changed since the recording. With
+--fail-on-deviation the command exits non-zero if there are such events.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		evts, err := executor.ReadRecordedEvents(f)
+		if err != nil {
+			return xerrors.Errorf("cannot read %s: %w", args[0], err)
+		}
+
+		if job, _ := cmd.Flags().GetString("job"); job != "" {
+			var filtered []executor.RecordedEvent
+			for _, evt := range evts {
+				if evt.Pod.Labels[executor.LabelJobName] == job {
+					filtered = append(filtered, evt)
+				}
+			}
+			evts = filtered
+		}
+		if len(evts) == 0 {
+			return xerrors.Errorf("%s contains no events", args[0])
+		}
+
+		all, _ := cmd.Flags().GetBool("all")
+		var deviations int
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tEVENT\tJOB\tPOD PHASE\tPHASE\tSUCCESS\tNOTES")
+		for _, r := range executor.ReplayEvents(evts) {
+			if r.Deviates {
+				deviations++
+			}
+			if !all && !r.Changed && !r.Deviates && r.Error == nil {
+				continue
+			}
+
+			var (
+				job, phase, success string
+				notes               []string
+			)
+			if r.Status != nil {
+				job = r.Status.Name
+				phase = strings.TrimPrefix(r.Status.Phase.String(), "PHASE_")
+				success = fmt.Sprint(r.Status.Conditions.Success)
+				if r.Status.Details != "" {
+					notes = append(notes, r.Status.Details)
+				}
+				if !r.Changed {
+					notes = append(notes, "not reported")
+				}
+			} else {
+				job = r.Event.Pod.Name
+				notes = append(notes, fmt.Sprintf("error: %v", r.Error))
+			}
+			if r.DeletesPod {
+				notes = append(notes, "deletes pod")
+			}
+			if r.Deviates {
+				notes = append(notes, fmt.Sprintf("DEVIATES from recording: %s", describeRecordedStatus(r.Event)))
+			}
+
+			evt := string(r.Event.Type)
+			if evt == "" {
+				evt = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Event.Time.Format(time.RFC3339), evt, job, r.Event.Pod.Status.Phase, phase, success, strings.Join(notes, ", "))
+		}
+		err = tw.Flush()
+		if err != nil {
+			return err
+		}
+
+		if fail, _ := cmd.Flags().GetBool("fail-on-deviation"); fail && deviations > 0 {
+			return xerrors.Errorf("%d events deviate from the recording", deviations)
+		}
+		return nil
+	},
+}
+
+// describeRecordedStatus summarises the status the executor computed when it recorded an event
+func describeRecordedStatus(evt executor.RecordedEvent) string {
+	if evt.Error != "" {
+		return "error: " + evt.Error
+	}
+	s := evt.Status
+	res := fmt.Sprintf("phase %s", strings.TrimPrefix(s.Phase.String(), "PHASE_"))
+	if c := s.Conditions; c != nil {
+		res += fmt.Sprintf(" success %v", c.Success)
+		if !c.Success {
+			res += fmt.Sprintf(" failure %s", strings.TrimPrefix(c.FailureClass.String(), "FAILURE_"))
+		}
+	}
+	if s.Details != "" {
+		res += fmt.Sprintf(" (%s)", s.Details)
+	}
+	return res
+}
+
+func init() {
+	debugCmd.AddCommand(debugReplayEventsCmd)
+
+	debugReplayEventsCmd.Flags().String("job", "", "replays the events of this job only")
+	debugReplayEventsCmd.Flags().Bool("all", false, "lists all events, including those which do not change the status")
+	debugReplayEventsCmd.Flags().Bool("fail-on-deviation", false, "exits non-zero if a status differs from the recorded one")
+}
@@ -0,0 +1,264 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// devCmd groups the commands which help developing and evaluating werft
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Helps developing and evaluating werft locally",
+}
+
+// devUpCmd starts a complete local werft
+var devUpCmd = &cobra.Command{
+	Use:   "up [config.yaml]",
+	Short: "Starts a complete local werft",
+	Long: `Starts a complete local werft in one go: the server keeps all state in memory (see run --dev),
+jobs run in the fake executor or in a local kind or k3d cluster which is created if it does not exist yet,
+and an example repository is created to try things with.
+
+Once werft is up, start the example job from another terminal:
+
+  cd werft-example && werft run local -j .werft/build.yaml
+
+The config file is optional, the development defaults fill in what it leaves out.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfig(args)
+		if err != nil {
+			return err
+		}
+
+		cluster, _ := cmd.Flags().GetString("cluster")
+		if cluster != "" {
+			name, _ := cmd.Flags().GetString("cluster-name")
+			cfg.Kubeconfig, err = startLocalCluster(cluster, name)
+			if err != nil {
+				return err
+			}
+			cfg.Executor.Backend = executor.BackendKubernetes
+			if cfg.Werft.WorkspaceNodePathPrefix == "" {
+				cfg.Werft.WorkspaceNodePathPrefix = "/tmp/werft"
+			}
+		}
+
+		example, _ := cmd.Flags().GetString("example")
+		if example != "" {
+			err = writeExampleRepo(example)
+			if err != nil {
+				return err
+			}
+		}
+
+		var cfgPath string
+		if len(args) > 0 {
+			cfgPath = args[0]
+		}
+		return serve(cfg, cfgPath, true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+	devCmd.AddCommand(devUpCmd)
+
+	devUpCmd.Flags().String("cluster", "", "run jobs in a local kind or k3d cluster rather than the fake executor")
+	devUpCmd.Flags().String("cluster-name", "werft-dev", "name of the local cluster")
+	devUpCmd.Flags().String("example", "werft-example", "directory of the example repository (empty to skip)")
+}
+
+// startLocalCluster makes sure a kind or k3d cluster runs and returns the path of its kubeconfig
+func startLocalCluster(tool, name string) (kubeconfig string, err error) {
+	var (
+		list          []string
+		create        []string
+		getKubeconfig []string
+	)
+	switch tool {
+	case "kind":
+		list = []string{"get", "clusters"}
+		create = []string{"create", "cluster", "--name", name}
+		getKubeconfig = []string{"get", "kubeconfig", "--name", name}
+	case "k3d":
+		list = []string{"cluster", "list", "--no-headers"}
+		create = []string{"cluster", "create", name}
+		getKubeconfig = []string{"kubeconfig", "get", name}
+	default:
+		return "", xerrors.Errorf("unknown cluster \"%s\" - must be kind or k3d", tool)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return "", xerrors.Errorf("cannot find %s - please install it first: %w", tool, err)
+	}
+
+	out, err := exec.Command(tool, list...).Output()
+	if err != nil {
+		return "", xerrors.Errorf("cannot list %s clusters: %w", tool, err)
+	}
+	var exists bool
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		log.WithField("name", name).Infof("creating %s cluster - this may take a while", tool)
+		c := exec.Command(tool, create...)
+		c.Stdout = os.Stderr
+		c.Stderr = os.Stderr
+		err = c.Run()
+		if err != nil {
+			return "", xerrors.Errorf("cannot create %s cluster: %w", tool, err)
+		}
+	}
+
+	out, err = exec.Command(tool, getKubeconfig...).Output()
+	if err != nil {
+		return "", xerrors.Errorf("cannot get kubeconfig of %s cluster: %w", tool, err)
+	}
+	kubeconfig = filepath.Join(os.TempDir(), fmt.Sprintf("werft-%s-%s.kubeconfig", tool, name))
+	err = ioutil.WriteFile(kubeconfig, out, 0600)
+	if err != nil {
+		return "", err
+	}
+	log.WithField("kubeconfig", kubeconfig).WithField("name", name).Infof("using %s cluster", tool)
+	return kubeconfig, nil
+}
+
+// exampleRepo is the content of the example repository, by path
+var exampleRepo = map[string]string{
+	"README.md": `# werft example
+
+This repository was created by werft dev up. Start its job with
+
+    werft run local -j .werft/build.yaml
+
+and watch it in the web UI at http://localhost:8080.
+`,
+	".werft/config.yaml": `defaultJob: ".werft/build.yaml"
+`,
+	".werft/build.yaml": `pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    workingDir: /workspace
+    imagePullPolicy: IfNotPresent
+    env:
+    # the fake executor runs this script instead of the command below
+    - name: WERFT_FAKE_SCRIPT
+      value: |
+        echo [build|PHASE] building the example
+        sleep 2s
+        echo [test|PHASE] testing the example
+        sleep 2s
+        echo all good
+    command:
+    - sh
+    - -c
+    - |
+      echo "[build|PHASE] building the example"
+      ls -la
+      echo "[test|PHASE] testing the example"
+      echo all good
+`,
+}
+
+// writeExampleRepo creates the example repository, unless the directory exists already
+func writeExampleRepo(dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		log.WithField("dir", dir).Info("example repository exists already - leaving it as is")
+		return nil
+	}
+
+	for fn, content := range exampleRepo {
+		fn = filepath.Join(dir, fn)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	log.WithField("dir", dir).Info("created example repository - start its job with: werft run local -j .werft/build.yaml")
+	return nil
+}
+
+// applyDevDefaults fills in what the server needs to start in development mode, s.t. it can run without config file.
+// Unless the config asks for a Kubernetes cluster explicitly, development mode uses the fake executor.
+func (cfg *Config) applyDevDefaults() {
+	if cfg.Executor.Backend == "" {
+		cfg.Executor.Backend = executor.BackendFake
+	}
+	if cfg.Service.WebPort == 0 {
+		cfg.Service.WebPort = 8080
+	}
+	if cfg.Service.GRPCPort == 0 {
+		cfg.Service.GRPCPort = 7777
+	}
+	if cfg.Werft.BaseURL == "" {
+		cfg.Werft.BaseURL = fmt.Sprintf("http://localhost:%d", cfg.Service.WebPort)
+	}
+	if cfg.Executor.JobPrepTimeout == nil {
+		cfg.Executor.JobPrepTimeout = &executor.Duration{Duration: 10 * time.Minute}
+	}
+	if cfg.Executor.JobTotalTimeout == nil {
+		cfg.Executor.JobTotalTimeout = &executor.Duration{Duration: 60 * time.Minute}
+	}
+}
+
+// newInMemoryStorage produces stores which keep their state in memory, i.e. lose it when the server stops
+func newInMemoryStorage() *storage {
+	return &storage{
+		Kind:                 "memory",
+		Jobs:                 store.NewInMemoryJobStore(),
+		Groups:               store.NewInMemoryNumberGroup(),
+		Preferences:          store.NewInMemoryPreferences(),
+		Deployments:          store.NewInMemoryDeployments(),
+		Maintenance:          store.NewInMemoryMaintenance(),
+		Repositories:         store.NewInMemoryRepositories(),
+		Attestations:         store.NewInMemoryAttestations(),
+		Stats:                store.NewInMemoryStats(),
+		Events:               store.NewInMemoryEvents(inMemoryEventTraceLimit),
+		ServiceAccountTokens: store.NewInMemoryServiceAccountTokens(),
+		WebhookDeliveries:    store.NewInMemoryWebhookDeliveries(),
+	}
+}
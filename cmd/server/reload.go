@@ -0,0 +1,237 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	plugin "github.com/32leaves/werft/pkg/plugin/host"
+	"github.com/32leaves/werft/pkg/werft"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// configReloadInterval is how often we look for changes of the config file. Config maps mounted into a pod
+// change by swapping symlinks, hence we poll rather than watch the file.
+const configReloadInterval = 10 * time.Second
+
+// configReloader applies changes of the config file to the running server. It also runs the plugins, because
+// they restart when their config changes.
+type configReloader struct {
+	// Path is the config file. If empty, there is nothing to reload.
+	Path string
+	// Dev is true if the server runs in development mode
+	Dev bool
+
+	Service  *werft.Service
+	UI       *werft.UIService
+	Executor *executor.Executor
+
+	// boot is the config the server started with. Changes to parts which cannot be reloaded are compared against it.
+	boot Config
+	// last is the content of the config file we saw last
+	last []byte
+
+	mu            sync.Mutex
+	plugins       *plugin.Plugins
+	pluginsConfig []byte
+	jobSpecRepos  []string
+}
+
+// Start starts the plugins of the config the server boots with
+func (r *configReloader) Start(cfg Config) error {
+	r.boot = cfg
+	r.jobSpecRepos = cfg.Service.JobSpecRepos
+	if r.Path != "" {
+		var err error
+		r.last, err = ioutil.ReadFile(r.Path)
+		if err != nil {
+			return err
+		}
+	}
+	err := r.startPlugins(cfg.Plugins)
+	if err != nil {
+		return xerrors.Errorf("cannot start plugins: %w", err)
+	}
+	return nil
+}
+
+// Run reloads the config whenever the file changes or we receive SIGHUP
+func (r *configReloader) Run() {
+	if r.Path == "" {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	tick := time.NewTicker(configReloadInterval)
+	defer tick.Stop()
+	for {
+		var force bool
+		select {
+		case <-tick.C:
+		case <-hup:
+			force = true
+		}
+
+		fc, err := ioutil.ReadFile(r.Path)
+		if err != nil {
+			log.WithError(err).WithField("path", r.Path).Warn("cannot read config file - keeping the current config")
+			continue
+		}
+		if !force && bytes.Equal(fc, r.last) {
+			continue
+		}
+		r.last = fc
+
+		err = r.reload(fc)
+		if err != nil {
+			log.WithError(err).WithField("path", r.Path).Error("rejected config change - keeping the current config")
+			continue
+		}
+		log.WithField("path", r.Path).Info("reloaded config")
+	}
+}
+
+// reload applies a new config. If the config is invalid, nothing changes.
+func (r *configReloader) reload(fc []byte) error {
+	cfg, err := parseConfig(fc)
+	if err != nil {
+		return err
+	}
+	if r.Dev {
+		cfg.applyDevDefaults()
+	}
+	err = cfg.validate(r.Dev)
+	if err != nil {
+		return err
+	}
+
+	err = r.Executor.SetTimeouts(cfg.Executor.JobPrepTimeout, cfg.Executor.JobTotalTimeout)
+	if err != nil {
+		return xerrors.Errorf("executor: %w", err)
+	}
+	needRestart, err := r.Service.ReloadConfig(cfg.Werft)
+	if err != nil {
+		return xerrors.Errorf("werft: %w", err)
+	}
+	for i, p := range needRestart {
+		needRestart[i] = "werft." + p
+	}
+
+	r.mu.Lock()
+	reposChanged := !reflect.DeepEqual(r.jobSpecRepos, cfg.Service.JobSpecRepos)
+	r.jobSpecRepos = cfg.Service.JobSpecRepos
+	r.mu.Unlock()
+	if reposChanged {
+		go func() {
+			err := r.UI.SetRepos(cfg.Service.JobSpecRepos)
+			if err != nil {
+				log.WithError(err).Warn("cannot update job specs of the new job spec repos")
+			}
+		}()
+	}
+
+	err = r.startPlugins(cfg.Plugins)
+	if err != nil {
+		// the rest of the config applies nonetheless, the plugins might just have failed to start
+		log.WithError(err).Error("cannot restart plugins with the new config")
+	}
+
+	boot, next := r.boot, cfg
+	boot.Executor.JobPrepTimeout, boot.Executor.JobTotalTimeout = nil, nil
+	next.Executor.JobPrepTimeout, next.Executor.JobTotalTimeout = nil, nil
+	for name, changed := range map[string]bool{
+		"service.webPort":  boot.Service.WebPort != next.Service.WebPort,
+		"service.grpcPort": boot.Service.GRPCPort != next.Service.GRPCPort,
+		"storage":          !reflect.DeepEqual(boot.Storage, next.Storage),
+		"executor":         !reflect.DeepEqual(boot.Executor, next.Executor),
+		"kubeconfig":       boot.Kubeconfig != next.Kubeconfig,
+		"github":           boot.GitHub != next.GitHub,
+		"gitCredentials":   !reflect.DeepEqual(boot.GitCredentials, next.GitCredentials),
+		"operator":         !reflect.DeepEqual(boot.Operator, next.Operator),
+	} {
+		if changed {
+			needRestart = append(needRestart, name)
+		}
+	}
+	if len(needRestart) > 0 {
+		sort.Strings(needRestart)
+		log.WithField("fields", needRestart).Warn("some config changes take effect only after a restart")
+	}
+	return nil
+}
+
+// startPlugins (re)starts the plugins if their config changed
+func (r *configReloader) startPlugins(cfg plugin.Config) error {
+	// plugin configs contain YAML nodes which remember where in the file they are, hence we compare what they marshal to
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.plugins != nil && bytes.Equal(raw, r.pluginsConfig) {
+		return nil
+	}
+
+	if r.plugins != nil {
+		log.Info("restarting plugins")
+		r.plugins.Stop()
+		r.plugins = nil
+		r.Service.SetPlugins(nil)
+	}
+	plugins, err := plugin.Start(cfg, r.Service)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for e := range plugins.Errchan {
+			log.WithError(e.Err).WithField("plugin", e.Reg.Name).Warn("plugin error")
+		}
+	}()
+	r.plugins = plugins
+	r.pluginsConfig = raw
+	r.Service.SetPlugins(plugins)
+	return nil
+}
+
+// Stop stops the plugins
+func (r *configReloader) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.plugins != nil {
+		r.plugins.Stop()
+		r.plugins = nil
+	}
+}
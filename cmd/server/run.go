@@ -23,20 +23,27 @@ package cmd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"expvar"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/devproxy"
 	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/ghretry"
+	"github.com/32leaves/werft/pkg/gitcreds"
 	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/operator"
 	plugin "github.com/32leaves/werft/pkg/plugin/host"
+	"github.com/32leaves/werft/pkg/recovery"
 	"github.com/32leaves/werft/pkg/store"
 	"github.com/32leaves/werft/pkg/store/postgres"
 	"github.com/32leaves/werft/pkg/werft"
@@ -46,57 +53,151 @@ import (
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:   "run <config.json>",
+	Use:   "run [config.json]",
 	Short: "Starts the werft server",
-	Args:  cobra.ExactArgs(1),
+	Long: `Starts the werft server using a config file.
+
+Changes to the config file apply while the server runs, e.g. when a Helm upgrade changes the config map it is mounted
+from: notifications, alerts, priorities, rate limits, tokens, job timeouts, job spec repos and plugins. Changes to
+other parts, e.g. storage or ports, take effect after a restart. Invalid configs are rejected as a whole and the
+server keeps running with the current one. Send SIGHUP to reload the config right away.
+
+With --dev, werft runs without Postgres and without a Kubernetes cluster: all state is kept in memory and a fake
+executor pretends to run jobs, i.e. their pods start and succeed without running anything. The config file is
+optional in this mode, and setting executor.backend to "kubernetes" in it runs the jobs in a cluster after all.
+This is meant for developing werft and its plugins.
+
+The fake executor can also be used with regular storage by setting executor.backend to "fake". Its containers run
+the script in their WERFT_FAKE_SCRIPT environment variable instead of their image, s.t. job templates, log cutting
+and integrations can be tested without a cluster.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if v, _ := cmd.Flags().GetBool("verbose"); v {
 			log.SetLevel(log.DebugLevel)
 		}
 
-		fc, err := ioutil.ReadFile(args[0])
-		if err != nil {
-			return err
+		dev, _ := cmd.Flags().GetBool("dev")
+		if len(args) == 0 && !dev {
+			return fmt.Errorf("requires a config file unless started with --dev")
 		}
 
-		var cfg Config
-		err = yaml.Unmarshal(fc, &cfg)
+		cfg, err := readConfig(args)
 		if err != nil {
 			return err
 		}
-
-		log.Info("connecting to database")
-		db, err := sql.Open("postgres", cfg.Storage.JobStore)
-		if err != nil {
-			return err
+		var cfgPath string
+		if len(args) > 0 {
+			cfgPath = args[0]
 		}
-		err = db.Ping()
+		if debugProxy, _ := cmd.Flags().GetString("debug-webui-proxy"); debugProxy != "" {
+			cfg.Werft.DevProxy.Target = debugProxy
+		}
+		if token, _ := cmd.Flags().GetString("debug-webui-proxy-token"); token != "" {
+			cfg.Werft.DevProxy.Token = token
+		}
+		return serve(cfg, cfgPath, dev)
+	},
+}
+
+// readConfig reads the config file if there is one, i.e. args names it
+func readConfig(args []string) (Config, error) {
+	var cfg Config
+	if len(args) == 0 {
+		return cfg, nil
+	}
+	fc, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return cfg, err
+	}
+	cfg, err = parseConfig(fc)
+	if err != nil {
+		return cfg, xerrors.Errorf("cannot parse %s: %w", args[0], err)
+	}
+	return cfg, nil
+}
+
+// serve runs the werft server until it receives SIGINT or SIGTERM. In development mode the server keeps its state in memory.
+// If the config came from a file, changes to the file apply while the server runs.
+func serve(cfg Config, cfgPath string, dev bool) error {
+	if dev {
+		cfg.applyDevDefaults()
+		log.Warn("running in development mode - all state is kept in memory")
+	}
+	err := cfg.validate(dev)
+	if err != nil {
+		return xerrors.Errorf("invalid config: %w", err)
+	}
+	if cfg.Executor.Backend == executor.BackendFake {
+		log.Warn("using the fake executor - jobs do not actually run")
+	}
+
+	var stores *storage
+	if dev {
+		stores = newInMemoryStorage()
+	} else {
+		stores, err = newPostgresStorage(cfg)
 		if err != nil {
 			return err
 		}
-		log.Info("making sure database schema is up to date")
-		err = postgres.Migrate(db)
+	}
+
+	var (
+		ghClient      *github.Client
+		gitAuth       werft.GitCredentialHelper
+		authProviders []string
+		useGitHubApp  = !dev || cfg.GitHub.PrivateKeyPath != ""
+	)
+	if useGitHubApp {
+		ghtr, err := ghinstallation.NewKeyFromFile(ghretry.NewTransport(http.DefaultTransport), cfg.GitHub.AppID, cfg.GitHub.InstallationID, cfg.GitHub.PrivateKeyPath)
 		if err != nil {
 			return err
 		}
-		jobStore, err := postgres.NewJobStore(db)
+		gitCredentials, err := gitcreds.NewRefresher(cfg.GitCredentials, gitcreds.GitHubAppConfig{
+			AppID:          cfg.GitHub.AppID,
+			InstallationID: cfg.GitHub.InstallationID,
+			PrivateKeyPath: cfg.GitHub.PrivateKeyPath,
+		})
 		if err != nil {
 			return err
 		}
-		nrGroups, err := postgres.NewNumberGroup(db)
+		ghClient = github.NewClient(&http.Client{Transport: ghtr})
+		gitAuth = gitCredentials.Helper
+		authProviders = []string{"github-app"}
+	} else {
+		// without a GitHub app we can still read public repositories, albeit rate limited
+		ghClient = github.NewClient(&http.Client{Transport: ghretry.NewTransport(nil)})
+	}
+
+	execCfg := cfg.Executor
+	if execCfg.Namespace == "" {
+		execCfg.Namespace = "default"
+	}
+
+	uiservice, err := werft.NewUIService(ghClient, cfg.Service.JobSpecRepos)
+	if err != nil {
+		return err
+	}
+
+	var (
+		kubeConfig *rest.Config
+		exec       *executor.Executor
+	)
+	if execCfg.Backend == executor.BackendFake {
+		exec, err = executor.NewFakeExecutor(execCfg)
 		if err != nil {
 			return err
 		}
-
-		var kubeConfig *rest.Config
+	} else {
 		if cfg.Kubeconfig == "" {
 			kubeConfig, err = rest.InClusterConfig()
 			if err != nil {
@@ -109,98 +210,198 @@ var runCmd = &cobra.Command{
 			}
 		}
 
-		ghtr, err := ghinstallation.NewKeyFromFile(http.DefaultTransport, cfg.GitHub.AppID, cfg.GitHub.InstallationID, cfg.GitHub.PrivateKeyPath)
+		log.Info("connecting to kubernetes")
+		exec, err = executor.NewExecutor(execCfg, kubeConfig)
 		if err != nil {
 			return err
 		}
-		ghClient := github.NewClient(&http.Client{Transport: ghtr})
-
-		execCfg := cfg.Executor
-		if execCfg.Namespace == "" {
-			execCfg.Namespace = "default"
-		}
+	}
 
-		logStore, err := store.NewFileLogStore(cfg.Storage.LogStore)
+	var (
+		baseLogStore store.Logs
+		logStoreKind = "file"
+	)
+	if dev {
+		baseLogStore = store.NewInMemoryLogStore()
+		logStoreKind = "memory"
+	} else {
+		baseLogStore, err = store.NewFileLogStore(cfg.Storage.LogStore)
 		if err != nil {
 			return err
 		}
-
-		uiservice, err := werft.NewUIService(ghClient, cfg.Service.JobSpecRepos)
+	}
+	logKeys, err := cfg.Storage.LogEncryption.LoadKeys()
+	if err != nil {
+		return err
+	}
+	if len(logKeys) > 0 {
+		baseLogStore, err = store.NewEncryptedLogs(baseLogStore, logKeys)
 		if err != nil {
 			return err
 		}
+		log.WithField("previousKeys", len(logKeys)-1).Info("encrypting logs at rest")
+	}
+	limitedLogStore := store.NewLimitedLogs(baseLogStore, cfg.Storage.LogLimits, func(id string) {
+		err := exec.MarkLogTruncated(id)
+		if err != nil {
+			log.WithError(err).WithField("name", id).Warn("cannot mark job log as truncated")
+		}
+	})
+	logStore := store.NewTimestampedLogs(limitedLogStore)
 
-		log.Info("connecting to kubernetes")
-		exec, err := executor.NewExecutor(execCfg, kubeConfig)
+	var archive store.Archive
+	if cfg.Storage.ArchivePath != "" {
+		archive, err = store.NewFileArchive(cfg.Storage.ArchivePath)
 		if err != nil {
 			return err
 		}
-		exec.Run()
-		service := &werft.Service{
-			Logs:     logStore,
-			Jobs:     jobStore,
-			Groups:   nrGroups,
-			Executor: exec,
-			Cutter:   logcutter.DefaultCutter,
-			GitHub: werft.GitHubSetup{
-				WebhookSecret: []byte(cfg.GitHub.WebhookSecret),
-				Client:        ghClient,
-				Auth: func(ctx context.Context) (user string, pass string, err error) {
-					tkn, err := ghtr.Token(ctx)
-					if err != nil {
-						return
-					}
-					user = "x-access-token"
-					pass = tkn
-					return
-				},
-			},
-			Config: cfg.Werft,
+		if len(logKeys) > 0 {
+			archive, err = store.NewEncryptedArchive(archive, logKeys)
+			if err != nil {
+				return err
+			}
 		}
-		if val, _ := cmd.Flags().GetString("debug-webui-proxy"); val != "" {
-			cfg.Werft.DebugProxy = val
+	}
+
+	if cfg.Storage.JobSpecs == storeInArchive || cfg.Storage.JobResults == storeInArchive {
+		var (
+			specs   store.SpecStore = stores.Jobs
+			results store.ResultStore
+		)
+		if cfg.Storage.JobSpecs == storeInArchive {
+			specs = store.NewArchiveSpecStore(archive)
 		}
-		service.Start()
+		if cfg.Storage.JobResults == storeInArchive {
+			results = store.NewArchiveResultStore(archive)
+		}
+		stores.Jobs = store.NewJobs(stores.Jobs, specs, results)
+		log.WithField("jobSpecs", cfg.Storage.JobSpecs).WithField("jobResults", cfg.Storage.JobResults).Info("storing job specs or results in the archive")
+	}
 
-		grpcServer := grpc.NewServer()
-		v1.RegisterWerftServiceServer(grpcServer, service)
-		v1.RegisterWerftUIServer(grpcServer, uiservice)
-		go startGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Service.GRPCPort))
-		go startWeb(service, grpcServer, fmt.Sprintf(":%d", cfg.Service.WebPort), cfg.Werft.DebugProxy)
+	exec.Run()
+	service := &werft.Service{
+		Logs:                 logStore,
+		Jobs:                 stores.Jobs,
+		Groups:               stores.Groups,
+		Preferences:          stores.Preferences,
+		Deployments:          stores.Deployments,
+		Maintenance:          stores.Maintenance,
+		Repositories:         stores.Repositories,
+		Attestations:         stores.Attestations,
+		Events:               stores.Events,
+		Stats:                stores.Stats,
+		Archive:              archive,
+		ServiceAccountTokens: stores.ServiceAccountTokens,
+		WebhookDeliveries:    stores.WebhookDeliveries,
+		Executor:             exec,
+		Cutter:               logcutter.DefaultCutter,
+		GitHub: werft.GitHubSetup{
+			WebhookSecret: []byte(cfg.GitHub.WebhookSecret),
+			Client:        ghClient,
+			Auth:          gitAuth,
+		},
+		Config: cfg.Werft,
+		Info: werft.ServerInfo{
+			Version:       version,
+			JobStore:      stores.Kind,
+			LogStore:      logStoreKind,
+			AuthProviders: authProviders,
+		},
+	}
+	for _, p := range cfg.Plugins {
+		service.Info.Plugins = append(service.Info.Plugins, p.Name)
+	}
+	service.Info.Config, err = yaml.Marshal(cfg.redacted())
+	if err != nil {
+		return err
+	}
+	service.Start()
 
-		plugins, err := plugin.Start(cfg.Plugins, service)
+	if cfg.Operator.Enabled {
+		opcfg := cfg.Operator
+		if opcfg.Namespace == "" {
+			opcfg.Namespace = execCfg.Namespace
+		}
+		dynClient, err := dynamic.NewForConfig(kubeConfig)
 		if err != nil {
-			log.WithError(err).Fatal("cannot start plugins")
+			return err
 		}
-		go func() {
-			for e := range plugins.Errchan {
-				log.WithError(e.Err).WithField("plugin", e.Reg.Name).Warn("plugin error")
-			}
-		}()
-		defer plugins.Stop()
-
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		log.Info("werft is up and running. Stop with SIGINT or CTRL+C")
-		<-sigChan
-		log.Info("Received SIGINT - shutting down")
+		repoController := &operator.RepositoryController{
+			Client: dynClient,
+			Kube:   exec.Client,
+			Store:  stores.Repositories,
+			Config: opcfg,
+		}
+		go repoController.Run(context.Background())
+		jobController := &operator.JobController{
+			Client:  dynClient,
+			Werft:   service,
+			Config:  opcfg,
+			BaseURL: cfg.Werft.BaseURL,
+		}
+		go jobController.Run(werft.WithInternalCaller(context.Background(), "operator"))
+		log.WithField("namespace", opcfg.Namespace).Info("operator mode enabled - reconciling werft resources")
+	}
 
-		return nil
-	},
+	reloader := &configReloader{
+		Path:     cfgPath,
+		Dev:      dev,
+		Service:  service,
+		UI:       uiservice,
+		Executor: exec,
+	}
+	err = reloader.Start(cfg)
+	if err != nil {
+		return err
+	}
+	defer reloader.Stop()
+	go reloader.Run()
+
+	grpcServer := grpc.NewServer(
+		// grpc-go cannot chain interceptors, hence we call the panic recovery from within the request log ourselves.
+		// That way calls which panicked are logged with their internal error.
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return service.LogUnaryCall(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return recovery.UnaryServerInterceptor(ctx, req, info, handler)
+			})
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return service.LogStreamCall(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+				return recovery.StreamServerInterceptor(srv, ss, info, handler)
+			})
+		}),
+	)
+	v1.RegisterWerftServiceServer(grpcServer, service)
+	v1.RegisterWerftUIServer(grpcServer, uiservice)
+	v1.RegisterWerftAdminServer(grpcServer, service)
+	go startGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Service.GRPCPort))
+	go startWeb(service, grpcServer, stores.readyz, fmt.Sprintf(":%d", cfg.Service.WebPort), cfg.Werft.DevProxy)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	log.Info("werft is up and running. Stop with SIGINT or CTRL+C")
+	<-sigChan
+	log.Info("Received SIGINT - shutting down")
+
+	return nil
 }
 
 // startWeb starts the werft web UI service
-func startWeb(srv *werft.Service, grpcServer *grpc.Server, addr string, debugProxy string) {
+func startWeb(srv *werft.Service, grpcServer *grpc.Server, readyz http.HandlerFunc, addr string, devProxy devproxy.Config) {
 	var webuiServer http.Handler
-	if debugProxy != "" {
-		tgt, err := url.Parse(debugProxy)
+	if devProxy.Enabled() {
+		var err error
+		webuiServer, err = devproxy.New(devProxy)
 		if err != nil {
-			// this is debug only - it's ok to panic
+			// the config was validated before - this is a programming error
 			panic(err)
 		}
 
-		log.WithField("target", tgt).Debug("proxying to webui server")
-		webuiServer = httputil.NewSingleHostReverseProxy(tgt)
+		if devProxy.Token == "" {
+			log.WithField("target", devProxy.Target).Warn("proxying web UI to a development server without a token - anyone who can reach werft can use it")
+		} else {
+			log.WithField("target", devProxy.Target).Info("proxying web UI to a development server")
+		}
 	} else {
 		// WebUI is a single-page app, hence any path that does not resolve to a static file must result in /index.html.
 		// As a (rather crude) fix we intercept the response writer to find out if the FileServer returned an error. If so
@@ -222,6 +423,13 @@ func startWeb(srv *werft.Service, grpcServer *grpc.Server, addr string, debugPro
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/github/app", srv.HandleGithubWebhook)
+	mux.Handle("/api/v1/events", hstsHandler(srv.HandleSSESubscribe))
+	mux.Handle("/api/v1/listen/", hstsHandler(srv.HandleSSEListen))
+	mux.Handle("/api/v1/maintenance", hstsHandler(srv.HandleMaintenance))
+	mux.Handle("/api/v1/provenance/", hstsHandler(srv.HandleProvenance))
+	mux.Handle("/api/v1/logs/", hstsHandler(srv.HandleLogArchive))
+	mux.HandleFunc("/readyz", readyz)
+	mux.Handle("/debug/vars", expvar.Handler())
 	mux.Handle("/", hstsHandler(
 		grpcTrafficSplitter(
 			webuiServer,
@@ -293,29 +501,216 @@ func (w *interceptResponseWriter) Write(p []byte) (n int, err error) {
 func init() {
 	rootCmd.AddCommand(runCmd)
 
-	runCmd.Flags().String("debug-webui-proxy", "", "proxies the web UI to this address")
+	runCmd.Flags().String("debug-webui-proxy", "", "proxies the web UI to this address (overrides werft.devProxy.target)")
+	runCmd.Flags().String("debug-webui-proxy-token", "", "requires this token to use the web UI proxy (overrides werft.devProxy.token)")
 	runCmd.Flags().Bool("verbose", false, "enable verbose debug output")
+	runCmd.Flags().Bool("dev", false, "run without database and cluster, keeping all state in memory and faking job execution")
+}
+
+// storage holds the stores the server keeps its state in
+type storage struct {
+	// Kind names the storage backend, e.g. postgres
+	Kind string
+
+	Jobs                 store.Jobs
+	Groups               store.NumberGroup
+	Preferences          store.Preferences
+	Deployments          store.Deployments
+	Maintenance          store.Maintenance
+	Repositories         store.Repositories
+	Attestations         store.Attestations
+	Stats                store.Stats
+	Events               store.Events
+	ServiceAccountTokens store.ServiceAccountTokens
+	WebhookDeliveries    store.WebhookDeliveries
+
+	// DB is the database the stores live in, or nil if they don't live in a database
+	DB *sql.DB
 }
 
-// Config configures the werft server
-type Config struct {
-	Werft   werft.Config `yaml:"werft"`
-	Service struct {
-		WebPort      int      `yaml:"webPort"`
-		GRPCPort     int      `yaml:"grpcPort"`
-		JobSpecRepos []string `yaml:"jobSpecRepos"`
-	}
-	Storage struct {
-		LogStore string `yaml:"logsPath"`
-		JobStore string `yaml:"jobsConnectionString"`
-	} `yaml:"storage"`
-	Executor   executor.Config `yaml:"executor"`
-	Kubeconfig string          `yaml:"kubeconfig,omitempty"`
-	GitHub     struct {
-		WebhookSecret  string `yaml:"webhookSecret"`
-		PrivateKeyPath string `yaml:"privateKeyPath"`
-		InstallationID int64  `yaml:"installationID,omitempty"`
-		AppID          int64  `yaml:"appID"`
-	} `yaml:"github"`
-	Plugins plugin.Config
+// newPostgresStorage connects to the database, migrates its schema and produces the stores which live in it
+func newPostgresStorage(cfg Config) (*storage, error) {
+	log.Info("connecting to database")
+	db, err := postgres.Open(cfg.Storage.JobStore, cfg.Storage.Pool)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("making sure database schema is up to date")
+	err = postgres.Migrate(db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &storage{Kind: "postgres", DB: db}
+	res.Jobs, err = postgres.NewJobStore(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Groups, err = postgres.NewNumberGroup(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Preferences, err = postgres.NewPreferences(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Deployments, err = postgres.NewDeployments(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Maintenance, err = postgres.NewMaintenance(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Repositories, err = postgres.NewRepositories(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Attestations, err = postgres.NewAttestations(db)
+	if err != nil {
+		return nil, err
+	}
+	res.Stats, err = postgres.NewStats(db)
+	if err != nil {
+		return nil, err
+	}
+	res.ServiceAccountTokens, err = postgres.NewServiceAccountTokens(db)
+	if err != nil {
+		return nil, err
+	}
+	res.WebhookDeliveries, err = postgres.NewWebhookDeliveries(db)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Storage.EventTrace {
+	case "":
+	case "memory":
+		res.Events = store.NewInMemoryEvents(inMemoryEventTraceLimit)
+	case "postgres":
+		res.Events, err = postgres.NewEvents(db)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown event trace storage \"%s\" - must be memory or postgres", cfg.Storage.EventTrace)
+	}
+	return res, nil
+}
+
+// readyzTimeout is how long the readiness check waits for the database
+const readyzTimeout = 5 * time.Second
+
+// readyz reports whether werft can serve requests, i.e. whether the database it stores its state in is reachable
+func (s *storage) readyz(w http.ResponseWriter, r *http.Request) {
+	res := struct {
+		Ready    bool             `json:"ready"`
+		Database *postgres.Health `json:"database,omitempty"`
+	}{Ready: true}
+	if s.DB != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readyzTimeout)
+		defer cancel()
+
+		health := postgres.CheckHealth(ctx, s.DB)
+		res.Database = &health
+		res.Ready = health.Error == ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !res.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	err := json.NewEncoder(w).Encode(res)
+	if err != nil {
+		log.WithError(err).Debug("cannot write readiness")
+	}
+}
+
+// inMemoryEventTraceLimit is the number of events we keep if the event trace is stored in memory
+const inMemoryEventTraceLimit = 10000
+
+const redactedValue = "<redacted>"
+
+// redacted returns a copy of the config with all secrets removed. Plugin configuration is opaque to werft
+// and may contain secrets, hence is removed altogether.
+func (cfg Config) redacted() Config {
+	if cfg.Storage.JobStore != "" {
+		cfg.Storage.JobStore = redactedValue
+	}
+	if cfg.GitHub.WebhookSecret != "" {
+		cfg.GitHub.WebhookSecret = redactedValue
+	}
+
+	tokens := make([]werft.TokenConfig, len(cfg.Werft.Tokens))
+	for i, t := range cfg.Werft.Tokens {
+		t.Token = redactedValue
+		tokens[i] = t
+	}
+	cfg.Werft.Tokens = tokens
+	if cfg.Werft.DevProxy.Token != "" {
+		cfg.Werft.DevProxy.Token = redactedValue
+	}
+
+	// incoming webhook URLs contain their credentials
+	rules := make([]*werft.AlertRule, len(cfg.Werft.Alerting.Rules))
+	for i, r := range cfg.Werft.Alerting.Rules {
+		rule := *r
+		rule.Notify = make([]*werft.AlertTarget, len(r.Notify))
+		for j, n := range r.Notify {
+			target := &werft.AlertTarget{}
+			if n.Slack != nil {
+				slack := *n.Slack
+				slack.URL = redactedValue
+				target.Slack = &slack
+			}
+			if n.Webhook != nil {
+				webhook := *n.Webhook
+				webhook.URL = redactedValue
+				target.Webhook = &webhook
+			}
+			rule.Notify[j] = target
+		}
+		rules[i] = &rule
+	}
+	cfg.Werft.Alerting.Rules = rules
+
+	notifications := make([]*repoconfig.Notification, len(cfg.Werft.Notifications))
+	for i, n := range cfg.Werft.Notifications {
+		notification := *n
+		if n.Slack != nil {
+			slack := *n.Slack
+			slack.URL = redactedValue
+			notification.Slack = &slack
+		}
+		if n.Teams != nil {
+			teams := *n.Teams
+			teams.URL = redactedValue
+			notification.Teams = &teams
+		}
+		if n.Chat != nil {
+			chat := *n.Chat
+			chat.URL = redactedValue
+			notification.Chat = &chat
+		}
+		if n.Telegram != nil {
+			telegram := *n.Telegram
+			telegram.BotToken = redactedValue
+			notification.Telegram = &telegram
+		}
+		if n.Matrix != nil {
+			matrix := *n.Matrix
+			matrix.AccessToken = redactedValue
+			notification.Matrix = &matrix
+		}
+		notifications[i] = &notification
+	}
+	cfg.Werft.Notifications = notifications
+
+	plugins := make(plugin.Config, len(cfg.Plugins))
+	for i, p := range cfg.Plugins {
+		p.Config = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: redactedValue}
+		plugins[i] = p
+	}
+	cfg.Plugins = plugins
+
+	return cfg
 }
@@ -16,6 +16,7 @@ require (
 	github.com/gorilla/websocket v1.4.1 // indirect
 	github.com/huandu/xstrings v1.2.1 // indirect
 	github.com/improbable-eng/grpc-web v0.11.0
+	github.com/klauspost/compress v1.9.8
 	github.com/lib/pq v1.2.0
 	github.com/mwitkow/go-conntrack v0.0.0-20190716064945-2f068394615f // indirect
 	github.com/olebedev/emitter v0.0.0-20190110104742-e8d1457e6aee
@@ -25,7 +26,9 @@ require (
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
 	github.com/technosophos/moniker v0.0.0-20180509230615-a5dbd03a2245
+	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
 	golang.org/x/oauth2 v0.0.0-20191122200657-5d9234df094c
+	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	golang.org/x/tools v0.0.0-20191219041853-979b82bfef62
 	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898
 	google.golang.org/grpc v1.25.1
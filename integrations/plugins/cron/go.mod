@@ -8,4 +8,5 @@ require (
 	github.com/32leaves/werft v0.0.0-00010101000000-000000000000
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.4.2
+	google.golang.org/grpc v1.25.1
 )
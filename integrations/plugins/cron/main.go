@@ -11,10 +11,15 @@ import (
 	"github.com/32leaves/werft/pkg/reporef"
 	cron "github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
 )
 
 // Config configures this plugin
 type Config struct {
+	// Token authenticates the plugin with werft. The jobs it starts are owned by the token. Without a token they
+	// are owned by anonymous.
+	Token string `yaml:"token,omitempty"`
+
 	Tasks []struct {
 		Spec        string            `yaml:"spec"`
 		Repo        string            `yaml:"repo"`
@@ -38,6 +43,10 @@ func (*cronPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftServ
 		return fmt.Errorf("config has wrong type %s", reflect.TypeOf(config))
 	}
 
+	if cfg.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+cfg.Token)
+	}
+
 	c := cron.New()
 	for idx, task := range cfg.Tasks {
 		repo, err := reporef.Parse(task.Repo)
@@ -63,7 +72,6 @@ func (*cronPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftServ
 		_, err = c.AddFunc(task.Spec, func() {
 			_, err := srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
 				Metadata: &v1.JobMetadata{
-					Owner:       "cron",
 					Annotations: annotations,
 					Trigger:     trigger,
 					Repository:  repo,
@@ -0,0 +1,12 @@
+module github.com/32leaves/werft/svn-plugin
+
+go 1.13
+
+replace github.com/32leaves/werft => ../../..
+
+require (
+	github.com/32leaves/werft v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.4.2
+	google.golang.org/grpc v1.25.1
+	k8s.io/api v0.0.0-20190620084959-7cf5895f2711
+)
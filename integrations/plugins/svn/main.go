@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	plugin "github.com/32leaves/werft/pkg/plugin/client"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Config configures this plugin
+type Config struct {
+	// URL is the base URL of the repositories, e.g. https://svn.acme.com/repos
+	URL string `yaml:"url"`
+
+	// Image is the image of the init container which checks out the workspace. It must contain svn.
+	Image string `yaml:"image,omitempty"`
+
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+func main() {
+	plugin.Serve(&Config{},
+		plugin.WithContentProviderPlugin(&svnPlugin{}),
+	)
+}
+
+type svnPlugin struct {
+	Config *Config
+}
+
+func (p *svnPlugin) Init(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config has wrong type %s", reflect.TypeOf(config))
+	}
+	if cfg.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if cfg.Image == "" {
+		cfg.Image = "jgsqware/svn-client:latest"
+	}
+	p.Config = cfg
+	return nil
+}
+
+// location is the URL of a path in the repository at the ref, e.g. https://svn.acme.com/repos/tools/build/trunk
+func (p *svnPlugin) location(repo *v1.Repository, path string) string {
+	segs := []string{strings.TrimSuffix(p.Config.URL, "/"), repo.Owner, repo.Repo}
+	if repo.Ref != "" {
+		segs = append(segs, strings.Trim(repo.Ref, "/"))
+	}
+	if path != "" {
+		segs = append(segs, strings.TrimPrefix(path, "/"))
+	}
+	return strings.Join(segs, "/")
+}
+
+func (p *svnPlugin) InitContainer(ctx context.Context, req *v1.ContentInitContainerRequest) (*v1.ContentInitContainerResponse, error) {
+	repo := req.Metadata.GetRepository()
+	if repo == nil {
+		return nil, status.Error(codes.InvalidArgument, "repository is required")
+	}
+
+	url := p.location(repo, "")
+	if repo.Revision != "" {
+		url += "@" + repo.Revision
+	}
+	cmd := fmt.Sprintf("svn checkout --non-interactive %s .", url)
+	if p.Config.Username != "" {
+		cmd = fmt.Sprintf("svn checkout --non-interactive --username \"$SVN_USER_SECRET\" --password \"$SVN_PASS_SECRET\" %s .", url)
+	}
+
+	container, err := json.Marshal(&corev1.Container{
+		Image:   p.Config.Image,
+		Command: []string{"sh", "-c", cmd},
+		Env: []corev1.EnvVar{
+			{Name: "SVN_USER_SECRET", Value: p.Config.Username},
+			{Name: "SVN_PASS_SECRET", Value: p.Config.Password},
+		},
+		WorkingDir: "/workspace",
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ContentInitContainerResponse{Container: container}, nil
+}
+
+func (p *svnPlugin) Serve(ctx context.Context, req *v1.ContentServeRequest) (*v1.ContentServeResponse, error) {
+	// the checkout needs no help from us
+	return &v1.ContentServeResponse{}, nil
+}
+
+func (p *svnPlugin) Download(req *v1.ContentDownloadRequest, srv v1.ContentProviderPlugin_DownloadServer) error {
+	if req.Repository == nil {
+		return status.Error(codes.InvalidArgument, "repository is required")
+	}
+
+	url := p.location(req.Repository, req.Path)
+	if req.Repository.Revision != "" {
+		url += "?p=" + req.Repository.Revision
+	}
+	hreq, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	hreq = hreq.WithContext(srv.Context())
+	if p.Config.Username != "" {
+		hreq.SetBasicAuth(p.Config.Username, p.Config.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return status.Errorf(codes.NotFound, "%s not found", req.Path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return status.Errorf(codes.Unknown, "cannot download %s: %s", req.Path, resp.Status)
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			serr := srv.Send(&v1.ContentDownloadResponse{Data: buf[:n]})
+			if serr != nil {
+				return serr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+	log.WithField("url", url).Debug("downloaded file")
+	return nil
+}
@@ -46,11 +46,14 @@ func (*webhookPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftS
 		filter, err := filterexpr.Parse(nf.Filter)
 		if err != nil {
 			log.WithError(err).Errorf("cannot parse filter for notification %d", idx)
+			// without a filter we'd notify about every single job
+			continue
 		}
 
 		tpl, err := template.New("tpl").Parse(nf.Template)
 		if err != nil {
 			log.WithError(err).Errorf("cannot parse template for notification %d", idx)
+			continue
 		}
 
 		wg.Add(1)
@@ -0,0 +1,262 @@
+package analytics
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// runSuffix matches the run number werft appends to job names, e.g. the .42 in werft-build-master.42
+var runSuffix = regexp.MustCompile(`\.\d+$`)
+
+// JobName returns the name of the job a run belongs to, i.e. the run name without its run number.
+func JobName(run string) string {
+	return runSuffix.ReplaceAllString(run, "")
+}
+
+type jobAcc struct {
+	Name     string
+	Runs     []*v1.JobStatus
+	Failures int
+	Flips    int
+	Total    time.Duration
+	Max      time.Duration
+}
+
+type stepAcc struct {
+	Job   string
+	Step  string
+	Runs  int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Compute aggregates the durations and failure rates of jobs. Only finished jobs are considered.
+// The slowest steps and flakiest jobs lists contain at most top entries.
+func Compute(runs []v1.JobStatus, top int) *v1.GetStatsResponse {
+	var (
+		jobs  = make(map[string]*jobAcc)
+		steps = make(map[string]*stepAcc)
+	)
+	for i := range runs {
+		run := &runs[i]
+		if run.Phase != v1.JobPhase_PHASE_DONE || run.Metadata == nil {
+			continue
+		}
+
+		name := JobName(run.Name)
+		acc, ok := jobs[name]
+		if !ok {
+			acc = &jobAcc{Name: name}
+			jobs[name] = acc
+		}
+		acc.Runs = append(acc.Runs, run)
+
+		finished, err := ptypes.Timestamp(run.Metadata.Finished)
+		if err != nil {
+			finished = time.Time{}
+		}
+		for _, s := range run.Steps {
+			dur, ok := stepDuration(s, finished)
+			if !ok {
+				continue
+			}
+
+			key := name + "/" + s.Name
+			sacc, ok := steps[key]
+			if !ok {
+				sacc = &stepAcc{Job: name, Step: s.Name}
+				steps[key] = sacc
+			}
+			sacc.Runs++
+			sacc.Total += dur
+			if dur > sacc.Max {
+				sacc.Max = dur
+			}
+		}
+	}
+
+	res := &v1.GetStatsResponse{}
+	for _, acc := range jobs {
+		sort.Slice(acc.Runs, func(i, j int) bool {
+			return created(acc.Runs[i]).Before(created(acc.Runs[j]))
+		})
+
+		var durRuns int
+		for i, run := range acc.Runs {
+			success := run.Conditions != nil && run.Conditions.Success
+			if !success {
+				acc.Failures++
+			}
+			if i > 0 {
+				prev := acc.Runs[i-1]
+				if success != (prev.Conditions != nil && prev.Conditions.Success) {
+					acc.Flips++
+				}
+			}
+
+			finished, err := ptypes.Timestamp(run.Metadata.Finished)
+			if err != nil {
+				continue
+			}
+			dur := finished.Sub(created(run))
+			if dur < 0 {
+				continue
+			}
+			durRuns++
+			acc.Total += dur
+			if dur > acc.Max {
+				acc.Max = dur
+			}
+		}
+
+		stats := &v1.JobStats{
+			Job:         acc.Name,
+			Runs:        int32(len(acc.Runs)),
+			Failures:    int32(acc.Failures),
+			FailureRate: float64(acc.Failures) / float64(len(acc.Runs)),
+			MaxDuration: ptypes.DurationProto(acc.Max),
+		}
+		if len(acc.Runs) > 1 {
+			stats.Flakiness = float64(acc.Flips) / float64(len(acc.Runs)-1)
+		}
+		if durRuns > 0 {
+			stats.AvgDuration = ptypes.DurationProto(acc.Total / time.Duration(durRuns))
+		}
+		res.Jobs = append(res.Jobs, stats)
+	}
+	sort.Slice(res.Jobs, func(i, j int) bool { return res.Jobs[i].Job < res.Jobs[j].Job })
+
+	for _, j := range res.Jobs {
+		if j.Flakiness > 0 {
+			res.FlakiestJobs = append(res.FlakiestJobs, j)
+		}
+	}
+	sort.SliceStable(res.FlakiestJobs, func(i, j int) bool { return res.FlakiestJobs[i].Flakiness > res.FlakiestJobs[j].Flakiness })
+	if top > 0 && len(res.FlakiestJobs) > top {
+		res.FlakiestJobs = res.FlakiestJobs[:top]
+	}
+
+	var stepStats []*stepAcc
+	for _, s := range steps {
+		stepStats = append(stepStats, s)
+	}
+	sort.Slice(stepStats, func(i, j int) bool {
+		ai, aj := stepStats[i].Total/time.Duration(stepStats[i].Runs), stepStats[j].Total/time.Duration(stepStats[j].Runs)
+		if ai == aj {
+			return stepStats[i].Job+stepStats[i].Step < stepStats[j].Job+stepStats[j].Step
+		}
+		return ai > aj
+	})
+	if top > 0 && len(stepStats) > top {
+		stepStats = stepStats[:top]
+	}
+	for _, s := range stepStats {
+		res.SlowestSteps = append(res.SlowestSteps, &v1.StepStats{
+			Job:         s.Job,
+			Step:        s.Step,
+			Runs:        int32(s.Runs),
+			AvgDuration: ptypes.DurationProto(s.Total / time.Duration(s.Runs)),
+			MaxDuration: ptypes.DurationProto(s.Max),
+		})
+	}
+
+	return res
+}
+
+func created(run *v1.JobStatus) time.Time {
+	t, _ := ptypes.Timestamp(run.Metadata.Created)
+	return t
+}
+
+// stepDuration computes the duration of a step. Steps which were still running when the job finished
+// (i.e. the last step of a job) are considered to have ended with the job.
+func stepDuration(s *v1.JobStep, jobFinished time.Time) (time.Duration, bool) {
+	started, err := ptypes.Timestamp(s.Started)
+	if err != nil {
+		return 0, false
+	}
+
+	finished := jobFinished
+	if s.Finished != nil {
+		finished, err = ptypes.Timestamp(s.Finished)
+		if err != nil {
+			return 0, false
+		}
+	}
+	if finished.IsZero() || finished.Before(started) {
+		return 0, false
+	}
+	return finished.Sub(started), true
+}
+
+// EstimateDuration estimates the duration of a job based on previous runs of that job, by taking the median
+// duration of all successful runs. If there are no such runs, ok is false.
+func EstimateDuration(runs []v1.JobStatus) (estimate time.Duration, ok bool) {
+	var durs []time.Duration
+	for _, run := range runs {
+		if run.Phase != v1.JobPhase_PHASE_DONE || run.Metadata == nil || run.Conditions == nil || !run.Conditions.Success {
+			continue
+		}
+
+		finished, err := ptypes.Timestamp(run.Metadata.Finished)
+		if err != nil {
+			continue
+		}
+		dur := finished.Sub(created(&run))
+		if dur <= 0 {
+			continue
+		}
+		durs = append(durs, dur)
+	}
+	if len(durs) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(durs, func(i, j int) bool { return durs[i] < durs[j] })
+	return durs[len(durs)/2], true
+}
+
+// ComputeCosts aggregates the cost of finished jobs per repository and month. Jobs without cost are ignored.
+// The result is sorted by month, most recent first, and repository.
+func ComputeCosts(runs []v1.JobStatus) []*v1.CostStats {
+	costs := make(map[string]*v1.CostStats)
+	for i := range runs {
+		run := &runs[i]
+		if run.Phase != v1.JobPhase_PHASE_DONE || run.Metadata == nil || run.Cost == nil {
+			continue
+		}
+
+		var repo string
+		if r := run.Metadata.Repository; r != nil {
+			repo = r.Owner + "/" + r.Repo
+		}
+		month := created(run).UTC().Format("2006-01")
+
+		key := repo + "@" + month
+		acc, ok := costs[key]
+		if !ok {
+			acc = &v1.CostStats{Repository: repo, Month: month, Currency: run.Cost.Currency}
+			costs[key] = acc
+		}
+		acc.Runs++
+		acc.CpuHours += run.Cost.CpuHours
+		acc.MemoryGbHours += run.Cost.MemoryGbHours
+		acc.Amount += run.Cost.Amount
+	}
+
+	res := make([]*v1.CostStats, 0, len(costs))
+	for _, c := range costs {
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Month == res[j].Month {
+			return res[i].Repository < res[j].Repository
+		}
+		return res[i].Month > res[j].Month
+	})
+	return res
+}
@@ -0,0 +1,176 @@
+package analytics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/analytics"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestJobName(t *testing.T) {
+	tests := []struct {
+		Run         string
+		Expectation string
+	}{
+		{"werft-build-master.42", "werft-build-master"},
+		{"werft-build-v1.2", "werft-build-v1"},
+		{"local-foo-happy-cat", "local-foo-happy-cat"},
+	}
+
+	for _, test := range tests {
+		act := analytics.JobName(test.Run)
+		if act != test.Expectation {
+			t.Errorf("%s: expected %s, actual %s", test.Run, test.Expectation, act)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	run := func(name string, start, dur time.Duration, success bool, steps ...*v1.JobStep) v1.JobStatus {
+		created, _ := ptypes.TimestampProto(base.Add(start))
+		finished, _ := ptypes.TimestampProto(base.Add(start + dur))
+		return v1.JobStatus{
+			Name:       name,
+			Phase:      v1.JobPhase_PHASE_DONE,
+			Metadata:   &v1.JobMetadata{Created: created, Finished: finished},
+			Conditions: &v1.JobConditions{Success: success},
+			Steps:      steps,
+		}
+	}
+	step := func(name string, start time.Duration) *v1.JobStep {
+		started, _ := ptypes.TimestampProto(base.Add(start))
+		return &v1.JobStep{Name: name, Started: started}
+	}
+
+	runs := []v1.JobStatus{
+		run("build-master.3", 2*time.Hour, 4*time.Minute, true, step("test", 2*time.Hour)),
+		run("build-master.1", 0, 2*time.Minute, true, step("test", 0)),
+		run("build-master.2", time.Hour, 6*time.Minute, false, step("test", time.Hour)),
+		run("lint-master.1", 0, time.Minute, true),
+		run("lint-master.2", time.Hour, time.Minute, true),
+		{Name: "lint-master.3", Phase: v1.JobPhase_PHASE_RUNNING},
+	}
+
+	res := analytics.Compute(runs, 10)
+	if len(res.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(res.Jobs))
+	}
+
+	build := res.Jobs[0]
+	if build.Job != "build-master" || build.Runs != 3 || build.Failures != 1 {
+		t.Errorf("unexpected build stats: %v", build)
+	}
+	// success, failure, success: both runs after the first one flipped
+	if build.Flakiness != 1 {
+		t.Errorf("expected build flakiness of 1, got %v", build.Flakiness)
+	}
+	if avg, _ := ptypes.Duration(build.AvgDuration); avg != 4*time.Minute {
+		t.Errorf("expected average build duration of 4m, got %v", avg)
+	}
+	if max, _ := ptypes.Duration(build.MaxDuration); max != 6*time.Minute {
+		t.Errorf("expected max build duration of 6m, got %v", max)
+	}
+
+	lint := res.Jobs[1]
+	if lint.Job != "lint-master" || lint.Runs != 2 || lint.Flakiness != 0 {
+		t.Errorf("unexpected lint stats: %v", lint)
+	}
+
+	if len(res.FlakiestJobs) != 1 || res.FlakiestJobs[0].Job != "build-master" {
+		t.Errorf("unexpected flakiest jobs: %v", res.FlakiestJobs)
+	}
+	if len(res.SlowestSteps) != 1 || res.SlowestSteps[0].Step != "test" || res.SlowestSteps[0].Runs != 3 {
+		t.Fatalf("unexpected slowest steps: %v", res.SlowestSteps)
+	}
+	if avg, _ := ptypes.Duration(res.SlowestSteps[0].AvgDuration); avg != 4*time.Minute {
+		t.Errorf("expected average step duration of 4m, got %v", avg)
+	}
+}
+
+func TestEstimateDuration(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(dur time.Duration, phase v1.JobPhase, success bool) v1.JobStatus {
+		created, _ := ptypes.TimestampProto(base)
+		finished, _ := ptypes.TimestampProto(base.Add(dur))
+		return v1.JobStatus{
+			Phase:      phase,
+			Metadata:   &v1.JobMetadata{Created: created, Finished: finished},
+			Conditions: &v1.JobConditions{Success: success},
+		}
+	}
+
+	tests := []struct {
+		Desc       string
+		Runs       []v1.JobStatus
+		Estimate   time.Duration
+		HasEstimate bool
+	}{
+		{"no runs", nil, 0, false},
+		{"only failed runs", []v1.JobStatus{run(time.Minute, v1.JobPhase_PHASE_DONE, false)}, 0, false},
+		{"only running", []v1.JobStatus{run(time.Minute, v1.JobPhase_PHASE_RUNNING, true)}, 0, false},
+		{
+			"median",
+			[]v1.JobStatus{
+				run(3*time.Minute, v1.JobPhase_PHASE_DONE, true),
+				run(time.Hour, v1.JobPhase_PHASE_DONE, true),
+				run(4*time.Minute, v1.JobPhase_PHASE_DONE, true),
+				run(10*time.Second, v1.JobPhase_PHASE_DONE, false),
+			},
+			4 * time.Minute, true,
+		},
+	}
+
+	for _, test := range tests {
+		est, ok := analytics.EstimateDuration(test.Runs)
+		if ok != test.HasEstimate || est != test.Estimate {
+			t.Errorf("%s: expected %v (%v), actual %v (%v)", test.Desc, test.Estimate, test.HasEstimate, est, ok)
+		}
+	}
+}
+
+func TestComputeCosts(t *testing.T) {
+	run := func(repo string, created time.Time, cpuHours, amount float64) v1.JobStatus {
+		ts, _ := ptypes.TimestampProto(created)
+		segs := strings.Split(repo, "/")
+		return v1.JobStatus{
+			Phase: v1.JobPhase_PHASE_DONE,
+			Metadata: &v1.JobMetadata{
+				Created:    ts,
+				Repository: &v1.Repository{Owner: segs[0], Repo: segs[1]},
+			},
+			Cost: &v1.JobCost{CpuHours: cpuHours, Amount: amount, Currency: "USD"},
+		}
+	}
+	jan := time.Date(2020, 1, 31, 23, 0, 0, 0, time.UTC)
+	feb := time.Date(2020, 2, 1, 1, 0, 0, 0, time.UTC)
+
+	runs := []v1.JobStatus{
+		run("foo/bar", jan, 1, 0.5),
+		run("foo/bar", jan, 2, 1),
+		run("foo/bar", feb, 1, 0.5),
+		run("foo/baz", feb, 4, 2),
+		{Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{}, Cost: &v1.JobCost{Amount: 100}},
+		{Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{}},
+	}
+
+	res := analytics.ComputeCosts(runs)
+	expectation := []v1.CostStats{
+		{Repository: "foo/bar", Month: "2020-02", Runs: 1, CpuHours: 1, Amount: 0.5, Currency: "USD"},
+		{Repository: "foo/baz", Month: "2020-02", Runs: 1, CpuHours: 4, Amount: 2, Currency: "USD"},
+		{Repository: "foo/bar", Month: "2020-01", Runs: 2, CpuHours: 3, Amount: 1.5, Currency: "USD"},
+	}
+	if len(res) != len(expectation) {
+		t.Fatalf("expected %d entries, got %d", len(expectation), len(res))
+	}
+	for i, exp := range expectation {
+		if !proto.Equal(res[i], &exp) {
+			t.Errorf("entry %d: expected %v, actual %v", i, exp, res[i])
+		}
+	}
+}
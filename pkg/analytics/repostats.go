@@ -0,0 +1,190 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	counterRuns     = "runs"
+	counterFailures = "failures"
+	// counterFailurePrefix prefixes the counters of failures per failure class, e.g. failure.checkout
+	counterFailurePrefix = "failure."
+	// counterDurationPrefix prefixes the histogram buckets of job durations, e.g. duration.le300
+	counterDurationPrefix = "duration."
+	// counterQueuePrefix prefixes the histogram buckets of queue latencies, e.g. queue.le60
+	counterQueuePrefix = "queue."
+	// counterPreparePrefix prefixes the histogram buckets of the time jobs took to prepare, e.g. prepare.le30
+	counterPreparePrefix = "prepare."
+	// counterRunPrefix prefixes the histogram buckets of the time the main container of jobs ran, e.g. run.le300
+	counterRunPrefix = "run."
+)
+
+// HistogramBuckets are the upper bounds of the histogram buckets we count job durations and queue latencies in.
+// Anything longer than the last bound ends up in an overflow bucket.
+var HistogramBuckets = []time.Duration{
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	1 * time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	20 * time.Minute,
+	30 * time.Minute,
+	45 * time.Minute,
+	1 * time.Hour,
+	2 * time.Hour,
+	4 * time.Hour,
+	8 * time.Hour,
+}
+
+// histogramBucket returns the name of the histogram bucket a duration falls into
+func histogramBucket(prefix string, d time.Duration) string {
+	for _, b := range HistogramBuckets {
+		if d <= b {
+			return fmt.Sprintf("%sle%d", prefix, int64(b.Seconds()))
+		}
+	}
+	return prefix + "inf"
+}
+
+// StatsCounters returns the counters a finished job adds to the statistics of its repository and ref.
+// Jobs which have not finished yet do not count.
+func StatsCounters(run *v1.JobStatus) map[string]int64 {
+	if run.Phase != v1.JobPhase_PHASE_DONE || run.Metadata == nil {
+		return nil
+	}
+
+	res := map[string]int64{counterRuns: 1}
+	if run.Conditions == nil || !run.Conditions.Success {
+		res[counterFailures] = 1
+
+		class := v1.JobFailureClass_FAILURE_UNCLASSIFIED
+		if run.Conditions != nil {
+			class = run.Conditions.FailureClass
+		}
+		res[counterFailurePrefix+strings.ToLower(strings.TrimPrefix(class.String(), "FAILURE_"))] = 1
+	}
+
+	created := created(run)
+	if finished, err := ptypes.Timestamp(run.Metadata.Finished); err == nil && !finished.Before(created) {
+		res[histogramBucket(counterDurationPrefix, finished.Sub(created))] = 1
+	}
+	if started, err := ptypes.Timestamp(run.Metadata.Started); err == nil && !started.Before(created) {
+		res[histogramBucket(counterQueuePrefix, started.Sub(created))] = 1
+	}
+
+	// jobs which ran before we recorded their phase timestamps only count towards the overall duration
+	if ts := run.Timestamps; ts != nil {
+		if d, ok := between(ts.Preparing, ts.Running); ok {
+			res[histogramBucket(counterPreparePrefix, d)] = 1
+		}
+		if d, ok := between(ts.Running, ts.Finished); ok {
+			res[histogramBucket(counterRunPrefix, d)] = 1
+		}
+	}
+	return res
+}
+
+// between returns the time between two timestamps, or false if either is missing or they are out of order
+func between(from, to *tspb.Timestamp) (time.Duration, bool) {
+	f, err := ptypes.Timestamp(from)
+	if err != nil {
+		return 0, false
+	}
+	t, err := ptypes.Timestamp(to)
+	if err != nil || t.Before(f) {
+		return 0, false
+	}
+	return t.Sub(f), true
+}
+
+// ComputeRepoStats computes the statistics of a repository and ref from their summed up counters
+func ComputeRepoStats(repository, ref string, counters map[string]int64) *v1.RepoStats {
+	res := &v1.RepoStats{
+		Repository: repository,
+		Ref:        ref,
+		Runs:       int32(counters[counterRuns]),
+		Failures:   int32(counters[counterFailures]),
+	}
+	if res.Runs > 0 {
+		res.SuccessRate = float64(res.Runs-res.Failures) / float64(res.Runs)
+	}
+
+	if p, ok := histogramPercentile(counters, counterDurationPrefix, 0.5); ok {
+		res.P50Duration = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterDurationPrefix, 0.95); ok {
+		res.P95Duration = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterQueuePrefix, 0.5); ok {
+		res.P50QueueLatency = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterQueuePrefix, 0.95); ok {
+		res.P95QueueLatency = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterPreparePrefix, 0.5); ok {
+		res.P50PrepareDuration = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterPreparePrefix, 0.95); ok {
+		res.P95PrepareDuration = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterRunPrefix, 0.5); ok {
+		res.P50RunDuration = ptypes.DurationProto(p)
+	}
+	if p, ok := histogramPercentile(counters, counterRunPrefix, 0.95); ok {
+		res.P95RunDuration = ptypes.DurationProto(p)
+	}
+
+	// we list the failure causes in the order of the failure classes
+	for i := int32(0); i < int32(len(v1.JobFailureClass_name)); i++ {
+		class := v1.JobFailureClass(i)
+		n := counters[counterFailurePrefix+strings.ToLower(strings.TrimPrefix(class.String(), "FAILURE_"))]
+		if n == 0 {
+			continue
+		}
+		res.FailureCauses = append(res.FailureCauses, &v1.FailureCauseStats{
+			FailureClass: class,
+			Count:        int32(n),
+		})
+	}
+	return res
+}
+
+// histogramPercentile estimates the p-th percentile (between 0 and 1) of a histogram by interpolating within
+// the bucket the percentile falls into. If the histogram is empty, ok is false.
+func histogramPercentile(counters map[string]int64, prefix string, p float64) (res time.Duration, ok bool) {
+	var total int64
+	for _, b := range HistogramBuckets {
+		total += counters[histogramBucket(prefix, b)]
+	}
+	overflow := counters[prefix+"inf"]
+	total += overflow
+	if total == 0 {
+		return 0, false
+	}
+
+	var (
+		rank  = p * float64(total)
+		cum   int64
+		lower time.Duration
+	)
+	for _, b := range HistogramBuckets {
+		n := counters[histogramBucket(prefix, b)]
+		if n > 0 && float64(cum+n) >= rank {
+			frac := (rank - float64(cum)) / float64(n)
+			return (lower + time.Duration(frac*float64(b-lower))).Round(time.Millisecond), true
+		}
+		cum += n
+		lower = b
+	}
+	// we cannot tell how long runs in the overflow bucket took, hence use its lower bound
+	return lower, true
+}
@@ -0,0 +1,139 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/analytics"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestComputeRepoStats(t *testing.T) {
+	created := time.Date(2020, 3, 28, 10, 0, 0, 0, time.UTC)
+	run := func(queued, duration time.Duration, success bool, class v1.JobFailureClass) *v1.JobStatus {
+		c, _ := ptypes.TimestampProto(created)
+		s, _ := ptypes.TimestampProto(created.Add(queued))
+		f, _ := ptypes.TimestampProto(created.Add(duration))
+		return &v1.JobStatus{
+			Phase:      v1.JobPhase_PHASE_DONE,
+			Metadata:   &v1.JobMetadata{Created: c, Started: s, Finished: f},
+			Conditions: &v1.JobConditions{Success: success, FailureClass: class},
+		}
+	}
+
+	phased := func(prepare, running time.Duration) *v1.JobStatus {
+		res := run(0, prepare+running, true, v1.JobFailureClass_FAILURE_UNCLASSIFIED)
+		p, _ := ptypes.TimestampProto(created.Add(prepare))
+		res.Timestamps = &v1.JobTimestamps{
+			Queued:    res.Metadata.Created,
+			Preparing: res.Metadata.Created,
+			Running:   p,
+			Finished:  res.Metadata.Finished,
+		}
+		return res
+	}
+
+	tests := []struct {
+		Name        string
+		Runs        []*v1.JobStatus
+		Expectation *v1.RepoStats
+	}{
+		{
+			Name: "no runs",
+			Expectation: &v1.RepoStats{
+				Repository: "foo/bar",
+				Ref:        "refs/heads/master",
+			},
+		},
+		{
+			Name: "unfinished runs do not count",
+			Runs: []*v1.JobStatus{
+				{Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{}},
+			},
+			Expectation: &v1.RepoStats{
+				Repository: "foo/bar",
+				Ref:        "refs/heads/master",
+			},
+		},
+		{
+			Name: "mixed runs",
+			Runs: []*v1.JobStatus{
+				run(3*time.Second, 1*time.Minute, true, v1.JobFailureClass_FAILURE_UNCLASSIFIED),
+				run(3*time.Second, 1*time.Minute, true, v1.JobFailureClass_FAILURE_UNCLASSIFIED),
+				run(3*time.Second, 1*time.Minute, false, v1.JobFailureClass_FAILURE_TEST),
+				run(3*time.Second, 10*time.Minute, false, v1.JobFailureClass_FAILURE_CHECKOUT),
+			},
+			Expectation: &v1.RepoStats{
+				Repository:      "foo/bar",
+				Ref:             "refs/heads/master",
+				Runs:            4,
+				Failures:        2,
+				SuccessRate:     0.5,
+				P50Duration:     ptypes.DurationProto(50 * time.Second),
+				P95Duration:     ptypes.DurationProto(9 * time.Minute),
+				P50QueueLatency: ptypes.DurationProto(2500 * time.Millisecond),
+				P95QueueLatency: ptypes.DurationProto(4750 * time.Millisecond),
+				FailureCauses: []*v1.FailureCauseStats{
+					{FailureClass: v1.JobFailureClass_FAILURE_CHECKOUT, Count: 1},
+					{FailureClass: v1.JobFailureClass_FAILURE_TEST, Count: 1},
+				},
+			},
+		},
+		{
+			Name: "overflow",
+			Runs: []*v1.JobStatus{
+				run(0, 24*time.Hour, true, v1.JobFailureClass_FAILURE_UNCLASSIFIED),
+			},
+			Expectation: &v1.RepoStats{
+				Repository:      "foo/bar",
+				Ref:             "refs/heads/master",
+				Runs:            1,
+				SuccessRate:     1,
+				P50Duration:     ptypes.DurationProto(8 * time.Hour),
+				P95Duration:     ptypes.DurationProto(8 * time.Hour),
+				P50QueueLatency: ptypes.DurationProto(2500 * time.Millisecond),
+				P95QueueLatency: ptypes.DurationProto(4750 * time.Millisecond),
+			},
+		},
+		{
+			Name: "phase breakdown",
+			Runs: []*v1.JobStatus{
+				phased(20*time.Second, 4*time.Minute),
+				phased(20*time.Second, 4*time.Minute),
+				run(0, 1*time.Minute, true, v1.JobFailureClass_FAILURE_UNCLASSIFIED),
+			},
+			Expectation: &v1.RepoStats{
+				Repository:         "foo/bar",
+				Ref:                "refs/heads/master",
+				Runs:               3,
+				SuccessRate:        1,
+				P50Duration:        ptypes.DurationProto(2*time.Minute + 45*time.Second),
+				P95Duration:        ptypes.DurationProto(4*time.Minute + 46500*time.Millisecond),
+				P50QueueLatency:    ptypes.DurationProto(2500 * time.Millisecond),
+				P95QueueLatency:    ptypes.DurationProto(4750 * time.Millisecond),
+				P50PrepareDuration: ptypes.DurationProto(20 * time.Second),
+				P95PrepareDuration: ptypes.DurationProto(29 * time.Second),
+				P50RunDuration:     ptypes.DurationProto(3*time.Minute + 30*time.Second),
+				P95RunDuration:     ptypes.DurationProto(4*time.Minute + 51*time.Second),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			counters := make(map[string]int64)
+			for _, r := range test.Runs {
+				for c, v := range analytics.StatsCounters(r) {
+					counters[c] += v
+				}
+			}
+
+			res := analytics.ComputeRepoStats("foo/bar", "refs/heads/master", counters)
+			if !proto.Equal(res, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package repoconfig
+
+import (
+	"regexp"
+	"strconv"
+
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// Types of annotation values
+const (
+	AnnotationTypeString = "string"
+	AnnotationTypeBool   = "bool"
+	AnnotationTypeInt    = "int"
+)
+
+// What happens to annotations a repository does not allow
+const (
+	// UnknownAnnotationsReject refuses to start jobs with annotations which are not allowed
+	UnknownAnnotationsReject = "reject"
+	// UnknownAnnotationsStrip starts jobs without the annotations which are not allowed
+	UnknownAnnotationsStrip = "strip"
+)
+
+// AnnotationPolicy restricts the annotations jobs of a repository accept, s.t. arbitrary user input cannot flow into
+// their job specs
+type AnnotationPolicy struct {
+	// Allowed are the annotations jobs accept
+	Allowed []*AnnotationSpec `yaml:"allowed"`
+	// Unknown decides what happens to annotations which are not allowed: reject (default) or strip
+	Unknown string `yaml:"unknown,omitempty"`
+}
+
+// UnmarshalYAML validates an annotation policy
+func (p *AnnotationPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawAnnotationPolicy AnnotationPolicy
+	var raw rawAnnotationPolicy
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+	switch raw.Unknown {
+	case "", UnknownAnnotationsReject, UnknownAnnotationsStrip:
+	default:
+		return xerrors.Errorf("annotations: unknown policy \"%s\" for unknown annotations - must be %s or %s", raw.Unknown, UnknownAnnotationsReject, UnknownAnnotationsStrip)
+	}
+	seen := make(map[string]struct{}, len(raw.Allowed))
+	for _, a := range raw.Allowed {
+		if _, ok := seen[a.Name]; ok {
+			return xerrors.Errorf("annotations: %s is allowed twice", a.Name)
+		}
+		seen[a.Name] = struct{}{}
+	}
+
+	*p = AnnotationPolicy(raw)
+	return nil
+}
+
+// AnnotationSpec describes an annotation jobs accept
+type AnnotationSpec struct {
+	Name string `yaml:"name"`
+	// Type is the type of the value: string (default), bool or int
+	Type string `yaml:"type,omitempty"`
+	// Pattern is a regular expression the whole value must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// Default is the value of the annotation for jobs which do not set it
+	Default *string `yaml:"default,omitempty"`
+}
+
+// UnmarshalYAML validates an annotation spec
+func (a *AnnotationSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawAnnotationSpec AnnotationSpec
+	var raw rawAnnotationSpec
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+	if raw.Name == "" {
+		return xerrors.Errorf("allowed annotations need a name")
+	}
+	switch raw.Type {
+	case "", AnnotationTypeString, AnnotationTypeBool, AnnotationTypeInt:
+	default:
+		return xerrors.Errorf("annotation %s: unknown type \"%s\" - must be %s, %s or %s", raw.Name, raw.Type, AnnotationTypeString, AnnotationTypeBool, AnnotationTypeInt)
+	}
+	if _, err := regexp.Compile(raw.Pattern); err != nil {
+		return xerrors.Errorf("annotation %s: invalid pattern: %w", raw.Name, err)
+	}
+
+	spec := AnnotationSpec(raw)
+	if spec.Default != nil {
+		if err := spec.Validate(*spec.Default); err != nil {
+			return xerrors.Errorf("annotation %s: invalid default: %w", raw.Name, err)
+		}
+	}
+	*a = spec
+	return nil
+}
+
+// Validate returns an error if the value is not of the type of the annotation or does not match its pattern
+func (a *AnnotationSpec) Validate(value string) error {
+	switch a.Type {
+	case AnnotationTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return xerrors.Errorf("\"%s\" is not a bool", value)
+		}
+	case AnnotationTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return xerrors.Errorf("\"%s\" is not an int", value)
+		}
+	}
+	if a.Pattern == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile("^(?:" + a.Pattern + ")$")
+	if err != nil {
+		return xerrors.Errorf("invalid pattern: %w", err)
+	}
+	if !re.MatchString(value) {
+		return xerrors.Errorf("\"%s\" does not match %s", value, a.Pattern)
+	}
+	return nil
+}
+
+// Apply checks annotations against the policy and returns those a job gets: the allowed ones, plus the defaults of the
+// allowed ones which are not set. Annotations which are not allowed are an error unless the policy strips them.
+func (p *AnnotationPolicy) Apply(annotations []*werftv1.Annotation) ([]*werftv1.Annotation, error) {
+	specs := make(map[string]*AnnotationSpec, len(p.Allowed))
+	for _, a := range p.Allowed {
+		specs[a.Name] = a
+	}
+
+	var (
+		res = make([]*werftv1.Annotation, 0, len(annotations))
+		set = make(map[string]struct{}, len(annotations))
+	)
+	for _, a := range annotations {
+		spec, ok := specs[a.Key]
+		if !ok {
+			if p.Unknown == UnknownAnnotationsStrip {
+				continue
+			}
+			return nil, xerrors.Errorf("annotation %s is not allowed in this repository", a.Key)
+		}
+		if err := spec.Validate(a.Value); err != nil {
+			return nil, xerrors.Errorf("annotation %s: %w", a.Key, err)
+		}
+		res = append(res, a)
+		set[a.Key] = struct{}{}
+	}
+	for _, spec := range p.Allowed {
+		if _, ok := set[spec.Name]; ok || spec.Default == nil {
+			continue
+		}
+		res = append(res, &werftv1.Annotation{Key: spec.Name, Value: *spec.Default})
+	}
+	return res, nil
+}
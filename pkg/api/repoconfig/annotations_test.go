@@ -0,0 +1,109 @@
+package repoconfig_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"gopkg.in/yaml.v3"
+)
+
+const testAnnotationPolicy = `allowed:
+- name: version
+  pattern: v[0-9]+\.[0-9]+
+- name: deploy
+  type: bool
+  default: "false"
+- name: replicas
+  type: int
+`
+
+func TestUnmarshalAnnotationPolicy(t *testing.T) {
+	tests := []struct {
+		Source string
+		Error  bool
+	}{
+		{testAnnotationPolicy, false},
+		{"unknown: strip\nallowed:\n- name: version", false},
+		{"unknown: ignore\nallowed:\n- name: version", true},
+		{"allowed:\n- type: bool", true},
+		{"allowed:\n- name: deploy\n  type: boolean", true},
+		{"allowed:\n- name: version\n  pattern: v[0-9", true},
+		{"allowed:\n- name: deploy\n  type: bool\n  default: maybe", true},
+		{"allowed:\n- name: version\n- name: version", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Source, func(t *testing.T) {
+			var p repoconfig.AnnotationPolicy
+			err := yaml.Unmarshal([]byte(test.Source), &p)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestAnnotationPolicyApply(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Unknown     string
+		Annotations []*v1.Annotation
+		Expectation []*v1.Annotation
+		Error       string
+	}{
+		{
+			Name:        "defaults",
+			Expectation: []*v1.Annotation{{Key: "deploy", Value: "false"}},
+		},
+		{
+			Name:        "valid",
+			Annotations: []*v1.Annotation{{Key: "version", Value: "v1.2"}, {Key: "deploy", Value: "true"}, {Key: "replicas", Value: "3"}},
+			Expectation: []*v1.Annotation{{Key: "version", Value: "v1.2"}, {Key: "deploy", Value: "true"}, {Key: "replicas", Value: "3"}},
+		},
+		{
+			Name:        "pattern mismatch",
+			Annotations: []*v1.Annotation{{Key: "version", Value: "v1.2; rm -rf /"}},
+			Error:       "annotation version: \"v1.2; rm -rf /\" does not match v[0-9]+\\.[0-9]+",
+		},
+		{
+			Name:        "wrong type",
+			Annotations: []*v1.Annotation{{Key: "replicas", Value: "many"}},
+			Error:       "annotation replicas: \"many\" is not an int",
+		},
+		{
+			Name:        "unknown rejected",
+			Annotations: []*v1.Annotation{{Key: "image", Value: "evil"}},
+			Error:       "annotation image is not allowed in this repository",
+		},
+		{
+			Name:        "unknown stripped",
+			Unknown:     repoconfig.UnknownAnnotationsStrip,
+			Annotations: []*v1.Annotation{{Key: "image", Value: "evil"}, {Key: "replicas", Value: "3"}},
+			Expectation: []*v1.Annotation{{Key: "replicas", Value: "3"}, {Key: "deploy", Value: "false"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var p repoconfig.AnnotationPolicy
+			err := yaml.Unmarshal([]byte(testAnnotationPolicy), &p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Unknown = test.Unknown
+
+			act, err := p.Apply(test.Annotations)
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if err == nil && !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
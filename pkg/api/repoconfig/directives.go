@@ -0,0 +1,102 @@
+package repoconfig
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+var (
+	// directiveExpr finds directives in commit messages, e.g. [skip ci] or [werft run job=integration]
+	directiveExpr = regexp.MustCompile(`\[([^\[\]\n]+)\]`)
+	// directiveJobExpr restricts the jobs directives can select to files in the werft directory
+	directiveJobExpr = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// directiveKeyExpr restricts the annotation keys directives can set
+	directiveKeyExpr = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// skipDirectives are the directives which make werft ignore a push
+var skipDirectives = []string{"skip ci", "ci skip", "werft skip"}
+
+// CommitDirectives are instructions for werft found in a commit message
+type CommitDirectives struct {
+	// Skip is true if no job should run for the commit, e.g. because of [skip ci]
+	Skip bool
+	// Runs lists the jobs to start instead of the one the repo config selects
+	Runs []*RunDirective
+}
+
+// RunDirective starts a job, e.g. [werft run job=integration]
+type RunDirective struct {
+	// Job is the name of the job file in the werft directory without its extension. If empty, the repo config selects the job.
+	Job string
+	// Annotations are added to the job. Directives which set the annotations werft uses itself start no job.
+	Annotations map[string]string
+}
+
+// JobPath returns the path of the job file the directive selects, or an empty string if the repo config selects the job
+func (r *RunDirective) JobPath() string {
+	if r.Job == "" {
+		return ""
+	}
+	return ".werft/" + r.Job + ".yaml"
+}
+
+// ParseCommitDirectives finds the directives in a commit message. Supported directives are [skip ci], [ci skip] and
+// [werft skip] which skip the commit, and [werft run job=<name> <key>=<value> ...] which starts the job in
+// .werft/<name>.yaml with the remaining pairs as annotations. Each run directive starts a job of its own.
+// Malformed run directives are ignored and reported as error, while all other directives still apply.
+func ParseCommitDirectives(msg string) (*CommitDirectives, error) {
+	var (
+		res  CommitDirectives
+		errs []string
+	)
+	for _, m := range directiveExpr.FindAllStringSubmatch(msg, -1) {
+		directive := strings.Join(strings.Fields(strings.ToLower(m[1])), " ")
+		for _, s := range skipDirectives {
+			if directive == s {
+				res.Skip = true
+			}
+		}
+
+		fields := strings.Fields(m[1])
+		if len(fields) < 2 || strings.ToLower(fields[0]) != "werft" || strings.ToLower(fields[1]) != "run" {
+			continue
+		}
+		run, err := parseRunDirective(fields[2:])
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("%s: %w", m[0], err).Error())
+			continue
+		}
+		res.Runs = append(res.Runs, run)
+	}
+
+	if len(errs) > 0 {
+		return &res, xerrors.Errorf("invalid commit directives: %s", strings.Join(errs, "; "))
+	}
+	return &res, nil
+}
+
+func parseRunDirective(args []string) (*RunDirective, error) {
+	res := &RunDirective{Annotations: make(map[string]string)}
+	for _, arg := range args {
+		segs := strings.SplitN(arg, "=", 2)
+		if len(segs) != 2 {
+			return nil, xerrors.Errorf("\"%s\" is not a key=value pair", arg)
+		}
+		key, value := segs[0], segs[1]
+		if key == "job" {
+			if !directiveJobExpr.MatchString(value) || strings.Contains(value, "..") {
+				return nil, xerrors.Errorf("invalid job name \"%s\"", value)
+			}
+			res.Job = value
+			continue
+		}
+		if !directiveKeyExpr.MatchString(key) {
+			return nil, xerrors.Errorf("invalid annotation \"%s\"", key)
+		}
+		res.Annotations[key] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package repoconfig_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+)
+
+func TestParseCommitDirectives(t *testing.T) {
+	tests := []struct {
+		Message     string
+		Expectation string
+		Error       bool
+	}{
+		{"fix the build", `{"Skip":false,"Runs":null}`, false},
+		{"update docs [skip ci]", `{"Skip":true,"Runs":null}`, false},
+		{"update docs\n\n[CI Skip]", `{"Skip":true,"Runs":null}`, false},
+		{"[werft  skip] wip", `{"Skip":true,"Runs":null}`, false},
+		{"[skip tests] is not a directive", `{"Skip":false,"Runs":null}`, false},
+		{"add benchmark [werft run job=integration]", `{"Skip":false,"Runs":[{"Job":"integration","Annotations":{}}]}`, false},
+		{"[werft run job=integration] [werft run job=bench size=large]", `{"Skip":false,"Runs":[{"Job":"integration","Annotations":{}},{"Job":"bench","Annotations":{"size":"large"}}]}`, false},
+		{"[werft run version=1.2.3]", `{"Skip":false,"Runs":[{"Job":"","Annotations":{"version":"1.2.3"}}]}`, false},
+		{"[werft run job=../secrets]", `{"Skip":false,"Runs":null}`, true},
+		{"[werft run job=a/b] [werft run job=ok]", `{"Skip":false,"Runs":[{"Job":"ok","Annotations":{}}]}`, true},
+		{"[werft run verbose] [skip ci]", `{"Skip":true,"Runs":null}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Message, func(t *testing.T) {
+			res, err := repoconfig.ParseCommitDirectives(test.Message)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+			act, _ := json.Marshal(res)
+			if string(act) != test.Expectation {
+				t.Errorf("expected %s, got %s", test.Expectation, string(act))
+			}
+		})
+	}
+}
+
+func TestRunDirectiveJobPath(t *testing.T) {
+	if p := (&repoconfig.RunDirective{Job: "integration"}).JobPath(); p != ".werft/integration.yaml" {
+		t.Errorf("expected .werft/integration.yaml, got %s", p)
+	}
+	if p := (&repoconfig.RunDirective{}).JobPath(); p != "" {
+		t.Errorf("expected no path without job, got %s", p)
+	}
+}
@@ -0,0 +1,12 @@
+package repoconfig
+
+import "time"
+
+// SetTemplateLimits changes the limits of ExecuteTemplate for tests. The returned function restores them.
+func SetTemplateLimits(size int, timeout time.Duration) (restore func()) {
+	oldSize, oldTimeout := maxRenderedJobSpecSize, templateTimeout
+	maxRenderedJobSpecSize, templateTimeout = size, timeout
+	return func() {
+		maxRenderedJobSpecSize, templateTimeout = oldSize, oldTimeout
+	}
+}
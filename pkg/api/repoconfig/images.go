@@ -0,0 +1,74 @@
+package repoconfig
+
+import (
+	"path"
+	"strings"
+)
+
+// ImageBuilder is the tool which builds the images of a job
+type ImageBuilder string
+
+const (
+	// ImageBuilderKaniko builds images using kaniko, which needs no privileges
+	ImageBuilderKaniko ImageBuilder = "kaniko"
+	// ImageBuilderBuildKit builds images using BuildKit, which runs privileged
+	ImageBuilderBuildKit ImageBuilder = "buildkit"
+)
+
+// ImageBuild builds a container image from the workspace and pushes it to one or more registries. The image builds of a job
+// run at the same time once all steps of the job succeeded. The digest of each image becomes a result of the job.
+type ImageBuild struct {
+	// Name names the step which builds the image
+	Name string `yaml:"name" json:"name"`
+
+	// Context is the directory in the workspace which is the build context. Defaults to the workspace itself.
+	Context string `yaml:"context,omitempty" json:"context,omitempty"`
+
+	// Dockerfile is the path of the Dockerfile within the build context. Defaults to Dockerfile.
+	Dockerfile string `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
+
+	// Destinations are the references the image is pushed to, e.g. eu.gcr.io/some-project/some-image:tag
+	Destinations []string `yaml:"destinations" json:"destinations"`
+
+	// BuildArgs are passed to the build as build arguments
+	BuildArgs map[string]string `yaml:"buildArgs,omitempty" json:"buildArgs,omitempty"`
+
+	// Builder is the tool which builds the image. Defaults to kaniko.
+	Builder ImageBuilder `yaml:"builder,omitempty" json:"builder,omitempty"`
+
+	// RegistrySecret names a secret bound to the repository which holds the registry credentials,
+	// i.e. a secret of type kubernetes.io/dockerconfigjson. Without it the build uses the registry credentials
+	// of the repository, if there are any.
+	RegistrySecret string `yaml:"registrySecret,omitempty" json:"registrySecret,omitempty"`
+}
+
+// ContextPath returns the path of the build context relative to the workspace
+func (b *ImageBuild) ContextPath() string {
+	return path.Clean(b.Context)
+}
+
+// DockerfilePath returns the path of the Dockerfile relative to the workspace
+func (b *ImageBuild) DockerfilePath() string {
+	df := b.Dockerfile
+	if df == "" {
+		df = "Dockerfile"
+	}
+	return path.Join(b.ContextPath(), df)
+}
+
+// GetBuilder returns the tool which builds the image
+func (b *ImageBuild) GetBuilder() ImageBuilder {
+	if b.Builder == "" {
+		return ImageBuilderKaniko
+	}
+	return b.Builder
+}
+
+// isWorkspacePath returns true if p is a relative path which stays within the workspace
+func isWorkspacePath(p string) bool {
+	if path.IsAbs(p) {
+		return false
+	}
+	p = path.Clean(p)
+	return p != ".." && !strings.HasPrefix(p, "../")
+}
@@ -0,0 +1,439 @@
+package repoconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Severity describes how severe a lint problem is
+type Severity string
+
+const (
+	// SeverityError marks problems which will prevent the job from starting
+	SeverityError Severity = "error"
+	// SeverityWarning marks problems which might lead to unexpected behaviour
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is an issue found while linting a job spec
+type Problem struct {
+	// Line is the line of the job spec the problem was found on, or zero if unknown
+	Line     int
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%d: %s: %s", p.Line, p.Severity, p.Message)
+}
+
+// WorkspaceVolume and CheckoutContainer are added to every job by werft and must not be used by job specs.
+// StepsVolume and StepRunnerContainer are added to jobs with steps.
+const (
+	WorkspaceVolume     = "werft-workspace"
+	CheckoutContainer   = "werft-checkout"
+	StepsVolume         = "werft-steps"
+	StepRunnerContainer = "werft-step-runner"
+)
+
+var (
+	templateErrLine = regexp.MustCompile(`^template: [^:]+:(\d+)(?::\d+)?: (.*)$`)
+	yamlErrLine     = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// LintJobSpec checks a job spec for problems before it's used to start a job. It executes the job template against the
+// metadata, checks the result for unknown or misplaced fields and validates the pod spec.
+// Line numbers refer to the rendered job spec, which matches the template unless template actions produce several lines.
+func LintJobSpec(tpl []byte, name string, md *werftv1.JobMetadata) []Problem {
+	jobTpl, err := template.New("job").Funcs(TemplateFuncs(false)).Parse(string(tpl))
+	if err != nil {
+		return []Problem{problemFromError(err, templateErrLine)}
+	}
+	rendered, err := ExecuteTemplate(jobTpl, NewTemplateObj(name, md))
+	if err != nil {
+		return []Problem{problemFromError(err, templateErrLine)}
+	}
+	buf := bytes.NewBuffer(rendered)
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(buf.Bytes(), &doc)
+	if err != nil {
+		return []Problem{problemFromError(err, yamlErrLine)}
+	}
+
+	l := &linter{lines: make(map[string]int)}
+	l.walk(&doc, reflect.TypeOf(JobSpec{}), "")
+
+	// we have to use the Kubernetes YAML decoder to decode the podspec
+	var jobspec JobSpec
+	err = k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(buf.Bytes()), 4096).Decode(&jobspec)
+	if err != nil {
+		// structural problems we found already are the likely cause and come with better messages
+		if len(l.problems) == 0 {
+			l.report("", SeverityError, "cannot decode job spec: %v", err)
+		}
+	} else {
+		l.checkJobSpec(&jobspec, md)
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool { return l.problems[i].Line < l.problems[j].Line })
+	return l.problems
+}
+
+// problemFromError extracts the line number from template or YAML errors
+func problemFromError(err error, lineExpr *regexp.Regexp) Problem {
+	msg := err.Error()
+	m := lineExpr.FindStringSubmatch(msg)
+	if m == nil {
+		return Problem{Severity: SeverityError, Message: msg}
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	return Problem{Line: line, Severity: SeverityError, Message: m[2]}
+}
+
+type linter struct {
+	problems []Problem
+	// lines maps field paths, e.g. pod.containers[0].image, to the line they're defined on
+	lines map[string]int
+}
+
+func (l *linter) report(path string, sev Severity, format string, args ...interface{}) {
+	var line int
+	if path != "" {
+		line = l.lines[path]
+	}
+	l.problems = append(l.problems, Problem{
+		Line:     line,
+		Severity: sev,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// walk compares the YAML structure to the Go type it will be decoded into
+func (l *linter) walk(node *yaml.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			l.walk(node.Content[0], t, path)
+		}
+		return
+	case yaml.AliasNode:
+		l.walk(node.Alias, t, path)
+		return
+	}
+
+	l.lines[path] = node.Line
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if t.Implements(jsonUnmarshaler) || reflect.PtrTo(t).Implements(jsonUnmarshaler) {
+		// types such as resource quantities bring their own format
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			l.report(path, SeverityError, "%s: expected an object", displayPath(path))
+			return
+		}
+		fields := structFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			ft, ok := fields[key.Value]
+			if !ok {
+				l.problems = append(l.problems, Problem{
+					Line:     key.Line,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%s: unknown field \"%s\"", displayPath(path), key.Value),
+				})
+				continue
+			}
+			l.walk(val, ft, joinPath(path, key.Value))
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			l.report(path, SeverityError, "%s: expected an object", displayPath(path))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			l.walk(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64 string
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			l.report(path, SeverityError, "%s: expected a list", displayPath(path))
+			return
+		}
+		for i, c := range node.Content {
+			l.walk(c, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			l.report(path, SeverityError, "%s: expected a single value", displayPath(path))
+		}
+	}
+}
+
+// structFields returns the fields of a struct by the name they're decoded from. Kubernetes types use JSON tags,
+// our own types YAML tags.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	res := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "" {
+			tag = f.Tag.Get("yaml")
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			for n, t := range structFields(ft) {
+				res[n] = t
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		res[name] = f.Type
+	}
+	return res
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "job spec"
+	}
+	return path
+}
+
+// checkJobSpec validates the decoded job spec
+func (l *linter) checkJobSpec(js *JobSpec, md *werftv1.JobMetadata) {
+	annotations := make(map[string]struct{})
+	for _, a := range md.Annotations {
+		annotations[a.Key] = struct{}{}
+	}
+	for i, arg := range js.Args {
+		if _, ok := annotations[arg.Name]; arg.Req && !ok {
+			l.report(fmt.Sprintf("args[%d]", i), SeverityWarning, "required argument \"%s\" is not set", arg.Name)
+		}
+	}
+
+	if js.OnSuccess != nil {
+		for i, d := range js.OnSuccess.Trigger {
+			path := fmt.Sprintf("onSuccess.trigger[%d]", i)
+			if segs := strings.Split(d.Repo, "/"); len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+				l.report(path, SeverityError, "repo \"%s\" is not in the form of owner/repo", d.Repo)
+			}
+		}
+	}
+
+	if js.Retry != nil {
+		rules := []struct {
+			Path string
+			Rule *RetryRule
+		}{
+			{"retry.infrastructure", js.Retry.Infrastructure},
+			{"retry.test", js.Retry.Test},
+		}
+		for _, rule := range rules {
+			r, path := rule.Rule, rule.Path
+			if r == nil {
+				continue
+			}
+			if r.Attempts < 0 {
+				l.report(path+".attempts", SeverityError, "retry attempts must not be negative")
+			}
+			if d, err := r.DelayDuration(); err != nil || d < 0 {
+				l.report(path+".delay", SeverityError, "retry delay \"%s\" is not a positive duration, e.g. 30s", r.Delay)
+			}
+		}
+	}
+
+	pod := js.Pod
+	hasSteps := len(js.Steps) > 0 || len(js.BuildImages) > 0
+
+	platform, err := js.GetPlatform()
+	if err != nil {
+		path := "platform"
+		if js.Platform == "" {
+			path = "arch"
+		}
+		l.report(path, SeverityError, "%v", err)
+	}
+	if platform != nil && platform.OS == OSWindows && hasSteps {
+		l.report("platform", SeverityError, "jobs on %s cannot have steps or build images - werft runs them in linux containers", platform)
+	}
+	if platform != nil && pod != nil {
+		for _, k := range []string{corev1.LabelOSStable, corev1.LabelArchStable} {
+			if _, ok := pod.NodeSelector[k]; ok {
+				l.report("pod.nodeSelector", SeverityWarning, "node selector %s is overridden by the platform", k)
+			}
+		}
+	}
+
+	if pod == nil {
+		if !hasSteps {
+			l.report("", SeverityError, "no pod spec present")
+			return
+		}
+		pod = &corev1.PodSpec{}
+	}
+	if len(pod.Containers) == 0 && !hasSteps {
+		l.report("pod", SeverityError, "pod has no containers")
+	}
+
+	volumes := make(map[string]struct{})
+	for i, v := range pod.Volumes {
+		path := fmt.Sprintf("pod.volumes[%d]", i)
+		if v.Name == WorkspaceVolume || v.Name == StepsVolume {
+			l.report(path, SeverityError, "volume name \"%s\" is reserved by werft", v.Name)
+		} else if _, exists := volumes[v.Name]; exists {
+			l.report(path, SeverityError, "duplicate volume name \"%s\"", v.Name)
+		}
+		volumes[v.Name] = struct{}{}
+	}
+	volumes[WorkspaceVolume] = struct{}{}
+
+	names := make(map[string]struct{})
+	checkName := func(path string, name string) {
+		switch {
+		case name == "":
+			l.report(path, SeverityError, "container has no name")
+		case name == CheckoutContainer || name == StepRunnerContainer:
+			l.report(path, SeverityError, "container name \"%s\" is reserved by werft", name)
+		default:
+			if _, exists := names[name]; exists {
+				l.report(path, SeverityError, "duplicate container name \"%s\"", name)
+			}
+			for _, msg := range validation.IsDNS1123Label(name) {
+				l.report(path+".name", SeverityError, "invalid container name \"%s\": %s", name, msg)
+			}
+		}
+		names[name] = struct{}{}
+	}
+	checkContainer := func(path string, c corev1.Container) {
+		checkName(path, c.Name)
+
+		if c.Image == "" {
+			l.report(path, SeverityError, "container \"%s\" has no image", c.Name)
+		}
+		for i, vm := range c.VolumeMounts {
+			vmpath := fmt.Sprintf("%s.volumeMounts[%d]", path, i)
+			if _, ok := volumes[vm.Name]; !ok {
+				l.report(vmpath, SeverityError, "volume mount refers to unknown volume \"%s\"", vm.Name)
+			}
+			if vm.MountPath == "" {
+				l.report(vmpath, SeverityError, "volume mount \"%s\" has no mountPath", vm.Name)
+			}
+		}
+		for i, e := range c.Env {
+			for _, msg := range validation.IsEnvVarName(e.Name) {
+				l.report(fmt.Sprintf("%s.env[%d]", path, i), SeverityError, "invalid environment variable name \"%s\": %s", e.Name, msg)
+			}
+		}
+		for i, p := range c.Ports {
+			for _, msg := range validation.IsValidPortNum(int(p.ContainerPort)) {
+				l.report(fmt.Sprintf("%s.ports[%d]", path, i), SeverityError, "invalid container port %d: %s", p.ContainerPort, msg)
+			}
+		}
+	}
+	for i, c := range pod.InitContainers {
+		checkContainer(fmt.Sprintf("pod.initContainers[%d]", i), c)
+	}
+	for i, c := range pod.Containers {
+		checkContainer(fmt.Sprintf("pod.containers[%d]", i), c)
+	}
+
+	var checkSteps func(path string, steps []*StepSpec)
+	checkSteps = func(path string, steps []*StepSpec) {
+		for i, s := range steps {
+			spath := fmt.Sprintf("%s[%d]", path, i)
+			if s == nil {
+				l.report(spath, SeverityError, "step is empty")
+				continue
+			}
+			if !s.IsGroup() {
+				checkContainer(spath, s.Container)
+				if len(s.Command) == 0 {
+					l.report(spath, SeverityError, "step \"%s\" has no command", s.Name)
+				}
+				continue
+			}
+
+			if len(s.Steps) == 0 {
+				l.report(spath, SeverityError, "step group has no steps")
+			}
+			if s.Image != "" {
+				l.report(spath, SeverityError, "step group must not have an image - only its steps run")
+			}
+			checkSteps(spath+".steps", s.Steps)
+		}
+	}
+	checkSteps("steps", js.Steps)
+
+	for i, b := range js.BuildImages {
+		path := fmt.Sprintf("buildImages[%d]", i)
+		if b == nil {
+			l.report(path, SeverityError, "image build is empty")
+			continue
+		}
+		checkName(path, b.Name)
+		if len(b.Destinations) == 0 {
+			l.report(path, SeverityError, "image build \"%s\" has no destinations", b.Name)
+		}
+		if !isWorkspacePath(b.Context) {
+			l.report(path+".context", SeverityError, "build context \"%s\" is not a path within the workspace", b.Context)
+		}
+		if !isWorkspacePath(b.DockerfilePath()) {
+			l.report(path+".dockerfile", SeverityError, "Dockerfile \"%s\" is not a path within the workspace", b.Dockerfile)
+		}
+		switch b.Builder {
+		case "", ImageBuilderKaniko, ImageBuilderBuildKit:
+		default:
+			l.report(path+".builder", SeverityError, "unknown image builder \"%s\" - must be %s or %s", b.Builder, ImageBuilderKaniko, ImageBuilderBuildKit)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package repoconfig_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestLintJobSpec(t *testing.T) {
+	tests := []struct {
+		Source      string
+		Expectation []string
+	}{
+		{
+			`pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    command: ["echo", "{{ .Repository.Repo }}"]
+    resources:
+      limits:
+        memory: 1Gi`,
+			nil,
+		},
+		{
+			`pod:
+  containers:
+  - name: build
+    imagee: alpine:latest
+    volumeMounts:
+    - name: cache
+      mountPath: /cache`,
+			[]string{
+				"3: error: container \"build\" has no image",
+				"4: error: pod.containers[0]: unknown field \"imagee\"",
+				"6: error: volume mount refers to unknown volume \"cache\"",
+			},
+		},
+		{
+			`args:
+- name: version
+  required: true
+pod:
+  containers:
+    name: build`,
+			[]string{
+				"6: error: pod.containers: expected a list",
+			},
+		},
+		{
+			`pod:
+  volumes:
+  - name: werft-workspace
+  initContainers:
+  - name: werft-checkout
+    image: alpine
+  containers:
+  - name: Build_Step
+    image: alpine
+    env:
+    - name: "1FOO"`,
+			[]string{
+				"3: error: volume name \"werft-workspace\" is reserved by werft",
+				"5: error: container name \"werft-checkout\" is reserved by werft",
+				"8: error: invalid container name \"Build_Step\": a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+				"11: error: invalid environment variable name \"1FOO\": a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')",
+			},
+		},
+		{
+			"pod:\n  containers:\n  - name: {{ .Foo }}",
+			[]string{"3: error: executing \"job\" at <.Foo>: can't evaluate field Foo in type repoconfig.TemplateObj"},
+		},
+		{
+			"pod:\n  containers:\n  - name: {{ env \"HOME\" }}",
+			[]string{"3: error: executing \"job\" at <env \"HOME\">: error calling env: env is not available in job specs on this werft installation"},
+		},
+		{
+			"pod:\n  containers: [\n",
+			[]string{"2: error: did not find expected node content"},
+		},
+		{"description: nothing to see", []string{"error: no pod spec present"}},
+		{
+			`pod:
+  containers:
+  - name: build
+    image: alpine
+onSuccess:
+  trigger:
+  - repo: 32leaves/werft-integration
+    job: .werft/integration.yaml
+  - repo: werft-integration
+    annotationz:
+      foo: bar`,
+			[]string{
+				"9: error: repo \"werft-integration\" is not in the form of owner/repo",
+				"10: error: onSuccess.trigger[1]: unknown field \"annotationz\"",
+			},
+		},
+		{
+			`pod:
+  containers:
+  - name: build
+    image: alpine
+retry:
+  infrastructure:
+    attempts: 3
+    delay: 30s
+  test:
+    attempts: -1
+    delay: soon`,
+			[]string{
+				"10: error: retry attempts must not be negative",
+				"11: error: retry delay \"soon\" is not a positive duration, e.g. 30s",
+			},
+		},
+		{
+			`steps:
+- name: build
+  image: golang
+  command: ["go", "build"]
+- parallel: true
+  steps:
+  - name: test
+    image: golang
+    command: ["go", "test"]
+  - name: lint
+    image: golangci/golangci-lint
+    command: ["golangci-lint", "run"]`,
+			nil,
+		},
+		{
+			`pod:
+  volumes:
+  - name: werft-steps
+steps:
+- name: build
+  image: golang
+- name: build
+  image: golang
+  command: ["go", "build"]
+- parallel: true
+  image: golang
+- parallel: true
+  steps:
+  - name: test
+    command: ["go", "test"]
+    paralel: true`,
+			[]string{
+				"3: error: volume name \"werft-steps\" is reserved by werft",
+				"5: error: step \"build\" has no command",
+				"7: error: duplicate container name \"build\"",
+				"10: error: step group has no steps",
+				"10: error: step group must not have an image - only its steps run",
+				"14: error: container \"test\" has no image",
+				"16: error: steps[3].steps[0]: unknown field \"paralel\"",
+			},
+		},
+		{
+			`steps:
+- name: werft-step-runner
+  image: gcr.io/distroless/base
+  command: ["/app"]`,
+			[]string{
+				"2: error: container name \"werft-step-runner\" is reserved by werft",
+			},
+		},
+		{
+			`buildImages:
+- name: image
+  context: components/server
+  destinations: ["eu.gcr.io/some-project/server:latest"]
+  buildArgs:
+    VERSION: "1.0"
+  registrySecret: gcr-push`,
+			nil,
+		},
+		{
+			`steps:
+- name: build
+  image: golang
+  command: ["go", "build"]
+buildImages:
+- name: build
+  destinations: ["eu.gcr.io/some-project/server:latest"]
+- name: image
+  context: ../elsewhere
+  dockerfile: ../../Dockerfile
+  builder: docker`,
+			[]string{
+				"6: error: duplicate container name \"build\"",
+				"8: error: image build \"image\" has no destinations",
+				"9: error: build context \"../elsewhere\" is not a path within the workspace",
+				"10: error: Dockerfile \"../../Dockerfile\" is not a path within the workspace",
+				"11: error: unknown image builder \"docker\" - must be kaniko or buildkit",
+			},
+		},
+		{
+			`platform: windows/amd64
+pod:
+  nodeSelector:
+    kubernetes.io/os: linux
+  containers:
+  - name: build
+    image: mcr.microsoft.com/windows/servercore:ltsc2019`,
+			[]string{
+				"4: warning: node selector kubernetes.io/os is overridden by the platform",
+			},
+		},
+		{
+			`platform: windows/amd64
+arch: arm64
+steps:
+- name: build
+  image: golang
+  command: ["go", "build"]`,
+			[]string{
+				"1: error: platform and arch are mutually exclusive - use platform: linux/arm64 instead",
+			},
+		},
+		{
+			`platform: windows
+steps:
+- name: build
+  image: golang
+  command: ["go", "build"]`,
+			[]string{
+				"1: error: jobs on windows cannot have steps or build images - werft runs them in linux containers",
+			},
+		},
+	}
+
+	md := &v1.JobMetadata{
+		Owner:      "foo",
+		Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "master"},
+		Trigger:    v1.JobTrigger_TRIGGER_MANUAL,
+	}
+	for idx, test := range tests {
+		problems := repoconfig.LintJobSpec([]byte(test.Source), "werft-build.1", md)
+
+		var act []string
+		for _, p := range problems {
+			act = append(act, p.String())
+		}
+		if !reflect.DeepEqual(act, test.Expectation) {
+			t.Errorf("test %d: unexpected problems:\n%s\nexpected:\n%s", idx, fmt.Sprintln(act), fmt.Sprintln(test.Expectation))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package repoconfig
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// OSLinux is the operating system of most nodes, and the one werft's own containers need
+	OSLinux = "linux"
+	// OSWindows runs on Windows nodes
+	OSWindows = "windows"
+)
+
+// knownArchs are the architectures Kubernetes nodes run on
+var knownArchs = map[string]struct{}{
+	"amd64":   {},
+	"arm64":   {},
+	"arm":     {},
+	"386":     {},
+	"ppc64le": {},
+	"s390x":   {},
+}
+
+// Platform is the operating system and architecture a job runs on
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// String returns the platform in the form of os/arch, or os if any architecture will do
+func (p Platform) String() string {
+	if p.Arch == "" {
+		return p.OS
+	}
+	return p.OS + "/" + p.Arch
+}
+
+// GetPlatform returns the platform the job runs on, or nil if it runs on any node
+func (js *JobSpec) GetPlatform() (*Platform, error) {
+	if js.Platform != "" && js.Arch != "" {
+		return nil, xerrors.Errorf("platform and arch are mutually exclusive - use platform: linux/%s instead", js.Arch)
+	}
+
+	var res Platform
+	switch {
+	case js.Platform != "":
+		segs := strings.Split(js.Platform, "/")
+		if len(segs) > 2 {
+			return nil, xerrors.Errorf("platform \"%s\" is not in the form of os/arch, e.g. windows/amd64", js.Platform)
+		}
+		res.OS = segs[0]
+		if len(segs) == 2 {
+			res.Arch = segs[1]
+		}
+	case js.Arch != "":
+		res = Platform{OS: OSLinux, Arch: js.Arch}
+	default:
+		return nil, nil
+	}
+
+	if res.OS != OSLinux && res.OS != OSWindows {
+		return nil, xerrors.Errorf("unknown operating system \"%s\" - must be %s or %s", res.OS, OSLinux, OSWindows)
+	}
+	if _, ok := knownArchs[res.Arch]; res.Arch != "" && !ok {
+		return nil, xerrors.Errorf("unknown architecture \"%s\"", res.Arch)
+	}
+	return &res, nil
+}
@@ -0,0 +1,42 @@
+package repoconfig_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+)
+
+func TestGetPlatform(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Spec        repoconfig.JobSpec
+		Expectation *repoconfig.Platform
+		Error       string
+	}{
+		{"any", repoconfig.JobSpec{}, nil, ""},
+		{"os and arch", repoconfig.JobSpec{Platform: "windows/amd64"}, &repoconfig.Platform{OS: "windows", Arch: "amd64"}, ""},
+		{"os only", repoconfig.JobSpec{Platform: "windows"}, &repoconfig.Platform{OS: "windows"}, ""},
+		{"arch only", repoconfig.JobSpec{Arch: "arm64"}, &repoconfig.Platform{OS: "linux", Arch: "arm64"}, ""},
+		{"both", repoconfig.JobSpec{Platform: "linux/amd64", Arch: "arm64"}, nil, "platform and arch are mutually exclusive - use platform: linux/arm64 instead"},
+		{"unknown os", repoconfig.JobSpec{Platform: "darwin/amd64"}, nil, "unknown operating system \"darwin\" - must be linux or windows"},
+		{"unknown arch", repoconfig.JobSpec{Arch: "mips"}, nil, "unknown architecture \"mips\""},
+		{"variant", repoconfig.JobSpec{Platform: "linux/arm/v7"}, nil, "platform \"linux/arm/v7\" is not in the form of os/arch, e.g. windows/amd64"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := test.Spec.GetPlatform()
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package repoconfig
+
+import (
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// Promotion releases what a successful job built without building it again, e.g. by re-tagging its images, copying
+// its files to a release bucket or deploying them. Promoting a job starts the promotion job on the same revision.
+// The promotion job learns about the promoted job from the promotion, promotedJob and promotedImages annotations.
+type Promotion struct {
+	// Name identifies the promotion, e.g. production
+	Name string `yaml:"name"`
+	// Job is the path of the promotion job, e.g. .werft/promote.yaml
+	Job string `yaml:"job"`
+	// From restricts the promotion to jobs of these job specs, e.g. build for .werft/build.yaml. Without it the jobs
+	// of any job spec can be promoted.
+	From []string `yaml:"from,omitempty"`
+}
+
+// UnmarshalYAML validates a promotion
+func (p *Promotion) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawPromotion Promotion
+	var raw rawPromotion
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+	if raw.Name == "" {
+		return xerrors.Errorf("promotions need a name")
+	}
+	if raw.Job == "" {
+		return xerrors.Errorf("promotion %s needs a job", raw.Name)
+	}
+
+	*p = Promotion(raw)
+	return nil
+}
+
+// Promotion returns the promotion with the name, or nil if there is none
+func (rc *C) Promotion(name string) *Promotion {
+	for _, p := range rc.Promotions {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Accepts returns true if a job can be promoted using this promotion. Only successful jobs can be promoted.
+func (p *Promotion) Accepts(job *werftv1.JobStatus) bool {
+	if job.Phase != werftv1.JobPhase_PHASE_DONE || job.Conditions == nil || !job.Conditions.Success {
+		return false
+	}
+	if len(p.From) == 0 {
+		return true
+	}
+	for _, f := range p.From {
+		if job.Metadata != nil && f == job.Metadata.JobSpec {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package repoconfig_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalPromotion(t *testing.T) {
+	tests := []struct {
+		Source string
+		Error  bool
+	}{
+		{"name: production\njob: .werft/promote.yaml", false},
+		{"name: production\njob: .werft/promote.yaml\nfrom: [build]", false},
+		{"job: .werft/promote.yaml", true},
+		{"name: production", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Source, func(t *testing.T) {
+			var p repoconfig.Promotion
+			err := yaml.Unmarshal([]byte(test.Source), &p)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestPromotionAccepts(t *testing.T) {
+	job := func(phase v1.JobPhase, success bool, spec string) *v1.JobStatus {
+		return &v1.JobStatus{
+			Phase:      phase,
+			Conditions: &v1.JobConditions{Success: success},
+			Metadata:   &v1.JobMetadata{JobSpec: spec},
+		}
+	}
+
+	tests := []struct {
+		Name        string
+		From        []string
+		Job         *v1.JobStatus
+		Expectation bool
+	}{
+		{"successful job", nil, job(v1.JobPhase_PHASE_DONE, true, "build"), true},
+		{"failed job", nil, job(v1.JobPhase_PHASE_DONE, false, "build"), false},
+		{"running job", nil, job(v1.JobPhase_PHASE_RUNNING, true, "build"), false},
+		{"listed job spec", []string{"build", "release"}, job(v1.JobPhase_PHASE_DONE, true, "release"), true},
+		{"other job spec", []string{"build"}, job(v1.JobPhase_PHASE_DONE, true, "test"), false},
+		{"custom job spec", []string{"build"}, job(v1.JobPhase_PHASE_DONE, true, ""), false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			p := &repoconfig.Promotion{Name: "production", Job: ".werft/promote.yaml", From: test.From}
+			if act := p.Accepts(test.Job); act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package repoconfig
 
 import (
+	"strings"
+	"time"
+
 	werftv1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/filterexpr"
+	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -10,6 +14,369 @@ import (
 type C struct {
 	DefaultJob string          `yaml:"defaultJob"`
 	Rules      []*JobStartRule `yaml:"rules"`
+
+	// Channels routes job results to where they're needed. Results declare the channels they're meant for
+	// (e.g. werft log result -c preview), and this map determines what happens with results in each channel.
+	Channels map[string]*ResultChannel `yaml:"channels,omitempty"`
+
+	// Preview configures the lifecycle of preview environments
+	Preview *PreviewConfig `yaml:"preview,omitempty"`
+
+	// Notifications send messages about the jobs of this repository to chat tools
+	Notifications []*Notification `yaml:"notifications,omitempty"`
+
+	// BranchProtection keeps the required status checks of protected branches in sync with the statuses werft reports
+	BranchProtection *BranchProtection `yaml:"branchProtection,omitempty"`
+
+	// Labels start jobs on pull requests when someone adds a label to them, e.g. needs-benchmark
+	Labels []*LabelTrigger `yaml:"labels,omitempty"`
+
+	// Annotations restricts the annotations jobs accept. Without it jobs accept any annotation.
+	Annotations *AnnotationPolicy `yaml:"annotations,omitempty"`
+
+	// Status configures the GitHub statuses of pushes which start several jobs, e.g. in monorepos
+	Status *StatusConfig `yaml:"status,omitempty"`
+
+	// Promotions release what successful jobs built without building it again, e.g. to production
+	Promotions []*Promotion `yaml:"promotions,omitempty"`
+
+	// Upstreams lists the repositories whose jobs may trigger jobs of this repository from their onSuccess section,
+	// in the form of owner/repo or owner/*. Without it only jobs of this repository may trigger its jobs.
+	Upstreams []string `yaml:"upstreams,omitempty"`
+}
+
+// AcceptsUpstream returns true if jobs of the repository owner/repo may trigger jobs of this repository
+func (rc *C) AcceptsUpstream(owner, repo string) bool {
+	for _, u := range rc.Upstreams {
+		segs := strings.Split(u, "/")
+		if len(segs) == 2 && segs[0] == owner && (segs[1] == "*" || segs[1] == repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusConfig configures the GitHub statuses werft reports on a commit. Without it all jobs of a commit report to
+// the werft context, which then shows the status of whichever job changed last.
+type StatusConfig struct {
+	// Aggregate makes the werft context report all jobs of a commit: it is pending while any of them runs, fails
+	// once one of them failed and succeeds only when all of them succeeded. Retries replace the job they retry.
+	Aggregate bool `yaml:"aggregate,omitempty"`
+	// PerJob additionally reports each job in a context of its own, named after its job spec, e.g. continunous-integration/werft/build
+	PerJob bool `yaml:"perJob,omitempty"`
+}
+
+// Permissions on a GitHub repository, from least to most privileged
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+	PermissionAdmin = "admin"
+)
+
+var permissionRank = map[string]int{
+	"none":          0,
+	PermissionRead:  1,
+	"triage":        1,
+	PermissionWrite: 2,
+	"maintain":      2,
+	PermissionAdmin: 3,
+}
+
+// LabelTrigger starts a job on the head of a pull request when a label is added to it
+type LabelTrigger struct {
+	// Label is the name of the label, e.g. needs-benchmark
+	Label string `yaml:"label"`
+	// Job is the path of the job to start, e.g. .werft/benchmark.yaml
+	Job string `yaml:"job"`
+	// Permission is the permission on the repository the one who adds the label needs: read, write or admin.
+	// Defaults to write.
+	Permission string `yaml:"permission,omitempty"`
+}
+
+// UnmarshalYAML validates a label trigger
+func (l *LabelTrigger) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawLabelTrigger LabelTrigger
+	var raw rawLabelTrigger
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+	if raw.Label == "" {
+		return xerrors.Errorf("label triggers need a label")
+	}
+	if raw.Job == "" {
+		return xerrors.Errorf("label trigger %s needs a job", raw.Label)
+	}
+	switch raw.Permission {
+	case "", PermissionRead, PermissionWrite, PermissionAdmin:
+	default:
+		return xerrors.Errorf("label trigger %s: unknown permission \"%s\" - must be %s, %s or %s", raw.Label, raw.Permission, PermissionRead, PermissionWrite, PermissionAdmin)
+	}
+
+	*l = LabelTrigger(raw)
+	return nil
+}
+
+// Allows returns true if someone with a permission on the repository, as reported by GitHub, may trigger the job
+func (l *LabelTrigger) Allows(permission string) bool {
+	required := l.Permission
+	if required == "" {
+		required = PermissionWrite
+	}
+	has, ok := permissionRank[permission]
+	return ok && has >= permissionRank[required]
+}
+
+// LabelTriggers returns the triggers of a label
+func (rc *C) LabelTriggers(label string) []*LabelTrigger {
+	var res []*LabelTrigger
+	for _, l := range rc.Labels {
+		if l.Label == label {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// BranchProtection makes the status contexts of werft required checks of protected branches. werft updates the branches
+// whenever a push to the default branch changes the werft config. This requires a GitHub app with administration permission.
+type BranchProtection struct {
+	// Branches are the protected branches whose required status checks werft maintains, e.g. master
+	Branches []string `yaml:"branches"`
+	// Contexts are further status contexts to require, e.g. those of GitHub result routes. The werft context is always required.
+	Contexts []string `yaml:"contexts,omitempty"`
+}
+
+// RequiredContexts merges the contexts werft reports into the status contexts a branch currently requires. Contexts starting
+// with the werft context which are no longer configured are removed, s.t. they cannot block merges forever. Contexts of other
+// CI systems remain required. The second return value is false if nothing changed.
+func (bp *BranchProtection) RequiredContexts(current []string, werftContext string) ([]string, bool) {
+	want := append([]string{werftContext}, bp.Contexts...)
+	wanted := make(map[string]bool, len(want))
+	for _, c := range want {
+		wanted[c] = true
+	}
+
+	var (
+		res     []string
+		present = make(map[string]bool, len(current))
+		changed bool
+	)
+	for _, c := range current {
+		if present[c] {
+			continue
+		}
+		present[c] = true
+		if !wanted[c] && (c == werftContext || strings.HasPrefix(c, werftContext+"/")) {
+			changed = true
+			continue
+		}
+		res = append(res, c)
+	}
+	for _, c := range want {
+		if present[c] {
+			continue
+		}
+		present[c] = true
+		res = append(res, c)
+		changed = true
+	}
+	return res, changed
+}
+
+// PreviewConfig configures the lifecycle of preview environments. Jobs register preview environments
+// using a result of type "preview" (e.g. werft log result preview my-namespace).
+type PreviewConfig struct {
+	// Teardown is the path to the job which removes a preview environment once the pull request of its branch
+	// is closed or merged. The job runs once per environment, which it finds in the previewEnvironment annotation.
+	Teardown string `yaml:"teardown,omitempty"`
+}
+
+// ResultChannel configures what happens to results in a channel. A channel can route to several handlers at once.
+type ResultChannel struct {
+	GitHub   *GitHubResultRoute   `yaml:"github,omitempty"`
+	Slack    *SlackResultRoute    `yaml:"slack,omitempty"`
+	Webhook  *WebhookResultRoute  `yaml:"webhook,omitempty"`
+	UI       *UIResultRoute       `yaml:"ui,omitempty"`
+	Teams    *TeamsResultRoute    `yaml:"teams,omitempty"`
+	Chat     *ChatResultRoute     `yaml:"chat,omitempty"`
+	Telegram *TelegramResultRoute `yaml:"telegram,omitempty"`
+	Matrix   *MatrixResultRoute   `yaml:"matrix,omitempty"`
+}
+
+// GitHubResultRoute publishes results as commit status
+type GitHubResultRoute struct {
+	// Context is the GitHub status context of the result. Defaults to the werft result context.
+	Context string `yaml:"context,omitempty"`
+}
+
+// SlackResultRoute posts results to Slack
+type SlackResultRoute struct {
+	// URL is the Slack incoming webhook URL
+	URL string `yaml:"url"`
+	// Channel overrides the channel configured for the incoming webhook
+	Channel string `yaml:"channel,omitempty"`
+}
+
+// WebhookResultRoute posts results as JSON to a URL
+type WebhookResultRoute struct {
+	URL string `yaml:"url"`
+}
+
+// UIResultRoute shows results in a section of the job's result view
+type UIResultRoute struct {
+	Section string `yaml:"section"`
+}
+
+// TeamsResultRoute posts results to Microsoft Teams
+type TeamsResultRoute struct {
+	// URL is the Teams incoming webhook URL
+	URL string `yaml:"url"`
+}
+
+// ChatResultRoute posts results to chat tools which accept JSON messages on an incoming webhook, e.g. Mattermost or Google Chat
+type ChatResultRoute struct {
+	URL string `yaml:"url"`
+	// Template is a Go template which produces the JSON body of a message. The message's text is available as .Text,
+	// the job as .Job and its URL as .URL. The json function encodes values as JSON. Defaults to {"text": {{ json .Text }}}.
+	Template string `yaml:"template,omitempty"`
+}
+
+// TelegramResultRoute posts results to a Telegram chat using a bot
+type TelegramResultRoute struct {
+	// BotToken is the token of the bot which posts the messages, as issued by the BotFather
+	BotToken string `yaml:"botToken"`
+	// ChatID is the ID of the chat to post to, or @channelusername for public channels
+	ChatID string `yaml:"chatID"`
+}
+
+// MatrixResultRoute posts results to a Matrix room
+type MatrixResultRoute struct {
+	// Homeserver is the base URL of the Matrix homeserver, e.g. https://matrix.org
+	Homeserver string `yaml:"homeserver"`
+	// RoomID is the ID of the room to post to, e.g. !abcdef:matrix.org. The user must have joined the room.
+	RoomID string `yaml:"roomID"`
+	// AccessToken authenticates the user who posts the messages
+	AccessToken string `yaml:"accessToken"`
+}
+
+// Events jobs notify about
+const (
+	NotifyStarted   = "started"
+	NotifySucceeded = "succeeded"
+	NotifyFailed    = "failed"
+	// NotifyFixed is a job which succeeded after its previous run on the same ref failed
+	NotifyFixed = "fixed"
+	// NotifyBroken is a job which failed after its previous run on the same ref succeeded
+	NotifyBroken = "broken"
+	// NotifyResult is a job which produced a result
+	NotifyResult = "result"
+)
+
+var notifyEvents = []string{NotifyStarted, NotifySucceeded, NotifyFailed, NotifyFixed, NotifyBroken, NotifyResult}
+
+// Notification is a rule which sends messages about some events of some jobs to chat tools
+type Notification struct {
+	// Events are the events to notify about, e.g. failed or fixed
+	Events []string
+	// Expr restricts the notification to jobs matching the filter, e.g. on some branches or triggers
+	Expr []*werftv1.FilterExpression
+	// ResultTypes restricts result notifications to results of these types. Without types, all results notify.
+	ResultTypes []string
+	// Muted disables the notification without removing it
+	Muted bool
+
+	Slack    *SlackResultRoute
+	Teams    *TeamsResultRoute
+	Chat     *ChatResultRoute
+	Telegram *TelegramResultRoute
+	Matrix   *MatrixResultRoute
+}
+
+// UnmarshalYAML unmarshals the filter expressions and validates the events of a notification
+func (n *Notification) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawNotification struct {
+		Events      []string             `yaml:"events"`
+		Expr        []JobStartRuleOr     `yaml:"matchesAll"`
+		ResultTypes []string             `yaml:"resultTypes"`
+		Muted       bool                 `yaml:"muted"`
+		Slack       *SlackResultRoute    `yaml:"slack"`
+		Teams       *TeamsResultRoute    `yaml:"teams"`
+		Chat        *ChatResultRoute     `yaml:"chat"`
+		Telegram    *TelegramResultRoute `yaml:"telegram"`
+		Matrix      *MatrixResultRoute   `yaml:"matrix"`
+	}
+	err := unmarshal(&rawNotification)
+	if err != nil {
+		return err
+	}
+	for _, e := range rawNotification.Events {
+		var known bool
+		for _, ne := range notifyEvents {
+			if e == ne {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return xerrors.Errorf("unknown notification event \"%s\" - must be one of %s", e, strings.Join(notifyEvents, ", "))
+		}
+	}
+
+	*n = Notification{
+		Events:      rawNotification.Events,
+		ResultTypes: rawNotification.ResultTypes,
+		Muted:       rawNotification.Muted,
+		Slack:       rawNotification.Slack,
+		Teams:       rawNotification.Teams,
+		Chat:        rawNotification.Chat,
+		Telegram:    rawNotification.Telegram,
+		Matrix:      rawNotification.Matrix,
+	}
+	for _, expr := range rawNotification.Expr {
+		terms, err := filterexpr.Parse(expr.Or)
+		if err != nil {
+			return err
+		}
+		n.Expr = append(n.Expr, &werftv1.FilterExpression{Terms: terms})
+	}
+	return nil
+}
+
+// Matches returns true if the notification is about an event of a job. Result events also pass the result.
+func (n *Notification) Matches(job *werftv1.JobStatus, event string, res *werftv1.JobResult) bool {
+	if n.Muted {
+		return false
+	}
+
+	var notifies bool
+	for _, e := range n.Events {
+		if e == event {
+			notifies = true
+			break
+		}
+	}
+	if !notifies {
+		return false
+	}
+
+	if event == NotifyResult && len(n.ResultTypes) > 0 {
+		if res == nil {
+			return false
+		}
+		var ok bool
+		for _, t := range n.ResultTypes {
+			if t == res.Type {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return filterexpr.MatchesFilter(job, n.Expr)
 }
 
 // JobStartRule determines if a job will be started
@@ -72,6 +439,21 @@ type JobSpec struct {
 	// as a Go template.
 	Pod *corev1.PodSpec `yaml:"pod"`
 
+	// Steps run one after the other in the pod, each in a container of its own whose output is placed in a log slice
+	// named after the step. werft adds them to the containers of the pod, hence jobs with steps need no pod spec.
+	Steps []*StepSpec `yaml:"steps,omitempty" json:"steps,omitempty"`
+
+	// BuildImages are the container images this job builds and pushes once all of its steps succeeded.
+	// werft runs each build as a step, hence jobs which build images need no pod spec either.
+	BuildImages []*ImageBuild `yaml:"buildImages,omitempty" json:"buildImages,omitempty"`
+
+	// Platform is the operating system and architecture the job runs on, e.g. windows/amd64 or linux/arm64.
+	// werft schedules the job on a node of that platform. Without platform or arch the job runs on any node.
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+
+	// Arch is the architecture the job runs on, e.g. arm64. It is short for platform linux/<arch>.
+	Arch string `yaml:"arch,omitempty" json:"arch,omitempty"`
+
 	// Mutex makes job execution exclusive, with new ones canceling the currently running one.
 	// For example: job A is running at the moment, and job B is about to start. If A and B share the
 	// same mutex, B will cancel A.
@@ -83,6 +465,72 @@ type JobSpec struct {
 	// (i.e. jobs can run even when annotations listed here are not present). What matters for a job to
 	// run is only if Kubernetes accepts the produced podspec.
 	Args []ArgSpec `yaml:"args,omitempty"`
+
+	// OnSuccess configures what happens once the job succeeded, e.g. starting dependent jobs in other repositories.
+	OnSuccess *JobHooks `yaml:"onSuccess,omitempty" json:"onSuccess,omitempty"`
+
+	// Retry configures if and when a failed job is started again. Without retry policy failed jobs are not retried.
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
+// RetryPolicy decides how often and when failed jobs are retried, depending on why they failed.
+// Retries are new jobs which run on the same revision and job spec as the failed one.
+type RetryPolicy struct {
+	// Infrastructure applies to jobs which failed for reasons outside of their control, e.g. a lost node or an image pull backoff
+	Infrastructure *RetryRule `yaml:"infrastructure,omitempty" json:"infrastructure,omitempty"`
+	// Test applies to jobs which failed themselves, e.g. because their tests failed
+	Test *RetryRule `yaml:"test,omitempty" json:"test,omitempty"`
+}
+
+// RetryRule configures the retries of a class of failures
+type RetryRule struct {
+	// Attempts is the maximum number of times a job is retried
+	Attempts int `yaml:"attempts" json:"attempts"`
+	// Delay is how long we wait before retrying, e.g. 30s. Defaults to retrying right away.
+	Delay string `yaml:"delay,omitempty" json:"delay,omitempty"`
+}
+
+// Rule returns the retry rule for a class of failures, or nil if such failures are not retried
+func (p *RetryPolicy) Rule(class werftv1.JobFailureClass) *RetryRule {
+	if p == nil {
+		return nil
+	}
+	switch class {
+	case werftv1.JobFailureClass_FAILURE_INFRASTRUCTURE:
+		return p.Infrastructure
+	case werftv1.JobFailureClass_FAILURE_TEST:
+		return p.Test
+	default:
+		return nil
+	}
+}
+
+// DelayDuration parses the delay of a retry rule
+func (r *RetryRule) DelayDuration() (time.Duration, error) {
+	if r.Delay == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(r.Delay)
+}
+
+// JobHooks are actions taken when a job finishes
+type JobHooks struct {
+	// Trigger lists jobs to start
+	Trigger []DownstreamJob `yaml:"trigger,omitempty" json:"trigger,omitempty"`
+}
+
+// DownstreamJob is a job started by another one. Downstream jobs inherit the owner of the job that triggered them
+// and learn about it from the upstreamJob, upstreamRepo and upstreamRevision annotations. Repositories other than the
+// upstream one must list it in their upstreams.
+type DownstreamJob struct {
+	// Repo is the GitHub repository to start the job in, in the form of owner/repo
+	Repo string `yaml:"repo" json:"repo"`
+	// Ref is the branch or tag to start the job on. Defaults to the default branch of the repository.
+	Ref string `yaml:"ref,omitempty" json:"ref,omitempty"`
+	// Job is the path to the job in the repository. Defaults to the job the repository's werft config selects.
+	Job string `yaml:"job,omitempty" json:"job,omitempty"`
+	// Annotations are added to the downstream job
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 // ArgSpec specifies an argument/annotation for a job.
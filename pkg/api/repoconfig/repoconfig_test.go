@@ -2,7 +2,10 @@ package repoconfig_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
@@ -14,7 +17,7 @@ func TestUnmarshalC(t *testing.T) {
 		Source      string
 		Expectation string
 	}{
-		{`defaultJob: "foo.yaml"`, `{"DefaultJob":"foo.yaml","Rules":null}`},
+		{`defaultJob: "foo.yaml"`, `{"DefaultJob":"foo.yaml","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`},
 		{
 			`rules:
 - path: ""
@@ -23,7 +26,7 @@ func TestUnmarshalC(t *testing.T) {
 - path: ""
   matchesAll:
   - or: ["repo.ref !~= refs/branches/"]`,
-			`{"DefaultJob":"","Rules":[{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/tags/","operation":3}]}]},{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3,"negate":true}]}]}]}`,
+			`{"DefaultJob":"","Rules":[{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/tags/","operation":3}]}]},{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3,"negate":true}]}]}],"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
 		},
 		{
 			`rules:
@@ -33,7 +36,95 @@ func TestUnmarshalC(t *testing.T) {
     - "repo.ref ~= refs/branches/"
   - or:
     - "name !~= 0"
-`, `{"DefaultJob":"","Rules":[{"Path":"foo.yaml","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3}]},{"terms":[{"field":"name","value":"0","operation":3,"negate":true}]}]}]}`,
+`, `{"DefaultJob":"","Rules":[{"Path":"foo.yaml","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3}]},{"terms":[{"field":"name","value":"0","operation":3,"negate":true}]}]}],"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`channels:
+  preview:
+    github:
+      context: preview
+    ui:
+      section: Preview
+  release:
+    slack:
+      url: https://hooks.slack.com/services/foo
+      channel: "#releases"
+    webhook:
+      url: https://example.com/hook
+`, `{"DefaultJob":"","Rules":null,"Channels":{"preview":{"GitHub":{"Context":"preview"},"Slack":null,"Webhook":null,"UI":{"Section":"Preview"},"Teams":null,"Chat":null,"Telegram":null,"Matrix":null},"release":{"GitHub":null,"Slack":{"URL":"https://hooks.slack.com/services/foo","Channel":"#releases"},"Webhook":{"URL":"https://example.com/hook"},"UI":null,"Teams":null,"Chat":null,"Telegram":null,"Matrix":null}},"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`notifications:
+- events: [failed, fixed]
+  matchesAll:
+  - or: ["repo.ref==refs/heads/master"]
+  teams:
+    url: https://example.webhook.office.com/webhookb2/foo
+- events: [started, succeeded]
+  chat:
+    url: https://chat.example.com/hooks/foo
+    template: '{"text": {{ json .Text }}, "username": "werft"}'
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":[{"Events":["failed","fixed"],"Expr":[{"terms":[{"field":"repo.ref","value":"refs/heads/master"}]}],"ResultTypes":null,"Muted":false,"Slack":null,"Teams":{"URL":"https://example.webhook.office.com/webhookb2/foo"},"Chat":null,"Telegram":null,"Matrix":null},{"Events":["started","succeeded"],"Expr":null,"ResultTypes":null,"Muted":false,"Slack":null,"Teams":null,"Chat":{"URL":"https://chat.example.com/hooks/foo","Template":"{\"text\": {{ json .Text }}, \"username\": \"werft\"}"},"Telegram":null,"Matrix":null}],"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`channels:
+  release:
+    telegram:
+      botToken: "123456:abcdef"
+      chatID: "-1001234567890"
+    matrix:
+      homeserver: https://matrix.example.com
+      roomID: "!abcdef:example.com"
+      accessToken: secret
+`, `{"DefaultJob":"","Rules":null,"Channels":{"release":{"GitHub":null,"Slack":null,"Webhook":null,"UI":null,"Teams":null,"Chat":null,"Telegram":{"BotToken":"123456:abcdef","ChatID":"-1001234567890"},"Matrix":{"Homeserver":"https://matrix.example.com","RoomID":"!abcdef:example.com","AccessToken":"secret"}}},"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`preview:
+  teardown: .werft/preview-teardown.yaml
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":{"Teardown":".werft/preview-teardown.yaml"},"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`branchProtection:
+  branches: [master]
+  contexts: [preview]
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":{"Branches":["master"],"Contexts":["preview"]},"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`labels:
+- label: needs-benchmark
+  job: .werft/benchmark.yaml
+- label: deploy
+  job: .werft/deploy.yaml
+  permission: admin
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":[{"Label":"needs-benchmark","Job":".werft/benchmark.yaml","Permission":""},{"Label":"deploy","Job":".werft/deploy.yaml","Permission":"admin"}],"Annotations":null,"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`annotations:
+  unknown: strip
+  allowed:
+  - name: version
+    pattern: v[0-9]+
+  - name: deploy
+    type: bool
+    default: "false"
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":{"Allowed":[{"Name":"version","Type":"","Pattern":"v[0-9]+","Default":null},{"Name":"deploy","Type":"bool","Pattern":"","Default":"false"}],"Unknown":"strip"},"Status":null,"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`status:
+  aggregate: true
+  perJob: true
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":{"Aggregate":true,"PerJob":true},"Promotions":null,"Upstreams":null}`,
+		},
+		{
+			`promotions:
+- name: production
+  job: .werft/promote.yaml
+  from: [build]
+`, `{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":[{"Name":"production","Job":".werft/promote.yaml","From":["build"]}],"Upstreams":null}`,
+		},
+		{
+			`upstreams: ["32leaves/werft", "32leaves/*"]`,
+			`{"DefaultJob":"","Rules":null,"Channels":null,"Preview":null,"Notifications":null,"BranchProtection":null,"Labels":null,"Annotations":null,"Status":null,"Promotions":null,"Upstreams":["32leaves/werft","32leaves/*"]}`,
 		},
 	}
 
@@ -110,3 +201,227 @@ func TestTemplatePath(t *testing.T) {
 		}
 	}
 }
+
+func TestNotificationMatches(t *testing.T) {
+	master := &v1.JobStatus{Metadata: &v1.JobMetadata{Repository: &v1.Repository{Ref: "refs/heads/master"}}}
+	branch := &v1.JobStatus{Metadata: &v1.JobMetadata{Repository: &v1.Repository{Ref: "refs/heads/foo"}}}
+
+	tests := []struct {
+		Name         string
+		Notification string
+		Job          *v1.JobStatus
+		Event        string
+		Result       *v1.JobResult
+		Expectation  bool
+	}{
+		{"event matches", `events: [failed]`, branch, repoconfig.NotifyFailed, nil, true},
+		{"event does not match", `events: [failed]`, branch, repoconfig.NotifySucceeded, nil, false},
+		{"filter matches", "events: [broken]\nmatchesAll:\n- or: [\"repo.ref==refs/heads/master\"]", master, repoconfig.NotifyBroken, nil, true},
+		{"filter does not match", "events: [broken]\nmatchesAll:\n- or: [\"repo.ref==refs/heads/master\"]", branch, repoconfig.NotifyBroken, nil, false},
+		{"muted", "events: [failed]\nmuted: true", branch, repoconfig.NotifyFailed, nil, false},
+		{"any result", `events: [result]`, branch, repoconfig.NotifyResult, &v1.JobResult{Type: "url"}, true},
+		{"result type matches", "events: [result]\nresultTypes: [docker]", branch, repoconfig.NotifyResult, &v1.JobResult{Type: "docker"}, true},
+		{"result type does not match", "events: [result]\nresultTypes: [docker]", branch, repoconfig.NotifyResult, &v1.JobResult{Type: "url"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var n repoconfig.Notification
+			err := yaml.Unmarshal([]byte(test.Notification), &n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act := n.Matches(test.Job, test.Event, test.Result)
+			if act != test.Expectation {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestBranchProtectionRequiredContexts(t *testing.T) {
+	const werftContext = "continunous-integration/werft"
+	tests := []struct {
+		Name        string
+		Contexts    []string
+		Current     []string
+		Expectation []string
+		Changed     bool
+	}{
+		{"adds werft context", nil, []string{"other-ci"}, []string{"other-ci", werftContext}, true},
+		{"unchanged", []string{"preview"}, []string{werftContext, "preview"}, []string{werftContext, "preview"}, false},
+		{"adds configured contexts", []string{"preview"}, nil, []string{werftContext, "preview"}, true},
+		{"removes stale werft contexts", nil, []string{werftContext, werftContext + "/result-000", "other-ci"}, []string{werftContext, "other-ci"}, true},
+		{"keeps configured werft contexts", []string{werftContext + "/result-000"}, []string{werftContext + "/result-000"}, []string{werftContext + "/result-000", werftContext}, true},
+		{"keeps contexts sharing a prefix", nil, []string{werftContext + "-legacy", werftContext}, []string{werftContext + "-legacy", werftContext}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			bp := &repoconfig.BranchProtection{Branches: []string{"master"}, Contexts: test.Contexts}
+			act, changed := bp.RequiredContexts(test.Current, werftContext)
+			if fmt.Sprint(act) != fmt.Sprint(test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+			if changed != test.Changed {
+				t.Errorf("expected changed to be %v, got %v", test.Changed, changed)
+			}
+		})
+	}
+}
+
+func TestUnmarshalLabelTrigger(t *testing.T) {
+	tests := []struct {
+		Source string
+		Error  bool
+	}{
+		{"label: needs-benchmark\njob: .werft/benchmark.yaml", false},
+		{"label: needs-benchmark\njob: .werft/benchmark.yaml\npermission: read", false},
+		{"job: .werft/benchmark.yaml", true},
+		{"label: needs-benchmark", true},
+		{"label: needs-benchmark\njob: .werft/benchmark.yaml\npermission: owner", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Source, func(t *testing.T) {
+			var l repoconfig.LabelTrigger
+			err := yaml.Unmarshal([]byte(test.Source), &l)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestLabelTriggerAllows(t *testing.T) {
+	tests := []struct {
+		Required    string
+		Permission  string
+		Expectation bool
+	}{
+		{"", "write", true},
+		{"", "maintain", true},
+		{"", "read", false},
+		{"", "triage", false},
+		{"read", "read", true},
+		{"read", "none", false},
+		{"admin", "write", false},
+		{"admin", "admin", true},
+		{"", "unknown", false},
+	}
+	for _, test := range tests {
+		t.Run(test.Required+"/"+test.Permission, func(t *testing.T) {
+			l := &repoconfig.LabelTrigger{Label: "foo", Job: "foo.yaml", Permission: test.Required}
+			if act := l.Allows(test.Permission); act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestLabelTriggers(t *testing.T) {
+	c := &repoconfig.C{Labels: []*repoconfig.LabelTrigger{
+		{Label: "needs-benchmark", Job: ".werft/benchmark.yaml"},
+		{Label: "deploy", Job: ".werft/deploy.yaml", Permission: "admin"},
+		{Label: "needs-benchmark", Job: ".werft/benchmark-arm.yaml"},
+	}}
+	tests := []struct {
+		Label       string
+		Expectation []string
+	}{
+		{"needs-benchmark", []string{".werft/benchmark.yaml", ".werft/benchmark-arm.yaml"}},
+		{"deploy", []string{".werft/deploy.yaml"}},
+		{"Deploy", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.Label, func(t *testing.T) {
+			var act []string
+			for _, l := range c.LabelTriggers(test.Label) {
+				act = append(act, l.Job)
+			}
+			if strings.Join(act, ",") != strings.Join(test.Expectation, ",") {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRule(t *testing.T) {
+	tests := []struct {
+		Source   string
+		Class    v1.JobFailureClass
+		Attempts int
+		Delay    time.Duration
+		NoRule   bool
+		Error    bool
+	}{
+		{Source: "infrastructure:\n  attempts: 2\n  delay: 30s", Class: v1.JobFailureClass_FAILURE_INFRASTRUCTURE, Attempts: 2, Delay: 30 * time.Second},
+		{Source: "infrastructure:\n  attempts: 2", Class: v1.JobFailureClass_FAILURE_INFRASTRUCTURE, Attempts: 2},
+		{Source: "test:\n  attempts: 1", Class: v1.JobFailureClass_FAILURE_TEST, Attempts: 1},
+		{Source: "test:\n  attempts: 1", Class: v1.JobFailureClass_FAILURE_INFRASTRUCTURE, NoRule: true},
+		{Source: "infrastructure:\n  attempts: 1\ntest:\n  attempts: 1", Class: v1.JobFailureClass_FAILURE_CHECKOUT, NoRule: true},
+		{Source: "infrastructure:\n  attempts: 1\ntest:\n  attempts: 1", Class: v1.JobFailureClass_FAILURE_UNCLASSIFIED, NoRule: true},
+		{Source: "infrastructure:\n  attempts: 1\n  delay: soon", Class: v1.JobFailureClass_FAILURE_INFRASTRUCTURE, Attempts: 1, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%s", test.Source, test.Class), func(t *testing.T) {
+			var p repoconfig.RetryPolicy
+			err := yaml.Unmarshal([]byte(test.Source), &p)
+			if err != nil {
+				t.Fatalf("cannot unmarshal retry policy: %v", err)
+			}
+
+			rule := p.Rule(test.Class)
+			if rule == nil {
+				if !test.NoRule {
+					t.Errorf("expected a rule for %s", test.Class)
+				}
+				return
+			}
+			if test.NoRule {
+				t.Fatalf("expected no rule for %s, got %+v", test.Class, rule)
+			}
+			if rule.Attempts != test.Attempts {
+				t.Errorf("expected %d attempts, got %d", test.Attempts, rule.Attempts)
+			}
+			delay, err := rule.DelayDuration()
+			if (err != nil) != test.Error {
+				t.Fatalf("expected error: %v, got %v", test.Error, err)
+			}
+			if delay != test.Delay {
+				t.Errorf("expected delay %v, got %v", test.Delay, delay)
+			}
+		})
+	}
+
+	var p *repoconfig.RetryPolicy
+	if rule := p.Rule(v1.JobFailureClass_FAILURE_INFRASTRUCTURE); rule != nil {
+		t.Errorf("jobs without retry policy must not be retried, got %+v", rule)
+	}
+}
+
+func TestAcceptsUpstream(t *testing.T) {
+	tests := []struct {
+		Upstreams   []string
+		Owner       string
+		Repo        string
+		Expectation bool
+	}{
+		{nil, "32leaves", "werft", false},
+		{[]string{"32leaves/werft"}, "32leaves", "werft", true},
+		{[]string{"32leaves/werft"}, "32leaves", "leeway", false},
+		{[]string{"32leaves/*"}, "32leaves", "leeway", true},
+		{[]string{"32leaves/*"}, "someone-else", "werft", false},
+		{[]string{"*/*"}, "32leaves", "werft", false},
+		{[]string{"32leaves"}, "32leaves", "werft", false},
+		{[]string{"gitpod-io/gitpod", "32leaves/werft"}, "32leaves", "werft", true},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.Upstreams, ",")+"/"+test.Owner+"/"+test.Repo, func(t *testing.T) {
+			c := &repoconfig.C{Upstreams: test.Upstreams}
+			if act := c.AcceptsUpstream(test.Owner, test.Repo); act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
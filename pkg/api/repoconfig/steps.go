@@ -0,0 +1,72 @@
+package repoconfig
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StepSpec is a step of a job, or a group of steps. Steps run one after the other in the pod of the job, each in a
+// container of its own, and share the workspace. The steps of a parallel group run at the same time.
+type StepSpec struct {
+	// Container runs the step and its name names the step. Each step brings its own image, which needs no
+	// shell - werft provides the one which waits for the previous steps. Steps need a command though, because
+	// werft runs it in that shell. Steps work in the workspace unless they set a working directory.
+	corev1.Container
+
+	// Parallel makes the steps of this group run at the same time
+	Parallel bool `yaml:"parallel,omitempty" json:"parallel,omitempty"`
+
+	// Steps makes this step a group of steps. Groups have no container of their own.
+	Steps []*StepSpec `yaml:"steps,omitempty" json:"steps,omitempty"`
+}
+
+// IsGroup returns true if the step is a group of steps rather than a step which runs
+func (s *StepSpec) IsGroup() bool {
+	return len(s.Steps) > 0 || s.Parallel
+}
+
+// StepPlan is a step which runs, together with the names of the steps it waits for
+type StepPlan struct {
+	Container corev1.Container
+	After     []string
+}
+
+// PlanSteps flattens the groups of steps into the steps which run and the steps each of them waits for
+func PlanSteps(steps []*StepSpec) []StepPlan {
+	res, _ := planSteps(steps, nil, false)
+	return res
+}
+
+// planSteps plans a list of steps which start once all steps in after are done. It returns the steps it
+// planned and the steps which have to be done for the list to be done.
+func planSteps(steps []*StepSpec, after []string, parallel bool) (plan []StepPlan, done []string) {
+	done = after
+	var parallelDone []string
+	for _, s := range steps {
+		if s == nil {
+			continue
+		}
+
+		var (
+			stepPlan []StepPlan
+			stepDone []string
+		)
+		if s.IsGroup() {
+			stepPlan, stepDone = planSteps(s.Steps, done, s.Parallel)
+		} else {
+			stepPlan = []StepPlan{{Container: s.Container, After: done}}
+			stepDone = []string{s.Name}
+		}
+		plan = append(plan, stepPlan...)
+
+		if parallel {
+			// all steps of a parallel group wait for the steps before the group, not for each other
+			parallelDone = append(parallelDone, stepDone...)
+		} else {
+			done = stepDone
+		}
+	}
+	if parallel && len(parallelDone) > 0 {
+		done = parallelDone
+	}
+	return plan, done
+}
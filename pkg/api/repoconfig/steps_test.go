@@ -0,0 +1,81 @@
+package repoconfig_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPlanSteps(t *testing.T) {
+	step := func(name string) *repoconfig.StepSpec {
+		return &repoconfig.StepSpec{Container: corev1.Container{Name: name}}
+	}
+	group := func(parallel bool, steps ...*repoconfig.StepSpec) *repoconfig.StepSpec {
+		return &repoconfig.StepSpec{Parallel: parallel, Steps: steps}
+	}
+
+	tests := []struct {
+		Name        string
+		Steps       []*repoconfig.StepSpec
+		Expectation map[string][]string
+	}{
+		{
+			Name:        "no steps",
+			Expectation: map[string][]string{},
+		},
+		{
+			Name:  "sequential",
+			Steps: []*repoconfig.StepSpec{step("a"), step("b"), step("c")},
+			Expectation: map[string][]string{
+				"a": nil,
+				"b": {"a"},
+				"c": {"b"},
+			},
+		},
+		{
+			Name:  "parallel group",
+			Steps: []*repoconfig.StepSpec{step("build"), group(true, step("test"), step("lint")), step("publish")},
+			Expectation: map[string][]string{
+				"build":   nil,
+				"test":    {"build"},
+				"lint":    {"build"},
+				"publish": {"test", "lint"},
+			},
+		},
+		{
+			Name: "sequential group in parallel group",
+			Steps: []*repoconfig.StepSpec{
+				group(true, group(false, step("compile"), step("test")), step("lint")),
+				step("publish"),
+			},
+			Expectation: map[string][]string{
+				"compile": nil,
+				"test":    {"compile"},
+				"lint":    nil,
+				"publish": {"test", "lint"},
+			},
+		},
+		{
+			Name:  "empty parallel group",
+			Steps: []*repoconfig.StepSpec{step("a"), group(true), step("b")},
+			Expectation: map[string][]string{
+				"a": nil,
+				"b": {"a"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := make(map[string][]string)
+			for _, p := range repoconfig.PlanSteps(test.Steps) {
+				act[p.Container.Name] = p.After
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
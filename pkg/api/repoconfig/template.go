@@ -0,0 +1,165 @@
+package repoconfig
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/Masterminds/sprig/v3"
+	"golang.org/x/xerrors"
+)
+
+// restrictedTemplateFuncs are the sprig functions job specs cannot use unless the operator allows all of them.
+// Job specs are executed in the werft server, where these functions would read its environment, make network requests,
+// keep its CPU busy or produce huge outputs. Some of them, e.g. bcrypt, come with later sprig versions only and are
+// listed s.t. they do not become available by upgrading sprig.
+var restrictedTemplateFuncs = []string{
+	"env",
+	"expandenv",
+	"getHostByName",
+	"genPrivateKey",
+	"derivePassword",
+	"genCA",
+	"genSelfSignedCert",
+	"genSignedCert",
+	"repeat",
+	"until",
+	"untilStep",
+	"bcrypt",
+	"htpasswd",
+}
+
+var (
+	// maxRenderedJobSpecSize is the largest job spec a template may produce
+	maxRenderedJobSpecSize = 1 << 20
+	// templateTimeout is how long executing a job spec template may take
+	templateTimeout = 10 * time.Second
+)
+
+// TemplateFuncs returns the functions job specs can use: the sprig functions, without the restricted ones unless unrestricted is set.
+// Job specs which call a restricted function still parse, but fail to execute.
+func TemplateFuncs(unrestricted bool) template.FuncMap {
+	res := sprig.TxtFuncMap()
+	if unrestricted {
+		return res
+	}
+	for _, name := range restrictedTemplateFuncs {
+		name := name
+		res[name] = func(...interface{}) (string, error) {
+			return "", xerrors.Errorf("%s is not available in job specs on this werft installation", name)
+		}
+	}
+	return res
+}
+
+// ExecuteTemplate executes a job spec template against obj. Executing the template fails if it produces more than
+// maxRenderedJobSpecSize bytes or takes longer than templateTimeout, s.t. job specs cannot exhaust the memory or CPU
+// of the werft server.
+func ExecuteTemplate(tpl *template.Template, obj interface{}) ([]byte, error) {
+	w := &limitedWriter{Limit: maxRenderedJobSpecSize, Timeout: templateTimeout}
+	w.Deadline = time.Now().Add(w.Timeout)
+	done := make(chan error, 1)
+	go func() {
+		done <- tpl.Execute(w, obj)
+	}()
+
+	timeout := time.NewTimer(w.Timeout)
+	defer timeout.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		return w.Buf.Bytes(), nil
+	case <-timeout.C:
+		// the template stops at its next write, which fails once the deadline has passed
+		return nil, xerrors.Errorf("template: %s: execution took longer than %s", tpl.Name(), w.Timeout)
+	}
+}
+
+// limitedWriter fails writes which exceed its limit or happen after its deadline
+type limitedWriter struct {
+	Buf      bytes.Buffer
+	Limit    int
+	Timeout  time.Duration
+	Deadline time.Time
+}
+
+func (w *limitedWriter) Write(p []byte) (n int, err error) {
+	if time.Now().After(w.Deadline) {
+		return 0, xerrors.Errorf("execution took longer than %s", w.Timeout)
+	}
+	if w.Buf.Len()+len(p) > w.Limit {
+		return 0, xerrors.Errorf("job spec is larger than %d bytes", w.Limit)
+	}
+	return w.Buf.Write(p)
+}
+
+// TemplateObj is the object job specs are executed against as Go templates
+type TemplateObj struct {
+	Name        string
+	Owner       string
+	Repository  werftv1.Repository
+	Trigger     string
+	Annotations map[string]string
+	Event       TemplateEvent
+}
+
+// TemplateEvent describes the webhook event which started a job. All fields are empty if no webhook started the job.
+type TemplateEvent struct {
+	// Type is the type of the event, e.g. push or pull_request
+	Type string
+	// PullRequest is the number of the pull request the job runs for, or zero if there is none
+	PullRequest int
+	Draft       bool
+	Labels      []string
+	// BaseBranch is the branch the pull request is to be merged into
+	BaseBranch    string
+	CommitMessage string
+}
+
+// HasLabel returns true if the pull request has the label, e.g. {{ if .Event.HasLabel "deploy" }}
+func (e TemplateEvent) HasLabel(label string) bool {
+	for _, l := range e.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTemplateObj produces the template object for a job
+func NewTemplateObj(name string, md *werftv1.JobMetadata) TemplateObj {
+	annotations := make(map[string]string)
+	for _, a := range md.Annotations {
+		annotations[a.Key] = a.Value
+	}
+
+	var repo werftv1.Repository
+	if md.Repository != nil {
+		repo = *md.Repository
+	}
+
+	var evt TemplateEvent
+	if e := md.Event; e != nil {
+		evt = TemplateEvent{
+			Type:          e.Type,
+			PullRequest:   int(e.PullRequest),
+			Draft:         e.Draft,
+			Labels:        e.Labels,
+			BaseBranch:    e.BaseBranch,
+			CommitMessage: e.CommitMessage,
+		}
+	}
+
+	return TemplateObj{
+		Name:        name,
+		Owner:       md.Owner,
+		Repository:  repo,
+		Trigger:     strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")),
+		Annotations: annotations,
+		Event:       evt,
+	}
+}
@@ -0,0 +1,133 @@
+package repoconfig_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestTemplateObjEvent(t *testing.T) {
+	const tpl = `{{ .Event.Type }}|{{ if .Event.Draft }}draft{{ end }}|{{ if .Event.HasLabel "deploy" }}deploy{{ end }}|{{ .Event.BaseBranch }}|{{ .Event.PullRequest }}`
+	tests := []struct {
+		Name        string
+		Event       *v1.JobEvent
+		Expectation string
+	}{
+		{"no event", nil, "||||0"},
+		{"push", &v1.JobEvent{Type: "push", CommitMessage: "fix the build"}, "push||||0"},
+		{"draft", &v1.JobEvent{Type: "pull_request", PullRequest: 42, Draft: true, BaseBranch: "main"}, "pull_request|draft||main|42"},
+		{"label", &v1.JobEvent{Type: "push", PullRequest: 42, Labels: []string{"docs", "deploy"}, BaseBranch: "main"}, "push||deploy|main|42"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			obj := repoconfig.NewTemplateObj("werft-build.1", &v1.JobMetadata{Event: test.Event})
+
+			buf := bytes.NewBuffer(nil)
+			err := template.Must(template.New("job").Parse(tpl)).Execute(buf, obj)
+			if err != nil {
+				t.Fatalf("cannot execute template: %v", err)
+			}
+			if act := buf.String(); act != test.Expectation {
+				t.Errorf("expected \"%s\", actual \"%s\"", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	os.Setenv("WERFT_TEMPLATE_TEST", "secret")
+	defer os.Unsetenv("WERFT_TEMPLATE_TEST")
+
+	tests := []struct {
+		Name         string
+		Template     string
+		Unrestricted bool
+		Expectation  string
+		Error        string
+	}{
+		{"safe function", `{{ "foo" | upper }}`, false, "FOO", ""},
+		{"env", `{{ env "WERFT_TEMPLATE_TEST" }}`, false, "", "template: job:1:3: executing \"job\" at <env \"WERFT_TEMPLATE_TEST\">: error calling env: env is not available in job specs on this werft installation"},
+		{"expandenv", `{{ expandenv "$WERFT_TEMPLATE_TEST" }}`, false, "", "template: job:1:3: executing \"job\" at <expandenv \"$WERFT_TEMPLATE_TEST\">: error calling expandenv: expandenv is not available in job specs on this werft installation"},
+		{"getHostByName", `{{ getHostByName "localhost" }}`, false, "", "template: job:1:3: executing \"job\" at <getHostByName \"localhost\">: error calling getHostByName: getHostByName is not available in job specs on this werft installation"},
+		{"genCA", `{{ genCA "werft" 365 }}`, false, "", "template: job:1:3: executing \"job\" at <genCA \"werft\" 365>: error calling genCA: genCA is not available in job specs on this werft installation"},
+		{"repeat", `{{ repeat 1000000000 "x" }}`, false, "", "template: job:1:3: executing \"job\" at <repeat 1000000000 \"x\">: error calling repeat: repeat is not available in job specs on this werft installation"},
+		{"until", `{{ range until 1000000000 }}{{ end }}`, false, "", "template: job:1:9: executing \"job\" at <until 1000000000>: error calling until: until is not available in job specs on this werft installation"},
+		{"untilStep", `{{ range untilStep 0 1000000000 1 }}{{ end }}`, false, "", "template: job:1:9: executing \"job\" at <untilStep 0 1000000000 1>: error calling untilStep: untilStep is not available in job specs on this werft installation"},
+		{"repeat unrestricted", `{{ repeat 3 "x" }}`, true, "xxx", ""},
+		{"env unrestricted", `{{ env "WERFT_TEMPLATE_TEST" }}`, true, "secret", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tpl, err := template.New("job").Funcs(repoconfig.TemplateFuncs(test.Unrestricted)).Parse(test.Template)
+			if err != nil {
+				t.Fatalf("cannot parse template: %v", err)
+			}
+
+			buf := bytes.NewBuffer(nil)
+			err = tpl.Execute(buf, nil)
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if act := buf.String(); err == nil && act != test.Expectation {
+				t.Errorf("expected \"%s\", actual \"%s\"", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	defer repoconfig.SetTemplateLimits(64, 100*time.Millisecond)()
+
+	tests := []struct {
+		Name        string
+		Template    string
+		Expectation string
+		Error       string
+	}{
+		{"small", `{{ .Name }}`, "werft-build.1", ""},
+		{"at the limit", strings.Repeat("x", 64), strings.Repeat("x", 64), ""},
+		{"too large", `{{ range $i := .Items }}{{ $i }}{{ end }}`, "", "larger than 64 bytes"},
+		{"too slow", `{{ range $i := .Items }}{{ sleep }}{{ end }}`, "", "took longer than 100ms"},
+	}
+
+	obj := struct {
+		Name  string
+		Items []string
+	}{"werft-build.1", strings.Split(strings.Repeat("0123456789", 10), "")}
+	funcs := template.FuncMap{
+		"sleep": func() string {
+			time.Sleep(20 * time.Millisecond)
+			return ""
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tpl := template.Must(template.New("job").Funcs(funcs).Parse(test.Template))
+			act, err := repoconfig.ExecuteTemplate(tpl, obj)
+			if test.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Error) {
+					t.Fatalf("expected error containing \"%s\", actual %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(act) != test.Expectation {
+				t.Errorf("expected \"%s\", actual \"%s\"", test.Expectation, act)
+			}
+		})
+	}
+}
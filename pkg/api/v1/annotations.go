@@ -0,0 +1,12 @@
+package v1
+
+// SetAnnotation adds an annotation to the job metadata or updates its value if it exists already
+func (md *JobMetadata) SetAnnotation(key, value string) {
+	for _, a := range md.Annotations {
+		if a.Key == key {
+			a.Value = value
+			return
+		}
+	}
+	md.Annotations = append(md.Annotations, &Annotation{Key: key, Value: value})
+}
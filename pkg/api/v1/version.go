@@ -0,0 +1,4 @@
+package v1
+
+// APIVersion is the version of the werft API defined in this package
+const APIVersion = "v1"
@@ -0,0 +1,3712 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: werft-admin.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SetDrainRequest struct {
+	Drain                bool     `protobuf:"varint,1,opt,name=drain,proto3" json:"drain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDrainRequest) Reset()         { *m = SetDrainRequest{} }
+func (m *SetDrainRequest) String() string { return proto.CompactTextString(m) }
+func (*SetDrainRequest) ProtoMessage()    {}
+func (*SetDrainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{0}
+}
+
+func (m *SetDrainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDrainRequest.Unmarshal(m, b)
+}
+func (m *SetDrainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDrainRequest.Marshal(b, m, deterministic)
+}
+func (m *SetDrainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDrainRequest.Merge(m, src)
+}
+func (m *SetDrainRequest) XXX_Size() int {
+	return xxx_messageInfo_SetDrainRequest.Size(m)
+}
+func (m *SetDrainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDrainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDrainRequest proto.InternalMessageInfo
+
+func (m *SetDrainRequest) GetDrain() bool {
+	if m != nil {
+		return m.Drain
+	}
+	return false
+}
+
+type SetDrainResponse struct {
+	Draining             bool     `protobuf:"varint,1,opt,name=draining,proto3" json:"draining,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDrainResponse) Reset()         { *m = SetDrainResponse{} }
+func (m *SetDrainResponse) String() string { return proto.CompactTextString(m) }
+func (*SetDrainResponse) ProtoMessage()    {}
+func (*SetDrainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{1}
+}
+
+func (m *SetDrainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDrainResponse.Unmarshal(m, b)
+}
+func (m *SetDrainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDrainResponse.Marshal(b, m, deterministic)
+}
+func (m *SetDrainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDrainResponse.Merge(m, src)
+}
+func (m *SetDrainResponse) XXX_Size() int {
+	return xxx_messageInfo_SetDrainResponse.Size(m)
+}
+func (m *SetDrainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDrainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDrainResponse proto.InternalMessageInfo
+
+func (m *SetDrainResponse) GetDraining() bool {
+	if m != nil {
+		return m.Draining
+	}
+	return false
+}
+
+type SetMaintenanceRequest struct {
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// message is shown to users whose jobs are rejected and on the UI
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// queue_triggers queues jobs triggered by webhooks or other jobs rather than rejecting them
+	QueueTriggers bool `protobuf:"varint,3,opt,name=queue_triggers,json=queueTriggers,proto3" json:"queue_triggers,omitempty"`
+	// stop_running stops all running jobs when maintenance starts
+	StopRunning          bool     `protobuf:"varint,4,opt,name=stop_running,json=stopRunning,proto3" json:"stop_running,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMaintenanceRequest) Reset()         { *m = SetMaintenanceRequest{} }
+func (m *SetMaintenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceRequest) ProtoMessage()    {}
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{2}
+}
+
+func (m *SetMaintenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceRequest.Unmarshal(m, b)
+}
+func (m *SetMaintenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceRequest.Merge(m, src)
+}
+func (m *SetMaintenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceRequest.Size(m)
+}
+func (m *SetMaintenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMaintenanceRequest proto.InternalMessageInfo
+
+func (m *SetMaintenanceRequest) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *SetMaintenanceRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *SetMaintenanceRequest) GetQueueTriggers() bool {
+	if m != nil {
+		return m.QueueTriggers
+	}
+	return false
+}
+
+func (m *SetMaintenanceRequest) GetStopRunning() bool {
+	if m != nil {
+		return m.StopRunning
+	}
+	return false
+}
+
+type SetMaintenanceResponse struct {
+	Mode *MaintenanceMode `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	// stopped lists the jobs stopped because of stop_running
+	Stopped []string `protobuf:"bytes,2,rep,name=stopped,proto3" json:"stopped,omitempty"`
+	// running lists the jobs which are still running
+	Running []string `protobuf:"bytes,3,rep,name=running,proto3" json:"running,omitempty"`
+	// started lists the queued jobs which started because maintenance ended
+	Started              []string `protobuf:"bytes,4,rep,name=started,proto3" json:"started,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMaintenanceResponse) Reset()         { *m = SetMaintenanceResponse{} }
+func (m *SetMaintenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceResponse) ProtoMessage()    {}
+func (*SetMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{3}
+}
+
+func (m *SetMaintenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceResponse.Unmarshal(m, b)
+}
+func (m *SetMaintenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceResponse.Merge(m, src)
+}
+func (m *SetMaintenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceResponse.Size(m)
+}
+func (m *SetMaintenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMaintenanceResponse proto.InternalMessageInfo
+
+func (m *SetMaintenanceResponse) GetMode() *MaintenanceMode {
+	if m != nil {
+		return m.Mode
+	}
+	return nil
+}
+
+func (m *SetMaintenanceResponse) GetStopped() []string {
+	if m != nil {
+		return m.Stopped
+	}
+	return nil
+}
+
+func (m *SetMaintenanceResponse) GetRunning() []string {
+	if m != nil {
+		return m.Running
+	}
+	return nil
+}
+
+func (m *SetMaintenanceResponse) GetStarted() []string {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+type RequeueStuckJobsRequest struct {
+	// older_than is the minimum age of a job before it's considered stuck
+	OlderThan            *duration.Duration `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	DryRun               bool               `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RequeueStuckJobsRequest) Reset()         { *m = RequeueStuckJobsRequest{} }
+func (m *RequeueStuckJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*RequeueStuckJobsRequest) ProtoMessage()    {}
+func (*RequeueStuckJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{4}
+}
+
+func (m *RequeueStuckJobsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequeueStuckJobsRequest.Unmarshal(m, b)
+}
+func (m *RequeueStuckJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequeueStuckJobsRequest.Marshal(b, m, deterministic)
+}
+func (m *RequeueStuckJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequeueStuckJobsRequest.Merge(m, src)
+}
+func (m *RequeueStuckJobsRequest) XXX_Size() int {
+	return xxx_messageInfo_RequeueStuckJobsRequest.Size(m)
+}
+func (m *RequeueStuckJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequeueStuckJobsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequeueStuckJobsRequest proto.InternalMessageInfo
+
+func (m *RequeueStuckJobsRequest) GetOlderThan() *duration.Duration {
+	if m != nil {
+		return m.OlderThan
+	}
+	return nil
+}
+
+func (m *RequeueStuckJobsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type RequeueStuckJobsResponse struct {
+	// stuck lists the jobs found to be stuck
+	Stuck []*JobStatus `protobuf:"bytes,1,rep,name=stuck,proto3" json:"stuck,omitempty"`
+	// restarted lists the names of the jobs started in place of the stuck ones
+	Restarted            []string `protobuf:"bytes,2,rep,name=restarted,proto3" json:"restarted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RequeueStuckJobsResponse) Reset()         { *m = RequeueStuckJobsResponse{} }
+func (m *RequeueStuckJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*RequeueStuckJobsResponse) ProtoMessage()    {}
+func (*RequeueStuckJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{5}
+}
+
+func (m *RequeueStuckJobsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequeueStuckJobsResponse.Unmarshal(m, b)
+}
+func (m *RequeueStuckJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequeueStuckJobsResponse.Marshal(b, m, deterministic)
+}
+func (m *RequeueStuckJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequeueStuckJobsResponse.Merge(m, src)
+}
+func (m *RequeueStuckJobsResponse) XXX_Size() int {
+	return xxx_messageInfo_RequeueStuckJobsResponse.Size(m)
+}
+func (m *RequeueStuckJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequeueStuckJobsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequeueStuckJobsResponse proto.InternalMessageInfo
+
+func (m *RequeueStuckJobsResponse) GetStuck() []*JobStatus {
+	if m != nil {
+		return m.Stuck
+	}
+	return nil
+}
+
+func (m *RequeueStuckJobsResponse) GetRestarted() []string {
+	if m != nil {
+		return m.Restarted
+	}
+	return nil
+}
+
+type PruneRequest struct {
+	// older_than is the minimum age of a finished job before it's removed
+	OlderThan            *duration.Duration `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	DryRun               bool               `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *PruneRequest) Reset()         { *m = PruneRequest{} }
+func (m *PruneRequest) String() string { return proto.CompactTextString(m) }
+func (*PruneRequest) ProtoMessage()    {}
+func (*PruneRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{6}
+}
+
+func (m *PruneRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneRequest.Unmarshal(m, b)
+}
+func (m *PruneRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneRequest.Marshal(b, m, deterministic)
+}
+func (m *PruneRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneRequest.Merge(m, src)
+}
+func (m *PruneRequest) XXX_Size() int {
+	return xxx_messageInfo_PruneRequest.Size(m)
+}
+func (m *PruneRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneRequest proto.InternalMessageInfo
+
+func (m *PruneRequest) GetOlderThan() *duration.Duration {
+	if m != nil {
+		return m.OlderThan
+	}
+	return nil
+}
+
+func (m *PruneRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type PruneResponse struct {
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// pinned are the jobs which were old enough but kept because they are pinned
+	Pinned               []string `protobuf:"bytes,2,rep,name=pinned,proto3" json:"pinned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneResponse) Reset()         { *m = PruneResponse{} }
+func (m *PruneResponse) String() string { return proto.CompactTextString(m) }
+func (*PruneResponse) ProtoMessage()    {}
+func (*PruneResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{7}
+}
+
+func (m *PruneResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneResponse.Unmarshal(m, b)
+}
+func (m *PruneResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneResponse.Marshal(b, m, deterministic)
+}
+func (m *PruneResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneResponse.Merge(m, src)
+}
+func (m *PruneResponse) XXX_Size() int {
+	return xxx_messageInfo_PruneResponse.Size(m)
+}
+func (m *PruneResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneResponse proto.InternalMessageInfo
+
+func (m *PruneResponse) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *PruneResponse) GetPinned() []string {
+	if m != nil {
+		return m.Pinned
+	}
+	return nil
+}
+
+type ListTokensRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTokensRequest) Reset()         { *m = ListTokensRequest{} }
+func (m *ListTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTokensRequest) ProtoMessage()    {}
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{8}
+}
+
+func (m *ListTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTokensRequest.Unmarshal(m, b)
+}
+func (m *ListTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTokensRequest.Merge(m, src)
+}
+func (m *ListTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTokensRequest.Size(m)
+}
+func (m *ListTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTokensRequest proto.InternalMessageInfo
+
+type ListTokensResponse struct {
+	Tokens               []*TokenInfo `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListTokensResponse) Reset()         { *m = ListTokensResponse{} }
+func (m *ListTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTokensResponse) ProtoMessage()    {}
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{9}
+}
+
+func (m *ListTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTokensResponse.Unmarshal(m, b)
+}
+func (m *ListTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTokensResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTokensResponse.Merge(m, src)
+}
+func (m *ListTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTokensResponse.Size(m)
+}
+func (m *ListTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTokensResponse proto.InternalMessageInfo
+
+func (m *ListTokensResponse) GetTokens() []*TokenInfo {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type TokenInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes               []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
+func (m *TokenInfo) String() string { return proto.CompactTextString(m) }
+func (*TokenInfo) ProtoMessage()    {}
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{10}
+}
+
+func (m *TokenInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenInfo.Unmarshal(m, b)
+}
+func (m *TokenInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenInfo.Marshal(b, m, deterministic)
+}
+func (m *TokenInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenInfo.Merge(m, src)
+}
+func (m *TokenInfo) XXX_Size() int {
+	return xxx_messageInfo_TokenInfo.Size(m)
+}
+func (m *TokenInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenInfo proto.InternalMessageInfo
+
+func (m *TokenInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type GetPluginStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPluginStatusRequest) Reset()         { *m = GetPluginStatusRequest{} }
+func (m *GetPluginStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPluginStatusRequest) ProtoMessage()    {}
+func (*GetPluginStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{11}
+}
+
+func (m *GetPluginStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPluginStatusRequest.Unmarshal(m, b)
+}
+func (m *GetPluginStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPluginStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPluginStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPluginStatusRequest.Merge(m, src)
+}
+func (m *GetPluginStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPluginStatusRequest.Size(m)
+}
+func (m *GetPluginStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPluginStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPluginStatusRequest proto.InternalMessageInfo
+
+type GetPluginStatusResponse struct {
+	Plugins              []*PluginStatus `protobuf:"bytes,1,rep,name=plugins,proto3" json:"plugins,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetPluginStatusResponse) Reset()         { *m = GetPluginStatusResponse{} }
+func (m *GetPluginStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPluginStatusResponse) ProtoMessage()    {}
+func (*GetPluginStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{12}
+}
+
+func (m *GetPluginStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPluginStatusResponse.Unmarshal(m, b)
+}
+func (m *GetPluginStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPluginStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPluginStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPluginStatusResponse.Merge(m, src)
+}
+func (m *GetPluginStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPluginStatusResponse.Size(m)
+}
+func (m *GetPluginStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPluginStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPluginStatusResponse proto.InternalMessageInfo
+
+func (m *GetPluginStatusResponse) GetPlugins() []*PluginStatus {
+	if m != nil {
+		return m.Plugins
+	}
+	return nil
+}
+
+type PluginStatus struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string               `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Running              bool                 `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"`
+	Started              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=started,proto3" json:"started,omitempty"`
+	Error                string               `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PluginStatus) Reset()         { *m = PluginStatus{} }
+func (m *PluginStatus) String() string { return proto.CompactTextString(m) }
+func (*PluginStatus) ProtoMessage()    {}
+func (*PluginStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{13}
+}
+
+func (m *PluginStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginStatus.Unmarshal(m, b)
+}
+func (m *PluginStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginStatus.Marshal(b, m, deterministic)
+}
+func (m *PluginStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginStatus.Merge(m, src)
+}
+func (m *PluginStatus) XXX_Size() int {
+	return xxx_messageInfo_PluginStatus.Size(m)
+}
+func (m *PluginStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginStatus proto.InternalMessageInfo
+
+func (m *PluginStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PluginStatus) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *PluginStatus) GetRunning() bool {
+	if m != nil {
+		return m.Running
+	}
+	return false
+}
+
+func (m *PluginStatus) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *PluginStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type DumpConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DumpConfigRequest) Reset()         { *m = DumpConfigRequest{} }
+func (m *DumpConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*DumpConfigRequest) ProtoMessage()    {}
+func (*DumpConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{14}
+}
+
+func (m *DumpConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DumpConfigRequest.Unmarshal(m, b)
+}
+func (m *DumpConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DumpConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *DumpConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DumpConfigRequest.Merge(m, src)
+}
+func (m *DumpConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_DumpConfigRequest.Size(m)
+}
+func (m *DumpConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DumpConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DumpConfigRequest proto.InternalMessageInfo
+
+type DumpConfigResponse struct {
+	Yaml                 string   `protobuf:"bytes,1,opt,name=yaml,proto3" json:"yaml,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DumpConfigResponse) Reset()         { *m = DumpConfigResponse{} }
+func (m *DumpConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*DumpConfigResponse) ProtoMessage()    {}
+func (*DumpConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{15}
+}
+
+func (m *DumpConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DumpConfigResponse.Unmarshal(m, b)
+}
+func (m *DumpConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DumpConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *DumpConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DumpConfigResponse.Merge(m, src)
+}
+func (m *DumpConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_DumpConfigResponse.Size(m)
+}
+func (m *DumpConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DumpConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DumpConfigResponse proto.InternalMessageInfo
+
+func (m *DumpConfigResponse) GetYaml() string {
+	if m != nil {
+		return m.Yaml
+	}
+	return ""
+}
+
+type RepositorySettings struct {
+	Owner string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repo  string `protobuf:"bytes,2,opt,name=repo,proto3" json:"repo,omitempty"`
+	// webhook_secret is the secret GitHub signs webhooks of this repository with, if it differs from the global one
+	WebhookSecret string `protobuf:"bytes,3,opt,name=webhook_secret,json=webhookSecret,proto3" json:"webhook_secret,omitempty"`
+	// secrets are Kubernetes secrets made available to jobs of this repository
+	Secrets []*SecretBinding  `protobuf:"bytes,4,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	Policy  *RepositoryPolicy `protobuf:"bytes,5,opt,name=policy,proto3" json:"policy,omitempty"`
+	// source names where the settings come from, e.g. crd:namespace/name for a WerftRepository resource
+	Source string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	// registries are the container registries jobs of this repository pull images from and push images to
+	Registries           []*RegistryCredential `protobuf:"bytes,7,rep,name=registries,proto3" json:"registries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *RepositorySettings) Reset()         { *m = RepositorySettings{} }
+func (m *RepositorySettings) String() string { return proto.CompactTextString(m) }
+func (*RepositorySettings) ProtoMessage()    {}
+func (*RepositorySettings) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{16}
+}
+
+func (m *RepositorySettings) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepositorySettings.Unmarshal(m, b)
+}
+func (m *RepositorySettings) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepositorySettings.Marshal(b, m, deterministic)
+}
+func (m *RepositorySettings) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepositorySettings.Merge(m, src)
+}
+func (m *RepositorySettings) XXX_Size() int {
+	return xxx_messageInfo_RepositorySettings.Size(m)
+}
+func (m *RepositorySettings) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepositorySettings.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepositorySettings proto.InternalMessageInfo
+
+func (m *RepositorySettings) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *RepositorySettings) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *RepositorySettings) GetWebhookSecret() string {
+	if m != nil {
+		return m.WebhookSecret
+	}
+	return ""
+}
+
+func (m *RepositorySettings) GetSecrets() []*SecretBinding {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func (m *RepositorySettings) GetPolicy() *RepositoryPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+func (m *RepositorySettings) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *RepositorySettings) GetRegistries() []*RegistryCredential {
+	if m != nil {
+		return m.Registries
+	}
+	return nil
+}
+
+type RegistryCredential struct {
+	// registry is the host of the registry, e.g. eu.gcr.io
+	Registry             string   `protobuf:"bytes,1,opt,name=registry,proto3" json:"registry,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegistryCredential) Reset()         { *m = RegistryCredential{} }
+func (m *RegistryCredential) String() string { return proto.CompactTextString(m) }
+func (*RegistryCredential) ProtoMessage()    {}
+func (*RegistryCredential) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{17}
+}
+
+func (m *RegistryCredential) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryCredential.Unmarshal(m, b)
+}
+func (m *RegistryCredential) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryCredential.Marshal(b, m, deterministic)
+}
+func (m *RegistryCredential) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryCredential.Merge(m, src)
+}
+func (m *RegistryCredential) XXX_Size() int {
+	return xxx_messageInfo_RegistryCredential.Size(m)
+}
+func (m *RegistryCredential) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryCredential.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryCredential proto.InternalMessageInfo
+
+func (m *RegistryCredential) GetRegistry() string {
+	if m != nil {
+		return m.Registry
+	}
+	return ""
+}
+
+func (m *RegistryCredential) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RegistryCredential) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type SecretBinding struct {
+	// secret is the name of the Kubernetes secret in the namespace werft runs jobs in
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// mount_path is where the secret is mounted in all containers of a job. If empty, the secret's keys
+	// become environment variables instead.
+	MountPath            string   `protobuf:"bytes,2,opt,name=mount_path,json=mountPath,proto3" json:"mount_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SecretBinding) Reset()         { *m = SecretBinding{} }
+func (m *SecretBinding) String() string { return proto.CompactTextString(m) }
+func (*SecretBinding) ProtoMessage()    {}
+func (*SecretBinding) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{18}
+}
+
+func (m *SecretBinding) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SecretBinding.Unmarshal(m, b)
+}
+func (m *SecretBinding) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SecretBinding.Marshal(b, m, deterministic)
+}
+func (m *SecretBinding) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SecretBinding.Merge(m, src)
+}
+func (m *SecretBinding) XXX_Size() int {
+	return xxx_messageInfo_SecretBinding.Size(m)
+}
+func (m *SecretBinding) XXX_DiscardUnknown() {
+	xxx_messageInfo_SecretBinding.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SecretBinding proto.InternalMessageInfo
+
+func (m *SecretBinding) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *SecretBinding) GetMountPath() string {
+	if m != nil {
+		return m.MountPath
+	}
+	return ""
+}
+
+type RepositoryPolicy struct {
+	// allowed_refs are glob patterns of the refs jobs may run on, e.g. refs/heads/*. If empty, all refs are allowed.
+	AllowedRefs []string `protobuf:"bytes,1,rep,name=allowed_refs,json=allowedRefs,proto3" json:"allowed_refs,omitempty"`
+	// allowed_triggers are the triggers which may start jobs. If empty, all triggers are allowed.
+	AllowedTriggers      []JobTrigger `protobuf:"varint,2,rep,packed,name=allowed_triggers,json=allowedTriggers,proto3,enum=v1.JobTrigger" json:"allowed_triggers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *RepositoryPolicy) Reset()         { *m = RepositoryPolicy{} }
+func (m *RepositoryPolicy) String() string { return proto.CompactTextString(m) }
+func (*RepositoryPolicy) ProtoMessage()    {}
+func (*RepositoryPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{19}
+}
+
+func (m *RepositoryPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepositoryPolicy.Unmarshal(m, b)
+}
+func (m *RepositoryPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepositoryPolicy.Marshal(b, m, deterministic)
+}
+func (m *RepositoryPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepositoryPolicy.Merge(m, src)
+}
+func (m *RepositoryPolicy) XXX_Size() int {
+	return xxx_messageInfo_RepositoryPolicy.Size(m)
+}
+func (m *RepositoryPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepositoryPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepositoryPolicy proto.InternalMessageInfo
+
+func (m *RepositoryPolicy) GetAllowedRefs() []string {
+	if m != nil {
+		return m.AllowedRefs
+	}
+	return nil
+}
+
+func (m *RepositoryPolicy) GetAllowedTriggers() []JobTrigger {
+	if m != nil {
+		return m.AllowedTriggers
+	}
+	return nil
+}
+
+type ListRepositoriesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRepositoriesRequest) Reset()         { *m = ListRepositoriesRequest{} }
+func (m *ListRepositoriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRepositoriesRequest) ProtoMessage()    {}
+func (*ListRepositoriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{20}
+}
+
+func (m *ListRepositoriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRepositoriesRequest.Unmarshal(m, b)
+}
+func (m *ListRepositoriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRepositoriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRepositoriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRepositoriesRequest.Merge(m, src)
+}
+func (m *ListRepositoriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRepositoriesRequest.Size(m)
+}
+func (m *ListRepositoriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRepositoriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRepositoriesRequest proto.InternalMessageInfo
+
+type ListRepositoriesResponse struct {
+	Repositories         []*RepositorySettings `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ListRepositoriesResponse) Reset()         { *m = ListRepositoriesResponse{} }
+func (m *ListRepositoriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRepositoriesResponse) ProtoMessage()    {}
+func (*ListRepositoriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{21}
+}
+
+func (m *ListRepositoriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRepositoriesResponse.Unmarshal(m, b)
+}
+func (m *ListRepositoriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRepositoriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRepositoriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRepositoriesResponse.Merge(m, src)
+}
+func (m *ListRepositoriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRepositoriesResponse.Size(m)
+}
+func (m *ListRepositoriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRepositoriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRepositoriesResponse proto.InternalMessageInfo
+
+func (m *ListRepositoriesResponse) GetRepositories() []*RepositorySettings {
+	if m != nil {
+		return m.Repositories
+	}
+	return nil
+}
+
+type ListEventsRequest struct {
+	// name restricts the events to a single job
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// since and until restrict the events to a time range. Both are optional.
+	Since *timestamp.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamp.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	// limit is the maximum number of events returned. Zero means no limit.
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEventsRequest) Reset()         { *m = ListEventsRequest{} }
+func (m *ListEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListEventsRequest) ProtoMessage()    {}
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{22}
+}
+
+func (m *ListEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsRequest.Unmarshal(m, b)
+}
+func (m *ListEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsRequest.Merge(m, src)
+}
+func (m *ListEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListEventsRequest.Size(m)
+}
+func (m *ListEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsRequest proto.InternalMessageInfo
+
+func (m *ListEventsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListEventsRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *ListEventsRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *ListEventsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListEventsResponse struct {
+	Events               []*TraceEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListEventsResponse) Reset()         { *m = ListEventsResponse{} }
+func (m *ListEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListEventsResponse) ProtoMessage()    {}
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{23}
+}
+
+func (m *ListEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsResponse.Unmarshal(m, b)
+}
+func (m *ListEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsResponse.Merge(m, src)
+}
+func (m *ListEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListEventsResponse.Size(m)
+}
+func (m *ListEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsResponse proto.InternalMessageInfo
+
+func (m *ListEventsResponse) GetEvents() []*TraceEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type TraceEvent struct {
+	Time   *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Name   string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status *JobStatus           `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// pod is the JSON encoded Kubernetes pod of the job at the time of the event
+	Pod                  []byte   `protobuf:"bytes,4,opt,name=pod,proto3" json:"pod,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceEvent) Reset()         { *m = TraceEvent{} }
+func (m *TraceEvent) String() string { return proto.CompactTextString(m) }
+func (*TraceEvent) ProtoMessage()    {}
+func (*TraceEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{24}
+}
+
+func (m *TraceEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceEvent.Unmarshal(m, b)
+}
+func (m *TraceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceEvent.Marshal(b, m, deterministic)
+}
+func (m *TraceEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceEvent.Merge(m, src)
+}
+func (m *TraceEvent) XXX_Size() int {
+	return xxx_messageInfo_TraceEvent.Size(m)
+}
+func (m *TraceEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceEvent proto.InternalMessageInfo
+
+func (m *TraceEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *TraceEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TraceEvent) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *TraceEvent) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type NotificationSnooze struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// filter selects the jobs whose notifications are silenced. An empty filter silences all notifications.
+	Filter               []*FilterExpression  `protobuf:"bytes,2,rep,name=filter,proto3" json:"filter,omitempty"`
+	Until                *timestamp.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Reason               string               `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *NotificationSnooze) Reset()         { *m = NotificationSnooze{} }
+func (m *NotificationSnooze) String() string { return proto.CompactTextString(m) }
+func (*NotificationSnooze) ProtoMessage()    {}
+func (*NotificationSnooze) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{25}
+}
+
+func (m *NotificationSnooze) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NotificationSnooze.Unmarshal(m, b)
+}
+func (m *NotificationSnooze) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NotificationSnooze.Marshal(b, m, deterministic)
+}
+func (m *NotificationSnooze) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationSnooze.Merge(m, src)
+}
+func (m *NotificationSnooze) XXX_Size() int {
+	return xxx_messageInfo_NotificationSnooze.Size(m)
+}
+func (m *NotificationSnooze) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationSnooze.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationSnooze proto.InternalMessageInfo
+
+func (m *NotificationSnooze) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NotificationSnooze) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *NotificationSnooze) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *NotificationSnooze) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type SnoozeNotificationsRequest struct {
+	Filter               []*FilterExpression `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
+	Duration             *duration.Duration  `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	Reason               string              `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SnoozeNotificationsRequest) Reset()         { *m = SnoozeNotificationsRequest{} }
+func (m *SnoozeNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*SnoozeNotificationsRequest) ProtoMessage()    {}
+func (*SnoozeNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{26}
+}
+
+func (m *SnoozeNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnoozeNotificationsRequest.Unmarshal(m, b)
+}
+func (m *SnoozeNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnoozeNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *SnoozeNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnoozeNotificationsRequest.Merge(m, src)
+}
+func (m *SnoozeNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_SnoozeNotificationsRequest.Size(m)
+}
+func (m *SnoozeNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnoozeNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnoozeNotificationsRequest proto.InternalMessageInfo
+
+func (m *SnoozeNotificationsRequest) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *SnoozeNotificationsRequest) GetDuration() *duration.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *SnoozeNotificationsRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type SnoozeNotificationsResponse struct {
+	Snooze               *NotificationSnooze `protobuf:"bytes,1,opt,name=snooze,proto3" json:"snooze,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SnoozeNotificationsResponse) Reset()         { *m = SnoozeNotificationsResponse{} }
+func (m *SnoozeNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*SnoozeNotificationsResponse) ProtoMessage()    {}
+func (*SnoozeNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{27}
+}
+
+func (m *SnoozeNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnoozeNotificationsResponse.Unmarshal(m, b)
+}
+func (m *SnoozeNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnoozeNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *SnoozeNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnoozeNotificationsResponse.Merge(m, src)
+}
+func (m *SnoozeNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_SnoozeNotificationsResponse.Size(m)
+}
+func (m *SnoozeNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnoozeNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnoozeNotificationsResponse proto.InternalMessageInfo
+
+func (m *SnoozeNotificationsResponse) GetSnooze() *NotificationSnooze {
+	if m != nil {
+		return m.Snooze
+	}
+	return nil
+}
+
+type ListSnoozesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSnoozesRequest) Reset()         { *m = ListSnoozesRequest{} }
+func (m *ListSnoozesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSnoozesRequest) ProtoMessage()    {}
+func (*ListSnoozesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{28}
+}
+
+func (m *ListSnoozesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSnoozesRequest.Unmarshal(m, b)
+}
+func (m *ListSnoozesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSnoozesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSnoozesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSnoozesRequest.Merge(m, src)
+}
+func (m *ListSnoozesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSnoozesRequest.Size(m)
+}
+func (m *ListSnoozesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSnoozesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSnoozesRequest proto.InternalMessageInfo
+
+type ListSnoozesResponse struct {
+	Snoozes              []*NotificationSnooze `protobuf:"bytes,1,rep,name=snoozes,proto3" json:"snoozes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ListSnoozesResponse) Reset()         { *m = ListSnoozesResponse{} }
+func (m *ListSnoozesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSnoozesResponse) ProtoMessage()    {}
+func (*ListSnoozesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{29}
+}
+
+func (m *ListSnoozesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSnoozesResponse.Unmarshal(m, b)
+}
+func (m *ListSnoozesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSnoozesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSnoozesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSnoozesResponse.Merge(m, src)
+}
+func (m *ListSnoozesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSnoozesResponse.Size(m)
+}
+func (m *ListSnoozesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSnoozesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSnoozesResponse proto.InternalMessageInfo
+
+func (m *ListSnoozesResponse) GetSnoozes() []*NotificationSnooze {
+	if m != nil {
+		return m.Snoozes
+	}
+	return nil
+}
+
+type DeleteSnoozeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSnoozeRequest) Reset()         { *m = DeleteSnoozeRequest{} }
+func (m *DeleteSnoozeRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSnoozeRequest) ProtoMessage()    {}
+func (*DeleteSnoozeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{30}
+}
+
+func (m *DeleteSnoozeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSnoozeRequest.Unmarshal(m, b)
+}
+func (m *DeleteSnoozeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSnoozeRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSnoozeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSnoozeRequest.Merge(m, src)
+}
+func (m *DeleteSnoozeRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSnoozeRequest.Size(m)
+}
+func (m *DeleteSnoozeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSnoozeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSnoozeRequest proto.InternalMessageInfo
+
+func (m *DeleteSnoozeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type DeleteSnoozeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSnoozeResponse) Reset()         { *m = DeleteSnoozeResponse{} }
+func (m *DeleteSnoozeResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSnoozeResponse) ProtoMessage()    {}
+func (*DeleteSnoozeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{31}
+}
+
+func (m *DeleteSnoozeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSnoozeResponse.Unmarshal(m, b)
+}
+func (m *DeleteSnoozeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSnoozeResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteSnoozeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSnoozeResponse.Merge(m, src)
+}
+func (m *DeleteSnoozeResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteSnoozeResponse.Size(m)
+}
+func (m *DeleteSnoozeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSnoozeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSnoozeResponse proto.InternalMessageInfo
+
+type CreateServiceAccountTokenRequest struct {
+	ServiceAccount string   `protobuf:"bytes,1,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	Scopes         []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// expires_in limits how long the token works. If unset, the token works until it's revoked.
+	ExpiresIn            *duration.Duration `protobuf:"bytes,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CreateServiceAccountTokenRequest) Reset()         { *m = CreateServiceAccountTokenRequest{} }
+func (m *CreateServiceAccountTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateServiceAccountTokenRequest) ProtoMessage()    {}
+func (*CreateServiceAccountTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{32}
+}
+
+func (m *CreateServiceAccountTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateServiceAccountTokenRequest.Unmarshal(m, b)
+}
+func (m *CreateServiceAccountTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateServiceAccountTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateServiceAccountTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateServiceAccountTokenRequest.Merge(m, src)
+}
+func (m *CreateServiceAccountTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateServiceAccountTokenRequest.Size(m)
+}
+func (m *CreateServiceAccountTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateServiceAccountTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateServiceAccountTokenRequest proto.InternalMessageInfo
+
+func (m *CreateServiceAccountTokenRequest) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *CreateServiceAccountTokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateServiceAccountTokenRequest) GetExpiresIn() *duration.Duration {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return nil
+}
+
+type CreateServiceAccountTokenResponse struct {
+	Token *ServiceAccountToken `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// secret is the token clients present as bearer token
+	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateServiceAccountTokenResponse) Reset()         { *m = CreateServiceAccountTokenResponse{} }
+func (m *CreateServiceAccountTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateServiceAccountTokenResponse) ProtoMessage()    {}
+func (*CreateServiceAccountTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{33}
+}
+
+func (m *CreateServiceAccountTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateServiceAccountTokenResponse.Unmarshal(m, b)
+}
+func (m *CreateServiceAccountTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateServiceAccountTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateServiceAccountTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateServiceAccountTokenResponse.Merge(m, src)
+}
+func (m *CreateServiceAccountTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateServiceAccountTokenResponse.Size(m)
+}
+func (m *CreateServiceAccountTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateServiceAccountTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateServiceAccountTokenResponse proto.InternalMessageInfo
+
+func (m *CreateServiceAccountTokenResponse) GetToken() *ServiceAccountToken {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *CreateServiceAccountTokenResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type ListServiceAccountTokensRequest struct {
+	// service_account limits the list to the tokens of one service account
+	ServiceAccount       string   `protobuf:"bytes,1,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListServiceAccountTokensRequest) Reset()         { *m = ListServiceAccountTokensRequest{} }
+func (m *ListServiceAccountTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListServiceAccountTokensRequest) ProtoMessage()    {}
+func (*ListServiceAccountTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{34}
+}
+
+func (m *ListServiceAccountTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListServiceAccountTokensRequest.Unmarshal(m, b)
+}
+func (m *ListServiceAccountTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListServiceAccountTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ListServiceAccountTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListServiceAccountTokensRequest.Merge(m, src)
+}
+func (m *ListServiceAccountTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListServiceAccountTokensRequest.Size(m)
+}
+func (m *ListServiceAccountTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListServiceAccountTokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListServiceAccountTokensRequest proto.InternalMessageInfo
+
+func (m *ListServiceAccountTokensRequest) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+type ListServiceAccountTokensResponse struct {
+	Tokens               []*ServiceAccountToken `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ListServiceAccountTokensResponse) Reset()         { *m = ListServiceAccountTokensResponse{} }
+func (m *ListServiceAccountTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListServiceAccountTokensResponse) ProtoMessage()    {}
+func (*ListServiceAccountTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{35}
+}
+
+func (m *ListServiceAccountTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListServiceAccountTokensResponse.Unmarshal(m, b)
+}
+func (m *ListServiceAccountTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListServiceAccountTokensResponse.Marshal(b, m, deterministic)
+}
+func (m *ListServiceAccountTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListServiceAccountTokensResponse.Merge(m, src)
+}
+func (m *ListServiceAccountTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListServiceAccountTokensResponse.Size(m)
+}
+func (m *ListServiceAccountTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListServiceAccountTokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListServiceAccountTokensResponse proto.InternalMessageInfo
+
+func (m *ListServiceAccountTokensResponse) GetTokens() []*ServiceAccountToken {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type RotateServiceAccountTokenRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// grace_period is how long the old token keeps working. If unset, it stops working immediately.
+	GracePeriod          *duration.Duration `protobuf:"bytes,2,opt,name=grace_period,json=gracePeriod,proto3" json:"grace_period,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RotateServiceAccountTokenRequest) Reset()         { *m = RotateServiceAccountTokenRequest{} }
+func (m *RotateServiceAccountTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RotateServiceAccountTokenRequest) ProtoMessage()    {}
+func (*RotateServiceAccountTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{36}
+}
+
+func (m *RotateServiceAccountTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateServiceAccountTokenRequest.Unmarshal(m, b)
+}
+func (m *RotateServiceAccountTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateServiceAccountTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RotateServiceAccountTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateServiceAccountTokenRequest.Merge(m, src)
+}
+func (m *RotateServiceAccountTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RotateServiceAccountTokenRequest.Size(m)
+}
+func (m *RotateServiceAccountTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateServiceAccountTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateServiceAccountTokenRequest proto.InternalMessageInfo
+
+func (m *RotateServiceAccountTokenRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RotateServiceAccountTokenRequest) GetGracePeriod() *duration.Duration {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return nil
+}
+
+type RotateServiceAccountTokenResponse struct {
+	Token *ServiceAccountToken `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// secret is the new token clients present as bearer token
+	Secret               string               `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	Previous             *ServiceAccountToken `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *RotateServiceAccountTokenResponse) Reset()         { *m = RotateServiceAccountTokenResponse{} }
+func (m *RotateServiceAccountTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RotateServiceAccountTokenResponse) ProtoMessage()    {}
+func (*RotateServiceAccountTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{37}
+}
+
+func (m *RotateServiceAccountTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateServiceAccountTokenResponse.Unmarshal(m, b)
+}
+func (m *RotateServiceAccountTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateServiceAccountTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *RotateServiceAccountTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateServiceAccountTokenResponse.Merge(m, src)
+}
+func (m *RotateServiceAccountTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RotateServiceAccountTokenResponse.Size(m)
+}
+func (m *RotateServiceAccountTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateServiceAccountTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateServiceAccountTokenResponse proto.InternalMessageInfo
+
+func (m *RotateServiceAccountTokenResponse) GetToken() *ServiceAccountToken {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *RotateServiceAccountTokenResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *RotateServiceAccountTokenResponse) GetPrevious() *ServiceAccountToken {
+	if m != nil {
+		return m.Previous
+	}
+	return nil
+}
+
+type RevokeServiceAccountTokenRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeServiceAccountTokenRequest) Reset()         { *m = RevokeServiceAccountTokenRequest{} }
+func (m *RevokeServiceAccountTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeServiceAccountTokenRequest) ProtoMessage()    {}
+func (*RevokeServiceAccountTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{38}
+}
+
+func (m *RevokeServiceAccountTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeServiceAccountTokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeServiceAccountTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeServiceAccountTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeServiceAccountTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeServiceAccountTokenRequest.Merge(m, src)
+}
+func (m *RevokeServiceAccountTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeServiceAccountTokenRequest.Size(m)
+}
+func (m *RevokeServiceAccountTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeServiceAccountTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeServiceAccountTokenRequest proto.InternalMessageInfo
+
+func (m *RevokeServiceAccountTokenRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RevokeServiceAccountTokenResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeServiceAccountTokenResponse) Reset()         { *m = RevokeServiceAccountTokenResponse{} }
+func (m *RevokeServiceAccountTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeServiceAccountTokenResponse) ProtoMessage()    {}
+func (*RevokeServiceAccountTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{39}
+}
+
+func (m *RevokeServiceAccountTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeServiceAccountTokenResponse.Unmarshal(m, b)
+}
+func (m *RevokeServiceAccountTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeServiceAccountTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeServiceAccountTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeServiceAccountTokenResponse.Merge(m, src)
+}
+func (m *RevokeServiceAccountTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeServiceAccountTokenResponse.Size(m)
+}
+func (m *RevokeServiceAccountTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeServiceAccountTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeServiceAccountTokenResponse proto.InternalMessageInfo
+
+type ListNumberGroupsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNumberGroupsRequest) Reset()         { *m = ListNumberGroupsRequest{} }
+func (m *ListNumberGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListNumberGroupsRequest) ProtoMessage()    {}
+func (*ListNumberGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{40}
+}
+
+func (m *ListNumberGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNumberGroupsRequest.Unmarshal(m, b)
+}
+func (m *ListNumberGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNumberGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListNumberGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNumberGroupsRequest.Merge(m, src)
+}
+func (m *ListNumberGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListNumberGroupsRequest.Size(m)
+}
+func (m *ListNumberGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNumberGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNumberGroupsRequest proto.InternalMessageInfo
+
+type ListNumberGroupsResponse struct {
+	Groups               []*NumberGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListNumberGroupsResponse) Reset()         { *m = ListNumberGroupsResponse{} }
+func (m *ListNumberGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListNumberGroupsResponse) ProtoMessage()    {}
+func (*ListNumberGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{41}
+}
+
+func (m *ListNumberGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNumberGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListNumberGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNumberGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListNumberGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNumberGroupsResponse.Merge(m, src)
+}
+func (m *ListNumberGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListNumberGroupsResponse.Size(m)
+}
+func (m *ListNumberGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNumberGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNumberGroupsResponse proto.InternalMessageInfo
+
+func (m *ListNumberGroupsResponse) GetGroups() []*NumberGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+type NumberGroup struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Latest               int64    `protobuf:"varint,2,opt,name=latest,proto3" json:"latest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NumberGroup) Reset()         { *m = NumberGroup{} }
+func (m *NumberGroup) String() string { return proto.CompactTextString(m) }
+func (*NumberGroup) ProtoMessage()    {}
+func (*NumberGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{42}
+}
+
+func (m *NumberGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NumberGroup.Unmarshal(m, b)
+}
+func (m *NumberGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NumberGroup.Marshal(b, m, deterministic)
+}
+func (m *NumberGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NumberGroup.Merge(m, src)
+}
+func (m *NumberGroup) XXX_Size() int {
+	return xxx_messageInfo_NumberGroup.Size(m)
+}
+func (m *NumberGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_NumberGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NumberGroup proto.InternalMessageInfo
+
+func (m *NumberGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NumberGroup) GetLatest() int64 {
+	if m != nil {
+		return m.Latest
+	}
+	return 0
+}
+
+type ResetNumberGroupRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// latest is the number the group is reset to - the next job gets latest+1
+	Latest int64 `protobuf:"varint,2,opt,name=latest,proto3" json:"latest,omitempty"`
+	// force resets the group even if there are jobs with a higher number, whose names the next jobs would reuse
+	Force                bool     `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetNumberGroupRequest) Reset()         { *m = ResetNumberGroupRequest{} }
+func (m *ResetNumberGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetNumberGroupRequest) ProtoMessage()    {}
+func (*ResetNumberGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{43}
+}
+
+func (m *ResetNumberGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetNumberGroupRequest.Unmarshal(m, b)
+}
+func (m *ResetNumberGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetNumberGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *ResetNumberGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetNumberGroupRequest.Merge(m, src)
+}
+func (m *ResetNumberGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_ResetNumberGroupRequest.Size(m)
+}
+func (m *ResetNumberGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetNumberGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetNumberGroupRequest proto.InternalMessageInfo
+
+func (m *ResetNumberGroupRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResetNumberGroupRequest) GetLatest() int64 {
+	if m != nil {
+		return m.Latest
+	}
+	return 0
+}
+
+func (m *ResetNumberGroupRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type ResetNumberGroupResponse struct {
+	Group                *NumberGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ResetNumberGroupResponse) Reset()         { *m = ResetNumberGroupResponse{} }
+func (m *ResetNumberGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetNumberGroupResponse) ProtoMessage()    {}
+func (*ResetNumberGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{44}
+}
+
+func (m *ResetNumberGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetNumberGroupResponse.Unmarshal(m, b)
+}
+func (m *ResetNumberGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetNumberGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetNumberGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetNumberGroupResponse.Merge(m, src)
+}
+func (m *ResetNumberGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetNumberGroupResponse.Size(m)
+}
+func (m *ResetNumberGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetNumberGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetNumberGroupResponse proto.InternalMessageInfo
+
+func (m *ResetNumberGroupResponse) GetGroup() *NumberGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+type DeleteNumberGroupsRequest struct {
+	// names are the groups to remove. If empty, the groups no job refers to anymore are removed.
+	Names                []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteNumberGroupsRequest) Reset()         { *m = DeleteNumberGroupsRequest{} }
+func (m *DeleteNumberGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteNumberGroupsRequest) ProtoMessage()    {}
+func (*DeleteNumberGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{45}
+}
+
+func (m *DeleteNumberGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteNumberGroupsRequest.Unmarshal(m, b)
+}
+func (m *DeleteNumberGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteNumberGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteNumberGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteNumberGroupsRequest.Merge(m, src)
+}
+func (m *DeleteNumberGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteNumberGroupsRequest.Size(m)
+}
+func (m *DeleteNumberGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteNumberGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteNumberGroupsRequest proto.InternalMessageInfo
+
+func (m *DeleteNumberGroupsRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *DeleteNumberGroupsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type DeleteNumberGroupsResponse struct {
+	Names                []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteNumberGroupsResponse) Reset()         { *m = DeleteNumberGroupsResponse{} }
+func (m *DeleteNumberGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteNumberGroupsResponse) ProtoMessage()    {}
+func (*DeleteNumberGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{46}
+}
+
+func (m *DeleteNumberGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteNumberGroupsResponse.Unmarshal(m, b)
+}
+func (m *DeleteNumberGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteNumberGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteNumberGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteNumberGroupsResponse.Merge(m, src)
+}
+func (m *DeleteNumberGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteNumberGroupsResponse.Size(m)
+}
+func (m *DeleteNumberGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteNumberGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteNumberGroupsResponse proto.InternalMessageInfo
+
+func (m *DeleteNumberGroupsResponse) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	// id identifies the delivery within werft
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// delivery_id is the ID GitHub assigned to the delivery. Redeliveries from GitHub keep that ID.
+	DeliveryId string               `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	Received   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=received,proto3" json:"received,omitempty"`
+	// event is the type of event, e.g. push or pull_request
+	Event string `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	// repository is the repository the event is about, e.g. 32leaves/werft
+	Repository string `protobuf:"bytes,5,opt,name=repository,proto3" json:"repository,omitempty"`
+	// ref is the ref a push is about, e.g. refs/heads/master
+	Ref     string            `protobuf:"bytes,6,opt,name=ref,proto3" json:"ref,omitempty"`
+	Headers map[string]string `protobuf:"bytes,7,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// digest is the hex encoded SHA-256 hash of the payload
+	Digest      string `protobuf:"bytes,8,opt,name=digest,proto3" json:"digest,omitempty"`
+	PayloadSize int64  `protobuf:"varint,9,opt,name=payload_size,json=payloadSize,proto3" json:"payload_size,omitempty"`
+	// verified is true if the payload carried a valid signature. Only verified deliveries can be redelivered.
+	Verified bool `protobuf:"varint,10,opt,name=verified,proto3" json:"verified,omitempty"`
+	// jobs are the jobs the delivery started
+	Jobs []string `protobuf:"bytes,11,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// skip_reason explains why the delivery started no job
+	SkipReason string `protobuf:"bytes,12,opt,name=skip_reason,json=skipReason,proto3" json:"skip_reason,omitempty"`
+	// error is why werft failed to process the delivery
+	Error string `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	// redelivery_of is the id of the delivery this one redelivered using RedeliverWebhook
+	RedeliveryOf         string   `protobuf:"bytes,14,opt,name=redelivery_of,json=redeliveryOf,proto3" json:"redelivery_of,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WebhookDelivery) Reset()         { *m = WebhookDelivery{} }
+func (m *WebhookDelivery) String() string { return proto.CompactTextString(m) }
+func (*WebhookDelivery) ProtoMessage()    {}
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{47}
+}
+
+func (m *WebhookDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookDelivery.Unmarshal(m, b)
+}
+func (m *WebhookDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookDelivery.Marshal(b, m, deterministic)
+}
+func (m *WebhookDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookDelivery.Merge(m, src)
+}
+func (m *WebhookDelivery) XXX_Size() int {
+	return xxx_messageInfo_WebhookDelivery.Size(m)
+}
+func (m *WebhookDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookDelivery proto.InternalMessageInfo
+
+func (m *WebhookDelivery) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetDeliveryId() string {
+	if m != nil {
+		return m.DeliveryId
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetReceived() *timestamp.Timestamp {
+	if m != nil {
+		return m.Received
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetPayloadSize() int64 {
+	if m != nil {
+		return m.PayloadSize
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetVerified() bool {
+	if m != nil {
+		return m.Verified
+	}
+	return false
+}
+
+func (m *WebhookDelivery) GetJobs() []string {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetSkipReason() string {
+	if m != nil {
+		return m.SkipReason
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetRedeliveryOf() string {
+	if m != nil {
+		return m.RedeliveryOf
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesRequest struct {
+	// repository limits the list to the deliveries of one repository, e.g. 32leaves/werft
+	Repository string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// limit is the maximum number of deliveries to return. If zero, all deliveries are returned.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// without_jobs limits the list to the deliveries which started no job
+	WithoutJobs          bool     `protobuf:"varint,3,opt,name=without_jobs,json=withoutJobs,proto3" json:"without_jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesRequest) Reset()         { *m = ListWebhookDeliveriesRequest{} }
+func (m *ListWebhookDeliveriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesRequest) ProtoMessage()    {}
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{48}
+}
+
+func (m *ListWebhookDeliveriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Size(m)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesRequest proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesRequest) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListWebhookDeliveriesRequest) GetWithoutJobs() bool {
+	if m != nil {
+		return m.WithoutJobs
+	}
+	return false
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries           []*WebhookDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesResponse) Reset()         { *m = ListWebhookDeliveriesResponse{} }
+func (m *ListWebhookDeliveriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesResponse) ProtoMessage()    {}
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{49}
+}
+
+func (m *ListWebhookDeliveriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Size(m)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesResponse proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+type GetWebhookDeliveryRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWebhookDeliveryRequest) Reset()         { *m = GetWebhookDeliveryRequest{} }
+func (m *GetWebhookDeliveryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWebhookDeliveryRequest) ProtoMessage()    {}
+func (*GetWebhookDeliveryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{50}
+}
+
+func (m *GetWebhookDeliveryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWebhookDeliveryRequest.Unmarshal(m, b)
+}
+func (m *GetWebhookDeliveryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWebhookDeliveryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWebhookDeliveryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWebhookDeliveryRequest.Merge(m, src)
+}
+func (m *GetWebhookDeliveryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWebhookDeliveryRequest.Size(m)
+}
+func (m *GetWebhookDeliveryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWebhookDeliveryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWebhookDeliveryRequest proto.InternalMessageInfo
+
+func (m *GetWebhookDeliveryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetWebhookDeliveryResponse struct {
+	Delivery             *WebhookDelivery `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	Payload              []byte           `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetWebhookDeliveryResponse) Reset()         { *m = GetWebhookDeliveryResponse{} }
+func (m *GetWebhookDeliveryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWebhookDeliveryResponse) ProtoMessage()    {}
+func (*GetWebhookDeliveryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{51}
+}
+
+func (m *GetWebhookDeliveryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWebhookDeliveryResponse.Unmarshal(m, b)
+}
+func (m *GetWebhookDeliveryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWebhookDeliveryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWebhookDeliveryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWebhookDeliveryResponse.Merge(m, src)
+}
+func (m *GetWebhookDeliveryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWebhookDeliveryResponse.Size(m)
+}
+func (m *GetWebhookDeliveryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWebhookDeliveryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWebhookDeliveryResponse proto.InternalMessageInfo
+
+func (m *GetWebhookDeliveryResponse) GetDelivery() *WebhookDelivery {
+	if m != nil {
+		return m.Delivery
+	}
+	return nil
+}
+
+func (m *GetWebhookDeliveryResponse) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type RedeliverWebhookRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RedeliverWebhookRequest) Reset()         { *m = RedeliverWebhookRequest{} }
+func (m *RedeliverWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*RedeliverWebhookRequest) ProtoMessage()    {}
+func (*RedeliverWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{52}
+}
+
+func (m *RedeliverWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RedeliverWebhookRequest.Unmarshal(m, b)
+}
+func (m *RedeliverWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RedeliverWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *RedeliverWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RedeliverWebhookRequest.Merge(m, src)
+}
+func (m *RedeliverWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_RedeliverWebhookRequest.Size(m)
+}
+func (m *RedeliverWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RedeliverWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RedeliverWebhookRequest proto.InternalMessageInfo
+
+func (m *RedeliverWebhookRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RedeliverWebhookResponse struct {
+	Delivery             *WebhookDelivery `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RedeliverWebhookResponse) Reset()         { *m = RedeliverWebhookResponse{} }
+func (m *RedeliverWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*RedeliverWebhookResponse) ProtoMessage()    {}
+func (*RedeliverWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96d1ce54815e5dbd, []int{53}
+}
+
+func (m *RedeliverWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RedeliverWebhookResponse.Unmarshal(m, b)
+}
+func (m *RedeliverWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RedeliverWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *RedeliverWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RedeliverWebhookResponse.Merge(m, src)
+}
+func (m *RedeliverWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_RedeliverWebhookResponse.Size(m)
+}
+func (m *RedeliverWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RedeliverWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RedeliverWebhookResponse proto.InternalMessageInfo
+
+func (m *RedeliverWebhookResponse) GetDelivery() *WebhookDelivery {
+	if m != nil {
+		return m.Delivery
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SetDrainRequest)(nil), "v1.SetDrainRequest")
+	proto.RegisterType((*SetDrainResponse)(nil), "v1.SetDrainResponse")
+	proto.RegisterType((*SetMaintenanceRequest)(nil), "v1.SetMaintenanceRequest")
+	proto.RegisterType((*SetMaintenanceResponse)(nil), "v1.SetMaintenanceResponse")
+	proto.RegisterType((*RequeueStuckJobsRequest)(nil), "v1.RequeueStuckJobsRequest")
+	proto.RegisterType((*RequeueStuckJobsResponse)(nil), "v1.RequeueStuckJobsResponse")
+	proto.RegisterType((*PruneRequest)(nil), "v1.PruneRequest")
+	proto.RegisterType((*PruneResponse)(nil), "v1.PruneResponse")
+	proto.RegisterType((*ListTokensRequest)(nil), "v1.ListTokensRequest")
+	proto.RegisterType((*ListTokensResponse)(nil), "v1.ListTokensResponse")
+	proto.RegisterType((*TokenInfo)(nil), "v1.TokenInfo")
+	proto.RegisterType((*GetPluginStatusRequest)(nil), "v1.GetPluginStatusRequest")
+	proto.RegisterType((*GetPluginStatusResponse)(nil), "v1.GetPluginStatusResponse")
+	proto.RegisterType((*PluginStatus)(nil), "v1.PluginStatus")
+	proto.RegisterType((*DumpConfigRequest)(nil), "v1.DumpConfigRequest")
+	proto.RegisterType((*DumpConfigResponse)(nil), "v1.DumpConfigResponse")
+	proto.RegisterType((*RepositorySettings)(nil), "v1.RepositorySettings")
+	proto.RegisterType((*RegistryCredential)(nil), "v1.RegistryCredential")
+	proto.RegisterType((*SecretBinding)(nil), "v1.SecretBinding")
+	proto.RegisterType((*RepositoryPolicy)(nil), "v1.RepositoryPolicy")
+	proto.RegisterType((*ListRepositoriesRequest)(nil), "v1.ListRepositoriesRequest")
+	proto.RegisterType((*ListRepositoriesResponse)(nil), "v1.ListRepositoriesResponse")
+	proto.RegisterType((*ListEventsRequest)(nil), "v1.ListEventsRequest")
+	proto.RegisterType((*ListEventsResponse)(nil), "v1.ListEventsResponse")
+	proto.RegisterType((*TraceEvent)(nil), "v1.TraceEvent")
+	proto.RegisterType((*NotificationSnooze)(nil), "v1.NotificationSnooze")
+	proto.RegisterType((*SnoozeNotificationsRequest)(nil), "v1.SnoozeNotificationsRequest")
+	proto.RegisterType((*SnoozeNotificationsResponse)(nil), "v1.SnoozeNotificationsResponse")
+	proto.RegisterType((*ListSnoozesRequest)(nil), "v1.ListSnoozesRequest")
+	proto.RegisterType((*ListSnoozesResponse)(nil), "v1.ListSnoozesResponse")
+	proto.RegisterType((*DeleteSnoozeRequest)(nil), "v1.DeleteSnoozeRequest")
+	proto.RegisterType((*DeleteSnoozeResponse)(nil), "v1.DeleteSnoozeResponse")
+	proto.RegisterType((*CreateServiceAccountTokenRequest)(nil), "v1.CreateServiceAccountTokenRequest")
+	proto.RegisterType((*CreateServiceAccountTokenResponse)(nil), "v1.CreateServiceAccountTokenResponse")
+	proto.RegisterType((*ListServiceAccountTokensRequest)(nil), "v1.ListServiceAccountTokensRequest")
+	proto.RegisterType((*ListServiceAccountTokensResponse)(nil), "v1.ListServiceAccountTokensResponse")
+	proto.RegisterType((*RotateServiceAccountTokenRequest)(nil), "v1.RotateServiceAccountTokenRequest")
+	proto.RegisterType((*RotateServiceAccountTokenResponse)(nil), "v1.RotateServiceAccountTokenResponse")
+	proto.RegisterType((*RevokeServiceAccountTokenRequest)(nil), "v1.RevokeServiceAccountTokenRequest")
+	proto.RegisterType((*RevokeServiceAccountTokenResponse)(nil), "v1.RevokeServiceAccountTokenResponse")
+	proto.RegisterType((*ListNumberGroupsRequest)(nil), "v1.ListNumberGroupsRequest")
+	proto.RegisterType((*ListNumberGroupsResponse)(nil), "v1.ListNumberGroupsResponse")
+	proto.RegisterType((*NumberGroup)(nil), "v1.NumberGroup")
+	proto.RegisterType((*ResetNumberGroupRequest)(nil), "v1.ResetNumberGroupRequest")
+	proto.RegisterType((*ResetNumberGroupResponse)(nil), "v1.ResetNumberGroupResponse")
+	proto.RegisterType((*DeleteNumberGroupsRequest)(nil), "v1.DeleteNumberGroupsRequest")
+	proto.RegisterType((*DeleteNumberGroupsResponse)(nil), "v1.DeleteNumberGroupsResponse")
+	proto.RegisterType((*WebhookDelivery)(nil), "v1.WebhookDelivery")
+	proto.RegisterMapType((map[string]string)(nil), "v1.WebhookDelivery.HeadersEntry")
+	proto.RegisterType((*ListWebhookDeliveriesRequest)(nil), "v1.ListWebhookDeliveriesRequest")
+	proto.RegisterType((*ListWebhookDeliveriesResponse)(nil), "v1.ListWebhookDeliveriesResponse")
+	proto.RegisterType((*GetWebhookDeliveryRequest)(nil), "v1.GetWebhookDeliveryRequest")
+	proto.RegisterType((*GetWebhookDeliveryResponse)(nil), "v1.GetWebhookDeliveryResponse")
+	proto.RegisterType((*RedeliverWebhookRequest)(nil), "v1.RedeliverWebhookRequest")
+	proto.RegisterType((*RedeliverWebhookResponse)(nil), "v1.RedeliverWebhookResponse")
+}
+
+func init() { proto.RegisterFile("werft-admin.proto", fileDescriptor_96d1ce54815e5dbd) }
+
+var fileDescriptor_96d1ce54815e5dbd = []byte{
+	// 2191 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x73, 0xdb, 0xc6,
+	0x11, 0x2f, 0x29, 0x89, 0x12, 0x97, 0xd4, 0x87, 0x4f, 0x8a, 0x04, 0xc1, 0xb2, 0x2d, 0x9f, 0xa3,
+	0xda, 0x6d, 0x1a, 0x3a, 0x91, 0xdb, 0x7c, 0xb8, 0xc9, 0x4c, 0x5d, 0xdb, 0x71, 0xed, 0xc6, 0x89,
+	0x07, 0x52, 0x9b, 0xce, 0x74, 0x5a, 0x0e, 0x44, 0x2c, 0xa9, 0xb3, 0x48, 0x1c, 0x72, 0x77, 0x94,
+	0x42, 0xbf, 0xf5, 0xb1, 0x4f, 0xed, 0x4b, 0x1f, 0x3a, 0xd3, 0x4e, 0xa7, 0xff, 0x67, 0x1f, 0x3a,
+	0xf7, 0x01, 0x10, 0x04, 0x01, 0x29, 0xc9, 0xb4, 0x6f, 0xd8, 0xdd, 0x1f, 0xf6, 0xf6, 0xe3, 0xf6,
+	0x76, 0xef, 0xe0, 0xda, 0x05, 0x8a, 0xbe, 0x7a, 0x37, 0x8c, 0x46, 0x2c, 0xee, 0x24, 0x82, 0x2b,
+	0x4e, 0xea, 0xe7, 0xef, 0xfb, 0xb7, 0x06, 0x9c, 0x0f, 0x86, 0x78, 0xdf, 0x70, 0x4e, 0xc6, 0xfd,
+	0xfb, 0x8a, 0x8d, 0x50, 0xaa, 0x70, 0x94, 0x58, 0x90, 0x7f, 0xb3, 0x08, 0x88, 0xc6, 0x22, 0x54,
+	0x8c, 0x3b, 0x25, 0x7e, 0xcb, 0xe8, 0xb5, 0x04, 0xbd, 0x0b, 0xeb, 0x47, 0xa8, 0x9e, 0x88, 0x90,
+	0xc5, 0x01, 0x7e, 0x3d, 0x46, 0xa9, 0xc8, 0x16, 0x2c, 0x45, 0x9a, 0xf6, 0x6a, 0xfb, 0xb5, 0x7b,
+	0x2b, 0x81, 0x25, 0x68, 0x07, 0x36, 0xa6, 0x40, 0x99, 0xf0, 0x58, 0x22, 0xf1, 0x61, 0xc5, 0x08,
+	0x59, 0x3c, 0x70, 0xe0, 0x8c, 0xa6, 0x7f, 0xab, 0xc1, 0x5b, 0x47, 0xa8, 0x5e, 0x86, 0x2c, 0x56,
+	0x18, 0x87, 0x71, 0x0f, 0x53, 0xfd, 0x1e, 0x2c, 0x63, 0x1c, 0x9e, 0x0c, 0x31, 0x72, 0x3f, 0xa5,
+	0xa4, 0x96, 0x8c, 0x50, 0xca, 0x70, 0x80, 0x5e, 0x7d, 0xbf, 0x76, 0xaf, 0x19, 0xa4, 0x24, 0x39,
+	0x80, 0xb5, 0xaf, 0xc7, 0x38, 0xc6, 0xae, 0x12, 0x6c, 0x30, 0x40, 0x21, 0xbd, 0x05, 0xf3, 0xeb,
+	0xaa, 0xe1, 0x1e, 0x3b, 0x26, 0xb9, 0x0d, 0x6d, 0xa9, 0x78, 0xd2, 0x15, 0xe3, 0xd8, 0x18, 0xb5,
+	0x68, 0x40, 0x2d, 0xcd, 0x0b, 0x2c, 0x8b, 0xfe, 0xa5, 0x06, 0xdb, 0x45, 0xbb, 0x9c, 0x3b, 0x77,
+	0x61, 0x71, 0xc4, 0x23, 0x34, 0x56, 0xb5, 0x0e, 0x37, 0x3b, 0xe7, 0xef, 0x77, 0x72, 0xb0, 0x97,
+	0x3c, 0xc2, 0xc0, 0x00, 0xb4, 0x9d, 0x5a, 0x65, 0x82, 0x91, 0x57, 0xdf, 0x5f, 0xd0, 0x76, 0x3a,
+	0x52, 0x4b, 0xd2, 0xb5, 0x17, 0xac, 0xc4, 0x91, 0xf6, 0x9f, 0x50, 0x28, 0x8c, 0xbc, 0xc5, 0xf4,
+	0x1f, 0x43, 0xd2, 0x21, 0xec, 0x98, 0xd0, 0x8c, 0xf1, 0x48, 0x8d, 0x7b, 0x67, 0x2f, 0xf8, 0x89,
+	0x4c, 0x43, 0xf5, 0x11, 0x00, 0x1f, 0x46, 0x28, 0xba, 0xea, 0x34, 0x8c, 0x9d, 0x5d, 0xbb, 0x1d,
+	0x9b, 0xdf, 0x4e, 0x9a, 0xdf, 0xce, 0x13, 0x97, 0xdf, 0xa0, 0x69, 0xc0, 0xc7, 0xa7, 0x61, 0x4c,
+	0x76, 0x60, 0x39, 0x12, 0x13, 0x1d, 0x08, 0x13, 0xca, 0x95, 0xa0, 0x11, 0x89, 0x49, 0x30, 0x8e,
+	0xe9, 0x1f, 0xc0, 0x9b, 0x5f, 0xcd, 0x05, 0xe0, 0x0e, 0x2c, 0x49, 0xcd, 0xf4, 0x6a, 0xfb, 0x0b,
+	0xf7, 0x5a, 0x87, 0xab, 0x3a, 0x02, 0x2f, 0xf8, 0xc9, 0x91, 0x0a, 0xd5, 0x58, 0x06, 0x56, 0x46,
+	0xf6, 0xa0, 0x29, 0x30, 0x75, 0xc5, 0xba, 0x3f, 0x65, 0xd0, 0x10, 0xda, 0xaf, 0xc4, 0x38, 0xc6,
+	0xff, 0xa3, 0x07, 0x9f, 0xc2, 0xaa, 0x5b, 0xc2, 0x99, 0xbd, 0x05, 0x4b, 0x71, 0x38, 0x42, 0x69,
+	0xcc, 0x6e, 0x06, 0x96, 0x20, 0xdb, 0xd0, 0x48, 0x58, 0x1c, 0x67, 0x46, 0x3a, 0x8a, 0x6e, 0xc2,
+	0xb5, 0xcf, 0x99, 0x54, 0xc7, 0xfc, 0x0c, 0xe3, 0x34, 0xd0, 0xf4, 0xe7, 0x40, 0xf2, 0x4c, 0xa7,
+	0xf8, 0x00, 0x1a, 0xca, 0x70, 0xf2, 0x01, 0x31, 0x98, 0xe7, 0x71, 0x9f, 0x07, 0x4e, 0x48, 0x3f,
+	0x84, 0x66, 0xc6, 0x24, 0x04, 0x16, 0xf5, 0xfa, 0xc6, 0xd5, 0x66, 0x60, 0xbe, 0xb5, 0x29, 0xb2,
+	0xc7, 0x13, 0x94, 0xa9, 0x29, 0x96, 0xa2, 0x1e, 0x6c, 0x3f, 0x43, 0xf5, 0x6a, 0x38, 0x1e, 0xb0,
+	0xd8, 0x05, 0xd9, 0xd9, 0xf3, 0x14, 0x76, 0xe6, 0x24, 0xce, 0xa8, 0x1f, 0xc3, 0x72, 0x62, 0xf8,
+	0xa9, 0x55, 0x1b, 0xda, 0xaa, 0x19, 0x68, 0x0a, 0xa0, 0xff, 0xa8, 0x41, 0x3b, 0x2f, 0x29, 0xb5,
+	0x8e, 0xc0, 0xa2, 0x9a, 0x24, 0x69, 0xc9, 0x99, 0xef, 0xd9, 0x7d, 0x6c, 0x6a, 0xd4, 0x91, 0xe4,
+	0xa7, 0xf9, 0x7d, 0xac, 0xb3, 0xe9, 0xcf, 0x65, 0xf3, 0x38, 0x3d, 0x90, 0xb2, 0x3d, 0xae, 0x53,
+	0x84, 0x42, 0x70, 0xe1, 0x2d, 0x99, 0x45, 0x2c, 0xa1, 0x53, 0xf1, 0x64, 0x3c, 0x4a, 0x1e, 0xf3,
+	0xb8, 0xcf, 0x06, 0xa9, 0xeb, 0xf7, 0x80, 0xe4, 0x99, 0xce, 0x6b, 0x02, 0x8b, 0x93, 0x70, 0x34,
+	0x4c, 0x0d, 0xd7, 0xdf, 0xf4, 0xaf, 0x75, 0x20, 0x01, 0x26, 0x5c, 0x32, 0xc5, 0xc5, 0xe4, 0x08,
+	0x95, 0x62, 0xf1, 0x40, 0xea, 0xb5, 0xf8, 0x45, 0x8c, 0xc2, 0x61, 0x2d, 0xa1, 0x15, 0x08, 0x4c,
+	0x78, 0xea, 0xa5, 0xfe, 0xd6, 0xa7, 0xca, 0x05, 0x9e, 0x9c, 0x72, 0x7e, 0xd6, 0x95, 0xd8, 0x13,
+	0xa8, 0x8c, 0xb3, 0xcd, 0x60, 0xd5, 0x71, 0x8f, 0x0c, 0x93, 0xbc, 0x03, 0xcb, 0x56, 0x2c, 0x4d,
+	0xe9, 0xb6, 0x0e, 0xaf, 0xe9, 0x88, 0x5b, 0xe1, 0x2f, 0x59, 0x1c, 0xb1, 0x78, 0x10, 0xa4, 0x08,
+	0xf2, 0x13, 0x68, 0x24, 0x7c, 0xc8, 0x7a, 0x13, 0xe3, 0x6a, 0xeb, 0x70, 0x4b, 0x63, 0xa7, 0x56,
+	0xbe, 0x32, 0xb2, 0xc0, 0x61, 0xcc, 0xce, 0xe0, 0x63, 0xd1, 0x43, 0xaf, 0x61, 0x56, 0x76, 0x14,
+	0xf9, 0x00, 0x40, 0xe0, 0x80, 0x49, 0x25, 0x18, 0x4a, 0x6f, 0xd9, 0xac, 0xba, 0x6d, 0x35, 0x19,
+	0xee, 0xe4, 0xb1, 0xc0, 0x08, 0x63, 0xc5, 0xc2, 0x61, 0x90, 0x43, 0xd2, 0x53, 0x1d, 0x91, 0x22,
+	0x42, 0x9f, 0xd3, 0x0e, 0x33, 0x71, 0x41, 0xc9, 0x68, 0x2d, 0x1b, 0x4b, 0x14, 0x66, 0x57, 0xd8,
+	0xd8, 0x64, 0xb4, 0x96, 0x25, 0xa1, 0x94, 0x17, 0x5c, 0x44, 0x2e, 0x32, 0x19, 0x4d, 0x3f, 0x83,
+	0xd5, 0x99, 0x08, 0x18, 0x57, 0x6c, 0x10, 0x6b, 0xce, 0x15, 0x43, 0x91, 0x1b, 0x00, 0x23, 0x3e,
+	0x8e, 0x55, 0x37, 0x09, 0xd5, 0xa9, 0x5b, 0xa2, 0x69, 0x38, 0xaf, 0x42, 0x75, 0x4a, 0x13, 0xd8,
+	0x28, 0x46, 0x47, 0x1f, 0xe3, 0xe1, 0x70, 0xc8, 0x2f, 0x30, 0xea, 0x0a, 0xec, 0xa7, 0x75, 0xdd,
+	0x72, 0xbc, 0x00, 0xfb, 0x92, 0x7c, 0x0c, 0x1b, 0x29, 0x24, 0x6b, 0x09, 0xba, 0xb8, 0xd6, 0x0e,
+	0xd7, 0xdc, 0xa9, 0xe5, 0x9a, 0x42, 0xb0, 0xee, 0x70, 0x8e, 0x96, 0x74, 0x17, 0x76, 0x74, 0xad,
+	0x67, 0xab, 0x32, 0xcc, 0xca, 0xee, 0xb7, 0xe0, 0xcd, 0x8b, 0xdc, 0x0e, 0x7c, 0x08, 0x6d, 0x91,
+	0xe3, 0x7b, 0xb5, 0x7c, 0x52, 0x8a, 0x9b, 0x30, 0x98, 0xc1, 0xd2, 0x7f, 0xd7, 0xec, 0xa1, 0xf3,
+	0xf4, 0x1c, 0x63, 0x95, 0x9d, 0xee, 0x65, 0xc5, 0xf8, 0x1e, 0x2c, 0x49, 0x16, 0xf7, 0x6c, 0x2e,
+	0x2e, 0x2f, 0x2e, 0x0b, 0xd4, 0x7f, 0x8c, 0x63, 0xc5, 0x86, 0xde, 0xc2, 0xd5, 0x7f, 0x18, 0xa0,
+	0x2e, 0x90, 0x21, 0x1b, 0x31, 0x65, 0x0a, 0x78, 0x29, 0xb0, 0x04, 0xfd, 0x04, 0x48, 0xde, 0x44,
+	0xe7, 0xf5, 0x0f, 0xa1, 0x81, 0x86, 0xe3, 0xfc, 0x35, 0xd1, 0x3d, 0x16, 0x61, 0x0f, 0x0d, 0x30,
+	0x70, 0x52, 0xfa, 0xe7, 0x1a, 0xc0, 0x94, 0x4d, 0x3a, 0xb0, 0xa8, 0x98, 0x73, 0xed, 0x72, 0x9b,
+	0x0c, 0x2e, 0x0b, 0x45, 0x3d, 0x17, 0x8a, 0x03, 0x68, 0x48, 0x73, 0x6a, 0x39, 0xcf, 0x0a, 0xed,
+	0xc8, 0x09, 0xc9, 0x06, 0x2c, 0x24, 0xdc, 0x1e, 0x46, 0xed, 0x40, 0x7f, 0xea, 0x53, 0x8f, 0x7c,
+	0xc1, 0x15, 0xeb, 0xb3, 0x9e, 0xe9, 0x2a, 0x47, 0x31, 0xe7, 0x6f, 0x90, 0xac, 0x41, 0x9d, 0x45,
+	0x2e, 0xd8, 0x75, 0x16, 0xe9, 0x4a, 0xed, 0xb3, 0xa1, 0x42, 0x61, 0x36, 0x8e, 0xab, 0xd4, 0xcf,
+	0x0c, 0xe7, 0xe9, 0x37, 0x89, 0x40, 0x29, 0x75, 0x47, 0x72, 0x98, 0xef, 0x11, 0xe6, 0x6d, 0x68,
+	0x08, 0x0c, 0x25, 0x8f, 0x8d, 0x6d, 0xcd, 0xc0, 0x51, 0xf4, 0xef, 0x35, 0xf0, 0xad, 0x49, 0x79,
+	0x23, 0xb3, 0x5d, 0x31, 0x35, 0xab, 0xf6, 0x2d, 0xcc, 0xfa, 0x19, 0xac, 0xa4, 0xe3, 0x9d, 0x57,
+	0xbf, 0xaa, 0xbb, 0x66, 0xd0, 0x9c, 0x6d, 0x0b, 0x33, 0xb6, 0xbd, 0x84, 0xeb, 0xa5, 0xa6, 0xb9,
+	0xdd, 0xd0, 0x81, 0x86, 0x34, 0x62, 0x97, 0x58, 0xb3, 0xfb, 0xe7, 0x43, 0x1d, 0x38, 0x14, 0xdd,
+	0xb2, 0x7b, 0xca, 0x72, 0xb3, 0x2a, 0x7b, 0x06, 0x9b, 0x33, 0x5c, 0xa7, 0xfc, 0x3d, 0x58, 0xb6,
+	0xbf, 0xcd, 0xd4, 0x56, 0x89, 0xf6, 0x14, 0x46, 0x0f, 0x60, 0xf3, 0x09, 0x0e, 0x51, 0xa1, 0x13,
+	0xb8, 0x08, 0x16, 0x12, 0x4d, 0xb7, 0x61, 0x6b, 0x16, 0x66, 0x17, 0xa4, 0xff, 0xac, 0xc1, 0xfe,
+	0x63, 0x81, 0xa1, 0xc2, 0x23, 0x14, 0xe7, 0xac, 0x87, 0x8f, 0x7a, 0x3d, 0x7d, 0x2e, 0x99, 0x66,
+	0x9e, 0x2a, 0xbb, 0x0b, 0xeb, 0xd2, 0x4a, 0xbb, 0xa1, 0x15, 0x3b, 0xcd, 0x6b, 0x72, 0xe6, 0xa7,
+	0xaa, 0x26, 0xaf, 0x27, 0x20, 0xfc, 0x26, 0x61, 0x02, 0x65, 0x97, 0xc5, 0xde, 0xc2, 0x55, 0x39,
+	0x6a, 0x3a, 0xf0, 0xf3, 0x98, 0xbe, 0x86, 0xdb, 0x97, 0x98, 0xe7, 0xa2, 0xf6, 0x2e, 0x2c, 0x99,
+	0x31, 0xc4, 0x65, 0x64, 0xc7, 0xb6, 0xa6, 0x79, 0xbc, 0x45, 0xe5, 0x4e, 0xe9, 0x7a, 0xfe, 0x94,
+	0xa6, 0x2f, 0xe0, 0x96, 0xc9, 0xc9, 0xfc, 0x9f, 0xf2, 0xbb, 0x46, 0x82, 0x1e, 0xc1, 0x7e, 0xb5,
+	0x2e, 0x67, 0xf6, 0xfd, 0xc2, 0x68, 0x55, 0x69, 0xb7, 0x83, 0xd1, 0x04, 0xf6, 0x03, 0xae, 0x2e,
+	0xcf, 0x55, 0xb1, 0xc2, 0x3f, 0x81, 0xf6, 0x40, 0x9f, 0x49, 0xdd, 0x04, 0x05, 0xe3, 0xd1, 0xd5,
+	0x05, 0xd2, 0x32, 0xf0, 0x57, 0x06, 0x4d, 0xff, 0x55, 0x83, 0xdb, 0x97, 0x2c, 0xf9, 0x3f, 0x8d,
+	0x3f, 0x79, 0x00, 0x2b, 0x89, 0xc0, 0x73, 0xc6, 0xb3, 0xe3, 0xae, 0x52, 0x53, 0x06, 0xa4, 0x87,
+	0xb0, 0x1f, 0xe0, 0x39, 0x3f, 0xfb, 0x0e, 0x31, 0xa1, 0x77, 0xe0, 0xf6, 0x25, 0xff, 0xb8, 0xca,
+	0x70, 0x2d, 0xf2, 0x8b, 0xf1, 0xe8, 0x04, 0xc5, 0x33, 0xc1, 0xc7, 0x49, 0x56, 0xbc, 0x8f, 0xc1,
+	0x9b, 0x17, 0x65, 0x17, 0xa8, 0xc6, 0xc0, 0x70, 0x5c, 0x52, 0xd7, 0x4d, 0x01, 0x4f, 0x91, 0x81,
+	0x13, 0xd3, 0x8f, 0xa1, 0x95, 0x63, 0x57, 0xcd, 0xcc, 0xc3, 0x50, 0xa1, 0xb4, 0x81, 0x5a, 0x08,
+	0x1c, 0x45, 0x7f, 0xaf, 0x6f, 0x4b, 0x12, 0xf3, 0x06, 0x5c, 0xd6, 0x4f, 0x2b, 0xd4, 0xe8, 0x1e,
+	0xd8, 0xe7, 0x7a, 0xee, 0xb2, 0xe3, 0xad, 0x25, 0xe8, 0x23, 0xf0, 0xe6, 0x95, 0x67, 0x97, 0x81,
+	0x25, 0x63, 0xbd, 0x4b, 0xf4, 0x9c, 0x6f, 0x56, 0x4a, 0x5f, 0xc0, 0xae, 0x3d, 0x6c, 0x4a, 0x82,
+	0x57, 0x71, 0x53, 0xa9, 0xbc, 0xe9, 0x1c, 0x82, 0x5f, 0xa6, 0xeb, 0xb2, 0x6b, 0x0f, 0xfd, 0xd3,
+	0x22, 0xac, 0x7f, 0x65, 0xc7, 0xd7, 0x27, 0x38, 0x64, 0xe7, 0x28, 0x26, 0x73, 0x75, 0x71, 0x0b,
+	0x5a, 0x91, 0x93, 0x75, 0x59, 0xe4, 0x76, 0x22, 0xa4, 0xac, 0xe7, 0x11, 0xf9, 0x40, 0x0f, 0x8c,
+	0x3d, 0x64, 0xe7, 0x18, 0x7d, 0x8b, 0x7e, 0x97, 0x61, 0xb5, 0x49, 0x66, 0x1e, 0x70, 0x1d, 0xcf,
+	0x12, 0xe4, 0x26, 0x40, 0x36, 0x0d, 0x4d, 0xdc, 0x0d, 0x20, 0xc7, 0xd1, 0x1d, 0x5c, 0x60, 0xdf,
+	0x4d, 0xc0, 0xfa, 0x93, 0x3c, 0x84, 0xe5, 0x53, 0x0c, 0x23, 0x14, 0xe9, 0xec, 0xbb, 0xaf, 0xa3,
+	0x5d, 0x70, 0xab, 0xf3, 0x2b, 0x0b, 0x79, 0x1a, 0x2b, 0x31, 0x09, 0xd2, 0x1f, 0x74, 0xc6, 0x23,
+	0x36, 0xd0, 0x19, 0x5f, 0xb1, 0x15, 0x66, 0x29, 0x3d, 0x54, 0x26, 0xe1, 0x64, 0xc8, 0xc3, 0xa8,
+	0x2b, 0xd9, 0x1b, 0xf4, 0x9a, 0x66, 0x3f, 0xb4, 0x1c, 0xef, 0x88, 0xbd, 0x31, 0xf3, 0xee, 0x39,
+	0x0a, 0xd6, 0x67, 0x18, 0x79, 0x60, 0xdf, 0x33, 0x52, 0x5a, 0x6f, 0xae, 0xd7, 0xfc, 0x44, 0x7a,
+	0x2d, 0x13, 0x6c, 0xf3, 0xad, 0xe3, 0x28, 0xcf, 0x58, 0xd2, 0x75, 0xad, 0xb4, 0x6d, 0x3d, 0xd3,
+	0xac, 0xc0, 0x70, 0xa6, 0xd7, 0x9e, 0xd5, 0xdc, 0xb5, 0x87, 0xdc, 0x81, 0x55, 0x81, 0x59, 0x02,
+	0x78, 0xdf, 0x5b, 0x33, 0xd2, 0xf6, 0x94, 0xf9, 0x65, 0xdf, 0x7f, 0x08, 0xed, 0xbc, 0x7f, 0x3a,
+	0x48, 0x67, 0x98, 0x8e, 0xef, 0xfa, 0x53, 0x2b, 0x3f, 0x0f, 0x87, 0xe3, 0x74, 0x68, 0xb2, 0xc4,
+	0xc3, 0xfa, 0x47, 0x35, 0x7a, 0x01, 0x7b, 0xba, 0x46, 0x67, 0xe3, 0x35, 0x1d, 0x73, 0x0b, 0x09,
+	0xa9, 0xcd, 0x25, 0x24, 0x1b, 0x10, 0xeb, 0xb9, 0x01, 0x51, 0x07, 0xf0, 0x82, 0xa9, 0x53, 0x3e,
+	0x56, 0x5d, 0x13, 0x09, 0x5b, 0x39, 0x2d, 0xc7, 0xd3, 0x0f, 0x09, 0xf4, 0x18, 0x6e, 0x54, 0x2c,
+	0xec, 0xf6, 0xec, 0x03, 0x48, 0xb7, 0xd9, 0x74, 0x84, 0xde, 0x2c, 0xc9, 0x6d, 0x90, 0x83, 0xd1,
+	0x77, 0x60, 0xf7, 0x19, 0xaa, 0x22, 0xa2, 0xe2, 0x7c, 0x1b, 0x80, 0x5f, 0x06, 0xce, 0xda, 0xce,
+	0x4a, 0x1a, 0xe3, 0xfc, 0x33, 0x4f, 0x11, 0x9e, 0x81, 0xf4, 0x45, 0xd8, 0xed, 0x10, 0x13, 0x8c,
+	0x76, 0x90, 0x92, 0xf4, 0x47, 0xfa, 0x20, 0x72, 0x38, 0xf7, 0x7f, 0x95, 0x4d, 0xbf, 0x06, 0x6f,
+	0x1e, 0xfa, 0x3d, 0x2d, 0x3a, 0xfc, 0xcf, 0x2a, 0xc0, 0x57, 0xfa, 0x05, 0xef, 0x91, 0x7e, 0x18,
+	0x24, 0x1f, 0xc2, 0x4a, 0xfa, 0x2e, 0x47, 0x36, 0x6d, 0xcb, 0x98, 0x79, 0xce, 0xf3, 0xb7, 0x66,
+	0x99, 0xee, 0x84, 0xff, 0x01, 0x79, 0x0e, 0x6b, 0xb3, 0xef, 0x60, 0x64, 0xd7, 0x21, 0xe7, 0xdf,
+	0xec, 0x7c, 0xbf, 0x4c, 0x94, 0xa9, 0xfa, 0x12, 0x36, 0x8a, 0x6f, 0x4a, 0xe4, 0xba, 0xbd, 0x18,
+	0x95, 0xbe, 0x6b, 0xf9, 0x7b, 0xe5, 0xc2, 0x4c, 0x61, 0x07, 0x96, 0xcc, 0x13, 0x0f, 0xb1, 0x6f,
+	0x1b, 0xb9, 0x07, 0x25, 0xff, 0x5a, 0x8e, 0x93, 0xe1, 0x3f, 0x05, 0x98, 0x3e, 0xdf, 0x90, 0xb7,
+	0x34, 0x64, 0xee, 0x8d, 0xc7, 0xdf, 0x2e, 0xb2, 0xb3, 0xdf, 0x3f, 0x87, 0xf5, 0xc2, 0x6b, 0x0b,
+	0x31, 0x0e, 0x97, 0x3f, 0xce, 0xf8, 0xd7, 0x4b, 0x65, 0x79, 0x63, 0xa6, 0x0f, 0x18, 0xd6, 0x98,
+	0xb9, 0x57, 0x0e, 0x7f, 0xbb, 0xc8, 0xce, 0x07, 0xb3, 0x78, 0x07, 0xb5, 0xc1, 0xac, 0xb8, 0xb4,
+	0xfa, 0x7b, 0xe5, 0xc2, 0x62, 0x70, 0xec, 0xc5, 0x6e, 0x1a, 0x9c, 0x99, 0xbb, 0xa8, 0xbf, 0x5d,
+	0x64, 0x67, 0xbf, 0xff, 0x0e, 0x36, 0x4b, 0xae, 0x04, 0xe4, 0xa6, 0xd9, 0x11, 0x95, 0xd7, 0x18,
+	0xff, 0x56, 0xa5, 0x3c, 0xd3, 0xfc, 0x0b, 0x68, 0xe5, 0xee, 0x01, 0x24, 0x33, 0x61, 0xf6, 0xba,
+	0xe0, 0xef, 0xcc, 0xf1, 0x33, 0x0d, 0x8f, 0xa1, 0x9d, 0x9f, 0xec, 0x89, 0x81, 0x96, 0x5c, 0x09,
+	0x7c, 0x6f, 0x5e, 0x90, 0x29, 0x79, 0x0d, 0xbb, 0x95, 0x63, 0x36, 0x79, 0x5b, 0xff, 0x78, 0xd5,
+	0x25, 0xc1, 0x3f, 0xb8, 0x02, 0x95, 0xad, 0x35, 0xb0, 0xd3, 0x53, 0x09, 0x48, 0x92, 0x3b, 0x99,
+	0x9f, 0xd5, 0x43, 0xb8, 0xff, 0xf6, 0xe5, 0xa0, 0xbc, 0x53, 0x95, 0xb3, 0xab, 0x75, 0xea, 0xaa,
+	0x69, 0xda, 0x3f, 0xb8, 0x02, 0x35, 0xb3, 0x56, 0xd5, 0x48, 0xe9, 0xd6, 0xba, 0x62, 0x4a, 0xf5,
+	0x0f, 0xae, 0x40, 0x15, 0xab, 0x23, 0x3f, 0x10, 0x4d, 0xab, 0xa3, 0x64, 0xe4, 0xf2, 0xf7, 0xca,
+	0x85, 0xb3, 0x67, 0xd7, 0xec, 0xc8, 0x97, 0x9e, 0x5d, 0xa5, 0x53, 0xa6, 0xbf, 0x57, 0x2e, 0xcc,
+	0x14, 0xfe, 0x06, 0xc8, 0xfc, 0xd0, 0x46, 0x6e, 0x4c, 0x37, 0x60, 0x99, 0x95, 0x37, 0xab, 0xc4,
+	0x99, 0xda, 0x3f, 0xc2, 0x5b, 0xa5, 0xad, 0x95, 0xec, 0xa7, 0x0e, 0x56, 0xb5, 0x7b, 0xff, 0xf6,
+	0x25, 0x88, 0xbc, 0xd9, 0xf3, 0x7d, 0xd3, 0x9a, 0x5d, 0xd9, 0x7c, 0xfd, 0x9b, 0x55, 0xe2, 0xd9,
+	0xf0, 0xce, 0xb6, 0xbe, 0x34, 0xbc, 0xa5, 0xbd, 0xd3, 0xdf, 0x2b, 0x17, 0xa6, 0x0a, 0x4f, 0x1a,
+	0x66, 0x00, 0x7d, 0xf0, 0xdf, 0x01, 0x00, 0x76, 0x8b, 0xd6, 0xf2, 0x1e, 0x1b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// WerftAdminClient is the client API for WerftAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WerftAdminClient interface {
+	// SetDrain makes werft stop (or resume) accepting new jobs. Running jobs are not affected.
+	// This is a shorthand for SetMaintenance without message and queueing.
+	SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error)
+	// SetMaintenance starts or ends maintenance, e.g. for a cluster upgrade. During maintenance werft does not accept
+	// new jobs. The maintenance mode survives restarts of werft.
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error)
+	// RequeueStuckJobs marks jobs which are not done but have no pod anymore as failed and starts them again.
+	RequeueStuckJobs(ctx context.Context, in *RequeueStuckJobsRequest, opts ...grpc.CallOption) (*RequeueStuckJobsResponse, error)
+	// Prune removes finished jobs and their logs.
+	Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error)
+	// ListTokens lists the configured API tokens without revealing their secret.
+	ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error)
+	// GetPluginStatus returns the status of all configured plugins.
+	GetPluginStatus(ctx context.Context, in *GetPluginStatusRequest, opts ...grpc.CallOption) (*GetPluginStatusResponse, error)
+	// DumpConfig returns the server configuration with all secrets redacted.
+	DumpConfig(ctx context.Context, in *DumpConfigRequest, opts ...grpc.CallOption) (*DumpConfigResponse, error)
+	// ListRepositories lists the settings of all registered repositories without revealing their webhook secret.
+	ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error)
+	// ListEvents queries the event trace, i.e. the status updates werft saw for jobs, oldest first.
+	// This requires the event trace to be stored, see storage.eventTrace in the server config.
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// SnoozeNotifications silences the notifications about jobs matching a filter for a while.
+	// Snoozes do not survive restarts of werft.
+	SnoozeNotifications(ctx context.Context, in *SnoozeNotificationsRequest, opts ...grpc.CallOption) (*SnoozeNotificationsResponse, error)
+	// ListSnoozes lists the notification snoozes which have not expired yet.
+	ListSnoozes(ctx context.Context, in *ListSnoozesRequest, opts ...grpc.CallOption) (*ListSnoozesResponse, error)
+	// DeleteSnooze ends a notification snooze before it expires.
+	DeleteSnooze(ctx context.Context, in *DeleteSnoozeRequest, opts ...grpc.CallOption) (*DeleteSnoozeResponse, error)
+	// CreateServiceAccountToken creates a long-lived token for a service account, e.g. a deployment bot.
+	// The response is the only time the token is revealed.
+	CreateServiceAccountToken(ctx context.Context, in *CreateServiceAccountTokenRequest, opts ...grpc.CallOption) (*CreateServiceAccountTokenResponse, error)
+	// ListServiceAccountTokens lists the tokens of service accounts without revealing them.
+	ListServiceAccountTokens(ctx context.Context, in *ListServiceAccountTokensRequest, opts ...grpc.CallOption) (*ListServiceAccountTokensResponse, error)
+	// RotateServiceAccountToken replaces a token with a new one of the same service account and scopes.
+	// The old token keeps working for a grace period, s.t. it can be replaced wherever it's used.
+	RotateServiceAccountToken(ctx context.Context, in *RotateServiceAccountTokenRequest, opts ...grpc.CallOption) (*RotateServiceAccountTokenResponse, error)
+	// RevokeServiceAccountToken makes a token stop working immediately.
+	RevokeServiceAccountToken(ctx context.Context, in *RevokeServiceAccountTokenRequest, opts ...grpc.CallOption) (*RevokeServiceAccountTokenResponse, error)
+	// ListNumberGroups lists the counters job names and build numbers are drawn from, and their latest number.
+	ListNumberGroups(ctx context.Context, in *ListNumberGroupsRequest, opts ...grpc.CallOption) (*ListNumberGroupsResponse, error)
+	// ResetNumberGroup sets the latest number of a group, s.t. the next job continues from there.
+	ResetNumberGroup(ctx context.Context, in *ResetNumberGroupRequest, opts ...grpc.CallOption) (*ResetNumberGroupResponse, error)
+	// DeleteNumberGroups removes number groups. Without names it removes the groups no job refers to anymore,
+	// e.g. those of removed repositories or pruned branches.
+	DeleteNumberGroups(ctx context.Context, in *DeleteNumberGroupsRequest, opts ...grpc.CallOption) (*DeleteNumberGroupsResponse, error)
+	// ListWebhookDeliveries lists the webhooks werft received within the retention window, newest first,
+	// and what became of them - e.g. to find out why a push did not start a job.
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// GetWebhookDelivery returns a webhook delivery including its payload.
+	GetWebhookDelivery(ctx context.Context, in *GetWebhookDeliveryRequest, opts ...grpc.CallOption) (*GetWebhookDeliveryResponse, error)
+	// RedeliverWebhook processes the payload of a webhook delivery again, as if it was just received.
+	// The redelivery is recorded as a delivery of its own.
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error)
+}
+
+type werftAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWerftAdminClient(cc *grpc.ClientConn) WerftAdminClient {
+	return &werftAdminClient{cc}
+}
+
+func (c *werftAdminClient) SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error) {
+	out := new(SetDrainResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/SetDrain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error) {
+	out := new(SetMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/SetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) RequeueStuckJobs(ctx context.Context, in *RequeueStuckJobsRequest, opts ...grpc.CallOption) (*RequeueStuckJobsResponse, error) {
+	out := new(RequeueStuckJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/RequeueStuckJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error) {
+	out := new(PruneResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/Prune", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error) {
+	out := new(ListTokensResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) GetPluginStatus(ctx context.Context, in *GetPluginStatusRequest, opts ...grpc.CallOption) (*GetPluginStatusResponse, error) {
+	out := new(GetPluginStatusResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/GetPluginStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) DumpConfig(ctx context.Context, in *DumpConfigRequest, opts ...grpc.CallOption) (*DumpConfigResponse, error) {
+	out := new(DumpConfigResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/DumpConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error) {
+	out := new(ListRepositoriesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListRepositories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) SnoozeNotifications(ctx context.Context, in *SnoozeNotificationsRequest, opts ...grpc.CallOption) (*SnoozeNotificationsResponse, error) {
+	out := new(SnoozeNotificationsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/SnoozeNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListSnoozes(ctx context.Context, in *ListSnoozesRequest, opts ...grpc.CallOption) (*ListSnoozesResponse, error) {
+	out := new(ListSnoozesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListSnoozes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) DeleteSnooze(ctx context.Context, in *DeleteSnoozeRequest, opts ...grpc.CallOption) (*DeleteSnoozeResponse, error) {
+	out := new(DeleteSnoozeResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/DeleteSnooze", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) CreateServiceAccountToken(ctx context.Context, in *CreateServiceAccountTokenRequest, opts ...grpc.CallOption) (*CreateServiceAccountTokenResponse, error) {
+	out := new(CreateServiceAccountTokenResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/CreateServiceAccountToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListServiceAccountTokens(ctx context.Context, in *ListServiceAccountTokensRequest, opts ...grpc.CallOption) (*ListServiceAccountTokensResponse, error) {
+	out := new(ListServiceAccountTokensResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListServiceAccountTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) RotateServiceAccountToken(ctx context.Context, in *RotateServiceAccountTokenRequest, opts ...grpc.CallOption) (*RotateServiceAccountTokenResponse, error) {
+	out := new(RotateServiceAccountTokenResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/RotateServiceAccountToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) RevokeServiceAccountToken(ctx context.Context, in *RevokeServiceAccountTokenRequest, opts ...grpc.CallOption) (*RevokeServiceAccountTokenResponse, error) {
+	out := new(RevokeServiceAccountTokenResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/RevokeServiceAccountToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListNumberGroups(ctx context.Context, in *ListNumberGroupsRequest, opts ...grpc.CallOption) (*ListNumberGroupsResponse, error) {
+	out := new(ListNumberGroupsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListNumberGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ResetNumberGroup(ctx context.Context, in *ResetNumberGroupRequest, opts ...grpc.CallOption) (*ResetNumberGroupResponse, error) {
+	out := new(ResetNumberGroupResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ResetNumberGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) DeleteNumberGroups(ctx context.Context, in *DeleteNumberGroupsRequest, opts ...grpc.CallOption) (*DeleteNumberGroupsResponse, error) {
+	out := new(DeleteNumberGroupsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/DeleteNumberGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) GetWebhookDelivery(ctx context.Context, in *GetWebhookDeliveryRequest, opts ...grpc.CallOption) (*GetWebhookDeliveryResponse, error) {
+	out := new(GetWebhookDeliveryResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/GetWebhookDelivery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftAdminClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error) {
+	out := new(RedeliverWebhookResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftAdmin/RedeliverWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WerftAdminServer is the server API for WerftAdmin service.
+type WerftAdminServer interface {
+	// SetDrain makes werft stop (or resume) accepting new jobs. Running jobs are not affected.
+	// This is a shorthand for SetMaintenance without message and queueing.
+	SetDrain(context.Context, *SetDrainRequest) (*SetDrainResponse, error)
+	// SetMaintenance starts or ends maintenance, e.g. for a cluster upgrade. During maintenance werft does not accept
+	// new jobs. The maintenance mode survives restarts of werft.
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error)
+	// RequeueStuckJobs marks jobs which are not done but have no pod anymore as failed and starts them again.
+	RequeueStuckJobs(context.Context, *RequeueStuckJobsRequest) (*RequeueStuckJobsResponse, error)
+	// Prune removes finished jobs and their logs.
+	Prune(context.Context, *PruneRequest) (*PruneResponse, error)
+	// ListTokens lists the configured API tokens without revealing their secret.
+	ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error)
+	// GetPluginStatus returns the status of all configured plugins.
+	GetPluginStatus(context.Context, *GetPluginStatusRequest) (*GetPluginStatusResponse, error)
+	// DumpConfig returns the server configuration with all secrets redacted.
+	DumpConfig(context.Context, *DumpConfigRequest) (*DumpConfigResponse, error)
+	// ListRepositories lists the settings of all registered repositories without revealing their webhook secret.
+	ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error)
+	// ListEvents queries the event trace, i.e. the status updates werft saw for jobs, oldest first.
+	// This requires the event trace to be stored, see storage.eventTrace in the server config.
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	// SnoozeNotifications silences the notifications about jobs matching a filter for a while.
+	// Snoozes do not survive restarts of werft.
+	SnoozeNotifications(context.Context, *SnoozeNotificationsRequest) (*SnoozeNotificationsResponse, error)
+	// ListSnoozes lists the notification snoozes which have not expired yet.
+	ListSnoozes(context.Context, *ListSnoozesRequest) (*ListSnoozesResponse, error)
+	// DeleteSnooze ends a notification snooze before it expires.
+	DeleteSnooze(context.Context, *DeleteSnoozeRequest) (*DeleteSnoozeResponse, error)
+	// CreateServiceAccountToken creates a long-lived token for a service account, e.g. a deployment bot.
+	// The response is the only time the token is revealed.
+	CreateServiceAccountToken(context.Context, *CreateServiceAccountTokenRequest) (*CreateServiceAccountTokenResponse, error)
+	// ListServiceAccountTokens lists the tokens of service accounts without revealing them.
+	ListServiceAccountTokens(context.Context, *ListServiceAccountTokensRequest) (*ListServiceAccountTokensResponse, error)
+	// RotateServiceAccountToken replaces a token with a new one of the same service account and scopes.
+	// The old token keeps working for a grace period, s.t. it can be replaced wherever it's used.
+	RotateServiceAccountToken(context.Context, *RotateServiceAccountTokenRequest) (*RotateServiceAccountTokenResponse, error)
+	// RevokeServiceAccountToken makes a token stop working immediately.
+	RevokeServiceAccountToken(context.Context, *RevokeServiceAccountTokenRequest) (*RevokeServiceAccountTokenResponse, error)
+	// ListNumberGroups lists the counters job names and build numbers are drawn from, and their latest number.
+	ListNumberGroups(context.Context, *ListNumberGroupsRequest) (*ListNumberGroupsResponse, error)
+	// ResetNumberGroup sets the latest number of a group, s.t. the next job continues from there.
+	ResetNumberGroup(context.Context, *ResetNumberGroupRequest) (*ResetNumberGroupResponse, error)
+	// DeleteNumberGroups removes number groups. Without names it removes the groups no job refers to anymore,
+	// e.g. those of removed repositories or pruned branches.
+	DeleteNumberGroups(context.Context, *DeleteNumberGroupsRequest) (*DeleteNumberGroupsResponse, error)
+	// ListWebhookDeliveries lists the webhooks werft received within the retention window, newest first,
+	// and what became of them - e.g. to find out why a push did not start a job.
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// GetWebhookDelivery returns a webhook delivery including its payload.
+	GetWebhookDelivery(context.Context, *GetWebhookDeliveryRequest) (*GetWebhookDeliveryResponse, error)
+	// RedeliverWebhook processes the payload of a webhook delivery again, as if it was just received.
+	// The redelivery is recorded as a delivery of its own.
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error)
+}
+
+// UnimplementedWerftAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedWerftAdminServer struct {
+}
+
+func (*UnimplementedWerftAdminServer) SetDrain(ctx context.Context, req *SetDrainRequest) (*SetDrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDrain not implemented")
+}
+func (*UnimplementedWerftAdminServer) SetMaintenance(ctx context.Context, req *SetMaintenanceRequest) (*SetMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (*UnimplementedWerftAdminServer) RequeueStuckJobs(ctx context.Context, req *RequeueStuckJobsRequest) (*RequeueStuckJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueStuckJobs not implemented")
+}
+func (*UnimplementedWerftAdminServer) Prune(ctx context.Context, req *PruneRequest) (*PruneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prune not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListTokens(ctx context.Context, req *ListTokensRequest) (*ListTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokens not implemented")
+}
+func (*UnimplementedWerftAdminServer) GetPluginStatus(ctx context.Context, req *GetPluginStatusRequest) (*GetPluginStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPluginStatus not implemented")
+}
+func (*UnimplementedWerftAdminServer) DumpConfig(ctx context.Context, req *DumpConfigRequest) (*DumpConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpConfig not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListRepositories(ctx context.Context, req *ListRepositoriesRequest) (*ListRepositoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRepositories not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListEvents(ctx context.Context, req *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (*UnimplementedWerftAdminServer) SnoozeNotifications(ctx context.Context, req *SnoozeNotificationsRequest) (*SnoozeNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnoozeNotifications not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListSnoozes(ctx context.Context, req *ListSnoozesRequest) (*ListSnoozesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnoozes not implemented")
+}
+func (*UnimplementedWerftAdminServer) DeleteSnooze(ctx context.Context, req *DeleteSnoozeRequest) (*DeleteSnoozeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSnooze not implemented")
+}
+func (*UnimplementedWerftAdminServer) CreateServiceAccountToken(ctx context.Context, req *CreateServiceAccountTokenRequest) (*CreateServiceAccountTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccountToken not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListServiceAccountTokens(ctx context.Context, req *ListServiceAccountTokensRequest) (*ListServiceAccountTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccountTokens not implemented")
+}
+func (*UnimplementedWerftAdminServer) RotateServiceAccountToken(ctx context.Context, req *RotateServiceAccountTokenRequest) (*RotateServiceAccountTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateServiceAccountToken not implemented")
+}
+func (*UnimplementedWerftAdminServer) RevokeServiceAccountToken(ctx context.Context, req *RevokeServiceAccountTokenRequest) (*RevokeServiceAccountTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeServiceAccountToken not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListNumberGroups(ctx context.Context, req *ListNumberGroupsRequest) (*ListNumberGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNumberGroups not implemented")
+}
+func (*UnimplementedWerftAdminServer) ResetNumberGroup(ctx context.Context, req *ResetNumberGroupRequest) (*ResetNumberGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetNumberGroup not implemented")
+}
+func (*UnimplementedWerftAdminServer) DeleteNumberGroups(ctx context.Context, req *DeleteNumberGroupsRequest) (*DeleteNumberGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNumberGroups not implemented")
+}
+func (*UnimplementedWerftAdminServer) ListWebhookDeliveries(ctx context.Context, req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (*UnimplementedWerftAdminServer) GetWebhookDelivery(ctx context.Context, req *GetWebhookDeliveryRequest) (*GetWebhookDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDelivery not implemented")
+}
+func (*UnimplementedWerftAdminServer) RedeliverWebhook(ctx context.Context, req *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+
+func RegisterWerftAdminServer(s *grpc.Server, srv WerftAdminServer) {
+	s.RegisterService(&_WerftAdmin_serviceDesc, srv)
+}
+
+func _WerftAdmin_SetDrain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).SetDrain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/SetDrain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).SetDrain(ctx, req.(*SetDrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/SetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_RequeueStuckJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueStuckJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).RequeueStuckJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/RequeueStuckJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).RequeueStuckJobs(ctx, req.(*RequeueStuckJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_Prune_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).Prune(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/Prune",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).Prune(ctx, req.(*PruneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListTokens(ctx, req.(*ListTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_GetPluginStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPluginStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).GetPluginStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/GetPluginStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).GetPluginStatus(ctx, req.(*GetPluginStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_DumpConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DumpConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).DumpConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/DumpConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).DumpConfig(ctx, req.(*DumpConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListRepositories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRepositoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListRepositories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListRepositories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListRepositories(ctx, req.(*ListRepositoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_SnoozeNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnoozeNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).SnoozeNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/SnoozeNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).SnoozeNotifications(ctx, req.(*SnoozeNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListSnoozes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnoozesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListSnoozes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListSnoozes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListSnoozes(ctx, req.(*ListSnoozesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_DeleteSnooze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSnoozeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).DeleteSnooze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/DeleteSnooze",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).DeleteSnooze(ctx, req.(*DeleteSnoozeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_CreateServiceAccountToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).CreateServiceAccountToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/CreateServiceAccountToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).CreateServiceAccountToken(ctx, req.(*CreateServiceAccountTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListServiceAccountTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListServiceAccountTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListServiceAccountTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListServiceAccountTokens(ctx, req.(*ListServiceAccountTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_RotateServiceAccountToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateServiceAccountTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).RotateServiceAccountToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/RotateServiceAccountToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).RotateServiceAccountToken(ctx, req.(*RotateServiceAccountTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_RevokeServiceAccountToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeServiceAccountTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).RevokeServiceAccountToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/RevokeServiceAccountToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).RevokeServiceAccountToken(ctx, req.(*RevokeServiceAccountTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListNumberGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNumberGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListNumberGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListNumberGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListNumberGroups(ctx, req.(*ListNumberGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ResetNumberGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetNumberGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ResetNumberGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ResetNumberGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ResetNumberGroup(ctx, req.(*ResetNumberGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_DeleteNumberGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNumberGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).DeleteNumberGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/DeleteNumberGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).DeleteNumberGroups(ctx, req.(*DeleteNumberGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_GetWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).GetWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/GetWebhookDelivery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).GetWebhookDelivery(ctx, req.(*GetWebhookDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftAdmin_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftAdminServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftAdmin/RedeliverWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftAdminServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WerftAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.WerftAdmin",
+	HandlerType: (*WerftAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetDrain",
+			Handler:    _WerftAdmin_SetDrain_Handler,
+		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _WerftAdmin_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "RequeueStuckJobs",
+			Handler:    _WerftAdmin_RequeueStuckJobs_Handler,
+		},
+		{
+			MethodName: "Prune",
+			Handler:    _WerftAdmin_Prune_Handler,
+		},
+		{
+			MethodName: "ListTokens",
+			Handler:    _WerftAdmin_ListTokens_Handler,
+		},
+		{
+			MethodName: "GetPluginStatus",
+			Handler:    _WerftAdmin_GetPluginStatus_Handler,
+		},
+		{
+			MethodName: "DumpConfig",
+			Handler:    _WerftAdmin_DumpConfig_Handler,
+		},
+		{
+			MethodName: "ListRepositories",
+			Handler:    _WerftAdmin_ListRepositories_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _WerftAdmin_ListEvents_Handler,
+		},
+		{
+			MethodName: "SnoozeNotifications",
+			Handler:    _WerftAdmin_SnoozeNotifications_Handler,
+		},
+		{
+			MethodName: "ListSnoozes",
+			Handler:    _WerftAdmin_ListSnoozes_Handler,
+		},
+		{
+			MethodName: "DeleteSnooze",
+			Handler:    _WerftAdmin_DeleteSnooze_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccountToken",
+			Handler:    _WerftAdmin_CreateServiceAccountToken_Handler,
+		},
+		{
+			MethodName: "ListServiceAccountTokens",
+			Handler:    _WerftAdmin_ListServiceAccountTokens_Handler,
+		},
+		{
+			MethodName: "RotateServiceAccountToken",
+			Handler:    _WerftAdmin_RotateServiceAccountToken_Handler,
+		},
+		{
+			MethodName: "RevokeServiceAccountToken",
+			Handler:    _WerftAdmin_RevokeServiceAccountToken_Handler,
+		},
+		{
+			MethodName: "ListNumberGroups",
+			Handler:    _WerftAdmin_ListNumberGroups_Handler,
+		},
+		{
+			MethodName: "ResetNumberGroup",
+			Handler:    _WerftAdmin_ResetNumberGroup_Handler,
+		},
+		{
+			MethodName: "DeleteNumberGroups",
+			Handler:    _WerftAdmin_DeleteNumberGroups_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _WerftAdmin_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "GetWebhookDelivery",
+			Handler:    _WerftAdmin_GetWebhookDelivery_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _WerftAdmin_RedeliverWebhook_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "werft-admin.proto",
+}
@@ -0,0 +1,493 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: werft-plugin.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ContentInitContainerRequest struct {
+	Metadata             *JobMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ContentInitContainerRequest) Reset()         { *m = ContentInitContainerRequest{} }
+func (m *ContentInitContainerRequest) String() string { return proto.CompactTextString(m) }
+func (*ContentInitContainerRequest) ProtoMessage()    {}
+func (*ContentInitContainerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{0}
+}
+
+func (m *ContentInitContainerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentInitContainerRequest.Unmarshal(m, b)
+}
+func (m *ContentInitContainerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentInitContainerRequest.Marshal(b, m, deterministic)
+}
+func (m *ContentInitContainerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentInitContainerRequest.Merge(m, src)
+}
+func (m *ContentInitContainerRequest) XXX_Size() int {
+	return xxx_messageInfo_ContentInitContainerRequest.Size(m)
+}
+func (m *ContentInitContainerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentInitContainerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentInitContainerRequest proto.InternalMessageInfo
+
+func (m *ContentInitContainerRequest) GetMetadata() *JobMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type ContentInitContainerResponse struct {
+	// container is the JSON encoded Kubernetes container spec of the init container
+	Container            []byte   `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContentInitContainerResponse) Reset()         { *m = ContentInitContainerResponse{} }
+func (m *ContentInitContainerResponse) String() string { return proto.CompactTextString(m) }
+func (*ContentInitContainerResponse) ProtoMessage()    {}
+func (*ContentInitContainerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{1}
+}
+
+func (m *ContentInitContainerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentInitContainerResponse.Unmarshal(m, b)
+}
+func (m *ContentInitContainerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentInitContainerResponse.Marshal(b, m, deterministic)
+}
+func (m *ContentInitContainerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentInitContainerResponse.Merge(m, src)
+}
+func (m *ContentInitContainerResponse) XXX_Size() int {
+	return xxx_messageInfo_ContentInitContainerResponse.Size(m)
+}
+func (m *ContentInitContainerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentInitContainerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentInitContainerResponse proto.InternalMessageInfo
+
+func (m *ContentInitContainerResponse) GetContainer() []byte {
+	if m != nil {
+		return m.Container
+	}
+	return nil
+}
+
+type ContentServeRequest struct {
+	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata             *JobMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ContentServeRequest) Reset()         { *m = ContentServeRequest{} }
+func (m *ContentServeRequest) String() string { return proto.CompactTextString(m) }
+func (*ContentServeRequest) ProtoMessage()    {}
+func (*ContentServeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{2}
+}
+
+func (m *ContentServeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentServeRequest.Unmarshal(m, b)
+}
+func (m *ContentServeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentServeRequest.Marshal(b, m, deterministic)
+}
+func (m *ContentServeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentServeRequest.Merge(m, src)
+}
+func (m *ContentServeRequest) XXX_Size() int {
+	return xxx_messageInfo_ContentServeRequest.Size(m)
+}
+func (m *ContentServeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentServeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentServeRequest proto.InternalMessageInfo
+
+func (m *ContentServeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ContentServeRequest) GetMetadata() *JobMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type ContentServeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContentServeResponse) Reset()         { *m = ContentServeResponse{} }
+func (m *ContentServeResponse) String() string { return proto.CompactTextString(m) }
+func (*ContentServeResponse) ProtoMessage()    {}
+func (*ContentServeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{3}
+}
+
+func (m *ContentServeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentServeResponse.Unmarshal(m, b)
+}
+func (m *ContentServeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentServeResponse.Marshal(b, m, deterministic)
+}
+func (m *ContentServeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentServeResponse.Merge(m, src)
+}
+func (m *ContentServeResponse) XXX_Size() int {
+	return xxx_messageInfo_ContentServeResponse.Size(m)
+}
+func (m *ContentServeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentServeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentServeResponse proto.InternalMessageInfo
+
+type ContentDownloadRequest struct {
+	Repository           *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Path                 string      `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ContentDownloadRequest) Reset()         { *m = ContentDownloadRequest{} }
+func (m *ContentDownloadRequest) String() string { return proto.CompactTextString(m) }
+func (*ContentDownloadRequest) ProtoMessage()    {}
+func (*ContentDownloadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{4}
+}
+
+func (m *ContentDownloadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentDownloadRequest.Unmarshal(m, b)
+}
+func (m *ContentDownloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentDownloadRequest.Marshal(b, m, deterministic)
+}
+func (m *ContentDownloadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentDownloadRequest.Merge(m, src)
+}
+func (m *ContentDownloadRequest) XXX_Size() int {
+	return xxx_messageInfo_ContentDownloadRequest.Size(m)
+}
+func (m *ContentDownloadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentDownloadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentDownloadRequest proto.InternalMessageInfo
+
+func (m *ContentDownloadRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *ContentDownloadRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type ContentDownloadResponse struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContentDownloadResponse) Reset()         { *m = ContentDownloadResponse{} }
+func (m *ContentDownloadResponse) String() string { return proto.CompactTextString(m) }
+func (*ContentDownloadResponse) ProtoMessage()    {}
+func (*ContentDownloadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{5}
+}
+
+func (m *ContentDownloadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContentDownloadResponse.Unmarshal(m, b)
+}
+func (m *ContentDownloadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContentDownloadResponse.Marshal(b, m, deterministic)
+}
+func (m *ContentDownloadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContentDownloadResponse.Merge(m, src)
+}
+func (m *ContentDownloadResponse) XXX_Size() int {
+	return xxx_messageInfo_ContentDownloadResponse.Size(m)
+}
+func (m *ContentDownloadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContentDownloadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContentDownloadResponse proto.InternalMessageInfo
+
+func (m *ContentDownloadResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ContentInitContainerRequest)(nil), "v1.ContentInitContainerRequest")
+	proto.RegisterType((*ContentInitContainerResponse)(nil), "v1.ContentInitContainerResponse")
+	proto.RegisterType((*ContentServeRequest)(nil), "v1.ContentServeRequest")
+	proto.RegisterType((*ContentServeResponse)(nil), "v1.ContentServeResponse")
+	proto.RegisterType((*ContentDownloadRequest)(nil), "v1.ContentDownloadRequest")
+	proto.RegisterType((*ContentDownloadResponse)(nil), "v1.ContentDownloadResponse")
+}
+
+func init() { proto.RegisterFile("werft-plugin.proto", fileDescriptor_9a931a6e0aa932ef) }
+
+var fileDescriptor_9a931a6e0aa932ef = []byte{
+	// 319 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0xd1, 0x4a, 0x02, 0x41,
+	0x14, 0x6d, 0xc5, 0x42, 0xaf, 0x56, 0x70, 0x2b, 0x95, 0x55, 0x48, 0xf6, 0x29, 0x08, 0x97, 0xb4,
+	0x57, 0xdf, 0x0a, 0x22, 0x21, 0x90, 0x29, 0x7a, 0xea, 0x65, 0x6c, 0x6f, 0xb5, 0xa0, 0x33, 0xdb,
+	0xec, 0xb8, 0xd2, 0x7f, 0xf7, 0x01, 0xb1, 0xe3, 0xec, 0xb6, 0x9b, 0x56, 0x6f, 0x87, 0x33, 0x67,
+	0xce, 0x9c, 0x7b, 0xee, 0x00, 0xae, 0x48, 0xbd, 0xe8, 0x41, 0x34, 0x5f, 0xbe, 0x86, 0xc2, 0x8f,
+	0x94, 0xd4, 0x12, 0x2b, 0xc9, 0xd0, 0x6d, 0x18, 0x7e, 0x4d, 0x78, 0x13, 0xe8, 0x5e, 0x49, 0xa1,
+	0x49, 0xe8, 0x5b, 0x11, 0xea, 0x14, 0xf2, 0x50, 0x90, 0x62, 0xf4, 0xbe, 0xa4, 0x58, 0xe3, 0x39,
+	0xd4, 0x16, 0xa4, 0x79, 0xc0, 0x35, 0xef, 0x38, 0x7d, 0xe7, 0xac, 0x31, 0x3a, 0xf4, 0x93, 0xa1,
+	0x3f, 0x91, 0xb3, 0x3b, 0x4b, 0xb3, 0x5c, 0xe0, 0x8d, 0xa1, 0xb7, 0xdd, 0x2b, 0x8e, 0xa4, 0x88,
+	0x09, 0x7b, 0x50, 0x7f, 0xce, 0x48, 0xe3, 0xd6, 0x64, 0xdf, 0x84, 0xf7, 0x08, 0x47, 0xf6, 0xf6,
+	0x3d, 0xa9, 0x84, 0xb2, 0x04, 0x08, 0x55, 0xc1, 0x17, 0x64, 0xf4, 0x75, 0x66, 0x70, 0x29, 0x55,
+	0xe5, 0xbf, 0x54, 0x2d, 0x38, 0x2e, 0xfb, 0xae, 0xd3, 0x78, 0x4f, 0xd0, 0xb2, 0xfc, 0xb5, 0x5c,
+	0x89, 0xb9, 0xe4, 0x41, 0xf6, 0xa4, 0x0f, 0xa0, 0x28, 0x92, 0x71, 0xa8, 0xa5, 0xfa, 0xb0, 0x63,
+	0x1f, 0xa4, 0x0f, 0xb0, 0x9c, 0x65, 0x05, 0x45, 0x1a, 0x31, 0xe2, 0xfa, 0xcd, 0x44, 0xa9, 0x33,
+	0x83, 0xbd, 0x01, 0xb4, 0x37, 0xdc, 0x6d, 0x0d, 0x08, 0xd5, 0xbc, 0xcf, 0x26, 0x33, 0x78, 0xf4,
+	0xe9, 0xc0, 0x89, 0xd5, 0x4f, 0x95, 0x4c, 0xc2, 0x80, 0xd4, 0xd4, 0xec, 0x0d, 0x1f, 0x60, 0xbf,
+	0xd4, 0x26, 0x9e, 0xa6, 0x49, 0xfe, 0xd8, 0x99, 0xdb, 0xff, 0x5d, 0x60, 0x47, 0xdf, 0xc1, 0x31,
+	0xec, 0x9a, 0x36, 0xb0, 0x5d, 0x10, 0x17, 0x7b, 0x77, 0x3b, 0x9b, 0x07, 0xf9, 0xed, 0x1b, 0xa8,
+	0x65, 0x53, 0xa1, 0x5b, 0xd0, 0xfd, 0x28, 0xd2, 0xed, 0x6e, 0x3d, 0xcb, 0x6c, 0x2e, 0x9c, 0xd9,
+	0x9e, 0xf9, 0x84, 0x97, 0x5f, 0x03, 0x00, 0xc9, 0xb0, 0x68, 0x5e, 0xab, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ContentProviderPluginClient is the client API for ContentProviderPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ContentProviderPluginClient interface {
+	// InitContainer produces the container which initializes the workspace of a job.
+	// The workspace is mounted at /workspace. Name and image pull policy of the container are overwritten.
+	InitContainer(ctx context.Context, in *ContentInitContainerRequest, opts ...grpc.CallOption) (*ContentInitContainerResponse, error)
+	// Serve provides additional services required during initialization, e.g. uploading content into the init container.
+	// werft waits for this call to return before it considers the job started.
+	Serve(ctx context.Context, in *ContentServeRequest, opts ...grpc.CallOption) (*ContentServeResponse, error)
+	// Download provides access to a single file of a repository, e.g. the werft config or a job spec
+	Download(ctx context.Context, in *ContentDownloadRequest, opts ...grpc.CallOption) (ContentProviderPlugin_DownloadClient, error)
+}
+
+type contentProviderPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewContentProviderPluginClient(cc *grpc.ClientConn) ContentProviderPluginClient {
+	return &contentProviderPluginClient{cc}
+}
+
+func (c *contentProviderPluginClient) InitContainer(ctx context.Context, in *ContentInitContainerRequest, opts ...grpc.CallOption) (*ContentInitContainerResponse, error) {
+	out := new(ContentInitContainerResponse)
+	err := c.cc.Invoke(ctx, "/v1.ContentProviderPlugin/InitContainer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentProviderPluginClient) Serve(ctx context.Context, in *ContentServeRequest, opts ...grpc.CallOption) (*ContentServeResponse, error) {
+	out := new(ContentServeResponse)
+	err := c.cc.Invoke(ctx, "/v1.ContentProviderPlugin/Serve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentProviderPluginClient) Download(ctx context.Context, in *ContentDownloadRequest, opts ...grpc.CallOption) (ContentProviderPlugin_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ContentProviderPlugin_serviceDesc.Streams[0], "/v1.ContentProviderPlugin/Download", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &contentProviderPluginDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ContentProviderPlugin_DownloadClient interface {
+	Recv() (*ContentDownloadResponse, error)
+	grpc.ClientStream
+}
+
+type contentProviderPluginDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *contentProviderPluginDownloadClient) Recv() (*ContentDownloadResponse, error) {
+	m := new(ContentDownloadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ContentProviderPluginServer is the server API for ContentProviderPlugin service.
+type ContentProviderPluginServer interface {
+	// InitContainer produces the container which initializes the workspace of a job.
+	// The workspace is mounted at /workspace. Name and image pull policy of the container are overwritten.
+	InitContainer(context.Context, *ContentInitContainerRequest) (*ContentInitContainerResponse, error)
+	// Serve provides additional services required during initialization, e.g. uploading content into the init container.
+	// werft waits for this call to return before it considers the job started.
+	Serve(context.Context, *ContentServeRequest) (*ContentServeResponse, error)
+	// Download provides access to a single file of a repository, e.g. the werft config or a job spec
+	Download(*ContentDownloadRequest, ContentProviderPlugin_DownloadServer) error
+}
+
+// UnimplementedContentProviderPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedContentProviderPluginServer struct {
+}
+
+func (*UnimplementedContentProviderPluginServer) InitContainer(ctx context.Context, req *ContentInitContainerRequest) (*ContentInitContainerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitContainer not implemented")
+}
+func (*UnimplementedContentProviderPluginServer) Serve(ctx context.Context, req *ContentServeRequest) (*ContentServeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Serve not implemented")
+}
+func (*UnimplementedContentProviderPluginServer) Download(req *ContentDownloadRequest, srv ContentProviderPlugin_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+
+func RegisterContentProviderPluginServer(s *grpc.Server, srv ContentProviderPluginServer) {
+	s.RegisterService(&_ContentProviderPlugin_serviceDesc, srv)
+}
+
+func _ContentProviderPlugin_InitContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContentInitContainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentProviderPluginServer).InitContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ContentProviderPlugin/InitContainer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentProviderPluginServer).InitContainer(ctx, req.(*ContentInitContainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentProviderPlugin_Serve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContentServeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentProviderPluginServer).Serve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ContentProviderPlugin/Serve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentProviderPluginServer).Serve(ctx, req.(*ContentServeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentProviderPlugin_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContentDownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContentProviderPluginServer).Download(m, &contentProviderPluginDownloadServer{stream})
+}
+
+type ContentProviderPlugin_DownloadServer interface {
+	Send(*ContentDownloadResponse) error
+	grpc.ServerStream
+}
+
+type contentProviderPluginDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *contentProviderPluginDownloadServer) Send(m *ContentDownloadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ContentProviderPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.ContentProviderPlugin",
+	HandlerType: (*ContentProviderPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InitContainer",
+			Handler:    _ContentProviderPlugin_InitContainer_Handler,
+		},
+		{
+			MethodName: "Serve",
+			Handler:    _ContentProviderPlugin_Serve_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Download",
+			Handler:       _ContentProviderPlugin_Download_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "werft-plugin.proto",
+}
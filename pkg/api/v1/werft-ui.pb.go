@@ -191,7 +191,7 @@ func init() {
 func init() { proto.RegisterFile("werft-ui.proto", fileDescriptor_8d41ca2a021dc92d) }
 
 var fileDescriptor_8d41ca2a021dc92d = []byte{
-	// 268 bytes of a gzipped FileDescriptorProto
+	// 265 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x4b, 0xc3, 0x30,
 	0x18, 0x86, 0xcd, 0x5a, 0x75, 0xfb, 0x2a, 0x03, 0xa3, 0xc3, 0xd0, 0x53, 0xc9, 0xa9, 0x17, 0x8b,
 	0xeb, 0x7e, 0x81, 0xa0, 0x07, 0xc5, 0x83, 0x44, 0xc4, 0x73, 0xb7, 0x7d, 0x6a, 0x0e, 0x4b, 0xb2,
@@ -207,8 +207,8 @@ var fileDescriptor_8d41ca2a021dc92d = []byte{
 	0x37, 0xa1, 0x29, 0x7e, 0x7a, 0x1c, 0xe1, 0x74, 0x90, 0xef, 0x9d, 0x48, 0xcf, 0x29, 0x87, 0xb1,
 	0xc5, 0x6d, 0x1b, 0x9a, 0xd1, 0x75, 0x2c, 0xf6, 0xdf, 0x7f, 0xdd, 0x92, 0x81, 0x5b, 0xfd, 0x08,
 	0xc7, 0x2f, 0x61, 0xc8, 0xe7, 0x3b, 0x7a, 0x0b, 0x27, 0xfd, 0xb1, 0xe8, 0x45, 0x70, 0xfc, 0x67,
-	0xd6, 0x9c, 0x0d, 0x83, 0x6e, 0x57, 0x7e, 0x70, 0x45, 0x96, 0x47, 0xf1, 0x49, 0x16, 0xdf, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0x14, 0xb8, 0xdd, 0x0d, 0xb5, 0x01, 0x00, 0x00,
+	0xd6, 0x9c, 0x0d, 0x83, 0x6e, 0x57, 0x7e, 0x70, 0x45, 0x96, 0x47, 0xf1, 0x49, 0x16, 0xdf, 0x03,
+	0x00, 0x14, 0xb8, 0xdd, 0x0d, 0xb5, 0x01, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
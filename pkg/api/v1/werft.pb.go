@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -66,6 +67,8 @@ const (
 	ListenRequestLogs_LOGS_UNSLICED ListenRequestLogs = 1
 	ListenRequestLogs_LOGS_RAW      ListenRequestLogs = 2
 	ListenRequestLogs_LOGS_HTML     ListenRequestLogs = 3
+	// LOGS_PLAIN works like LOGS_RAW but strips all ANSI escape sequences (e.g. colors) from the log content
+	ListenRequestLogs_LOGS_PLAIN ListenRequestLogs = 4
 )
 
 var ListenRequestLogs_name = map[int32]string{
@@ -73,6 +76,7 @@ var ListenRequestLogs_name = map[int32]string{
 	1: "LOGS_UNSLICED",
 	2: "LOGS_RAW",
 	3: "LOGS_HTML",
+	4: "LOGS_PLAIN",
 }
 
 var ListenRequestLogs_value = map[string]int32{
@@ -80,6 +84,7 @@ var ListenRequestLogs_value = map[string]int32{
 	"LOGS_UNSLICED": 1,
 	"LOGS_RAW":      2,
 	"LOGS_HTML":     3,
+	"LOGS_PLAIN":    4,
 }
 
 func (x ListenRequestLogs) String() string {
@@ -90,6 +95,102 @@ func (ListenRequestLogs) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_9fe744feedd6d332, []int{1}
 }
 
+type ListenRequestStatus int32
+
+const (
+	// STATUS_LOG renders status snapshots as werft:status log slices, the way werft used to write them into the log
+	ListenRequestStatus_STATUS_LOG      ListenRequestStatus = 0
+	ListenRequestStatus_STATUS_DISABLED ListenRequestStatus = 1
+	// STATUS_STRUCTURED sends status snapshots as ListenResponse.status, interleaved with the log slices
+	ListenRequestStatus_STATUS_STRUCTURED ListenRequestStatus = 2
+)
+
+var ListenRequestStatus_name = map[int32]string{
+	0: "STATUS_LOG",
+	1: "STATUS_DISABLED",
+	2: "STATUS_STRUCTURED",
+}
+
+var ListenRequestStatus_value = map[string]int32{
+	"STATUS_LOG":        0,
+	"STATUS_DISABLED":   1,
+	"STATUS_STRUCTURED": 2,
+}
+
+func (x ListenRequestStatus) String() string {
+	return proto.EnumName(ListenRequestStatus_name, int32(x))
+}
+
+func (ListenRequestStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{2}
+}
+
+type CleanupState int32
+
+const (
+	// Pending means the workspace is being removed, or another attempt to remove it is due
+	CleanupState_CLEANUP_PENDING CleanupState = 0
+	// Done means the workspace was removed
+	CleanupState_CLEANUP_DONE CleanupState = 1
+	// Failed means werft gave up removing the workspace
+	CleanupState_CLEANUP_FAILED CleanupState = 2
+)
+
+var CleanupState_name = map[int32]string{
+	0: "CLEANUP_PENDING",
+	1: "CLEANUP_DONE",
+	2: "CLEANUP_FAILED",
+}
+
+var CleanupState_value = map[string]int32{
+	"CLEANUP_PENDING": 0,
+	"CLEANUP_DONE":    1,
+	"CLEANUP_FAILED":  2,
+}
+
+func (x CleanupState) String() string {
+	return proto.EnumName(CleanupState_name, int32(x))
+}
+
+func (CleanupState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{3}
+}
+
+type StepOutcome int32
+
+const (
+	// Unknown means the step has not finished yet, or it is a phase of the job which has no outcome of its own
+	StepOutcome_STEP_UNKNOWN StepOutcome = 0
+	// Succeeded means the command of the step exited with code zero
+	StepOutcome_STEP_SUCCEEDED StepOutcome = 1
+	// Failed means the command of the step exited with a non-zero code
+	StepOutcome_STEP_FAILED StepOutcome = 2
+	// Skipped means the step did not run because a step it waits for did not succeed
+	StepOutcome_STEP_SKIPPED StepOutcome = 3
+)
+
+var StepOutcome_name = map[int32]string{
+	0: "STEP_UNKNOWN",
+	1: "STEP_SUCCEEDED",
+	2: "STEP_FAILED",
+	3: "STEP_SKIPPED",
+}
+
+var StepOutcome_value = map[string]int32{
+	"STEP_UNKNOWN":   0,
+	"STEP_SUCCEEDED": 1,
+	"STEP_FAILED":    2,
+	"STEP_SKIPPED":   3,
+}
+
+func (x StepOutcome) String() string {
+	return proto.EnumName(StepOutcome_name, int32(x))
+}
+
+func (StepOutcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{4}
+}
+
 type JobTrigger int32
 
 const (
@@ -97,6 +198,10 @@ const (
 	JobTrigger_TRIGGER_MANUAL  JobTrigger = 1
 	JobTrigger_TRIGGER_PUSH    JobTrigger = 2
 	JobTrigger_TRIGGER_DELETED JobTrigger = 3
+	// Upstream jobs are started by another job which succeeded
+	JobTrigger_TRIGGER_UPSTREAM JobTrigger = 4
+	// Promotion jobs release what another job built
+	JobTrigger_TRIGGER_PROMOTION JobTrigger = 5
 )
 
 var JobTrigger_name = map[int32]string{
@@ -104,13 +209,17 @@ var JobTrigger_name = map[int32]string{
 	1: "TRIGGER_MANUAL",
 	2: "TRIGGER_PUSH",
 	3: "TRIGGER_DELETED",
+	4: "TRIGGER_UPSTREAM",
+	5: "TRIGGER_PROMOTION",
 }
 
 var JobTrigger_value = map[string]int32{
-	"TRIGGER_UNKNOWN": 0,
-	"TRIGGER_MANUAL":  1,
-	"TRIGGER_PUSH":    2,
-	"TRIGGER_DELETED": 3,
+	"TRIGGER_UNKNOWN":   0,
+	"TRIGGER_MANUAL":    1,
+	"TRIGGER_PUSH":      2,
+	"TRIGGER_DELETED":   3,
+	"TRIGGER_UPSTREAM":  4,
+	"TRIGGER_PROMOTION": 5,
 }
 
 func (x JobTrigger) String() string {
@@ -118,7 +227,7 @@ func (x JobTrigger) String() string {
 }
 
 func (JobTrigger) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{2}
+	return fileDescriptor_9fe744feedd6d332, []int{5}
 }
 
 type JobPhase int32
@@ -162,7 +271,42 @@ func (x JobPhase) String() string {
 }
 
 func (JobPhase) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{3}
+	return fileDescriptor_9fe744feedd6d332, []int{6}
+}
+
+type JobFailureClass int32
+
+const (
+	// Unclassified failures, or no failure at all
+	JobFailureClass_FAILURE_UNCLASSIFIED JobFailureClass = 0
+	// Checkout means the workspace could not be initialized, e.g. because of bad credentials or a missing ref
+	JobFailureClass_FAILURE_CHECKOUT JobFailureClass = 1
+	// Infrastructure means the job failed for reasons outside of its control, e.g. a lost node or an image pull backoff
+	JobFailureClass_FAILURE_INFRASTRUCTURE JobFailureClass = 2
+	// Test means the job itself failed, e.g. because its tests failed
+	JobFailureClass_FAILURE_TEST JobFailureClass = 3
+)
+
+var JobFailureClass_name = map[int32]string{
+	0: "FAILURE_UNCLASSIFIED",
+	1: "FAILURE_CHECKOUT",
+	2: "FAILURE_INFRASTRUCTURE",
+	3: "FAILURE_TEST",
+}
+
+var JobFailureClass_value = map[string]int32{
+	"FAILURE_UNCLASSIFIED":   0,
+	"FAILURE_CHECKOUT":       1,
+	"FAILURE_INFRASTRUCTURE": 2,
+	"FAILURE_TEST":           3,
+}
+
+func (x JobFailureClass) String() string {
+	return proto.EnumName(JobFailureClass_name, int32(x))
+}
+
+func (JobFailureClass) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{7}
 }
 
 type LogSliceType int32
@@ -175,6 +319,7 @@ const (
 	LogSliceType_SLICE_DONE      LogSliceType = 4
 	LogSliceType_SLICE_FAIL      LogSliceType = 5
 	LogSliceType_SLICE_RESULT    LogSliceType = 6
+	LogSliceType_SLICE_PROGRESS  LogSliceType = 7
 )
 
 var LogSliceType_name = map[int32]string{
@@ -185,6 +330,7 @@ var LogSliceType_name = map[int32]string{
 	4: "SLICE_DONE",
 	5: "SLICE_FAIL",
 	6: "SLICE_RESULT",
+	7: "SLICE_PROGRESS",
 }
 
 var LogSliceType_value = map[string]int32{
@@ -195,6 +341,7 @@ var LogSliceType_value = map[string]int32{
 	"SLICE_DONE":      4,
 	"SLICE_FAIL":      5,
 	"SLICE_RESULT":    6,
+	"SLICE_PROGRESS":  7,
 }
 
 func (x LogSliceType) String() string {
@@ -202,7 +349,41 @@ func (x LogSliceType) String() string {
 }
 
 func (LogSliceType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{4}
+	return fileDescriptor_9fe744feedd6d332, []int{8}
+}
+
+type StageStatus int32
+
+const (
+	StageStatus_STAGE_UNKNOWN   StageStatus = 0
+	StageStatus_STAGE_RUNNING   StageStatus = 1
+	StageStatus_STAGE_SUCCESS   StageStatus = 2
+	StageStatus_STAGE_FAILED    StageStatus = 3
+	StageStatus_STAGE_ABANDONED StageStatus = 4
+)
+
+var StageStatus_name = map[int32]string{
+	0: "STAGE_UNKNOWN",
+	1: "STAGE_RUNNING",
+	2: "STAGE_SUCCESS",
+	3: "STAGE_FAILED",
+	4: "STAGE_ABANDONED",
+}
+
+var StageStatus_value = map[string]int32{
+	"STAGE_UNKNOWN":   0,
+	"STAGE_RUNNING":   1,
+	"STAGE_SUCCESS":   2,
+	"STAGE_FAILED":    3,
+	"STAGE_ABANDONED": 4,
+}
+
+func (x StageStatus) String() string {
+	return proto.EnumName(StageStatus_name, int32(x))
+}
+
+func (StageStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{9}
 }
 
 type StartLocalJobRequest struct {
@@ -370,14 +551,17 @@ func (m *StartJobResponse) GetStatus() *JobStatus {
 }
 
 type StartGitHubJobRequest struct {
-	Metadata             *JobMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	JobPath              string       `protobuf:"bytes,2,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
-	JobYaml              []byte       `protobuf:"bytes,3,opt,name=job_yaml,json=jobYaml,proto3" json:"job_yaml,omitempty"`
-	GithubToken          string       `protobuf:"bytes,4,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
-	Sideload             []byte       `protobuf:"bytes,5,opt,name=sideload,proto3" json:"sideload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Metadata    *JobMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	JobPath     string       `protobuf:"bytes,2,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
+	JobYaml     []byte       `protobuf:"bytes,3,opt,name=job_yaml,json=jobYaml,proto3" json:"job_yaml,omitempty"`
+	GithubToken string       `protobuf:"bytes,4,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	Sideload    []byte       `protobuf:"bytes,5,opt,name=sideload,proto3" json:"sideload,omitempty"`
+	// idempotency_key makes retrying this call safe: if a job was started with the same key recently, that job is returned
+	// instead of starting a new one.
+	IdempotencyKey       string   `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StartGitHubJobRequest) Reset()         { *m = StartGitHubJobRequest{} }
@@ -440,9 +624,22 @@ func (m *StartGitHubJobRequest) GetSideload() []byte {
 	return nil
 }
 
+func (m *StartGitHubJobRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
 type StartFromPreviousJobRequest struct {
-	PreviousJob          string   `protobuf:"bytes,1,opt,name=previous_job,json=previousJob,proto3" json:"previous_job,omitempty"`
-	GithubToken          string   `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	PreviousJob string `protobuf:"bytes,1,opt,name=previous_job,json=previousJob,proto3" json:"previous_job,omitempty"`
+	GithubToken string `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	// idempotency_key makes retrying this call safe: if a job was started with the same key recently, that job is returned
+	// instead of starting a new one.
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// exact runs the job spec as it was rendered for the previous job rather than rendering its template again.
+	// This requires the server to store rendered job specs.
+	Exact                bool     `protobuf:"varint,4,opt,name=exact,proto3" json:"exact,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -487,6 +684,114 @@ func (m *StartFromPreviousJobRequest) GetGithubToken() string {
 	return ""
 }
 
+func (m *StartFromPreviousJobRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *StartFromPreviousJobRequest) GetExact() bool {
+	if m != nil {
+		return m.Exact
+	}
+	return false
+}
+
+type StartTarballJobRequest struct {
+	Metadata *JobMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// url is the HTTP(S) URL of the gzipped tarball. s3://bucket/key URLs refer to publicly readable S3 objects,
+	// private objects require a presigned HTTPS URL.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// sha256 is the hex encoded SHA-256 checksum of the tarball. If set, the job fails if the tarball does not match.
+	Sha256 string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// strip_components removes leading path components from the tarball content, e.g. 1 for archives with a top-level directory
+	StripComponents int32 `protobuf:"varint,4,opt,name=strip_components,json=stripComponents,proto3" json:"strip_components,omitempty"`
+	// job_path is the path of the job spec in the tarball. Defaults to the job the werft config in the tarball selects.
+	JobPath string `protobuf:"bytes,5,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
+	JobYaml []byte `protobuf:"bytes,6,opt,name=job_yaml,json=jobYaml,proto3" json:"job_yaml,omitempty"`
+	// idempotency_key makes retrying this call safe: if a job was started with the same key recently, that job is returned
+	// instead of starting a new one.
+	IdempotencyKey       string   `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartTarballJobRequest) Reset()         { *m = StartTarballJobRequest{} }
+func (m *StartTarballJobRequest) String() string { return proto.CompactTextString(m) }
+func (*StartTarballJobRequest) ProtoMessage()    {}
+func (*StartTarballJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{4}
+}
+
+func (m *StartTarballJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartTarballJobRequest.Unmarshal(m, b)
+}
+func (m *StartTarballJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartTarballJobRequest.Marshal(b, m, deterministic)
+}
+func (m *StartTarballJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartTarballJobRequest.Merge(m, src)
+}
+func (m *StartTarballJobRequest) XXX_Size() int {
+	return xxx_messageInfo_StartTarballJobRequest.Size(m)
+}
+func (m *StartTarballJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartTarballJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartTarballJobRequest proto.InternalMessageInfo
+
+func (m *StartTarballJobRequest) GetMetadata() *JobMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *StartTarballJobRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *StartTarballJobRequest) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *StartTarballJobRequest) GetStripComponents() int32 {
+	if m != nil {
+		return m.StripComponents
+	}
+	return 0
+}
+
+func (m *StartTarballJobRequest) GetJobPath() string {
+	if m != nil {
+		return m.JobPath
+	}
+	return ""
+}
+
+func (m *StartTarballJobRequest) GetJobYaml() []byte {
+	if m != nil {
+		return m.JobYaml
+	}
+	return nil
+}
+
+func (m *StartTarballJobRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
 type ListJobsRequest struct {
 	Filter               []*FilterExpression `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
 	Order                []*OrderExpression  `protobuf:"bytes,2,rep,name=order,proto3" json:"order,omitempty"`
@@ -501,7 +806,7 @@ func (m *ListJobsRequest) Reset()         { *m = ListJobsRequest{} }
 func (m *ListJobsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListJobsRequest) ProtoMessage()    {}
 func (*ListJobsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{4}
+	return fileDescriptor_9fe744feedd6d332, []int{5}
 }
 
 func (m *ListJobsRequest) XXX_Unmarshal(b []byte) error {
@@ -561,7 +866,7 @@ func (m *FilterExpression) Reset()         { *m = FilterExpression{} }
 func (m *FilterExpression) String() string { return proto.CompactTextString(m) }
 func (*FilterExpression) ProtoMessage()    {}
 func (*FilterExpression) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{5}
+	return fileDescriptor_9fe744feedd6d332, []int{6}
 }
 
 func (m *FilterExpression) XXX_Unmarshal(b []byte) error {
@@ -603,7 +908,7 @@ func (m *FilterTerm) Reset()         { *m = FilterTerm{} }
 func (m *FilterTerm) String() string { return proto.CompactTextString(m) }
 func (*FilterTerm) ProtoMessage()    {}
 func (*FilterTerm) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{6}
+	return fileDescriptor_9fe744feedd6d332, []int{7}
 }
 
 func (m *FilterTerm) XXX_Unmarshal(b []byte) error {
@@ -664,7 +969,7 @@ func (m *OrderExpression) Reset()         { *m = OrderExpression{} }
 func (m *OrderExpression) String() string { return proto.CompactTextString(m) }
 func (*OrderExpression) ProtoMessage()    {}
 func (*OrderExpression) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{7}
+	return fileDescriptor_9fe744feedd6d332, []int{8}
 }
 
 func (m *OrderExpression) XXX_Unmarshal(b []byte) error {
@@ -711,7 +1016,7 @@ func (m *ListJobsResponse) Reset()         { *m = ListJobsResponse{} }
 func (m *ListJobsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListJobsResponse) ProtoMessage()    {}
 func (*ListJobsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{8}
+	return fileDescriptor_9fe744feedd6d332, []int{9}
 }
 
 func (m *ListJobsResponse) XXX_Unmarshal(b []byte) error {
@@ -757,7 +1062,7 @@ func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
 func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
 func (*SubscribeRequest) ProtoMessage()    {}
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{9}
+	return fileDescriptor_9fe744feedd6d332, []int{10}
 }
 
 func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
@@ -796,7 +1101,7 @@ func (m *SubscribeResponse) Reset()         { *m = SubscribeResponse{} }
 func (m *SubscribeResponse) String() string { return proto.CompactTextString(m) }
 func (*SubscribeResponse) ProtoMessage()    {}
 func (*SubscribeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{10}
+	return fileDescriptor_9fe744feedd6d332, []int{11}
 }
 
 func (m *SubscribeResponse) XXX_Unmarshal(b []byte) error {
@@ -835,7 +1140,7 @@ func (m *GetJobRequest) Reset()         { *m = GetJobRequest{} }
 func (m *GetJobRequest) String() string { return proto.CompactTextString(m) }
 func (*GetJobRequest) ProtoMessage()    {}
 func (*GetJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{11}
+	return fileDescriptor_9fe744feedd6d332, []int{12}
 }
 
 func (m *GetJobRequest) XXX_Unmarshal(b []byte) error {
@@ -874,7 +1179,7 @@ func (m *GetJobResponse) Reset()         { *m = GetJobResponse{} }
 func (m *GetJobResponse) String() string { return proto.CompactTextString(m) }
 func (*GetJobResponse) ProtoMessage()    {}
 func (*GetJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{12}
+	return fileDescriptor_9fe744feedd6d332, []int{13}
 }
 
 func (m *GetJobResponse) XXX_Unmarshal(b []byte) error {
@@ -903,19 +1208,29 @@ func (m *GetJobResponse) GetResult() *JobStatus {
 }
 
 type ListenRequest struct {
-	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Updates              bool              `protobuf:"varint,2,opt,name=updates,proto3" json:"updates,omitempty"`
-	Logs                 ListenRequestLogs `protobuf:"varint,3,opt,name=logs,proto3,enum=v1.ListenRequestLogs" json:"logs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Name    string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Updates bool              `protobuf:"varint,2,opt,name=updates,proto3" json:"updates,omitempty"`
+	Logs    ListenRequestLogs `protobuf:"varint,3,opt,name=logs,proto3,enum=v1.ListenRequestLogs" json:"logs,omitempty"`
+	// cursor resumes log listening from a previously received LogSliceEvent cursor.
+	// If empty, logs are sent from the very beginning.
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// level, if set, restricts log content to structured log lines of at least this level
+	// (debug, info, warn, error or fatal). All other slice events are still sent.
+	Level string `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
+	// filter, if set, restricts the job updates sent to those matching the filter, e.g. phase==done
+	Filter []*FilterExpression `protobuf:"bytes,6,rep,name=filter,proto3" json:"filter,omitempty"`
+	// status decides how the status snapshots of the job are sent alongside its logs
+	Status               ListenRequestStatus `protobuf:"varint,7,opt,name=status,proto3,enum=v1.ListenRequestStatus" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *ListenRequest) Reset()         { *m = ListenRequest{} }
 func (m *ListenRequest) String() string { return proto.CompactTextString(m) }
 func (*ListenRequest) ProtoMessage()    {}
 func (*ListenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{13}
+	return fileDescriptor_9fe744feedd6d332, []int{14}
 }
 
 func (m *ListenRequest) XXX_Unmarshal(b []byte) error {
@@ -957,10 +1272,39 @@ func (m *ListenRequest) GetLogs() ListenRequestLogs {
 	return ListenRequestLogs_LOGS_DISABLED
 }
 
+func (m *ListenRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListenRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *ListenRequest) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ListenRequest) GetStatus() ListenRequestStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ListenRequestStatus_STATUS_LOG
+}
+
 type ListenResponse struct {
 	// Types that are valid to be assigned to Content:
 	//	*ListenResponse_Update
 	//	*ListenResponse_Slice
+	//	*ListenResponse_Status
 	Content              isListenResponse_Content `protobuf_oneof:"content"`
 	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
 	XXX_unrecognized     []byte                   `json:"-"`
@@ -971,7 +1315,7 @@ func (m *ListenResponse) Reset()         { *m = ListenResponse{} }
 func (m *ListenResponse) String() string { return proto.CompactTextString(m) }
 func (*ListenResponse) ProtoMessage()    {}
 func (*ListenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{14}
+	return fileDescriptor_9fe744feedd6d332, []int{15}
 }
 
 func (m *ListenResponse) XXX_Unmarshal(b []byte) error {
@@ -1004,10 +1348,16 @@ type ListenResponse_Slice struct {
 	Slice *LogSliceEvent `protobuf:"bytes,2,opt,name=slice,proto3,oneof"`
 }
 
+type ListenResponse_Status struct {
+	Status *JobStatusSnapshot `protobuf:"bytes,3,opt,name=status,proto3,oneof"`
+}
+
 func (*ListenResponse_Update) isListenResponse_Content() {}
 
 func (*ListenResponse_Slice) isListenResponse_Content() {}
 
+func (*ListenResponse_Status) isListenResponse_Content() {}
+
 func (m *ListenResponse) GetContent() isListenResponse_Content {
 	if m != nil {
 		return m.Content
@@ -1029,31 +1379,105 @@ func (m *ListenResponse) GetSlice() *LogSliceEvent {
 	return nil
 }
 
+func (m *ListenResponse) GetStatus() *JobStatusSnapshot {
+	if x, ok := m.GetContent().(*ListenResponse_Status); ok {
+		return x.Status
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*ListenResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*ListenResponse_Update)(nil),
 		(*ListenResponse_Slice)(nil),
+		(*ListenResponse_Status)(nil),
+	}
+}
+
+// JobStatusSnapshot is the status of a job at some point in time
+type JobStatusSnapshot struct {
+	Time                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Status               *JobStatus           `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *JobStatusSnapshot) Reset()         { *m = JobStatusSnapshot{} }
+func (m *JobStatusSnapshot) String() string { return proto.CompactTextString(m) }
+func (*JobStatusSnapshot) ProtoMessage()    {}
+func (*JobStatusSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{16}
+}
+
+func (m *JobStatusSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobStatusSnapshot.Unmarshal(m, b)
+}
+func (m *JobStatusSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobStatusSnapshot.Marshal(b, m, deterministic)
+}
+func (m *JobStatusSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobStatusSnapshot.Merge(m, src)
+}
+func (m *JobStatusSnapshot) XXX_Size() int {
+	return xxx_messageInfo_JobStatusSnapshot.Size(m)
+}
+func (m *JobStatusSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobStatusSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobStatusSnapshot proto.InternalMessageInfo
+
+func (m *JobStatusSnapshot) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *JobStatusSnapshot) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
 	}
+	return nil
 }
 
 type JobStatus struct {
-	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Metadata             *JobMetadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	Phase                JobPhase       `protobuf:"varint,3,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
-	Conditions           *JobConditions `protobuf:"bytes,4,opt,name=conditions,proto3" json:"conditions,omitempty"`
-	Details              string         `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
-	Results              []*JobResult   `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Name       string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata   *JobMetadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Phase      JobPhase       `protobuf:"varint,3,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
+	Conditions *JobConditions `protobuf:"bytes,4,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Details    string         `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	Results    []*JobResult   `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	// progress is the last progress the job reported using a PROGRESS log slice
+	Progress *JobProgress `protobuf:"bytes,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	// steps are the phases the job went through, as reported using PHASE log slices
+	Steps []*JobStep `protobuf:"bytes,8,rep,name=steps,proto3" json:"steps,omitempty"`
+	// estimated_finish is the time an unfinished job is expected to finish based on previous runs of the same job
+	EstimatedFinish *timestamp.Timestamp `protobuf:"bytes,9,opt,name=estimated_finish,json=estimatedFinish,proto3" json:"estimated_finish,omitempty"`
+	// cost is the approximate cost of a finished job based on the resources its pod requested and its runtime
+	Cost *JobCost `protobuf:"bytes,10,opt,name=cost,proto3" json:"cost,omitempty"`
+	// workspace_usage_bytes is the disk space the workspace of the job used, or zero if unknown
+	WorkspaceUsageBytes int64 `protobuf:"varint,11,opt,name=workspace_usage_bytes,json=workspaceUsageBytes,proto3" json:"workspace_usage_bytes,omitempty"`
+	// timestamps are the times the job entered its phases, s.t. its duration can be broken down into queue, preparation and run time
+	Timestamps *JobTimestamps `protobuf:"bytes,12,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
+	// cleanup is the outcome of removing the workspace of the job from its node, if the workspace lived there
+	Cleanup *JobCleanup `protobuf:"bytes,13,opt,name=cleanup,proto3" json:"cleanup,omitempty"`
+	// failed_slices are the log slices which failed, in the order they failed. Links can point to them in the job view.
+	FailedSlices []*LogAnchor `protobuf:"bytes,14,rep,name=failed_slices,json=failedSlices,proto3" json:"failed_slices,omitempty"`
+	// environment fingerprints the environment the job ran in, e.g. to find out why a job behaves differently on rerun
+	Environment          *JobEnvironment `protobuf:"bytes,15,opt,name=environment,proto3" json:"environment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *JobStatus) Reset()         { *m = JobStatus{} }
 func (m *JobStatus) String() string { return proto.CompactTextString(m) }
 func (*JobStatus) ProtoMessage()    {}
 func (*JobStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{15}
+	return fileDescriptor_9fe744feedd6d332, []int{17}
 }
 
 func (m *JobStatus) XXX_Unmarshal(b []byte) error {
@@ -1116,456 +1540,4185 @@ func (m *JobStatus) GetResults() []*JobResult {
 	return nil
 }
 
-type JobMetadata struct {
-	Owner                string               `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
-	Repository           *Repository          `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
-	Trigger              JobTrigger           `protobuf:"varint,3,opt,name=trigger,proto3,enum=v1.JobTrigger" json:"trigger,omitempty"`
-	Created              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
-	Finished             *timestamp.Timestamp `protobuf:"bytes,5,opt,name=finished,proto3" json:"finished,omitempty"`
-	Annotations          []*Annotation        `protobuf:"bytes,6,rep,name=annotations,proto3" json:"annotations,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+func (m *JobStatus) GetProgress() *JobProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
 }
 
-func (m *JobMetadata) Reset()         { *m = JobMetadata{} }
-func (m *JobMetadata) String() string { return proto.CompactTextString(m) }
-func (*JobMetadata) ProtoMessage()    {}
-func (*JobMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{16}
+func (m *JobStatus) GetSteps() []*JobStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
 }
 
-func (m *JobMetadata) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobMetadata.Unmarshal(m, b)
-}
-func (m *JobMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobMetadata.Marshal(b, m, deterministic)
-}
-func (m *JobMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobMetadata.Merge(m, src)
-}
-func (m *JobMetadata) XXX_Size() int {
-	return xxx_messageInfo_JobMetadata.Size(m)
-}
-func (m *JobMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobMetadata.DiscardUnknown(m)
+func (m *JobStatus) GetEstimatedFinish() *timestamp.Timestamp {
+	if m != nil {
+		return m.EstimatedFinish
+	}
+	return nil
 }
 
-var xxx_messageInfo_JobMetadata proto.InternalMessageInfo
-
-func (m *JobMetadata) GetOwner() string {
+func (m *JobStatus) GetCost() *JobCost {
 	if m != nil {
-		return m.Owner
+		return m.Cost
 	}
-	return ""
+	return nil
 }
 
-func (m *JobMetadata) GetRepository() *Repository {
+func (m *JobStatus) GetWorkspaceUsageBytes() int64 {
 	if m != nil {
-		return m.Repository
+		return m.WorkspaceUsageBytes
 	}
-	return nil
+	return 0
 }
 
-func (m *JobMetadata) GetTrigger() JobTrigger {
+func (m *JobStatus) GetTimestamps() *JobTimestamps {
 	if m != nil {
-		return m.Trigger
+		return m.Timestamps
 	}
-	return JobTrigger_TRIGGER_UNKNOWN
+	return nil
 }
 
-func (m *JobMetadata) GetCreated() *timestamp.Timestamp {
+func (m *JobStatus) GetCleanup() *JobCleanup {
 	if m != nil {
-		return m.Created
+		return m.Cleanup
 	}
 	return nil
 }
 
-func (m *JobMetadata) GetFinished() *timestamp.Timestamp {
+func (m *JobStatus) GetFailedSlices() []*LogAnchor {
 	if m != nil {
-		return m.Finished
+		return m.FailedSlices
 	}
 	return nil
 }
 
-func (m *JobMetadata) GetAnnotations() []*Annotation {
+func (m *JobStatus) GetEnvironment() *JobEnvironment {
 	if m != nil {
-		return m.Annotations
+		return m.Environment
 	}
 	return nil
 }
 
-type Repository struct {
-	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	Repo                 string   `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
-	Ref                  string   `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
-	Revision             string   `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+// JobEnvironment describes the images, node and cluster a job ran on
+type JobEnvironment struct {
+	// images are the images the containers of the job ran
+	Images []*ContainerImage `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	// node is the name of the node the job ran on
+	Node string `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	// os_image is the operating system of the node, e.g. Ubuntu 18.04.4 LTS
+	OsImage       string `protobuf:"bytes,3,opt,name=os_image,json=osImage,proto3" json:"os_image,omitempty"`
+	KernelVersion string `protobuf:"bytes,4,opt,name=kernel_version,json=kernelVersion,proto3" json:"kernel_version,omitempty"`
+	Architecture  string `protobuf:"bytes,5,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	// container_runtime_version is the container runtime of the node, e.g. containerd://1.3.3
+	ContainerRuntimeVersion string `protobuf:"bytes,6,opt,name=container_runtime_version,json=containerRuntimeVersion,proto3" json:"container_runtime_version,omitempty"`
+	KubeletVersion          string `protobuf:"bytes,7,opt,name=kubelet_version,json=kubeletVersion,proto3" json:"kubelet_version,omitempty"`
+	// kubernetes_version is the version of the Kubernetes API server when the job was scheduled
+	KubernetesVersion    string   `protobuf:"bytes,8,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetes_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Repository) Reset()         { *m = Repository{} }
-func (m *Repository) String() string { return proto.CompactTextString(m) }
-func (*Repository) ProtoMessage()    {}
-func (*Repository) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{17}
+func (m *JobEnvironment) Reset()         { *m = JobEnvironment{} }
+func (m *JobEnvironment) String() string { return proto.CompactTextString(m) }
+func (*JobEnvironment) ProtoMessage()    {}
+func (*JobEnvironment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{18}
 }
 
-func (m *Repository) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Repository.Unmarshal(m, b)
+func (m *JobEnvironment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobEnvironment.Unmarshal(m, b)
 }
-func (m *Repository) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Repository.Marshal(b, m, deterministic)
+func (m *JobEnvironment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobEnvironment.Marshal(b, m, deterministic)
 }
-func (m *Repository) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Repository.Merge(m, src)
+func (m *JobEnvironment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobEnvironment.Merge(m, src)
 }
-func (m *Repository) XXX_Size() int {
-	return xxx_messageInfo_Repository.Size(m)
+func (m *JobEnvironment) XXX_Size() int {
+	return xxx_messageInfo_JobEnvironment.Size(m)
 }
-func (m *Repository) XXX_DiscardUnknown() {
-	xxx_messageInfo_Repository.DiscardUnknown(m)
+func (m *JobEnvironment) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobEnvironment.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Repository proto.InternalMessageInfo
+var xxx_messageInfo_JobEnvironment proto.InternalMessageInfo
 
-func (m *Repository) GetHost() string {
+func (m *JobEnvironment) GetImages() []*ContainerImage {
 	if m != nil {
-		return m.Host
+		return m.Images
+	}
+	return nil
+}
+
+func (m *JobEnvironment) GetNode() string {
+	if m != nil {
+		return m.Node
 	}
 	return ""
 }
 
-func (m *Repository) GetOwner() string {
+func (m *JobEnvironment) GetOsImage() string {
 	if m != nil {
-		return m.Owner
+		return m.OsImage
 	}
 	return ""
 }
 
-func (m *Repository) GetRepo() string {
+func (m *JobEnvironment) GetKernelVersion() string {
 	if m != nil {
-		return m.Repo
+		return m.KernelVersion
 	}
 	return ""
 }
 
-func (m *Repository) GetRef() string {
+func (m *JobEnvironment) GetArchitecture() string {
 	if m != nil {
-		return m.Ref
+		return m.Architecture
 	}
 	return ""
 }
 
-func (m *Repository) GetRevision() string {
+func (m *JobEnvironment) GetContainerRuntimeVersion() string {
 	if m != nil {
-		return m.Revision
+		return m.ContainerRuntimeVersion
 	}
 	return ""
 }
 
-type Annotation struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+func (m *JobEnvironment) GetKubeletVersion() string {
+	if m != nil {
+		return m.KubeletVersion
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetKubernetesVersion() string {
+	if m != nil {
+		return m.KubernetesVersion
+	}
+	return ""
+}
+
+type ContainerImage struct {
+	Container string `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	// image is the image as the job spec refers to it, e.g. golang:1.14
+	Image string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// image_id identifies the image the container actually ran, usually by its digest
+	ImageId              string   `protobuf:"bytes,3,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Annotation) Reset()         { *m = Annotation{} }
-func (m *Annotation) String() string { return proto.CompactTextString(m) }
-func (*Annotation) ProtoMessage()    {}
-func (*Annotation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{18}
+func (m *ContainerImage) Reset()         { *m = ContainerImage{} }
+func (m *ContainerImage) String() string { return proto.CompactTextString(m) }
+func (*ContainerImage) ProtoMessage()    {}
+func (*ContainerImage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{19}
 }
 
-func (m *Annotation) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Annotation.Unmarshal(m, b)
+func (m *ContainerImage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerImage.Unmarshal(m, b)
 }
-func (m *Annotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Annotation.Marshal(b, m, deterministic)
+func (m *ContainerImage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerImage.Marshal(b, m, deterministic)
 }
-func (m *Annotation) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Annotation.Merge(m, src)
+func (m *ContainerImage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerImage.Merge(m, src)
 }
-func (m *Annotation) XXX_Size() int {
-	return xxx_messageInfo_Annotation.Size(m)
+func (m *ContainerImage) XXX_Size() int {
+	return xxx_messageInfo_ContainerImage.Size(m)
 }
-func (m *Annotation) XXX_DiscardUnknown() {
-	xxx_messageInfo_Annotation.DiscardUnknown(m)
+func (m *ContainerImage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerImage.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Annotation proto.InternalMessageInfo
+var xxx_messageInfo_ContainerImage proto.InternalMessageInfo
 
-func (m *Annotation) GetKey() string {
+func (m *ContainerImage) GetContainer() string {
 	if m != nil {
-		return m.Key
+		return m.Container
 	}
 	return ""
 }
 
-func (m *Annotation) GetValue() string {
+func (m *ContainerImage) GetImage() string {
 	if m != nil {
-		return m.Value
+		return m.Image
 	}
 	return ""
 }
 
-type JobConditions struct {
-	Success              bool     `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	FailureCount         int32    `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
-	CanReplay            bool     `protobuf:"varint,3,opt,name=can_replay,json=canReplay,proto3" json:"can_replay,omitempty"`
+func (m *ContainerImage) GetImageId() string {
+	if m != nil {
+		return m.ImageId
+	}
+	return ""
+}
+
+// LogAnchor identifies a slice of a job log. The job view uses phase:slice as anchor of the slice, and phase:<phase> as
+// anchor of the phase itself.
+type LogAnchor struct {
+	// phase is the phase the slice was written in
+	Phase                string   `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Slice                string   `protobuf:"bytes,2,opt,name=slice,proto3" json:"slice,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *JobConditions) Reset()         { *m = JobConditions{} }
-func (m *JobConditions) String() string { return proto.CompactTextString(m) }
-func (*JobConditions) ProtoMessage()    {}
-func (*JobConditions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{19}
+func (m *LogAnchor) Reset()         { *m = LogAnchor{} }
+func (m *LogAnchor) String() string { return proto.CompactTextString(m) }
+func (*LogAnchor) ProtoMessage()    {}
+func (*LogAnchor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{20}
 }
 
-func (m *JobConditions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobConditions.Unmarshal(m, b)
+func (m *LogAnchor) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogAnchor.Unmarshal(m, b)
 }
-func (m *JobConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobConditions.Marshal(b, m, deterministic)
+func (m *LogAnchor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogAnchor.Marshal(b, m, deterministic)
 }
-func (m *JobConditions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobConditions.Merge(m, src)
+func (m *LogAnchor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogAnchor.Merge(m, src)
 }
-func (m *JobConditions) XXX_Size() int {
-	return xxx_messageInfo_JobConditions.Size(m)
+func (m *LogAnchor) XXX_Size() int {
+	return xxx_messageInfo_LogAnchor.Size(m)
 }
-func (m *JobConditions) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobConditions.DiscardUnknown(m)
+func (m *LogAnchor) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogAnchor.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JobConditions proto.InternalMessageInfo
-
-func (m *JobConditions) GetSuccess() bool {
-	if m != nil {
-		return m.Success
-	}
-	return false
-}
+var xxx_messageInfo_LogAnchor proto.InternalMessageInfo
 
-func (m *JobConditions) GetFailureCount() int32 {
+func (m *LogAnchor) GetPhase() string {
 	if m != nil {
-		return m.FailureCount
+		return m.Phase
 	}
-	return 0
+	return ""
 }
 
-func (m *JobConditions) GetCanReplay() bool {
+func (m *LogAnchor) GetSlice() string {
 	if m != nil {
-		return m.CanReplay
+		return m.Slice
 	}
-	return false
+	return ""
 }
 
-type JobResult struct {
-	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Payload              string   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
-	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Channels             []string `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+type JobCleanup struct {
+	State CleanupState `protobuf:"varint,1,opt,name=state,proto3,enum=v1.CleanupState" json:"state,omitempty"`
+	// attempts is the number of cleanup jobs started for the job so far
+	Attempts int32 `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// node is the node the workspace lives on
+	Node string `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	// details describes why the last attempt failed
+	Details              string   `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *JobResult) Reset()         { *m = JobResult{} }
-func (m *JobResult) String() string { return proto.CompactTextString(m) }
-func (*JobResult) ProtoMessage()    {}
-func (*JobResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{20}
+func (m *JobCleanup) Reset()         { *m = JobCleanup{} }
+func (m *JobCleanup) String() string { return proto.CompactTextString(m) }
+func (*JobCleanup) ProtoMessage()    {}
+func (*JobCleanup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{21}
 }
 
-func (m *JobResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobResult.Unmarshal(m, b)
+func (m *JobCleanup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobCleanup.Unmarshal(m, b)
 }
-func (m *JobResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobResult.Marshal(b, m, deterministic)
+func (m *JobCleanup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobCleanup.Marshal(b, m, deterministic)
 }
-func (m *JobResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobResult.Merge(m, src)
+func (m *JobCleanup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobCleanup.Merge(m, src)
 }
-func (m *JobResult) XXX_Size() int {
-	return xxx_messageInfo_JobResult.Size(m)
+func (m *JobCleanup) XXX_Size() int {
+	return xxx_messageInfo_JobCleanup.Size(m)
 }
-func (m *JobResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobResult.DiscardUnknown(m)
+func (m *JobCleanup) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobCleanup.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JobResult proto.InternalMessageInfo
+var xxx_messageInfo_JobCleanup proto.InternalMessageInfo
 
-func (m *JobResult) GetType() string {
+func (m *JobCleanup) GetState() CleanupState {
 	if m != nil {
-		return m.Type
+		return m.State
 	}
-	return ""
+	return CleanupState_CLEANUP_PENDING
 }
 
-func (m *JobResult) GetPayload() string {
+func (m *JobCleanup) GetAttempts() int32 {
 	if m != nil {
-		return m.Payload
+		return m.Attempts
 	}
-	return ""
+	return 0
 }
 
-func (m *JobResult) GetDescription() string {
+func (m *JobCleanup) GetNode() string {
 	if m != nil {
-		return m.Description
+		return m.Node
 	}
 	return ""
 }
 
-func (m *JobResult) GetChannels() []string {
+func (m *JobCleanup) GetDetails() string {
 	if m != nil {
-		return m.Channels
+		return m.Details
 	}
-	return nil
+	return ""
 }
 
-type LogSliceEvent struct {
-	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type                 LogSliceType `protobuf:"varint,2,opt,name=type,proto3,enum=v1.LogSliceType" json:"type,omitempty"`
-	Payload              string       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+type JobTimestamps struct {
+	// queued is the time the job was created and waited to be scheduled
+	Queued *timestamp.Timestamp `protobuf:"bytes,1,opt,name=queued,proto3" json:"queued,omitempty"`
+	// preparing is the time the job was scheduled onto a node and began to prepare its workspace
+	Preparing *timestamp.Timestamp `protobuf:"bytes,2,opt,name=preparing,proto3" json:"preparing,omitempty"`
+	// running is the time the job's main container started
+	Running *timestamp.Timestamp `protobuf:"bytes,3,opt,name=running,proto3" json:"running,omitempty"`
+	// finished is the time the job finished
+	Finished             *timestamp.Timestamp `protobuf:"bytes,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *LogSliceEvent) Reset()         { *m = LogSliceEvent{} }
-func (m *LogSliceEvent) String() string { return proto.CompactTextString(m) }
-func (*LogSliceEvent) ProtoMessage()    {}
-func (*LogSliceEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{21}
+func (m *JobTimestamps) Reset()         { *m = JobTimestamps{} }
+func (m *JobTimestamps) String() string { return proto.CompactTextString(m) }
+func (*JobTimestamps) ProtoMessage()    {}
+func (*JobTimestamps) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{22}
 }
 
-func (m *LogSliceEvent) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogSliceEvent.Unmarshal(m, b)
+func (m *JobTimestamps) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobTimestamps.Unmarshal(m, b)
 }
-func (m *LogSliceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogSliceEvent.Marshal(b, m, deterministic)
+func (m *JobTimestamps) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobTimestamps.Marshal(b, m, deterministic)
 }
-func (m *LogSliceEvent) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogSliceEvent.Merge(m, src)
+func (m *JobTimestamps) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobTimestamps.Merge(m, src)
 }
-func (m *LogSliceEvent) XXX_Size() int {
-	return xxx_messageInfo_LogSliceEvent.Size(m)
+func (m *JobTimestamps) XXX_Size() int {
+	return xxx_messageInfo_JobTimestamps.Size(m)
 }
-func (m *LogSliceEvent) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogSliceEvent.DiscardUnknown(m)
+func (m *JobTimestamps) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobTimestamps.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LogSliceEvent proto.InternalMessageInfo
+var xxx_messageInfo_JobTimestamps proto.InternalMessageInfo
 
-func (m *LogSliceEvent) GetName() string {
+func (m *JobTimestamps) GetQueued() *timestamp.Timestamp {
 	if m != nil {
-		return m.Name
+		return m.Queued
 	}
-	return ""
+	return nil
 }
 
-func (m *LogSliceEvent) GetType() LogSliceType {
+func (m *JobTimestamps) GetPreparing() *timestamp.Timestamp {
 	if m != nil {
-		return m.Type
+		return m.Preparing
 	}
-	return LogSliceType_SLICE_ABANDONED
+	return nil
 }
 
-func (m *LogSliceEvent) GetPayload() string {
+func (m *JobTimestamps) GetRunning() *timestamp.Timestamp {
 	if m != nil {
-		return m.Payload
+		return m.Running
 	}
-	return ""
+	return nil
 }
 
-type StopJobRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+func (m *JobTimestamps) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
+}
+
+type JobCost struct {
+	// cpu_hours is the number of CPUs the job requested multiplied by its runtime in hours
+	CpuHours float64 `protobuf:"fixed64,1,opt,name=cpu_hours,json=cpuHours,proto3" json:"cpu_hours,omitempty"`
+	// memory_gb_hours is the memory the job requested in GB multiplied by its runtime in hours
+	MemoryGbHours float64 `protobuf:"fixed64,2,opt,name=memory_gb_hours,json=memoryGbHours,proto3" json:"memory_gb_hours,omitempty"`
+	// amount is the cost of the job based on the rates configured on the server
+	Amount               float64  `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency             string   `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StopJobRequest) Reset()         { *m = StopJobRequest{} }
-func (m *StopJobRequest) String() string { return proto.CompactTextString(m) }
-func (*StopJobRequest) ProtoMessage()    {}
-func (*StopJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{22}
+func (m *JobCost) Reset()         { *m = JobCost{} }
+func (m *JobCost) String() string { return proto.CompactTextString(m) }
+func (*JobCost) ProtoMessage()    {}
+func (*JobCost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{23}
 }
 
-func (m *StopJobRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StopJobRequest.Unmarshal(m, b)
+func (m *JobCost) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobCost.Unmarshal(m, b)
 }
-func (m *StopJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StopJobRequest.Marshal(b, m, deterministic)
+func (m *JobCost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobCost.Marshal(b, m, deterministic)
 }
-func (m *StopJobRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StopJobRequest.Merge(m, src)
+func (m *JobCost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobCost.Merge(m, src)
 }
-func (m *StopJobRequest) XXX_Size() int {
-	return xxx_messageInfo_StopJobRequest.Size(m)
+func (m *JobCost) XXX_Size() int {
+	return xxx_messageInfo_JobCost.Size(m)
 }
-func (m *StopJobRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_StopJobRequest.DiscardUnknown(m)
+func (m *JobCost) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobCost.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StopJobRequest proto.InternalMessageInfo
+var xxx_messageInfo_JobCost proto.InternalMessageInfo
 
-func (m *StopJobRequest) GetName() string {
+func (m *JobCost) GetCpuHours() float64 {
 	if m != nil {
-		return m.Name
+		return m.CpuHours
 	}
-	return ""
+	return 0
 }
 
-type StopJobResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *JobCost) GetMemoryGbHours() float64 {
+	if m != nil {
+		return m.MemoryGbHours
+	}
+	return 0
 }
 
-func (m *StopJobResponse) Reset()         { *m = StopJobResponse{} }
-func (m *StopJobResponse) String() string { return proto.CompactTextString(m) }
-func (*StopJobResponse) ProtoMessage()    {}
-func (*StopJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{23}
+func (m *JobCost) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
 }
 
-func (m *StopJobResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StopJobResponse.Unmarshal(m, b)
+func (m *JobCost) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+type JobStep struct {
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Started  *timestamp.Timestamp `protobuf:"bytes,2,opt,name=started,proto3" json:"started,omitempty"`
+	Finished *timestamp.Timestamp `protobuf:"bytes,3,opt,name=finished,proto3" json:"finished,omitempty"`
+	// outcome is known for the steps of the job spec only, not for the phases a job reports in its log
+	Outcome              StepOutcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=v1.StepOutcome" json:"outcome,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *JobStep) Reset()         { *m = JobStep{} }
+func (m *JobStep) String() string { return proto.CompactTextString(m) }
+func (*JobStep) ProtoMessage()    {}
+func (*JobStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{24}
+}
+
+func (m *JobStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobStep.Unmarshal(m, b)
+}
+func (m *JobStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobStep.Marshal(b, m, deterministic)
+}
+func (m *JobStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobStep.Merge(m, src)
+}
+func (m *JobStep) XXX_Size() int {
+	return xxx_messageInfo_JobStep.Size(m)
+}
+func (m *JobStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobStep proto.InternalMessageInfo
+
+func (m *JobStep) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JobStep) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *JobStep) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
+}
+
+func (m *JobStep) GetOutcome() StepOutcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return StepOutcome_STEP_UNKNOWN
+}
+
+type JobProgress struct {
+	// slice is the name of the log slice which reported the progress
+	Slice string `protobuf:"bytes,1,opt,name=slice,proto3" json:"slice,omitempty"`
+	// percent is the overall progress between 0 and 100
+	Percent int32 `protobuf:"varint,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	// step and total_steps are set if the progress was reported in steps, e.g. 3/10
+	Step                 int32    `protobuf:"varint,3,opt,name=step,proto3" json:"step,omitempty"`
+	TotalSteps           int32    `protobuf:"varint,4,opt,name=total_steps,json=totalSteps,proto3" json:"total_steps,omitempty"`
+	Description          string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobProgress) Reset()         { *m = JobProgress{} }
+func (m *JobProgress) String() string { return proto.CompactTextString(m) }
+func (*JobProgress) ProtoMessage()    {}
+func (*JobProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{25}
+}
+
+func (m *JobProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobProgress.Unmarshal(m, b)
+}
+func (m *JobProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobProgress.Marshal(b, m, deterministic)
+}
+func (m *JobProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobProgress.Merge(m, src)
+}
+func (m *JobProgress) XXX_Size() int {
+	return xxx_messageInfo_JobProgress.Size(m)
+}
+func (m *JobProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobProgress proto.InternalMessageInfo
+
+func (m *JobProgress) GetSlice() string {
+	if m != nil {
+		return m.Slice
+	}
+	return ""
+}
+
+func (m *JobProgress) GetPercent() int32 {
+	if m != nil {
+		return m.Percent
+	}
+	return 0
+}
+
+func (m *JobProgress) GetStep() int32 {
+	if m != nil {
+		return m.Step
+	}
+	return 0
+}
+
+func (m *JobProgress) GetTotalSteps() int32 {
+	if m != nil {
+		return m.TotalSteps
+	}
+	return 0
+}
+
+func (m *JobProgress) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type JobMetadata struct {
+	Owner       string               `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repository  *Repository          `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	Trigger     JobTrigger           `protobuf:"varint,3,opt,name=trigger,proto3,enum=v1.JobTrigger" json:"trigger,omitempty"`
+	Created     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	Finished    *timestamp.Timestamp `protobuf:"bytes,5,opt,name=finished,proto3" json:"finished,omitempty"`
+	Annotations []*Annotation        `protobuf:"bytes,6,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	// started is the time the pod of the job started on a node. The time between created and started is the time the job was queued.
+	Started *timestamp.Timestamp `protobuf:"bytes,7,opt,name=started,proto3" json:"started,omitempty"`
+	// triggered_by names the token which started the job on behalf of its owner, e.g. the token of a bot.
+	// It is empty if the owner started the job themselves.
+	TriggeredBy string `protobuf:"bytes,8,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	// event describes the webhook event which started the job. It is empty for jobs which were not started by a webhook.
+	Event *JobEvent `protobuf:"bytes,9,opt,name=event,proto3" json:"event,omitempty"`
+	// number counts the jobs of the repository, starting at one. Unlike the job name it does not depend on the
+	// job spec or branch, which makes it a human-friendly build number.
+	Number int32 `protobuf:"varint,10,opt,name=number,proto3" json:"number,omitempty"`
+	// job_spec is the name of the job spec the job runs, e.g. build for .werft/build.yaml. It is empty for jobs which
+	// brought their own job spec.
+	JobSpec              string   `protobuf:"bytes,11,opt,name=job_spec,json=jobSpec,proto3" json:"job_spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobMetadata) Reset()         { *m = JobMetadata{} }
+func (m *JobMetadata) String() string { return proto.CompactTextString(m) }
+func (*JobMetadata) ProtoMessage()    {}
+func (*JobMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{26}
+}
+
+func (m *JobMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobMetadata.Unmarshal(m, b)
+}
+func (m *JobMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobMetadata.Marshal(b, m, deterministic)
+}
+func (m *JobMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobMetadata.Merge(m, src)
+}
+func (m *JobMetadata) XXX_Size() int {
+	return xxx_messageInfo_JobMetadata.Size(m)
+}
+func (m *JobMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobMetadata proto.InternalMessageInfo
+
+func (m *JobMetadata) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *JobMetadata) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetTrigger() JobTrigger {
+	if m != nil {
+		return m.Trigger
+	}
+	return JobTrigger_TRIGGER_UNKNOWN
+}
+
+func (m *JobMetadata) GetCreated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetAnnotations() []*Annotation {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetTriggeredBy() string {
+	if m != nil {
+		return m.TriggeredBy
+	}
+	return ""
+}
+
+func (m *JobMetadata) GetEvent() *JobEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *JobMetadata) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *JobMetadata) GetJobSpec() string {
+	if m != nil {
+		return m.JobSpec
+	}
+	return ""
+}
+
+// JobEvent are the fields of a webhook event job specs can make decisions on
+type JobEvent struct {
+	// type is the type of the event, e.g. push or pull_request
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// pull_request is the number of the pull request the job runs for, or zero if there is none
+	PullRequest int32 `protobuf:"varint,2,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	Draft       bool  `protobuf:"varint,3,opt,name=draft,proto3" json:"draft,omitempty"`
+	// labels are the labels of the pull request
+	Labels []string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty"`
+	// base_branch is the branch the pull request is to be merged into, e.g. main
+	BaseBranch string `protobuf:"bytes,5,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	// commit_message is the message of the head commit of a push
+	CommitMessage        string   `protobuf:"bytes,6,opt,name=commit_message,json=commitMessage,proto3" json:"commit_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobEvent) Reset()         { *m = JobEvent{} }
+func (m *JobEvent) String() string { return proto.CompactTextString(m) }
+func (*JobEvent) ProtoMessage()    {}
+func (*JobEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{27}
+}
+
+func (m *JobEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobEvent.Unmarshal(m, b)
+}
+func (m *JobEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobEvent.Marshal(b, m, deterministic)
+}
+func (m *JobEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobEvent.Merge(m, src)
+}
+func (m *JobEvent) XXX_Size() int {
+	return xxx_messageInfo_JobEvent.Size(m)
+}
+func (m *JobEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobEvent proto.InternalMessageInfo
+
+func (m *JobEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *JobEvent) GetPullRequest() int32 {
+	if m != nil {
+		return m.PullRequest
+	}
+	return 0
+}
+
+func (m *JobEvent) GetDraft() bool {
+	if m != nil {
+		return m.Draft
+	}
+	return false
+}
+
+func (m *JobEvent) GetLabels() []string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *JobEvent) GetBaseBranch() string {
+	if m != nil {
+		return m.BaseBranch
+	}
+	return ""
+}
+
+func (m *JobEvent) GetCommitMessage() string {
+	if m != nil {
+		return m.CommitMessage
+	}
+	return ""
+}
+
+type Repository struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repo                 string   `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	Ref                  string   `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
+	Revision             string   `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Repository) Reset()         { *m = Repository{} }
+func (m *Repository) String() string { return proto.CompactTextString(m) }
+func (*Repository) ProtoMessage()    {}
+func (*Repository) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{28}
+}
+
+func (m *Repository) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Repository.Unmarshal(m, b)
+}
+func (m *Repository) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Repository.Marshal(b, m, deterministic)
+}
+func (m *Repository) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Repository.Merge(m, src)
+}
+func (m *Repository) XXX_Size() int {
+	return xxx_messageInfo_Repository.Size(m)
+}
+func (m *Repository) XXX_DiscardUnknown() {
+	xxx_messageInfo_Repository.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Repository proto.InternalMessageInfo
+
+func (m *Repository) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Repository) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Repository) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *Repository) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *Repository) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type Annotation struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Annotation) Reset()         { *m = Annotation{} }
+func (m *Annotation) String() string { return proto.CompactTextString(m) }
+func (*Annotation) ProtoMessage()    {}
+func (*Annotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{29}
+}
+
+func (m *Annotation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Annotation.Unmarshal(m, b)
+}
+func (m *Annotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Annotation.Marshal(b, m, deterministic)
+}
+func (m *Annotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Annotation.Merge(m, src)
+}
+func (m *Annotation) XXX_Size() int {
+	return xxx_messageInfo_Annotation.Size(m)
+}
+func (m *Annotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Annotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Annotation proto.InternalMessageInfo
+
+func (m *Annotation) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Annotation) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type JobConditions struct {
+	Success      bool  `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	FailureCount int32 `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	CanReplay    bool  `protobuf:"varint,3,opt,name=can_replay,json=canReplay,proto3" json:"can_replay,omitempty"`
+	// log_truncated is true if the job produced more log output than werft was configured to keep
+	LogTruncated bool `protobuf:"varint,4,opt,name=log_truncated,json=logTruncated,proto3" json:"log_truncated,omitempty"`
+	// failure_class classifies why a job failed
+	FailureClass JobFailureClass `protobuf:"varint,5,opt,name=failure_class,json=failureClass,proto3,enum=v1.JobFailureClass" json:"failure_class,omitempty"`
+	// archived is true if the log and job spec of the job were moved to the archive
+	Archived bool `protobuf:"varint,6,opt,name=archived,proto3" json:"archived,omitempty"`
+	// pinned is true if the job is exempt from pruning
+	Pinned               bool     `protobuf:"varint,7,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobConditions) Reset()         { *m = JobConditions{} }
+func (m *JobConditions) String() string { return proto.CompactTextString(m) }
+func (*JobConditions) ProtoMessage()    {}
+func (*JobConditions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{30}
+}
+
+func (m *JobConditions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobConditions.Unmarshal(m, b)
+}
+func (m *JobConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobConditions.Marshal(b, m, deterministic)
+}
+func (m *JobConditions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobConditions.Merge(m, src)
+}
+func (m *JobConditions) XXX_Size() int {
+	return xxx_messageInfo_JobConditions.Size(m)
+}
+func (m *JobConditions) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobConditions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobConditions proto.InternalMessageInfo
+
+func (m *JobConditions) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *JobConditions) GetFailureCount() int32 {
+	if m != nil {
+		return m.FailureCount
+	}
+	return 0
+}
+
+func (m *JobConditions) GetCanReplay() bool {
+	if m != nil {
+		return m.CanReplay
+	}
+	return false
+}
+
+func (m *JobConditions) GetLogTruncated() bool {
+	if m != nil {
+		return m.LogTruncated
+	}
+	return false
+}
+
+func (m *JobConditions) GetFailureClass() JobFailureClass {
+	if m != nil {
+		return m.FailureClass
+	}
+	return JobFailureClass_FAILURE_UNCLASSIFIED
+}
+
+func (m *JobConditions) GetArchived() bool {
+	if m != nil {
+		return m.Archived
+	}
+	return false
+}
+
+func (m *JobConditions) GetPinned() bool {
+	if m != nil {
+		return m.Pinned
+	}
+	return false
+}
+
+type JobResult struct {
+	Type        string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Payload     string   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Channels    []string `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	// section groups results on the UI. It's set when a result channel is routed to a UI section.
+	Section              string   `protobuf:"bytes,5,opt,name=section,proto3" json:"section,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobResult) Reset()         { *m = JobResult{} }
+func (m *JobResult) String() string { return proto.CompactTextString(m) }
+func (*JobResult) ProtoMessage()    {}
+func (*JobResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{31}
+}
+
+func (m *JobResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobResult.Unmarshal(m, b)
+}
+func (m *JobResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobResult.Marshal(b, m, deterministic)
+}
+func (m *JobResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobResult.Merge(m, src)
+}
+func (m *JobResult) XXX_Size() int {
+	return xxx_messageInfo_JobResult.Size(m)
+}
+func (m *JobResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobResult proto.InternalMessageInfo
+
+func (m *JobResult) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *JobResult) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *JobResult) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *JobResult) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *JobResult) GetSection() string {
+	if m != nil {
+		return m.Section
+	}
+	return ""
+}
+
+type LogSliceEvent struct {
+	Name    string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type    LogSliceType `protobuf:"varint,2,opt,name=type,proto3,enum=v1.LogSliceType" json:"type,omitempty"`
+	Payload string       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// cursor is an opaque token marking the position in the log after this event.
+	// Pass it to Listen to resume listening from this point on.
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// time is the time the log line this event stems from was written
+	Time *timestamp.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	// level is the log level of a structured (JSON) log line, e.g. info or error.
+	// Empty for unstructured content.
+	Level string `protobuf:"bytes,6,opt,name=level,proto3" json:"level,omitempty"`
+	// fields are the additional fields of a structured (JSON) log line
+	Fields               map[string]string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LogSliceEvent) Reset()         { *m = LogSliceEvent{} }
+func (m *LogSliceEvent) String() string { return proto.CompactTextString(m) }
+func (*LogSliceEvent) ProtoMessage()    {}
+func (*LogSliceEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{32}
+}
+
+func (m *LogSliceEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSliceEvent.Unmarshal(m, b)
+}
+func (m *LogSliceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSliceEvent.Marshal(b, m, deterministic)
+}
+func (m *LogSliceEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSliceEvent.Merge(m, src)
+}
+func (m *LogSliceEvent) XXX_Size() int {
+	return xxx_messageInfo_LogSliceEvent.Size(m)
+}
+func (m *LogSliceEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSliceEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSliceEvent proto.InternalMessageInfo
+
+func (m *LogSliceEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSliceEvent) GetType() LogSliceType {
+	if m != nil {
+		return m.Type
+	}
+	return LogSliceType_SLICE_ABANDONED
+}
+
+func (m *LogSliceEvent) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *LogSliceEvent) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *LogSliceEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *LogSliceEvent) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogSliceEvent) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+type StopJobRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopJobRequest) Reset()         { *m = StopJobRequest{} }
+func (m *StopJobRequest) String() string { return proto.CompactTextString(m) }
+func (*StopJobRequest) ProtoMessage()    {}
+func (*StopJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{33}
+}
+
+func (m *StopJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopJobRequest.Unmarshal(m, b)
+}
+func (m *StopJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopJobRequest.Marshal(b, m, deterministic)
+}
+func (m *StopJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopJobRequest.Merge(m, src)
+}
+func (m *StopJobRequest) XXX_Size() int {
+	return xxx_messageInfo_StopJobRequest.Size(m)
+}
+func (m *StopJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopJobRequest proto.InternalMessageInfo
+
+func (m *StopJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type StopJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopJobResponse) Reset()         { *m = StopJobResponse{} }
+func (m *StopJobResponse) String() string { return proto.CompactTextString(m) }
+func (*StopJobResponse) ProtoMessage()    {}
+func (*StopJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{34}
+}
+
+func (m *StopJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopJobResponse.Unmarshal(m, b)
 }
 func (m *StopJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_StopJobResponse.Marshal(b, m, deterministic)
 }
-func (m *StopJobResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StopJobResponse.Merge(m, src)
+func (m *StopJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopJobResponse.Merge(m, src)
+}
+func (m *StopJobResponse) XXX_Size() int {
+	return xxx_messageInfo_StopJobResponse.Size(m)
+}
+func (m *StopJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopJobResponse proto.InternalMessageInfo
+
+type AnnotateJobRequest struct {
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Annotations          []*Annotation `protobuf:"bytes,2,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AnnotateJobRequest) Reset()         { *m = AnnotateJobRequest{} }
+func (m *AnnotateJobRequest) String() string { return proto.CompactTextString(m) }
+func (*AnnotateJobRequest) ProtoMessage()    {}
+func (*AnnotateJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{35}
+}
+
+func (m *AnnotateJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnnotateJobRequest.Unmarshal(m, b)
+}
+func (m *AnnotateJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnnotateJobRequest.Marshal(b, m, deterministic)
+}
+func (m *AnnotateJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnnotateJobRequest.Merge(m, src)
+}
+func (m *AnnotateJobRequest) XXX_Size() int {
+	return xxx_messageInfo_AnnotateJobRequest.Size(m)
+}
+func (m *AnnotateJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnnotateJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnnotateJobRequest proto.InternalMessageInfo
+
+func (m *AnnotateJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AnnotateJobRequest) GetAnnotations() []*Annotation {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+type AnnotateJobResponse struct {
+	Status               *JobStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *AnnotateJobResponse) Reset()         { *m = AnnotateJobResponse{} }
+func (m *AnnotateJobResponse) String() string { return proto.CompactTextString(m) }
+func (*AnnotateJobResponse) ProtoMessage()    {}
+func (*AnnotateJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{36}
+}
+
+func (m *AnnotateJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnnotateJobResponse.Unmarshal(m, b)
+}
+func (m *AnnotateJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnnotateJobResponse.Marshal(b, m, deterministic)
+}
+func (m *AnnotateJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnnotateJobResponse.Merge(m, src)
+}
+func (m *AnnotateJobResponse) XXX_Size() int {
+	return xxx_messageInfo_AnnotateJobResponse.Size(m)
+}
+func (m *AnnotateJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnnotateJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnnotateJobResponse proto.InternalMessageInfo
+
+func (m *AnnotateJobResponse) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type PinJobRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// pinned is false to unpin the job
+	Pinned               bool     `protobuf:"varint,2,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PinJobRequest) Reset()         { *m = PinJobRequest{} }
+func (m *PinJobRequest) String() string { return proto.CompactTextString(m) }
+func (*PinJobRequest) ProtoMessage()    {}
+func (*PinJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{37}
+}
+
+func (m *PinJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinJobRequest.Unmarshal(m, b)
+}
+func (m *PinJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinJobRequest.Marshal(b, m, deterministic)
+}
+func (m *PinJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinJobRequest.Merge(m, src)
+}
+func (m *PinJobRequest) XXX_Size() int {
+	return xxx_messageInfo_PinJobRequest.Size(m)
+}
+func (m *PinJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinJobRequest proto.InternalMessageInfo
+
+func (m *PinJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PinJobRequest) GetPinned() bool {
+	if m != nil {
+		return m.Pinned
+	}
+	return false
+}
+
+type PinJobResponse struct {
+	Status               *JobStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *PinJobResponse) Reset()         { *m = PinJobResponse{} }
+func (m *PinJobResponse) String() string { return proto.CompactTextString(m) }
+func (*PinJobResponse) ProtoMessage()    {}
+func (*PinJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{38}
+}
+
+func (m *PinJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinJobResponse.Unmarshal(m, b)
+}
+func (m *PinJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinJobResponse.Marshal(b, m, deterministic)
+}
+func (m *PinJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinJobResponse.Merge(m, src)
+}
+func (m *PinJobResponse) XXX_Size() int {
+	return xxx_messageInfo_PinJobResponse.Size(m)
+}
+func (m *PinJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinJobResponse proto.InternalMessageInfo
+
+func (m *PinJobResponse) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type PromoteJobRequest struct {
+	// name is the name of the job to promote
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// promotion is the name of the promotion configured in the repository, e.g. production
+	Promotion string `protobuf:"bytes,2,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	// annotations are added to the promotion job
+	Annotations          []*Annotation `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *PromoteJobRequest) Reset()         { *m = PromoteJobRequest{} }
+func (m *PromoteJobRequest) String() string { return proto.CompactTextString(m) }
+func (*PromoteJobRequest) ProtoMessage()    {}
+func (*PromoteJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{39}
+}
+
+func (m *PromoteJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteJobRequest.Unmarshal(m, b)
+}
+func (m *PromoteJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteJobRequest.Marshal(b, m, deterministic)
+}
+func (m *PromoteJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteJobRequest.Merge(m, src)
+}
+func (m *PromoteJobRequest) XXX_Size() int {
+	return xxx_messageInfo_PromoteJobRequest.Size(m)
+}
+func (m *PromoteJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteJobRequest proto.InternalMessageInfo
+
+func (m *PromoteJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PromoteJobRequest) GetPromotion() string {
+	if m != nil {
+		return m.Promotion
+	}
+	return ""
+}
+
+func (m *PromoteJobRequest) GetAnnotations() []*Annotation {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+type GetJobGraphRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobGraphRequest) Reset()         { *m = GetJobGraphRequest{} }
+func (m *GetJobGraphRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobGraphRequest) ProtoMessage()    {}
+func (*GetJobGraphRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{40}
+}
+
+func (m *GetJobGraphRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobGraphRequest.Unmarshal(m, b)
+}
+func (m *GetJobGraphRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobGraphRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobGraphRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobGraphRequest.Merge(m, src)
+}
+func (m *GetJobGraphRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobGraphRequest.Size(m)
+}
+func (m *GetJobGraphRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobGraphRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobGraphRequest proto.InternalMessageInfo
+
+func (m *GetJobGraphRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetJobGraphResponse struct {
+	Status               *JobStatus  `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Stages               []*JobStage `protobuf:"bytes,2,rep,name=stages,proto3" json:"stages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetJobGraphResponse) Reset()         { *m = GetJobGraphResponse{} }
+func (m *GetJobGraphResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobGraphResponse) ProtoMessage()    {}
+func (*GetJobGraphResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{41}
+}
+
+func (m *GetJobGraphResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobGraphResponse.Unmarshal(m, b)
+}
+func (m *GetJobGraphResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobGraphResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobGraphResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobGraphResponse.Merge(m, src)
+}
+func (m *GetJobGraphResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobGraphResponse.Size(m)
+}
+func (m *GetJobGraphResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobGraphResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobGraphResponse proto.InternalMessageInfo
+
+func (m *GetJobGraphResponse) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *GetJobGraphResponse) GetStages() []*JobStage {
+	if m != nil {
+		return m.Stages
+	}
+	return nil
+}
+
+// JobStage is a phase of a job as marked by a PHASE log slice. Log output preceding the first phase forms the default stage.
+type JobStage struct {
+	Name        string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string      `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Status      StageStatus `protobuf:"varint,3,opt,name=status,proto3,enum=v1.StageStatus" json:"status,omitempty"`
+	// depends_on lists the stages which have to finish before this one starts. Phases run one after the other,
+	// hence this is the previous stage.
+	DependsOn []string             `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Started   *timestamp.Timestamp `protobuf:"bytes,5,opt,name=started,proto3" json:"started,omitempty"`
+	Finished  *timestamp.Timestamp `protobuf:"bytes,6,opt,name=finished,proto3" json:"finished,omitempty"`
+	// duration is the time the stage took or has been running for so far
+	Duration *duration.Duration `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	// slices summarises the log slices started during this stage
+	Slices               []*LogSliceSummary `protobuf:"bytes,8,rep,name=slices,proto3" json:"slices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *JobStage) Reset()         { *m = JobStage{} }
+func (m *JobStage) String() string { return proto.CompactTextString(m) }
+func (*JobStage) ProtoMessage()    {}
+func (*JobStage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{42}
+}
+
+func (m *JobStage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobStage.Unmarshal(m, b)
+}
+func (m *JobStage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobStage.Marshal(b, m, deterministic)
+}
+func (m *JobStage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobStage.Merge(m, src)
+}
+func (m *JobStage) XXX_Size() int {
+	return xxx_messageInfo_JobStage.Size(m)
+}
+func (m *JobStage) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobStage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobStage proto.InternalMessageInfo
+
+func (m *JobStage) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JobStage) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *JobStage) GetStatus() StageStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StageStatus_STAGE_UNKNOWN
+}
+
+func (m *JobStage) GetDependsOn() []string {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
+func (m *JobStage) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *JobStage) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
+}
+
+func (m *JobStage) GetDuration() *duration.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *JobStage) GetSlices() []*LogSliceSummary {
+	if m != nil {
+		return m.Slices
+	}
+	return nil
+}
+
+type LogSliceSummary struct {
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status   StageStatus          `protobuf:"varint,2,opt,name=status,proto3,enum=v1.StageStatus" json:"status,omitempty"`
+	Started  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	Finished *timestamp.Timestamp `protobuf:"bytes,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	Duration *duration.Duration   `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	// lines is the number of log lines the slice produced
+	Lines int32 `protobuf:"varint,6,opt,name=lines,proto3" json:"lines,omitempty"`
+	// failure is the reason given when the slice failed
+	Failure string `protobuf:"bytes,7,opt,name=failure,proto3" json:"failure,omitempty"`
+	// phase is the name of the phase the slice started in
+	Phase string `protobuf:"bytes,8,opt,name=phase,proto3" json:"phase,omitempty"`
+	// start_offset is the position in the log of the line the slice starts with
+	StartOffset int64 `protobuf:"varint,9,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"`
+	// end_offset is the position in the log right after the last line of the slice. Lines of other slices can
+	// lie between the start and end offset.
+	EndOffset            int64    `protobuf:"varint,10,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSliceSummary) Reset()         { *m = LogSliceSummary{} }
+func (m *LogSliceSummary) String() string { return proto.CompactTextString(m) }
+func (*LogSliceSummary) ProtoMessage()    {}
+func (*LogSliceSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{43}
+}
+
+func (m *LogSliceSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSliceSummary.Unmarshal(m, b)
+}
+func (m *LogSliceSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSliceSummary.Marshal(b, m, deterministic)
+}
+func (m *LogSliceSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSliceSummary.Merge(m, src)
+}
+func (m *LogSliceSummary) XXX_Size() int {
+	return xxx_messageInfo_LogSliceSummary.Size(m)
+}
+func (m *LogSliceSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSliceSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSliceSummary proto.InternalMessageInfo
+
+func (m *LogSliceSummary) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSliceSummary) GetStatus() StageStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StageStatus_STAGE_UNKNOWN
+}
+
+func (m *LogSliceSummary) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *LogSliceSummary) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
+}
+
+func (m *LogSliceSummary) GetDuration() *duration.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *LogSliceSummary) GetLines() int32 {
+	if m != nil {
+		return m.Lines
+	}
+	return 0
+}
+
+func (m *LogSliceSummary) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
+func (m *LogSliceSummary) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *LogSliceSummary) GetStartOffset() int64 {
+	if m != nil {
+		return m.StartOffset
+	}
+	return 0
+}
+
+func (m *LogSliceSummary) GetEndOffset() int64 {
+	if m != nil {
+		return m.EndOffset
+	}
+	return 0
+}
+
+type StarJobRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StarJobRequest) Reset()         { *m = StarJobRequest{} }
+func (m *StarJobRequest) String() string { return proto.CompactTextString(m) }
+func (*StarJobRequest) ProtoMessage()    {}
+func (*StarJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{44}
+}
+
+func (m *StarJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StarJobRequest.Unmarshal(m, b)
+}
+func (m *StarJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StarJobRequest.Marshal(b, m, deterministic)
+}
+func (m *StarJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StarJobRequest.Merge(m, src)
+}
+func (m *StarJobRequest) XXX_Size() int {
+	return xxx_messageInfo_StarJobRequest.Size(m)
+}
+func (m *StarJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StarJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StarJobRequest proto.InternalMessageInfo
+
+func (m *StarJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type StarJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StarJobResponse) Reset()         { *m = StarJobResponse{} }
+func (m *StarJobResponse) String() string { return proto.CompactTextString(m) }
+func (*StarJobResponse) ProtoMessage()    {}
+func (*StarJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{45}
+}
+
+func (m *StarJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StarJobResponse.Unmarshal(m, b)
+}
+func (m *StarJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StarJobResponse.Marshal(b, m, deterministic)
+}
+func (m *StarJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StarJobResponse.Merge(m, src)
+}
+func (m *StarJobResponse) XXX_Size() int {
+	return xxx_messageInfo_StarJobResponse.Size(m)
+}
+func (m *StarJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StarJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StarJobResponse proto.InternalMessageInfo
+
+type UnstarJobRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnstarJobRequest) Reset()         { *m = UnstarJobRequest{} }
+func (m *UnstarJobRequest) String() string { return proto.CompactTextString(m) }
+func (*UnstarJobRequest) ProtoMessage()    {}
+func (*UnstarJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{46}
+}
+
+func (m *UnstarJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnstarJobRequest.Unmarshal(m, b)
+}
+func (m *UnstarJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnstarJobRequest.Marshal(b, m, deterministic)
+}
+func (m *UnstarJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnstarJobRequest.Merge(m, src)
+}
+func (m *UnstarJobRequest) XXX_Size() int {
+	return xxx_messageInfo_UnstarJobRequest.Size(m)
+}
+func (m *UnstarJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnstarJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnstarJobRequest proto.InternalMessageInfo
+
+func (m *UnstarJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type UnstarJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnstarJobResponse) Reset()         { *m = UnstarJobResponse{} }
+func (m *UnstarJobResponse) String() string { return proto.CompactTextString(m) }
+func (*UnstarJobResponse) ProtoMessage()    {}
+func (*UnstarJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{47}
+}
+
+func (m *UnstarJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnstarJobResponse.Unmarshal(m, b)
+}
+func (m *UnstarJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnstarJobResponse.Marshal(b, m, deterministic)
+}
+func (m *UnstarJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnstarJobResponse.Merge(m, src)
+}
+func (m *UnstarJobResponse) XXX_Size() int {
+	return xxx_messageInfo_UnstarJobResponse.Size(m)
+}
+func (m *UnstarJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnstarJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnstarJobResponse proto.InternalMessageInfo
+
+type ListStarredJobsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListStarredJobsRequest) Reset()         { *m = ListStarredJobsRequest{} }
+func (m *ListStarredJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListStarredJobsRequest) ProtoMessage()    {}
+func (*ListStarredJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{48}
+}
+
+func (m *ListStarredJobsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListStarredJobsRequest.Unmarshal(m, b)
+}
+func (m *ListStarredJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListStarredJobsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListStarredJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListStarredJobsRequest.Merge(m, src)
+}
+func (m *ListStarredJobsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListStarredJobsRequest.Size(m)
+}
+func (m *ListStarredJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListStarredJobsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListStarredJobsRequest proto.InternalMessageInfo
+
+type ListStarredJobsResponse struct {
+	Result               []*JobStatus `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListStarredJobsResponse) Reset()         { *m = ListStarredJobsResponse{} }
+func (m *ListStarredJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListStarredJobsResponse) ProtoMessage()    {}
+func (*ListStarredJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{49}
+}
+
+func (m *ListStarredJobsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListStarredJobsResponse.Unmarshal(m, b)
+}
+func (m *ListStarredJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListStarredJobsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListStarredJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListStarredJobsResponse.Merge(m, src)
+}
+func (m *ListStarredJobsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListStarredJobsResponse.Size(m)
+}
+func (m *ListStarredJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListStarredJobsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListStarredJobsResponse proto.InternalMessageInfo
+
+func (m *ListStarredJobsResponse) GetResult() []*JobStatus {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// SavedSearch is a named job search, e.g. to be shown on a user's dashboard
+type SavedSearch struct {
+	Name                 string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Filter               []*FilterExpression `protobuf:"bytes,2,rep,name=filter,proto3" json:"filter,omitempty"`
+	Order                []*OrderExpression  `protobuf:"bytes,3,rep,name=order,proto3" json:"order,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SavedSearch) Reset()         { *m = SavedSearch{} }
+func (m *SavedSearch) String() string { return proto.CompactTextString(m) }
+func (*SavedSearch) ProtoMessage()    {}
+func (*SavedSearch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{50}
+}
+
+func (m *SavedSearch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SavedSearch.Unmarshal(m, b)
+}
+func (m *SavedSearch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SavedSearch.Marshal(b, m, deterministic)
+}
+func (m *SavedSearch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SavedSearch.Merge(m, src)
+}
+func (m *SavedSearch) XXX_Size() int {
+	return xxx_messageInfo_SavedSearch.Size(m)
+}
+func (m *SavedSearch) XXX_DiscardUnknown() {
+	xxx_messageInfo_SavedSearch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SavedSearch proto.InternalMessageInfo
+
+func (m *SavedSearch) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SavedSearch) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *SavedSearch) GetOrder() []*OrderExpression {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+type SaveSearchRequest struct {
+	Search               *SavedSearch `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SaveSearchRequest) Reset()         { *m = SaveSearchRequest{} }
+func (m *SaveSearchRequest) String() string { return proto.CompactTextString(m) }
+func (*SaveSearchRequest) ProtoMessage()    {}
+func (*SaveSearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{51}
+}
+
+func (m *SaveSearchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SaveSearchRequest.Unmarshal(m, b)
+}
+func (m *SaveSearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SaveSearchRequest.Marshal(b, m, deterministic)
+}
+func (m *SaveSearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SaveSearchRequest.Merge(m, src)
+}
+func (m *SaveSearchRequest) XXX_Size() int {
+	return xxx_messageInfo_SaveSearchRequest.Size(m)
+}
+func (m *SaveSearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SaveSearchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SaveSearchRequest proto.InternalMessageInfo
+
+func (m *SaveSearchRequest) GetSearch() *SavedSearch {
+	if m != nil {
+		return m.Search
+	}
+	return nil
+}
+
+type SaveSearchResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SaveSearchResponse) Reset()         { *m = SaveSearchResponse{} }
+func (m *SaveSearchResponse) String() string { return proto.CompactTextString(m) }
+func (*SaveSearchResponse) ProtoMessage()    {}
+func (*SaveSearchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{52}
+}
+
+func (m *SaveSearchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SaveSearchResponse.Unmarshal(m, b)
+}
+func (m *SaveSearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SaveSearchResponse.Marshal(b, m, deterministic)
+}
+func (m *SaveSearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SaveSearchResponse.Merge(m, src)
+}
+func (m *SaveSearchResponse) XXX_Size() int {
+	return xxx_messageInfo_SaveSearchResponse.Size(m)
+}
+func (m *SaveSearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SaveSearchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SaveSearchResponse proto.InternalMessageInfo
+
+type DeleteSearchRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSearchRequest) Reset()         { *m = DeleteSearchRequest{} }
+func (m *DeleteSearchRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSearchRequest) ProtoMessage()    {}
+func (*DeleteSearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{53}
+}
+
+func (m *DeleteSearchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSearchRequest.Unmarshal(m, b)
+}
+func (m *DeleteSearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSearchRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSearchRequest.Merge(m, src)
+}
+func (m *DeleteSearchRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSearchRequest.Size(m)
+}
+func (m *DeleteSearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSearchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSearchRequest proto.InternalMessageInfo
+
+func (m *DeleteSearchRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DeleteSearchResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSearchResponse) Reset()         { *m = DeleteSearchResponse{} }
+func (m *DeleteSearchResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSearchResponse) ProtoMessage()    {}
+func (*DeleteSearchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{54}
+}
+
+func (m *DeleteSearchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSearchResponse.Unmarshal(m, b)
+}
+func (m *DeleteSearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSearchResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteSearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSearchResponse.Merge(m, src)
+}
+func (m *DeleteSearchResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteSearchResponse.Size(m)
+}
+func (m *DeleteSearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSearchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSearchResponse proto.InternalMessageInfo
+
+type ListSearchesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSearchesRequest) Reset()         { *m = ListSearchesRequest{} }
+func (m *ListSearchesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSearchesRequest) ProtoMessage()    {}
+func (*ListSearchesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{55}
+}
+
+func (m *ListSearchesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSearchesRequest.Unmarshal(m, b)
+}
+func (m *ListSearchesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSearchesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSearchesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSearchesRequest.Merge(m, src)
+}
+func (m *ListSearchesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSearchesRequest.Size(m)
+}
+func (m *ListSearchesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSearchesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSearchesRequest proto.InternalMessageInfo
+
+type ListSearchesResponse struct {
+	Searches             []*SavedSearch `protobuf:"bytes,1,rep,name=searches,proto3" json:"searches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListSearchesResponse) Reset()         { *m = ListSearchesResponse{} }
+func (m *ListSearchesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSearchesResponse) ProtoMessage()    {}
+func (*ListSearchesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{56}
+}
+
+func (m *ListSearchesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSearchesResponse.Unmarshal(m, b)
+}
+func (m *ListSearchesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSearchesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSearchesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSearchesResponse.Merge(m, src)
+}
+func (m *ListSearchesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSearchesResponse.Size(m)
+}
+func (m *ListSearchesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSearchesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSearchesResponse proto.InternalMessageInfo
+
+func (m *ListSearchesResponse) GetSearches() []*SavedSearch {
+	if m != nil {
+		return m.Searches
+	}
+	return nil
+}
+
+// Deployment is recorded whenever a job produces a result of type "deployment"
+type Deployment struct {
+	Environment string `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Url         string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Version     string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// job is the name of the job which produced the deployment
+	Job                  string               `protobuf:"bytes,4,opt,name=job,proto3" json:"job,omitempty"`
+	Repository           *Repository          `protobuf:"bytes,5,opt,name=repository,proto3" json:"repository,omitempty"`
+	Deployed             *timestamp.Timestamp `protobuf:"bytes,6,opt,name=deployed,proto3" json:"deployed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Deployment) Reset()         { *m = Deployment{} }
+func (m *Deployment) String() string { return proto.CompactTextString(m) }
+func (*Deployment) ProtoMessage()    {}
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{57}
+}
+
+func (m *Deployment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Deployment.Unmarshal(m, b)
+}
+func (m *Deployment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Deployment.Marshal(b, m, deterministic)
+}
+func (m *Deployment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Deployment.Merge(m, src)
+}
+func (m *Deployment) XXX_Size() int {
+	return xxx_messageInfo_Deployment.Size(m)
+}
+func (m *Deployment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Deployment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Deployment proto.InternalMessageInfo
+
+func (m *Deployment) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *Deployment) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Deployment) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Deployment) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *Deployment) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *Deployment) GetDeployed() *timestamp.Timestamp {
+	if m != nil {
+		return m.Deployed
+	}
+	return nil
+}
+
+type ListDeploymentsRequest struct {
+	// environment restricts the result to a single environment
+	Environment string `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	// history returns all deployments to the environment, most recent first, rather than the current one only.
+	// Requires an environment.
+	History bool `protobuf:"varint,2,opt,name=history,proto3" json:"history,omitempty"`
+	// limit is the maximum number of deployments returned from the history. Defaults to 50.
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeploymentsRequest) Reset()         { *m = ListDeploymentsRequest{} }
+func (m *ListDeploymentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDeploymentsRequest) ProtoMessage()    {}
+func (*ListDeploymentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{58}
+}
+
+func (m *ListDeploymentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeploymentsRequest.Unmarshal(m, b)
+}
+func (m *ListDeploymentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeploymentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDeploymentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeploymentsRequest.Merge(m, src)
+}
+func (m *ListDeploymentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDeploymentsRequest.Size(m)
+}
+func (m *ListDeploymentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeploymentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeploymentsRequest proto.InternalMessageInfo
+
+func (m *ListDeploymentsRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *ListDeploymentsRequest) GetHistory() bool {
+	if m != nil {
+		return m.History
+	}
+	return false
+}
+
+func (m *ListDeploymentsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListDeploymentsResponse struct {
+	Deployments          []*Deployment `protobuf:"bytes,1,rep,name=deployments,proto3" json:"deployments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListDeploymentsResponse) Reset()         { *m = ListDeploymentsResponse{} }
+func (m *ListDeploymentsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDeploymentsResponse) ProtoMessage()    {}
+func (*ListDeploymentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{59}
+}
+
+func (m *ListDeploymentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeploymentsResponse.Unmarshal(m, b)
+}
+func (m *ListDeploymentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeploymentsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDeploymentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeploymentsResponse.Merge(m, src)
+}
+func (m *ListDeploymentsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDeploymentsResponse.Size(m)
+}
+func (m *ListDeploymentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeploymentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeploymentsResponse proto.InternalMessageInfo
+
+func (m *ListDeploymentsResponse) GetDeployments() []*Deployment {
+	if m != nil {
+		return m.Deployments
+	}
+	return nil
+}
+
+type GetJobProvenanceRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobProvenanceRequest) Reset()         { *m = GetJobProvenanceRequest{} }
+func (m *GetJobProvenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobProvenanceRequest) ProtoMessage()    {}
+func (*GetJobProvenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{60}
+}
+
+func (m *GetJobProvenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobProvenanceRequest.Unmarshal(m, b)
+}
+func (m *GetJobProvenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobProvenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobProvenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobProvenanceRequest.Merge(m, src)
+}
+func (m *GetJobProvenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobProvenanceRequest.Size(m)
+}
+func (m *GetJobProvenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobProvenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobProvenanceRequest proto.InternalMessageInfo
+
+func (m *GetJobProvenanceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetJobProvenanceResponse struct {
+	// envelope is the DSSE envelope (JSON) containing the in-toto statement with the SLSA provenance of the job
+	Envelope []byte `protobuf:"bytes,1,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	// public_key is the PEM encoded key the envelope can be verified with
+	PublicKey            string   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobProvenanceResponse) Reset()         { *m = GetJobProvenanceResponse{} }
+func (m *GetJobProvenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobProvenanceResponse) ProtoMessage()    {}
+func (*GetJobProvenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{61}
+}
+
+func (m *GetJobProvenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobProvenanceResponse.Unmarshal(m, b)
+}
+func (m *GetJobProvenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobProvenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobProvenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobProvenanceResponse.Merge(m, src)
+}
+func (m *GetJobProvenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobProvenanceResponse.Size(m)
+}
+func (m *GetJobProvenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobProvenanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobProvenanceResponse proto.InternalMessageInfo
+
+func (m *GetJobProvenanceResponse) GetEnvelope() []byte {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+func (m *GetJobProvenanceResponse) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+type DescribeJobRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeJobRequest) Reset()         { *m = DescribeJobRequest{} }
+func (m *DescribeJobRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeJobRequest) ProtoMessage()    {}
+func (*DescribeJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{62}
+}
+
+func (m *DescribeJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeJobRequest.Unmarshal(m, b)
+}
+func (m *DescribeJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeJobRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeJobRequest.Merge(m, src)
+}
+func (m *DescribeJobRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeJobRequest.Size(m)
+}
+func (m *DescribeJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeJobRequest proto.InternalMessageInfo
+
+func (m *DescribeJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DescribeJobResponse struct {
+	Status *JobStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// timeline lists the phases the job went through, oldest first
+	Timeline []*JobTimelineEntry `protobuf:"bytes,2,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	// events are the Kubernetes events of the job's pod, oldest first. Kubernetes keeps events for a limited time only.
+	Events []*KubernetesEvent `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	// links point to the job and what it was built from, e.g. the commit
+	Links                []*JobLink `protobuf:"bytes,4,rep,name=links,proto3" json:"links,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *DescribeJobResponse) Reset()         { *m = DescribeJobResponse{} }
+func (m *DescribeJobResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeJobResponse) ProtoMessage()    {}
+func (*DescribeJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{63}
+}
+
+func (m *DescribeJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeJobResponse.Unmarshal(m, b)
+}
+func (m *DescribeJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeJobResponse.Marshal(b, m, deterministic)
+}
+func (m *DescribeJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeJobResponse.Merge(m, src)
+}
+func (m *DescribeJobResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeJobResponse.Size(m)
+}
+func (m *DescribeJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeJobResponse proto.InternalMessageInfo
+
+func (m *DescribeJobResponse) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *DescribeJobResponse) GetTimeline() []*JobTimelineEntry {
+	if m != nil {
+		return m.Timeline
+	}
+	return nil
+}
+
+func (m *DescribeJobResponse) GetEvents() []*KubernetesEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *DescribeJobResponse) GetLinks() []*JobLink {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+type JobTimelineEntry struct {
+	Time                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Phase                JobPhase             `protobuf:"varint,2,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
+	Details              string               `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *JobTimelineEntry) Reset()         { *m = JobTimelineEntry{} }
+func (m *JobTimelineEntry) String() string { return proto.CompactTextString(m) }
+func (*JobTimelineEntry) ProtoMessage()    {}
+func (*JobTimelineEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{64}
+}
+
+func (m *JobTimelineEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobTimelineEntry.Unmarshal(m, b)
+}
+func (m *JobTimelineEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobTimelineEntry.Marshal(b, m, deterministic)
+}
+func (m *JobTimelineEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobTimelineEntry.Merge(m, src)
+}
+func (m *JobTimelineEntry) XXX_Size() int {
+	return xxx_messageInfo_JobTimelineEntry.Size(m)
+}
+func (m *JobTimelineEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobTimelineEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobTimelineEntry proto.InternalMessageInfo
+
+func (m *JobTimelineEntry) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *JobTimelineEntry) GetPhase() JobPhase {
+	if m != nil {
+		return m.Phase
+	}
+	return JobPhase_PHASE_UNKNOWN
+}
+
+func (m *JobTimelineEntry) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+type KubernetesEvent struct {
+	// time is the last time the event occurred
+	Time *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// type is Normal or Warning
+	Type    string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Reason  string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// count is how often the event occurred
+	Count int32 `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	// source is the component which reported the event, e.g. kubelet
+	Source               string   `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KubernetesEvent) Reset()         { *m = KubernetesEvent{} }
+func (m *KubernetesEvent) String() string { return proto.CompactTextString(m) }
+func (*KubernetesEvent) ProtoMessage()    {}
+func (*KubernetesEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{65}
+}
+
+func (m *KubernetesEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KubernetesEvent.Unmarshal(m, b)
+}
+func (m *KubernetesEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KubernetesEvent.Marshal(b, m, deterministic)
+}
+func (m *KubernetesEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KubernetesEvent.Merge(m, src)
+}
+func (m *KubernetesEvent) XXX_Size() int {
+	return xxx_messageInfo_KubernetesEvent.Size(m)
+}
+func (m *KubernetesEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_KubernetesEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KubernetesEvent proto.InternalMessageInfo
+
+func (m *KubernetesEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *KubernetesEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *KubernetesEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *KubernetesEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *KubernetesEvent) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *KubernetesEvent) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+type JobLink struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobLink) Reset()         { *m = JobLink{} }
+func (m *JobLink) String() string { return proto.CompactTextString(m) }
+func (*JobLink) ProtoMessage()    {}
+func (*JobLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{66}
+}
+
+func (m *JobLink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobLink.Unmarshal(m, b)
+}
+func (m *JobLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobLink.Marshal(b, m, deterministic)
+}
+func (m *JobLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobLink.Merge(m, src)
+}
+func (m *JobLink) XXX_Size() int {
+	return xxx_messageInfo_JobLink.Size(m)
+}
+func (m *JobLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobLink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobLink proto.InternalMessageInfo
+
+func (m *JobLink) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JobLink) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	RepoOwner string `protobuf:"bytes,1,opt,name=repo_owner,json=repoOwner,proto3" json:"repo_owner,omitempty"`
+	// repo_repo is the repository to compute the statistics for. If empty, all repositories of repo_owner are considered.
+	RepoRepo string `protobuf:"bytes,2,opt,name=repo_repo,json=repoRepo,proto3" json:"repo_repo,omitempty"`
+	// limit is the number of most recent jobs to analyse. Defaults to 500.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// top is the number of entries in the slowest steps and flakiest jobs lists. Defaults to 10.
+	Top                  int32    `protobuf:"varint,4,opt,name=top,proto3" json:"top,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatsRequest) Reset()         { *m = GetStatsRequest{} }
+func (m *GetStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatsRequest) ProtoMessage()    {}
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{67}
+}
+
+func (m *GetStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatsRequest.Unmarshal(m, b)
+}
+func (m *GetStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatsRequest.Merge(m, src)
+}
+func (m *GetStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatsRequest.Size(m)
+}
+func (m *GetStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatsRequest proto.InternalMessageInfo
+
+func (m *GetStatsRequest) GetRepoOwner() string {
+	if m != nil {
+		return m.RepoOwner
+	}
+	return ""
+}
+
+func (m *GetStatsRequest) GetRepoRepo() string {
+	if m != nil {
+		return m.RepoRepo
+	}
+	return ""
+}
+
+func (m *GetStatsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *GetStatsRequest) GetTop() int32 {
+	if m != nil {
+		return m.Top
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	// jobs contains the statistics of each job, where runs of the same job on the same ref are considered the same job
+	Jobs         []*JobStats  `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	SlowestSteps []*StepStats `protobuf:"bytes,2,rep,name=slowest_steps,json=slowestSteps,proto3" json:"slowest_steps,omitempty"`
+	FlakiestJobs []*JobStats  `protobuf:"bytes,3,rep,name=flakiest_jobs,json=flakiestJobs,proto3" json:"flakiest_jobs,omitempty"`
+	// costs contains the cost of all finished jobs per repository and month
+	Costs                []*CostStats `protobuf:"bytes,4,rep,name=costs,proto3" json:"costs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetStatsResponse) Reset()         { *m = GetStatsResponse{} }
+func (m *GetStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatsResponse) ProtoMessage()    {}
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{68}
+}
+
+func (m *GetStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatsResponse.Unmarshal(m, b)
+}
+func (m *GetStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatsResponse.Merge(m, src)
+}
+func (m *GetStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatsResponse.Size(m)
+}
+func (m *GetStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatsResponse proto.InternalMessageInfo
+
+func (m *GetStatsResponse) GetJobs() []*JobStats {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+func (m *GetStatsResponse) GetSlowestSteps() []*StepStats {
+	if m != nil {
+		return m.SlowestSteps
+	}
+	return nil
+}
+
+func (m *GetStatsResponse) GetFlakiestJobs() []*JobStats {
+	if m != nil {
+		return m.FlakiestJobs
+	}
+	return nil
+}
+
+func (m *GetStatsResponse) GetCosts() []*CostStats {
+	if m != nil {
+		return m.Costs
+	}
+	return nil
+}
+
+type CostStats struct {
+	// repository is the repository of the jobs in the form of owner/repo
+	Repository string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// month is the month the jobs were started in, e.g. 2020-02
+	Month                string   `protobuf:"bytes,2,opt,name=month,proto3" json:"month,omitempty"`
+	Runs                 int32    `protobuf:"varint,3,opt,name=runs,proto3" json:"runs,omitempty"`
+	CpuHours             float64  `protobuf:"fixed64,4,opt,name=cpu_hours,json=cpuHours,proto3" json:"cpu_hours,omitempty"`
+	MemoryGbHours        float64  `protobuf:"fixed64,5,opt,name=memory_gb_hours,json=memoryGbHours,proto3" json:"memory_gb_hours,omitempty"`
+	Amount               float64  `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency             string   `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CostStats) Reset()         { *m = CostStats{} }
+func (m *CostStats) String() string { return proto.CompactTextString(m) }
+func (*CostStats) ProtoMessage()    {}
+func (*CostStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{69}
+}
+
+func (m *CostStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CostStats.Unmarshal(m, b)
+}
+func (m *CostStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CostStats.Marshal(b, m, deterministic)
+}
+func (m *CostStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostStats.Merge(m, src)
+}
+func (m *CostStats) XXX_Size() int {
+	return xxx_messageInfo_CostStats.Size(m)
+}
+func (m *CostStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CostStats proto.InternalMessageInfo
+
+func (m *CostStats) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *CostStats) GetMonth() string {
+	if m != nil {
+		return m.Month
+	}
+	return ""
+}
+
+func (m *CostStats) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *CostStats) GetCpuHours() float64 {
+	if m != nil {
+		return m.CpuHours
+	}
+	return 0
+}
+
+func (m *CostStats) GetMemoryGbHours() float64 {
+	if m != nil {
+		return m.MemoryGbHours
+	}
+	return 0
+}
+
+func (m *CostStats) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *CostStats) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+type JobStats struct {
+	Job      string `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Runs     int32  `protobuf:"varint,2,opt,name=runs,proto3" json:"runs,omitempty"`
+	Failures int32  `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	// failure_rate is the ratio of failed runs to all runs
+	FailureRate float64 `protobuf:"fixed64,4,opt,name=failure_rate,json=failureRate,proto3" json:"failure_rate,omitempty"`
+	// flakiness is the ratio of runs whose outcome differed from the previous run
+	Flakiness            float64            `protobuf:"fixed64,5,opt,name=flakiness,proto3" json:"flakiness,omitempty"`
+	AvgDuration          *duration.Duration `protobuf:"bytes,6,opt,name=avg_duration,json=avgDuration,proto3" json:"avg_duration,omitempty"`
+	MaxDuration          *duration.Duration `protobuf:"bytes,7,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *JobStats) Reset()         { *m = JobStats{} }
+func (m *JobStats) String() string { return proto.CompactTextString(m) }
+func (*JobStats) ProtoMessage()    {}
+func (*JobStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{70}
+}
+
+func (m *JobStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobStats.Unmarshal(m, b)
+}
+func (m *JobStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobStats.Marshal(b, m, deterministic)
+}
+func (m *JobStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobStats.Merge(m, src)
+}
+func (m *JobStats) XXX_Size() int {
+	return xxx_messageInfo_JobStats.Size(m)
+}
+func (m *JobStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobStats proto.InternalMessageInfo
+
+func (m *JobStats) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *JobStats) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *JobStats) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *JobStats) GetFailureRate() float64 {
+	if m != nil {
+		return m.FailureRate
+	}
+	return 0
+}
+
+func (m *JobStats) GetFlakiness() float64 {
+	if m != nil {
+		return m.Flakiness
+	}
+	return 0
+}
+
+func (m *JobStats) GetAvgDuration() *duration.Duration {
+	if m != nil {
+		return m.AvgDuration
+	}
+	return nil
+}
+
+func (m *JobStats) GetMaxDuration() *duration.Duration {
+	if m != nil {
+		return m.MaxDuration
+	}
+	return nil
+}
+
+type StepStats struct {
+	Job                  string             `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Step                 string             `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`
+	Runs                 int32              `protobuf:"varint,3,opt,name=runs,proto3" json:"runs,omitempty"`
+	AvgDuration          *duration.Duration `protobuf:"bytes,4,opt,name=avg_duration,json=avgDuration,proto3" json:"avg_duration,omitempty"`
+	MaxDuration          *duration.Duration `protobuf:"bytes,5,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StepStats) Reset()         { *m = StepStats{} }
+func (m *StepStats) String() string { return proto.CompactTextString(m) }
+func (*StepStats) ProtoMessage()    {}
+func (*StepStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{71}
+}
+
+func (m *StepStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepStats.Unmarshal(m, b)
+}
+func (m *StepStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepStats.Marshal(b, m, deterministic)
+}
+func (m *StepStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepStats.Merge(m, src)
+}
+func (m *StepStats) XXX_Size() int {
+	return xxx_messageInfo_StepStats.Size(m)
+}
+func (m *StepStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepStats proto.InternalMessageInfo
+
+func (m *StepStats) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *StepStats) GetStep() string {
+	if m != nil {
+		return m.Step
+	}
+	return ""
+}
+
+func (m *StepStats) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *StepStats) GetAvgDuration() *duration.Duration {
+	if m != nil {
+		return m.AvgDuration
+	}
+	return nil
+}
+
+func (m *StepStats) GetMaxDuration() *duration.Duration {
+	if m != nil {
+		return m.MaxDuration
+	}
+	return nil
+}
+
+type GetRepoStatsRequest struct {
+	RepoOwner string `protobuf:"bytes,1,opt,name=repo_owner,json=repoOwner,proto3" json:"repo_owner,omitempty"`
+	// repo_repo is the repository to return the statistics of. If empty, all repositories of repo_owner are considered.
+	RepoRepo string `protobuf:"bytes,2,opt,name=repo_repo,json=repoRepo,proto3" json:"repo_repo,omitempty"`
+	// ref restricts the statistics to a ref, e.g. refs/heads/master. If empty, all refs are considered.
+	Ref string `protobuf:"bytes,3,opt,name=ref,proto3" json:"ref,omitempty"`
+	// since and until select the days (in UTC) the jobs finished on. since defaults to 30 days ago, until to now.
+	Since                *timestamp.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until                *timestamp.Timestamp `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetRepoStatsRequest) Reset()         { *m = GetRepoStatsRequest{} }
+func (m *GetRepoStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRepoStatsRequest) ProtoMessage()    {}
+func (*GetRepoStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{72}
+}
+
+func (m *GetRepoStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRepoStatsRequest.Unmarshal(m, b)
+}
+func (m *GetRepoStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRepoStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRepoStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRepoStatsRequest.Merge(m, src)
+}
+func (m *GetRepoStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRepoStatsRequest.Size(m)
+}
+func (m *GetRepoStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRepoStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRepoStatsRequest proto.InternalMessageInfo
+
+func (m *GetRepoStatsRequest) GetRepoOwner() string {
+	if m != nil {
+		return m.RepoOwner
+	}
+	return ""
+}
+
+func (m *GetRepoStatsRequest) GetRepoRepo() string {
+	if m != nil {
+		return m.RepoRepo
+	}
+	return ""
+}
+
+func (m *GetRepoStatsRequest) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *GetRepoStatsRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *GetRepoStatsRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+type GetRepoStatsResponse struct {
+	// stats contains the statistics of each repository and ref
+	Stats                []*RepoStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetRepoStatsResponse) Reset()         { *m = GetRepoStatsResponse{} }
+func (m *GetRepoStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRepoStatsResponse) ProtoMessage()    {}
+func (*GetRepoStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{73}
+}
+
+func (m *GetRepoStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRepoStatsResponse.Unmarshal(m, b)
+}
+func (m *GetRepoStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRepoStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRepoStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRepoStatsResponse.Merge(m, src)
+}
+func (m *GetRepoStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRepoStatsResponse.Size(m)
+}
+func (m *GetRepoStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRepoStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRepoStatsResponse proto.InternalMessageInfo
+
+func (m *GetRepoStatsResponse) GetStats() []*RepoStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type RepoStats struct {
+	// repository is the repository of the jobs in the form of owner/repo
+	Repository string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Ref        string `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	Runs       int32  `protobuf:"varint,3,opt,name=runs,proto3" json:"runs,omitempty"`
+	Failures   int32  `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`
+	// success_rate is the ratio of successful runs to all runs
+	SuccessRate float64 `protobuf:"fixed64,5,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	// The durations and queue latencies are estimated from histograms, hence are approximate
+	P50Duration     *duration.Duration   `protobuf:"bytes,6,opt,name=p50_duration,json=p50Duration,proto3" json:"p50_duration,omitempty"`
+	P95Duration     *duration.Duration   `protobuf:"bytes,7,opt,name=p95_duration,json=p95Duration,proto3" json:"p95_duration,omitempty"`
+	P50QueueLatency *duration.Duration   `protobuf:"bytes,8,opt,name=p50_queue_latency,json=p50QueueLatency,proto3" json:"p50_queue_latency,omitempty"`
+	P95QueueLatency *duration.Duration   `protobuf:"bytes,9,opt,name=p95_queue_latency,json=p95QueueLatency,proto3" json:"p95_queue_latency,omitempty"`
+	FailureCauses   []*FailureCauseStats `protobuf:"bytes,10,rep,name=failure_causes,json=failureCauses,proto3" json:"failure_causes,omitempty"`
+	// The preparation and run times break down the durations of jobs which recorded their phase timestamps
+	P50PrepareDuration   *duration.Duration `protobuf:"bytes,11,opt,name=p50_prepare_duration,json=p50PrepareDuration,proto3" json:"p50_prepare_duration,omitempty"`
+	P95PrepareDuration   *duration.Duration `protobuf:"bytes,12,opt,name=p95_prepare_duration,json=p95PrepareDuration,proto3" json:"p95_prepare_duration,omitempty"`
+	P50RunDuration       *duration.Duration `protobuf:"bytes,13,opt,name=p50_run_duration,json=p50RunDuration,proto3" json:"p50_run_duration,omitempty"`
+	P95RunDuration       *duration.Duration `protobuf:"bytes,14,opt,name=p95_run_duration,json=p95RunDuration,proto3" json:"p95_run_duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RepoStats) Reset()         { *m = RepoStats{} }
+func (m *RepoStats) String() string { return proto.CompactTextString(m) }
+func (*RepoStats) ProtoMessage()    {}
+func (*RepoStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{74}
+}
+
+func (m *RepoStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepoStats.Unmarshal(m, b)
+}
+func (m *RepoStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepoStats.Marshal(b, m, deterministic)
+}
+func (m *RepoStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoStats.Merge(m, src)
+}
+func (m *RepoStats) XXX_Size() int {
+	return xxx_messageInfo_RepoStats.Size(m)
+}
+func (m *RepoStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoStats proto.InternalMessageInfo
+
+func (m *RepoStats) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *RepoStats) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *RepoStats) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *RepoStats) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *RepoStats) GetSuccessRate() float64 {
+	if m != nil {
+		return m.SuccessRate
+	}
+	return 0
+}
+
+func (m *RepoStats) GetP50Duration() *duration.Duration {
+	if m != nil {
+		return m.P50Duration
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP95Duration() *duration.Duration {
+	if m != nil {
+		return m.P95Duration
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP50QueueLatency() *duration.Duration {
+	if m != nil {
+		return m.P50QueueLatency
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP95QueueLatency() *duration.Duration {
+	if m != nil {
+		return m.P95QueueLatency
+	}
+	return nil
+}
+
+func (m *RepoStats) GetFailureCauses() []*FailureCauseStats {
+	if m != nil {
+		return m.FailureCauses
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP50PrepareDuration() *duration.Duration {
+	if m != nil {
+		return m.P50PrepareDuration
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP95PrepareDuration() *duration.Duration {
+	if m != nil {
+		return m.P95PrepareDuration
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP50RunDuration() *duration.Duration {
+	if m != nil {
+		return m.P50RunDuration
+	}
+	return nil
+}
+
+func (m *RepoStats) GetP95RunDuration() *duration.Duration {
+	if m != nil {
+		return m.P95RunDuration
+	}
+	return nil
+}
+
+type FailureCauseStats struct {
+	FailureClass         JobFailureClass `protobuf:"varint,1,opt,name=failure_class,json=failureClass,proto3,enum=v1.JobFailureClass" json:"failure_class,omitempty"`
+	Count                int32           `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *FailureCauseStats) Reset()         { *m = FailureCauseStats{} }
+func (m *FailureCauseStats) String() string { return proto.CompactTextString(m) }
+func (*FailureCauseStats) ProtoMessage()    {}
+func (*FailureCauseStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{75}
+}
+
+func (m *FailureCauseStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FailureCauseStats.Unmarshal(m, b)
+}
+func (m *FailureCauseStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FailureCauseStats.Marshal(b, m, deterministic)
+}
+func (m *FailureCauseStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FailureCauseStats.Merge(m, src)
+}
+func (m *FailureCauseStats) XXX_Size() int {
+	return xxx_messageInfo_FailureCauseStats.Size(m)
+}
+func (m *FailureCauseStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_FailureCauseStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FailureCauseStats proto.InternalMessageInfo
+
+func (m *FailureCauseStats) GetFailureClass() JobFailureClass {
+	if m != nil {
+		return m.FailureClass
+	}
+	return JobFailureClass_FAILURE_UNCLASSIFIED
+}
+
+func (m *FailureCauseStats) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type GetServerInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetServerInfoRequest) Reset()         { *m = GetServerInfoRequest{} }
+func (m *GetServerInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetServerInfoRequest) ProtoMessage()    {}
+func (*GetServerInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{76}
+}
+
+func (m *GetServerInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetServerInfoRequest.Unmarshal(m, b)
+}
+func (m *GetServerInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetServerInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *GetServerInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServerInfoRequest.Merge(m, src)
+}
+func (m *GetServerInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetServerInfoRequest.Size(m)
+}
+func (m *GetServerInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServerInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetServerInfoRequest proto.InternalMessageInfo
+
+type GetServerInfoResponse struct {
+	// version is the version of the werft server
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// api_version is the version of this API, e.g. v1
+	ApiVersion string `protobuf:"bytes,2,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	// features lists the optional features this server supports, e.g. sse or stats
+	Features []string `protobuf:"bytes,3,rep,name=features,proto3" json:"features,omitempty"`
+	// plugins lists the names of the plugins this server runs
+	Plugins []string `protobuf:"bytes,4,rep,name=plugins,proto3" json:"plugins,omitempty"`
+	// job_store is the kind of store jobs are kept in, e.g. postgres
+	JobStore string `protobuf:"bytes,5,opt,name=job_store,json=jobStore,proto3" json:"job_store,omitempty"`
+	// log_store is the kind of store logs are kept in, e.g. file
+	LogStore string `protobuf:"bytes,6,opt,name=log_store,json=logStore,proto3" json:"log_store,omitempty"`
+	// auth_providers lists the means by which this server authenticates users and repositories, e.g. github-app
+	AuthProviders []string `protobuf:"bytes,7,rep,name=auth_providers,json=authProviders,proto3" json:"auth_providers,omitempty"`
+	// base_url is the URL the werft UI is available on, e.g. https://werft.some-domain.com
+	BaseUrl string `protobuf:"bytes,8,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// maintenance is set while werft is in maintenance and does not accept new jobs
+	Maintenance *MaintenanceMode `protobuf:"bytes,9,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	// anonymous_read_only is true if requests without a token can only read, e.g. list jobs and view logs,
+	// but cannot start, stop or annotate jobs
+	AnonymousReadOnly    bool     `protobuf:"varint,10,opt,name=anonymous_read_only,json=anonymousReadOnly,proto3" json:"anonymous_read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetServerInfoResponse) Reset()         { *m = GetServerInfoResponse{} }
+func (m *GetServerInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*GetServerInfoResponse) ProtoMessage()    {}
+func (*GetServerInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{77}
+}
+
+func (m *GetServerInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetServerInfoResponse.Unmarshal(m, b)
+}
+func (m *GetServerInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetServerInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *GetServerInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServerInfoResponse.Merge(m, src)
+}
+func (m *GetServerInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_GetServerInfoResponse.Size(m)
+}
+func (m *GetServerInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServerInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetServerInfoResponse proto.InternalMessageInfo
+
+func (m *GetServerInfoResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *GetServerInfoResponse) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *GetServerInfoResponse) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *GetServerInfoResponse) GetPlugins() []string {
+	if m != nil {
+		return m.Plugins
+	}
+	return nil
+}
+
+func (m *GetServerInfoResponse) GetJobStore() string {
+	if m != nil {
+		return m.JobStore
+	}
+	return ""
+}
+
+func (m *GetServerInfoResponse) GetLogStore() string {
+	if m != nil {
+		return m.LogStore
+	}
+	return ""
+}
+
+func (m *GetServerInfoResponse) GetAuthProviders() []string {
+	if m != nil {
+		return m.AuthProviders
+	}
+	return nil
+}
+
+func (m *GetServerInfoResponse) GetBaseUrl() string {
+	if m != nil {
+		return m.BaseUrl
+	}
+	return ""
+}
+
+func (m *GetServerInfoResponse) GetMaintenance() *MaintenanceMode {
+	if m != nil {
+		return m.Maintenance
+	}
+	return nil
+}
+
+func (m *GetServerInfoResponse) GetAnonymousReadOnly() bool {
+	if m != nil {
+		return m.AnonymousReadOnly
+	}
+	return false
+}
+
+// MaintenanceMode describes a planned interruption of the werft service, e.g. for a cluster upgrade
+type MaintenanceMode struct {
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// message is shown to users whose jobs are rejected and on the UI
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// queue_triggers queues jobs triggered by webhooks or other jobs rather than rejecting them. Queued jobs start once maintenance ends.
+	QueueTriggers bool                 `protobuf:"varint,3,opt,name=queue_triggers,json=queueTriggers,proto3" json:"queue_triggers,omitempty"`
+	Since         *timestamp.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	// queued is the number of jobs waiting for maintenance to end
+	Queued               int32    `protobuf:"varint,5,opt,name=queued,proto3" json:"queued,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceMode) Reset()         { *m = MaintenanceMode{} }
+func (m *MaintenanceMode) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceMode) ProtoMessage()    {}
+func (*MaintenanceMode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{78}
+}
+
+func (m *MaintenanceMode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceMode.Unmarshal(m, b)
+}
+func (m *MaintenanceMode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceMode.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceMode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceMode.Merge(m, src)
+}
+func (m *MaintenanceMode) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceMode.Size(m)
+}
+func (m *MaintenanceMode) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceMode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceMode proto.InternalMessageInfo
+
+func (m *MaintenanceMode) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *MaintenanceMode) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *MaintenanceMode) GetQueueTriggers() bool {
+	if m != nil {
+		return m.QueueTriggers
+	}
+	return false
+}
+
+func (m *MaintenanceMode) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *MaintenanceMode) GetQueued() int32 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+type DownloadLogRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// offset is the number of bytes of the log to skip, e.g. because they were downloaded before
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadLogRequest) Reset()         { *m = DownloadLogRequest{} }
+func (m *DownloadLogRequest) String() string { return proto.CompactTextString(m) }
+func (*DownloadLogRequest) ProtoMessage()    {}
+func (*DownloadLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{79}
+}
+
+func (m *DownloadLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadLogRequest.Unmarshal(m, b)
+}
+func (m *DownloadLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadLogRequest.Marshal(b, m, deterministic)
+}
+func (m *DownloadLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadLogRequest.Merge(m, src)
+}
+func (m *DownloadLogRequest) XXX_Size() int {
+	return xxx_messageInfo_DownloadLogRequest.Size(m)
+}
+func (m *DownloadLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadLogRequest proto.InternalMessageInfo
+
+func (m *DownloadLogRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DownloadLogRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type DownloadLogResponse struct {
+	// offset is the position of this chunk in the log
+	Offset               int64    `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadLogResponse) Reset()         { *m = DownloadLogResponse{} }
+func (m *DownloadLogResponse) String() string { return proto.CompactTextString(m) }
+func (*DownloadLogResponse) ProtoMessage()    {}
+func (*DownloadLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{80}
+}
+
+func (m *DownloadLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadLogResponse.Unmarshal(m, b)
+}
+func (m *DownloadLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadLogResponse.Marshal(b, m, deterministic)
+}
+func (m *DownloadLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadLogResponse.Merge(m, src)
+}
+func (m *DownloadLogResponse) XXX_Size() int {
+	return xxx_messageInfo_DownloadLogResponse.Size(m)
+}
+func (m *DownloadLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadLogResponse proto.InternalMessageInfo
+
+func (m *DownloadLogResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *DownloadLogResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ListLogSlicesRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListLogSlicesRequest) Reset()         { *m = ListLogSlicesRequest{} }
+func (m *ListLogSlicesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListLogSlicesRequest) ProtoMessage()    {}
+func (*ListLogSlicesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{81}
+}
+
+func (m *ListLogSlicesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLogSlicesRequest.Unmarshal(m, b)
+}
+func (m *ListLogSlicesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLogSlicesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListLogSlicesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLogSlicesRequest.Merge(m, src)
+}
+func (m *ListLogSlicesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListLogSlicesRequest.Size(m)
+}
+func (m *ListLogSlicesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLogSlicesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLogSlicesRequest proto.InternalMessageInfo
+
+func (m *ListLogSlicesRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ListLogSlicesResponse struct {
+	Status *JobStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// slices lists the slices of the log in the order they started
+	Slices []*LogSliceSummary `protobuf:"bytes,2,rep,name=slices,proto3" json:"slices,omitempty"`
+	// indexed is false if the log of the job could not be read, e.g. because the job is still running but the server
+	// started after it. There are no slices then.
+	Indexed              bool     `protobuf:"varint,3,opt,name=indexed,proto3" json:"indexed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListLogSlicesResponse) Reset()         { *m = ListLogSlicesResponse{} }
+func (m *ListLogSlicesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListLogSlicesResponse) ProtoMessage()    {}
+func (*ListLogSlicesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{82}
+}
+
+func (m *ListLogSlicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLogSlicesResponse.Unmarshal(m, b)
+}
+func (m *ListLogSlicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLogSlicesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListLogSlicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLogSlicesResponse.Merge(m, src)
+}
+func (m *ListLogSlicesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListLogSlicesResponse.Size(m)
+}
+func (m *ListLogSlicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLogSlicesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLogSlicesResponse proto.InternalMessageInfo
+
+func (m *ListLogSlicesResponse) GetStatus() *JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *ListLogSlicesResponse) GetSlices() []*LogSliceSummary {
+	if m != nil {
+		return m.Slices
+	}
+	return nil
+}
+
+func (m *ListLogSlicesResponse) GetIndexed() bool {
+	if m != nil {
+		return m.Indexed
+	}
+	return false
+}
+
+type ServiceAccountToken struct {
+	// id identifies the token without revealing it
+	Id             string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceAccount string `protobuf:"bytes,2,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// scopes lists what the token may be used for, e.g. admin, trigger or trigger:owner/repo
+	Scopes  []string             `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Created *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	// expires is when the token stops working, or unset if it never does
+	Expires *timestamp.Timestamp `protobuf:"bytes,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	// last_used is when the token was last presented, or unset if it never was
+	LastUsed             *timestamp.Timestamp `protobuf:"bytes,6,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ServiceAccountToken) Reset()         { *m = ServiceAccountToken{} }
+func (m *ServiceAccountToken) String() string { return proto.CompactTextString(m) }
+func (*ServiceAccountToken) ProtoMessage()    {}
+func (*ServiceAccountToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{83}
+}
+
+func (m *ServiceAccountToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceAccountToken.Unmarshal(m, b)
+}
+func (m *ServiceAccountToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceAccountToken.Marshal(b, m, deterministic)
+}
+func (m *ServiceAccountToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceAccountToken.Merge(m, src)
+}
+func (m *ServiceAccountToken) XXX_Size() int {
+	return xxx_messageInfo_ServiceAccountToken.Size(m)
+}
+func (m *ServiceAccountToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceAccountToken.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceAccountToken proto.InternalMessageInfo
+
+func (m *ServiceAccountToken) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ServiceAccountToken) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *ServiceAccountToken) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *ServiceAccountToken) GetCreated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *ServiceAccountToken) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+func (m *ServiceAccountToken) GetLastUsed() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastUsed
+	}
+	return nil
+}
+
+type ListOwnTokensRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *StopJobResponse) XXX_Size() int {
-	return xxx_messageInfo_StopJobResponse.Size(m)
+
+func (m *ListOwnTokensRequest) Reset()         { *m = ListOwnTokensRequest{} }
+func (m *ListOwnTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOwnTokensRequest) ProtoMessage()    {}
+func (*ListOwnTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{84}
 }
-func (m *StopJobResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_StopJobResponse.DiscardUnknown(m)
+
+func (m *ListOwnTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOwnTokensRequest.Unmarshal(m, b)
+}
+func (m *ListOwnTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOwnTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOwnTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOwnTokensRequest.Merge(m, src)
+}
+func (m *ListOwnTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOwnTokensRequest.Size(m)
+}
+func (m *ListOwnTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOwnTokensRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StopJobResponse proto.InternalMessageInfo
+var xxx_messageInfo_ListOwnTokensRequest proto.InternalMessageInfo
+
+type ListOwnTokensResponse struct {
+	Tokens               []*ServiceAccountToken `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ListOwnTokensResponse) Reset()         { *m = ListOwnTokensResponse{} }
+func (m *ListOwnTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListOwnTokensResponse) ProtoMessage()    {}
+func (*ListOwnTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{85}
+}
+
+func (m *ListOwnTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOwnTokensResponse.Unmarshal(m, b)
+}
+func (m *ListOwnTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOwnTokensResponse.Marshal(b, m, deterministic)
+}
+func (m *ListOwnTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOwnTokensResponse.Merge(m, src)
+}
+func (m *ListOwnTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListOwnTokensResponse.Size(m)
+}
+func (m *ListOwnTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOwnTokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOwnTokensResponse proto.InternalMessageInfo
+
+func (m *ListOwnTokensResponse) GetTokens() []*ServiceAccountToken {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type RevokeOwnTokenRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeOwnTokenRequest) Reset()         { *m = RevokeOwnTokenRequest{} }
+func (m *RevokeOwnTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeOwnTokenRequest) ProtoMessage()    {}
+func (*RevokeOwnTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{86}
+}
+
+func (m *RevokeOwnTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeOwnTokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeOwnTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeOwnTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeOwnTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeOwnTokenRequest.Merge(m, src)
+}
+func (m *RevokeOwnTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeOwnTokenRequest.Size(m)
+}
+func (m *RevokeOwnTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeOwnTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeOwnTokenRequest proto.InternalMessageInfo
+
+func (m *RevokeOwnTokenRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RevokeOwnTokenResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeOwnTokenResponse) Reset()         { *m = RevokeOwnTokenResponse{} }
+func (m *RevokeOwnTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeOwnTokenResponse) ProtoMessage()    {}
+func (*RevokeOwnTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{87}
+}
+
+func (m *RevokeOwnTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeOwnTokenResponse.Unmarshal(m, b)
+}
+func (m *RevokeOwnTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeOwnTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeOwnTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeOwnTokenResponse.Merge(m, src)
+}
+func (m *RevokeOwnTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeOwnTokenResponse.Size(m)
+}
+func (m *RevokeOwnTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeOwnTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeOwnTokenResponse proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterEnum("v1.FilterOp", FilterOp_name, FilterOp_value)
 	proto.RegisterEnum("v1.ListenRequestLogs", ListenRequestLogs_name, ListenRequestLogs_value)
+	proto.RegisterEnum("v1.ListenRequestStatus", ListenRequestStatus_name, ListenRequestStatus_value)
+	proto.RegisterEnum("v1.CleanupState", CleanupState_name, CleanupState_value)
+	proto.RegisterEnum("v1.StepOutcome", StepOutcome_name, StepOutcome_value)
 	proto.RegisterEnum("v1.JobTrigger", JobTrigger_name, JobTrigger_value)
 	proto.RegisterEnum("v1.JobPhase", JobPhase_name, JobPhase_value)
+	proto.RegisterEnum("v1.JobFailureClass", JobFailureClass_name, JobFailureClass_value)
 	proto.RegisterEnum("v1.LogSliceType", LogSliceType_name, LogSliceType_value)
+	proto.RegisterEnum("v1.StageStatus", StageStatus_name, StageStatus_value)
 	proto.RegisterType((*StartLocalJobRequest)(nil), "v1.StartLocalJobRequest")
 	proto.RegisterType((*StartJobResponse)(nil), "v1.StartJobResponse")
 	proto.RegisterType((*StartGitHubJobRequest)(nil), "v1.StartGitHubJobRequest")
 	proto.RegisterType((*StartFromPreviousJobRequest)(nil), "v1.StartFromPreviousJobRequest")
+	proto.RegisterType((*StartTarballJobRequest)(nil), "v1.StartTarballJobRequest")
 	proto.RegisterType((*ListJobsRequest)(nil), "v1.ListJobsRequest")
 	proto.RegisterType((*FilterExpression)(nil), "v1.FilterExpression")
 	proto.RegisterType((*FilterTerm)(nil), "v1.FilterTerm")
@@ -1577,122 +5730,401 @@ func init() {
 	proto.RegisterType((*GetJobResponse)(nil), "v1.GetJobResponse")
 	proto.RegisterType((*ListenRequest)(nil), "v1.ListenRequest")
 	proto.RegisterType((*ListenResponse)(nil), "v1.ListenResponse")
+	proto.RegisterType((*JobStatusSnapshot)(nil), "v1.JobStatusSnapshot")
 	proto.RegisterType((*JobStatus)(nil), "v1.JobStatus")
+	proto.RegisterType((*JobEnvironment)(nil), "v1.JobEnvironment")
+	proto.RegisterType((*ContainerImage)(nil), "v1.ContainerImage")
+	proto.RegisterType((*LogAnchor)(nil), "v1.LogAnchor")
+	proto.RegisterType((*JobCleanup)(nil), "v1.JobCleanup")
+	proto.RegisterType((*JobTimestamps)(nil), "v1.JobTimestamps")
+	proto.RegisterType((*JobCost)(nil), "v1.JobCost")
+	proto.RegisterType((*JobStep)(nil), "v1.JobStep")
+	proto.RegisterType((*JobProgress)(nil), "v1.JobProgress")
 	proto.RegisterType((*JobMetadata)(nil), "v1.JobMetadata")
+	proto.RegisterType((*JobEvent)(nil), "v1.JobEvent")
 	proto.RegisterType((*Repository)(nil), "v1.Repository")
 	proto.RegisterType((*Annotation)(nil), "v1.Annotation")
 	proto.RegisterType((*JobConditions)(nil), "v1.JobConditions")
 	proto.RegisterType((*JobResult)(nil), "v1.JobResult")
 	proto.RegisterType((*LogSliceEvent)(nil), "v1.LogSliceEvent")
+	proto.RegisterMapType((map[string]string)(nil), "v1.LogSliceEvent.FieldsEntry")
 	proto.RegisterType((*StopJobRequest)(nil), "v1.StopJobRequest")
 	proto.RegisterType((*StopJobResponse)(nil), "v1.StopJobResponse")
+	proto.RegisterType((*AnnotateJobRequest)(nil), "v1.AnnotateJobRequest")
+	proto.RegisterType((*AnnotateJobResponse)(nil), "v1.AnnotateJobResponse")
+	proto.RegisterType((*PinJobRequest)(nil), "v1.PinJobRequest")
+	proto.RegisterType((*PinJobResponse)(nil), "v1.PinJobResponse")
+	proto.RegisterType((*PromoteJobRequest)(nil), "v1.PromoteJobRequest")
+	proto.RegisterType((*GetJobGraphRequest)(nil), "v1.GetJobGraphRequest")
+	proto.RegisterType((*GetJobGraphResponse)(nil), "v1.GetJobGraphResponse")
+	proto.RegisterType((*JobStage)(nil), "v1.JobStage")
+	proto.RegisterType((*LogSliceSummary)(nil), "v1.LogSliceSummary")
+	proto.RegisterType((*StarJobRequest)(nil), "v1.StarJobRequest")
+	proto.RegisterType((*StarJobResponse)(nil), "v1.StarJobResponse")
+	proto.RegisterType((*UnstarJobRequest)(nil), "v1.UnstarJobRequest")
+	proto.RegisterType((*UnstarJobResponse)(nil), "v1.UnstarJobResponse")
+	proto.RegisterType((*ListStarredJobsRequest)(nil), "v1.ListStarredJobsRequest")
+	proto.RegisterType((*ListStarredJobsResponse)(nil), "v1.ListStarredJobsResponse")
+	proto.RegisterType((*SavedSearch)(nil), "v1.SavedSearch")
+	proto.RegisterType((*SaveSearchRequest)(nil), "v1.SaveSearchRequest")
+	proto.RegisterType((*SaveSearchResponse)(nil), "v1.SaveSearchResponse")
+	proto.RegisterType((*DeleteSearchRequest)(nil), "v1.DeleteSearchRequest")
+	proto.RegisterType((*DeleteSearchResponse)(nil), "v1.DeleteSearchResponse")
+	proto.RegisterType((*ListSearchesRequest)(nil), "v1.ListSearchesRequest")
+	proto.RegisterType((*ListSearchesResponse)(nil), "v1.ListSearchesResponse")
+	proto.RegisterType((*Deployment)(nil), "v1.Deployment")
+	proto.RegisterType((*ListDeploymentsRequest)(nil), "v1.ListDeploymentsRequest")
+	proto.RegisterType((*ListDeploymentsResponse)(nil), "v1.ListDeploymentsResponse")
+	proto.RegisterType((*GetJobProvenanceRequest)(nil), "v1.GetJobProvenanceRequest")
+	proto.RegisterType((*GetJobProvenanceResponse)(nil), "v1.GetJobProvenanceResponse")
+	proto.RegisterType((*DescribeJobRequest)(nil), "v1.DescribeJobRequest")
+	proto.RegisterType((*DescribeJobResponse)(nil), "v1.DescribeJobResponse")
+	proto.RegisterType((*JobTimelineEntry)(nil), "v1.JobTimelineEntry")
+	proto.RegisterType((*KubernetesEvent)(nil), "v1.KubernetesEvent")
+	proto.RegisterType((*JobLink)(nil), "v1.JobLink")
+	proto.RegisterType((*GetStatsRequest)(nil), "v1.GetStatsRequest")
+	proto.RegisterType((*GetStatsResponse)(nil), "v1.GetStatsResponse")
+	proto.RegisterType((*CostStats)(nil), "v1.CostStats")
+	proto.RegisterType((*JobStats)(nil), "v1.JobStats")
+	proto.RegisterType((*StepStats)(nil), "v1.StepStats")
+	proto.RegisterType((*GetRepoStatsRequest)(nil), "v1.GetRepoStatsRequest")
+	proto.RegisterType((*GetRepoStatsResponse)(nil), "v1.GetRepoStatsResponse")
+	proto.RegisterType((*RepoStats)(nil), "v1.RepoStats")
+	proto.RegisterType((*FailureCauseStats)(nil), "v1.FailureCauseStats")
+	proto.RegisterType((*GetServerInfoRequest)(nil), "v1.GetServerInfoRequest")
+	proto.RegisterType((*GetServerInfoResponse)(nil), "v1.GetServerInfoResponse")
+	proto.RegisterType((*MaintenanceMode)(nil), "v1.MaintenanceMode")
+	proto.RegisterType((*DownloadLogRequest)(nil), "v1.DownloadLogRequest")
+	proto.RegisterType((*DownloadLogResponse)(nil), "v1.DownloadLogResponse")
+	proto.RegisterType((*ListLogSlicesRequest)(nil), "v1.ListLogSlicesRequest")
+	proto.RegisterType((*ListLogSlicesResponse)(nil), "v1.ListLogSlicesResponse")
+	proto.RegisterType((*ServiceAccountToken)(nil), "v1.ServiceAccountToken")
+	proto.RegisterType((*ListOwnTokensRequest)(nil), "v1.ListOwnTokensRequest")
+	proto.RegisterType((*ListOwnTokensResponse)(nil), "v1.ListOwnTokensResponse")
+	proto.RegisterType((*RevokeOwnTokenRequest)(nil), "v1.RevokeOwnTokenRequest")
+	proto.RegisterType((*RevokeOwnTokenResponse)(nil), "v1.RevokeOwnTokenResponse")
 }
 
 func init() { proto.RegisterFile("werft.proto", fileDescriptor_9fe744feedd6d332) }
 
 var fileDescriptor_9fe744feedd6d332 = []byte{
-	// 1604 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xdd, 0x6e, 0xdb, 0xc8,
-	0x15, 0x16, 0x25, 0x4b, 0x96, 0x8e, 0x24, 0x9b, 0x1e, 0x3b, 0x85, 0xd6, 0xdb, 0x62, 0x1d, 0x6e,
-	0x16, 0xeb, 0x75, 0x5b, 0xef, 0xc6, 0x1b, 0x74, 0xdb, 0xa2, 0x17, 0x55, 0x6c, 0xc6, 0x72, 0xaa,
-	0x48, 0xea, 0x90, 0x6a, 0x5a, 0xa0, 0x80, 0x30, 0xa2, 0x46, 0x12, 0x13, 0x8a, 0xc3, 0x92, 0x23,
-	0x67, 0x0d, 0xec, 0x13, 0xf4, 0x01, 0x7a, 0xd7, 0x07, 0xe9, 0x0b, 0xb5, 0x6f, 0x51, 0x14, 0xf3,
-	0xc3, 0x1f, 0xc9, 0x4e, 0x82, 0xee, 0x1d, 0xcf, 0x37, 0x67, 0xce, 0xcf, 0x37, 0xe7, 0x9c, 0x19,
-	0x42, 0xf3, 0x1d, 0x8d, 0xe7, 0xfc, 0x3c, 0x8a, 0x19, 0x67, 0xa8, 0x7c, 0xfb, 0xf4, 0xf8, 0xb3,
-	0x05, 0x63, 0x8b, 0x80, 0x7e, 0x2d, 0x91, 0xe9, 0x7a, 0xfe, 0x35, 0xf7, 0x57, 0x34, 0xe1, 0x64,
-	0x15, 0x29, 0x25, 0xeb, 0x3f, 0x06, 0x1c, 0x39, 0x9c, 0xc4, 0xbc, 0xcf, 0x3c, 0x12, 0xbc, 0x64,
-	0x53, 0x4c, 0xff, 0xb6, 0xa6, 0x09, 0x47, 0xbf, 0x84, 0xfa, 0x8a, 0x72, 0x32, 0x23, 0x9c, 0x74,
-	0x8c, 0x13, 0xe3, 0xb4, 0x79, 0xb1, 0x7f, 0x7e, 0xfb, 0xf4, 0xfc, 0x25, 0x9b, 0xbe, 0xd2, 0x70,
-	0xaf, 0x84, 0x33, 0x15, 0xf4, 0x18, 0x9a, 0x1e, 0x0b, 0xe7, 0xfe, 0x62, 0x72, 0x47, 0x56, 0x41,
-	0xa7, 0x7c, 0x62, 0x9c, 0xb6, 0x7a, 0x25, 0x0c, 0x0a, 0xfc, 0x0b, 0x59, 0x05, 0xe8, 0x53, 0xa8,
-	0xbf, 0x61, 0x53, 0xb5, 0x5e, 0xd1, 0xeb, 0xbb, 0x6f, 0xd8, 0x54, 0x2e, 0x7e, 0x01, 0xed, 0x77,
-	0x2c, 0x7e, 0x9b, 0x44, 0xc4, 0xa3, 0x13, 0x4e, 0xe2, 0xce, 0x8e, 0xd6, 0x68, 0x65, 0xb0, 0x4b,
-	0x62, 0x74, 0x0e, 0x68, 0x43, 0x6d, 0x32, 0x63, 0x21, 0xed, 0x54, 0x4f, 0x8c, 0xd3, 0x7a, 0xaf,
-	0x84, 0xcd, 0xa2, 0xee, 0x15, 0x0b, 0xe9, 0xf3, 0x06, 0xec, 0x7a, 0x2c, 0xe4, 0x34, 0xe4, 0xd6,
-	0x6f, 0xc0, 0x94, 0x89, 0xca, 0x1c, 0x93, 0x88, 0x85, 0x09, 0x45, 0x5f, 0x40, 0x2d, 0xe1, 0x84,
-	0xaf, 0x13, 0x9d, 0x62, 0x5b, 0xa7, 0xe8, 0x48, 0x10, 0xeb, 0x45, 0xeb, 0x5f, 0x06, 0x3c, 0x92,
-	0x7b, 0xaf, 0x7d, 0xde, 0x5b, 0x4f, 0x0b, 0x2c, 0xfd, 0xfc, 0xa3, 0x2c, 0x15, 0x38, 0xfa, 0x44,
-	0x11, 0x10, 0x11, 0xbe, 0x94, 0x04, 0x35, 0x64, 0xfa, 0x23, 0xc2, 0x97, 0xe9, 0x52, 0xce, 0x4d,
-	0xce, 0xcc, 0x63, 0x68, 0x2d, 0x7c, 0xbe, 0x5c, 0x4f, 0x27, 0x9c, 0xbd, 0xa5, 0xa1, 0x24, 0xa6,
-	0x81, 0x9b, 0x0a, 0x73, 0x05, 0x84, 0x8e, 0xa1, 0x9e, 0xf8, 0x33, 0x1a, 0x30, 0x32, 0x93, 0x5c,
-	0xb4, 0x70, 0x26, 0x5b, 0x1e, 0x7c, 0x2a, 0x43, 0x7f, 0x11, 0xb3, 0xd5, 0x28, 0xa6, 0xb7, 0x3e,
-	0x5b, 0x27, 0x85, 0x04, 0x1e, 0x43, 0x2b, 0xd2, 0xe8, 0xe4, 0x0d, 0x9b, 0xca, 0x24, 0x1a, 0xb8,
-	0x19, 0xe5, 0x9a, 0xf7, 0x02, 0x28, 0xdf, 0x0b, 0xc0, 0xfa, 0x87, 0x01, 0xfb, 0x7d, 0x3f, 0x11,
-	0xdc, 0x26, 0xa9, 0xe5, 0x5f, 0x40, 0x6d, 0xee, 0x07, 0x9c, 0xc6, 0x1d, 0xe3, 0xa4, 0x72, 0xda,
-	0xbc, 0x38, 0x12, 0xc4, 0xbc, 0x90, 0x88, 0xfd, 0x7d, 0x14, 0xd3, 0x24, 0xf1, 0x59, 0x88, 0xb5,
-	0x0e, 0xfa, 0x0a, 0xaa, 0x2c, 0x9e, 0xd1, 0xb8, 0x53, 0x96, 0xca, 0x87, 0x42, 0x79, 0x28, 0x80,
-	0x82, 0xae, 0xd2, 0x40, 0x47, 0x50, 0x4d, 0x44, 0x46, 0x92, 0xa8, 0x2a, 0x56, 0x82, 0x40, 0x03,
-	0x7f, 0xe5, 0x73, 0xc9, 0x4f, 0x15, 0x2b, 0xc1, 0xfa, 0x35, 0x98, 0xdb, 0x2e, 0xd1, 0x13, 0xa8,
-	0x72, 0x1a, 0xaf, 0x12, 0x1d, 0xd7, 0x5e, 0x1e, 0x97, 0x4b, 0xe3, 0x15, 0x56, 0x8b, 0xd6, 0x0f,
-	0x00, 0x39, 0x28, 0xac, 0xcf, 0x7d, 0x1a, 0xcc, 0x34, 0x3f, 0x4a, 0x10, 0xe8, 0x2d, 0x09, 0xd6,
-	0x54, 0x53, 0xa2, 0x04, 0x74, 0x06, 0x0d, 0x16, 0xd1, 0x98, 0x70, 0x9f, 0x85, 0x32, 0xc6, 0xbd,
-	0x8b, 0x56, 0xee, 0x63, 0x18, 0xe1, 0x7c, 0x19, 0xfd, 0x04, 0x6a, 0x21, 0x5d, 0x10, 0x4e, 0x65,
-	0xd8, 0x75, 0xac, 0x25, 0xcb, 0x86, 0xfd, 0xad, 0xec, 0xdf, 0x13, 0xc2, 0x4f, 0xa1, 0x41, 0x12,
-	0x8f, 0x86, 0x33, 0x3f, 0x5c, 0xc8, 0x30, 0xea, 0x38, 0x07, 0xac, 0x21, 0x98, 0xf9, 0xb1, 0xe8,
-	0x9a, 0x3f, 0x82, 0x2a, 0x67, 0x9c, 0x04, 0xd2, 0x4e, 0x15, 0x2b, 0x41, 0x74, 0x42, 0x4c, 0x93,
-	0x75, 0xc0, 0xf5, 0x01, 0x6c, 0x77, 0x82, 0x5a, 0xb4, 0x7e, 0x0f, 0xa6, 0xb3, 0x9e, 0x26, 0x5e,
-	0xec, 0x4f, 0xe9, 0x8f, 0x3a, 0x68, 0xeb, 0xb7, 0x70, 0x50, 0xb0, 0x90, 0xf7, 0xa1, 0xf6, 0xfe,
-	0x70, 0x1f, 0x6a, 0xef, 0x9f, 0x43, 0xfb, 0x9a, 0xf2, 0x42, 0xf5, 0x22, 0xd8, 0x09, 0xc9, 0x8a,
-	0x6a, 0x4a, 0xe4, 0xb7, 0xf5, 0x1d, 0xec, 0xa5, 0x4a, 0xff, 0x9f, 0xf5, 0x25, 0xb4, 0x05, 0x59,
-	0x34, 0xfc, 0x80, 0x75, 0xd4, 0x81, 0xdd, 0x75, 0x34, 0x23, 0x9c, 0x26, 0x9a, 0xed, 0x54, 0x44,
-	0x5f, 0xc1, 0x4e, 0xc0, 0x16, 0x89, 0x3e, 0xf1, 0x47, 0xc2, 0xc7, 0x86, 0xb9, 0x3e, 0x5b, 0x24,
-	0x58, 0xaa, 0x58, 0x0c, 0xf6, 0xd2, 0x25, 0x1d, 0xe2, 0x97, 0x50, 0x53, 0x76, 0x1e, 0x0c, 0xb1,
-	0x57, 0xc2, 0x7a, 0x59, 0xf4, 0x49, 0x12, 0xf8, 0x9e, 0x2a, 0xb9, 0xe6, 0xc5, 0x81, 0x74, 0xc3,
-	0x16, 0x8e, 0xc0, 0xec, 0x5b, 0x1a, 0xf2, 0x5e, 0x09, 0x2b, 0x8d, 0xe2, 0xec, 0xfb, 0xb7, 0x01,
-	0x8d, 0xcc, 0xda, 0x83, 0x79, 0x15, 0x07, 0x59, 0xf9, 0x63, 0x83, 0xcc, 0x82, 0x6a, 0xb4, 0x24,
-	0x09, 0x2d, 0x56, 0xf7, 0x4b, 0x36, 0x1d, 0x09, 0x0c, 0xab, 0x25, 0xf4, 0x14, 0xc4, 0xec, 0x9f,
-	0xf9, 0xa2, 0xcc, 0x13, 0x59, 0xdd, 0x3a, 0xda, 0x97, 0x6c, 0x7a, 0x99, 0x2d, 0xe0, 0x82, 0x92,
-	0xe0, 0x76, 0x46, 0x39, 0xf1, 0x83, 0x44, 0x4e, 0xb1, 0x06, 0x4e, 0x45, 0xf4, 0x25, 0xec, 0xaa,
-	0x43, 0x4a, 0x3a, 0xb5, 0x8d, 0xf2, 0xc4, 0x12, 0xc5, 0xe9, 0xaa, 0xf5, 0xcf, 0x32, 0x34, 0x0b,
-	0x31, 0x8b, 0x62, 0x67, 0xef, 0x42, 0x59, 0x9a, 0xb2, 0x69, 0xa4, 0x80, 0xce, 0x01, 0x62, 0x1a,
-	0xb1, 0xc4, 0xe7, 0x2c, 0xbe, 0xd3, 0xe9, 0xca, 0x31, 0x80, 0x33, 0x14, 0x17, 0x34, 0xd0, 0x29,
-	0xec, 0xf2, 0xd8, 0x5f, 0x2c, 0x68, 0xac, 0x33, 0xde, 0xd3, 0xee, 0x5d, 0x85, 0xe2, 0x74, 0x19,
-	0x3d, 0x83, 0x5d, 0x2f, 0xa6, 0x84, 0xd3, 0x99, 0x4e, 0xf9, 0xf8, 0x5c, 0xdd, 0xc0, 0xe7, 0xe9,
-	0x0d, 0x7c, 0xee, 0xa6, 0x37, 0x30, 0x4e, 0x55, 0xd1, 0xaf, 0xa0, 0x3e, 0xf7, 0x43, 0x3f, 0x59,
-	0x52, 0x35, 0xbf, 0x3f, 0xbc, 0x2d, 0xd3, 0x45, 0xdf, 0x40, 0x93, 0x84, 0x21, 0xe3, 0x44, 0x91,
-	0x5c, 0xcb, 0xe7, 0x59, 0x37, 0x83, 0x71, 0x51, 0xc5, 0xfa, 0x1e, 0x20, 0xcf, 0x51, 0x14, 0xc2,
-	0x92, 0x25, 0x3c, 0x2d, 0x04, 0xf1, 0x9d, 0x33, 0x56, 0x2e, 0x32, 0x86, 0x60, 0x47, 0xf0, 0x21,
-	0xd3, 0x6f, 0x60, 0xf9, 0x8d, 0x4c, 0xa8, 0xc4, 0x74, 0xae, 0xef, 0x23, 0xf1, 0x29, 0xee, 0x21,
-	0x71, 0x6f, 0x88, 0x7e, 0xd7, 0x27, 0x98, 0xc9, 0xd6, 0x33, 0x80, 0x3c, 0x28, 0xb1, 0xf7, 0x2d,
-	0xbd, 0xd3, 0x8e, 0xc5, 0xe7, 0xc3, 0xb3, 0xd4, 0x5a, 0x41, 0x7b, 0xa3, 0x5e, 0x44, 0x8d, 0x24,
-	0x6b, 0xcf, 0xa3, 0x89, 0xba, 0xb2, 0xeb, 0x38, 0x15, 0xd1, 0xe7, 0xd0, 0x9e, 0x13, 0x3f, 0x58,
-	0xc7, 0x74, 0xe2, 0xb1, 0x75, 0xc8, 0xa5, 0xa1, 0x2a, 0x6e, 0x69, 0xf0, 0x52, 0x60, 0xe8, 0x67,
-	0x00, 0x1e, 0x09, 0x27, 0x31, 0x8d, 0x02, 0x72, 0x27, 0xb3, 0xa9, 0xe3, 0x86, 0x47, 0x42, 0x2c,
-	0x01, 0xeb, 0x9d, 0x6c, 0x13, 0x55, 0x54, 0x22, 0x67, 0x7e, 0x17, 0x65, 0x6d, 0x22, 0xbe, 0x85,
-	0xfb, 0x88, 0xdc, 0xc9, 0x8b, 0x56, 0xdf, 0xe0, 0x5a, 0x44, 0x27, 0xd0, 0x9c, 0x51, 0x31, 0xd6,
-	0xa2, 0x6c, 0xee, 0x37, 0x70, 0x11, 0x12, 0xec, 0x78, 0x4b, 0x12, 0x86, 0x34, 0x10, 0xfd, 0x50,
-	0x11, 0xec, 0xa4, 0xb2, 0xe5, 0x41, 0x7b, 0xa3, 0x8b, 0x1f, 0xec, 0xd1, 0x27, 0x3a, 0xa0, 0xb2,
-	0xac, 0x41, 0xb3, 0xd8, 0xfa, 0xee, 0x5d, 0x44, 0xef, 0x87, 0x58, 0xd9, 0x08, 0xd1, 0x7a, 0x02,
-	0x7b, 0x0e, 0x67, 0xd1, 0x47, 0xe6, 0xe7, 0x01, 0xec, 0x67, 0x5a, 0x6a, 0x3a, 0x9d, 0x4d, 0xa0,
-	0x9e, 0x5e, 0x5e, 0xa8, 0x0d, 0x8d, 0xe1, 0x68, 0x62, 0xff, 0x71, 0xdc, 0xed, 0x3b, 0x66, 0x09,
-	0x21, 0xd8, 0x1b, 0x8e, 0x26, 0x8e, 0xdb, 0xc5, 0xae, 0x33, 0x79, 0x7d, 0xe3, 0xf6, 0x4c, 0x03,
-	0x99, 0xd0, 0x12, 0x2a, 0x83, 0x2b, 0x8d, 0x94, 0xd1, 0x3e, 0x34, 0x87, 0xa3, 0xc9, 0xe5, 0x70,
-	0xe0, 0x76, 0x6f, 0x06, 0x8e, 0x59, 0x49, 0xad, 0xfc, 0xf9, 0xc6, 0x71, 0x1d, 0x73, 0xe7, 0xec,
-	0x4f, 0x70, 0x70, 0x6f, 0x56, 0xa2, 0x03, 0x68, 0xf7, 0x87, 0xd7, 0xce, 0xe4, 0xea, 0xc6, 0xe9,
-	0x3e, 0xef, 0xdb, 0x57, 0x66, 0x29, 0x83, 0xc6, 0x03, 0xa7, 0x7f, 0x73, 0x69, 0x5f, 0x99, 0x06,
-	0x6a, 0x41, 0x5d, 0x42, 0xb8, 0xfb, 0xda, 0x2c, 0x0b, 0xbb, 0x52, 0xea, 0xb9, 0xaf, 0xfa, 0x66,
-	0xe5, 0xec, 0xaf, 0x00, 0x79, 0x97, 0xa2, 0x43, 0xd8, 0x77, 0xf1, 0xcd, 0xf5, 0xb5, 0x8d, 0x27,
-	0xe3, 0xc1, 0x1f, 0x06, 0xc3, 0xd7, 0x03, 0x95, 0x40, 0x0a, 0xbe, 0xea, 0x0e, 0xc6, 0xdd, 0xbe,
-	0x4a, 0x20, 0xc5, 0x46, 0x63, 0x47, 0x24, 0x50, 0xd8, 0x7a, 0x65, 0xf7, 0x6d, 0xd7, 0xbe, 0x32,
-	0x2b, 0x67, 0x3f, 0x40, 0x3d, 0x9d, 0x7a, 0x22, 0xb2, 0x51, 0xaf, 0xeb, 0xd8, 0x05, 0xcb, 0x87,
-	0xb0, 0xaf, 0xa0, 0x11, 0xb6, 0x47, 0x5d, 0x7c, 0x33, 0xb8, 0x36, 0x0d, 0xe1, 0x4e, 0x81, 0x92,
-	0x32, 0x81, 0x95, 0xf3, 0xbd, 0x78, 0x3c, 0x18, 0x08, 0xa8, 0x82, 0xf6, 0x00, 0x14, 0x74, 0x35,
-	0x1c, 0xd8, 0xe6, 0x4e, 0xae, 0x72, 0xd9, 0xb7, 0xbb, 0x83, 0xf1, 0xc8, 0xac, 0x9e, 0xfd, 0xdd,
-	0x80, 0x56, 0xf1, 0xf8, 0x85, 0x3f, 0xc9, 0xca, 0xa4, 0xfb, 0xbc, 0x3b, 0x10, 0xfb, 0x04, 0x63,
-	0xfb, 0xd0, 0x54, 0xa0, 0xdc, 0x6e, 0x1a, 0x39, 0x20, 0x03, 0x50, 0xde, 0x15, 0x20, 0x8e, 0xc7,
-	0x1e, 0xb8, 0xca, 0xbb, 0x82, 0xb4, 0xf7, 0x4c, 0x7e, 0xd1, 0xbd, 0xe9, 0x9b, 0x55, 0xc1, 0x8f,
-	0x92, 0xb1, 0xed, 0x8c, 0xfb, 0xae, 0x59, 0xbb, 0xf8, 0x6f, 0x05, 0x5a, 0xaf, 0xc5, 0xbf, 0x87,
-	0x43, 0xe3, 0x5b, 0xdf, 0xa3, 0xe8, 0x12, 0xda, 0x1b, 0xbf, 0x15, 0xa8, 0x23, 0xca, 0xf5, 0xa1,
-	0x3f, 0x8d, 0xe3, 0xa3, 0x6c, 0xa5, 0x50, 0x73, 0x56, 0xe9, 0xd4, 0x40, 0x97, 0xa2, 0x60, 0x8b,
-	0xcf, 0x6e, 0xf4, 0x49, 0xa6, 0xbb, 0xfd, 0x14, 0x7f, 0x9f, 0x19, 0x34, 0xd4, 0x3f, 0x38, 0x5b,
-	0x0f, 0x60, 0xf4, 0x59, 0xa6, 0xff, 0xf0, 0xd3, 0xf8, 0xbd, 0x06, 0xbf, 0x83, 0x7a, 0xfa, 0xa8,
-	0x42, 0x87, 0xe9, 0x35, 0x5f, 0x78, 0xf9, 0xaa, 0x8d, 0xdb, 0xef, 0x2e, 0xab, 0x84, 0x7e, 0x07,
-	0x8d, 0xec, 0xe9, 0x83, 0x94, 0xf5, 0xad, 0xb7, 0xd4, 0xf1, 0xa3, 0x2d, 0x34, 0xdd, 0xfb, 0x8d,
-	0x81, 0x9e, 0x42, 0x4d, 0xbd, 0x6b, 0x90, 0xbc, 0x46, 0x37, 0x1e, 0x42, 0xc7, 0xa8, 0x08, 0x65,
-	0x0e, 0xbf, 0x85, 0x9a, 0x6a, 0x2b, 0xb5, 0x65, 0xa3, 0xc5, 0xd4, 0x96, 0xcd, 0x67, 0x88, 0xf4,
-	0xf3, 0x0c, 0x76, 0x75, 0xff, 0x23, 0xa4, 0x18, 0x28, 0x8e, 0x8c, 0xe3, 0xc3, 0x0d, 0x2c, 0xdd,
-	0x37, 0xad, 0xc9, 0x8b, 0xea, 0xdb, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0xf4, 0xb0, 0xe6, 0xd7,
-	0x82, 0x0e, 0x00, 0x00,
+	// 5047 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x3b, 0x4d, 0x6f, 0x1b, 0x49,
+	0x76, 0x6e, 0x52, 0xa4, 0xc8, 0x47, 0x8a, 0xa2, 0x4a, 0x92, 0x4d, 0xd3, 0x33, 0x3b, 0x76, 0xcf,
+	0x87, 0x3d, 0x9a, 0x8c, 0xc7, 0xf6, 0x5a, 0x33, 0xe3, 0x19, 0x07, 0x18, 0x5a, 0xa2, 0x25, 0x8d,
+	0x65, 0x91, 0xd3, 0xa4, 0x76, 0x92, 0x5c, 0x1a, 0x4d, 0xb2, 0x44, 0xb5, 0x4d, 0x76, 0xf7, 0x76,
+	0x37, 0x65, 0x2b, 0x58, 0x04, 0x8b, 0x00, 0x01, 0xb2, 0xc8, 0x29, 0x40, 0x10, 0x20, 0x97, 0x20,
+	0xc8, 0x5f, 0x08, 0x92, 0xdc, 0x82, 0xe4, 0x14, 0x20, 0x40, 0x72, 0xca, 0x29, 0xc7, 0x5c, 0x72,
+	0xd8, 0x73, 0x0e, 0x41, 0x72, 0x08, 0x5e, 0x7d, 0x75, 0x35, 0x49, 0x9b, 0x92, 0x93, 0x8b, 0xc0,
+	0xf7, 0x51, 0xaf, 0xaa, 0x5e, 0xbd, 0x7a, 0xf5, 0x3e, 0x5a, 0x50, 0x7a, 0x45, 0xc3, 0x93, 0xf8,
+	0x6e, 0x10, 0xfa, 0xb1, 0x4f, 0x32, 0x67, 0xf7, 0xeb, 0x1f, 0x0c, 0x7d, 0x7f, 0x38, 0xa2, 0x5f,
+	0x30, 0x4c, 0x6f, 0x72, 0xf2, 0x45, 0xec, 0x8e, 0x69, 0x14, 0x3b, 0xe3, 0x80, 0x33, 0xd5, 0x7f,
+	0x32, 0xcd, 0x30, 0x98, 0x84, 0x4e, 0xec, 0xfa, 0x1e, 0xa7, 0x9b, 0xff, 0x61, 0xc0, 0x46, 0x27,
+	0x76, 0xc2, 0xf8, 0xd0, 0xef, 0x3b, 0xa3, 0xef, 0xfd, 0x9e, 0x45, 0x7f, 0x3e, 0xa1, 0x51, 0x4c,
+	0x3e, 0x87, 0xc2, 0x98, 0xc6, 0xce, 0xc0, 0x89, 0x9d, 0x9a, 0x71, 0xd3, 0xb8, 0x53, 0x7a, 0xb0,
+	0x7a, 0xf7, 0xec, 0xfe, 0xdd, 0xef, 0xfd, 0xde, 0x73, 0x81, 0xde, 0xbf, 0x62, 0x29, 0x16, 0x72,
+	0x0b, 0x4a, 0x7d, 0xdf, 0x3b, 0x71, 0x87, 0xf6, 0xb9, 0x33, 0x1e, 0xd5, 0x32, 0x37, 0x8d, 0x3b,
+	0xe5, 0xfd, 0x2b, 0x16, 0x70, 0xe4, 0x6f, 0x3b, 0xe3, 0x11, 0xb9, 0x01, 0x85, 0x17, 0x7e, 0x8f,
+	0xd3, 0xb3, 0x82, 0xbe, 0xfc, 0xc2, 0xef, 0x31, 0xe2, 0xc7, 0xb0, 0xf2, 0xca, 0x0f, 0x5f, 0x46,
+	0x81, 0xd3, 0xa7, 0x76, 0xec, 0x84, 0xb5, 0x25, 0xc1, 0x51, 0x56, 0xe8, 0xae, 0x13, 0x92, 0xbb,
+	0x40, 0x52, 0x6c, 0xf6, 0xc0, 0xf7, 0x68, 0x2d, 0x77, 0xd3, 0xb8, 0x53, 0xd8, 0xbf, 0x62, 0x55,
+	0x75, 0xde, 0x5d, 0xdf, 0xa3, 0x4f, 0x8a, 0xb0, 0xdc, 0xf7, 0xbd, 0x98, 0x7a, 0xb1, 0xf9, 0x08,
+	0xaa, 0x6c, 0xa3, 0x6c, 0x8f, 0x51, 0xe0, 0x7b, 0x11, 0x25, 0x1f, 0x43, 0x3e, 0x8a, 0x9d, 0x78,
+	0x12, 0x89, 0x2d, 0xae, 0x88, 0x2d, 0x76, 0x18, 0xd2, 0x12, 0x44, 0xf3, 0xdf, 0x0d, 0xd8, 0x64,
+	0x63, 0xf7, 0xdc, 0x78, 0x7f, 0xd2, 0xd3, 0xb4, 0xf4, 0xd9, 0x42, 0x2d, 0x69, 0x3a, 0xba, 0xce,
+	0x15, 0x10, 0x38, 0xf1, 0x29, 0x53, 0x50, 0x91, 0x6d, 0xbf, 0xed, 0xc4, 0xa7, 0xe4, 0xfa, 0xb4,
+	0x6e, 0x12, 0xcd, 0xdc, 0x82, 0xf2, 0xd0, 0x8d, 0x4f, 0x27, 0x3d, 0x3b, 0xf6, 0x5f, 0x52, 0x8f,
+	0x29, 0xa6, 0x68, 0x95, 0x38, 0xae, 0x8b, 0x28, 0x52, 0x87, 0x42, 0xe4, 0x0e, 0xe8, 0xc8, 0x77,
+	0x06, 0x4c, 0x17, 0x65, 0x4b, 0xc1, 0xe4, 0x36, 0xac, 0xba, 0x03, 0x3a, 0x0e, 0xfc, 0x98, 0x7a,
+	0xfd, 0x73, 0xfb, 0x25, 0x3d, 0xaf, 0xe5, 0x99, 0x84, 0x8a, 0x86, 0x7e, 0x46, 0xcf, 0xcd, 0xbf,
+	0x34, 0xe0, 0x06, 0xdb, 0xe4, 0xd3, 0xd0, 0x1f, 0xb7, 0x43, 0x7a, 0xe6, 0xfa, 0x93, 0x48, 0xdb,
+	0xea, 0x2d, 0x28, 0x07, 0x02, 0x6b, 0xbf, 0xf0, 0x7b, 0x6c, 0xbb, 0x45, 0xab, 0x14, 0x24, 0x9c,
+	0x33, 0x4b, 0xcd, 0xcc, 0x2e, 0x75, 0xce, 0x72, 0xb2, 0xf3, 0x96, 0x43, 0x36, 0x20, 0x47, 0x5f,
+	0x3b, 0xfd, 0x98, 0xed, 0xb7, 0x60, 0x71, 0xc0, 0xfc, 0x2f, 0x03, 0xae, 0xb2, 0x45, 0x76, 0x9d,
+	0xb0, 0xe7, 0x8c, 0x46, 0xef, 0x7a, 0x14, 0x55, 0xc8, 0x4e, 0xc2, 0x91, 0x58, 0x20, 0xfe, 0x24,
+	0x57, 0x21, 0x1f, 0x9d, 0x3a, 0x0f, 0xb6, 0xbf, 0x14, 0xeb, 0x11, 0x10, 0xf9, 0x14, 0xaa, 0x51,
+	0x1c, 0xba, 0x81, 0xdd, 0xf7, 0xc7, 0x81, 0xef, 0x51, 0x2f, 0x8e, 0xd8, 0x92, 0x72, 0xd6, 0x2a,
+	0xc3, 0xef, 0x28, 0x74, 0xea, 0x7c, 0x73, 0x6f, 0x3e, 0xdf, 0x7c, 0xfa, 0x7c, 0xe7, 0x68, 0x64,
+	0x79, 0xee, 0x01, 0xfd, 0xa9, 0x01, 0xab, 0x87, 0x6e, 0x84, 0x06, 0x1c, 0xc9, 0x4d, 0xff, 0x06,
+	0xe4, 0x4f, 0xdc, 0x51, 0x4c, 0xc3, 0x9a, 0x71, 0x33, 0x7b, 0xa7, 0xf4, 0x60, 0x03, 0xb7, 0xfc,
+	0x94, 0x61, 0x9a, 0xaf, 0x83, 0x90, 0x46, 0x91, 0xeb, 0x7b, 0x96, 0xe0, 0x21, 0x9f, 0x42, 0xce,
+	0x0f, 0x07, 0x34, 0xac, 0x65, 0x18, 0xf3, 0x3a, 0x32, 0xb7, 0xc2, 0x41, 0x8a, 0x97, 0x73, 0xa0,
+	0xfa, 0x23, 0xd4, 0x33, 0xd3, 0x46, 0xce, 0xe2, 0x00, 0x62, 0x47, 0xee, 0xd8, 0x8d, 0x85, 0x06,
+	0x38, 0x60, 0x7e, 0x0d, 0xd5, 0xe9, 0x29, 0xc9, 0x47, 0x90, 0x8b, 0x69, 0x38, 0x8e, 0xc4, 0xba,
+	0x2a, 0xc9, 0xba, 0xba, 0x34, 0x1c, 0x5b, 0x9c, 0x68, 0xfe, 0x02, 0x20, 0x41, 0xa2, 0xf4, 0x13,
+	0x97, 0x8e, 0x06, 0xc2, 0xb4, 0x38, 0x80, 0xd8, 0x33, 0x67, 0x34, 0xa1, 0xe2, 0xb0, 0x38, 0x40,
+	0xb6, 0xa0, 0xe8, 0x07, 0x94, 0xbb, 0x32, 0xb6, 0xc6, 0xca, 0x83, 0x72, 0x32, 0x47, 0x2b, 0xb0,
+	0x12, 0x32, 0x1e, 0xad, 0x47, 0x87, 0x4e, 0x4c, 0x85, 0x2d, 0x09, 0xc8, 0x6c, 0xc2, 0xea, 0xd4,
+	0xee, 0xdf, 0xb0, 0x84, 0xf7, 0xa0, 0xe8, 0x44, 0x7d, 0xea, 0x0d, 0x5c, 0x6f, 0xc8, 0x96, 0x51,
+	0xb0, 0x12, 0x84, 0xd9, 0x82, 0x6a, 0x72, 0x2c, 0xc2, 0xb1, 0x6c, 0x40, 0x2e, 0xf6, 0x63, 0x67,
+	0xc4, 0xe4, 0xe4, 0x2c, 0x0e, 0xa0, 0xbb, 0x09, 0x69, 0x34, 0x19, 0xc5, 0xe2, 0x00, 0xa6, 0xdd,
+	0x0d, 0x27, 0x9a, 0xdf, 0x41, 0xb5, 0x33, 0xe9, 0x45, 0xfd, 0xd0, 0xed, 0xd1, 0x77, 0x3a, 0x68,
+	0xf3, 0x1b, 0x58, 0xd3, 0x24, 0x24, 0xce, 0x4e, 0xcc, 0x3e, 0xdf, 0xd9, 0x89, 0xd9, 0x3f, 0x84,
+	0x95, 0x3d, 0x1a, 0x6b, 0x17, 0x8b, 0xc0, 0x92, 0xe7, 0x8c, 0xa9, 0x50, 0x09, 0xfb, 0x6d, 0x7e,
+	0x05, 0x15, 0xc9, 0x74, 0x39, 0xe9, 0xff, 0x69, 0xc0, 0x0a, 0x6a, 0x8b, 0x7a, 0x6f, 0x11, 0x4f,
+	0x6a, 0xb0, 0x3c, 0x09, 0x06, 0x4e, 0x4c, 0x23, 0xa1, 0x6e, 0x09, 0x92, 0x4f, 0x61, 0x69, 0xe4,
+	0x0f, 0x23, 0x71, 0xe4, 0x9b, 0x38, 0x49, 0x4a, 0xdc, 0xa1, 0x3f, 0x8c, 0x2c, 0xc6, 0x82, 0xc7,
+	0xde, 0x9f, 0x84, 0x91, 0x1f, 0x0a, 0x97, 0x29, 0x20, 0x66, 0xc4, 0xf4, 0x8c, 0x8e, 0xc4, 0x1d,
+	0xe5, 0x80, 0xa6, 0xe0, 0xfc, 0x05, 0x6e, 0xd2, 0x17, 0xea, 0xe1, 0x58, 0x66, 0x0b, 0xb9, 0x36,
+	0xb3, 0x90, 0xa9, 0x27, 0xe4, 0x2f, 0x0c, 0xa8, 0x48, 0xba, 0xd0, 0xd8, 0x6d, 0xc8, 0xf3, 0x5d,
+	0xcd, 0xd5, 0xd8, 0xfe, 0x15, 0x4b, 0x90, 0xf1, 0xda, 0x46, 0x23, 0xb7, 0xcf, 0x6f, 0x40, 0xe9,
+	0xc1, 0x1a, 0x9b, 0xcb, 0x1f, 0x76, 0x10, 0xd7, 0x3c, 0xa3, 0x5e, 0xbc, 0x7f, 0xc5, 0xe2, 0x1c,
+	0xda, 0xba, 0xb2, 0x8c, 0x77, 0x33, 0x25, 0xb3, 0xe3, 0x39, 0x41, 0x74, 0xea, 0x23, 0xbf, 0x60,
+	0xd3, 0x1f, 0xc8, 0x17, 0xb0, 0x36, 0xc3, 0x49, 0xee, 0xc2, 0x12, 0x86, 0x14, 0x62, 0x89, 0xf5,
+	0xbb, 0x3c, 0x9c, 0xb8, 0x2b, 0xc3, 0x89, 0xbb, 0x5d, 0x19, 0x6f, 0x58, 0x8c, 0x4f, 0x7b, 0x51,
+	0x33, 0x6f, 0x7b, 0x51, 0xff, 0x2c, 0x07, 0x45, 0x85, 0x9d, 0x6b, 0x02, 0xba, 0x3b, 0xcf, 0x2c,
+	0x72, 0xe7, 0x26, 0xe4, 0x82, 0x53, 0x27, 0xa2, 0xba, 0x27, 0xf8, 0xde, 0xef, 0xb5, 0x11, 0x67,
+	0x71, 0x12, 0xb9, 0x0f, 0x18, 0x8c, 0x0c, 0x5c, 0x74, 0x09, 0xdc, 0x85, 0x0b, 0x55, 0x7e, 0xef,
+	0xf7, 0x76, 0x14, 0xc1, 0xd2, 0x98, 0xd0, 0x0c, 0x07, 0x34, 0x76, 0xdc, 0x51, 0x24, 0xfd, 0xb9,
+	0x00, 0xc9, 0x6d, 0x58, 0xe6, 0x06, 0x1d, 0x09, 0x73, 0x91, 0xfb, 0xb4, 0x18, 0xd6, 0x92, 0x54,
+	0xdc, 0x46, 0x10, 0xfa, 0x43, 0xb4, 0x9f, 0xda, 0x72, 0x6a, 0x1b, 0x6d, 0x81, 0xb6, 0x14, 0x03,
+	0xb9, 0x85, 0x4e, 0x97, 0x06, 0x51, 0xad, 0xc0, 0x64, 0x96, 0x94, 0xee, 0x68, 0x60, 0x71, 0x0a,
+	0x69, 0x42, 0x95, 0x46, 0xb1, 0x3b, 0x76, 0x62, 0x3a, 0xb0, 0x4f, 0x5c, 0xcf, 0x8d, 0x4e, 0x6b,
+	0xc5, 0x85, 0x67, 0xb3, 0xaa, 0xc6, 0x3c, 0x65, 0x43, 0xc8, 0x07, 0xb0, 0xd4, 0xf7, 0xa3, 0xb8,
+	0x06, 0x37, 0x0d, 0x6d, 0xa2, 0x1d, 0x3f, 0x8a, 0x2d, 0x46, 0x20, 0x0f, 0x60, 0x33, 0x09, 0xb4,
+	0x26, 0x91, 0x33, 0xa4, 0x76, 0xef, 0x1c, 0xef, 0x63, 0xe9, 0xa6, 0x71, 0x27, 0x6b, 0xad, 0x2b,
+	0xe2, 0x31, 0xd2, 0x9e, 0x20, 0x09, 0x35, 0xac, 0xc2, 0xcf, 0xa8, 0x56, 0x4e, 0x69, 0x58, 0xad,
+	0x25, 0xb2, 0x34, 0x26, 0x72, 0x07, 0x96, 0xfb, 0x23, 0xea, 0x78, 0x93, 0xa0, 0xb6, 0x72, 0xd3,
+	0x90, 0x0f, 0x05, 0x2e, 0x85, 0x63, 0x2d, 0x49, 0x26, 0x0f, 0x60, 0xe5, 0xc4, 0x71, 0x47, 0x74,
+	0x60, 0x33, 0x4b, 0x8f, 0x6a, 0x95, 0x44, 0xef, 0x87, 0xfe, 0xb0, 0xe1, 0xf5, 0x4f, 0xfd, 0xd0,
+	0x2a, 0x73, 0x1e, 0x76, 0x35, 0x22, 0xf2, 0x10, 0x4a, 0xd4, 0x3b, 0x73, 0x43, 0xdf, 0x1b, 0x53,
+	0x2f, 0xae, 0xad, 0xb2, 0x19, 0x88, 0x98, 0xa1, 0x99, 0x50, 0x2c, 0x9d, 0xcd, 0xfc, 0xa7, 0x0c,
+	0x54, 0xd2, 0x74, 0xb2, 0x05, 0x79, 0x77, 0xec, 0x0c, 0xa9, 0x7c, 0xce, 0x98, 0x8c, 0x1d, 0xdf,
+	0x8b, 0x1d, 0xd7, 0xa3, 0xe1, 0x01, 0x92, 0x2c, 0xc1, 0xc1, 0x8c, 0xd9, 0x1f, 0xc8, 0xe7, 0x8a,
+	0xfd, 0xc6, 0xe7, 0xdf, 0x8f, 0x6c, 0xc6, 0x20, 0xc2, 0x8b, 0x65, 0x3f, 0x62, 0xc3, 0xc8, 0xc7,
+	0x50, 0x79, 0x49, 0x43, 0x8f, 0x8e, 0xec, 0x33, 0x1a, 0xa2, 0x8f, 0x11, 0xde, 0x6a, 0x85, 0x63,
+	0x7f, 0xc6, 0x91, 0xc4, 0x84, 0xb2, 0x13, 0xf6, 0x4f, 0xdd, 0x98, 0xf6, 0xe3, 0x49, 0x48, 0x85,
+	0x3d, 0xa6, 0x70, 0xe4, 0x1b, 0xb8, 0xde, 0x97, 0x6b, 0xb2, 0xc3, 0x89, 0x87, 0x7a, 0x56, 0x52,
+	0x79, 0xd0, 0x77, 0x4d, 0x31, 0x58, 0x9c, 0x2e, 0xe5, 0xdf, 0x86, 0xd5, 0x97, 0x93, 0x1e, 0x1d,
+	0xd1, 0x58, 0x8d, 0x10, 0x51, 0x88, 0x40, 0x4b, 0xc6, 0xcf, 0x81, 0x20, 0x26, 0xf4, 0x68, 0x4c,
+	0x23, 0xc5, 0x5b, 0x60, 0xbc, 0x6b, 0x09, 0x45, 0xb0, 0x9b, 0x36, 0x54, 0xd2, 0x7a, 0xc2, 0xc7,
+	0x54, 0x2d, 0x42, 0xdc, 0xf8, 0x04, 0x81, 0xce, 0x99, 0xab, 0x49, 0xbc, 0xf6, 0x0c, 0x40, 0xfd,
+	0xb1, 0x1f, 0xb6, 0x3b, 0x90, 0xfa, 0x63, 0xf0, 0xc1, 0xc0, 0xfc, 0x0a, 0x8a, 0xea, 0xf8, 0x71,
+	0x34, 0xf7, 0x03, 0xe2, 0xf9, 0x66, 0x00, 0x62, 0x13, 0xff, 0x59, 0x14, 0xae, 0xd2, 0xfc, 0x3d,
+	0x80, 0xc4, 0xce, 0xc8, 0x27, 0x2c, 0xde, 0x11, 0xbe, 0xb8, 0xf2, 0xa0, 0xca, 0x0e, 0x98, 0xd3,
+	0xd0, 0x49, 0x51, 0x8b, 0x93, 0x31, 0xd4, 0x76, 0xe2, 0x98, 0x8e, 0x83, 0x98, 0x7b, 0xb8, 0x9c,
+	0xa5, 0x60, 0x75, 0xf2, 0x59, 0xed, 0xe4, 0x35, 0x17, 0xb2, 0x94, 0x72, 0x21, 0xe6, 0xaf, 0x0d,
+	0x58, 0x49, 0x5d, 0x0c, 0xf2, 0x00, 0xf2, 0x3f, 0x9f, 0xd0, 0x09, 0x1d, 0x5c, 0xc0, 0xdb, 0x0a,
+	0x4e, 0xf2, 0x35, 0x14, 0x83, 0x90, 0x06, 0x4e, 0x28, 0x43, 0x93, 0xb7, 0x0f, 0x4b, 0x98, 0xc9,
+	0x43, 0x58, 0x0e, 0x27, 0x9e, 0x87, 0xe3, 0xb2, 0x0b, 0xc7, 0x49, 0x56, 0xf2, 0x25, 0x14, 0xb8,
+	0xd7, 0xa1, 0x83, 0xda, 0xd2, 0xc2, 0x61, 0x8a, 0xd7, 0xfc, 0x7d, 0x03, 0x96, 0x85, 0x87, 0x21,
+	0x37, 0xa0, 0xd8, 0x0f, 0x26, 0xf6, 0xa9, 0x3f, 0x09, 0x79, 0xe2, 0x65, 0x58, 0x85, 0x7e, 0x30,
+	0xd9, 0x47, 0x98, 0x7c, 0x02, 0xab, 0x63, 0x3a, 0xf6, 0xc3, 0x73, 0x7b, 0xd8, 0x13, 0x2c, 0x19,
+	0xc6, 0xb2, 0xc2, 0xd1, 0x7b, 0x3d, 0xce, 0x77, 0x15, 0xf2, 0xce, 0xd8, 0x9f, 0x78, 0x3c, 0x42,
+	0x35, 0x2c, 0x01, 0xe1, 0x01, 0xf5, 0x27, 0x61, 0x88, 0x41, 0xb3, 0xd0, 0xb8, 0x82, 0xcd, 0xbf,
+	0xe1, 0x8b, 0x40, 0x7f, 0x3a, 0xf7, 0xcd, 0x79, 0x08, 0xcb, 0x2c, 0xce, 0xa5, 0x83, 0x0b, 0xa8,
+	0x52, 0xb2, 0xa6, 0x54, 0x92, 0xbd, 0xb8, 0x4a, 0xc8, 0xa7, 0xb0, 0xec, 0x4f, 0xe2, 0xbe, 0x3f,
+	0xe6, 0x71, 0x69, 0x85, 0xbf, 0x0c, 0xb8, 0xb8, 0x16, 0x47, 0x5b, 0x92, 0x6e, 0xfe, 0x89, 0x01,
+	0x25, 0xed, 0xc9, 0x48, 0x2c, 0xda, 0xd0, 0x2c, 0x1a, 0x6d, 0x2d, 0xa0, 0x61, 0x1f, 0x5d, 0x1d,
+	0x37, 0x4d, 0x09, 0xe2, 0x66, 0xf1, 0xf9, 0x10, 0xc1, 0x3c, 0xfb, 0x4d, 0x3e, 0x80, 0x12, 0x8b,
+	0x4a, 0x6d, 0xfe, 0xe4, 0xf0, 0x88, 0x1e, 0x18, 0x0a, 0xd7, 0x10, 0x91, 0x9b, 0x50, 0x1a, 0x50,
+	0x8c, 0x21, 0x03, 0x16, 0x64, 0x73, 0x8f, 0xa3, 0xa3, 0xcc, 0x7f, 0xce, 0x42, 0x49, 0x7b, 0x90,
+	0x71, 0x59, 0xfe, 0xab, 0xe4, 0x5a, 0x73, 0x80, 0xdc, 0x05, 0x08, 0x69, 0xe0, 0x47, 0x6e, 0xec,
+	0x87, 0xe7, 0xb5, 0x4c, 0xe2, 0xe6, 0x2d, 0x85, 0xb5, 0x34, 0x0e, 0x7c, 0x13, 0xe2, 0xd0, 0x1d,
+	0x0e, 0x69, 0x28, 0x9e, 0x73, 0xf9, 0x26, 0x74, 0x39, 0xd6, 0x92, 0x64, 0x3c, 0xaf, 0x7e, 0x48,
+	0xf1, 0x59, 0xbb, 0x80, 0x2d, 0x4a, 0xd6, 0xd4, 0x79, 0xe5, 0x2e, 0x71, 0x5e, 0xf7, 0xa0, 0xe4,
+	0x78, 0x9e, 0x1f, 0x3b, 0x3c, 0x82, 0xc8, 0x27, 0x89, 0x4d, 0x43, 0xa1, 0x2d, 0x9d, 0x45, 0xb7,
+	0xa7, 0xe5, 0x8b, 0xdb, 0xd3, 0x2d, 0x28, 0x8b, 0x0d, 0xd2, 0x81, 0xdd, 0x3b, 0x17, 0xbe, 0xb5,
+	0xa4, 0x70, 0x4f, 0xce, 0x31, 0xde, 0xa1, 0x18, 0xf8, 0x89, 0xa7, 0x5f, 0xc6, 0x3b, 0x2c, 0x18,
+	0xb4, 0x38, 0x89, 0x65, 0x3d, 0x93, 0x71, 0x8f, 0x86, 0xec, 0x91, 0xcf, 0x59, 0x02, 0x92, 0xa9,
+	0x68, 0x14, 0xd0, 0x7e, 0xad, 0xa4, 0xb2, 0xd4, 0x4e, 0x40, 0xfb, 0xe6, 0xdf, 0x1a, 0x50, 0x90,
+	0x62, 0xd0, 0x66, 0xe2, 0xf3, 0x40, 0x5d, 0x10, 0xfc, 0xcd, 0x6a, 0x00, 0x93, 0xd1, 0xc8, 0x0e,
+	0x79, 0x8c, 0x2b, 0xcc, 0xac, 0x84, 0x38, 0x19, 0xce, 0x6f, 0x40, 0x6e, 0x10, 0x3a, 0x27, 0xfc,
+	0x5a, 0x16, 0x2c, 0x0e, 0xe0, 0x62, 0x46, 0x4e, 0x8f, 0x32, 0x2f, 0x98, 0xc5, 0x58, 0x9c, 0x43,
+	0x68, 0x84, 0x3d, 0x27, 0xa2, 0x76, 0x2f, 0x74, 0xbc, 0xbe, 0xcc, 0x9a, 0x01, 0x51, 0x4f, 0x18,
+	0x06, 0x9f, 0xc7, 0xbe, 0x3f, 0x1e, 0xbb, 0xb1, 0x3d, 0xa6, 0x11, 0x86, 0x1a, 0xe2, 0x21, 0x5b,
+	0xe1, 0xd8, 0xe7, 0x1c, 0x69, 0xbe, 0x06, 0x48, 0xac, 0x09, 0x97, 0x7e, 0x8a, 0xd1, 0x8d, 0x58,
+	0xfa, 0xa9, 0xcf, 0xd7, 0xc5, 0x6d, 0x33, 0xa3, 0xdb, 0x26, 0x81, 0x25, 0xb4, 0x3c, 0xe9, 0xb2,
+	0xf1, 0x37, 0xd6, 0x06, 0x42, 0x7a, 0x22, 0x9c, 0x07, 0xfe, 0x44, 0x9f, 0x82, 0x55, 0x8e, 0x28,
+	0xb9, 0x06, 0x0a, 0x36, 0x1f, 0x02, 0x24, 0xc7, 0x8f, 0x63, 0x31, 0x81, 0xe7, 0x13, 0xe3, 0xcf,
+	0xf9, 0xe9, 0xab, 0xf9, 0xcb, 0x0c, 0xac, 0xa4, 0xe2, 0x4e, 0xbc, 0xbc, 0xd1, 0xa4, 0xdf, 0xc7,
+	0x38, 0xd1, 0xe0, 0x29, 0x8f, 0x00, 0xc9, 0x87, 0x3c, 0xf2, 0x99, 0x84, 0xd4, 0xee, 0x33, 0x87,
+	0xc7, 0xb5, 0x5e, 0x16, 0xc8, 0x1d, 0xc4, 0x91, 0xf7, 0x01, 0xfa, 0x8e, 0x67, 0x87, 0x34, 0x18,
+	0x39, 0xe7, 0x42, 0xf7, 0xc5, 0xbe, 0xe3, 0x59, 0x0c, 0x81, 0x32, 0x46, 0xfe, 0xd0, 0x8e, 0xc3,
+	0x89, 0xd7, 0x57, 0xf7, 0xa5, 0x60, 0x95, 0x47, 0xfe, 0xb0, 0x2b, 0x71, 0xe4, 0x6b, 0x6d, 0xa2,
+	0x91, 0x13, 0xf1, 0xa0, 0xb7, 0xc2, 0xcb, 0x04, 0xdf, 0xfb, 0xbd, 0xa7, 0x62, 0x3e, 0x24, 0x25,
+	0xb3, 0x23, 0xc4, 0x5e, 0x45, 0x8c, 0x44, 0xce, 0xe8, 0x80, 0x9d, 0x4f, 0xc1, 0x52, 0x30, 0x1e,
+	0x7d, 0xe0, 0x7a, 0x9e, 0xb8, 0x03, 0x05, 0x4b, 0x40, 0xe6, 0x1f, 0x1b, 0x50, 0x54, 0x01, 0xf3,
+	0x5c, 0x6b, 0x43, 0x7f, 0xe6, 0x9c, 0xb3, 0xaa, 0x96, 0x28, 0x97, 0x09, 0x70, 0xda, 0x35, 0x65,
+	0x67, 0x5c, 0x13, 0x7b, 0x06, 0x4e, 0x1d, 0xcf, 0x4b, 0x4c, 0x4e, 0xc1, 0x4c, 0xd5, 0xb4, 0xaf,
+	0x39, 0x35, 0x09, 0x9a, 0x7f, 0x95, 0x81, 0x95, 0x54, 0x66, 0x35, 0xf7, 0x99, 0xf8, 0x48, 0xac,
+	0x35, 0x93, 0x84, 0x0a, 0x72, 0x50, 0xf7, 0x3c, 0xa0, 0xb3, 0xab, 0xcf, 0xa6, 0x57, 0xff, 0xa6,
+	0xc4, 0x54, 0xe6, 0x5a, 0xb9, 0x0b, 0xe6, 0x5a, 0x2a, 0x91, 0xcd, 0xeb, 0x89, 0xec, 0x36, 0x26,
+	0xb2, 0x74, 0x34, 0xc0, 0x7c, 0x03, 0x3d, 0xd4, 0xfb, 0x33, 0xe9, 0xe2, 0xdd, 0xa7, 0x8c, 0xde,
+	0xf4, 0xe2, 0xf0, 0xdc, 0x12, 0xcc, 0xf5, 0x47, 0x50, 0xd2, 0xd0, 0x17, 0x35, 0xe4, 0x6f, 0x32,
+	0x5f, 0x1b, 0xe6, 0x47, 0x50, 0xe9, 0xc4, 0x7e, 0xb0, 0xa0, 0x64, 0xb0, 0x06, 0xab, 0x8a, 0x8b,
+	0x67, 0xc0, 0xe6, 0xef, 0x00, 0x11, 0x77, 0x87, 0xbe, 0x7d, 0xf0, 0xb4, 0xef, 0xcd, 0x2c, 0xf4,
+	0xbd, 0xe6, 0x63, 0x58, 0x4f, 0xc9, 0xbe, 0x5c, 0xc5, 0xf7, 0x5b, 0x58, 0x69, 0xbb, 0xde, 0x82,
+	0x45, 0x25, 0x96, 0x9d, 0x49, 0x59, 0xf6, 0x57, 0x50, 0x91, 0x83, 0x2f, 0x37, 0xeb, 0x2b, 0x58,
+	0x6b, 0x87, 0xfe, 0xd8, 0x5f, 0xa8, 0x8e, 0xf7, 0x30, 0xea, 0x43, 0x46, 0xb4, 0x61, 0x7e, 0x1e,
+	0x09, 0x62, 0x5a, 0x59, 0xd9, 0xc5, 0xca, 0xba, 0x03, 0x84, 0x97, 0x73, 0xf6, 0x42, 0x27, 0x38,
+	0x7d, 0xdb, 0x29, 0xf6, 0x60, 0x3d, 0xc5, 0x79, 0xa9, 0x0d, 0x92, 0x8f, 0x18, 0xdb, 0x90, 0xca,
+	0x13, 0x2c, 0x27, 0x6c, 0x98, 0x41, 0x71, 0x9a, 0xf9, 0x6f, 0x19, 0x28, 0x48, 0xe4, 0xdc, 0xed,
+	0x4f, 0x5d, 0xff, 0xcc, 0xec, 0xf5, 0xbf, 0x9d, 0xaa, 0x83, 0xa8, 0xd0, 0xca, 0x19, 0xd2, 0xa9,
+	0x15, 0xbd, 0x0f, 0x30, 0xa0, 0x01, 0xf5, 0x06, 0x91, 0xed, 0x7b, 0xc2, 0x53, 0x14, 0x05, 0xa6,
+	0xe5, 0xe9, 0x2f, 0x78, 0xee, 0xdd, 0x22, 0xc2, 0xfc, 0x25, 0x22, 0x8c, 0x6d, 0x28, 0xc8, 0xf6,
+	0x8c, 0x08, 0x18, 0xae, 0xcf, 0x8c, 0xdb, 0x15, 0x0c, 0x96, 0x62, 0x25, 0x9f, 0x41, 0x5e, 0xe4,
+	0xc4, 0x85, 0xa4, 0xae, 0x2b, 0x6f, 0x7c, 0x67, 0x32, 0x1e, 0x3b, 0x78, 0xcf, 0x39, 0x8b, 0xf9,
+	0xdf, 0x19, 0x58, 0x9d, 0xa2, 0xcd, 0xd5, 0xf1, 0xed, 0x54, 0x21, 0xe7, 0x2d, 0x1a, 0xd4, 0x54,
+	0x94, 0x7d, 0x37, 0x15, 0x2d, 0xbd, 0xa3, 0x8a, 0x72, 0x17, 0x57, 0x11, 0x2b, 0x5c, 0x7b, 0x34,
+	0xaa, 0xe5, 0x65, 0xe1, 0xda, 0xa3, 0xec, 0x21, 0x10, 0xcf, 0x98, 0x48, 0x76, 0x25, 0x98, 0x24,
+	0x92, 0x05, 0x3d, 0x91, 0xbc, 0x05, 0x65, 0xb6, 0x7e, 0xdb, 0x3f, 0x39, 0x89, 0x28, 0x8f, 0xbe,
+	0xb2, 0x56, 0x89, 0xe1, 0x5a, 0x0c, 0x85, 0xf6, 0x44, 0xbd, 0x81, 0x64, 0x00, 0xc6, 0x50, 0xa4,
+	0xde, 0x80, 0x93, 0xb9, 0xab, 0x74, 0xc2, 0x8b, 0xb8, 0x4a, 0xc1, 0x25, 0x5c, 0xe5, 0x27, 0x50,
+	0x3d, 0xf6, 0xa2, 0xc5, 0x43, 0xd7, 0x61, 0x4d, 0xe3, 0x13, 0x83, 0x6b, 0x70, 0x15, 0x6b, 0x8f,
+	0x28, 0x33, 0xa4, 0x03, 0xad, 0x7f, 0x60, 0x7e, 0x07, 0xd7, 0x66, 0x28, 0x73, 0x0a, 0xba, 0x6f,
+	0x29, 0x56, 0xff, 0x2e, 0x94, 0x3a, 0xce, 0x19, 0x1d, 0x74, 0x28, 0xbe, 0xf8, 0x73, 0x4d, 0x29,
+	0x29, 0xad, 0x66, 0x2e, 0xd3, 0xa4, 0xc8, 0x2e, 0x6a, 0x52, 0x98, 0x8f, 0x61, 0x0d, 0xe7, 0xe6,
+	0x53, 0x4b, 0xad, 0xa0, 0xe1, 0x32, 0x84, 0xde, 0x05, 0xd2, 0x96, 0x68, 0x09, 0xb2, 0xb9, 0x01,
+	0x44, 0x1f, 0x2d, 0x74, 0xf5, 0x29, 0xac, 0xef, 0xd2, 0x11, 0x8d, 0xa7, 0xa4, 0xce, 0xd3, 0xf5,
+	0x55, 0xd8, 0x48, 0xb3, 0x0a, 0x11, 0x9b, 0xb0, 0xce, 0x94, 0xca, 0xb0, 0x54, 0xe9, 0x7a, 0x07,
+	0x36, 0xd2, 0x68, 0xa1, 0xe8, 0xcf, 0xa0, 0x10, 0x09, 0x9c, 0x50, 0xf5, 0xcc, 0x92, 0x15, 0x83,
+	0xf9, 0xaf, 0x06, 0xc0, 0x2e, 0x0d, 0x46, 0xfe, 0x39, 0x2b, 0x4c, 0xdd, 0x4c, 0x57, 0xb8, 0x44,
+	0x4f, 0x4e, 0x43, 0xcd, 0xe9, 0x74, 0xd5, 0x60, 0x59, 0x96, 0x6d, 0x44, 0x60, 0x22, 0x40, 0xe4,
+	0xc5, 0xce, 0x9e, 0x88, 0x7c, 0x5f, 0xf8, 0xbd, 0xa9, 0xdc, 0x2d, 0xb7, 0x30, 0x77, 0xfb, 0x12,
+	0x0a, 0x03, 0xb6, 0xba, 0x8b, 0x79, 0x3e, 0xc9, 0x6b, 0xbe, 0xe0, 0x16, 0x9a, 0xec, 0x4c, 0x75,
+	0xb8, 0x16, 0xef, 0xb0, 0x06, 0xcb, 0xa7, 0x6e, 0xa4, 0x92, 0xcb, 0x82, 0x25, 0xc1, 0xa4, 0x5d,
+	0x95, 0xd5, 0xdb, 0x55, 0xcf, 0xe0, 0xda, 0xcc, 0x5c, 0xe2, 0x28, 0xee, 0xe1, 0xc3, 0xa2, 0xd0,
+	0x7a, 0xef, 0x2a, 0xe1, 0xb6, 0x74, 0x16, 0xf3, 0x73, 0xb8, 0xc6, 0xdf, 0xc3, 0x76, 0xe8, 0x9f,
+	0x51, 0xcf, 0xf1, 0xfa, 0xf4, 0x6d, 0x26, 0x73, 0x0c, 0xb5, 0x59, 0x76, 0x31, 0x79, 0x1d, 0x0a,
+	0xd4, 0x3b, 0xa3, 0x23, 0x5f, 0x84, 0xc1, 0x65, 0x4b, 0xc1, 0xe8, 0x56, 0x82, 0x49, 0x6f, 0xe4,
+	0xf6, 0x59, 0x7f, 0x50, 0xbe, 0xf8, 0x0c, 0x83, 0xad, 0xc1, 0x3b, 0x40, 0x76, 0x29, 0x6f, 0xf7,
+	0x2c, 0xf0, 0x0f, 0x7f, 0x67, 0xc0, 0x7a, 0x8a, 0xf5, 0x72, 0x0f, 0xf8, 0x3d, 0x28, 0x60, 0xe8,
+	0x89, 0xee, 0x53, 0xbf, 0xcc, 0xa2, 0x8e, 0x85, 0x68, 0x1e, 0x55, 0x2a, 0x2e, 0x7c, 0x9c, 0x58,
+	0x3e, 0x1a, 0xe9, 0xf7, 0xf9, 0x99, 0xaa, 0x13, 0xf2, 0x94, 0x55, 0xb0, 0x60, 0x01, 0x7c, 0xe4,
+	0x7a, 0x2f, 0x79, 0xc8, 0x9e, 0xd4, 0xa5, 0x0f, 0x5d, 0xef, 0xa5, 0xc5, 0x29, 0xe6, 0x2f, 0x0d,
+	0xa8, 0x4e, 0x4f, 0x77, 0xe9, 0x2e, 0x85, 0xea, 0x17, 0x64, 0xde, 0xdc, 0x2f, 0xd0, 0x2a, 0x77,
+	0xd9, 0x74, 0xe5, 0xee, 0xaf, 0x0d, 0x58, 0x9d, 0xda, 0xc1, 0xa5, 0x57, 0x40, 0xb4, 0x1c, 0x42,
+	0xe6, 0x3b, 0x57, 0xd1, 0xe3, 0x3a, 0x91, 0xba, 0x97, 0x02, 0xc2, 0x95, 0xc8, 0xe4, 0x57, 0xd4,
+	0x10, 0x05, 0x88, 0x06, 0xce, 0x53, 0xc2, 0x1c, 0x37, 0x70, 0x06, 0xa0, 0x9c, 0xc8, 0x9f, 0x84,
+	0x7d, 0x99, 0x2b, 0x0b, 0xc8, 0xfc, 0x02, 0x96, 0x85, 0x32, 0xe7, 0xba, 0xe9, 0x19, 0x4f, 0x61,
+	0x4e, 0x60, 0x75, 0x8f, 0xb2, 0x4e, 0x96, 0xba, 0x8e, 0xef, 0x73, 0x87, 0x60, 0xeb, 0x75, 0x9e,
+	0x22, 0x62, 0x5a, 0x88, 0xc0, 0xd2, 0x1e, 0x23, 0xe3, 0x1f, 0x21, 0xa9, 0x80, 0xbf, 0xd1, 0x5d,
+	0xcc, 0xbf, 0x8e, 0x38, 0x6d, 0xec, 0x07, 0xa2, 0xfe, 0x84, 0x3f, 0xcd, 0xbf, 0x37, 0xa0, 0x9a,
+	0xcc, 0x2b, 0x0c, 0xf4, 0x26, 0x2c, 0xbd, 0xf0, 0x7b, 0xf2, 0x4e, 0x6a, 0x81, 0x63, 0x1c, 0x59,
+	0x8c, 0x82, 0x1d, 0x82, 0x68, 0xe4, 0xbf, 0xa2, 0x51, 0x2c, 0x4a, 0x5a, 0x5a, 0x93, 0x15, 0x2b,
+	0x5a, 0x9c, 0xb7, 0x2c, 0x78, 0x78, 0x8d, 0xeb, 0x3e, 0xac, 0x9c, 0x8c, 0x9c, 0x97, 0x2e, 0x0e,
+	0x62, 0xe2, 0xb3, 0x73, 0xc4, 0x97, 0x25, 0x0b, 0xbe, 0x8f, 0xe4, 0x43, 0xd4, 0x79, 0x14, 0x4b,
+	0x1b, 0x5d, 0xe1, 0xad, 0x80, 0x48, 0x2c, 0x97, 0xd3, 0xcc, 0x7f, 0x31, 0xa0, 0xa8, 0x90, 0xe4,
+	0x27, 0x29, 0x2f, 0xca, 0x95, 0xa6, 0x61, 0x50, 0x31, 0x63, 0xdf, 0x53, 0x5f, 0x85, 0x70, 0x80,
+	0xd5, 0x26, 0x26, 0x5e, 0x24, 0x8b, 0x76, 0xf8, 0x3b, 0x5d, 0x3a, 0x5d, 0x5a, 0x5c, 0x3a, 0xcd,
+	0xbd, 0xbd, 0x74, 0x9a, 0x7f, 0x63, 0xe9, 0x74, 0x79, 0xaa, 0x74, 0xfa, 0x2b, 0x15, 0x93, 0xc7,
+	0x91, 0x7c, 0x27, 0x8c, 0xe4, 0x9d, 0x90, 0x6b, 0xcd, 0x68, 0x6b, 0xad, 0x43, 0x41, 0x84, 0x53,
+	0x72, 0x0f, 0x0a, 0xc6, 0x48, 0x4a, 0xfc, 0xb6, 0x43, 0xd9, 0x98, 0x37, 0xac, 0x92, 0xc0, 0x59,
+	0x4e, 0xcc, 0x72, 0x1c, 0xa6, 0x77, 0x8f, 0x46, 0x72, 0x1f, 0x09, 0x82, 0x3c, 0x86, 0xb2, 0x73,
+	0x36, 0xb4, 0x55, 0x2c, 0x98, 0x5f, 0x14, 0x0b, 0x96, 0x9c, 0xb3, 0xa1, 0x04, 0x70, 0xf4, 0xd8,
+	0x79, 0x6d, 0x5f, 0x3c, 0xd8, 0x2e, 0x8d, 0x9d, 0xd7, 0x12, 0x30, 0xff, 0xc1, 0x80, 0xa2, 0x32,
+	0xa8, 0xf9, 0xca, 0x60, 0xd5, 0x56, 0x71, 0xb7, 0x23, 0x51, 0x6e, 0x9e, 0x39, 0xcc, 0xe9, 0x3d,
+	0x2c, 0xfd, 0x9f, 0xf6, 0x90, 0xbb, 0xd4, 0x1e, 0xfe, 0xd1, 0x60, 0x89, 0x1c, 0xde, 0xcb, 0xff,
+	0xb7, 0xfb, 0x2d, 0x0a, 0x67, 0xd9, 0xa4, 0x70, 0x76, 0x0f, 0x72, 0x91, 0xeb, 0xf5, 0xe9, 0x05,
+	0x42, 0x7c, 0xce, 0x88, 0x23, 0xb0, 0x31, 0x35, 0xba, 0x40, 0xba, 0xc5, 0x19, 0xcd, 0x6f, 0x61,
+	0x23, 0xbd, 0x11, 0xe1, 0x30, 0x3e, 0xe4, 0x1d, 0x9d, 0x48, 0x0f, 0x5f, 0x13, 0x2e, 0x4e, 0x33,
+	0xff, 0x27, 0x07, 0x45, 0x85, 0x5c, 0x78, 0x4f, 0xc5, 0x06, 0x33, 0xc9, 0x06, 0xe7, 0x1d, 0xab,
+	0x6e, 0xf7, 0x4b, 0xb3, 0x76, 0x2f, 0xca, 0x7a, 0xdc, 0xee, 0xb9, 0x5d, 0x97, 0x04, 0x8e, 0xd9,
+	0xfd, 0x63, 0x28, 0x07, 0xdb, 0xf7, 0x2e, 0x63, 0xd9, 0xc1, 0xf6, 0x3d, 0xdd, 0x2a, 0x82, 0x47,
+	0xdb, 0x97, 0xb1, 0xec, 0xe0, 0xd1, 0xb6, 0x1a, 0xdd, 0x84, 0x35, 0x9c, 0x9b, 0xf5, 0x96, 0xec,
+	0x91, 0xc3, 0x3e, 0x3d, 0xaa, 0x15, 0x16, 0x89, 0x58, 0x0d, 0xb6, 0xef, 0xfd, 0x80, 0x43, 0x0e,
+	0xf9, 0x08, 0x26, 0xe6, 0xd1, 0xf6, 0x94, 0x98, 0xe2, 0x62, 0x31, 0x8f, 0xb6, 0x53, 0x62, 0x1e,
+	0x43, 0x45, 0xd5, 0x23, 0x9d, 0x49, 0x44, 0xa3, 0x1a, 0xdc, 0xcc, 0xca, 0x8f, 0x1a, 0x64, 0x35,
+	0x12, 0x09, 0xfc, 0x48, 0x57, 0x4e, 0x34, 0x54, 0x44, 0x9e, 0xc1, 0x06, 0xee, 0x85, 0x37, 0xbc,
+	0x68, 0xa2, 0x91, 0xd2, 0xa2, 0x75, 0x90, 0x60, 0xfb, 0x5e, 0x9b, 0x8f, 0x52, 0x8a, 0x41, 0x61,
+	0x8f, 0xb6, 0x67, 0x85, 0x95, 0x17, 0x0b, 0x7b, 0xb4, 0x3d, 0x2d, 0x6c, 0x07, 0xaa, 0xb8, 0xb2,
+	0x70, 0xe2, 0x25, 0x82, 0x56, 0x16, 0x09, 0xaa, 0x04, 0xdb, 0xf7, 0xac, 0x89, 0x97, 0x12, 0xf2,
+	0x68, 0x3b, 0x2d, 0xa4, 0xb2, 0x58, 0xc8, 0xa3, 0x6d, 0x4d, 0x88, 0xd9, 0x87, 0xb5, 0x19, 0x3d,
+	0xce, 0x96, 0x81, 0x8d, 0x8b, 0x96, 0x81, 0x55, 0x38, 0x92, 0xd1, 0xc2, 0x11, 0x4c, 0x93, 0xf0,
+	0x35, 0xa7, 0xe1, 0x19, 0x0d, 0x0f, 0xbc, 0x13, 0x5f, 0xe6, 0x43, 0xbf, 0xce, 0xc0, 0xe6, 0x14,
+	0x41, 0x5c, 0x5d, 0x2d, 0x43, 0x31, 0xd2, 0x19, 0xca, 0x07, 0x50, 0x72, 0x02, 0x57, 0xb5, 0x9d,
+	0xf9, 0x4d, 0x04, 0x27, 0x70, 0x65, 0x7b, 0x1a, 0x2f, 0x1f, 0x75, 0x62, 0xf1, 0xe8, 0xb0, 0xba,
+	0xaf, 0x84, 0x51, 0x6c, 0x30, 0x9a, 0x0c, 0x5d, 0x4f, 0x96, 0x84, 0x25, 0x88, 0x6e, 0x8d, 0xf5,
+	0x44, 0x62, 0x5f, 0xb5, 0xd6, 0xb1, 0x49, 0xd2, 0x41, 0x18, 0x89, 0x58, 0x3b, 0xe7, 0x44, 0x1e,
+	0x51, 0x15, 0x46, 0xfe, 0x90, 0x13, 0x3f, 0x86, 0x8a, 0x33, 0x89, 0x4f, 0xed, 0x20, 0xf4, 0xcf,
+	0xdc, 0x01, 0x0d, 0x79, 0xd5, 0xb5, 0x68, 0xad, 0x20, 0xb6, 0x2d, 0x91, 0xd8, 0x74, 0x61, 0x7d,
+	0x0e, 0x0c, 0xb0, 0x78, 0x49, 0x61, 0x19, 0xe1, 0xe3, 0x10, 0xeb, 0xb5, 0xa5, 0xb1, 0xe3, 0x7a,
+	0x31, 0xcf, 0x06, 0xc4, 0x35, 0x61, 0xca, 0x7e, 0x9e, 0xa0, 0x9f, 0xfb, 0x03, 0x6a, 0xe9, 0x7c,
+	0xe4, 0x2e, 0xac, 0x3b, 0x9e, 0xef, 0x9d, 0x8f, 0xf1, 0x7b, 0xcc, 0x90, 0x3a, 0x03, 0xdb, 0xf7,
+	0x46, 0xe7, 0xac, 0xe2, 0x50, 0xb0, 0xd6, 0x14, 0xc9, 0xa2, 0xce, 0xa0, 0xe5, 0x8d, 0x58, 0xef,
+	0x73, 0x75, 0x4a, 0x20, 0x2a, 0x84, 0x7a, 0x4e, 0x6f, 0x24, 0x3a, 0xce, 0x05, 0x4b, 0x82, 0x7a,
+	0xc8, 0x99, 0x49, 0x87, 0x9c, 0x1f, 0x43, 0x85, 0xdf, 0x6b, 0xd1, 0x8f, 0x8a, 0x44, 0xb3, 0x61,
+	0x85, 0x61, 0x45, 0x8b, 0x2e, 0x7a, 0x07, 0xcf, 0x7f, 0x55, 0x75, 0xbf, 0x79, 0x30, 0x2b, 0x20,
+	0xf3, 0x3b, 0x20, 0xbb, 0xfe, 0x2b, 0x0f, 0x2b, 0xe7, 0x87, 0xfe, 0x70, 0x41, 0x3d, 0x56, 0xd4,
+	0x5d, 0x32, 0xac, 0xee, 0x22, 0x20, 0xb3, 0x01, 0xeb, 0x29, 0x09, 0xc2, 0xca, 0x12, 0x76, 0x43,
+	0x67, 0x47, 0xd1, 0xea, 0xab, 0xa3, 0xb2, 0xc5, 0x7e, 0x9b, 0x5b, 0x3c, 0x77, 0x97, 0x85, 0xb3,
+	0xe8, 0x6d, 0x29, 0xd6, 0x1f, 0x18, 0xb0, 0x39, 0xc5, 0x7c, 0xb9, 0x24, 0x2b, 0xa9, 0xe7, 0x65,
+	0x16, 0xd6, 0xf3, 0xf0, 0xa4, 0x5c, 0x6f, 0x40, 0x5f, 0x8b, 0xf2, 0x5b, 0xc1, 0x92, 0xa0, 0xf9,
+	0x47, 0x19, 0x58, 0xc7, 0xcb, 0xe5, 0xf6, 0x69, 0xa3, 0xcf, 0xae, 0x22, 0xff, 0x04, 0xb7, 0x02,
+	0x19, 0x57, 0x7e, 0xe0, 0x98, 0x71, 0xd9, 0x17, 0xc2, 0x11, 0x67, 0xb3, 0x9d, 0x7e, 0x72, 0x7f,
+	0x8b, 0x56, 0x25, 0x4a, 0x8d, 0x66, 0x79, 0x45, 0xdf, 0x0f, 0xd4, 0xcd, 0x12, 0xd0, 0x3b, 0xb6,
+	0x61, 0x1f, 0xc2, 0x32, 0x7d, 0x1d, 0xb8, 0x78, 0x51, 0x2f, 0x50, 0x5a, 0x15, 0xac, 0xe4, 0x2b,
+	0x28, 0x8e, 0x9c, 0x28, 0xb6, 0x27, 0xd1, 0xc5, 0x2a, 0x0c, 0xc8, 0x7c, 0x1c, 0xd1, 0x01, 0x7a,
+	0x21, 0x3c, 0x94, 0xd6, 0x2b, 0x8f, 0x69, 0x41, 0x55, 0x65, 0xf6, 0x61, 0x73, 0x0a, 0x2f, 0x0e,
+	0xeb, 0x0b, 0xc8, 0xb3, 0xaf, 0x98, 0x65, 0x00, 0xc1, 0x3e, 0xf1, 0x9b, 0xa3, 0x4f, 0x4b, 0xb0,
+	0x99, 0xb7, 0x61, 0xd3, 0xa2, 0x67, 0xfe, 0x4b, 0x2a, 0x65, 0x49, 0x23, 0x99, 0x52, 0x38, 0x96,
+	0xe3, 0xa6, 0x19, 0xf9, 0x9c, 0x5b, 0x36, 0x14, 0xe4, 0x07, 0xac, 0x64, 0x05, 0x8a, 0xad, 0xb6,
+	0xdd, 0xfc, 0xe1, 0xb8, 0x71, 0xd8, 0xa9, 0x5e, 0x21, 0x04, 0x2a, 0xad, 0xb6, 0xdd, 0xe9, 0x36,
+	0xac, 0x6e, 0xc7, 0xfe, 0xf1, 0xa0, 0xbb, 0x5f, 0x35, 0x48, 0x15, 0xca, 0xc8, 0x72, 0xb4, 0x2b,
+	0x30, 0x19, 0xb2, 0x0a, 0xa5, 0x56, 0xdb, 0xde, 0x69, 0x1d, 0x75, 0x1b, 0x07, 0x47, 0x9d, 0x6a,
+	0x56, 0x4a, 0xf9, 0xad, 0x83, 0x4e, 0xb7, 0x53, 0x5d, 0xda, 0x3a, 0x81, 0xb5, 0x99, 0xcf, 0x25,
+	0xc9, 0x1a, 0xac, 0x1c, 0xb6, 0xf6, 0x3a, 0xf6, 0xee, 0x41, 0xa7, 0xf1, 0xe4, 0xb0, 0xb9, 0x5b,
+	0xbd, 0xa2, 0x50, 0xc7, 0x47, 0x9d, 0xc3, 0x83, 0x9d, 0xe6, 0x6e, 0xd5, 0x20, 0x65, 0x28, 0x30,
+	0x94, 0xd5, 0xf8, 0xb1, 0x9a, 0x41, 0xb9, 0x0c, 0xda, 0xef, 0x3e, 0x3f, 0xac, 0x66, 0x49, 0x05,
+	0x80, 0x81, 0xed, 0xc3, 0xc6, 0xc1, 0x51, 0x75, 0x69, 0xeb, 0x07, 0x58, 0x4f, 0xcd, 0x23, 0x3e,
+	0xf4, 0xab, 0x00, 0x74, 0xba, 0x8d, 0xee, 0x71, 0xc7, 0x3e, 0x6c, 0xed, 0x55, 0xaf, 0x90, 0x75,
+	0x58, 0x15, 0xb0, 0x9a, 0xdb, 0x20, 0x9b, 0xb0, 0x26, 0x90, 0x9d, 0xae, 0x75, 0xbc, 0xd3, 0x3d,
+	0xb6, 0x9a, 0xbb, 0xd5, 0xcc, 0xd6, 0x01, 0x94, 0xf5, 0x0f, 0x72, 0x70, 0xec, 0xce, 0x61, 0xb3,
+	0x71, 0x74, 0xdc, 0xb6, 0xdb, 0xcd, 0xa3, 0xdd, 0x83, 0x23, 0x14, 0x58, 0x85, 0xb2, 0x44, 0xee,
+	0xb6, 0x8e, 0x9a, 0x55, 0x03, 0xf5, 0x26, 0x31, 0x4f, 0x1b, 0x07, 0x87, 0x4c, 0xd4, 0xcf, 0xa0,
+	0xa4, 0x7d, 0x66, 0x81, 0x83, 0x3a, 0xdd, 0x66, 0xdb, 0x3e, 0x3e, 0x7a, 0x76, 0xd4, 0xfa, 0xf1,
+	0x88, 0x2b, 0x9b, 0x61, 0x3a, 0xc7, 0x3b, 0x3b, 0xcd, 0xe6, 0x2e, 0x5b, 0xd6, 0x2a, 0x94, 0x18,
+	0x4e, 0x4a, 0x51, 0xc3, 0x3a, 0xcf, 0x0e, 0xda, 0xed, 0xe6, 0x6e, 0x35, 0xbb, 0xf5, 0x2b, 0x83,
+	0x7d, 0x53, 0x24, 0x9c, 0x20, 0xae, 0xb0, 0x6b, 0x1d, 0xec, 0xed, 0x35, 0xad, 0xb4, 0x68, 0x89,
+	0x7c, 0xde, 0x38, 0x3a, 0x6e, 0x1c, 0xf2, 0x73, 0x94, 0xb8, 0xf6, 0x71, 0x07, 0xcf, 0x51, 0x1b,
+	0xba, 0xdb, 0x3c, 0x6c, 0x76, 0x51, 0x3c, 0xd9, 0x80, 0xaa, 0x92, 0xd7, 0xee, 0x74, 0xad, 0x66,
+	0xe3, 0x79, 0x75, 0x09, 0xd5, 0xa5, 0x06, 0x5b, 0xad, 0xe7, 0xad, 0xee, 0x41, 0xeb, 0xa8, 0x9a,
+	0xdb, 0xfa, 0x05, 0x14, 0x64, 0x45, 0x03, 0x4f, 0xb3, 0xbd, 0xdf, 0xe8, 0x34, 0xb5, 0x65, 0xac,
+	0xc3, 0x2a, 0x47, 0xb5, 0xad, 0x66, 0xbb, 0x61, 0xa1, 0xf6, 0x98, 0xae, 0x38, 0x92, 0x99, 0x19,
+	0xe2, 0x32, 0xc9, 0x58, 0xeb, 0xf8, 0xe8, 0x08, 0x51, 0xec, 0xb0, 0x39, 0x8a, 0xa9, 0x78, 0x29,
+	0x61, 0x11, 0x8a, 0xae, 0xe6, 0xb6, 0x7c, 0x58, 0x9d, 0x0a, 0x15, 0x48, 0x0d, 0x36, 0x50, 0x75,
+	0xc7, 0x16, 0x2e, 0x63, 0xe7, 0xb0, 0xd1, 0xe9, 0x1c, 0x3c, 0x3d, 0x60, 0xc6, 0xb6, 0x01, 0x55,
+	0x49, 0xd9, 0xd9, 0x6f, 0xee, 0x3c, 0x6b, 0x1d, 0x77, 0xab, 0x06, 0xa9, 0xc3, 0x55, 0x89, 0x3d,
+	0x38, 0x7a, 0x6a, 0x35, 0x94, 0x31, 0x70, 0xd5, 0x4b, 0x5a, 0xb7, 0xd9, 0xe9, 0x56, 0xb3, 0x5b,
+	0x7f, 0x6e, 0x40, 0x59, 0x6f, 0xc2, 0x32, 0xd3, 0x42, 0xd3, 0xb5, 0x1b, 0x4f, 0x1a, 0x47, 0xb8,
+	0x50, 0x9c, 0x09, 0xcf, 0x90, 0x21, 0xd9, 0x7a, 0xab, 0x46, 0x82, 0x60, 0x3b, 0xe6, 0xdb, 0xe5,
+	0x08, 0xbc, 0x43, 0xcd, 0xa3, 0x2e, 0xdf, 0x2e, 0x47, 0x89, 0xed, 0x2a, 0x18, 0x97, 0x50, 0xcd,
+	0x31, 0x3b, 0x60, 0xb0, 0xd5, 0xec, 0x1c, 0x1f, 0x76, 0xab, 0x79, 0x66, 0x3e, 0x7c, 0x1a, 0xab,
+	0xb5, 0x67, 0x35, 0x3b, 0x9d, 0xea, 0xf2, 0xd6, 0x18, 0x4a, 0x5a, 0xf7, 0x84, 0xcd, 0xd3, 0x6d,
+	0xec, 0xe9, 0x47, 0xa2, 0x50, 0x52, 0xd3, 0x46, 0x82, 0x62, 0x86, 0xd8, 0xe9, 0x48, 0xab, 0x6b,
+	0xec, 0xf1, 0xd9, 0x99, 0x59, 0xf0, 0x4b, 0xb4, 0xa7, 0xef, 0x74, 0xe9, 0xc1, 0x1f, 0xae, 0x42,
+	0xf9, 0x47, 0xfc, 0x67, 0x21, 0xe1, 0xb1, 0xc8, 0x0e, 0xac, 0xa4, 0xfe, 0xcf, 0x87, 0xd4, 0x44,
+	0x43, 0x67, 0xe6, 0x5f, 0x7f, 0xea, 0x1b, 0x8a, 0xa2, 0x37, 0x11, 0xae, 0xdc, 0x31, 0xc8, 0x0e,
+	0x54, 0xd2, 0xff, 0x07, 0x43, 0xae, 0x2b, 0xde, 0xe9, 0xff, 0x8d, 0x79, 0x93, 0x18, 0xd2, 0x82,
+	0x8d, 0x79, 0xff, 0x67, 0x42, 0x3e, 0x50, 0xfc, 0xf3, 0xff, 0x03, 0xe5, 0x8d, 0x02, 0x9b, 0xb0,
+	0x3a, 0xf5, 0x3f, 0x21, 0xa4, 0xae, 0x58, 0x67, 0xfe, 0x51, 0xe4, 0x8d, 0x62, 0xbe, 0x82, 0x82,
+	0xfc, 0x8e, 0x9f, 0xac, 0xcb, 0xef, 0xb9, 0xb5, 0x66, 0x49, 0x7d, 0x23, 0x8d, 0x54, 0x03, 0x1f,
+	0x43, 0x51, 0x7d, 0x6d, 0x4f, 0xb8, 0xf4, 0xa9, 0xcf, 0xf7, 0xeb, 0x9b, 0x53, 0x58, 0x39, 0xf6,
+	0x9e, 0x41, 0xee, 0x43, 0x9e, 0x97, 0x84, 0x09, 0xfb, 0x56, 0x36, 0xf5, 0xed, 0x7d, 0x9d, 0xe8,
+	0x28, 0x35, 0xe1, 0x4f, 0x21, 0xcf, 0xbd, 0x2b, 0x1f, 0x92, 0xf2, 0xb4, 0x75, 0xa2, 0xa3, 0xb4,
+	0x79, 0x1e, 0xc2, 0xb2, 0xe8, 0xbf, 0x13, 0xc2, 0x35, 0xa0, 0xb7, 0xec, 0xeb, 0xeb, 0x29, 0x9c,
+	0xae, 0x14, 0x59, 0x8a, 0xe3, 0x4a, 0x99, 0x2a, 0x08, 0xd6, 0x37, 0xd2, 0x48, 0x35, 0x70, 0x07,
+	0xca, 0x7a, 0x5a, 0x4e, 0xae, 0x09, 0xbe, 0xe9, 0x8a, 0x43, 0xbd, 0x36, 0x4b, 0x50, 0x42, 0x9e,
+	0xb2, 0xff, 0x45, 0x48, 0x32, 0x04, 0x22, 0x99, 0x67, 0xb2, 0x89, 0xfa, 0xf5, 0x39, 0x14, 0x25,
+	0xe7, 0x3b, 0x28, 0x69, 0x1f, 0x03, 0x90, 0xab, 0x5a, 0x2f, 0x5c, 0x2b, 0x98, 0xd7, 0xaf, 0xcd,
+	0xe0, 0x95, 0x84, 0xfb, 0x90, 0xe7, 0x3d, 0x7d, 0xae, 0xf2, 0xd4, 0xc7, 0x01, 0x75, 0xa2, 0xa3,
+	0xd4, 0x90, 0x6f, 0x01, 0x92, 0x6e, 0x3e, 0x61, 0x16, 0x30, 0xd3, 0xdd, 0x7f, 0xa3, 0x31, 0x7e,
+	0x07, 0x25, 0xad, 0xcf, 0xce, 0x57, 0x3c, 0xdb, 0xa2, 0xaf, 0x5f, 0x9b, 0xc1, 0x2b, 0x09, 0xec,
+	0xbc, 0x9d, 0x50, 0x3b, 0x6f, 0x27, 0x9c, 0x3d, 0xef, 0x74, 0xa3, 0xf0, 0x0a, 0xf9, 0x06, 0x8a,
+	0xaa, 0x7f, 0xc8, 0x6d, 0x79, 0xba, 0xed, 0x58, 0xdf, 0x9c, 0xc2, 0xaa, 0xb1, 0x87, 0xfc, 0xff,
+	0x93, 0xb4, 0x66, 0x22, 0xbf, 0x87, 0xf3, 0x7b, 0x8f, 0xf5, 0x1b, 0x73, 0x69, 0x4a, 0xda, 0x6f,
+	0x02, 0x24, 0xed, 0x39, 0xae, 0xbe, 0x99, 0x66, 0x5f, 0xfd, 0xea, 0x34, 0x5a, 0xb7, 0x3f, 0xbd,
+	0x39, 0xc7, 0xed, 0x6f, 0x4e, 0x67, 0xaf, 0x5e, 0x9b, 0x25, 0xe8, 0x42, 0xf4, 0x96, 0x1d, 0x51,
+	0xff, 0xe6, 0x31, 0xd5, 0xdb, 0xab, 0xd7, 0x66, 0x09, 0xd3, 0x6a, 0xd1, 0xfa, 0x4d, 0x89, 0x5a,
+	0x66, 0x1b, 0x5e, 0xf5, 0x1b, 0x73, 0x69, 0x9a, 0xf7, 0xac, 0x4e, 0x77, 0x90, 0xc8, 0x8d, 0xc4,
+	0x0a, 0x66, 0xda, 0x50, 0xf5, 0xf7, 0xe6, 0x13, 0x75, 0x4b, 0xd3, 0x1a, 0x42, 0xdc, 0xd2, 0x66,
+	0x9b, 0x49, 0xf5, 0x6b, 0x33, 0x78, 0x25, 0xe1, 0x09, 0x94, 0xb4, 0xfc, 0x4a, 0x48, 0x98, 0x49,
+	0xd9, 0xea, 0xd7, 0x66, 0xf0, 0x9a, 0x77, 0x7a, 0xca, 0xff, 0x2d, 0x48, 0xe5, 0x4c, 0x44, 0x69,
+	0x74, 0x3a, 0xe7, 0xaa, 0x5f, 0x9f, 0x43, 0xd1, 0x3d, 0x46, 0x2a, 0x9c, 0x4f, 0xe4, 0x4c, 0x47,
+	0xfe, 0xf5, 0xeb, 0x73, 0x28, 0x4a, 0xce, 0x01, 0x54, 0xd2, 0x31, 0x3a, 0x7f, 0xe9, 0xe6, 0x06,
+	0xf8, 0xf5, 0xfa, 0x3c, 0x92, 0x14, 0xd5, 0xcb, 0xb3, 0xbc, 0xe4, 0xa7, 0xff, 0x3b, 0x00, 0x9d,
+	0xa2, 0x80, 0x5e, 0xbd, 0x3b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1719,6 +6151,8 @@ type WerftServiceClient interface {
 	// StartFromPreviousJob starts a new job based on a previous one.
 	// If the previous job does not have the can-replay condition set this call will result in an error.
 	StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
+	// StartTarballJob starts a job on the content of a gzipped tarball, e.g. a release archive.
+	StartTarballJob(ctx context.Context, in *StartTarballJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
 	// Searches for jobs known to this instance
 	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
 	// Subscribe listens to new jobs/job updates
@@ -1729,6 +6163,51 @@ type WerftServiceClient interface {
 	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error)
 	// StopJob stops a currently running job
 	StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error)
+	// GetStats aggregates durations and failure rates of past jobs of a repository
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// GetRepoStats returns service level statistics of repositories and their refs over a time window, e.g. for dashboards.
+	// The statistics are aggregated per day as jobs finish, hence are fast to query over long time windows.
+	GetRepoStats(ctx context.Context, in *GetRepoStatsRequest, opts ...grpc.CallOption) (*GetRepoStatsResponse, error)
+	// GetServerInfo returns the version and capabilities of this server
+	GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error)
+	// AnnotateJob adds or updates annotations of a job, e.g. to attach a deployment URL after the job started
+	AnnotateJob(ctx context.Context, in *AnnotateJobRequest, opts ...grpc.CallOption) (*AnnotateJobResponse, error)
+	// PinJob pins or unpins a job. Pruning never removes pinned jobs, e.g. release builds which must remain auditable.
+	PinJob(ctx context.Context, in *PinJobRequest, opts ...grpc.CallOption) (*PinJobResponse, error)
+	// PromoteJob releases what a successful job built without building it again. It starts the promotion job
+	// configured in the repository, which runs on the same revision and links back to the promoted job.
+	PromoteJob(ctx context.Context, in *PromoteJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
+	// GetJobGraph returns the stages of a job with their dependencies, status, durations and log slices in one call
+	GetJobGraph(ctx context.Context, in *GetJobGraphRequest, opts ...grpc.CallOption) (*GetJobGraphResponse, error)
+	// StarJob adds a job to the starred jobs of the authenticated user
+	StarJob(ctx context.Context, in *StarJobRequest, opts ...grpc.CallOption) (*StarJobResponse, error)
+	// UnstarJob removes a job from the starred jobs of the authenticated user
+	UnstarJob(ctx context.Context, in *UnstarJobRequest, opts ...grpc.CallOption) (*UnstarJobResponse, error)
+	// ListStarredJobs returns the starred jobs of the authenticated user
+	ListStarredJobs(ctx context.Context, in *ListStarredJobsRequest, opts ...grpc.CallOption) (*ListStarredJobsResponse, error)
+	// SaveSearch stores a search of the authenticated user. Saving a search with the name of an existing one replaces it.
+	SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SaveSearchResponse, error)
+	// DeleteSearch removes a saved search of the authenticated user
+	DeleteSearch(ctx context.Context, in *DeleteSearchRequest, opts ...grpc.CallOption) (*DeleteSearchResponse, error)
+	// ListSearches returns the saved searches of the authenticated user
+	ListSearches(ctx context.Context, in *ListSearchesRequest, opts ...grpc.CallOption) (*ListSearchesResponse, error)
+	// ListDeployments returns what's currently deployed to each environment, or the deployment history of a single environment
+	ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error)
+	// GetJobProvenance returns the signed provenance attestation of a successful job
+	GetJobProvenance(ctx context.Context, in *GetJobProvenanceRequest, opts ...grpc.CallOption) (*GetJobProvenanceResponse, error)
+	// DescribeJob returns everything there is to know about a job: its status, phase timeline, Kubernetes events and links
+	DescribeJob(ctx context.Context, in *DescribeJobRequest, opts ...grpc.CallOption) (*DescribeJobResponse, error)
+	// DownloadLog streams the complete stored log of a job as is, starting at an offset to resume interrupted downloads
+	DownloadLog(ctx context.Context, in *DownloadLogRequest, opts ...grpc.CallOption) (WerftService_DownloadLogClient, error)
+	// ListLogSlices returns the slice index of a job's log without the log content, s.t. the UI can show the outline
+	// of a job right away and load the slice content on demand
+	ListLogSlices(ctx context.Context, in *ListLogSlicesRequest, opts ...grpc.CallOption) (*ListLogSlicesResponse, error)
+	// ListOwnTokens lists the tokens of the service account whose token authenticates the request, without revealing them.
+	// Unlike ListServiceAccountTokens this needs no admin scope.
+	ListOwnTokens(ctx context.Context, in *ListOwnTokensRequest, opts ...grpc.CallOption) (*ListOwnTokensResponse, error)
+	// RevokeOwnToken makes a token of the service account whose token authenticates the request stop working immediately,
+	// e.g. because it leaked. Unlike RevokeServiceAccountToken this needs no admin scope.
+	RevokeOwnToken(ctx context.Context, in *RevokeOwnTokenRequest, opts ...grpc.CallOption) (*RevokeOwnTokenResponse, error)
 }
 
 type werftServiceClient struct {
@@ -1782,30 +6261,265 @@ func (c *werftServiceClient) StartGitHubJob(ctx context.Context, in *StartGitHub
 	return out, nil
 }
 
-func (c *werftServiceClient) StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
-	out := new(StartJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/StartFromPreviousJob", in, out, opts...)
+func (c *werftServiceClient) StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StartFromPreviousJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) StartTarballJob(ctx context.Context, in *StartTarballJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StartTarballJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[1], "/v1.WerftService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_SubscribeClient interface {
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
+	m := new(SubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[2], "/v1.WerftService/Listen", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceListenClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_ListenClient interface {
+	Recv() (*ListenResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceListenClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceListenClient) Recv() (*ListenResponse, error) {
+	m := new(ListenResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error) {
+	out := new(StopJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StopJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetRepoStats(ctx context.Context, in *GetRepoStatsRequest, opts ...grpc.CallOption) (*GetRepoStatsResponse, error) {
+	out := new(GetRepoStatsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetRepoStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error) {
+	out := new(GetServerInfoResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetServerInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) AnnotateJob(ctx context.Context, in *AnnotateJobRequest, opts ...grpc.CallOption) (*AnnotateJobResponse, error) {
+	out := new(AnnotateJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/AnnotateJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) PinJob(ctx context.Context, in *PinJobRequest, opts ...grpc.CallOption) (*PinJobResponse, error) {
+	out := new(PinJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/PinJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) PromoteJob(ctx context.Context, in *PromoteJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/PromoteJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobGraph(ctx context.Context, in *GetJobGraphRequest, opts ...grpc.CallOption) (*GetJobGraphResponse, error) {
+	out := new(GetJobGraphResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) StarJob(ctx context.Context, in *StarJobRequest, opts ...grpc.CallOption) (*StarJobResponse, error) {
+	out := new(StarJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StarJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) UnstarJob(ctx context.Context, in *UnstarJobRequest, opts ...grpc.CallOption) (*UnstarJobResponse, error) {
+	out := new(UnstarJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/UnstarJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListStarredJobs(ctx context.Context, in *ListStarredJobsRequest, opts ...grpc.CallOption) (*ListStarredJobsResponse, error) {
+	out := new(ListStarredJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListStarredJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SaveSearchResponse, error) {
+	out := new(SaveSearchResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/SaveSearch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) DeleteSearch(ctx context.Context, in *DeleteSearchRequest, opts ...grpc.CallOption) (*DeleteSearchResponse, error) {
+	out := new(DeleteSearchResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/DeleteSearch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListSearches(ctx context.Context, in *ListSearchesRequest, opts ...grpc.CallOption) (*ListSearchesResponse, error) {
+	out := new(ListSearchesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListSearches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error) {
+	out := new(ListDeploymentsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListDeployments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobProvenance(ctx context.Context, in *GetJobProvenanceRequest, opts ...grpc.CallOption) (*GetJobProvenanceResponse, error) {
+	out := new(GetJobProvenanceResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobProvenance", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *werftServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
-	out := new(ListJobsResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/ListJobs", in, out, opts...)
+func (c *werftServiceClient) DescribeJob(ctx context.Context, in *DescribeJobRequest, opts ...grpc.CallOption) (*DescribeJobResponse, error) {
+	out := new(DescribeJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/DescribeJob", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *werftServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[1], "/v1.WerftService/Subscribe", opts...)
+func (c *werftServiceClient) DownloadLog(ctx context.Context, in *DownloadLogRequest, opts ...grpc.CallOption) (WerftService_DownloadLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[3], "/v1.WerftService/DownloadLog", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &werftServiceSubscribeClient{stream}
+	x := &werftServiceDownloadLogClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1815,67 +6529,44 @@ func (c *werftServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest
 	return x, nil
 }
 
-type WerftService_SubscribeClient interface {
-	Recv() (*SubscribeResponse, error)
+type WerftService_DownloadLogClient interface {
+	Recv() (*DownloadLogResponse, error)
 	grpc.ClientStream
 }
 
-type werftServiceSubscribeClient struct {
+type werftServiceDownloadLogClient struct {
 	grpc.ClientStream
 }
 
-func (x *werftServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
-	m := new(SubscribeResponse)
+func (x *werftServiceDownloadLogClient) Recv() (*DownloadLogResponse, error) {
+	m := new(DownloadLogResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *werftServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
-	out := new(GetJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJob", in, out, opts...)
+func (c *werftServiceClient) ListLogSlices(ctx context.Context, in *ListLogSlicesRequest, opts ...grpc.CallOption) (*ListLogSlicesResponse, error) {
+	out := new(ListLogSlicesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListLogSlices", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *werftServiceClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[2], "/v1.WerftService/Listen", opts...)
+func (c *werftServiceClient) ListOwnTokens(ctx context.Context, in *ListOwnTokensRequest, opts ...grpc.CallOption) (*ListOwnTokensResponse, error) {
+	out := new(ListOwnTokensResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListOwnTokens", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &werftServiceListenClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type WerftService_ListenClient interface {
-	Recv() (*ListenResponse, error)
-	grpc.ClientStream
-}
-
-type werftServiceListenClient struct {
-	grpc.ClientStream
-}
-
-func (x *werftServiceListenClient) Recv() (*ListenResponse, error) {
-	m := new(ListenResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+	return out, nil
 }
 
-func (c *werftServiceClient) StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error) {
-	out := new(StopJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/StopJob", in, out, opts...)
+func (c *werftServiceClient) RevokeOwnToken(ctx context.Context, in *RevokeOwnTokenRequest, opts ...grpc.CallOption) (*RevokeOwnTokenResponse, error) {
+	out := new(RevokeOwnTokenResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/RevokeOwnToken", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1896,6 +6587,8 @@ type WerftServiceServer interface {
 	// StartFromPreviousJob starts a new job based on a previous one.
 	// If the previous job does not have the can-replay condition set this call will result in an error.
 	StartFromPreviousJob(context.Context, *StartFromPreviousJobRequest) (*StartJobResponse, error)
+	// StartTarballJob starts a job on the content of a gzipped tarball, e.g. a release archive.
+	StartTarballJob(context.Context, *StartTarballJobRequest) (*StartJobResponse, error)
 	// Searches for jobs known to this instance
 	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
 	// Subscribe listens to new jobs/job updates
@@ -1906,6 +6599,51 @@ type WerftServiceServer interface {
 	Listen(*ListenRequest, WerftService_ListenServer) error
 	// StopJob stops a currently running job
 	StopJob(context.Context, *StopJobRequest) (*StopJobResponse, error)
+	// GetStats aggregates durations and failure rates of past jobs of a repository
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// GetRepoStats returns service level statistics of repositories and their refs over a time window, e.g. for dashboards.
+	// The statistics are aggregated per day as jobs finish, hence are fast to query over long time windows.
+	GetRepoStats(context.Context, *GetRepoStatsRequest) (*GetRepoStatsResponse, error)
+	// GetServerInfo returns the version and capabilities of this server
+	GetServerInfo(context.Context, *GetServerInfoRequest) (*GetServerInfoResponse, error)
+	// AnnotateJob adds or updates annotations of a job, e.g. to attach a deployment URL after the job started
+	AnnotateJob(context.Context, *AnnotateJobRequest) (*AnnotateJobResponse, error)
+	// PinJob pins or unpins a job. Pruning never removes pinned jobs, e.g. release builds which must remain auditable.
+	PinJob(context.Context, *PinJobRequest) (*PinJobResponse, error)
+	// PromoteJob releases what a successful job built without building it again. It starts the promotion job
+	// configured in the repository, which runs on the same revision and links back to the promoted job.
+	PromoteJob(context.Context, *PromoteJobRequest) (*StartJobResponse, error)
+	// GetJobGraph returns the stages of a job with their dependencies, status, durations and log slices in one call
+	GetJobGraph(context.Context, *GetJobGraphRequest) (*GetJobGraphResponse, error)
+	// StarJob adds a job to the starred jobs of the authenticated user
+	StarJob(context.Context, *StarJobRequest) (*StarJobResponse, error)
+	// UnstarJob removes a job from the starred jobs of the authenticated user
+	UnstarJob(context.Context, *UnstarJobRequest) (*UnstarJobResponse, error)
+	// ListStarredJobs returns the starred jobs of the authenticated user
+	ListStarredJobs(context.Context, *ListStarredJobsRequest) (*ListStarredJobsResponse, error)
+	// SaveSearch stores a search of the authenticated user. Saving a search with the name of an existing one replaces it.
+	SaveSearch(context.Context, *SaveSearchRequest) (*SaveSearchResponse, error)
+	// DeleteSearch removes a saved search of the authenticated user
+	DeleteSearch(context.Context, *DeleteSearchRequest) (*DeleteSearchResponse, error)
+	// ListSearches returns the saved searches of the authenticated user
+	ListSearches(context.Context, *ListSearchesRequest) (*ListSearchesResponse, error)
+	// ListDeployments returns what's currently deployed to each environment, or the deployment history of a single environment
+	ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error)
+	// GetJobProvenance returns the signed provenance attestation of a successful job
+	GetJobProvenance(context.Context, *GetJobProvenanceRequest) (*GetJobProvenanceResponse, error)
+	// DescribeJob returns everything there is to know about a job: its status, phase timeline, Kubernetes events and links
+	DescribeJob(context.Context, *DescribeJobRequest) (*DescribeJobResponse, error)
+	// DownloadLog streams the complete stored log of a job as is, starting at an offset to resume interrupted downloads
+	DownloadLog(*DownloadLogRequest, WerftService_DownloadLogServer) error
+	// ListLogSlices returns the slice index of a job's log without the log content, s.t. the UI can show the outline
+	// of a job right away and load the slice content on demand
+	ListLogSlices(context.Context, *ListLogSlicesRequest) (*ListLogSlicesResponse, error)
+	// ListOwnTokens lists the tokens of the service account whose token authenticates the request, without revealing them.
+	// Unlike ListServiceAccountTokens this needs no admin scope.
+	ListOwnTokens(context.Context, *ListOwnTokensRequest) (*ListOwnTokensResponse, error)
+	// RevokeOwnToken makes a token of the service account whose token authenticates the request stop working immediately,
+	// e.g. because it leaked. Unlike RevokeServiceAccountToken this needs no admin scope.
+	RevokeOwnToken(context.Context, *RevokeOwnTokenRequest) (*RevokeOwnTokenResponse, error)
 }
 
 // UnimplementedWerftServiceServer can be embedded to have forward compatible implementations.
@@ -1921,6 +6659,9 @@ func (*UnimplementedWerftServiceServer) StartGitHubJob(ctx context.Context, req
 func (*UnimplementedWerftServiceServer) StartFromPreviousJob(ctx context.Context, req *StartFromPreviousJobRequest) (*StartJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartFromPreviousJob not implemented")
 }
+func (*UnimplementedWerftServiceServer) StartTarballJob(ctx context.Context, req *StartTarballJobRequest) (*StartJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTarballJob not implemented")
+}
 func (*UnimplementedWerftServiceServer) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
 }
@@ -1933,168 +6674,609 @@ func (*UnimplementedWerftServiceServer) GetJob(ctx context.Context, req *GetJobR
 func (*UnimplementedWerftServiceServer) Listen(req *ListenRequest, srv WerftService_ListenServer) error {
 	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
 }
-func (*UnimplementedWerftServiceServer) StopJob(ctx context.Context, req *StopJobRequest) (*StopJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StopJob not implemented")
+func (*UnimplementedWerftServiceServer) StopJob(ctx context.Context, req *StopJobRequest) (*StopJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetStats(ctx context.Context, req *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetRepoStats(ctx context.Context, req *GetRepoStatsRequest) (*GetRepoStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepoStats not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetServerInfo(ctx context.Context, req *GetServerInfoRequest) (*GetServerInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerInfo not implemented")
+}
+func (*UnimplementedWerftServiceServer) AnnotateJob(ctx context.Context, req *AnnotateJobRequest) (*AnnotateJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnotateJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) PinJob(ctx context.Context, req *PinJobRequest) (*PinJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) PromoteJob(ctx context.Context, req *PromoteJobRequest) (*StartJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobGraph(ctx context.Context, req *GetJobGraphRequest) (*GetJobGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobGraph not implemented")
+}
+func (*UnimplementedWerftServiceServer) StarJob(ctx context.Context, req *StarJobRequest) (*StarJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StarJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) UnstarJob(ctx context.Context, req *UnstarJobRequest) (*UnstarJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnstarJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListStarredJobs(ctx context.Context, req *ListStarredJobsRequest) (*ListStarredJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStarredJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) SaveSearch(ctx context.Context, req *SaveSearchRequest) (*SaveSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveSearch not implemented")
+}
+func (*UnimplementedWerftServiceServer) DeleteSearch(ctx context.Context, req *DeleteSearchRequest) (*DeleteSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSearch not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListSearches(ctx context.Context, req *ListSearchesRequest) (*ListSearchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSearches not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListDeployments(ctx context.Context, req *ListDeploymentsRequest) (*ListDeploymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeployments not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobProvenance(ctx context.Context, req *GetJobProvenanceRequest) (*GetJobProvenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobProvenance not implemented")
+}
+func (*UnimplementedWerftServiceServer) DescribeJob(ctx context.Context, req *DescribeJobRequest) (*DescribeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) DownloadLog(req *DownloadLogRequest, srv WerftService_DownloadLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadLog not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListLogSlices(ctx context.Context, req *ListLogSlicesRequest) (*ListLogSlicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLogSlices not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListOwnTokens(ctx context.Context, req *ListOwnTokensRequest) (*ListOwnTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOwnTokens not implemented")
+}
+func (*UnimplementedWerftServiceServer) RevokeOwnToken(ctx context.Context, req *RevokeOwnTokenRequest) (*RevokeOwnTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeOwnToken not implemented")
+}
+
+func RegisterWerftServiceServer(s *grpc.Server, srv WerftServiceServer) {
+	s.RegisterService(&_WerftService_serviceDesc, srv)
+}
+
+func _WerftService_StartLocalJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WerftServiceServer).StartLocalJob(&werftServiceStartLocalJobServer{stream})
+}
+
+type WerftService_StartLocalJobServer interface {
+	SendAndClose(*StartJobResponse) error
+	Recv() (*StartLocalJobRequest, error)
+	grpc.ServerStream
+}
+
+type werftServiceStartLocalJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceStartLocalJobServer) SendAndClose(m *StartJobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *werftServiceStartLocalJobServer) Recv() (*StartLocalJobRequest, error) {
+	m := new(StartLocalJobRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WerftService_StartGitHubJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartGitHubJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StartGitHubJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StartGitHubJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StartGitHubJob(ctx, req.(*StartGitHubJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_StartFromPreviousJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartFromPreviousJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StartFromPreviousJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, req.(*StartFromPreviousJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_StartTarballJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTarballJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StartTarballJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StartTarballJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StartTarballJob(ctx, req.(*StartTarballJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WerftServiceServer).Subscribe(m, &werftServiceSubscribeServer{stream})
+}
+
+type WerftService_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	grpc.ServerStream
+}
+
+type werftServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceSubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WerftService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WerftServiceServer).Listen(m, &werftServiceListenServer{stream})
+}
+
+type WerftService_ListenServer interface {
+	Send(*ListenResponse) error
+	grpc.ServerStream
+}
+
+type werftServiceListenServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceListenServer) Send(m *ListenResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WerftService_StopJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StopJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StopJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StopJob(ctx, req.(*StopJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_GetRepoStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRepoStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetRepoStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetRepoStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetRepoStats(ctx, req.(*GetRepoStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWerftServiceServer(s *grpc.Server, srv WerftServiceServer) {
-	s.RegisterService(&_WerftService_serviceDesc, srv)
+func _WerftService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetServerInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetServerInfo(ctx, req.(*GetServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartLocalJob_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(WerftServiceServer).StartLocalJob(&werftServiceStartLocalJobServer{stream})
+func _WerftService_AnnotateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnotateJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).AnnotateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/AnnotateJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).AnnotateJob(ctx, req.(*AnnotateJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type WerftService_StartLocalJobServer interface {
-	SendAndClose(*StartJobResponse) error
-	Recv() (*StartLocalJobRequest, error)
-	grpc.ServerStream
+func _WerftService_PinJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).PinJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/PinJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).PinJob(ctx, req.(*PinJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type werftServiceStartLocalJobServer struct {
-	grpc.ServerStream
+func _WerftService_PromoteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).PromoteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/PromoteJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).PromoteJob(ctx, req.(*PromoteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceStartLocalJobServer) SendAndClose(m *StartJobResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WerftService_GetJobGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJobGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJobGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJobGraph(ctx, req.(*GetJobGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceStartLocalJobServer) Recv() (*StartLocalJobRequest, error) {
-	m := new(StartLocalJobRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _WerftService_StarJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StarJobRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StarJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StarJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StarJob(ctx, req.(*StarJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartGitHubJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartGitHubJobRequest)
+func _WerftService_UnstarJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnstarJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StartGitHubJob(ctx, in)
+		return srv.(WerftServiceServer).UnstarJob(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StartGitHubJob",
+		FullMethod: "/v1.WerftService/UnstarJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StartGitHubJob(ctx, req.(*StartGitHubJobRequest))
+		return srv.(WerftServiceServer).UnstarJob(ctx, req.(*UnstarJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartFromPreviousJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartFromPreviousJobRequest)
+func _WerftService_ListStarredJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStarredJobsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, in)
+		return srv.(WerftServiceServer).ListStarredJobs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StartFromPreviousJob",
+		FullMethod: "/v1.WerftService/ListStarredJobs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, req.(*StartFromPreviousJobRequest))
+		return srv.(WerftServiceServer).ListStarredJobs(ctx, req.(*ListStarredJobsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListJobsRequest)
+func _WerftService_SaveSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveSearchRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).ListJobs(ctx, in)
+		return srv.(WerftServiceServer).SaveSearch(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/ListJobs",
+		FullMethod: "/v1.WerftService/SaveSearch",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+		return srv.(WerftServiceServer).SaveSearch(ctx, req.(*SaveSearchRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _WerftService_DeleteSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(WerftServiceServer).Subscribe(m, &werftServiceSubscribeServer{stream})
+	if interceptor == nil {
+		return srv.(WerftServiceServer).DeleteSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/DeleteSearch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).DeleteSearch(ctx, req.(*DeleteSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type WerftService_SubscribeServer interface {
-	Send(*SubscribeResponse) error
-	grpc.ServerStream
+func _WerftService_ListSearches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSearchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListSearches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListSearches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListSearches(ctx, req.(*ListSearchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type werftServiceSubscribeServer struct {
-	grpc.ServerStream
+func _WerftService_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListDeployments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListDeployments(ctx, req.(*ListDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceSubscribeServer) Send(m *SubscribeResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WerftService_GetJobProvenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobProvenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJobProvenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJobProvenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJobProvenance(ctx, req.(*GetJobProvenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetJobRequest)
+func _WerftService_DescribeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).GetJob(ctx, in)
+		return srv.(WerftServiceServer).DescribeJob(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/GetJob",
+		FullMethod: "/v1.WerftService/DescribeJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).GetJob(ctx, req.(*GetJobRequest))
+		return srv.(WerftServiceServer).DescribeJob(ctx, req.(*DescribeJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListenRequest)
+func _WerftService_DownloadLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadLogRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(WerftServiceServer).Listen(m, &werftServiceListenServer{stream})
+	return srv.(WerftServiceServer).DownloadLog(m, &werftServiceDownloadLogServer{stream})
 }
 
-type WerftService_ListenServer interface {
-	Send(*ListenResponse) error
+type WerftService_DownloadLogServer interface {
+	Send(*DownloadLogResponse) error
 	grpc.ServerStream
 }
 
-type werftServiceListenServer struct {
+type werftServiceDownloadLogServer struct {
 	grpc.ServerStream
 }
 
-func (x *werftServiceListenServer) Send(m *ListenResponse) error {
+func (x *werftServiceDownloadLogServer) Send(m *DownloadLogResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _WerftService_StopJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopJobRequest)
+func _WerftService_ListLogSlices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLogSlicesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StopJob(ctx, in)
+		return srv.(WerftServiceServer).ListLogSlices(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StopJob",
+		FullMethod: "/v1.WerftService/ListLogSlices",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StopJob(ctx, req.(*StopJobRequest))
+		return srv.(WerftServiceServer).ListLogSlices(ctx, req.(*ListLogSlicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_ListOwnTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOwnTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListOwnTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListOwnTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListOwnTokens(ctx, req.(*ListOwnTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_RevokeOwnToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeOwnTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).RevokeOwnToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/RevokeOwnToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).RevokeOwnToken(ctx, req.(*RevokeOwnTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -2111,6 +7293,10 @@ var _WerftService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StartFromPreviousJob",
 			Handler:    _WerftService_StartFromPreviousJob_Handler,
 		},
+		{
+			MethodName: "StartTarballJob",
+			Handler:    _WerftService_StartTarballJob_Handler,
+		},
 		{
 			MethodName: "ListJobs",
 			Handler:    _WerftService_ListJobs_Handler,
@@ -2123,6 +7309,82 @@ var _WerftService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StopJob",
 			Handler:    _WerftService_StopJob_Handler,
 		},
+		{
+			MethodName: "GetStats",
+			Handler:    _WerftService_GetStats_Handler,
+		},
+		{
+			MethodName: "GetRepoStats",
+			Handler:    _WerftService_GetRepoStats_Handler,
+		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _WerftService_GetServerInfo_Handler,
+		},
+		{
+			MethodName: "AnnotateJob",
+			Handler:    _WerftService_AnnotateJob_Handler,
+		},
+		{
+			MethodName: "PinJob",
+			Handler:    _WerftService_PinJob_Handler,
+		},
+		{
+			MethodName: "PromoteJob",
+			Handler:    _WerftService_PromoteJob_Handler,
+		},
+		{
+			MethodName: "GetJobGraph",
+			Handler:    _WerftService_GetJobGraph_Handler,
+		},
+		{
+			MethodName: "StarJob",
+			Handler:    _WerftService_StarJob_Handler,
+		},
+		{
+			MethodName: "UnstarJob",
+			Handler:    _WerftService_UnstarJob_Handler,
+		},
+		{
+			MethodName: "ListStarredJobs",
+			Handler:    _WerftService_ListStarredJobs_Handler,
+		},
+		{
+			MethodName: "SaveSearch",
+			Handler:    _WerftService_SaveSearch_Handler,
+		},
+		{
+			MethodName: "DeleteSearch",
+			Handler:    _WerftService_DeleteSearch_Handler,
+		},
+		{
+			MethodName: "ListSearches",
+			Handler:    _WerftService_ListSearches_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _WerftService_ListDeployments_Handler,
+		},
+		{
+			MethodName: "GetJobProvenance",
+			Handler:    _WerftService_GetJobProvenance_Handler,
+		},
+		{
+			MethodName: "DescribeJob",
+			Handler:    _WerftService_DescribeJob_Handler,
+		},
+		{
+			MethodName: "ListLogSlices",
+			Handler:    _WerftService_ListLogSlices_Handler,
+		},
+		{
+			MethodName: "ListOwnTokens",
+			Handler:    _WerftService_ListOwnTokens_Handler,
+		},
+		{
+			MethodName: "RevokeOwnToken",
+			Handler:    _WerftService_RevokeOwnToken_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2140,6 +7402,11 @@ var _WerftService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _WerftService_Listen_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DownloadLog",
+			Handler:       _WerftService_DownloadLog_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "werft.proto",
 }
@@ -0,0 +1,176 @@
+package devproxy
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TokenParam is the query parameter which carries the token when opening the proxied web UI in a browser
+	TokenParam = "devproxy-token"
+
+	// tokenCookie keeps the token once a browser presented it
+	tokenCookie = "werft-devproxy"
+)
+
+// Config configures the proxy which serves the web UI from a development server
+type Config struct {
+	// Target is the URL of the development server, e.g. http://localhost:3000. The proxy is disabled if this is empty.
+	Target string `yaml:"target,omitempty"`
+
+	// Token protects the proxy. Browsers present it once using the devproxy-token query parameter and keep it in a
+	// cookie, other clients send it as bearer token. Without a token anyone who can reach werft can use the proxy.
+	Token string `yaml:"token,omitempty"`
+
+	// AllowedPaths are the path prefixes which are proxied, e.g. /static or /sockjs-node. Requests to other paths are
+	// rejected. Defaults to all paths.
+	AllowedPaths []string `yaml:"allowedPaths,omitempty"`
+}
+
+// Enabled returns true if the web UI is to be proxied
+func (c Config) Enabled() bool {
+	return c.Target != ""
+}
+
+// Validate checks if the config is complete and sound, naming the offending field
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	tgt, err := url.Parse(c.Target)
+	if err != nil || (tgt.Scheme != "http" && tgt.Scheme != "https") || tgt.Host == "" {
+		return xerrors.Errorf("target: \"%s\" is not an http or https URL, e.g. http://localhost:3000", c.Target)
+	}
+	for i, p := range c.AllowedPaths {
+		if !strings.HasPrefix(p, "/") {
+			return xerrors.Errorf("allowedPaths[%d]: \"%s\" must start with /", i, p)
+		}
+	}
+	return nil
+}
+
+// New produces a handler which proxies requests to the development server, including WebSocket connections
+// the development server uses for live reloading.
+func New(cfg Config) (http.Handler, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, xerrors.Errorf("dev proxy has no target")
+	}
+	tgt, _ := url.Parse(cfg.Target)
+
+	proxy := httputil.NewSingleHostReverseProxy(tgt)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		// development servers tend to reject requests for hosts other than their own
+		req.Host = tgt.Host
+		stripToken(req, cfg.Token)
+	}
+
+	return &handler{Config: cfg, proxy: proxy}, nil
+}
+
+type handler struct {
+	Config Config
+	proxy  http.Handler
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if h.Config.Token != "" {
+		if tkn := r.URL.Query().Get(TokenParam); tkn != "" {
+			h.login(w, r, tkn)
+			return
+		}
+		if !h.authenticated(r) {
+			http.Error(w, "the dev proxy requires a token - append ?"+TokenParam+"=<token> to the URL", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	h.proxy.ServeHTTP(w, r)
+}
+
+// allowed returns true if the path lies within one of the allowed paths
+func (h *handler) allowed(p string) bool {
+	if len(h.Config.AllowedPaths) == 0 {
+		return true
+	}
+
+	p = path.Clean("/" + p)
+	for _, a := range h.Config.AllowedPaths {
+		a = strings.TrimSuffix(a, "/")
+		if a == "" || p == a || strings.HasPrefix(p, a+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticated returns true if the request carries the token as bearer token or cookie
+func (h *handler) authenticated(r *http.Request) bool {
+	if tkn := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); tkn != "" && h.validToken(tkn) {
+		return true
+	}
+	if c, err := r.Cookie(tokenCookie); err == nil && h.validToken(c.Value) {
+		return true
+	}
+	return false
+}
+
+// login stores a token presented as query parameter in a cookie and redirects to the URL without the token
+func (h *handler) login(w http.ResponseWriter, r *http.Request, tkn string) {
+	if !h.validToken(tkn) {
+		http.Error(w, "invalid dev proxy token", http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     tokenCookie,
+		Value:    tkn,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	u := *r.URL
+	q := u.Query()
+	q.Del(TokenParam)
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.RequestURI(), http.StatusFound)
+}
+
+func (h *handler) validToken(tkn string) bool {
+	return subtle.ConstantTimeCompare([]byte(tkn), []byte(h.Config.Token)) == 1
+}
+
+// stripToken removes the dev proxy token from a request, s.t. it does not reach the development server
+func stripToken(req *http.Request, token string) {
+	if token != "" && req.Header.Get("Authorization") == "Bearer "+token {
+		req.Header.Del("Authorization")
+	}
+
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, c := range cookies {
+		if c.Name == tokenCookie {
+			continue
+		}
+		req.AddCookie(c)
+	}
+}
@@ -0,0 +1,225 @@
+package devproxy_test
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/devproxy"
+)
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cookies []string
+		for _, c := range r.Cookies() {
+			cookies = append(cookies, c.Name)
+		}
+		fmt.Fprintf(w, "%s %s auth=%s cookies=%s", r.Host, r.URL.Path, r.Header.Get("Authorization"), strings.Join(cookies, ","))
+	}))
+	defer backend.Close()
+	backendHost := strings.TrimPrefix(backend.URL, "http://")
+
+	tests := []struct {
+		Name   string
+		Config devproxy.Config
+		Path   string
+		Header map[string]string
+		Status int
+		Body   string
+	}{
+		{
+			Name:   "no token",
+			Path:   "/static/app.js",
+			Status: http.StatusOK,
+			Body:   backendHost + " /static/app.js auth= cookies=",
+		},
+		{
+			Name:   "missing token",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/",
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Name:   "bearer token",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/",
+			Header: map[string]string{"Authorization": "Bearer secret"},
+			Status: http.StatusOK,
+			Body:   backendHost + " / auth= cookies=",
+		},
+		{
+			Name:   "wrong bearer token",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/",
+			Header: map[string]string{"Authorization": "Bearer guess"},
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Name:   "cookie",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/",
+			Header: map[string]string{"Cookie": "werft-devproxy=secret; other=1"},
+			Status: http.StatusOK,
+			Body:   backendHost + " / auth= cookies=other",
+		},
+		{
+			Name:   "query token",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/job/foo?devproxy-token=secret&bar=1",
+			Status: http.StatusFound,
+		},
+		{
+			Name:   "wrong query token",
+			Config: devproxy.Config{Token: "secret"},
+			Path:   "/?devproxy-token=guess",
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Name:   "allowed path",
+			Config: devproxy.Config{AllowedPaths: []string{"/static"}},
+			Path:   "/static/js/app.js",
+			Status: http.StatusOK,
+			Body:   backendHost + " /static/js/app.js auth= cookies=",
+		},
+		{
+			Name:   "forbidden path",
+			Config: devproxy.Config{AllowedPaths: []string{"/static"}},
+			Path:   "/staticfoo",
+			Status: http.StatusNotFound,
+		},
+		{
+			Name:   "path escaping allowed path",
+			Config: devproxy.Config{AllowedPaths: []string{"/static"}},
+			Path:   "/static/../admin",
+			Status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := test.Config
+			cfg.Target = backend.URL
+			proxy, err := devproxy.New(cfg)
+			if err != nil {
+				t.Fatalf("cannot create proxy: %v", err)
+			}
+
+			req := httptest.NewRequest("GET", test.Path, nil)
+			for k, v := range test.Header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status %d, expected %d", rec.Code, test.Status)
+			}
+			if test.Body != "" && rec.Body.String() != test.Body {
+				t.Errorf("unexpected body \"%s\", expected \"%s\"", rec.Body.String(), test.Body)
+			}
+		})
+	}
+}
+
+func TestProxyLogin(t *testing.T) {
+	proxy, err := devproxy.New(devproxy.Config{Target: "http://localhost:3000", Token: "secret"})
+	if err != nil {
+		t.Fatalf("cannot create proxy: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/job/foo?devproxy-token=secret&bar=1", nil)
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+
+	if loc := rec.Header().Get("Location"); loc != "/job/foo?bar=1" {
+		t.Errorf("unexpected redirect to %s", loc)
+	}
+	resp := http.Response{Header: rec.Header()}
+	cookies := resp.Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "secret" || !cookies[0].HttpOnly {
+		t.Errorf("unexpected cookies: %v", cookies)
+	}
+}
+
+func TestProxyWebSocket(t *testing.T) {
+	// the backend answers the upgrade and echoes a line, which is all there is to a WebSocket as far as the proxy cares
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "expected upgrade", http.StatusBadRequest)
+			return
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		brw.Flush()
+		line, _ := brw.ReadString('\n')
+		fmt.Fprint(brw, "echo "+line)
+		brw.Flush()
+	}))
+	defer backend.Close()
+
+	proxy, err := devproxy.New(devproxy.Config{Target: backend.URL, Token: "secret"})
+	if err != nil {
+		t.Fatalf("cannot create proxy: %v", err)
+	}
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatalf("cannot connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET /sockjs-node HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nCookie: werft-devproxy=secret\r\n\r\n", u.Host)
+
+	rd := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(rd, nil)
+	if err != nil {
+		t.Fatalf("cannot read upgrade response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	fmt.Fprint(conn, "hello\n")
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		t.Fatalf("cannot read from WebSocket: %v", err)
+	}
+	if line != "echo hello\n" {
+		t.Errorf("unexpected message \"%s\"", line)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config devproxy.Config
+		Error  bool
+	}{
+		{"disabled", devproxy.Config{}, false},
+		{"valid", devproxy.Config{Target: "http://localhost:3000", AllowedPaths: []string{"/static"}}, false},
+		{"no scheme", devproxy.Config{Target: "localhost:3000"}, true},
+		{"relative path", devproxy.Config{Target: "http://localhost:3000", AllowedPaths: []string{"static"}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"encoding/json"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/recovery"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordEnvironment stores the node a job was scheduled on and the version of the cluster on its pod, once the pod
+// is scheduled. Kubernetes does not keep that information once the node or cluster changes.
+func (js *Executor) recordEnvironment(obj *corev1.Pod) {
+	if obj.Spec.NodeName == "" {
+		return
+	}
+	if _, ok := obj.Annotations[AnnotationEnvironment]; ok {
+		js.environments.Delete(obj.Name)
+		return
+	}
+	if _, busy := js.environments.LoadOrStore(obj.Name, struct{}{}); busy {
+		return
+	}
+
+	recovery.Go("executor", func() {
+		env, err := js.nodeEnvironment(obj.Spec.NodeName)
+		if err == nil {
+			var data []byte
+			data, err = json.Marshal(env)
+			if err == nil {
+				err = js.addAnnotation(obj.Name, map[string]string{AnnotationEnvironment: string(data)})
+			}
+		}
+		if err != nil {
+			log.WithError(err).WithField("name", obj.Name).Warn("cannot record job environment")
+			// we'll try again with the next update of the pod
+			js.environments.Delete(obj.Name)
+		}
+	})
+}
+
+// nodeEnvironment describes a node and the cluster it belongs to
+func (js *Executor) nodeEnvironment(nodeName string) (*v1.JobEnvironment, error) {
+	node, err := js.Client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get node %s: %w", nodeName, err)
+	}
+	info := node.Status.NodeInfo
+	env := &v1.JobEnvironment{
+		Node:                    nodeName,
+		OsImage:                 info.OSImage,
+		KernelVersion:           info.KernelVersion,
+		Architecture:            info.Architecture,
+		ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+		KubeletVersion:          info.KubeletVersion,
+	}
+
+	version, err := js.Client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get Kubernetes version: %w", err)
+	}
+	env.KubernetesVersion = version.GitVersion
+	return env, nil
+}
+
+// getEnvironment extracts the environment of a job from its pod: the recorded node and cluster, and the images its
+// containers run. Returns nil if we know nothing about the environment yet.
+func getEnvironment(obj *corev1.Pod) (*v1.JobEnvironment, error) {
+	env := &v1.JobEnvironment{}
+	if c, ok := obj.Annotations[AnnotationEnvironment]; ok {
+		err := json.Unmarshal([]byte(c), env)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot unmarshal environment: %w", err)
+		}
+	}
+
+	for _, s := range append(obj.Status.InitContainerStatuses, obj.Status.ContainerStatuses...) {
+		if s.ImageID == "" {
+			// the container has not started yet
+			continue
+		}
+		env.Images = append(env.Images, &v1.ContainerImage{
+			Container: s.Name,
+			Image:     s.Image,
+			ImageId:   s.ImageID,
+		})
+	}
+
+	if env.Node == "" && len(env.Images) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
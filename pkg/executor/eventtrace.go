@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultEventTraceLogBackups is the number of rotated event trace logs we keep if the config does not say otherwise
+const defaultEventTraceLogBackups = 3
+
+// rotateFile renames fn to fn.1 (and fn.1 to fn.2 and so on) once it has grown to maxSize bytes.
+// Of the rotated files we keep the newest backups ones.
+func rotateFile(fn string, maxSize int64, backups int) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	stat, err := os.Stat(fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stat.Size() < maxSize {
+		return nil
+	}
+
+	if backups <= 0 {
+		backups = defaultEventTraceLogBackups
+	}
+	err = os.Remove(fmt.Sprintf("%s.%d", fn, backups))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := backups - 1; i > 0; i-- {
+		err = os.Rename(fmt.Sprintf("%s.%d", fn, i), fmt.Sprintf("%s.%d", fn, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(fn, fn+".1")
+}
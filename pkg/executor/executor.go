@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/recovery"
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/golang/protobuf/ptypes"
 	log "github.com/sirupsen/logrus"
@@ -45,6 +48,33 @@ const (
 
 	// AnnotationCanReplay stores if this job can be replayed
 	AnnotationCanReplay = "werft.sh/canReplay"
+
+	// AnnotationProgress stores the JSON encoded progress last reported by a job
+	AnnotationProgress = "werft.sh/progress"
+
+	// AnnotationLogTruncated marks a job whose log exceeded the configured size limit
+	AnnotationLogTruncated = "werft.sh/logTruncated"
+
+	// AnnotationSteps stores the JSON encoded list of steps (phases) a job went through
+	AnnotationSteps = "werft.sh/steps"
+
+	// AnnotationFailedSlices stores the JSON encoded list of log slices which failed
+	AnnotationFailedSlices = "werft.sh/failedSlices"
+
+	// AnnotationDownstream stores the JSON encoded list of jobs to start once a job succeeded
+	AnnotationDownstream = "werft.sh/downstream"
+
+	// AnnotationRetryPolicy stores the JSON encoded policy which decides if a failed job is retried
+	AnnotationRetryPolicy = "werft.sh/retryPolicy"
+
+	// AnnotationWorkspaceUsage stores the disk space in bytes the workspace of a job used when it was last measured
+	AnnotationWorkspaceUsage = "werft.sh/workspaceUsage"
+
+	// AnnotationPinned marks a job which is exempt from pruning
+	AnnotationPinned = "werft.sh/pinned"
+
+	// AnnotationEnvironment stores the JSON encoded node and cluster a job was scheduled on
+	AnnotationEnvironment = "werft.sh/environment"
 )
 
 // Config configures the executor
@@ -53,8 +83,31 @@ type Config struct {
 	EventTraceLog   string    `yaml:"eventTraceLog,omitempty"`
 	JobPrepTimeout  *Duration `yaml:"preperationTimeout"`
 	JobTotalTimeout *Duration `yaml:"totalTimeout"`
+
+	// EventTraceLogMaxSize is the size in bytes at which the event trace log is rotated. Zero disables rotation.
+	EventTraceLogMaxSize int64 `yaml:"eventTraceLogMaxSize,omitempty"`
+	// EventTraceLogMaxBackups is the number of rotated event trace logs we keep. Defaults to 3.
+	EventTraceLogMaxBackups int `yaml:"eventTraceLogMaxBackups,omitempty"`
+
+	// EventRecordingPath is a directory in which all pod watch events of each job are recorded, one file per job.
+	// Recordings can be replayed using "werft debug replay-events". Recording is disabled if this is empty.
+	EventRecordingPath string `yaml:"eventRecordingPath,omitempty"`
+	// EventRecordingMaxAge is how long recordings are kept after the last event of their job. Defaults to 7 days.
+	EventRecordingMaxAge *Duration `yaml:"eventRecordingMaxAge,omitempty"`
+
+	// Backend is where jobs run: kubernetes (the default) or fake, which simulates jobs without a cluster
+	Backend string `yaml:"backend,omitempty"`
+	// Fake configures the fake backend
+	Fake FakeConfig `yaml:"fake,omitempty"`
 }
 
+const (
+	// BackendKubernetes runs jobs as pods in a Kubernetes cluster
+	BackendKubernetes = "kubernetes"
+	// BackendFake simulates jobs without a cluster, see NewFakeExecutor
+	BackendFake = "fake"
+)
+
 // Duration is a JSON un-/marshallable type
 type Duration struct {
 	time.Duration
@@ -82,14 +135,9 @@ func NewExecutor(config Config, kubeConfig *rest.Config) (*Executor, error) {
 		return nil, err
 	}
 
-	if config.JobPrepTimeout == nil {
-		return nil, xerrors.Errorf("job preperation timeout is required")
-	}
-	if config.JobTotalTimeout == nil {
-		return nil, xerrors.Errorf("total job timeout is required")
-	}
-	if config.JobTotalTimeout.Duration < config.JobPrepTimeout.Duration {
-		return nil, xerrors.Errorf("total job timeout must be greater than the preparation timeout")
+	err = config.Validate()
+	if err != nil {
+		return nil, err
 	}
 
 	return &Executor{
@@ -101,15 +149,83 @@ func NewExecutor(config Config, kubeConfig *rest.Config) (*Executor, error) {
 	}, nil
 }
 
+// Validate ensures the config has sensible timeouts and a known backend, naming the offending field
+func (config Config) Validate() error {
+	if config.JobPrepTimeout == nil {
+		return xerrors.Errorf("preperationTimeout: job preperation timeout is required")
+	}
+	if config.JobTotalTimeout == nil {
+		return xerrors.Errorf("totalTimeout: total job timeout is required")
+	}
+	if config.JobTotalTimeout.Duration < config.JobPrepTimeout.Duration {
+		return xerrors.Errorf("totalTimeout: total job timeout must be greater than the preparation timeout")
+	}
+	if config.Backend != "" && config.Backend != BackendKubernetes && config.Backend != BackendFake {
+		return xerrors.Errorf("backend: unknown executor backend \"%s\" - must be %s or %s", config.Backend, BackendKubernetes, BackendFake)
+	}
+	if config.EventTraceLogMaxSize < 0 {
+		return xerrors.Errorf("eventTraceLogMaxSize: must not be negative")
+	}
+	if config.EventTraceLogMaxBackups < 0 {
+		return xerrors.Errorf("eventTraceLogMaxBackups: must not be negative")
+	}
+	if config.EventRecordingMaxAge != nil && config.EventRecordingMaxAge.Duration <= 0 {
+		return xerrors.Errorf("eventRecordingMaxAge: must be positive")
+	}
+	if config.Fake.Script != "" {
+		_, err := parseFakeScript(config.Fake.Script)
+		if err != nil {
+			return xerrors.Errorf("fake.script: %w", err)
+		}
+	}
+	return nil
+}
+
 // Executor starts and watches jobs running in Kubernetes
 type Executor struct {
-	// OnUpdate is called when the status of a job changes.
-	// Beware: this function can be called several times with the same status.
+	// OnUpdate is called when the status of a job changes, once per change. Jobs never move back to an earlier phase
+	// and their outcome does not change once they're done.
 	OnUpdate func(pod *corev1.Pod, status *werftv1.JobStatus)
 
 	Client     kubernetes.Interface
 	Config     Config
 	KubeConfig *rest.Config
+
+	// streamLogs streams the log of a container. If nil, the logs are read from Kubernetes.
+	streamLogs logStreamer
+
+	// timeoutsMu guards the timeouts in Config, which can change while the executor runs
+	timeoutsMu sync.RWMutex
+
+	// environments holds the pods whose environment is being recorded
+	environments sync.Map
+
+	// phases holds the status last reported for each job
+	phases jobPhases
+}
+
+// SetTimeouts changes the preparation and total timeout of jobs. Jobs which run already are subject to the new timeouts.
+func (js *Executor) SetTimeouts(prep, total *Duration) error {
+	js.timeoutsMu.Lock()
+	defer js.timeoutsMu.Unlock()
+
+	cfg := js.Config
+	cfg.JobPrepTimeout, cfg.JobTotalTimeout = prep, total
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+	// we must not replace the whole config, the rest of it is read without lock
+	js.Config.JobPrepTimeout, js.Config.JobTotalTimeout = prep, total
+	return nil
+}
+
+// timeouts returns the preparation and total timeout of jobs
+func (js *Executor) timeouts() (prep, total time.Duration) {
+	js.timeoutsMu.RLock()
+	defer js.timeoutsMu.RUnlock()
+
+	return js.Config.JobPrepTimeout.Duration, js.Config.JobTotalTimeout.Duration
 }
 
 // Run starts the executor and returns immediately
@@ -138,6 +254,24 @@ func WithBackoff(limit int) StartOpt {
 	}
 }
 
+// WithDownstream stores the JSON encoded jobs to start once the job succeeded
+func WithDownstream(downstream string) StartOpt {
+	return func(opts *startOptions) {
+		opts.Modifier = append(opts.Modifier, func(j *corev1.Pod) {
+			j.Annotations[AnnotationDownstream] = downstream
+		})
+	}
+}
+
+// WithRetryPolicy stores the JSON encoded policy which decides if the job is retried once it failed
+func WithRetryPolicy(policy string) StartOpt {
+	return func(opts *startOptions) {
+		opts.Modifier = append(opts.Modifier, func(j *corev1.Pod) {
+			j.Annotations[AnnotationRetryPolicy] = policy
+		})
+	}
+}
+
 // WithAnnotation sets a single annotation on a job
 func WithAnnotation(key, value string) StartOpt {
 	return func(opts *startOptions) {
@@ -285,14 +419,23 @@ func (js *Executor) monitorJobs() {
 }
 
 func (js *Executor) handleJobEvent(evttpe watch.EventType, obj *corev1.Pod) {
+	// a pod we cannot make sense of must not stop us from watching all others
+	defer recovery.Recover("executor")
+
 	status, err := getStatus(obj)
 	js.writeEventTraceLog(status, obj)
+	js.recordEvent(evttpe, obj, status, err)
 	if err != nil {
 		log.WithError(err).WithField("name", obj.Name).Error("cannot compute status")
 		return
 	}
 
-	js.OnUpdate(obj, status)
+	js.recordEnvironment(obj)
+	status, changed := js.phases.Update(evttpe, status)
+	if changed {
+		js.OnUpdate(obj, status)
+	}
+	// we act on unchanged statuses too, s.t. a failed attempt to delete a pod is retried on its next event
 	err = js.actOnUpdate(status, obj)
 	if err != nil {
 		log.WithError(err).WithField("name", obj.Name).Error("cannot act on status update")
@@ -301,7 +444,7 @@ func (js *Executor) handleJobEvent(evttpe watch.EventType, obj *corev1.Pod) {
 }
 
 func (js *Executor) actOnUpdate(status *werftv1.JobStatus, obj *corev1.Pod) error {
-	if status.Phase == werftv1.JobPhase_PHASE_DONE {
+	if needsDeletion(status) {
 		gracePeriod := int64(5)
 		policy := metav1.DeletePropagationForeground
 
@@ -321,6 +464,11 @@ func (js *Executor) actOnUpdate(status *werftv1.JobStatus, obj *corev1.Pod) erro
 	return nil
 }
 
+// needsDeletion returns true if the pod of a job is to be deleted, i.e. the job is done
+func needsDeletion(status *werftv1.JobStatus) bool {
+	return status.Phase == werftv1.JobPhase_PHASE_DONE
+}
+
 func (js *Executor) writeEventTraceLog(status *werftv1.JobStatus, obj *corev1.Pod) {
 	// make sure we recover from a panic in this function - not that we expect this to ever happen
 	//nolint:errcheck
@@ -334,6 +482,11 @@ func (js *Executor) writeEventTraceLog(status *werftv1.JobStatus, obj *corev1.Po
 	if js.Config.EventTraceLog == "-" {
 		out = os.Stdout
 	} else {
+		err := rotateFile(js.Config.EventTraceLog, js.Config.EventTraceLogMaxSize, js.Config.EventTraceLogMaxBackups)
+		if err != nil {
+			log.WithError(err).Warn("cannot rotate event trace log")
+		}
+
 		f, err := os.OpenFile(js.Config.EventTraceLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			return
@@ -356,11 +509,18 @@ func (js *Executor) writeEventTraceLog(status *werftv1.JobStatus, obj *corev1.Po
 
 // Logs provides the log output of a running job. If the job is unknown, nil is returned.
 func (js *Executor) Logs(name string) io.Reader {
-	return listenToLogs(js.Client, name, js.Config.Namespace)
+	stream := js.streamLogs
+	if stream == nil {
+		stream = func(pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+			return js.Client.CoreV1().Pods(js.Config.Namespace).GetLogs(pod, opts).Stream()
+		}
+	}
+	return listenToLogs(js.Client, name, js.Config.Namespace, stream)
 }
 
 func (js *Executor) doHousekeeping() {
-	tick := time.NewTicker(js.Config.JobPrepTimeout.Duration / 2)
+	prepTimeout, _ := js.timeouts()
+	tick := time.NewTicker(prepTimeout / 2)
 	for {
 		// check our state and watch for non-existent jobs/events that we missed
 		pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
@@ -371,7 +531,12 @@ func (js *Executor) doHousekeeping() {
 			continue
 		}
 
+		known := make(map[string]struct{}, len(pods.Items))
 		for _, pod := range pods.Items {
+			if name, ok := getJobName(&pod); ok {
+				known[name] = struct{}{}
+			}
+
 			status, err := getStatus(&pod)
 			if err != nil {
 				log.WithError(err).WithField("name", pod.Name).Warn("cannot perform housekeeping")
@@ -384,11 +549,9 @@ func (js *Executor) doHousekeeping() {
 				continue
 			}
 
-			var ttl time.Duration
-			if status.Phase == v1.JobPhase_PHASE_PREPARING {
-				ttl = js.Config.JobPrepTimeout.Duration
-			} else {
-				ttl = js.Config.JobTotalTimeout.Duration
+			ttl, totalTimeout := js.timeouts()
+			if status.Phase != v1.JobPhase_PHASE_PREPARING {
+				ttl = totalTimeout
 			}
 			if time.Since(created) < ttl {
 				continue
@@ -400,6 +563,9 @@ func (js *Executor) doHousekeeping() {
 				AnnotationFailed: msg,
 			})
 		}
+		// jobs whose pod is gone won't see any more events - we might have missed their deletion
+		js.phases.Retain(known)
+		js.pruneEventRecordings()
 
 		<-tick.C
 	}
@@ -424,6 +590,18 @@ func (js *Executor) getJobPod(name string) (*corev1.Pod, error) {
 	return &pods.Items[0], nil
 }
 
+// IsRunning returns true if there's still a pod executing the job
+func (js *Executor) IsRunning(name string) (bool, error) {
+	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", LabelJobName, name),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(pods.Items) > 0, nil
+}
+
 // Stop stops a job
 func (js *Executor) Stop(name, reason string) error {
 	pod, err := js.getJobPod(name)
@@ -479,6 +657,170 @@ func (js *Executor) RegisterResult(jobname string, res *v1.JobResult) error {
 	return err
 }
 
+// RegisterProgress records the progress last reported by a job
+func (js *Executor) RegisterProgress(jobname string, progress *v1.JobProgress) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+
+	pa, err := json.Marshal(progress)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal progress: %w", err)
+	}
+	return js.addAnnotation(pod.Name, map[string]string{
+		AnnotationProgress: string(pa),
+	})
+}
+
+// RegisterStep records the start of a new step (phase) of a job, which also marks the end of the previous step.
+// Steps which do not start after the last recorded one are ignored, because we read the log from the beginning
+// again after a restart.
+func (js *Executor) RegisterStep(jobname, step string, started time.Time) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+	podname := pod.Name
+
+	ts, err := ptypes.TimestampProto(started)
+	if err != nil {
+		return err
+	}
+
+	client := js.Client.CoreV1().Pods(js.Config.Namespace)
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pod, err := client.Get(podname, metav1.GetOptions{})
+		if err != nil {
+			return xerrors.Errorf("cannot find job pod %s: %w", podname, err)
+		}
+		if pod == nil {
+			return xerrors.Errorf("job pod %s does not exist", podname)
+		}
+
+		var steps []v1.JobStep
+		if c, ok := pod.Annotations[AnnotationSteps]; ok {
+			err := json.Unmarshal([]byte(c), &steps)
+			if err != nil {
+				return xerrors.Errorf("cannot unmarshal previous steps: %w", err)
+			}
+		}
+		if len(steps) > 0 {
+			last, err := ptypes.Timestamp(steps[len(steps)-1].Started)
+			if err == nil && !started.After(last) {
+				return nil
+			}
+		}
+		if len(steps) > 0 && steps[len(steps)-1].Finished == nil {
+			steps[len(steps)-1].Finished = ts
+		}
+		steps = append(steps, v1.JobStep{Name: step, Started: ts})
+		sa, err := json.Marshal(steps)
+		if err != nil {
+			return xerrors.Errorf("cannot remarshal steps: %w", err)
+		}
+		pod.Annotations[AnnotationSteps] = string(sa)
+
+		_, err = client.Update(pod)
+		return err
+	})
+	return err
+}
+
+// RegisterFailedSlice records a log slice which failed. Slices which were recorded before are ignored, because we read
+// the log from the beginning again after a restart.
+func (js *Executor) RegisterFailedSlice(jobname string, anchor *v1.LogAnchor) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+	podname := pod.Name
+
+	client := js.Client.CoreV1().Pods(js.Config.Namespace)
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pod, err := client.Get(podname, metav1.GetOptions{})
+		if err != nil {
+			return xerrors.Errorf("cannot find job pod %s: %w", podname, err)
+		}
+		if pod == nil {
+			return xerrors.Errorf("job pod %s does not exist", podname)
+		}
+
+		var slices []v1.LogAnchor
+		if c, ok := pod.Annotations[AnnotationFailedSlices]; ok {
+			err := json.Unmarshal([]byte(c), &slices)
+			if err != nil {
+				return xerrors.Errorf("cannot unmarshal previous failed slices: %w", err)
+			}
+		}
+		for _, s := range slices {
+			if s.Phase == anchor.Phase && s.Slice == anchor.Slice {
+				return nil
+			}
+		}
+		slices = append(slices, *anchor)
+		sa, err := json.Marshal(slices)
+		if err != nil {
+			return xerrors.Errorf("cannot remarshal failed slices: %w", err)
+		}
+		pod.Annotations[AnnotationFailedSlices] = string(sa)
+
+		_, err = client.Update(pod)
+		return err
+	})
+	return err
+}
+
+// AddUserAnnotations adds or updates user annotations of a running job. The annotations become part of the job's metadata.
+func (js *Executor) AddUserAnnotations(jobname string, annotations map[string]string) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+
+	userdata := make(map[string]string, len(annotations))
+	for key, val := range annotations {
+		userdata[fmt.Sprintf("%s/%s", UserDataAnnotationPrefix, key)] = val
+	}
+	return js.addAnnotation(pod.Name, userdata)
+}
+
+// RecordWorkspaceUsage stores the disk space in bytes the workspace of a job uses
+func (js *Executor) RecordWorkspaceUsage(jobname string, bytes int64) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+
+	return js.addAnnotation(pod.Name, map[string]string{
+		AnnotationWorkspaceUsage: fmt.Sprintf("%d", bytes),
+	})
+}
+
+// MarkLogTruncated records that the log of a job was truncated
+func (js *Executor) MarkLogTruncated(jobname string) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+
+	return js.addAnnotation(pod.Name, map[string]string{
+		AnnotationLogTruncated: "true",
+	})
+}
+
+// SetPinned pins or unpins a job
+func (js *Executor) SetPinned(jobname string, pinned bool) error {
+	pod, err := js.getJobPod(jobname)
+	if err != nil {
+		return err
+	}
+
+	return js.addAnnotation(pod.Name, map[string]string{
+		AnnotationPinned: strconv.FormatBool(pinned),
+	})
+}
+
 // addAnnotation adds annotations to a pod
 func (js *Executor) addAnnotation(podname string, annotations map[string]string) error {
 	client := js.Client.CoreV1().Pods(js.Config.Namespace)
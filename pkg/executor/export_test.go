@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LogLine exposes logLine to tests
+type LogLine = logLine
+
+// LogOrder exposes logOrder to tests
+type LogOrder = logOrder
+
+// ParseLogLine exposes parseLogLine to tests
+var ParseLogLine = parseLogLine
+
+// ListenToLogs exposes listenToLogs to tests
+func ListenToLogs(client kubernetes.Interface, job, namespace string, stream func(pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error)) io.Reader {
+	return listenToLogs(client, job, namespace, stream)
+}
@@ -0,0 +1,535 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// fakeStartDelay is how long the pods of the fake executor prepare before they run, unless configured otherwise
+	fakeStartDelay = 1 * time.Second
+	// fakeRunDuration is how long the containers of the fake executor run if they have no script
+	fakeRunDuration = 3 * time.Second
+	// fakeLogRetention is how long the logs of deleted pods remain readable
+	fakeLogRetention = 1 * time.Minute
+)
+
+// FakeConfig configures the fake executor backend
+type FakeConfig struct {
+	// StartDelay is how long pods prepare before they run. Defaults to 1s.
+	StartDelay *Duration `yaml:"startDelay,omitempty"`
+	// Script is run by containers which have no WERFT_FAKE_SCRIPT environment variable. If empty, containers log
+	// what they would have run and succeed.
+	Script string `yaml:"script,omitempty"`
+}
+
+// podsResource is the resource of pods in the fake Kubernetes client
+var podsResource = corev1.SchemeGroupVersion.WithResource("pods")
+
+// NewFakeExecutor creates an executor which runs jobs without Kubernetes. Its pods live in a fake Kubernetes client
+// where a fake kubelet plays the part of the cluster: rather than running their image, containers run a script
+// which produces log output, takes time and ends with an exit code (see EnvFakeScript). This is meant for developing
+// werft and testing job templates, plugins and integrations, not for running actual jobs.
+func NewFakeExecutor(config Config) (*Executor, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	client := fake.NewSimpleClientset()
+	kubelet := &fakeKubelet{
+		Client:    client,
+		Namespace: config.Namespace,
+		Config:    config.Fake,
+		logs:      make(map[string]*fakeLog),
+		stop:      make(map[string]chan struct{}),
+	}
+	client.PrependReactor("create", "pods", kubelet.createPod)
+	client.PrependReactor("update", "pods", kubelet.updatePod)
+	client.PrependReactor("delete", "pods", kubelet.deletePod)
+	client.PrependWatchReactor("pods", kubelet.watchPods)
+	err = kubelet.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot watch fake pods: %w", err)
+	}
+
+	return &Executor{
+		OnUpdate: func(pod *corev1.Pod, status *werftv1.JobStatus) {},
+
+		Config:     config,
+		Client:     client,
+		streamLogs: kubelet.Logs,
+	}, nil
+}
+
+// fakeKubelet runs the pods of a fake Kubernetes client and makes the client behave enough like a cluster for werft,
+// i.e. it detects conflicting updates, deletes pods gracefully and filters watches by label.
+type fakeKubelet struct {
+	Client    *fake.Clientset
+	Namespace string
+	Config    FakeConfig
+
+	// version is the last resource version we handed out. Reactors run one at a time, hence need no lock.
+	version int
+
+	logs map[string]*fakeLog
+	// stop is closed when a pod is deleted, s.t. its containers stop running
+	stop map[string]chan struct{}
+	mu   sync.Mutex
+}
+
+// Run starts the pods which are created in the fake client. It returns once it watches the client.
+func (k *fakeKubelet) Run() error {
+	w, err := k.Client.Tracker().Watch(podsResource, k.Namespace)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for evt := range w.ResultChan() {
+			pod, ok := evt.Object.(*corev1.Pod)
+			if !ok || evt.Type != watch.Added {
+				continue
+			}
+			go k.runPod(pod.Name)
+		}
+	}()
+	return nil
+}
+
+// runPod moves a pod through its lifecycle. Like in a cluster, the init containers run one after the other while
+// the pod is pending, then all containers run at once.
+func (k *fakeKubelet) runPod(name string) {
+	stop := k.stopChan(name)
+	startDelay := fakeStartDelay
+	if k.Config.StartDelay != nil {
+		startDelay = k.Config.StartDelay.Duration
+	}
+	if !sleepUnlessStopped(startDelay, stop) {
+		return
+	}
+
+	pod, err := k.Client.CoreV1().Pods(k.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("fake pod did not start")
+		return
+	}
+	err = k.updateStatus(name, func(pod *corev1.Pod) {
+		now := metav1.Now()
+		pod.Status.StartTime = &now
+		waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}
+		pod.Status.InitContainerStatuses = nil
+		for _, c := range pod.Spec.InitContainers {
+			pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, corev1.ContainerStatus{Name: c.Name, Image: c.Image, State: waiting})
+		}
+		pod.Status.ContainerStatuses = nil
+		for _, c := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: c.Name, Image: c.Image, State: waiting})
+		}
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("fake pod did not start")
+		return
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		code, ok := k.runContainer(name, c, true, stop)
+		if !ok {
+			return
+		}
+		if code != 0 {
+			// the main containers never start, just like in a cluster
+			k.setPhase(name, corev1.PodFailed)
+			return
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed int32
+	)
+	for _, c := range pod.Spec.Containers {
+		wg.Add(1)
+		go func(c corev1.Container) {
+			defer wg.Done()
+			code, ok := k.runContainer(name, c, false, stop)
+			if ok && code != 0 {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	select {
+	case <-stop:
+		return
+	default:
+	}
+	if atomic.LoadInt32(&failed) != 0 {
+		k.setPhase(name, corev1.PodFailed)
+	} else {
+		k.setPhase(name, corev1.PodSucceeded)
+	}
+}
+
+// runContainer runs the script of a container and returns its exit code. It returns false if the pod was deleted
+// before the container finished.
+func (k *fakeKubelet) runContainer(pod string, c corev1.Container, init bool, stop <-chan struct{}) (code int, ok bool) {
+	defer k.closeLog(pod, c.Name)
+
+	started := metav1.Now()
+	err := k.setContainerState(pod, c.Name, init, corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: started}})
+	if err != nil {
+		log.WithError(err).WithField("name", pod).Debug("fake container did not start")
+		return 0, false
+	}
+
+	logs := k.log(pod, c.Name)
+	steps, err := parseFakeScript(k.fakeScript(c, init))
+	if err != nil {
+		logs.Write(fmt.Sprintf("fake executor: invalid %s: %v", EnvFakeScript, err))
+		code = 127
+	}
+	for _, step := range steps {
+		if step.Echo != nil {
+			logs.Write(*step.Echo)
+		}
+		if !sleepUnlessStopped(step.Sleep, stop) {
+			return 0, false
+		}
+		if step.Exit != nil {
+			code = *step.Exit
+			break
+		}
+	}
+
+	reason := "Completed"
+	if code != 0 {
+		reason = "Error"
+	}
+	err = k.setContainerState(pod, c.Name, init, corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: int32(code), Reason: reason, StartedAt: started, FinishedAt: metav1.Now()},
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", pod).Debug("fake container did not finish")
+		return 0, false
+	}
+	return code, true
+}
+
+// setContainerState changes the state of a container. Once all init containers have completed, the pod runs.
+func (k *fakeKubelet) setContainerState(pod, container string, init bool, state corev1.ContainerState) error {
+	return k.updateStatus(pod, func(pod *corev1.Pod) {
+		statuses := pod.Status.ContainerStatuses
+		if init {
+			statuses = pod.Status.InitContainerStatuses
+		}
+		for i := range statuses {
+			if statuses[i].Name != container {
+				continue
+			}
+			statuses[i].State = state
+			statuses[i].Ready = !init && state.Running != nil
+		}
+
+		if !init && state.Running != nil {
+			pod.Status.Phase = corev1.PodRunning
+		}
+	})
+}
+
+// setPhase marks a pod as finished
+func (k *fakeKubelet) setPhase(name string, phase corev1.PodPhase) {
+	err := k.updateStatus(name, func(pod *corev1.Pod) {
+		pod.Status.Phase = phase
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("fake pod did not finish")
+	}
+}
+
+// stopChan returns the channel which is closed once a pod is deleted
+func (k *fakeKubelet) stopChan(pod string) <-chan struct{} {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	c, ok := k.stop[pod]
+	if !ok {
+		// the pod is gone already
+		c = make(chan struct{})
+		close(c)
+	}
+	return c
+}
+
+// sleepUnlessStopped waits for the duration and returns false if stop was closed in the meantime
+func sleepUnlessStopped(d time.Duration, stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// updateStatus modifies the status of a pod, retrying on conflicts
+func (k *fakeKubelet) updateStatus(name string, mod func(pod *corev1.Pod)) error {
+	client := k.Client.CoreV1().Pods(k.Namespace)
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pod, err := client.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mod(pod)
+		_, err = client.UpdateStatus(pod)
+		return err
+	})
+}
+
+// createPod stores new pods as pending, like the API server does
+func (k *fakeKubelet) createPod(action k8stesting.Action) (bool, runtime.Object, error) {
+	pod, ok := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+	if !ok {
+		return false, nil, nil
+	}
+
+	pod = pod.DeepCopy()
+	pod.Namespace = action.GetNamespace()
+	pod.Status.Phase = corev1.PodPending
+	k.version++
+	pod.ResourceVersion = strconv.Itoa(k.version)
+
+	// the pod must be able to stop before the kubelet sees it
+	k.mu.Lock()
+	_, exists := k.stop[pod.Name]
+	if !exists {
+		k.stop[pod.Name] = make(chan struct{})
+	}
+	k.mu.Unlock()
+	err := k.Client.Tracker().Create(podsResource, pod, action.GetNamespace())
+	if err != nil {
+		if !exists {
+			k.mu.Lock()
+			delete(k.stop, pod.Name)
+			k.mu.Unlock()
+		}
+		return true, nil, err
+	}
+	return true, pod, nil
+}
+
+// updatePod rejects updates of pods which were modified since the caller read them, like the API server does
+func (k *fakeKubelet) updatePod(action k8stesting.Action) (bool, runtime.Object, error) {
+	pod, ok := action.(k8stesting.UpdateAction).GetObject().(*corev1.Pod)
+	if !ok {
+		return false, nil, nil
+	}
+	obj, err := k.Client.Tracker().Get(podsResource, action.GetNamespace(), pod.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	if current := obj.(*corev1.Pod); current.ResourceVersion != pod.ResourceVersion {
+		return true, nil, apierrors.NewConflict(podsResource.GroupResource(), pod.Name, fmt.Errorf("the pod has been modified"))
+	}
+
+	pod = pod.DeepCopy()
+	k.version++
+	pod.ResourceVersion = strconv.Itoa(k.version)
+	err = k.Client.Tracker().Update(podsResource, pod, action.GetNamespace())
+	if err != nil {
+		return true, nil, err
+	}
+	return true, pod, nil
+}
+
+// deletePod marks a pod as being deleted before it removes it, s.t. watchers see the pod terminate like they would in a cluster
+func (k *fakeKubelet) deletePod(action k8stesting.Action) (bool, runtime.Object, error) {
+	name := action.(k8stesting.DeleteAction).GetName()
+	obj, err := k.Client.Tracker().Get(podsResource, action.GetNamespace(), name)
+	if err != nil {
+		return true, nil, err
+	}
+	pod := obj.(*corev1.Pod).DeepCopy()
+	if pod.DeletionTimestamp == nil {
+		now := metav1.Now()
+		pod.DeletionTimestamp = &now
+		k.version++
+		pod.ResourceVersion = strconv.Itoa(k.version)
+		err = k.Client.Tracker().Update(podsResource, pod, action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+	}
+
+	k.mu.Lock()
+	if stop, ok := k.stop[pod.Name]; ok {
+		close(stop)
+		delete(k.stop, pod.Name)
+	}
+	for id, l := range k.logs {
+		if strings.HasPrefix(id, pod.Name+"/") {
+			l.Close()
+		}
+	}
+	k.mu.Unlock()
+	// containers which finish quickly may be deleted before anyone read their logs, hence we keep them for a while
+	time.AfterFunc(fakeLogRetention, func() { k.dropLogs(pod.Name) })
+
+	return true, nil, k.Client.Tracker().Delete(podsResource, action.GetNamespace(), name)
+}
+
+// watchPods watches pods, honouring the label selector of the watch which the fake client ignores otherwise
+func (k *fakeKubelet) watchPods(action k8stesting.Action) (bool, watch.Interface, error) {
+	w, err := k.Client.Tracker().Watch(podsResource, action.GetNamespace())
+	if err != nil {
+		return true, nil, err
+	}
+	sel := action.(k8stesting.WatchAction).GetWatchRestrictions().Labels
+	if sel == nil || sel.Empty() {
+		return true, w, nil
+	}
+	return true, watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		pod, ok := in.Object.(*corev1.Pod)
+		return in, ok && sel.Matches(labels.Set(pod.Labels))
+	}), nil
+}
+
+// dropLogs forgets the logs of a pod
+func (k *fakeKubelet) dropLogs(pod string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for id := range k.logs {
+		if strings.HasPrefix(id, pod+"/") {
+			delete(k.logs, id)
+		}
+	}
+}
+
+// closeLog ends the log output of a container
+func (k *fakeKubelet) closeLog(pod, container string) {
+	k.log(pod, container).Close()
+}
+
+// log returns the log of a container
+func (k *fakeKubelet) log(pod, container string) *fakeLog {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	id := pod + "/" + container
+	l, ok := k.logs[id]
+	if !ok {
+		l = newFakeLog()
+		k.logs[id] = l
+	}
+	return l
+}
+
+// Logs streams the log of a container
+func (k *fakeKubelet) Logs(pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if opts.Previous {
+		// fake containers never restart
+		l := newFakeLog()
+		l.Close()
+		return l.Reader(false), nil
+	}
+	return k.log(pod, opts.Container).Reader(opts.Follow), nil
+}
+
+// fakeLog is the log output of a fake container
+type fakeLog struct {
+	data   []byte
+	closed bool
+	cond   *sync.Cond
+}
+
+func newFakeLog() *fakeLog {
+	return &fakeLog{cond: sync.NewCond(&sync.Mutex{})}
+}
+
+// Write adds a line to the log, prefixed with a timestamp like Kubernetes does
+func (l *fakeLog) Write(line string) {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	l.data = append(l.data, []byte(fmt.Sprintf("%s %s\n", time.Now().UTC().Format(time.RFC3339Nano), line))...)
+	l.cond.Broadcast()
+}
+
+// Close ends the log. Readers which follow the log reach its end.
+func (l *fakeLog) Close() {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	l.closed = true
+	l.cond.Broadcast()
+}
+
+// Reader reads the log from its start. If follow is true, the reader waits for more output until the log is closed.
+func (l *fakeLog) Reader(follow bool) io.ReadCloser {
+	return &fakeLogReader{log: l, follow: follow}
+}
+
+type fakeLogReader struct {
+	log    *fakeLog
+	pos    int
+	follow bool
+	closed bool
+}
+
+func (r *fakeLogReader) Read(p []byte) (n int, err error) {
+	l := r.log
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for r.follow && !r.closed && !l.closed && r.pos >= len(l.data) {
+		l.cond.Wait()
+	}
+	if r.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if r.pos >= len(l.data) {
+		return 0, io.EOF
+	}
+	n = copy(p, l.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+func (r *fakeLogReader) Close() error {
+	l := r.log
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	r.closed = true
+	l.cond.Broadcast()
+	return nil
+}
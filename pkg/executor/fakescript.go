@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EnvFakeScript is the environment variable which holds the script a container runs in the fake executor.
+// Containers without one run the script of the fake executor config.
+const EnvFakeScript = "WERFT_FAKE_SCRIPT"
+
+// fakeStep is a single line of a fake script
+type fakeStep struct {
+	// Echo is written to the container log
+	Echo *string
+	// Sleep pauses the container
+	Sleep time.Duration
+	// Exit ends the container with this exit code
+	Exit *int
+}
+
+// parseFakeScript parses the script of a fake container. A script has one command per line:
+//
+//	echo <text>         writes text to the log, e.g. logcutter markers such as [build|PHASE] building
+//	sleep <duration>    pauses, e.g. sleep 2s. Plain numbers are seconds.
+//	exit <code>         ends the container with the exit code. Containers which run out of script exit with 0.
+//
+// Empty lines and lines starting with # are ignored.
+func parseFakeScript(script string) ([]fakeStep, error) {
+	var (
+		res     []fakeStep
+		scanner = bufio.NewScanner(strings.NewReader(script))
+		lineNr  int
+	)
+	for scanner.Scan() {
+		lineNr++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		segs := strings.SplitN(line, " ", 2)
+		cmd, arg := segs[0], ""
+		if len(segs) > 1 {
+			arg = strings.TrimSpace(segs[1])
+		}
+		switch cmd {
+		case "echo":
+			res = append(res, fakeStep{Echo: &arg})
+		case "sleep":
+			d, err := time.ParseDuration(arg)
+			if secs, serr := strconv.ParseFloat(arg, 64); serr == nil {
+				d, err = time.Duration(secs*float64(time.Second)), nil
+			}
+			if err != nil || d < 0 {
+				return nil, xerrors.Errorf("line %d: sleep needs a duration, e.g. 2s", lineNr)
+			}
+			res = append(res, fakeStep{Sleep: d})
+		case "exit":
+			code, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, xerrors.Errorf("line %d: exit needs an exit code", lineNr)
+			}
+			res = append(res, fakeStep{Exit: &code})
+		default:
+			return nil, xerrors.Errorf("line %d: unknown command \"%s\"", lineNr, cmd)
+		}
+	}
+	return res, nil
+}
+
+// fakeScript returns the script a container runs in the fake executor
+func (k *fakeKubelet) fakeScript(c corev1.Container, init bool) string {
+	for _, e := range c.Env {
+		if e.Name == EnvFakeScript {
+			return e.Value
+		}
+	}
+	if k.Config.Script != "" {
+		return k.Config.Script
+	}
+
+	cmd := strings.Join(append(append([]string{}, c.Command...), c.Args...), " ")
+	if cmd == "" {
+		cmd = "the image's entrypoint"
+	}
+	// the script is line based, hence the command must not span lines
+	cmd = strings.Join(strings.Fields(cmd), " ")
+	script := "echo fake executor: would run " + cmd + " in " + c.Image
+	if !init {
+		script += "\nsleep " + fakeRunDuration.String()
+	}
+	return script
+}
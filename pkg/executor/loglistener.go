@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/32leaves/werft/pkg/logcutter"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,31 +17,53 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// logOrderingWindow is the time we buffer log lines for to order them by timestamp across containers
+const logOrderingWindow = 500 * time.Millisecond
+
+// logStreamer streams the log of a container of a pod
+type logStreamer func(pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+
 type logListener struct {
 	Clientset kubernetes.Interface
 	Job       string
 	Namespace string
+	Stream    logStreamer
 
 	listener map[string]io.Closer
+	tailed   map[string]struct{}
 	started  time.Time
 	closed   bool
 	mu       sync.RWMutex
 
-	out  io.Reader
-	in   io.WriteCloser
-	inmu sync.Mutex
+	lines chan logLine
+	done  chan struct{}
+
+	out io.Reader
+	in  io.WriteCloser
+}
+
+// logLine is a single line of container output
+type logLine struct {
+	Time     time.Time
+	Received time.Time
+	Content  string
 }
 
 // Listen establishes a log listener for a job
-func listenToLogs(client kubernetes.Interface, job, namespace string) io.Reader {
+func listenToLogs(client kubernetes.Interface, job, namespace string, stream logStreamer) io.Reader {
 	ll := &logListener{
 		Clientset: client,
 		Job:       job,
 		Namespace: namespace,
+		Stream:    stream,
 		started:   time.Now(),
 		listener:  make(map[string]io.Closer),
+		tailed:    make(map[string]struct{}),
+		lines:     make(chan logLine),
+		done:      make(chan struct{}),
 	}
 	ll.out, ll.in = io.Pipe()
+	go ll.forward()
 	go ll.Start()
 
 	return ll.out
@@ -58,10 +83,9 @@ func (ll *logListener) Close() error {
 	}
 
 	ll.closed = true
-	ll.inmu.Lock()
-	defer ll.inmu.Unlock()
+	close(ll.done)
 
-	return ll.in.Close()
+	return nil
 }
 
 func (ll *logListener) Start() {
@@ -87,22 +111,23 @@ func (ll *logListener) Start() {
 			return
 		}
 
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
 		switch e.Type {
 		case watch.Added, watch.Modified:
-			var statuses []corev1.ContainerStatus
-			statuses = append(statuses, pod.Status.InitContainerStatuses...)
-			statuses = append(statuses, pod.Status.ContainerStatuses...)
-
 			for _, c := range statuses {
-				if c.State.Running != nil {
-					go ll.tail(pod.Name, c.Name)
+				// containers which ran to completion before we saw them running (e.g. short-lived init containers)
+				// still have their logs around, hence we tail them as well.
+				if c.State.Running != nil || c.State.Terminated != nil {
+					go ll.tail(pod.Name, c.Name, containerSlice(pod, c.Name), c.State.Running != nil, false)
+				} else if c.LastTerminationState.Terminated != nil {
+					// containers which failed and wait to be restarted, e.g. a failed checkout, only have the logs of their previous run
+					go ll.tail(pod.Name, c.Name, containerSlice(pod, c.Name), false, true)
 				}
 			}
 		case watch.Deleted:
-			var statuses []corev1.ContainerStatus
-			statuses = append(statuses, pod.Status.InitContainerStatuses...)
-			statuses = append(statuses, pod.Status.ContainerStatuses...)
-
 			for _, c := range statuses {
 				if c.State.Terminated != nil {
 					go ll.stopTailing(pod.Name, c.Name)
@@ -112,43 +137,137 @@ func (ll *logListener) Start() {
 	}
 }
 
-func (ll *logListener) tail(pod, container string) {
+// containerSlice returns the log slice unmarked output of a container is placed in.
+// The output of the first (main) container is forwarded as is, the output of all others (init containers,
+// sidecars and steps) is placed in a slice named after the container.
+func containerSlice(pod *corev1.Pod, container string) string {
+	if len(pod.Spec.Containers) > 0 && pod.Spec.Containers[0].Name == container && !isStepContainer(pod, container) {
+		return ""
+	}
+	return container
+}
+
+func (ll *logListener) tail(pod, container, slice string, follow, previous bool) {
 	var once sync.Once
 
 	ll.mu.Lock()
 	defer once.Do(ll.mu.Unlock)
 
 	id := fmt.Sprintf("%s/%s", pod, container)
-	_, ok := ll.listener[id]
-	if ok {
-		// we're already listening
+	if _, ok := ll.tailed[id]; ok {
+		// we're already listening or have listened
+		return
+	}
+	if ll.closed {
 		return
 	}
 
 	log.WithField("id", id).Debug("tailing container")
 
 	// we have to start listenting
-	req := ll.Clientset.CoreV1().Pods(ll.Namespace).GetLogs(pod, &corev1.PodLogOptions{
-		Container: container,
-		Follow:    true,
-		Previous:  false,
+	logs, err := ll.Stream(pod, &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     follow,
+		Previous:   previous,
+		Timestamps: true,
 	})
-	logs, err := req.Stream()
 	if err != nil {
 		log.WithError(err).Debug("cannot connect to logs")
 		return
 	}
 	ll.listener[id] = logs
+	ll.tailed[id] = struct{}{}
 	once.Do(ll.mu.Unlock)
 
 	// forward the logs line by line to ensure we don't mix the output of different conainer
 	scanner := bufio.NewScanner(logs)
 	for scanner.Scan() {
-		line := scanner.Text()
-		ll.inmu.Lock()
-		ll.in.Write([]byte(line + "\n"))
-		ll.inmu.Unlock()
+		line := parseLogLine(scanner.Text())
+		if slice != "" && !logcutter.HasMarker(line.Content) {
+			line.Content = fmt.Sprintf("[%s] %s", slice, line.Content)
+		}
+
+		select {
+		case ll.lines <- line:
+		case <-ll.done:
+			return
+		}
+	}
+}
+
+// parseLogLine parses a line produced by Kubernetes with timestamps enabled, e.g. 2020-01-02T15:04:05.123456789Z hello world
+func parseLogLine(raw string) logLine {
+	res := logLine{Received: time.Now(), Content: raw}
+
+	segs := strings.SplitN(raw, " ", 2)
+	ts, err := time.Parse(time.RFC3339Nano, segs[0])
+	if err != nil {
+		res.Time = res.Received
+		return res
+	}
+	res.Time = ts
+	if len(segs) == 2 {
+		res.Content = segs[1]
+	} else {
+		res.Content = ""
+	}
+	return res
+}
+
+// forward writes the lines produced by all containers to the output of this listener. To interleave the output of
+// several containers in the order it was produced, lines are buffered for the logOrderingWindow and sorted by time.
+func (ll *logListener) forward() {
+	var (
+		order logOrder
+		write = func(lines []logLine) {
+			for _, l := range lines {
+				ll.in.Write([]byte(l.Content + "\n"))
+			}
+		}
+		tick = time.NewTicker(logOrderingWindow / 2)
+	)
+	defer tick.Stop()
+
+	for {
+		select {
+		case l := <-ll.lines:
+			order.Add(l)
+		case <-tick.C:
+			write(order.Flush(time.Now().Add(-logOrderingWindow)))
+		case <-ll.done:
+			write(order.Flush(time.Now()))
+			ll.in.Close()
+			return
+		}
+	}
+}
+
+// logOrder buffers log lines to order them by the time they were produced
+type logOrder struct {
+	buf []logLine
+}
+
+// Add buffers a line
+func (o *logOrder) Add(l logLine) {
+	o.buf = append(o.buf, l)
+}
+
+// Flush returns the buffered lines received until the given time, ordered by the time they were produced. Lines of
+// the same time keep the order they were received in. Lines which are received after a flush are never placed before
+// the lines it returned, even if they were produced earlier: ordering holds within the logOrderingWindow only.
+func (o *logOrder) Flush(until time.Time) []logLine {
+	sort.SliceStable(o.buf, func(i, j int) bool { return o.buf[i].Time.Before(o.buf[j].Time) })
+
+	var res, rest []logLine
+	for _, l := range o.buf {
+		if l.Received.After(until) {
+			rest = append(rest, l)
+			continue
+		}
+		res = append(res, l)
 	}
+	o.buf = rest
+	return res
 }
 
 func (ll *logListener) stopTailing(pod, container string) {
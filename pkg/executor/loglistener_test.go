@@ -0,0 +1,227 @@
+package executor_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseLogLine(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 15, 4, 5, 123456789, time.UTC)
+	tests := []struct {
+		Line    string
+		Time    time.Time
+		Content string
+	}{
+		{"2020-01-02T15:04:05.123456789Z hello world", ts, "hello world"},
+		{"2020-01-02T15:04:05.123456789Z ", ts, ""},
+		{"2020-01-02T15:04:05.123456789Z", ts, ""},
+		{"2020-01-02T15:04:05.123456789Z 2020-01-03T00:00:00Z own timestamp", ts, "2020-01-03T00:00:00Z own timestamp"},
+		{"hello world", time.Time{}, "hello world"},
+		{"", time.Time{}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Line, func(t *testing.T) {
+			act := executor.ParseLogLine(test.Line)
+			if act.Content != test.Content {
+				t.Errorf("expected content %q, actual %q", test.Content, act.Content)
+			}
+			// lines without timestamp are placed at the time we received them
+			exp := test.Time
+			if exp.IsZero() {
+				exp = act.Received
+			}
+			if !act.Time.Equal(exp) {
+				t.Errorf("expected time %v, actual %v", exp, act.Time)
+			}
+		})
+	}
+}
+
+func TestLogOrder(t *testing.T) {
+	t0 := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	line := func(content string, produced, received int) executor.LogLine {
+		return executor.LogLine{
+			Content:  content,
+			Time:     t0.Add(time.Duration(produced) * time.Millisecond),
+			Received: t0.Add(time.Duration(received) * time.Millisecond),
+		}
+	}
+	type flush struct {
+		Until int
+		Lines []string
+	}
+	tests := []struct {
+		Name    string
+		Lines   []executor.LogLine
+		Flushes []flush
+	}{
+		{
+			Name: "two containers with overlapping timestamps",
+			Lines: []executor.LogLine{
+				line("main 1", 0, 100),
+				line("main 2", 20, 100),
+				line("main 3", 40, 100),
+				line("sidecar 1", 10, 150),
+				line("sidecar 2", 30, 150),
+			},
+			Flushes: []flush{
+				{200, []string{"main 1", "sidecar 1", "main 2", "sidecar 2", "main 3"}},
+			},
+		},
+		{
+			Name: "same time keeps the order received",
+			Lines: []executor.LogLine{
+				line("main", 10, 100),
+				line("sidecar", 10, 110),
+				line("step", 10, 120),
+			},
+			Flushes: []flush{
+				{200, []string{"main", "sidecar", "step"}},
+			},
+		},
+		{
+			Name: "lines received after the flush wait for the next one",
+			Lines: []executor.LogLine{
+				line("main 1", 0, 100),
+				line("main 2", 20, 300),
+				line("sidecar 1", 10, 300),
+			},
+			Flushes: []flush{
+				{200, []string{"main 1"}},
+				{400, []string{"sidecar 1", "main 2"}},
+			},
+		},
+		{
+			// a container whose output arrives late, e.g. because we only started tailing it, cannot be interleaved
+			// with the output we have written already
+			Name: "no ordering across flushes",
+			Lines: []executor.LogLine{
+				line("main 1", 0, 100),
+				line("main 2", 20, 100),
+				line("late sidecar", 10, 300),
+			},
+			Flushes: []flush{
+				{200, []string{"main 1", "main 2"}},
+				{400, []string{"late sidecar"}},
+			},
+		},
+		{
+			Name: "empty flush",
+			Lines: []executor.LogLine{
+				line("main 1", 0, 300),
+			},
+			Flushes: []flush{
+				{200, nil},
+				{400, []string{"main 1"}},
+				{600, nil},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var order executor.LogOrder
+			for _, l := range test.Lines {
+				order.Add(l)
+			}
+			for i, f := range test.Flushes {
+				var act []string
+				for _, l := range order.Flush(t0.Add(time.Duration(f.Until) * time.Millisecond)) {
+					act = append(act, l.Content)
+				}
+				if !reflect.DeepEqual(act, f.Lines) {
+					t.Errorf("flush %d: expected %q, actual %q", i, f.Lines, act)
+				}
+			}
+		})
+	}
+}
+
+func TestListenToLogsInterleavesContainers(t *testing.T) {
+	t0 := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	at := func(ms int, content string) string {
+		return fmt.Sprintf("%s %s\n", t0.Add(time.Duration(ms)*time.Millisecond).Format(time.RFC3339Nano), content)
+	}
+	containerLogs := map[string]string{
+		"main":    at(0, "main 0") + at(2, "main 2") + at(4, "main 4"),
+		"sidecar": at(1, "sidecar 1") + at(3, "sidecar 3"),
+	}
+	stream := func(pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(containerLogs[opts.Container])), nil
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Labels:    map[string]string{executor.LabelJobName: "foo"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset()
+	out := executor.ListenToLogs(client, "foo", "default", stream)
+
+	// the listener only learns about pods through its watch, hence we keep telling it about the pod until it listens
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		_, err := client.CoreV1().Pods("default").Create(pod)
+		if err != nil {
+			t.Errorf("cannot create pod: %v", err)
+			return
+		}
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			p := pod.DeepCopy()
+			p.Annotations = map[string]string{"update": fmt.Sprint(i)}
+			_, _ = client.CoreV1().Pods("default").Update(p)
+		}
+	}()
+
+	expected := []string{"main 0", "[sidecar] sidecar 1", "main 2", "[sidecar] sidecar 3", "main 4"}
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	var act []string
+	timeout := time.After(10 * time.Second)
+	for len(act) < len(expected) {
+		select {
+		case l := <-lines:
+			act = append(act, l)
+		case <-timeout:
+			t.Fatalf("timed out waiting for the log - got %q so far", act)
+		}
+	}
+	if !reflect.DeepEqual(act, expected) {
+		t.Errorf("expected %q, actual %q", expected, act)
+	}
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// phaseTransitions lists the phases a job can move to from each phase. A job which has not been scheduled yet is in
+// the unknown phase, i.e. queued. Jobs never move back to an earlier phase.
+var phaseTransitions = map[v1.JobPhase][]v1.JobPhase{
+	v1.JobPhase_PHASE_UNKNOWN:   {v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING, v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP},
+	v1.JobPhase_PHASE_PREPARING: {v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING, v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP},
+	v1.JobPhase_PHASE_STARTING:  {v1.JobPhase_PHASE_RUNNING, v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP},
+	v1.JobPhase_PHASE_RUNNING:   {v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP},
+	v1.JobPhase_PHASE_DONE:      {v1.JobPhase_PHASE_CLEANUP},
+	v1.JobPhase_PHASE_CLEANUP:   {},
+}
+
+// canTransition returns true if a job may move from one phase to another. Staying in a phase is always possible.
+func canTransition(from, to v1.JobPhase) bool {
+	if from == to {
+		return true
+	}
+	for _, p := range phaseTransitions[from] {
+		if p == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinished returns true if the outcome of a job in this phase is known
+func isFinished(phase v1.JobPhase) bool {
+	return phase == v1.JobPhase_PHASE_DONE || phase == v1.JobPhase_PHASE_CLEANUP
+}
+
+// transition computes the status of a job from the status derived from its pod and the status reported last (nil if
+// there is none). It upholds the invariants of the job lifecycle the pod alone cannot guarantee:
+//   - jobs never move back to an earlier phase, e.g. when a pod is pending again because its sandbox was recreated
+//   - once a job is done its outcome is final, e.g. containers killed while the pod is deleted do not fail the job
+//
+// changed is true if the status differs from the one reported last, i.e. needs reporting. Neither status is modified.
+func transition(prev, derived *v1.JobStatus) (status *v1.JobStatus, changed bool) {
+	if prev == nil {
+		return derived, true
+	}
+
+	status = proto.Clone(derived).(*v1.JobStatus)
+	if !canTransition(prev.Phase, status.Phase) {
+		status.Phase = prev.Phase
+	}
+	if isFinished(prev.Phase) {
+		keepOutcome(prev, status)
+	}
+	return status, !proto.Equal(prev, status)
+}
+
+// keepOutcome copies the outcome of a finished job, i.e. its success, failure and time of finishing
+func keepOutcome(from, to *v1.JobStatus) {
+	to.Details = from.Details
+	if from.Conditions != nil {
+		if to.Conditions == nil {
+			to.Conditions = &v1.JobConditions{}
+		}
+		to.Conditions.Success = from.Conditions.Success
+		to.Conditions.FailureClass = from.Conditions.FailureClass
+		to.Conditions.FailureCount = from.Conditions.FailureCount
+	}
+	if from.Metadata != nil && to.Metadata != nil {
+		to.Metadata.Finished = from.Metadata.Finished
+	}
+	if from.Timestamps != nil && to.Timestamps != nil {
+		to.Timestamps.Finished = from.Timestamps.Finished
+	}
+}
+
+// jobPhases remembers the status last reported for each job, s.t. every change is reported exactly once.
+// The zero value is ready to use.
+type jobPhases struct {
+	mu     sync.Mutex
+	status map[string]*v1.JobStatus
+}
+
+// Update feeds the status derived from a pod event into the state machine of its job. It returns the status to
+// report and if it changed since the last report.
+func (p *jobPhases) Update(evttpe watch.EventType, derived *v1.JobStatus) (status *v1.JobStatus, changed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status, changed = transition(p.status[derived.Name], derived)
+	if evttpe == watch.Deleted {
+		// there won't be any more events for this job
+		delete(p.status, derived.Name)
+		return status, changed
+	}
+	if p.status == nil {
+		p.status = make(map[string]*v1.JobStatus)
+	}
+	// whoever we report the status to might modify it
+	p.status[derived.Name] = proto.Clone(status).(*v1.JobStatus)
+	return status, changed
+}
+
+// Retain forgets all jobs but the ones named, e.g. if we missed the deletion of their pod
+func (p *jobPhases) Retain(names map[string]struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for name := range p.status {
+		if _, ok := names[name]; !ok {
+			delete(p.status, name)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package executor_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/golang/protobuf/jsonpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+type podMod func(*corev1.Pod)
+
+func testPod(phase corev1.PodPhase, mods ...podMod) *corev1.Pod {
+	md, _ := (&jsonpb.Marshaler{}).MarshalToString(&v1.JobMetadata{Owner: "foo"})
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "werft-job-1",
+			Labels:      map[string]string{executor.LabelJobName: "job-1"},
+			Annotations: map[string]string{executor.AnnotationMetadata: md},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	for _, m := range mods {
+		m(pod)
+	}
+	return pod
+}
+
+func container(state corev1.ContainerState) podMod {
+	return func(pod *corev1.Pod) {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "build", State: state}}
+	}
+}
+
+func running() podMod {
+	return container(corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Unix(100, 0))}})
+}
+
+func exited(code int32) podMod {
+	return container(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode:   code,
+		StartedAt:  metav1.NewTime(time.Unix(100, 0)),
+		FinishedAt: metav1.NewTime(time.Unix(200+int64(code), 0)),
+	}})
+}
+
+func deleting() podMod {
+	return func(pod *corev1.Pod) {
+		ts := metav1.NewTime(time.Unix(300, 0))
+		pod.DeletionTimestamp = &ts
+	}
+}
+
+func annotated(key, value string) podMod {
+	return func(pod *corev1.Pod) {
+		pod.Annotations[key] = value
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	type expectation struct {
+		Phase      v1.JobPhase
+		Success    bool
+		Changed    bool
+		DeletesPod bool
+	}
+	type event struct {
+		Type watch.EventType
+		Pod  *corev1.Pod
+	}
+	tests := []struct {
+		Name         string
+		Events       []event
+		Expectations []expectation
+	}{
+		{
+			Name: "lifecycle",
+			Events: []event{
+				{watch.Added, testPod("")},
+				{watch.Modified, testPod(corev1.PodPending)},
+				{watch.Modified, testPod(corev1.PodRunning, running())},
+				{watch.Modified, testPod(corev1.PodSucceeded, exited(0))},
+				{watch.Modified, testPod(corev1.PodSucceeded, exited(0), deleting())},
+				{watch.Deleted, testPod(corev1.PodSucceeded, exited(0), deleting())},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_UNKNOWN, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_PREPARING, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_DONE, Success: true, Changed: true, DeletesPod: true},
+				{Phase: v1.JobPhase_PHASE_CLEANUP, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_CLEANUP, Success: true},
+			},
+		},
+		{
+			Name: "same status reported once",
+			Events: []event{
+				{watch.Added, testPod(corev1.PodRunning, running())},
+				{watch.Modified, testPod(corev1.PodRunning, running())},
+				{watch.Modified, testPod(corev1.PodRunning, running(), annotated(executor.AnnotationWorkspaceUsage, "42"))},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true},
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+			},
+		},
+		{
+			Name: "no phase regression",
+			Events: []event{
+				{watch.Modified, testPod(corev1.PodRunning, running())},
+				{watch.Modified, testPod(corev1.PodPending)},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+			},
+		},
+		{
+			Name: "outcome is final",
+			Events: []event{
+				{watch.Modified, testPod(corev1.PodSucceeded, exited(0))},
+				{watch.Modified, testPod(corev1.PodFailed, exited(137), deleting())},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_DONE, Success: true, Changed: true, DeletesPod: true},
+				{Phase: v1.JobPhase_PHASE_CLEANUP, Success: true, Changed: true},
+			},
+		},
+		{
+			Name: "failed",
+			Events: []event{
+				{watch.Modified, testPod(corev1.PodRunning, running())},
+				{watch.Modified, testPod(corev1.PodRunning, running(), annotated(executor.AnnotationFailed, "job timed out during running"))},
+				{watch.Modified, testPod(corev1.PodRunning, running(), annotated(executor.AnnotationFailed, "job timed out during running"))},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_RUNNING, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_DONE, Success: false, Changed: true, DeletesPod: true},
+				{Phase: v1.JobPhase_PHASE_DONE, Success: false, DeletesPod: true},
+			},
+		},
+		{
+			Name: "job restarted after deletion",
+			Events: []event{
+				{watch.Modified, testPod(corev1.PodSucceeded, exited(0), deleting())},
+				{watch.Deleted, testPod(corev1.PodSucceeded, exited(0), deleting())},
+				{watch.Added, testPod(corev1.PodPending)},
+			},
+			Expectations: []expectation{
+				{Phase: v1.JobPhase_PHASE_CLEANUP, Success: true, Changed: true},
+				{Phase: v1.JobPhase_PHASE_CLEANUP, Success: true},
+				{Phase: v1.JobPhase_PHASE_PREPARING, Success: true, Changed: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			evts := make([]executor.RecordedEvent, len(test.Events))
+			for i, e := range test.Events {
+				evts[i] = executor.RecordedEvent{Type: e.Type, Pod: e.Pod}
+			}
+
+			act := executor.ReplayEvents(evts)
+			if len(act) != len(test.Expectations) {
+				t.Fatalf("unexpected number of replayed events: %d, expected %d", len(act), len(test.Expectations))
+			}
+			for i, exp := range test.Expectations {
+				r := act[i]
+				if r.Error != nil {
+					t.Errorf("event %d: unexpected error: %v", i, r.Error)
+					continue
+				}
+				res := expectation{
+					Phase:      r.Status.Phase,
+					Success:    r.Status.Conditions.Success,
+					Changed:    r.Changed,
+					DeletesPod: r.DeletesPod,
+				}
+				if res != exp {
+					t.Errorf("event %d: unexpected outcome %+v, expected %+v", i, res, exp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package executor
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// defaultEventRecordingMaxAge is how long we keep event recordings if the config does not say otherwise
+	defaultEventRecordingMaxAge = 7 * 24 * time.Hour
+
+	// eventRecordingExt is the file extension of event recordings
+	eventRecordingExt = ".json"
+)
+
+// RecordedEvent is a pod watch event as the executor received it, together with the status it computed.
+// Its JSON representation is compatible with the event trace log, hence both can be replayed.
+type RecordedEvent struct {
+	Time time.Time       `json:"time"`
+	Type watch.EventType `json:"type,omitempty"`
+	Pod  *corev1.Pod     `json:"job"`
+
+	// Status is the status the executor computed for the pod, Error the reason if it could not compute one
+	Status *v1.JobStatus `json:"status,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// recordEvent appends a pod watch event to the recording of its job, if recording is enabled
+func (js *Executor) recordEvent(evttpe watch.EventType, obj *corev1.Pod, status *v1.JobStatus, serr error) {
+	if js.Config.EventRecordingPath == "" {
+		return
+	}
+
+	evt := RecordedEvent{
+		Time:   time.Now(),
+		Type:   evttpe,
+		Pod:    obj,
+		Status: status,
+	}
+	if serr != nil {
+		evt.Error = serr.Error()
+	}
+
+	err := appendRecordedEvent(js.Config.EventRecordingPath, evt)
+	if err != nil {
+		log.WithError(err).WithField("name", obj.Name).Warn("cannot record pod event")
+	}
+}
+
+func appendRecordedEvent(dir string, evt RecordedEvent) error {
+	name, ok := getJobName(evt.Pod)
+	if !ok {
+		name = evt.Pod.Name
+	}
+	// job names are valid label values and hence safe file names, but the pod name of a pod without job name might not be
+	name = filepath.Base(filepath.Clean("/" + name))
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, name+eventRecordingExt), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(evt)
+}
+
+// pruneEventRecordings removes recordings whose job has not seen an event for longer than the configured max age
+func (js *Executor) pruneEventRecordings() {
+	dir := js.Config.EventRecordingPath
+	if dir == "" {
+		return
+	}
+	maxAge := defaultEventRecordingMaxAge
+	if js.Config.EventRecordingMaxAge != nil {
+		maxAge = js.Config.EventRecordingMaxAge.Duration
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.WithError(err).Warn("cannot prune event recordings")
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), eventRecordingExt) || time.Since(f.ModTime()) < maxAge {
+			continue
+		}
+		err = os.Remove(filepath.Join(dir, f.Name()))
+		if err != nil {
+			log.WithError(err).WithField("file", f.Name()).Warn("cannot prune event recording")
+		}
+	}
+}
+
+// ReadRecordedEvents reads an event recording or event trace log
+func ReadRecordedEvents(in io.Reader) ([]RecordedEvent, error) {
+	var res []RecordedEvent
+	dec := json.NewDecoder(in)
+	for {
+		var evt RecordedEvent
+		err := dec.Decode(&evt)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read event %d: %w", len(res)+1, err)
+		}
+		if evt.Pod == nil {
+			return nil, xerrors.Errorf("event %d has no pod", len(res)+1)
+		}
+		res = append(res, evt)
+	}
+	return res, nil
+}
+
+// ReplayedEvent is the outcome of computing the status of a recorded event anew
+type ReplayedEvent struct {
+	Event RecordedEvent
+
+	// Status is the status the executor reports for the event, Error the reason if there is none
+	Status *v1.JobStatus
+	Error  error
+
+	// Changed is true if the status differs from the one reported last for the same job, i.e. the executor reports it
+	Changed bool
+	// Deviates is true if the status derived from the pod differs from the recorded one, i.e. the status derivation
+	// has changed since
+	Deviates bool
+	// DeletesPod is true if the executor deletes the pod in response to the event
+	DeletesPod bool
+}
+
+// ReplayEvents computes the status of each recorded event the way the executor does when it receives the event.
+// Nothing is sent to Kubernetes.
+func ReplayEvents(evts []RecordedEvent) []ReplayedEvent {
+	var (
+		res    = make([]ReplayedEvent, len(evts))
+		phases jobPhases
+	)
+	for i, evt := range evts {
+		derived, err := getStatus(evt.Pod)
+		if err != nil {
+			res[i] = ReplayedEvent{
+				Event:    evt,
+				Error:    err,
+				Deviates: evt.Status != nil,
+			}
+			continue
+		}
+
+		status, changed := phases.Update(evt.Type, derived)
+		res[i] = ReplayedEvent{
+			Event:      evt,
+			Status:     status,
+			Changed:    changed,
+			Deviates:   evt.Error != "" || (evt.Status != nil && !sameStatus(evt.Status, derived)),
+			DeletesPod: needsDeletion(status),
+		}
+	}
+	return res
+}
+
+// sameStatus compares what the executor derives from a pod, but not the timestamps which depend on when it does that
+func sameStatus(a, b *v1.JobStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ac, bc := a.Conditions, b.Conditions
+	if ac == nil {
+		ac = &v1.JobConditions{}
+	}
+	if bc == nil {
+		bc = &v1.JobConditions{}
+	}
+	return a.Phase == b.Phase &&
+		a.Details == b.Details &&
+		ac.Success == bc.Success &&
+		ac.FailureClass == bc.FailureClass &&
+		ac.FailureCount == bc.FailureCount
+}
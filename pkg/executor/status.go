@@ -2,8 +2,12 @@ package executor
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/gogo/protobuf/jsonpb"
@@ -18,6 +22,12 @@ const (
 
 	// LabelMutex makes jobs findable via their mutex
 	LabelMutex = "werft.sh/mutex"
+
+	// ContainerCheckout is the name of the init container which initializes the workspace of a job
+	ContainerCheckout = "werft-checkout"
+
+	// VolumeWorkspace is the name of the volume which contains the workspace of a job
+	VolumeWorkspace = "werft-workspace"
 )
 
 // extracts the phase from the job object
@@ -26,6 +36,9 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		if status != nil && status.Phase == v1.JobPhase_PHASE_DONE {
 			status.Metadata.Finished = ptypes.TimestampNow()
 		}
+		if status != nil {
+			status.Timestamps = getTimestamps(obj, status)
+		}
 	}()
 
 	name, hasName := getJobName(obj)
@@ -43,6 +56,17 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		return nil, xerrors.Errorf("cannot unmarshal metadata: %w", err)
 	}
 
+	// user annotations added while the job runs are part of its metadata - sorted so that the order is stable
+	userdata := getUserData(obj)
+	keys := make([]string, 0, len(userdata))
+	for key := range userdata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		md.SetAnnotation(key, userdata[key])
+	}
+
 	var results []*v1.JobResult
 	if c, ok := obj.Annotations[AnnotationResults]; ok {
 		err = json.Unmarshal([]byte(c), &results)
@@ -51,16 +75,64 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		}
 	}
 
+	var progress *v1.JobProgress
+	if c, ok := obj.Annotations[AnnotationProgress]; ok {
+		progress = &v1.JobProgress{}
+		err = json.Unmarshal([]byte(c), progress)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot unmarshal progress: %w", err)
+		}
+	}
+
+	var steps []*v1.JobStep
+	if c, ok := obj.Annotations[AnnotationSteps]; ok {
+		err = json.Unmarshal([]byte(c), &steps)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot unmarshal steps: %w", err)
+		}
+	}
+	if sc := getStepContainers(obj); len(sc) > 0 {
+		steps = append(steps, getStepOutcomes(obj, sc)...)
+	}
+
+	var failedSlices []*v1.LogAnchor
+	if c, ok := obj.Annotations[AnnotationFailedSlices]; ok {
+		err = json.Unmarshal([]byte(c), &failedSlices)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot unmarshal failed slices: %w", err)
+		}
+	}
+
+	if obj.Status.StartTime != nil {
+		md.Started, _ = ptypes.TimestampProto(obj.Status.StartTime.Time)
+	}
+
+	environment, err := getEnvironment(obj)
+	if err != nil {
+		return nil, err
+	}
+
 	_, canReplay := obj.Annotations[AnnotationCanReplay]
+	_, logTruncated := obj.Annotations[AnnotationLogTruncated]
+	pinned, _ := strconv.ParseBool(obj.Annotations[AnnotationPinned])
+	workspaceUsage, _ := strconv.ParseInt(obj.Annotations[AnnotationWorkspaceUsage], 10, 64)
 	status = &v1.JobStatus{
 		Name:     name,
 		Metadata: &md,
 		Phase:    v1.JobPhase_PHASE_UNKNOWN,
 		Conditions: &v1.JobConditions{
-			Success:   true,
-			CanReplay: canReplay,
+			Success:      true,
+			CanReplay:    canReplay,
+			LogTruncated: logTruncated,
+			Pinned:       pinned,
 		},
-		Results: results,
+		Results:  results,
+		Progress: progress,
+		Steps:    steps,
+
+		FailedSlices:        failedSlices,
+		WorkspaceUsageBytes: workspaceUsage,
+		Environment:         environment,
 	}
 
 	var (
@@ -70,13 +142,6 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		allTerminated = len(statuses) != 0
 	)
 	for _, cs := range statuses {
-		if w := cs.State.Waiting; w != nil && w.Reason == "ErrImagePull" {
-			status.Phase = v1.JobPhase_PHASE_DONE
-			status.Conditions.Success = false
-			status.Details = w.Message
-			return
-		}
-
 		if cs.State.Terminated != nil {
 			if cs.State.Terminated.ExitCode != 0 {
 				anyFailed = true
@@ -91,15 +156,30 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 	}
 	status.Conditions.FailureCount = maxRestart
 	status.Conditions.Success = !(anyFailed || maxRestart > getFailureLimit(obj))
+	if !status.Conditions.Success {
+		status.Conditions.FailureClass = v1.JobFailureClass_FAILURE_TEST
+	}
 
-	if msg, failed := obj.Annotations[AnnotationFailed]; failed {
-		status.Phase = v1.JobPhase_PHASE_DONE
-		if obj.DeletionTimestamp != nil {
-			status.Phase = v1.JobPhase_PHASE_CLEANUP
+	if _, failed := obj.Annotations[AnnotationFailed]; !failed {
+		// failures werft caused itself, e.g. timeouts or stopping the job, take precedence over the ones we detect
+		if msg, failed := storageLimitFailure(obj); failed {
+			// the job filled its volumes itself, hence this is not an infrastructure failure
+			failStatus(status, obj, v1.JobFailureClass_FAILURE_TEST, msg)
+			return
 		}
-		status.Conditions.Success = false
-		status.Details = msg
+		if msg, failed := infrastructureFailure(obj); failed {
+			failStatus(status, obj, v1.JobFailureClass_FAILURE_INFRASTRUCTURE, msg)
+			return
+		}
+		if msg, failed := checkoutFailure(obj); failed {
+			// there's no point in waiting for the job to time out while preparing - without workspace it won't ever run
+			failStatus(status, obj, v1.JobFailureClass_FAILURE_CHECKOUT, msg)
+			return
+		}
+	}
 
+	if msg, failed := obj.Annotations[AnnotationFailed]; failed {
+		failStatus(status, obj, v1.JobFailureClass_FAILURE_UNCLASSIFIED, msg)
 		return
 	}
 	if obj.DeletionTimestamp != nil {
@@ -121,11 +201,139 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		return
 	case corev1.PodRunning:
 		status.Phase = v1.JobPhase_PHASE_RUNNING
+	case corev1.PodFailed:
+		// e.g. an init container of a pod which never restarts containers failed, hence the other containers never run
+		status.Phase = v1.JobPhase_PHASE_DONE
 	}
 
 	return
 }
 
+// getTimestamps derives the times a job entered its phases from its pod
+func getTimestamps(obj *corev1.Pod, status *v1.JobStatus) *v1.JobTimestamps {
+	res := &v1.JobTimestamps{
+		Queued: status.Metadata.Created,
+	}
+
+	for _, c := range obj.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionTrue {
+			res.Preparing, _ = ptypes.TimestampProto(c.LastTransitionTime.Time)
+		}
+	}
+	if res.Preparing == nil && obj.Status.StartTime != nil {
+		res.Preparing, _ = ptypes.TimestampProto(obj.Status.StartTime.Time)
+	}
+
+	var running, finished time.Time
+	for _, cs := range obj.Status.ContainerStatuses {
+		var started time.Time
+		if cs.State.Running != nil {
+			started = cs.State.Running.StartedAt.Time
+		} else if cs.State.Terminated != nil {
+			started = cs.State.Terminated.StartedAt.Time
+		}
+		if !started.IsZero() && (running.IsZero() || started.Before(running)) {
+			running = started
+		}
+	}
+	for _, cs := range append(obj.Status.InitContainerStatuses, obj.Status.ContainerStatuses...) {
+		if cs.State.Terminated != nil && cs.State.Terminated.FinishedAt.Time.After(finished) {
+			finished = cs.State.Terminated.FinishedAt.Time
+		}
+	}
+	if !running.IsZero() {
+		res.Running, _ = ptypes.TimestampProto(running)
+	}
+
+	if status.Phase == v1.JobPhase_PHASE_DONE || status.Phase == v1.JobPhase_PHASE_CLEANUP {
+		if !finished.IsZero() {
+			res.Finished, _ = ptypes.TimestampProto(finished)
+		} else {
+			// the job never got to run a container, e.g. because it failed to schedule
+			res.Finished = status.Metadata.Finished
+		}
+	}
+	return res
+}
+
+// failStatus marks a job as failed
+func failStatus(status *v1.JobStatus, obj *corev1.Pod, class v1.JobFailureClass, msg string) {
+	status.Phase = v1.JobPhase_PHASE_DONE
+	if obj.DeletionTimestamp != nil {
+		status.Phase = v1.JobPhase_PHASE_CLEANUP
+	}
+	status.Conditions.Success = false
+	status.Conditions.FailureClass = class
+	status.Details = msg
+}
+
+// evictedForStorageLimit matches the message of pods the kubelet evicted because one of their volumes exceeded its size limit
+var evictedForStorageLimit = regexp.MustCompile(`Usage of EmptyDir volume "([^"]+)" exceeds the limit "([^"]+)"`)
+
+// storageLimitFailure returns true if the job was evicted because it used more disk space than it may
+func storageLimitFailure(obj *corev1.Pod) (msg string, failed bool) {
+	if obj.Status.Reason != "Evicted" {
+		return "", false
+	}
+	m := evictedForStorageLimit.FindStringSubmatch(obj.Status.Message)
+	if m == nil {
+		return "", false
+	}
+	if m[1] == VolumeWorkspace {
+		return fmt.Sprintf("the workspace exceeded its size limit of %s", m[2]), true
+	}
+	return fmt.Sprintf("volume %s exceeded its size limit of %s", m[1], m[2]), true
+}
+
+// infrastructureFailure returns true if the job failed for reasons outside of its control, e.g. because its node was lost
+func infrastructureFailure(obj *corev1.Pod) (msg string, failed bool) {
+	for _, cs := range append(obj.Status.InitContainerStatuses, obj.Status.ContainerStatuses...) {
+		if w := cs.State.Waiting; w != nil && (w.Reason == "ErrImagePull" || w.Reason == "ImagePullBackOff") {
+			return w.Message, true
+		}
+	}
+
+	// Kubernetes explains pod failures it caused (e.g. Evicted or Shutdown) with a reason. Pods on lost nodes never fail.
+	if obj.Status.Reason != "" && (obj.Status.Phase == corev1.PodFailed || obj.Status.Reason == "NodeLost") {
+		if obj.Status.Message != "" {
+			return obj.Status.Message, true
+		}
+		return obj.Status.Reason, true
+	}
+	return "", false
+}
+
+// checkoutFailure returns true if the checkout init container failed more often than the job may fail
+func checkoutFailure(obj *corev1.Pod) (msg string, failed bool) {
+	for _, cs := range obj.Status.InitContainerStatuses {
+		if cs.Name != ContainerCheckout {
+			continue
+		}
+
+		// with restart policy OnFailure the init container is restarted, hence its failure may be in the past already
+		term := cs.State.Terminated
+		if term == nil && cs.State.Running == nil {
+			term = cs.LastTerminationState.Terminated
+		}
+		// pods which never restart containers, e.g. those of jobs with steps, fail on the first attempt
+		retries := obj.Spec.RestartPolicy != corev1.RestartPolicyNever
+		if term == nil || term.ExitCode == 0 || (retries && cs.RestartCount < getFailureLimit(obj)) {
+			return "", false
+		}
+
+		msg = "checkout failed"
+		// the termination message contains the end of the log output of the container - its last line is usually the error
+		lines := strings.Split(strings.TrimSpace(term.Message), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			msg += ": " + last
+		} else if term.Reason != "" && term.Reason != "Error" {
+			msg += ": " + term.Reason
+		}
+		return msg, true
+	}
+	return "", false
+}
+
 func getFailureLimit(obj *corev1.Pod) int32 {
 	val := obj.Annotations[AnnotationFailureLimit]
 	if val == "" {
@@ -142,10 +350,11 @@ func getJobName(obj *corev1.Pod) (id string, ok bool) {
 }
 
 func getUserData(obj *corev1.Pod) map[string]string {
+	prefix := UserDataAnnotationPrefix + "/"
 	res := make(map[string]string)
 	for key, val := range obj.Annotations {
-		if strings.HasPrefix(key, UserDataAnnotationPrefix) {
-			res[strings.TrimPrefix(key, UserDataAnnotationPrefix)] = val
+		if strings.HasPrefix(key, prefix) {
+			res[strings.TrimPrefix(key, prefix)] = val
 		}
 	}
 	return res
@@ -0,0 +1,204 @@
+package executor
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AnnotationStepContainers stores the JSON encoded list of containers which run the steps of a job
+	AnnotationStepContainers = "werft.sh/stepContainers"
+
+	// VolumeSteps is the name of the volume which holds the step runner and which the steps of a job use to tell each other their outcome
+	VolumeSteps = "werft-steps"
+
+	// ContainerStepRunner is the name of the init container which provides the step runner
+	ContainerStepRunner = "werft-step-runner"
+
+	// stepRunnerImage provides a statically linked busybox, which runs in any image
+	stepRunnerImage = "busybox:1.32.0-musl"
+
+	// StepsMountPath is where the steps of a job find the steps volume
+	StepsMountPath = "/werft"
+
+	// StepRunner is the shell the steps of a job run in, s.t. their images need no shell of their own.
+	// It is a busybox, hence steps can use its tools, too.
+	StepRunner = StepsMountPath + "/busybox"
+
+	// stepOutcomes is where the steps of a job find the outcomes of the other steps
+	stepOutcomes = StepsMountPath + "/steps"
+
+	// stepSkipped is the termination message of steps which did not run
+	stepSkipped = "skipped"
+)
+
+// stepScript waits for the steps in WERFT_STEP_AFTER to succeed, runs the command of the step and reports its exit code
+// to the steps which wait for it. If a step it waits for did not succeed, the step is skipped. All tools but the shell
+// builtins come from the step runner.
+const stepScript = `for s in $WERFT_STEP_AFTER; do
+  while [ ! -f "` + stepOutcomes + `/$s" ]; do ` + StepRunner + ` sleep 1; done
+  if [ "$(` + StepRunner + ` cat "` + stepOutcomes + `/$s")" != 0 ]; then
+    echo "skipping $WERFT_STEP because $s did not succeed"
+    echo ` + stepSkipped + ` > /dev/termination-log
+    echo ` + stepSkipped + ` > "` + stepOutcomes + `/.$WERFT_STEP" && ` + StepRunner + ` mv "` + stepOutcomes + `/.$WERFT_STEP" "` + stepOutcomes + `/$WERFT_STEP"
+    exit 0
+  fi
+done
+"$@"
+code=$?
+echo $code > "` + stepOutcomes + `/.$WERFT_STEP" && ` + StepRunner + ` mv "` + stepOutcomes + `/.$WERFT_STEP" "` + stepOutcomes + `/$WERFT_STEP"
+exit $code`
+
+// StepContainer is a container which runs a step of a job, and the steps it waits for
+type StepContainer struct {
+	Name  string   `json:"name"`
+	After []string `json:"after,omitempty"`
+}
+
+// WithSteps marks the containers which run the steps of a job. Their outcome becomes part of the job's status
+// and their output is placed in log slices named after them.
+func WithSteps(steps []StepContainer) StartOpt {
+	return func(opts *startOptions) {
+		opts.Modifier = append(opts.Modifier, func(j *corev1.Pod) {
+			raw, _ := json.Marshal(steps)
+			j.Annotations[AnnotationStepContainers] = string(raw)
+		})
+	}
+}
+
+// WrapStep makes a container run the command of a step once the steps it waits for succeeded.
+// The container needs a command, but its image needs no shell - the step runner brings its own.
+func WrapStep(c corev1.Container, after []string) corev1.Container {
+	c.Command = append([]string{StepRunner, "sh", "-c", stepScript, c.Name}, c.Command...)
+	c.Env = append(c.Env,
+		corev1.EnvVar{Name: "WERFT_STEP", Value: c.Name},
+		corev1.EnvVar{Name: "WERFT_STEP_AFTER", Value: strings.Join(after, " ")},
+	)
+	c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+		Name:      VolumeSteps,
+		MountPath: StepsMountPath,
+	})
+	return c
+}
+
+// StepRunnerContainer produces the init container which places the step runner in the steps volume
+func StepRunnerContainer() corev1.Container {
+	return corev1.Container{
+		Name:            ContainerStepRunner,
+		Image:           stepRunnerImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", "mkdir -p " + stepOutcomes + " && cp /bin/busybox " + StepRunner},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      VolumeSteps,
+				MountPath: StepsMountPath,
+			},
+		},
+	}
+}
+
+// StepsVolume produces the volume the steps of a job share
+func StepsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: VolumeSteps,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+// getStepContainers returns the containers which run the steps of a job, or nil if the job has no steps
+func getStepContainers(obj *corev1.Pod) []StepContainer {
+	raw, ok := obj.Annotations[AnnotationStepContainers]
+	if !ok {
+		return nil
+	}
+	var res []StepContainer
+	err := json.Unmarshal([]byte(raw), &res)
+	if err != nil {
+		return nil
+	}
+	return res
+}
+
+// isStepContainer returns true if the container runs a step of the job
+func isStepContainer(obj *corev1.Pod, container string) bool {
+	for _, s := range getStepContainers(obj) {
+		if s.Name == container {
+			return true
+		}
+	}
+	return false
+}
+
+// getStepOutcomes derives the outcome of the steps of a job from the containers which run them. Steps start
+// once the steps they wait for finished, or when their container started if they wait for none.
+func getStepOutcomes(obj *corev1.Pod, steps []StepContainer) []*v1.JobStep {
+	statuses := make(map[string]corev1.ContainerStatus, len(obj.Status.ContainerStatuses))
+	for _, cs := range obj.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	finishedAt := func(name string) (time.Time, bool) {
+		cs, ok := statuses[name]
+		if !ok || cs.State.Terminated == nil {
+			return time.Time{}, false
+		}
+		return cs.State.Terminated.FinishedAt.Time, true
+	}
+
+	res := make([]*v1.JobStep, 0, len(steps))
+	for _, s := range steps {
+		step := &v1.JobStep{Name: s.Name}
+		res = append(res, step)
+
+		cs, ok := statuses[s.Name]
+		if !ok {
+			continue
+		}
+		var containerStarted time.Time
+		if cs.State.Running != nil {
+			containerStarted = cs.State.Running.StartedAt.Time
+		} else if cs.State.Terminated != nil {
+			containerStarted = cs.State.Terminated.StartedAt.Time
+		}
+
+		if t := cs.State.Terminated; t != nil {
+			step.Finished, _ = ptypes.TimestampProto(t.FinishedAt.Time)
+			switch {
+			case t.ExitCode != 0:
+				step.Outcome = v1.StepOutcome_STEP_FAILED
+			case strings.TrimSpace(t.Message) == stepSkipped:
+				step.Outcome = v1.StepOutcome_STEP_SKIPPED
+				// skipped steps never started
+				step.Finished = nil
+				continue
+			default:
+				step.Outcome = v1.StepOutcome_STEP_SUCCEEDED
+			}
+		}
+
+		if containerStarted.IsZero() {
+			continue
+		}
+		started := containerStarted
+		for _, a := range s.After {
+			f, ok := finishedAt(a)
+			if !ok {
+				started = time.Time{}
+				break
+			}
+			if f.After(started) {
+				started = f
+			}
+		}
+		if !started.IsZero() {
+			step.Started, _ = ptypes.TimestampProto(started)
+		}
+	}
+	return res
+}
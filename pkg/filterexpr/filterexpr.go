@@ -2,6 +2,7 @@ package filterexpr
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
@@ -71,6 +72,48 @@ func Parse(exprs []string) ([]*v1.FilterTerm, error) {
 	return res, nil
 }
 
+// Fields lists the fields filter terms can refer to. Additionally, annotations can be referred to
+// using the "annotation." prefix, e.g. annotation.foo==bar.
+var Fields = []string{
+	"name",
+	"phase",
+	"owner",
+	"trigger",
+	"success",
+	"repo.owner",
+	"repo.repo",
+	"repo.host",
+	"repo.ref",
+	"repo.rev",
+	"pr",
+}
+
+// AnnotationPrefix is the prefix of fields referring to job annotations
+const AnnotationPrefix = "annotation."
+
+// Validate ensures that all terms of a filter refer to known fields
+func Validate(filter []*v1.FilterExpression) error {
+	for _, expr := range filter {
+		for _, term := range expr.Terms {
+			if strings.HasPrefix(term.Field, AnnotationPrefix) && len(term.Field) > len(AnnotationPrefix) {
+				continue
+			}
+
+			var known bool
+			for _, f := range Fields {
+				if f == term.Field {
+					known = true
+					break
+				}
+			}
+			if !known {
+				return xerrors.Errorf("unknown filter field: %s", term.Field)
+			}
+		}
+	}
+	return nil
+}
+
 // MatchesFilter returns true if the annotations are matched by the filter
 func MatchesFilter(js *v1.JobStatus, filter []*v1.FilterExpression) (matches bool) {
 	if len(filter) == 0 {
@@ -84,9 +127,17 @@ func MatchesFilter(js *v1.JobStatus, filter []*v1.FilterExpression) (matches boo
 		"name":  js.Name,
 		"phase": strings.ToLower(strings.TrimPrefix(js.Phase.String(), "PHASE_")),
 	}
+	if js.Conditions != nil {
+		// Parse turns success==true into success==1
+		if js.Conditions.Success {
+			idx["success"] = "1"
+		} else {
+			idx["success"] = "0"
+		}
+	}
 	if js.Metadata != nil {
 		idx["owner"] = js.Metadata.Owner
-		idx["trigger"] = strings.ToLower(strings.TrimPrefix("TRIGGER_", js.Metadata.Trigger.String()))
+		idx["trigger"] = strings.ToLower(strings.TrimPrefix(js.Metadata.Trigger.String(), "TRIGGER_"))
 		if js.Metadata.Repository != nil {
 			idx["repo.owner"] = js.Metadata.Repository.Owner
 			idx["repo.repo"] = js.Metadata.Repository.Repo
@@ -94,9 +145,12 @@ func MatchesFilter(js *v1.JobStatus, filter []*v1.FilterExpression) (matches boo
 			idx["repo.ref"] = js.Metadata.Repository.Ref
 			idx["repo.rev"] = js.Metadata.Repository.Revision
 		}
-	}
-	for _, at := range js.Metadata.Annotations {
-		idx["annotation."+at.Key] = at.Value
+		if pr := js.Metadata.GetEvent().GetPullRequest(); pr > 0 {
+			idx["pr"] = strconv.Itoa(int(pr))
+		}
+		for _, at := range js.Metadata.Annotations {
+			idx[AnnotationPrefix+at.Key] = at.Value
+		}
 	}
 
 	matches = true
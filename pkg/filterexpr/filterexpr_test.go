@@ -72,6 +72,37 @@ func TestMatchesFilter(t *testing.T) {
 			[]*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "name", Value: "foobar", Operation: v1.FilterOp_OP_STARTS_WITH}}}},
 			true,
 		},
+		{
+			&v1.JobStatus{Metadata: &v1.JobMetadata{Trigger: v1.JobTrigger_TRIGGER_PUSH}, Conditions: &v1.JobConditions{Success: true}},
+			[]*v1.FilterExpression{
+				&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "trigger", Value: "push", Operation: v1.FilterOp_OP_EQUALS}}},
+				&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "success", Value: "1", Operation: v1.FilterOp_OP_EQUALS}}},
+			},
+			true,
+		},
+		{
+			&v1.JobStatus{Metadata: &v1.JobMetadata{Annotations: []*v1.Annotation{&v1.Annotation{Key: "team", Value: "infra"}}}},
+			[]*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "annotation.team", Value: "web", Operation: v1.FilterOp_OP_EQUALS}}}},
+			false,
+		},
+		{
+			&v1.JobStatus{Metadata: &v1.JobMetadata{Repository: &v1.Repository{Revision: "3f2a9c1d"}, Event: &v1.JobEvent{PullRequest: 42}}},
+			[]*v1.FilterExpression{
+				&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "repo.rev", Value: "3f2a", Operation: v1.FilterOp_OP_STARTS_WITH}}},
+				&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "pr", Value: "42", Operation: v1.FilterOp_OP_EQUALS}}},
+			},
+			true,
+		},
+		{
+			&v1.JobStatus{Metadata: md},
+			[]*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "pr", Value: "0", Operation: v1.FilterOp_OP_EQUALS}}}},
+			false,
+		},
+		{
+			&v1.JobStatus{Name: "no-metadata"},
+			[]*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "annotation.team", Value: "web", Operation: v1.FilterOp_OP_EQUALS}}}},
+			false,
+		},
 	}
 
 	for idx, test := range tests {
@@ -80,3 +111,24 @@ func TestMatchesFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Field string
+		Valid bool
+	}{
+		{"repo.owner", true},
+		{"phase", true},
+		{"pr", true},
+		{"annotation.foo", true},
+		{"annotation.", false},
+		{"foo", false},
+	}
+
+	for _, test := range tests {
+		err := filterexpr.Validate([]*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: test.Field}}}})
+		if (err == nil) != test.Valid {
+			t.Errorf("%s: expected valid=%v, got error %v", test.Field, test.Valid, err)
+		}
+	}
+}
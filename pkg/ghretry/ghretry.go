@@ -0,0 +1,192 @@
+// Package ghretry retries GitHub API requests which failed because of rate limits or transient errors
+package ghretry
+
+import (
+	"bytes"
+	"expvar"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxRetries is how often a request is retried at most
+	DefaultMaxRetries = 5
+	// DefaultMaxWait is how long we wait for a rate limit to reset at most before we give up
+	DefaultMaxWait = 2 * time.Minute
+	// DefaultMinBackoff is how long we wait before the first retry of a transient error
+	DefaultMinBackoff = 1 * time.Second
+
+	// maxBackoff caps the exponential backoff between retries of transient errors
+	maxBackoff = 30 * time.Second
+	// secondaryRateLimitWait is how long GitHub asks clients to wait after hitting a secondary rate limit
+	// which does not say when to retry
+	secondaryRateLimitWait = 1 * time.Minute
+	// maxInspectedBody limits how much of an error response we read to tell secondary rate limits from other errors
+	maxInspectedBody = 64 * 1024
+)
+
+// Stats counts what happened to GitHub API requests
+type Stats struct {
+	// Requests is the number of requests, not counting retries
+	Requests int64 `json:"requests"`
+	// Retries is the number of retried requests
+	Retries int64 `json:"retries"`
+	// RateLimited is the number of responses which hit a rate limit
+	RateLimited int64 `json:"rateLimited"`
+	// SecondaryRateLimited is the number of responses which hit a secondary (abuse) rate limit
+	SecondaryRateLimited int64 `json:"secondaryRateLimited"`
+	// GaveUp is the number of requests which still failed once we ran out of retries or the rate limit reset too late
+	GaveUp int64 `json:"gaveUp"`
+}
+
+var stats Stats
+
+func init() {
+	// the server exposes these at /debug/vars
+	expvar.Publish("github", expvar.Func(func() interface{} { return Counters() }))
+}
+
+// Counters returns the stats of all transports
+func Counters() Stats {
+	return Stats{
+		Requests:             atomic.LoadInt64(&stats.Requests),
+		Retries:              atomic.LoadInt64(&stats.Retries),
+		RateLimited:          atomic.LoadInt64(&stats.RateLimited),
+		SecondaryRateLimited: atomic.LoadInt64(&stats.SecondaryRateLimited),
+		GaveUp:               atomic.LoadInt64(&stats.GaveUp),
+	}
+}
+
+// Transport retries requests which hit a rate limit once the limit resets, and requests which failed
+// because of network or server errors with exponential backoff.
+type Transport struct {
+	Base http.RoundTripper
+
+	MaxRetries int
+	MaxWait    time.Duration
+	MinBackoff time.Duration
+}
+
+// NewTransport produces a transport with the default retry policy. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:       base,
+		MaxRetries: DefaultMaxRetries,
+		MaxWait:    DefaultMaxWait,
+		MinBackoff: DefaultMinBackoff,
+	}
+}
+
+// RoundTrip sends a request and retries it if need be
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	atomic.AddInt64(&stats.Requests, 1)
+
+	// we must be able to send the body again when retrying
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			// WithContext copies the request, s.t. each attempt gets a fresh body
+			r = req.WithContext(req.Context())
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := base.RoundTrip(r)
+		wait, reason := t.retryAfter(resp, err, attempt)
+		if reason == "" {
+			return resp, err
+		}
+		if attempt >= t.MaxRetries || wait > t.MaxWait {
+			atomic.AddInt64(&stats.GaveUp, 1)
+			log.WithField("url", req.URL.String()).WithField("reason", reason).WithField("wait", wait).Warn("giving up on GitHub API request")
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		log.WithField("url", req.URL.String()).WithField("reason", reason).WithField("wait", wait).Debug("retrying GitHub API request")
+		atomic.AddInt64(&stats.Retries, 1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter determines if and when a request should be retried. If it should not, the reason is empty.
+func (t *Transport) retryAfter(resp *http.Response, err error, attempt int) (wait time.Duration, reason string) {
+	if err != nil {
+		return t.backoff(attempt), err.Error()
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return t.backoff(attempt), resp.Status
+	case http.StatusForbidden, http.StatusTooManyRequests:
+	default:
+		return 0, ""
+	}
+
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		atomic.AddInt64(&stats.SecondaryRateLimited, 1)
+		secs, _ := strconv.Atoi(ra)
+		return time.Duration(secs) * time.Second, "secondary rate limit"
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		atomic.AddInt64(&stats.RateLimited, 1)
+		reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		wait = time.Until(time.Unix(reset, 0))
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, "rate limit"
+	}
+
+	// secondary rate limits do not always say when to retry, but name themselves in the body
+	inspected, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxInspectedBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(inspected), resp.Body), resp.Body}
+	msg := strings.ToLower(string(inspected))
+	if strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection") {
+		atomic.AddInt64(&stats.SecondaryRateLimited, 1)
+		return secondaryRateLimitWait, "secondary rate limit"
+	}
+	return 0, ""
+}
+
+// backoff returns the time to wait before the next attempt after a transient error
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.MinBackoff << uint(attempt)
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	// jitter keeps many failed requests from retrying all at once
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
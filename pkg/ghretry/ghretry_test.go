@@ -0,0 +1,82 @@
+package ghretry_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/ghretry"
+)
+
+func TestTransport(t *testing.T) {
+	type response struct {
+		Status  int
+		Headers map[string]string
+		Body    string
+	}
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		Name      string
+		Responses []response
+		Status    int
+		Attempts  int
+	}{
+		{"success", []response{{Status: 200}}, 200, 1},
+		{"not found", []response{{Status: 404}}, 404, 1},
+		{"server error", []response{{Status: 502}, {Status: 503}, {Status: 201}}, 201, 3},
+		{"rate limit reset", []response{{Status: 403, Headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": past}}, {Status: 200}}, 200, 2},
+		{"rate limit resets too late", []response{{Status: 403, Headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": future}}}, 403, 1},
+		{"secondary rate limit", []response{{Status: 403, Headers: map[string]string{"Retry-After": "0"}}, {Status: 200}}, 200, 2},
+		{"secondary rate limit without retry-after", []response{{Status: 403, Body: `{"message": "You have exceeded a secondary rate limit"}`}}, 403, 1},
+		{"forbidden", []response{{Status: 403, Body: `{"message": "Resource not accessible by integration"}`}}, 403, 1},
+		{"out of retries", []response{{Status: 502}, {Status: 502}, {Status: 502}, {Status: 502}}, 502, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var attempts int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("attempt %d: expected request body to be sent again, got %q", attempts, string(body))
+				}
+				resp := test.Responses[attempts]
+				attempts++
+				for k, v := range resp.Headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(resp.Status)
+				w.Write([]byte(resp.Body))
+			}))
+			defer srv.Close()
+
+			tr := ghretry.NewTransport(nil)
+			tr.MaxRetries = 2
+			tr.MaxWait = time.Second
+			tr.MinBackoff = time.Millisecond
+			client := &http.Client{Transport: tr}
+
+			resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.Status {
+				t.Errorf("expected status %d, got %d", test.Status, resp.StatusCode)
+			}
+			if attempts != test.Attempts {
+				t.Errorf("expected %d attempts, got %d", test.Attempts, attempts)
+			}
+			if exp := test.Responses[attempts-1].Body; string(body) != exp {
+				t.Errorf("expected body %q, got %q", exp, string(body))
+			}
+		})
+	}
+}
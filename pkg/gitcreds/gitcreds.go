@@ -0,0 +1,187 @@
+package gitcreds
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/ghretry"
+	"github.com/bradleyfalzon/ghinstallation"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// BackendGitHubApp issues GitHub App installation tokens
+	BackendGitHubApp = "github-app"
+
+	// BackendVault reads credentials from HashiCorp Vault
+	BackendVault = "vault"
+
+	// defaultMinValidity is how long credentials must remain valid once we hand them out if the config does not say otherwise
+	defaultMinValidity = 30 * time.Minute
+)
+
+// Credentials authenticate git operations
+type Credentials struct {
+	User     string
+	Password string
+
+	// Expiry is the time the credentials expire. Credentials without expiry have a zero expiry.
+	Expiry time.Time
+}
+
+// Backend issues git credentials
+type Backend interface {
+	// Credentials issues new credentials
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// Config configures where werft gets its git credentials from
+type Config struct {
+	// Backend is either github-app (default) or vault
+	Backend string `yaml:"backend,omitempty"`
+
+	// MinValidity is how long credentials must remain valid once handed to a job, i.e. how long its checkout may take.
+	// Credentials which expire sooner are refreshed.
+	MinValidity *executor.Duration `yaml:"minValidity,omitempty"`
+
+	// Vault configures the vault backend
+	Vault *VaultConfig `yaml:"vault,omitempty"`
+}
+
+// GitHubAppConfig is the part of the GitHub App setup we need to issue installation tokens
+type GitHubAppConfig struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyPath string
+}
+
+// Validate checks if the config selects a known backend and configures it, naming the offending field
+func (cfg Config) Validate() error {
+	switch cfg.Backend {
+	case "", BackendGitHubApp:
+	case BackendVault:
+		if cfg.Vault == nil {
+			return xerrors.Errorf("vault: required by the vault backend")
+		}
+		if cfg.Vault.Address == "" {
+			return xerrors.Errorf("vault.address: must not be empty")
+		}
+		if cfg.Vault.Path == "" {
+			return xerrors.Errorf("vault.path: must not be empty")
+		}
+		if cfg.Vault.Role == "" {
+			return xerrors.Errorf("vault.role: must not be empty")
+		}
+	default:
+		return xerrors.Errorf("backend: unknown git credential backend \"%s\" - must be %s or %s", cfg.Backend, BackendGitHubApp, BackendVault)
+	}
+	if cfg.MinValidity != nil && cfg.MinValidity.Duration < 0 {
+		return xerrors.Errorf("minValidity: must not be negative")
+	}
+	return nil
+}
+
+// NewBackend produces the backend the config selects
+func NewBackend(cfg Config, app GitHubAppConfig) (Backend, error) {
+	switch cfg.Backend {
+	case "", BackendGitHubApp:
+		tr, err := ghinstallation.NewAppsTransportKeyFromFile(ghretry.NewTransport(http.DefaultTransport), app.AppID, app.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		return &GitHubApp{
+			Client:         github.NewClient(&http.Client{Transport: tr}),
+			InstallationID: app.InstallationID,
+		}, nil
+	case BackendVault:
+		if cfg.Vault == nil {
+			return nil, xerrors.Errorf("vault backend is not configured")
+		}
+		return &Vault{Config: *cfg.Vault}, nil
+	default:
+		return nil, xerrors.Errorf("unknown git credential backend \"%s\"", cfg.Backend)
+	}
+}
+
+// GitHubApp issues GitHub App installation tokens
+type GitHubApp struct {
+	// Client authenticates as the GitHub App
+	Client         *github.Client
+	InstallationID int64
+}
+
+// Credentials issues a new installation token
+func (g *GitHubApp) Credentials(ctx context.Context) (*Credentials, error) {
+	tkn, _, err := g.Client.Apps.CreateInstallationToken(ctx, g.InstallationID)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create installation token: %w", err)
+	}
+	return &Credentials{
+		User:     "x-access-token",
+		Password: tkn.GetToken(),
+		Expiry:   tkn.GetExpiresAt(),
+	}, nil
+}
+
+// Refresher caches the credentials of a backend and refreshes them before they expire
+type Refresher struct {
+	Backend Backend
+
+	// MinValidity is how long credentials must remain valid once we hand them out
+	MinValidity time.Duration
+
+	mu      sync.Mutex
+	current *Credentials
+}
+
+// NewRefresher produces a refresher for the backend the config selects
+func NewRefresher(cfg Config, app GitHubAppConfig) (*Refresher, error) {
+	backend, err := NewBackend(cfg, app)
+	if err != nil {
+		return nil, err
+	}
+
+	minValidity := defaultMinValidity
+	if cfg.MinValidity != nil && cfg.MinValidity.Duration > 0 {
+		minValidity = cfg.MinValidity.Duration
+	}
+	return &Refresher{Backend: backend, MinValidity: minValidity}, nil
+}
+
+// Credentials returns credentials which remain valid for at least MinValidity
+func (r *Refresher) Credentials(ctx context.Context) (*Credentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current != nil && (r.current.Expiry.IsZero() || time.Until(r.current.Expiry) > r.MinValidity) {
+		return r.current, nil
+	}
+
+	creds, err := r.Backend.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !creds.Expiry.IsZero() && time.Until(creds.Expiry) <= r.MinValidity {
+		// the backend issues credentials which are too short-lived to ever satisfy our minimum validity - we use them
+		// anyways, but don't cache them so that every checkout gets fresh ones.
+		log.WithField("expiry", creds.Expiry).Warn("git credentials expire sooner than the configured minimum validity")
+		r.current = nil
+		return creds, nil
+	}
+	r.current = creds
+	return creds, nil
+}
+
+// Helper provides the credentials as user and password, e.g. to serve as werft.GitCredentialHelper
+func (r *Refresher) Helper(ctx context.Context) (user string, pass string, err error) {
+	creds, err := r.Credentials(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return creds.User, creds.Password, nil
+}
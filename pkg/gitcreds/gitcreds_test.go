@@ -0,0 +1,173 @@
+package gitcreds_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/gitcreds"
+)
+
+type fakeBackend struct {
+	Validity time.Duration
+	Calls    int
+}
+
+func (f *fakeBackend) Credentials(ctx context.Context) (*gitcreds.Credentials, error) {
+	f.Calls++
+	res := &gitcreds.Credentials{User: "user", Password: fmt.Sprintf("pass%d", f.Calls)}
+	if f.Validity != 0 {
+		res.Expiry = time.Now().Add(f.Validity)
+	}
+	return res, nil
+}
+
+func TestRefresher(t *testing.T) {
+	tests := []struct {
+		Validity    time.Duration
+		MinValidity time.Duration
+		Calls       int
+	}{
+		{0, 30 * time.Minute, 1},
+		{time.Hour, 30 * time.Minute, 1},
+		{time.Hour, 2 * time.Hour, 3},
+		{10 * time.Minute, 30 * time.Minute, 3},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			backend := &fakeBackend{Validity: test.Validity}
+			r := &gitcreds.Refresher{Backend: backend, MinValidity: test.MinValidity}
+
+			var pass string
+			for i := 0; i < 3; i++ {
+				var err error
+				_, pass, err = r.Helper(context.Background())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if backend.Calls != test.Calls {
+				t.Errorf("unexpected number of backend calls: expected %d, actual %d", test.Calls, backend.Calls)
+			}
+			if exp := fmt.Sprintf("pass%d", test.Calls); pass != exp {
+				t.Errorf("unexpected password: expected %s, actual %s", exp, pass)
+			}
+		})
+	}
+}
+
+func TestVault(t *testing.T) {
+	tokenFile, err := ioutil.TempFile("", "gitcreds-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tokenFile.Name())
+	fmt.Fprint(tokenFile, "service-account-jwt\n")
+	tokenFile.Close()
+
+	tests := []struct {
+		Path     string
+		Config   gitcreds.VaultConfig
+		User     string
+		Password string
+		HasLease bool
+		Error    bool
+	}{
+		{"secret/data/werft/git", gitcreds.VaultConfig{}, "werft", "kv-pass", false, false},
+		{"github/token", gitcreds.VaultConfig{PasswordKey: "token", Username: "x-access-token"}, "x-access-token", "gh-token", true, false},
+		{"secret/unknown", gitcreds.VaultConfig{}, "", "", false, true},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/kubernetes/login" {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["jwt"] != "service-account-jwt" || body["role"] != "werft" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"errors":["permission denied"]}`)
+				return
+			}
+			fmt.Fprint(w, `{"auth":{"client_token":"vault-token"}}`)
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/werft/git":
+			fmt.Fprint(w, `{"data":{"data":{"username":"werft","password":"kv-pass"}}}`)
+		case "/v1/github/token":
+			fmt.Fprint(w, `{"lease_duration":3600,"data":{"token":"gh-token"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			cfg := test.Config
+			cfg.Address = srv.URL
+			cfg.Path = test.Path
+			cfg.Role = "werft"
+			cfg.TokenPath = tokenFile.Name()
+
+			v := &gitcreds.Vault{Config: cfg}
+			creds, err := v.Credentials(context.Background())
+			if test.Error {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if creds.User != test.User {
+				t.Errorf("unexpected user: expected %s, actual %s", test.User, creds.User)
+			}
+			if creds.Password != test.Password {
+				t.Errorf("unexpected password: expected %s, actual %s", test.Password, creds.Password)
+			}
+			if hasLease := time.Until(creds.Expiry) > 30*time.Minute; hasLease != test.HasLease {
+				t.Errorf("unexpected expiry: %v", creds.Expiry)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	vault := &gitcreds.VaultConfig{Address: "https://vault:8200", Path: "secret/data/werft/git", Role: "werft"}
+	tests := []struct {
+		Name   string
+		Config gitcreds.Config
+		Error  bool
+	}{
+		{"default", gitcreds.Config{}, false},
+		{"vault", gitcreds.Config{Backend: gitcreds.BackendVault, Vault: vault}, false},
+		{"vault without config", gitcreds.Config{Backend: gitcreds.BackendVault}, true},
+		{"vault without role", gitcreds.Config{Backend: gitcreds.BackendVault, Vault: &gitcreds.VaultConfig{Address: vault.Address, Path: vault.Path}}, true},
+		{"unknown backend", gitcreds.Config{Backend: "ldap"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package gitcreds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultVaultAuthPath is where the Kubernetes auth method is mounted if the config does not say otherwise
+	defaultVaultAuthPath = "kubernetes"
+
+	// defaultServiceAccountTokenPath is where Kubernetes mounts the service account token we log in to vault with
+	defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// vaultSecretTTL is how long we use a secret which vault issued without a lease, e.g. a KV secret
+	vaultSecretTTL = 5 * time.Minute
+)
+
+// VaultConfig configures the vault backend
+type VaultConfig struct {
+	// Address is the address of the vault server, e.g. https://vault:8200
+	Address string `yaml:"address"`
+
+	// Path is the path of the secret which contains the credentials, e.g. secret/data/werft/git for a KV secret
+	// or github/token for a secrets engine issuing GitHub tokens
+	Path string `yaml:"path"`
+
+	// Role is the role we log in to vault with using the Kubernetes auth method
+	Role string `yaml:"role"`
+
+	// AuthPath is where the Kubernetes auth method is mounted. Defaults to kubernetes.
+	AuthPath string `yaml:"authPath,omitempty"`
+
+	// TokenPath is the path of the service account token we log in with. Defaults to the token Kubernetes mounts into the pod.
+	TokenPath string `yaml:"tokenPath,omitempty"`
+
+	// UsernameKey is the key of the username in the secret. Defaults to username.
+	UsernameKey string `yaml:"usernameKey,omitempty"`
+
+	// PasswordKey is the key of the password in the secret. Defaults to password.
+	PasswordKey string `yaml:"passwordKey,omitempty"`
+
+	// Username is used if the secret does not contain a username, e.g. x-access-token for GitHub tokens
+	Username string `yaml:"username,omitempty"`
+}
+
+// Vault reads credentials from HashiCorp Vault. It logs in using the Kubernetes auth method.
+type Vault struct {
+	Config VaultConfig
+
+	// Client is the HTTP client we talk to vault with. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// vaultResponse is the part of vault's responses we care about
+type vaultResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// Credentials reads the credentials from vault
+func (v *Vault) Credentials(ctx context.Context) (*Credentials, error) {
+	token, err := v.login(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot log in to vault: %w", err)
+	}
+
+	issued := time.Now()
+	resp, err := v.request(ctx, http.MethodGet, v.Config.Path, token, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read %s from vault: %w", v.Config.Path, err)
+	}
+
+	data := resp.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		// KV version 2 nests the secret
+		data = nested
+	}
+	usernameKey, passwordKey := v.Config.UsernameKey, v.Config.PasswordKey
+	if usernameKey == "" {
+		usernameKey = "username"
+	}
+	if passwordKey == "" {
+		passwordKey = "password"
+	}
+
+	res := &Credentials{User: v.Config.Username}
+	if user, ok := data[usernameKey].(string); ok && user != "" {
+		res.User = user
+	}
+	res.Password, _ = data[passwordKey].(string)
+	if res.Password == "" {
+		return nil, xerrors.Errorf("%s has no %s", v.Config.Path, passwordKey)
+	}
+	if resp.LeaseDuration > 0 {
+		res.Expiry = issued.Add(time.Duration(resp.LeaseDuration) * time.Second)
+	} else {
+		res.Expiry = issued.Add(vaultSecretTTL)
+	}
+	return res, nil
+}
+
+// login logs in using the Kubernetes auth method and returns the vault token
+func (v *Vault) login(ctx context.Context) (string, error) {
+	tokenPath := v.Config.TokenPath
+	if tokenPath == "" {
+		tokenPath = defaultServiceAccountTokenPath
+	}
+	jwt, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		return "", err
+	}
+	authPath := v.Config.AuthPath
+	if authPath == "" {
+		authPath = defaultVaultAuthPath
+	}
+
+	resp, err := v.request(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", strings.Trim(authPath, "/")), "", map[string]string{
+		"role": v.Config.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", xerrors.Errorf("vault did not issue a token")
+	}
+	return resp.Auth.ClientToken, nil
+}
+
+func (v *Vault) request(ctx context.Context, method, path, token string, body interface{}) (*vaultResponse, error) {
+	var in bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&in).Encode(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(v.Config.Address, "/"), strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequest(method, url, &in)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res vaultResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil && resp.StatusCode == http.StatusOK {
+		return nil, xerrors.Errorf("invalid response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(res.Errors) > 0 {
+			return nil, xerrors.Errorf("vault responded with %d: %s", resp.StatusCode, strings.Join(res.Errors, ", "))
+		}
+		return nil, xerrors.Errorf("vault responded with %d", resp.StatusCode)
+	}
+	return &res, nil
+}
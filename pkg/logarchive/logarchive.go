@@ -0,0 +1,220 @@
+package logarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/xerrors"
+)
+
+// Format is the compression of a log archive
+type Format string
+
+const (
+	// FormatGzip compresses archives with gzip
+	FormatGzip Format = "gzip"
+	// FormatZstd compresses archives with zstd
+	FormatZstd Format = "zstd"
+)
+
+// ParseFormat parses the name of an archive format. An empty string yields gzip.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatGzip, "gz":
+		return FormatGzip, nil
+	case FormatZstd, "zst":
+		return FormatZstd, nil
+	default:
+		return "", xerrors.Errorf("unknown archive format \"%s\" - must be %s or %s", s, FormatGzip, FormatZstd)
+	}
+}
+
+// ContentType returns the MIME type of archives in this format
+func (f Format) ContentType() string {
+	if f == FormatZstd {
+		return "application/zstd"
+	}
+	return "application/gzip"
+}
+
+// Extension returns the file extension of archives in this format, e.g. .log.gz
+func (f Format) Extension(split bool) string {
+	ext := ".gz"
+	if f == FormatZstd {
+		ext = ".zst"
+	}
+	if split {
+		return ".tar" + ext
+	}
+	return ".log" + ext
+}
+
+// Options configure how an archive is written
+type Options struct {
+	Format Format
+
+	// Split adds a file per log slice next to the complete log. Split archives are tarballs.
+	Split bool
+
+	// Cutter cuts the log into slices when splitting it. Defaults to logcutter.DefaultCutter.
+	Cutter logcutter.Cutter
+
+	// ModTime is the modification time of the files in split archives
+	ModTime time.Time
+}
+
+// Write compresses the log of a job to out. Unless the log is split, the log is streamed as it's read.
+// Split archives contain <name>/job.log and a file per slice in <name>/slices, numbered in the order the slices started.
+// To split the log we have to hold it in memory.
+func Write(out io.Writer, name string, log io.Reader, opts Options) (err error) {
+	cw, err := newCompressor(out, opts.Format)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := cw.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	if !opts.Split {
+		_, err = io.Copy(cw, log)
+		return err
+	}
+
+	full, err := ioutil.ReadAll(log)
+	if err != nil {
+		return err
+	}
+	cutter := opts.Cutter
+	if cutter == nil {
+		cutter = logcutter.DefaultCutter
+	}
+	slices, err := splitLog(full, cutter)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(cw)
+	err = writeFile(tw, fmt.Sprintf("%s/job.log", name), full, opts.ModTime)
+	if err != nil {
+		return err
+	}
+	for i, sl := range slices {
+		fn := fmt.Sprintf("%s/slices/%03d-%s.log", name, i+1, sanitizeFilename(sl.Name))
+		err = writeFile(tw, fn, sl.Content.Bytes(), opts.ModTime)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
+
+// newCompressor produces a writer which compresses to out in the given format
+func newCompressor(out io.Writer, format Format) (io.WriteCloser, error) {
+	switch format {
+	case "", FormatGzip:
+		return gzip.NewWriter(out), nil
+	case FormatZstd:
+		return zstd.NewWriter(out)
+	default:
+		return nil, xerrors.Errorf("unknown archive format: %s", format)
+	}
+}
+
+type logSlice struct {
+	Name    string
+	Content bytes.Buffer
+}
+
+// splitLog sorts the lines of a log into the slices they belong to. The slices keep the lines as they are in the log,
+// including their timestamp and marker.
+func splitLog(full []byte, cutter logcutter.Cutter) ([]*logSlice, error) {
+	var (
+		res       []*logSlice
+		idx       = make(map[string]*logSlice)
+		lineStart int64
+		cerr      error
+	)
+	evts, errchan := cutter.Slice(bytes.NewReader(full))
+	for evts != nil {
+		select {
+		case evt, ok := <-evts:
+			if !ok {
+				evts = nil
+				continue
+			}
+			if evt.Type == v1.LogSliceType_SLICE_START {
+				// start events point to the beginning of their line, all others to its end
+				continue
+			}
+
+			cursor, err := logcutter.ParseCursor(evt.Cursor)
+			if err != nil {
+				// we must not stop reading the events lest the cutter blocks forever
+				if cerr == nil {
+					cerr = err
+				}
+				continue
+			}
+			end := cursor.Offset
+			if end > int64(len(full)) {
+				end = int64(len(full))
+			}
+			if evt.Type == v1.LogSliceType_SLICE_CONTENT && end >= lineStart {
+				sl, ok := idx[evt.Name]
+				if !ok {
+					sl = &logSlice{Name: evt.Name}
+					idx[evt.Name] = sl
+					res = append(res, sl)
+				}
+				sl.Content.Write(full[lineStart:end])
+			}
+			lineStart = end
+		case err := <-errchan:
+			if err != nil && cerr == nil {
+				cerr = err
+			}
+		}
+	}
+	if cerr != nil {
+		return nil, cerr
+	}
+	return res, nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeFilename turns a slice name into something we can use as file name
+func sanitizeFilename(name string) string {
+	res := strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "._")
+	if res == "" {
+		return "slice"
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+package logarchive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/logarchive"
+	"github.com/klauspost/compress/zstd"
+)
+
+const testLog = `[build|PHASE] Building
+[foo] first line
+[bar] unrelated
+[foo] second line
+[foo|DONE]
+[bar|FAIL] broken
+plain output
+`
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Format logarchive.Format
+		Split  bool
+		Files  map[string]string
+	}{
+		{
+			Name:   "gzip",
+			Format: logarchive.FormatGzip,
+			Files:  map[string]string{"": testLog},
+		},
+		{
+			Name:   "zstd",
+			Format: logarchive.FormatZstd,
+			Files:  map[string]string{"": testLog},
+		},
+		{
+			Name:   "split",
+			Format: logarchive.FormatGzip,
+			Split:  true,
+			Files: map[string]string{
+				"job-1/job.log":              testLog,
+				"job-1/slices/001-foo.log":   "[foo] first line\n[foo] second line\n",
+				"job-1/slices/002-bar.log":   "[bar] unrelated\n",
+				"job-1/slices/003-build.log": "plain output\n",
+			},
+		},
+		{
+			Name:   "split zstd",
+			Format: logarchive.FormatZstd,
+			Split:  true,
+			Files: map[string]string{
+				"job-1/job.log":              testLog,
+				"job-1/slices/001-foo.log":   "[foo] first line\n[foo] second line\n",
+				"job-1/slices/002-bar.log":   "[bar] unrelated\n",
+				"job-1/slices/003-build.log": "plain output\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := logarchive.Write(&buf, "job-1", strings.NewReader(testLog), logarchive.Options{
+				Format: test.Format,
+				Split:  test.Split,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var rd io.Reader
+			switch test.Format {
+			case logarchive.FormatGzip:
+				rd, err = gzip.NewReader(&buf)
+			case logarchive.FormatZstd:
+				rd, err = zstd.NewReader(&buf)
+			}
+			if err != nil {
+				t.Fatalf("cannot decompress archive: %v", err)
+			}
+
+			files := make(map[string]string)
+			if !test.Split {
+				c, err := ioutil.ReadAll(rd)
+				if err != nil {
+					t.Fatalf("cannot decompress archive: %v", err)
+				}
+				files[""] = string(c)
+			} else {
+				tr := tar.NewReader(rd)
+				for {
+					hdr, err := tr.Next()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("cannot read tar archive: %v", err)
+					}
+					c, err := ioutil.ReadAll(tr)
+					if err != nil {
+						t.Fatalf("cannot read tar archive: %v", err)
+					}
+					files[hdr.Name] = string(c)
+				}
+			}
+
+			if !reflect.DeepEqual(files, test.Files) {
+				t.Errorf("unexpected archive content: %q, expected %q", files, test.Files)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		Input  string
+		Format logarchive.Format
+		Error  bool
+	}{
+		{"", logarchive.FormatGzip, false},
+		{"gz", logarchive.FormatGzip, false},
+		{"ZSTD", logarchive.FormatZstd, false},
+		{"bzip2", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			f, err := logarchive.ParseFormat(test.Input)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f != test.Format {
+				t.Errorf("unexpected format: %s, expected %s", f, test.Format)
+			}
+		})
+	}
+}
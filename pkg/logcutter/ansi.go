@@ -0,0 +1,57 @@
+package logcutter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiEscape matches ANSI CSI sequences (e.g. colors: \x1b[32m) and OSC sequences (e.g. window titles or hyperlinks)
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// StripANSI removes all ANSI escape sequences from a string
+func StripANSI(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// splitMarker attempts to split a line into its slice marker (i.e. the content between the leading square brackets)
+// and the remaining payload. ANSI escape sequences are ignored when looking for the marker: the marker is returned
+// without them, while those preceding the marker are retained in the payload so that colors remain intact.
+func splitMarker(line string) (marker, payload string, ok bool) {
+	var (
+		escapes = ansiEscape.FindAllStringIndex(line, -1)
+		lead    int
+	)
+	for _, e := range escapes {
+		if e[0] != lead {
+			break
+		}
+		lead = e[1]
+	}
+	if !strings.HasPrefix(line[lead:], "[") {
+		return "", "", false
+	}
+
+	// find the closing bracket outside of any escape sequence
+	end := -1
+	for i := lead + 1; i < len(line) && end < 0; i++ {
+		for _, e := range escapes {
+			if i >= e[0] && i < e[1] {
+				i = e[1]
+				break
+			}
+		}
+		if i < len(line) && line[i] == ']' {
+			end = i
+		}
+	}
+	if end < 0 {
+		return "", "", false
+	}
+
+	marker = StripANSI(line[lead+1 : end])
+	payload = line[:lead] + strings.TrimPrefix(line[end+1:], " ")
+	return marker, payload, true
+}
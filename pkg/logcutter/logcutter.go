@@ -2,10 +2,17 @@ package logcutter
 
 import (
 	"bufio"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/xerrors"
 )
 
 // Cutter splits a log stream into slices for more structured display
@@ -14,6 +21,12 @@ type Cutter interface {
 	// on the events channel. Once the reader returns EOF the events and errchan are closed.
 	// If anything goes wrong while reading a single error is written to errchan, but nothing is closed.
 	Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error)
+
+	// Resume works like Slice, except that it continues slicing from a previously produced cursor.
+	// The in reader is expected to be positioned at the beginning of the log. Resume replays everything
+	// before the cursor to restore the slices open at that point, but produces events only for what follows it.
+	// If the log ends before the cursor, ErrCursorOutOfRange is written to errchan.
+	Resume(in io.Reader, from Cursor) (events <-chan *v1.LogSliceEvent, errchan <-chan error)
 }
 
 const (
@@ -21,29 +34,125 @@ const (
 	DefaultSlice = "default"
 )
 
+// ErrCursorOutOfRange is produced when resuming from a cursor which points beyond the end of the log
+var ErrCursorOutOfRange = xerrors.New("cursor points beyond the end of the log")
+
+// HasMarker returns true if the line starts with a slice marker, e.g. [build] or [build|DONE]
+func HasMarker(line string) bool {
+	_, _, ok := splitMarker(strings.TrimSpace(line))
+	return ok
+}
+
+// Cursor marks a position in a log stream from which slicing can resume
+type Cursor struct {
+	// Offset is the number of bytes read from the log stream
+	Offset int64
+	// Phase is the phase slice that was active at this point
+	Phase string
+}
+
+// String produces the string representation of this cursor which is used as LogSliceEvent.Cursor
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d:%s", c.Offset, c.Phase)
+}
+
+// ParseCursor parses a cursor previously produced by Cursor.String().
+// An empty string is a valid cursor pointing to the beginning of the log.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+
+	segs := strings.SplitN(s, ":", 2)
+	if len(segs) != 2 {
+		return Cursor{}, xerrors.Errorf("invalid cursor: %s", s)
+	}
+	offset, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil || offset < 0 {
+		return Cursor{}, xerrors.Errorf("invalid cursor offset: %s", segs[0])
+	}
+
+	return Cursor{Offset: offset, Phase: segs[1]}, nil
+}
+
+// splitTimestamp splits the timestamp prefix (see store.NewTimestampedLogs) off a log line.
+// If the line has no such prefix, ts is nil and rest is the line itself. Lines which merely start with a timestamp,
+// e.g. because the job printed one, keep it: only timestamps which start with store.TimestampMarker are ours.
+func splitTimestamp(line string) (ts *tspb.Timestamp, rest string) {
+	if !strings.HasPrefix(line, store.TimestampMarker) {
+		return nil, line
+	}
+	prefixed := strings.TrimPrefix(line, store.TimestampMarker)
+	idx := strings.IndexRune(prefixed, ' ')
+	if idx < 0 {
+		return nil, line
+	}
+
+	t, err := time.Parse(store.TimestampFormat, prefixed[:idx])
+	if err != nil {
+		return nil, line
+	}
+	ts, err = ptypes.TimestampProto(t)
+	if err != nil {
+		return nil, line
+	}
+	return ts, prefixed[idx+1:]
+}
+
+// newLineScanner produces a line scanner which keeps track of the number of bytes consumed
+// so far in offset. After each call to Scan, offset points to the end of the current line.
+func newLineScanner(in io.Reader, offset *int64) *bufio.Scanner {
+	scanner := bufio.NewScanner(in)
+	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		advance, token, err = bufio.ScanLines(data, atEOF)
+		*offset += int64(advance)
+		return
+	})
+	return scanner
+}
+
 // NoCutter does not slice the content up at all
 var NoCutter Cutter = noCutter{}
 
 type noCutter struct{}
 
 // Slice returns all log lines
-func (noCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
+func (c noCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
+	return c.Resume(in, Cursor{})
+}
+
+// Resume returns all log lines starting at the cursor
+func (noCutter) Resume(in io.Reader, from Cursor) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
 	evts := make(chan *v1.LogSliceEvent)
 	errc := make(chan error)
 	events, errchan = evts, errc
 
-	scanner := bufio.NewScanner(in)
+	var offset int64
+	scanner := newLineScanner(in, &offset)
 	go func() {
 		for scanner.Scan() {
-			line := scanner.Text()
-			evts <- &v1.LogSliceEvent{
+			if offset <= from.Offset {
+				// the client has seen this line already
+				continue
+			}
+
+			ts, line := splitTimestamp(scanner.Text())
+			evt := &v1.LogSliceEvent{
 				Name:    DefaultSlice,
 				Type:    v1.LogSliceType_SLICE_CONTENT,
 				Payload: line + "\n",
+				Cursor:  Cursor{Offset: offset}.String(),
+				Time:    ts,
+			}
+			if msg, level, fields, ok := parseStructured(line); ok {
+				evt.Payload, evt.Level, evt.Fields = msg+"\n", level, fields
 			}
+			evts <- evt
 		}
 		if err := scanner.Err(); err != nil {
 			errc <- err
+		} else if offset < from.Offset {
+			errc <- ErrCursorOutOfRange
 		}
 		close(evts)
 		close(errc)
@@ -58,17 +167,37 @@ var DefaultCutter Cutter = defaultCutter{}
 type defaultCutter struct{}
 
 // Slice cuts a log stream into pieces based on a configurable delimiter
-func (defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
+func (c defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
+	return c.Resume(in, Cursor{})
+}
+
+// Resume cuts a log stream into pieces starting at the cursor
+func (defaultCutter) Resume(in io.Reader, from Cursor) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
 	evts := make(chan *v1.LogSliceEvent)
 	errc := make(chan error)
 	events, errchan = evts, errc
 
-	scanner := bufio.NewScanner(in)
+	var offset int64
+	scanner := newLineScanner(in, &offset)
 	phase := DefaultSlice
 	go func() {
+		// lines which end before the cursor are replayed only to restore the open slices and the phase,
+		// the client has seen their events already.
+		emit := func(evt *v1.LogSliceEvent) {
+			if offset > from.Offset {
+				evts <- evt
+			}
+		}
+
 		idx := make(map[string]struct{})
+		var start int64
 		for scanner.Scan() {
-			line := scanner.Text()
+			// events which precede the last event of a line point to the beginning of the line,
+			// so that resuming from them does not skip the remainder of that line.
+			lineStart := Cursor{Offset: start, Phase: phase}.String()
+			start = offset
+
+			ts, line := splitTimestamp(scanner.Text())
 			sl := strings.TrimSpace(line)
 
 			var (
@@ -77,14 +206,12 @@ func (defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errch
 				payload string
 			)
 
-			if !(strings.HasPrefix(sl, "[") && strings.Contains(sl, "]")) {
+			if marker, rest, ok := splitMarker(sl); !ok {
 				name = phase
 				payload = line
 			} else {
-				start := strings.IndexRune(sl, '[')
-				end := strings.IndexRune(sl, ']')
-				name = sl[start+1 : end]
-				payload = strings.TrimPrefix(sl[end+1:], " ")
+				name = marker
+				payload = rest
 
 				if segs := strings.Split(name, "|"); len(segs) == 2 {
 					name = segs[0]
@@ -95,58 +222,89 @@ func (defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errch
 			switch verb {
 			case "DONE":
 				delete(idx, name)
-				evts <- &v1.LogSliceEvent{
-					Name: name,
-					Type: v1.LogSliceType_SLICE_DONE,
-				}
+				emit(&v1.LogSliceEvent{
+					Name:   name,
+					Type:   v1.LogSliceType_SLICE_DONE,
+					Cursor: Cursor{Offset: offset, Phase: phase}.String(),
+					Time:   ts,
+				})
 				continue
 			case "FAIL":
 				delete(idx, name)
-				evts <- &v1.LogSliceEvent{
+				emit(&v1.LogSliceEvent{
 					Name:    name,
 					Payload: payload,
 					Type:    v1.LogSliceType_SLICE_FAIL,
-				}
+					Cursor:  Cursor{Offset: offset, Phase: phase}.String(),
+					Time:    ts,
+				})
 				continue
 			case "RESULT":
-				evts <- &v1.LogSliceEvent{
+				emit(&v1.LogSliceEvent{
 					Name:    name,
 					Type:    v1.LogSliceType_SLICE_RESULT,
 					Payload: payload,
-				}
+					Cursor:  Cursor{Offset: offset, Phase: phase}.String(),
+					Time:    ts,
+				})
+				continue
+			case "PROGRESS":
+				emit(&v1.LogSliceEvent{
+					Name:    name,
+					Type:    v1.LogSliceType_SLICE_PROGRESS,
+					Payload: payload,
+					Cursor:  Cursor{Offset: offset, Phase: phase}.String(),
+					Time:    ts,
+				})
 				continue
 			case "PHASE":
-				evts <- &v1.LogSliceEvent{
+				phase = name
+				emit(&v1.LogSliceEvent{
 					Name:    name,
 					Type:    v1.LogSliceType_SLICE_PHASE,
 					Payload: payload,
-				}
-				phase = name
+					Cursor:  Cursor{Offset: offset, Phase: phase}.String(),
+					Time:    ts,
+				})
 				continue
 			}
 
 			_, exists := idx[name]
 			if !exists {
 				idx[name] = struct{}{}
-				evts <- &v1.LogSliceEvent{
-					Name: name,
-					Type: v1.LogSliceType_SLICE_START,
-				}
+				emit(&v1.LogSliceEvent{
+					Name:   name,
+					Type:   v1.LogSliceType_SLICE_START,
+					Cursor: lineStart,
+					Time:   ts,
+				})
 			}
-			evts <- &v1.LogSliceEvent{
+			evt := &v1.LogSliceEvent{
 				Name:    name,
 				Type:    v1.LogSliceType_SLICE_CONTENT,
 				Payload: string([]byte(payload)),
+				Cursor:  Cursor{Offset: offset, Phase: phase}.String(),
+				Time:    ts,
+			}
+			if msg, level, fields, ok := parseStructured(payload); ok {
+				evt.Payload, evt.Level, evt.Fields = msg, level, fields
 			}
+			emit(evt)
 		}
 		if err := scanner.Err(); err != nil {
 			errc <- err
+		} else if offset < from.Offset {
+			errc <- ErrCursorOutOfRange
+			close(evts)
+			close(errc)
+			return
 		}
 
 		for name := range idx {
 			evts <- &v1.LogSliceEvent{
-				Name: name,
-				Type: v1.LogSliceType_SLICE_ABANDONED,
+				Name:   name,
+				Type:   v1.LogSliceType_SLICE_ABANDONED,
+				Cursor: Cursor{Offset: offset, Phase: phase}.String(),
 			}
 		}
 
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/logcutter"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
 )
 
 func TestDefaultCutterSlice(t *testing.T) {
@@ -26,14 +28,14 @@ func TestDefaultCutterSlice(t *testing.T) {
 [otherproc] Cool beans
 			`,
 			[]v1.LogSliceEvent{
-				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_START},
-				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Hello World this is a test"},
-				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_START},
-				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Some other process"},
-				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "More output"},
-				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_DONE},
-				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Cool beans"},
-				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_ABANDONED},
+				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_START, Cursor: "0:default"},
+				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Hello World this is a test", Cursor: "36:default"},
+				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_START, Cursor: "36:default"},
+				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Some other process", Cursor: "67:default"},
+				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "More output", Cursor: "88:default"},
+				v1.LogSliceEvent{Name: "foobar", Type: v1.LogSliceType_SLICE_DONE, Cursor: "102:default"},
+				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "Cool beans", Cursor: "124:default"},
+				v1.LogSliceEvent{Name: "otherproc", Type: v1.LogSliceType_SLICE_ABANDONED, Cursor: "124:default"},
 			},
 			nil,
 		},
@@ -43,10 +45,61 @@ func TestDefaultCutterSlice(t *testing.T) {
 [components/foobar:docker] c13a632cd17b: Preparing
 			`,
 			[]v1.LogSliceEvent{
-				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_PHASE, Payload: "Pushing foobar"},
-				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_START},
-				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "c13a632cd17b: Preparing"},
-				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_ABANDONED},
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_PHASE, Payload: "Pushing foobar", Cursor: "29:build"},
+				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_START, Cursor: "29:build"},
+				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "c13a632cd17b: Preparing", Cursor: "79:build"},
+				v1.LogSliceEvent{Name: "components/foobar:docker", Type: v1.LogSliceType_SLICE_ABANDONED, Cursor: "79:build"},
+			},
+			nil,
+		},
+		{
+			"\x1b[32m[build|PHASE] Building\x1b[0m\n[\x1b[1mfoo\x1b[0m] \x1b[31mred\x1b[0m output\n\x1b]0;title\x07[foo|DONE]",
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_PHASE, Payload: "\x1b[32mBuilding\x1b[0m", Cursor: "32:build"},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_START, Cursor: "32:build"},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "\x1b[31mred\x1b[0m output", Cursor: "66:build"},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_DONE, Cursor: "86:build"},
+			},
+			nil,
+		},
+		{
+			"\x1e2020-01-02T15:04:05.5Z [foo] timestamped\n[foo] not timestamped",
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_START, Cursor: "0:default", Time: &tspb.Timestamp{Seconds: 1577977445, Nanos: 500000000}},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "timestamped", Cursor: "42:default", Time: &tspb.Timestamp{Seconds: 1577977445, Nanos: 500000000}},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "not timestamped", Cursor: "63:default"},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_ABANDONED, Cursor: "63:default"},
+			},
+			nil,
+		},
+		{
+			// only timestamps with the marker are ours - those the job printed itself are part of the line
+			"\x1e2020-01-02T15:04:05.5Z [foo] 2020-01-03T00:00:00Z own timestamp\n[foo|DONE]\n2020-01-02T15:04:05.5Z no marker",
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_START, Cursor: "0:default", Time: &tspb.Timestamp{Seconds: 1577977445, Nanos: 500000000}},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "2020-01-03T00:00:00Z own timestamp", Cursor: "65:default", Time: &tspb.Timestamp{Seconds: 1577977445, Nanos: 500000000}},
+				v1.LogSliceEvent{Name: "foo", Type: v1.LogSliceType_SLICE_DONE, Cursor: "76:default"},
+				v1.LogSliceEvent{Name: "default", Type: v1.LogSliceType_SLICE_START, Cursor: "76:default"},
+				v1.LogSliceEvent{Name: "default", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "2020-01-02T15:04:05.5Z no marker", Cursor: "108:default"},
+				v1.LogSliceEvent{Name: "default", Type: v1.LogSliceType_SLICE_ABANDONED, Cursor: "108:default"},
+			},
+			nil,
+		},
+		{
+			`[build] {"level":"warning","msg":"disk low","free":12,"ts":"x"}
+[build] {not json}`,
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_START, Cursor: "0:default"},
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "disk low", Cursor: "64:default", Level: "warn", Fields: map[string]string{"free": "12"}},
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "{not json}", Cursor: "82:default"},
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_ABANDONED, Cursor: "82:default"},
+			},
+			nil,
+		},
+		{
+			"[build|PROGRESS] 3/4 pushing",
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_PROGRESS, Payload: "3/4 pushing", Cursor: "28:default"},
 			},
 			nil,
 		},
@@ -80,14 +133,228 @@ func TestDefaultCutterSlice(t *testing.T) {
 		if !reflect.DeepEqual(test.Events, events) {
 			expevt := make([]string, len(test.Events))
 			for i, evt := range test.Events {
-				expevt[i] = fmt.Sprintf("\t[%s] %s: %s", evt.Name, evt.Type.String(), evt.Payload)
+				expevt[i] = fmt.Sprintf("\t[%s] %s: %s (%s)", evt.Name, evt.Type.String(), evt.Payload, evt.Cursor)
 			}
 			actevt := make([]string, len(events))
 			for i, evt := range events {
-				actevt[i] = fmt.Sprintf("\t[%s] %s: %s", evt.Name, evt.Type.String(), evt.Payload)
+				actevt[i] = fmt.Sprintf("\t[%s] %s: %s (%s)", evt.Name, evt.Type.String(), evt.Payload, evt.Cursor)
 			}
 
 			t.Errorf("unexpected events:\n%s\nexpected:\n%s", strings.Join(actevt, "\n"), strings.Join(expevt, "\n"))
 		}
 	}
 }
+
+func TestDefaultCutterResume(t *testing.T) {
+	content := "[build|PHASE] Building\n[foo] first\nunmarked line\n[foo] second\n[bar] one\n[bar|DONE]\n"
+
+	// cursorAfter returns the cursor of the first event the slicer produces for which match returns true
+	cursorAfter := func(match func(evt *v1.LogSliceEvent) bool) logcutter.Cursor {
+		evtchan, _ := logcutter.DefaultCutter.Slice(bytes.NewReader([]byte(content)))
+		var cursor string
+		for evt := range evtchan {
+			if cursor == "" && match(evt) {
+				cursor = evt.Cursor
+			}
+		}
+		c, err := logcutter.ParseCursor(cursor)
+		if err != nil || cursor == "" {
+			t.Fatalf("cannot find cursor %q: %v", cursor, err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		Name   string
+		Cursor logcutter.Cursor
+		Phase  string
+		Events []string
+		// Abandoned lists the slices which are left open at the end of the log, in order
+		Abandoned []string
+		Error     error
+	}{
+		{
+			Name:   "beginning",
+			Cursor: logcutter.Cursor{},
+			Events: []string{
+				"PHASE build Building", "START foo", "CONTENT foo first", "START build", "CONTENT build unmarked line",
+				"CONTENT foo second", "START bar", "CONTENT bar one", "DONE bar",
+			},
+			Abandoned: []string{"build", "foo"},
+		},
+		{
+			Name: "open slice",
+			Cursor: cursorAfter(func(evt *v1.LogSliceEvent) bool {
+				return evt.Type == v1.LogSliceType_SLICE_CONTENT && evt.Payload == "first"
+			}),
+			Phase: "build",
+			Events: []string{
+				"START build", "CONTENT build unmarked line", "CONTENT foo second", "START bar", "CONTENT bar one", "DONE bar",
+			},
+			Abandoned: []string{"build", "foo"},
+		},
+		{
+			// a slice start points to the beginning of its line, s.t. resuming from it does not skip the line
+			Name: "slice start",
+			Cursor: cursorAfter(func(evt *v1.LogSliceEvent) bool {
+				return evt.Type == v1.LogSliceType_SLICE_START && evt.Name == "bar"
+			}),
+			Phase:     "build",
+			Events:    []string{"START bar", "CONTENT bar one", "DONE bar"},
+			Abandoned: []string{"build", "foo"},
+		},
+		{
+			Name: "end of the log",
+			Cursor: cursorAfter(func(evt *v1.LogSliceEvent) bool {
+				return evt.Type == v1.LogSliceType_SLICE_DONE
+			}),
+			Phase:     "build",
+			Abandoned: []string{"build", "foo"},
+		},
+		{
+			Name:   "beyond the end of the log",
+			Cursor: logcutter.Cursor{Offset: int64(len(content) + 10), Phase: "build"},
+			Phase:  "build",
+			Error:  logcutter.ErrCursorOutOfRange,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Phase != "" && test.Cursor.Phase != test.Phase {
+				t.Errorf("unexpected cursor phase: %s", test.Cursor.Phase)
+			}
+
+			var (
+				events    []string
+				abandoned []string
+				err       error
+			)
+			evtchan, errchan := logcutter.DefaultCutter.Resume(bytes.NewReader([]byte(content)), test.Cursor)
+			for evtchan != nil {
+				select {
+				case evt, ok := <-evtchan:
+					if !ok {
+						evtchan = nil
+						continue
+					}
+					if evt.Type == v1.LogSliceType_SLICE_ABANDONED {
+						abandoned = append(abandoned, evt.Name)
+						continue
+					}
+					events = append(events, strings.TrimSpace(fmt.Sprintf("%s %s %s", strings.TrimPrefix(evt.Type.String(), "SLICE_"), evt.Name, evt.Payload)))
+				case e := <-errchan:
+					if e != nil {
+						err = e
+					}
+				}
+			}
+			sort.Strings(abandoned)
+
+			if err != test.Error {
+				t.Errorf("unexpected error: %v, expected %v", err, test.Error)
+			}
+			if !reflect.DeepEqual(events, test.Events) {
+				t.Errorf("unexpected resumed events:\n%s\nexpected:\n%s", strings.Join(events, "\n"), strings.Join(test.Events, "\n"))
+			}
+			if !reflect.DeepEqual(abandoned, test.Abandoned) {
+				t.Errorf("unexpected abandoned slices: %v, expected %v", abandoned, test.Abandoned)
+			}
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	tests := []struct {
+		Input       string
+		Expectation logcutter.Cursor
+		Error       bool
+	}{
+		{"", logcutter.Cursor{}, false},
+		{"42:build", logcutter.Cursor{Offset: 42, Phase: "build"}, false},
+		{"42:components/foo:docker", logcutter.Cursor{Offset: 42, Phase: "components/foo:docker"}, false},
+		{"42", logcutter.Cursor{}, true},
+		{"-1:build", logcutter.Cursor{}, true},
+		{"abc:build", logcutter.Cursor{}, true},
+	}
+
+	for idx, test := range tests {
+		act, err := logcutter.ParseCursor(test.Input)
+		if (err != nil) != test.Error {
+			t.Errorf("test %d: unexpected error: %v", idx, err)
+			continue
+		}
+		if act != test.Expectation {
+			t.Errorf("test %d: expected %v, actual %v", idx, test.Expectation, act)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		Input       string
+		Expectation string
+	}{
+		{"no escapes here", "no escapes here"},
+		{"\x1b[32mgreen\x1b[0m", "green"},
+		{"\x1b[1;31mbold red\x1b[0m and [brackets]", "bold red and [brackets]"},
+		{"\x1b]0;window title\x07content", "content"},
+		{"\x1b]8;;https://werft.sh\x1b\\link\x1b]8;;\x1b\\", "link"},
+	}
+
+	for idx, test := range tests {
+		act := logcutter.StripANSI(test.Input)
+		if act != test.Expectation {
+			t.Errorf("test %d: expected %q, actual %q", idx, test.Expectation, act)
+		}
+	}
+}
+
+func TestLevelAtLeast(t *testing.T) {
+	tests := []struct {
+		Level       string
+		Min         string
+		Expectation bool
+	}{
+		{"error", "warn", true},
+		{"WARNING", "warn", true},
+		{"info", "error", false},
+		{"", "debug", false},
+		{"foobar", "debug", false},
+		{"panic", "error", true},
+	}
+
+	for idx, test := range tests {
+		act := logcutter.LevelAtLeast(test.Level, test.Min)
+		if act != test.Expectation {
+			t.Errorf("test %d: expected %v, actual %v", idx, test.Expectation, act)
+		}
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		Payload     string
+		Expectation *v1.JobProgress
+		Error       bool
+	}{
+		{"45%", &v1.JobProgress{Slice: "build", Percent: 45}, false},
+		{"12.5% compiling sources", &v1.JobProgress{Slice: "build", Percent: 12, Description: "compiling sources"}, false},
+		{"3/4 pushing", &v1.JobProgress{Slice: "build", Percent: 75, Step: 3, TotalSteps: 4, Description: "pushing"}, false},
+		{"150%", &v1.JobProgress{Slice: "build", Percent: 100}, false},
+		{"", nil, true},
+		{"3/0", nil, true},
+		{"almost there", nil, true},
+	}
+
+	for idx, test := range tests {
+		act, err := logcutter.ParseProgress(&v1.LogSliceEvent{Name: "build", Type: v1.LogSliceType_SLICE_PROGRESS, Payload: test.Payload})
+		if (err != nil) != test.Error {
+			t.Errorf("test %d: unexpected error: %v", idx, err)
+			continue
+		}
+		if !reflect.DeepEqual(act, test.Expectation) {
+			t.Errorf("test %d: expected %v, actual %v", idx, test.Expectation, act)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package logcutter
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// ParseProgress parses the payload of a PROGRESS slice event. The payload starts with either
+// a percentage (e.g. 45%) or a step count (e.g. 3/10), optionally followed by a description.
+func ParseProgress(evt *v1.LogSliceEvent) (*v1.JobProgress, error) {
+	segs := strings.Fields(evt.Payload)
+	if len(segs) == 0 {
+		return nil, xerrors.Errorf("progress is empty")
+	}
+
+	res := &v1.JobProgress{
+		Slice:       evt.Name,
+		Description: strings.Join(segs[1:], " "),
+	}
+	val := segs[0]
+	if strings.HasSuffix(val, "%") {
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid progress percentage %s: %w", val, err)
+		}
+		res.Percent = int32(pct)
+	} else if idx := strings.Index(val, "/"); idx > 0 {
+		step, err := strconv.ParseInt(val[:idx], 10, 32)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid progress step %s: %w", val, err)
+		}
+		total, err := strconv.ParseInt(val[idx+1:], 10, 32)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid progress step %s: %w", val, err)
+		}
+		if total <= 0 {
+			return nil, xerrors.Errorf("invalid progress step %s: total must be positive", val)
+		}
+		res.Step, res.TotalSteps = int32(step), int32(total)
+		res.Percent = int32(step * 100 / total)
+	} else {
+		return nil, xerrors.Errorf("invalid progress %s: must be a percentage or step count", val)
+	}
+
+	if res.Percent < 0 {
+		res.Percent = 0
+	} else if res.Percent > 100 {
+		res.Percent = 100
+	}
+	return res, nil
+}
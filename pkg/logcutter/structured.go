@@ -0,0 +1,119 @@
+package logcutter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Log levels recognised in structured log lines, ordered by severity
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+var levelSeverity = map[string]int{
+	LevelDebug: 1,
+	LevelInfo:  2,
+	LevelWarn:  3,
+	LevelError: 4,
+	LevelFatal: 5,
+}
+
+// levelAliases maps commonly used level names to the ones werft understands
+var levelAliases = map[string]string{
+	"trace":    LevelDebug,
+	"warning":  LevelWarn,
+	"err":      LevelError,
+	"critical": LevelFatal,
+	"panic":    LevelFatal,
+}
+
+// NormalizeLevel maps a log level name to one of the Level* constants.
+// Unknown levels produce an empty string.
+func NormalizeLevel(level string) string {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if alias, ok := levelAliases[level]; ok {
+		return alias
+	}
+	if _, ok := levelSeverity[level]; ok {
+		return level
+	}
+	return ""
+}
+
+// LevelAtLeast returns true if level is at least as severe as min.
+// Unknown levels never satisfy a minimum level.
+func LevelAtLeast(level, min string) bool {
+	l, ok := levelSeverity[NormalizeLevel(level)]
+	if !ok {
+		return false
+	}
+	return l >= levelSeverity[NormalizeLevel(min)]
+}
+
+var (
+	messageKeys = []string{"msg", "message"}
+	levelKeys   = []string{"level", "lvl", "severity"}
+	// timeKeys are dropped from the fields as werft timestamps each line itself
+	timeKeys = []string{"time", "ts", "timestamp"}
+)
+
+// parseStructured attempts to parse a JSON log line, e.g. {"level":"info","msg":"hello","foo":"bar"}.
+// A line only counts as structured if it is a JSON object containing a message.
+func parseStructured(line string) (msg, level string, fields map[string]string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+		return
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		return
+	}
+
+	msg, ok = popString(obj, messageKeys)
+	if !ok {
+		return
+	}
+	level, _ = popString(obj, levelKeys)
+	level = NormalizeLevel(level)
+	for _, k := range timeKeys {
+		delete(obj, k)
+	}
+
+	if len(obj) > 0 {
+		fields = make(map[string]string, len(obj))
+		for k, v := range obj {
+			switch v := v.(type) {
+			case string:
+				fields[k] = v
+			case map[string]interface{}, []interface{}:
+				b, _ := json.Marshal(v)
+				fields[k] = string(b)
+			default:
+				fields[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	return msg, level, fields, true
+}
+
+func popString(obj map[string]interface{}, keys []string) (string, bool) {
+	for _, k := range keys {
+		v, ok := obj[k]
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		delete(obj, k)
+		return s, true
+	}
+	return "", false
+}
@@ -0,0 +1,353 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// annotationJobResource is set on jobs started for a WerftJob resource and contains its namespace/name
+	annotationJobResource = "werftJob"
+
+	// defaultJobOwner is the owner of jobs whose resource does not name one
+	defaultJobOwner = "kubernetes"
+)
+
+// JobResource is the WerftJob custom resource
+var JobResource = schema.GroupVersionResource{Group: "werft.sh", Version: "v1alpha1", Resource: "werftjobs"}
+
+// JobSpec is the spec of a WerftJob resource
+type JobSpec struct {
+	// Repository is the repository to run the job on in the form of owner/repo
+	Repository string `json:"repository"`
+	// Ref is the ref to run the job on, e.g. refs/heads/master. Either ref or revision must be set.
+	Ref string `json:"ref,omitempty"`
+	// Revision is the commit to run the job on
+	Revision string `json:"revision,omitempty"`
+	// JobPath is the path of the job spec in the repository. Defaults to the job the repository's werft config selects.
+	JobPath string `json:"jobPath,omitempty"`
+	// Trigger is the trigger of the job, e.g. manual or push. Defaults to manual.
+	Trigger string `json:"trigger,omitempty"`
+	// Owner is the owner of the job. Defaults to kubernetes.
+	Owner string `json:"owner,omitempty"`
+	// Annotations are added to the job
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// JobStatus is the status of a WerftJob resource
+type JobStatus struct {
+	// Name is the name of the werft job started for the resource
+	Name string `json:"name,omitempty"`
+	// Phase is the phase of the job, e.g. running or done
+	Phase string `json:"phase,omitempty"`
+	// Success is true if the job finished successfully
+	Success bool `json:"success,omitempty"`
+	// Details explains the status, e.g. why the job failed
+	Details string `json:"details,omitempty"`
+	// URL is where the job can be seen in the werft UI
+	URL string `json:"url,omitempty"`
+	// Error is set if the job could not be started
+	Error string `json:"error,omitempty"`
+}
+
+// Request produces the request to start the job a resource declares
+func (spec *JobSpec) Request(resource string) (*v1.StartGitHubJobRequest, error) {
+	segs := strings.Split(spec.Repository, "/")
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		return nil, xerrors.Errorf("repository \"%s\" is not in the form of owner/repo", spec.Repository)
+	}
+	if spec.Ref == "" && spec.Revision == "" {
+		return nil, xerrors.Errorf("either ref or revision is required")
+	}
+
+	trigger := v1.JobTrigger_TRIGGER_MANUAL
+	if spec.Trigger != "" {
+		t, ok := v1.JobTrigger_value["TRIGGER_"+strings.ToUpper(spec.Trigger)]
+		if !ok {
+			return nil, xerrors.Errorf("unknown trigger \"%s\"", spec.Trigger)
+		}
+		trigger = v1.JobTrigger(t)
+	}
+	owner := spec.Owner
+	if owner == "" {
+		owner = defaultJobOwner
+	}
+
+	annotations := []*v1.Annotation{{Key: annotationJobResource, Value: resource}}
+	keys := make([]string, 0, len(spec.Annotations))
+	for k := range spec.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		annotations = append(annotations, &v1.Annotation{Key: k, Value: spec.Annotations[k]})
+	}
+
+	return &v1.StartGitHubJobRequest{
+		Metadata: &v1.JobMetadata{
+			Owner: owner,
+			Repository: &v1.Repository{
+				Host:     "github.com",
+				Owner:    segs[0],
+				Repo:     segs[1],
+				Ref:      spec.Ref,
+				Revision: spec.Revision,
+			},
+			Trigger:     trigger,
+			Annotations: annotations,
+		},
+		JobPath: spec.JobPath,
+	}, nil
+}
+
+// NewJobStatus produces the status of a WerftJob resource from the status of its job
+func NewJobStatus(job *v1.JobStatus, baseURL string) JobStatus {
+	res := JobStatus{
+		Name:    job.Name,
+		Phase:   strings.ToLower(strings.TrimPrefix(job.Phase.String(), "PHASE_")),
+		Details: job.Details,
+	}
+	if job.Conditions != nil {
+		res.Success = job.Conditions.Success
+	}
+	if baseURL != "" {
+		res.URL = fmt.Sprintf("%s/job/%s", strings.TrimSuffix(baseURL, "/"), job.Name)
+	}
+	return res
+}
+
+// JobService is the part of werft the job controller uses
+type JobService interface {
+	StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRequest) (*v1.StartJobResponse, error)
+	GetJob(ctx context.Context, req *v1.GetJobRequest) (*v1.GetJobResponse, error)
+	StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.StopJobResponse, error)
+	JobUpdates(ctx context.Context) <-chan *v1.JobStatus
+}
+
+// JobController starts jobs for WerftJob resources and reflects the status of those jobs in the resources
+type JobController struct {
+	Client  dynamic.Interface
+	Werft   JobService
+	Config  Config
+	BaseURL string
+
+	mu      sync.Mutex
+	pending map[string]*v1.JobStatus
+	updated chan struct{}
+}
+
+// Run reconciles WerftJob resources until the context is canceled
+func (c *JobController) Run(ctx context.Context) {
+	c.pending = make(map[string]*v1.JobStatus)
+	c.updated = make(chan struct{}, 1)
+
+	go c.collectUpdates(ctx)
+	go c.applyUpdates(ctx)
+	run(ctx, c.resources(), c.Config.resyncInterval(), c)
+}
+
+func (c *JobController) resources() dynamic.ResourceInterface {
+	return c.Client.Resource(JobResource).Namespace(c.Config.Namespace)
+}
+
+// collectUpdates remembers the latest status of all jobs started for a resource. We must not block werft while we
+// update the resources, hence we apply the updates separately.
+func (c *JobController) collectUpdates(ctx context.Context) {
+	for job := range c.Werft.JobUpdates(ctx) {
+		if job.Metadata == nil {
+			continue
+		}
+		var resource string
+		for _, a := range job.Metadata.Annotations {
+			if a.Key == annotationJobResource {
+				resource = a.Value
+				break
+			}
+		}
+		if resource == "" {
+			continue
+		}
+
+		c.mu.Lock()
+		c.pending[resource] = job
+		c.mu.Unlock()
+		select {
+		case c.updated <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// applyUpdates updates the status of resources whose job changed
+func (c *JobController) applyUpdates(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.updated:
+		}
+
+		c.mu.Lock()
+		pending := c.pending
+		c.pending = make(map[string]*v1.JobStatus)
+		c.mu.Unlock()
+
+		for resource, job := range pending {
+			segs := strings.Split(resource, "/")
+			if len(segs) != 2 || segs[0] != c.Config.Namespace {
+				continue
+			}
+			obj, err := c.resources().Get(segs[1], metav1.GetOptions{})
+			if err != nil {
+				log.WithError(err).WithField("name", resource).Debug("cannot update werft job status")
+				continue
+			}
+			err = c.updateStatus(obj, NewJobStatus(job, c.BaseURL))
+			if err != nil {
+				log.WithError(err).WithField("name", resource).Warn("cannot update werft job status")
+			}
+		}
+	}
+}
+
+// resync starts the jobs of new resources and updates the status of all unfinished ones
+func (c *JobController) resync(ctx context.Context) error {
+	list, err := c.resources().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		obj := &list.Items[i]
+		err := c.reconcile(ctx, obj)
+		if err != nil {
+			log.WithError(err).WithField("name", obj.GetName()).Warn("cannot reconcile werft job")
+		}
+	}
+	return nil
+}
+
+// handle starts the job of a new resource or stops the job of a deleted one
+func (c *JobController) handle(ctx context.Context, evt watch.EventType, obj *unstructured.Unstructured) error {
+	switch evt {
+	case watch.Added:
+		return c.reconcile(ctx, obj)
+	case watch.Deleted:
+		status, err := jobResourceStatus(obj)
+		if err != nil {
+			return err
+		}
+		if status.Name == "" || status.Phase == "done" || status.Phase == "cleanup" {
+			return nil
+		}
+		_, err = c.Werft.StopJob(ctx, &v1.StopJobRequest{Name: status.Name})
+		if err != nil {
+			return err
+		}
+		log.WithField("name", status.Name).WithField("resource", obj.GetName()).Info("stopped job because its werft job resource was deleted")
+	}
+	return nil
+}
+
+// reconcile starts the job of a resource if it has none yet, or updates the status of the resource if its job is still running
+func (c *JobController) reconcile(ctx context.Context, obj *unstructured.Unstructured) error {
+	current, err := jobResourceStatus(obj)
+	if err != nil {
+		return err
+	}
+	if current.Error != "" || current.Phase == "done" || current.Phase == "cleanup" {
+		return nil
+	}
+
+	if current.Name != "" {
+		// the job was started before - we might have missed an update though
+		resp, err := c.Werft.GetJob(ctx, &v1.GetJobRequest{Name: current.Name})
+		if err != nil {
+			return err
+		}
+		return c.updateStatus(obj, NewJobStatus(resp.Result, c.BaseURL))
+	}
+
+	resource := obj.GetNamespace() + "/" + obj.GetName()
+	req, err := jobRequest(obj, resource)
+	if err == nil {
+		// we use the UID of the resource as idempotency key so that we start its job only once, even if we see it twice
+		req.IdempotencyKey = "crd/" + string(obj.GetUID())
+
+		var resp *v1.StartJobResponse
+		resp, err = c.Werft.StartGitHubJob(ctx, req)
+		if err == nil {
+			log.WithField("name", resp.Status.Name).WithField("resource", resource).Info("started job for werft job resource")
+			return c.updateStatus(obj, NewJobStatus(resp.Status, c.BaseURL))
+		}
+		switch status.Code(err) {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+			// werft cannot start the job right now, e.g. because of maintenance - we'll try again during the next resync
+			return err
+		}
+	}
+	return c.updateStatus(obj, JobStatus{Error: err.Error()})
+}
+
+// updateStatus sets the status of a resource if it changed
+func (c *JobController) updateStatus(obj *unstructured.Unstructured, status JobStatus) error {
+	current, err := jobResourceStatus(obj)
+	if err != nil {
+		return err
+	}
+	if *current == status {
+		return nil
+	}
+
+	rawstatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	obj = obj.DeepCopy()
+	obj.Object["status"] = rawstatus
+	_, err = c.resources().UpdateStatus(obj, metav1.UpdateOptions{})
+	return err
+}
+
+func jobRequest(obj *unstructured.Unstructured, resource string) (*v1.StartGitHubJobRequest, error) {
+	rawspec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return nil, xerrors.Errorf("resource has no spec")
+	}
+
+	var spec JobSpec
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawspec, &spec)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid spec: %w", err)
+	}
+	return spec.Request(resource)
+}
+
+func jobResourceStatus(obj *unstructured.Unstructured) (*JobStatus, error) {
+	var status JobStatus
+	rawstatus, ok := obj.Object["status"].(map[string]interface{})
+	if !ok {
+		return &status, nil
+	}
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawstatus, &status)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid status: %w", err)
+	}
+	return &status, nil
+}
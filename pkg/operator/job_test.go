@@ -0,0 +1,91 @@
+package operator_test
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/operator"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestJobSpecRequest(t *testing.T) {
+	tests := []struct {
+		Spec        operator.JobSpec
+		Expectation *v1.StartGitHubJobRequest
+		Error       string
+	}{
+		{
+			operator.JobSpec{Repository: "foo/bar", Ref: "refs/heads/master"},
+			&v1.StartGitHubJobRequest{
+				Metadata: &v1.JobMetadata{
+					Owner:       "kubernetes",
+					Repository:  &v1.Repository{Host: "github.com", Owner: "foo", Repo: "bar", Ref: "refs/heads/master"},
+					Trigger:     v1.JobTrigger_TRIGGER_MANUAL,
+					Annotations: []*v1.Annotation{{Key: "werftJob", Value: "default/build"}},
+				},
+			},
+			"",
+		},
+		{
+			operator.JobSpec{
+				Repository:  "foo/bar",
+				Revision:    "abc",
+				JobPath:     ".werft/deploy.yaml",
+				Trigger:     "push",
+				Owner:       "argo",
+				Annotations: map[string]string{"version": "1.0", "env": "staging"},
+			},
+			&v1.StartGitHubJobRequest{
+				Metadata: &v1.JobMetadata{
+					Owner:      "argo",
+					Repository: &v1.Repository{Host: "github.com", Owner: "foo", Repo: "bar", Revision: "abc"},
+					Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+					Annotations: []*v1.Annotation{
+						{Key: "werftJob", Value: "default/build"},
+						{Key: "env", Value: "staging"},
+						{Key: "version", Value: "1.0"},
+					},
+				},
+				JobPath: ".werft/deploy.yaml",
+			},
+			"",
+		},
+		{operator.JobSpec{Repository: "foo/bar"}, nil, "either ref or revision is required"},
+		{operator.JobSpec{Repository: "bar", Ref: "master"}, nil, "repository \"bar\" is not in the form of owner/repo"},
+		{operator.JobSpec{Repository: "foo/bar", Ref: "master", Trigger: "cron"}, nil, "unknown trigger \"cron\""},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			act, err := test.Spec.Request("default/build")
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if !proto.Equal(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestNewJobStatus(t *testing.T) {
+	act := operator.NewJobStatus(&v1.JobStatus{
+		Name:       "bar-build-master.1",
+		Phase:      v1.JobPhase_PHASE_DONE,
+		Conditions: &v1.JobConditions{Success: true},
+	}, "https://werft.example.com/")
+	exp := operator.JobStatus{
+		Name:    "bar-build-master.1",
+		Phase:   "done",
+		Success: true,
+		URL:     "https://werft.example.com/job/bar-build-master.1",
+	}
+	if act != exp {
+		t.Errorf("expected %v, actual %v", exp, act)
+	}
+}
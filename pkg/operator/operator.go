@@ -0,0 +1,101 @@
+package operator
+
+import (
+	"context"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// sourcePrefix prefixes the source of everything reconciled from a werft resource
+	sourcePrefix = "crd:"
+
+	// defaultResyncInterval is how often we reconcile all resources if the config does not say otherwise
+	defaultResyncInterval = 10 * time.Minute
+)
+
+// Config configures the operator mode, in which werft is configured using Kubernetes custom resources
+type Config struct {
+	// Enabled makes werft reconcile WerftRepository and WerftJob resources. Both custom resource definitions must be installed.
+	Enabled bool `yaml:"enabled"`
+
+	// Namespace is the namespace we watch for werft resources. Defaults to the namespace jobs run in.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// ResyncInterval is how often we reconcile all resources in addition to watching them
+	ResyncInterval *executor.Duration `yaml:"resyncInterval,omitempty"`
+}
+
+func (c Config) resyncInterval() time.Duration {
+	if c.ResyncInterval == nil || c.ResyncInterval.Duration <= 0 {
+		return defaultResyncInterval
+	}
+	return c.ResyncInterval.Duration
+}
+
+// reconciler reconciles custom resources
+type reconciler interface {
+	// resync reconciles all resources
+	resync(ctx context.Context) error
+	// handle reconciles a single resource which changed
+	handle(ctx context.Context, evt watch.EventType, obj *unstructured.Unstructured) error
+}
+
+// run reconciles all resources periodically and watches them for changes in between, until the context is canceled
+func run(ctx context.Context, client dynamic.ResourceInterface, interval time.Duration, r reconciler) {
+	for {
+		err := r.resync(ctx)
+		if err != nil {
+			log.WithError(err).Warn("cannot reconcile werft resources")
+		}
+
+		err = watchResources(ctx, client, interval, r)
+		if err != nil {
+			log.WithError(err).Warn("cannot watch werft resources")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watchResources reconciles resources as they change until the timeout expires or the watch ends
+func watchResources(ctx context.Context, client dynamic.ResourceInterface, timeout time.Duration, r reconciler) error {
+	timeoutSeconds := int64(timeout.Seconds())
+	w, err := client.Watch(metav1.ListOptions{TimeoutSeconds: &timeoutSeconds})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		var evt watch.Event
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			evt = e
+		}
+
+		obj, ok := evt.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		err = r.handle(ctx, evt.Type, obj)
+		if err != nil {
+			log.WithError(err).WithField("name", obj.GetName()).WithField("kind", obj.GetKind()).Warn("cannot reconcile werft resource")
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RepositoryResource is the WerftRepository custom resource
+var RepositoryResource = schema.GroupVersionResource{Group: "werft.sh", Version: "v1alpha1", Resource: "werftrepositories"}
+
+// RepositorySpec is the spec of a WerftRepository resource
+type RepositorySpec struct {
+	// Repository is the repository in the form of owner/repo
+	Repository string `json:"repository"`
+
+	// WebhookSecretRef points to the key of a secret containing the webhook secret of this repository
+	WebhookSecretRef *corev1.SecretKeySelector `json:"webhookSecretRef,omitempty"`
+
+	// Secrets are made available to all jobs of this repository
+	Secrets []SecretBinding `json:"secrets,omitempty"`
+
+	// Policy restricts which jobs may run
+	Policy *RepositoryPolicy `json:"policy,omitempty"`
+
+	// Registries are the container registries jobs of this repository pull images from and push images to
+	Registries []RegistryCredential `json:"registries,omitempty"`
+}
+
+// SecretBinding makes a secret available to jobs
+type SecretBinding struct {
+	Secret    string `json:"secret"`
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// RegistryCredential grants jobs access to a container registry. The password is read from a secret in the
+// namespace of the resource - werft manages the secrets jobs use to access the registry themselves.
+type RegistryCredential struct {
+	Registry          string                    `json:"registry"`
+	Username          string                    `json:"username"`
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef"`
+}
+
+// RepositoryPolicy restricts which jobs may run
+type RepositoryPolicy struct {
+	AllowedRefs     []string `json:"allowedRefs,omitempty"`
+	AllowedTriggers []string `json:"allowedTriggers,omitempty"`
+}
+
+// Settings converts the spec into repository settings
+func (spec *RepositorySpec) Settings(source, webhookSecret string) (*v1.RepositorySettings, error) {
+	segs := strings.Split(spec.Repository, "/")
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		return nil, xerrors.Errorf("repository \"%s\" is not in the form of owner/repo", spec.Repository)
+	}
+
+	res := &v1.RepositorySettings{
+		Owner:         segs[0],
+		Repo:          segs[1],
+		WebhookSecret: webhookSecret,
+		Source:        source,
+	}
+	for _, s := range spec.Secrets {
+		if s.Secret == "" {
+			return nil, xerrors.Errorf("secret binding has no secret")
+		}
+		res.Secrets = append(res.Secrets, &v1.SecretBinding{Secret: s.Secret, MountPath: s.MountPath})
+	}
+	if p := spec.Policy; p != nil {
+		res.Policy = &v1.RepositoryPolicy{AllowedRefs: p.AllowedRefs}
+		for _, t := range p.AllowedTriggers {
+			trigger, ok := v1.JobTrigger_value["TRIGGER_"+strings.ToUpper(t)]
+			if !ok {
+				return nil, xerrors.Errorf("unknown trigger \"%s\"", t)
+			}
+			res.Policy.AllowedTriggers = append(res.Policy.AllowedTriggers, v1.JobTrigger(trigger))
+		}
+	}
+	return res, nil
+}
+
+// RegistryCredentials resolves the registry credentials of the spec. password reads the key of a secret.
+func (spec *RepositorySpec) RegistryCredentials(password func(ref *corev1.SecretKeySelector) (string, error)) ([]*v1.RegistryCredential, error) {
+	var res []*v1.RegistryCredential
+	for _, r := range spec.Registries {
+		if r.Registry == "" {
+			return nil, xerrors.Errorf("registry credential has no registry")
+		}
+		if r.PasswordSecretRef == nil {
+			return nil, xerrors.Errorf("registry credential for %s has no passwordSecretRef", r.Registry)
+		}
+		pwd, err := password(r.PasswordSecretRef)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get password for %s: %w", r.Registry, err)
+		}
+		res = append(res, &v1.RegistryCredential{Registry: r.Registry, Username: r.Username, Password: pwd})
+	}
+	return res, nil
+}
+
+// RepositoryController reconciles WerftRepository resources into the repository settings store
+type RepositoryController struct {
+	Client dynamic.Interface
+	Kube   kubernetes.Interface
+	Store  store.Repositories
+	Config Config
+}
+
+// Run reconciles the repository settings until the context is canceled
+func (c *RepositoryController) Run(ctx context.Context) {
+	run(ctx, c.Client.Resource(RepositoryResource).Namespace(c.Config.Namespace), c.Config.resyncInterval(), c)
+}
+
+// resync reconciles all resources and removes the settings of deleted ones
+func (c *RepositoryController) resync(ctx context.Context) error {
+	list, err := c.Client.Resource(RepositoryResource).Namespace(c.Config.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]struct{}, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		settings, err := c.reconcile(ctx, obj)
+		if err != nil {
+			log.WithError(err).WithField("name", obj.GetName()).Warn("cannot reconcile werft repository")
+			continue
+		}
+		known[settings.Owner+"/"+settings.Repo] = struct{}{}
+	}
+
+	existing, err := c.Store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range existing {
+		if !strings.HasPrefix(s.Source, sourcePrefix) {
+			// we only remove what we reconciled in the first place
+			continue
+		}
+		if _, ok := known[s.Owner+"/"+s.Repo]; ok {
+			continue
+		}
+
+		err = c.Store.Delete(ctx, s.Owner, s.Repo)
+		if err != nil && err != store.ErrNotFound {
+			return err
+		}
+		log.WithField("repo", s.Owner+"/"+s.Repo).Info("removed werft repository")
+	}
+	return nil
+}
+
+// handle reconciles a resource which changed
+func (c *RepositoryController) handle(ctx context.Context, evt watch.EventType, obj *unstructured.Unstructured) (err error) {
+	switch evt {
+	case watch.Added, watch.Modified:
+		_, err = c.reconcile(ctx, obj)
+	case watch.Deleted:
+		err = c.remove(ctx, obj)
+	}
+	return
+}
+
+// reconcile stores the settings a resource declares
+func (c *RepositoryController) reconcile(ctx context.Context, obj *unstructured.Unstructured) (*v1.RepositorySettings, error) {
+	spec, err := repositorySpec(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	secretValue := func(ref *corev1.SecretKeySelector) (string, error) {
+		secret, err := c.Kube.CoreV1().Secrets(obj.GetNamespace()).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		val, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", xerrors.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+		}
+		return string(val), nil
+	}
+
+	var webhookSecret string
+	if ref := spec.WebhookSecretRef; ref != nil {
+		webhookSecret, err = secretValue(ref)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get webhook secret: %w", err)
+		}
+	}
+
+	settings, err := spec.Settings(source(obj), webhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	settings.Registries, err = spec.RegistryCredentials(secretValue)
+	if err != nil {
+		return nil, err
+	}
+	err = c.Store.Set(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("repo", spec.Repository).WithField("source", settings.Source).Debug("reconciled werft repository")
+	return settings, nil
+}
+
+// remove deletes the settings of a deleted resource
+func (c *RepositoryController) remove(ctx context.Context, obj *unstructured.Unstructured) error {
+	spec, err := repositorySpec(obj)
+	if err != nil {
+		return err
+	}
+	segs := strings.Split(spec.Repository, "/")
+	if len(segs) != 2 {
+		return nil
+	}
+
+	existing, err := c.Store.Get(ctx, segs[0], segs[1])
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Source != source(obj) {
+		// another resource declares this repository now
+		return nil
+	}
+
+	err = c.Store.Delete(ctx, segs[0], segs[1])
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	log.WithField("repo", spec.Repository).Info("removed werft repository")
+	return nil
+}
+
+func repositorySpec(obj *unstructured.Unstructured) (*RepositorySpec, error) {
+	rawspec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return nil, xerrors.Errorf("resource has no spec")
+	}
+
+	var spec RepositorySpec
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawspec, &spec)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid spec: %w", err)
+	}
+	return &spec, nil
+}
+
+func source(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s%s/%s", sourcePrefix, obj.GetNamespace(), obj.GetName())
+}
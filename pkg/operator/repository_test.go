@@ -0,0 +1,129 @@
+package operator_test
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/operator"
+	"github.com/golang/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRepositorySpecSettings(t *testing.T) {
+	tests := []struct {
+		Spec        operator.RepositorySpec
+		Expectation *v1.RepositorySettings
+		Error       string
+	}{
+		{
+			operator.RepositorySpec{Repository: "foo/bar"},
+			&v1.RepositorySettings{Owner: "foo", Repo: "bar", WebhookSecret: "secret", Source: "crd:default/bar"},
+			"",
+		},
+		{
+			operator.RepositorySpec{
+				Repository: "foo/bar",
+				Secrets: []operator.SecretBinding{
+					{Secret: "npm"},
+					{Secret: "gcp", MountPath: "/mnt/secrets/gcp"},
+				},
+				Policy: &operator.RepositoryPolicy{
+					AllowedRefs:     []string{"refs/heads/master", "refs/tags/*"},
+					AllowedTriggers: []string{"push", "Manual"},
+				},
+			},
+			&v1.RepositorySettings{
+				Owner:         "foo",
+				Repo:          "bar",
+				WebhookSecret: "secret",
+				Source:        "crd:default/bar",
+				Secrets: []*v1.SecretBinding{
+					{Secret: "npm"},
+					{Secret: "gcp", MountPath: "/mnt/secrets/gcp"},
+				},
+				Policy: &v1.RepositoryPolicy{
+					AllowedRefs:     []string{"refs/heads/master", "refs/tags/*"},
+					AllowedTriggers: []v1.JobTrigger{v1.JobTrigger_TRIGGER_PUSH, v1.JobTrigger_TRIGGER_MANUAL},
+				},
+			},
+			"",
+		},
+		{operator.RepositorySpec{Repository: "foo"}, nil, "repository \"foo\" is not in the form of owner/repo"},
+		{operator.RepositorySpec{Repository: "foo/bar", Secrets: []operator.SecretBinding{{}}}, nil, "secret binding has no secret"},
+		{operator.RepositorySpec{Repository: "foo/bar", Policy: &operator.RepositoryPolicy{AllowedTriggers: []string{"cron"}}}, nil, "unknown trigger \"cron\""},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			act, err := test.Spec.Settings("crd:default/bar", "secret")
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if !proto.Equal(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestRepositorySpecRegistryCredentials(t *testing.T) {
+	password := func(ref *corev1.SecretKeySelector) (string, error) {
+		if ref.Name != "gcr-push" {
+			return "", fmt.Errorf("secret %s not found", ref.Name)
+		}
+		return "pwd-" + ref.Key, nil
+	}
+
+	tests := []struct {
+		Registries  []operator.RegistryCredential
+		Expectation []*v1.RegistryCredential
+		Error       string
+	}{
+		{nil, nil, ""},
+		{
+			[]operator.RegistryCredential{
+				{Registry: "eu.gcr.io", Username: "_json_key", PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "gcr-push"}, Key: "key.json"}},
+			},
+			[]*v1.RegistryCredential{
+				{Registry: "eu.gcr.io", Username: "_json_key", Password: "pwd-key.json"},
+			},
+			"",
+		},
+		{[]operator.RegistryCredential{{Username: "foo"}}, nil, "registry credential has no registry"},
+		{[]operator.RegistryCredential{{Registry: "eu.gcr.io"}}, nil, "registry credential for eu.gcr.io has no passwordSecretRef"},
+		{
+			[]operator.RegistryCredential{
+				{Registry: "docker.io", PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "hub"}, Key: "token"}},
+			},
+			nil,
+			"cannot get password for docker.io: secret hub not found",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			spec := operator.RepositorySpec{Repository: "foo/bar", Registries: test.Registries}
+			act, err := spec.RegistryCredentials(password)
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Fatalf("unexpected error: expected \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+			if len(act) != len(test.Expectation) {
+				t.Fatalf("expected %v, actual %v", test.Expectation, act)
+			}
+			for j := range act {
+				if !proto.Equal(act[j], test.Expectation[j]) {
+					t.Errorf("expected %v, actual %v", test.Expectation[j], act[j])
+				}
+			}
+		})
+	}
+}
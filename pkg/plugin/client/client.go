@@ -25,6 +25,14 @@ type IntegrationPlugin interface {
 	Run(ctx context.Context, config interface{}, srv v1.WerftServiceClient) error
 }
 
+// ContentProviderPlugin provides job workspace content from sources other than GitHub
+type ContentProviderPlugin interface {
+	v1.ContentProviderPluginServer
+
+	// Init configures the plugin. It's called once before werft makes its first call.
+	Init(config interface{}) error
+}
+
 // ServeOpt configures a plugin serve
 type ServeOpt struct {
 	Type common.Type
@@ -48,6 +56,32 @@ func WithIntegrationPlugin(p IntegrationPlugin) ServeOpt {
 	}
 }
 
+// WithContentProviderPlugin registers content provider plugin capabilities
+func WithContentProviderPlugin(p ContentProviderPlugin) ServeOpt {
+	return ServeOpt{
+		Type: common.TypeContentProvider,
+		Run: func(ctx context.Context, config interface{}, socket string) error {
+			err := p.Init(config)
+			if err != nil {
+				return err
+			}
+
+			lis, err := net.Listen("unix", socket)
+			if err != nil {
+				return xerrors.Errorf("cannot listen on %s: %v", socket, err)
+			}
+
+			s := grpc.NewServer()
+			v1.RegisterContentProviderPluginServer(s, p)
+			go func() {
+				<-ctx.Done()
+				s.GracefulStop()
+			}()
+			return s.Serve(lis)
+		},
+	}
+}
+
 // Serve is the main entry point for plugins
 func Serve(configType interface{}, opts ...ServeOpt) {
 	if typ := reflect.TypeOf(configType); typ.Kind() != reflect.Ptr {
@@ -6,4 +6,7 @@ type Type string
 const (
 	// TypeIntegration means the plugin can act as integration plugin
 	TypeIntegration Type = "integration"
+
+	// TypeContentProvider means the plugin can provide job workspace content from sources other than GitHub
+	TypeContentProvider Type = "content-provider"
 )
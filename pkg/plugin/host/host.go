@@ -7,10 +7,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/plugin/common"
+	"github.com/32leaves/werft/pkg/recovery"
+	"github.com/golang/protobuf/ptypes"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
@@ -23,11 +27,49 @@ type Registration struct {
 	Command []string      `yaml:"command"`
 	Type    []common.Type `yaml:"type"`
 	Config  yaml.Node     `yaml:"config"`
+
+	// Hosts are the repository hosts a content-provider plugin provides content for, e.g. perforce.acme.com
+	Hosts []string `yaml:"hosts,omitempty"`
 }
 
 // Config configures the plugin system
 type Config []Registration
 
+// Validate checks that every plugin has a unique name, a command and known types, naming the offending field
+func (c Config) Validate() error {
+	names := make(map[string]struct{}, len(c))
+	for i, reg := range c {
+		if reg.Name == "" {
+			return xerrors.Errorf("[%d].name: must not be empty", i)
+		}
+		if _, exists := names[reg.Name]; exists {
+			return xerrors.Errorf("[%d].name: there is another plugin named \"%s\"", i, reg.Name)
+		}
+		names[reg.Name] = struct{}{}
+
+		if len(reg.Command) == 0 {
+			return xerrors.Errorf("[%d].command: must not be empty", i)
+		}
+		if len(reg.Type) == 0 {
+			return xerrors.Errorf("[%d].type: must name at least one type", i)
+		}
+		var contentProvider bool
+		for j, t := range reg.Type {
+			switch t {
+			case common.TypeIntegration:
+			case common.TypeContentProvider:
+				contentProvider = true
+			default:
+				return xerrors.Errorf("[%d].type[%d]: unknown plugin type \"%s\" - must be %s or %s", i, j, t, common.TypeIntegration, common.TypeContentProvider)
+			}
+		}
+		if len(reg.Hosts) > 0 && !contentProvider {
+			return xerrors.Errorf("[%d].hosts: only %s plugins provide content for hosts", i, common.TypeContentProvider)
+		}
+	}
+	return nil
+}
+
 // Plugins represents an initialized plugin system
 type Plugins struct {
 	Errchan chan Error
@@ -35,6 +77,39 @@ type Plugins struct {
 	stopchan     chan struct{}
 	sockets      map[string]string
 	werftService v1.WerftServiceServer
+
+	mu               sync.RWMutex
+	status           map[string]*v1.PluginStatus
+	contentProviders map[string]v1.ContentProviderPluginClient
+	conns            []*grpc.ClientConn
+}
+
+// Status returns the status of all started plugins
+func (p *Plugins) Status() []*v1.PluginStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	res := make([]*v1.PluginStatus, 0, len(p.status))
+	for _, s := range p.status {
+		c := *s
+		res = append(res, &c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Type < res[j].Type
+	})
+	return res
+}
+
+// ContentProvider returns the content-provider plugin registered for a repository host
+func (p *Plugins) ContentProvider(host string) (v1.ContentProviderPluginClient, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	c, ok := p.contentProviders[host]
+	return c, ok
 }
 
 // Stop stops all plugins
@@ -42,6 +117,12 @@ func (p *Plugins) Stop() {
 	// TODO: backsync stopping using waitgroup
 	close(p.stopchan)
 
+	p.mu.Lock()
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.mu.Unlock()
+
 	for _, s := range p.sockets {
 		os.Remove(s)
 	}
@@ -58,10 +139,12 @@ func Start(cfg Config, srv v1.WerftServiceServer) (*Plugins, error) {
 	errchan, stopchan := make(chan Error), make(chan struct{})
 
 	plugins := &Plugins{
-		Errchan:      errchan,
-		stopchan:     stopchan,
-		sockets:      make(map[string]string),
-		werftService: srv,
+		Errchan:          errchan,
+		stopchan:         stopchan,
+		sockets:          make(map[string]string),
+		werftService:     srv,
+		status:           make(map[string]*v1.PluginStatus),
+		contentProviders: make(map[string]v1.ContentProviderPluginClient),
 	}
 
 	for _, pr := range cfg {
@@ -74,10 +157,12 @@ func Start(cfg Config, srv v1.WerftServiceServer) (*Plugins, error) {
 	return plugins, nil
 }
 
-func (p *Plugins) socketFor(t common.Type) (string, error) {
+func (p *Plugins) socketFor(reg Registration, t common.Type) (string, error) {
 	switch t {
 	case common.TypeIntegration:
 		return p.socketForIntegrationPlugin()
+	case common.TypeContentProvider:
+		return p.socketForContentProviderPlugin(reg)
 	default:
 		return "", xerrors.Errorf("unknown plugin type %s", t)
 	}
@@ -93,24 +178,63 @@ func (p *Plugins) socketForIntegrationPlugin() (string, error) {
 	if err != nil {
 		return "", xerrors.Errorf("cannot start inegration plugin server: %w", err)
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(recovery.UnaryServerInterceptor),
+		grpc.StreamInterceptor(recovery.StreamServerInterceptor),
+	)
 	v1.RegisterWerftServiceServer(s, p.werftService)
-	go func() {
+	recovery.Go("plugins", func() {
 		err := s.Serve(lis)
 		if err != nil {
 			p.Errchan <- Error{Err: err}
 		}
 		delete(p.sockets, string(common.TypeIntegration))
-	}()
-	go func() {
+	})
+	recovery.Go("plugins", func() {
 		<-p.stopchan
 		s.GracefulStop()
-	}()
+	})
 
 	p.sockets[string(common.TypeIntegration)] = socketFN
 	return socketFN, nil
 }
 
+// socketForContentProviderPlugin produces the socket a content-provider plugin serves on. Unlike integration plugins
+// where werft is the server, every content-provider plugin serves on its own socket.
+func (p *Plugins) socketForContentProviderPlugin(reg Registration) (string, error) {
+	if len(reg.Hosts) == 0 {
+		return "", xerrors.Errorf("content-provider plugin %s has no hosts", reg.Name)
+	}
+
+	socketFN := filepath.Join(os.TempDir(), fmt.Sprintf("werft-plugin-content-provider-%s-%d.sock", reg.Name, time.Now().UnixNano()))
+	// the plugin creates the socket once it's started, hence we must not block here
+	conn, err := grpc.Dial(socketFN, grpc.WithInsecure(), grpc.WithDialer(unixConnect))
+	if err != nil {
+		return "", xerrors.Errorf("cannot connect to content-provider plugin %s: %w", reg.Name, err)
+	}
+	client := v1.NewContentProviderPluginClient(conn)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, h := range reg.Hosts {
+		if _, exists := p.contentProviders[h]; exists {
+			conn.Close()
+			return "", xerrors.Errorf("host %s has more than one content-provider plugin", h)
+		}
+	}
+	for _, h := range reg.Hosts {
+		p.contentProviders[h] = client
+	}
+	p.conns = append(p.conns, conn)
+	p.sockets[string(common.TypeContentProvider)+"-"+reg.Name] = socketFN
+
+	return socketFN, nil
+}
+
+func unixConnect(addr string, t time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", addr, t)
+}
+
 func (p *Plugins) startPlugin(reg Registration) error {
 	cfgfile, err := ioutil.TempFile(os.TempDir(), "werft-plugin-cfg")
 	if err != nil {
@@ -126,7 +250,7 @@ func (p *Plugins) startPlugin(reg Registration) error {
 	}
 
 	for _, t := range reg.Type {
-		socket, err := p.socketFor(t)
+		socket, err := p.socketFor(reg, t)
 		if err != nil {
 			return err
 		}
@@ -160,9 +284,28 @@ func (p *Plugins) startPlugin(reg Registration) error {
 		}
 		pluginLog.Info("plugin started")
 
+		p.mu.Lock()
+		p.status[pluginName] = &v1.PluginStatus{
+			Name:    reg.Name,
+			Type:    string(t),
+			Running: true,
+			Started: ptypes.TimestampNow(),
+		}
+		p.mu.Unlock()
+
 		var mayFail bool
-		go func() {
+		recovery.Go("plugins", func() {
 			err := cmd.Wait()
+
+			p.mu.Lock()
+			if s, ok := p.status[pluginName]; ok {
+				s.Running = false
+				if err != nil {
+					s.Error = err.Error()
+				}
+			}
+			p.mu.Unlock()
+
 			if err != nil && !mayFail {
 				p.Errchan <- Error{
 					Err: err,
@@ -172,14 +315,14 @@ func (p *Plugins) startPlugin(reg Registration) error {
 
 			stdout.Close()
 			stderr.Close()
-		}()
-		go func() {
+		})
+		recovery.Go("plugins", func() {
 			<-p.stopchan
 			mayFail = true
 			if cmd.Process != nil {
 				cmd.Process.Kill()
 			}
-		}()
+		})
 	}
 
 	return nil
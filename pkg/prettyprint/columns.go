@@ -0,0 +1,143 @@
+package prettyprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"golang.org/x/xerrors"
+)
+
+// CustomColumnsFormat prints a table whose columns are selected using field paths, e.g. NAME:.name,PHASE:.phase
+const CustomColumnsFormat Format = "custom-columns"
+
+// Column is a single column printed by the custom-columns format
+type Column struct {
+	Header string
+	// Path points to the column value in the JSON representation of a row, e.g. .metadata.repository.owner
+	Path string
+}
+
+// ParseCustomColumns parses a custom columns spec in the form of HEADER:.path,HEADER:.path
+func ParseCustomColumns(spec string) ([]Column, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, xerrors.Errorf("custom-columns requires at least one column, e.g. NAME:.name")
+	}
+
+	var res []Column
+	for _, c := range strings.Split(spec, ",") {
+		segs := strings.SplitN(c, ":", 2)
+		if len(segs) != 2 || segs[0] == "" || !strings.HasPrefix(segs[1], ".") {
+			return nil, xerrors.Errorf("invalid column \"%s\": expected HEADER:.path", c)
+		}
+		res = append(res, Column{Header: segs[0], Path: segs[1]})
+	}
+	return res, nil
+}
+
+func formatCustomColumns(pp *Content) error {
+	if len(pp.Columns) == 0 {
+		return xerrors.Errorf("custom-columns requires at least one column")
+	}
+
+	// We go through the JSON representation of the content so that field paths match what --output-format json prints.
+	var buf bytes.Buffer
+	enc := &jsonpb.Marshaler{EmitDefaults: true}
+	err := enc.Marshal(&buf, pp.Obj)
+	if err != nil {
+		return err
+	}
+	var obj interface{}
+	err = json.Unmarshal(buf.Bytes(), &obj)
+	if err != nil {
+		return err
+	}
+
+	rows := []interface{}{obj}
+	if pp.Rows != "" {
+		r, _ := lookupPath(obj, pp.Rows)
+		rows, _ = r.([]interface{})
+	}
+
+	w := tabwriter.NewWriter(pp.Writer, 8, 8, 8, ' ', 0)
+	if !pp.NoHeaders {
+		hdr := make([]string, len(pp.Columns))
+		for i, c := range pp.Columns {
+			hdr[i] = c.Header
+		}
+		fmt.Fprintln(w, strings.Join(hdr, "\t"))
+	}
+	for _, row := range rows {
+		vals := make([]string, len(pp.Columns))
+		for i, c := range pp.Columns {
+			v, ok := lookupPath(row, c.Path)
+			if !ok {
+				vals[i] = "<none>"
+				continue
+			}
+			vals[i] = columnValue(v)
+		}
+		fmt.Fprintln(w, strings.Join(vals, "\t"))
+	}
+	return w.Flush()
+}
+
+// lookupPath resolves a path like .metadata.repository.owner or .results[0].type in a JSON object
+func lookupPath(obj interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return obj, true
+	}
+
+	for _, seg := range strings.Split(path, ".") {
+		var idx = -1
+		if i := strings.Index(seg, "["); i > 0 && strings.HasSuffix(seg, "]") {
+			n, err := strconv.Atoi(seg[i+1 : len(seg)-1])
+			if err != nil {
+				return nil, false
+			}
+			seg, idx = seg[:i], n
+		}
+
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj, ok = m[seg]
+		if !ok {
+			return nil, false
+		}
+
+		if idx >= 0 {
+			arr, ok := obj.([]interface{})
+			if !ok || idx >= len(arr) {
+				return nil, false
+			}
+			obj = arr[idx]
+		}
+	}
+	return obj, true
+}
+
+func columnValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	}
+}
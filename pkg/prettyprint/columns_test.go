@@ -0,0 +1,68 @@
+package prettyprint_test
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/prettyprint"
+)
+
+func TestCustomColumns(t *testing.T) {
+	obj := &v1.ListJobsResponse{
+		Result: []*v1.JobStatus{
+			&v1.JobStatus{
+				Name:  "werft-build.1",
+				Phase: v1.JobPhase_PHASE_DONE,
+				Metadata: &v1.JobMetadata{
+					Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "master"},
+				},
+				Conditions: &v1.JobConditions{Success: true},
+				Results:    []*v1.JobResult{&v1.JobResult{Type: "url", Payload: "https://werft.sh"}},
+			},
+			&v1.JobStatus{Name: "werft-build.2"},
+		},
+	}
+
+	tests := []struct {
+		Spec        string
+		NoHeaders   bool
+		Expectation string
+		Error       bool
+	}{
+		{"NAME:.name,REF:.metadata.repository.ref", false, "NAME                 REF\nwerft-build.1        master\nwerft-build.2        <none>\n", false},
+		{"NAME:.name,OK:.conditions.success,URL:.results[0].payload", true, "werft-build.1        true          https://werft.sh\nwerft-build.2        <none>        <none>\n", false},
+		{"PHASE:.phase", true, "PHASE_DONE\nPHASE_UNKNOWN\n", false},
+		{"NAME", false, "", true},
+		{"NAME:name", false, "", true},
+		{"", false, "", true},
+	}
+
+	for idx, test := range tests {
+		cols, err := prettyprint.ParseCustomColumns(test.Spec)
+		if (err != nil) != test.Error {
+			t.Errorf("test %d: unexpected error: %v", idx, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		var buf bytes.Buffer
+		err = (&prettyprint.Content{
+			Obj:       obj,
+			Format:    prettyprint.CustomColumnsFormat,
+			Writer:    &buf,
+			Columns:   cols,
+			Rows:      ".result",
+			NoHeaders: test.NoHeaders,
+		}).Print()
+		if err != nil {
+			t.Errorf("test %d: cannot print: %v", idx, err)
+			continue
+		}
+		if act := buf.String(); act != test.Expectation {
+			t.Errorf("test %d: expected %q, actual %q", idx, test.Expectation, act)
+		}
+	}
+}
@@ -24,10 +24,11 @@ const (
 type formatterFunc func(*Content) error
 
 var formatter = map[Format]formatterFunc{
-	StringFormat:   formatString,
-	TemplateFormat: formatTemplate,
-	JSONFormat:     formatJSON,
-	YAMLFormat:     formatYAML,
+	StringFormat:        formatString,
+	TemplateFormat:      formatTemplate,
+	JSONFormat:          formatJSON,
+	YAMLFormat:          formatYAML,
+	CustomColumnsFormat: formatCustomColumns,
 }
 
 func formatString(pp *Content) error {
@@ -41,6 +42,17 @@ type Content struct {
 	Format   Format
 	Writer   io.Writer
 	Template string
+
+	// NoHeaders omits the header line of tabular output, i.e. the custom-columns header and the first line of templates
+	// which have a header row
+	NoHeaders bool
+	// HeaderRow is true if the first line of Template is a header row
+	HeaderRow bool
+	// Columns are the columns printed by the custom-columns format
+	Columns []Column
+	// Rows is the path of the list the custom-columns format prints one row for each element of, e.g. .result.
+	// If empty, the content itself is printed as single row.
+	Rows string
 }
 
 // Print outputs the content to its writer in the given format
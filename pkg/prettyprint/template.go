@@ -1,11 +1,15 @@
 package prettyprint
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"text/tabwriter"
 	"text/template"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
+	durpb "github.com/golang/protobuf/ptypes/duration"
 	tspb "github.com/golang/protobuf/ptypes/timestamp"
 )
 
@@ -23,6 +27,38 @@ func formatTemplate(pp *Content) error {
 				}
 				return ts.Format(time.RFC3339)
 			},
+			"toDuration": func(d *durpb.Duration) string {
+				if d == nil {
+					return "-"
+				}
+				dur, err := ptypes.Duration(d)
+				if err != nil {
+					return err.Error()
+				}
+				return dur.Round(time.Second).String()
+			},
+			"between": func(from, to *tspb.Timestamp) string {
+				f, err := ptypes.Timestamp(from)
+				if err != nil {
+					return "-"
+				}
+				t, err := ptypes.Timestamp(to)
+				if err != nil {
+					return "-"
+				}
+				return t.Sub(f).Round(time.Second).String()
+			},
+			"remaining": func(t *tspb.Timestamp) string {
+				ts, err := ptypes.Timestamp(t)
+				if err != nil {
+					return err.Error()
+				}
+				return Remaining(ts)
+			},
+			"toPercent": func(f float64) string {
+				return fmt.Sprintf("%.0f%%", f*100)
+			},
+			"toBytes": Bytes,
 		}).
 		Parse(pp.Template)
 	if err != nil {
@@ -30,7 +66,11 @@ func formatTemplate(pp *Content) error {
 	}
 
 	w := tabwriter.NewWriter(pp.Writer, 8, 8, 8, ' ', 0)
-	if err := tmpl.Execute(w, pp.Obj); err != nil {
+	var out io.Writer = w
+	if pp.NoHeaders && pp.HeaderRow {
+		out = &skipFirstLineWriter{Writer: w}
+	}
+	if err := tmpl.Execute(out, pp.Obj); err != nil {
 		return err
 	}
 	if err := w.Flush(); err != nil {
@@ -38,3 +78,54 @@ func formatTemplate(pp *Content) error {
 	}
 	return nil
 }
+
+// Remaining describes the time until t in a human-friendly way, e.g. "about 4 minutes remaining"
+func Remaining(t time.Time) string {
+	d := time.Until(t)
+	switch {
+	case d < 0:
+		return "taking longer than usual"
+	case d < time.Minute:
+		return "less than a minute remaining"
+	case d < 2*time.Minute:
+		return "about a minute remaining"
+	case d < time.Hour:
+		return fmt.Sprintf("about %d minutes remaining", int(d.Round(time.Minute).Minutes()))
+	default:
+		return fmt.Sprintf("about %.1f hours remaining", d.Hours())
+	}
+}
+
+// Bytes describes a number of bytes in a human-friendly way, e.g. "1.5 GiB"
+func Bytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// skipFirstLineWriter drops everything written to it up to and including the first newline
+type skipFirstLineWriter struct {
+	io.Writer
+	skipped bool
+}
+
+func (w *skipFirstLineWriter) Write(p []byte) (n int, err error) {
+	if w.skipped {
+		return w.Writer.Write(p)
+	}
+
+	idx := bytes.IndexByte(p, '\n')
+	if idx < 0 {
+		return len(p), nil
+	}
+	w.skipped = true
+	_, err = w.Writer.Write(p[idx+1:])
+	return len(p), err
+}
@@ -0,0 +1,55 @@
+package prettyprint_test
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/prettyprint"
+)
+
+func TestTemplateNoHeaders(t *testing.T) {
+	obj := &v1.ListJobsResponse{
+		Result: []*v1.JobStatus{
+			&v1.JobStatus{Name: "werft-build.1"},
+			&v1.JobStatus{Name: "werft-build.2"},
+		},
+	}
+	const (
+		table  = "NAME\n{{- range .Result }}\n{{ .Name }}\n{{- end }}\n"
+		detail = "{{ range .Result }}Name: {{ .Name }}\n{{ end }}"
+	)
+
+	tests := []struct {
+		Name        string
+		Template    string
+		HeaderRow   bool
+		NoHeaders   bool
+		Expectation string
+	}{
+		{"table", table, true, false, "NAME\nwerft-build.1\nwerft-build.2\n"},
+		{"table without headers", table, true, true, "werft-build.1\nwerft-build.2\n"},
+		{"no header row", detail, false, false, "Name: werft-build.1\nName: werft-build.2\n"},
+		{"no header row without headers", detail, false, true, "Name: werft-build.1\nName: werft-build.2\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := (&prettyprint.Content{
+				Obj:       obj,
+				Format:    prettyprint.TemplateFormat,
+				Writer:    &buf,
+				Template:  test.Template,
+				HeaderRow: test.HeaderRow,
+				NoHeaders: test.NoHeaders,
+			}).Print()
+			if err != nil {
+				t.Fatalf("cannot print: %v", err)
+			}
+			if act := buf.String(); act != test.Expectation {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"regexp"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// PayloadType is the DSSE payload type of in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+
+	// StatementType is the type of in-toto statements
+	StatementType = "https://in-toto.io/Statement/v0.1"
+
+	// PredicateType is the type of SLSA provenance predicates
+	PredicateType = "https://slsa.dev/provenance/v0.1"
+
+	// RecipeType is the type of werft job recipes
+	RecipeType = "https://werft.sh/job@v1"
+)
+
+// digestRef matches references which contain a digest, e.g. docker images like eu.gcr.io/foo/bar@sha256:abc
+var digestRef = regexp.MustCompile(`^(.+)@sha256:([0-9a-f]{64})$`)
+
+// Statement is an in-toto statement about the subjects a job produced
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is something a job produced
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate is a SLSA provenance predicate
+type Predicate struct {
+	Builder   Builder    `json:"builder"`
+	Recipe    Recipe     `json:"recipe"`
+	Metadata  Metadata   `json:"metadata"`
+	Materials []Material `json:"materials"`
+}
+
+// Builder identifies the werft installation which ran a job
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Recipe describes how a job was run
+type Recipe struct {
+	Type              string            `json:"type"`
+	DefinedInMaterial int               `json:"definedInMaterial"`
+	EntryPoint        string            `json:"entryPoint"`
+	Arguments         map[string]string `json:"arguments,omitempty"`
+}
+
+// Metadata describes a job run
+type Metadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+	Reproducible      bool       `json:"reproducible"`
+}
+
+// Material is something a job used, e.g. its source
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// NewStatement produces the provenance statement of a job. Results become the subjects of the statement.
+// Results which reference a sha256 digest, e.g. docker images, use that digest - all others are identified by the
+// digest of their payload.
+func NewStatement(job *v1.JobStatus, builderID string, jobSpec []byte) *Statement {
+	md := job.Metadata
+	if md == nil {
+		md = &v1.JobMetadata{}
+	}
+
+	stmt := &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{},
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			Builder: Builder{ID: builderID},
+			Recipe: Recipe{
+				Type:       RecipeType,
+				EntryPoint: job.Name,
+			},
+			Metadata: Metadata{
+				BuildInvocationID: job.Name,
+				BuildStartedOn:    toTime(md.Created),
+				BuildFinishedOn:   toTime(md.Finished),
+			},
+			Materials: []Material{},
+		},
+	}
+
+	for _, r := range job.Results {
+		var s Subject
+		if m := digestRef.FindStringSubmatch(r.Payload); m != nil {
+			s = Subject{Name: m[1], Digest: map[string]string{"sha256": m[2]}}
+		} else {
+			s = Subject{Name: r.Payload, Digest: map[string]string{"sha256": sha256Hex([]byte(r.Payload))}}
+		}
+		stmt.Subject = append(stmt.Subject, s)
+	}
+
+	if repo := md.Repository; repo != nil {
+		stmt.Predicate.Materials = append(stmt.Predicate.Materials, Material{
+			URI:    fmt.Sprintf("git+https://%s/%s/%s", repo.Host, repo.Owner, repo.Repo),
+			Digest: map[string]string{"sha1": repo.Revision},
+		})
+	}
+	if jobSpec != nil {
+		stmt.Predicate.Materials = append(stmt.Predicate.Materials, Material{
+			URI:    "werft:job-spec/" + job.Name,
+			Digest: map[string]string{"sha256": sha256Hex(jobSpec)},
+		})
+	}
+
+	if len(md.Annotations) > 0 {
+		stmt.Predicate.Recipe.Arguments = make(map[string]string, len(md.Annotations))
+		for _, a := range md.Annotations {
+			stmt.Predicate.Recipe.Arguments[a.Key] = a.Value
+		}
+	}
+	sort.Slice(stmt.Subject, func(i, j int) bool { return stmt.Subject[i].Name < stmt.Subject[j].Name })
+
+	return stmt
+}
+
+func toTime(ts *timestamp.Timestamp) *time.Time {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Envelope is a DSSE envelope containing a signed statement
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is the signature of an envelope's payload
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Sign signs a statement. Only ECDSA and RSA keys are supported.
+func Sign(stmt *Statement, key crypto.Signer, keyID string) (*Envelope, error) {
+	payload, err := json.Marshal(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(pae(PayloadType, payload))
+	var sig []byte
+	switch key.Public().(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		sig, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		err = xerrors.Errorf("unsupported key type %T", key.Public())
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot sign statement: %w", err)
+	}
+
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks the signature of an envelope and returns the statement it contains
+func Verify(env *Envelope, key crypto.PublicKey) (*Statement, error) {
+	if env.PayloadType != PayloadType {
+		return nil, xerrors.Errorf("unsupported payload type %s", env.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid payload: %w", err)
+	}
+
+	digest := sha256.Sum256(pae(env.PayloadType, payload))
+	var verified bool
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			var esig struct{ R, S *big.Int }
+			if _, err := asn1.Unmarshal(sig, &esig); err == nil {
+				verified = ecdsa.Verify(k, digest[:], esig.R, esig.S)
+			}
+		case *rsa.PublicKey:
+			verified = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+		default:
+			return nil, xerrors.Errorf("unsupported key type %T", key)
+		}
+		if verified {
+			break
+		}
+	}
+	if !verified {
+		return nil, xerrors.Errorf("no valid signature")
+	}
+
+	var stmt Statement
+	err = json.Unmarshal(payload, &stmt)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid statement: %w", err)
+	}
+	return &stmt, nil
+}
+
+// pae is the DSSE pre-authentication encoding
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// LoadSigningKey loads a PEM encoded PKCS8 or EC private key
+func LoadSigningKey(fn string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, xerrors.Errorf("%s is not PEM encoded", fn)
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse %s: %w", fn, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// MarshalPublicKey produces the PEM encoding of a public key
+func MarshalPublicKey(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// ParsePublicKey parses a PEM encoded public key
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, xerrors.Errorf("public key is not PEM encoded")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// KeyID identifies a public key by the digest of its DER encoding
+func KeyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(der), nil
+}
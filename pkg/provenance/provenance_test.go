@@ -0,0 +1,118 @@
+package provenance_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestNewStatement(t *testing.T) {
+	created := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	finished := created.Add(5 * time.Minute)
+	createdTS, _ := ptypes.TimestampProto(created)
+	finishedTS, _ := ptypes.TimestampProto(finished)
+
+	job := &v1.JobStatus{
+		Name: "werft-build-master.1",
+		Metadata: &v1.JobMetadata{
+			Repository:  &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Revision: "abc"},
+			Created:     createdTS,
+			Finished:    finishedTS,
+			Annotations: []*v1.Annotation{{Key: "version", Value: "1.0"}},
+		},
+		Results: []*v1.JobResult{
+			{Type: "docker", Payload: "eu.gcr.io/werft/server@sha256:4c1e44bd4b5d7a1e1fa1c4e0e9a3e0b3d4c1e44bd4b5d7a1e1fa1c4e0e9a3e0b"},
+			{Type: "url", Payload: "https://example.com"},
+		},
+	}
+
+	act := provenance.NewStatement(job, "https://werft.example.com", []byte("pod: {}"))
+	exp := &provenance.Statement{
+		Type: provenance.StatementType,
+		Subject: []provenance.Subject{
+			{Name: "eu.gcr.io/werft/server", Digest: map[string]string{"sha256": "4c1e44bd4b5d7a1e1fa1c4e0e9a3e0b3d4c1e44bd4b5d7a1e1fa1c4e0e9a3e0b"}},
+			{Name: "https://example.com", Digest: map[string]string{"sha256": "100680ad546ce6a577f42f52df33b4cfdca756859e664b8d7de329b150d09ce9"}},
+		},
+		PredicateType: provenance.PredicateType,
+		Predicate: provenance.Predicate{
+			Builder: provenance.Builder{ID: "https://werft.example.com"},
+			Recipe: provenance.Recipe{
+				Type:       provenance.RecipeType,
+				EntryPoint: "werft-build-master.1",
+				Arguments:  map[string]string{"version": "1.0"},
+			},
+			Metadata: provenance.Metadata{
+				BuildInvocationID: "werft-build-master.1",
+				BuildStartedOn:    &created,
+				BuildFinishedOn:   &finished,
+			},
+			Materials: []provenance.Material{
+				{URI: "git+https://github.com/32leaves/werft", Digest: map[string]string{"sha1": "abc"}},
+				{URI: "werft:job-spec/werft-build-master.1", Digest: map[string]string{"sha256": "fb34438733a32f8b3ba2a3f7b0a64a8f23ceaf9526cc9556bb1a82ce56695475"}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("expected %+v, actual %+v", exp, act)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt := provenance.NewStatement(&v1.JobStatus{Name: "foo.1"}, "werft", nil)
+	env, err := provenance.Sign(stmt, key, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := provenance.Verify(env, key.Public())
+	if err != nil {
+		t.Fatalf("cannot verify with the signing key: %v", err)
+	}
+	if !reflect.DeepEqual(act, stmt) {
+		t.Errorf("expected %+v, actual %+v", stmt, act)
+	}
+
+	_, err = provenance.Verify(env, other.Public())
+	if err == nil {
+		t.Errorf("verified with the wrong key")
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	key, err := provenance.LoadSigningKey("../../testdata/provenance-key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt := provenance.NewStatement(&v1.JobStatus{Name: "foo.1"}, "werft", nil)
+	env, err := provenance.Sign(stmt, key, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = provenance.Verify(env, key.Public())
+	if err != nil {
+		t.Errorf("cannot verify with the loaded key: %v", err)
+	}
+
+	_, err = provenance.LoadSigningKey("../../testdata/push-event-payload.json")
+	if err == nil {
+		t.Errorf("loaded a signing key from a file which is not PEM encoded")
+	}
+}
@@ -0,0 +1,86 @@
+// Package recovery turns panics into logged errors, s.t. a single malformed job spec or request cannot take down the
+// whole werft server
+package recovery
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"runtime/debug"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// panics counts the recovered panics per component. The server exposes them at /debug/vars.
+var panics = expvar.NewMap("panics")
+
+// Counts returns the number of recovered panics per component
+func Counts() map[string]int64 {
+	res := make(map[string]int64)
+	panics.Do(func(kv expvar.KeyValue) {
+		if v, ok := kv.Value.(*expvar.Int); ok {
+			res[kv.Key] = v.Value()
+		}
+	})
+	return res
+}
+
+// Recover recovers a panic of the calling goroutine and logs it with its stack trace. It must be deferred directly,
+// e.g. defer recovery.Recover("executor").
+func Recover(component string) {
+	if r := recover(); r != nil {
+		report(component, r)
+	}
+}
+
+// Go runs f in a new goroutine which recovers from panics
+func Go(component string, f func()) {
+	go func() {
+		defer Recover(component)
+		f()
+	}()
+}
+
+// Call runs f and turns a panic into an error
+func Call(component string, f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = report(component, r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// UnaryServerInterceptor turns panics of unary gRPC handlers into internal errors
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			report(info.FullMethod, r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor turns panics of streaming gRPC handlers into internal errors
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			report(info.FullMethod, r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func report(component string, r interface{}) error {
+	panics.Add(component, 1)
+
+	err := fmt.Errorf("panic: %v", r)
+	log.WithError(err).WithField("component", component).WithField("stack", string(debug.Stack())).Error("recovered from panic")
+	return err
+}
@@ -0,0 +1,92 @@
+package recovery_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/recovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		Name  string
+		F     func()
+		Error string
+	}{
+		{"no panic", func() {}, ""},
+		{"panic", func() { panic("malformed job spec") }, "panic: malformed job spec"},
+		{"nil map", func() {
+			var m map[string]int
+			m["foo"] = 1
+		}, "panic: assignment to entry in nil map"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			before := recovery.Counts()["test-call"]
+
+			err := recovery.Call("test-call", test.F)
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != test.Error {
+				t.Errorf("expected error \"%s\", actual \"%s\"", test.Error, errMsg)
+			}
+
+			var expectedCount int64
+			if test.Error != "" {
+				expectedCount = 1
+			}
+			if act := recovery.Counts()["test-call"] - before; act != expectedCount {
+				t.Errorf("expected %d recorded panics, actual %d", expectedCount, act)
+			}
+		})
+	}
+}
+
+func TestGo(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	recovery.Go("test-go", func() {
+		defer wg.Done()
+		panic("boom")
+	})
+	wg.Wait()
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/v1.WerftService/StartJob"}
+	tests := []struct {
+		Name    string
+		Handler grpc.UnaryHandler
+		Code    codes.Code
+	}{
+		{"ok", func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }, codes.OK},
+		{"error", func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		}, codes.NotFound},
+		{"panic", func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") }, codes.Internal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := recovery.UnaryServerInterceptor(context.Background(), nil, info, test.Handler)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("expected code %v, actual %v", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/v1.WerftService/Listen"}
+	err := recovery.StreamServerInterceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error { panic("boom") })
+	if code := status.Code(err); code != codes.Internal {
+		t.Errorf("expected code %v, actual %v", codes.Internal, code)
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// Archive keeps data which is rarely needed, e.g. the logs of old jobs, on cheaper storage
+type Archive interface {
+	// Put stores data under a name, replacing what was stored under that name before.
+	Put(name string, data io.Reader) error
+
+	// Get retrieves previously stored data.
+	// If there is nothing stored under the name we'll return ErrNotFound.
+	Get(name string) (io.ReadCloser, error)
+
+	// Delete removes data from the archive.
+	// If there is nothing stored under the name we'll return ErrNotFound.
+	Delete(name string) error
+}
+
+// FileArchive is an archive which keeps gzip compressed files in a directory,
+// e.g. a mounted object storage bucket or a cheaper volume.
+type FileArchive struct {
+	Base string
+}
+
+// NewFileArchive creates a new file backed archive
+func NewFileArchive(base string) (*FileArchive, error) {
+	err := os.MkdirAll(base, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create archive: %w", err)
+	}
+	return &FileArchive{Base: base}, nil
+}
+
+func (fa *FileArchive) path(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name || name == "." || name == ".." {
+		return "", xerrors.Errorf("invalid archive name: %s", name)
+	}
+	return filepath.Join(fa.Base, name+".gz"), nil
+}
+
+// Put stores data in the archive
+func (fa *FileArchive) Put(name string, data io.Reader) error {
+	fn, err := fa.path(name)
+	if err != nil {
+		return err
+	}
+
+	// we write to a temporary file first s.t. readers never see half of the data
+	tmp, err := ioutil.TempFile(fa.Base, ".put-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	_, err = io.Copy(zw, data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = zw.Close()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fn)
+}
+
+// Get retrieves data from the archive
+func (fa *FileArchive) Get(name string) (io.ReadCloser, error) {
+	fn, err := fa.path(name)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := os.Open(fn)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(fp)
+	if err != nil {
+		fp.Close()
+		return nil, xerrors.Errorf("archive %s is corrupt: %w", name, err)
+	}
+	return &gzipFile{Reader: zr, fp: fp}, nil
+}
+
+// Delete removes data from the archive
+func (fa *FileArchive) Delete(name string) error {
+	fn, err := fa.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(fn)
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	fp *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.fp.Close()
+}
+
+// NewEncryptedArchive produces an archive which encrypts data the way NewEncryptedLogs encrypts logs,
+// s.t. logs which are encrypted at rest remain so once they are archived.
+func NewEncryptedArchive(archive Archive, keys [][]byte) (Archive, error) {
+	enc, err := NewEncryptedLogs(nil, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedArchive{Archive: archive, enc: enc.(*encryptedLogs)}, nil
+}
+
+type encryptedArchive struct {
+	Archive
+
+	enc *encryptedLogs
+}
+
+// Put encrypts data and stores it in the archive
+func (ea *encryptedArchive) Put(name string, data io.Reader) error {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := pw.Write(encryptedLogMagic)
+		if err == nil {
+			_, err = io.Copy(&encryptedLogWriter{parent: ea.enc, id: name, out: pw, sequenced: true}, data)
+		}
+		pw.CloseWithError(err)
+	}()
+
+	err := ea.Archive.Put(name, pr)
+	// unblocks the writer should the archive have stopped reading early
+	pr.Close()
+	return err
+}
+
+// Get retrieves data from the archive and decrypts it
+func (ea *encryptedArchive) Get(name string) (io.ReadCloser, error) {
+	in, err := ea.Archive.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedLogReader{parent: ea.enc, id: name, in: in}, nil
+}
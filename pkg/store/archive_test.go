@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+func TestFileArchive(t *testing.T) {
+	base, err := ioutil.TempDir("", "werft-archive")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	fa, err := store.NewFileArchive(base)
+	if err != nil {
+		t.Fatalf("cannot create archive: %v", err)
+	}
+	encrypted, err := store.NewEncryptedArchive(fa, [][]byte{bytes.Repeat([]byte{'a'}, 32)})
+	if err != nil {
+		t.Fatalf("cannot create archive: %v", err)
+	}
+
+	tests := []struct {
+		Name    string
+		Archive store.Archive
+	}{
+		{"plain", fa},
+		{"encrypted", encrypted},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			content := strings.Repeat("hello world\n", 10000)
+			err := test.Archive.Put("foo.log", strings.NewReader(content))
+			if err != nil {
+				t.Fatalf("cannot put: %v", err)
+			}
+
+			rd, err := test.Archive.Get("foo.log")
+			if err != nil {
+				t.Fatalf("cannot get: %v", err)
+			}
+			act, err := ioutil.ReadAll(rd)
+			rd.Close()
+			if err != nil {
+				t.Fatalf("cannot read: %v", err)
+			}
+			if string(act) != content {
+				t.Errorf("unexpected content of %d bytes, expected %d bytes", len(act), len(content))
+			}
+
+			err = test.Archive.Delete("foo.log")
+			if err != nil {
+				t.Fatalf("cannot delete: %v", err)
+			}
+			if _, err := test.Archive.Get("foo.log"); err != store.ErrNotFound {
+				t.Errorf("expected ErrNotFound after delete, got %v", err)
+			}
+			if err := test.Archive.Delete("foo.log"); err != store.ErrNotFound {
+				t.Errorf("expected ErrNotFound when deleting twice, got %v", err)
+			}
+		})
+	}
+
+	if err := fa.Put("../escape", strings.NewReader("")); err == nil {
+		t.Errorf("expected an error for names outside the archive")
+	}
+}
@@ -0,0 +1,396 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+var (
+	// encryptedLogMagic starts every encrypted log. Logs without it were written before encryption was enabled.
+	encryptedLogMagic = []byte("werftenc2\n")
+	// unsequencedLogMagic starts the encrypted logs written before records were numbered. They remain readable, but
+	// we cannot tell if their records were reordered or dropped.
+	unsequencedLogMagic = []byte("werftenc1\n")
+)
+
+const (
+	// logKeySize is the size of the AES-256 keys we encrypt logs with
+	logKeySize = 32
+	// logKeyIDSize is the size of the key ID in front of every record
+	logKeyIDSize = 4
+	// maxLogRecordSize limits the size of a single record s.t. a corrupt length cannot make us allocate gigabytes
+	maxLogRecordSize = 64 * 1024 * 1024
+)
+
+// LogEncryption configures the encryption of logs at rest
+type LogEncryption struct {
+	// KeyPath is a file containing the base64 encoded 32 byte key new logs are encrypted with, e.g. a mounted secret
+	// which a KMS keeps up to date. If empty, logs are not encrypted.
+	KeyPath string `yaml:"keyPath,omitempty"`
+	// PreviousKeyPaths are keys logs were encrypted with before, s.t. they remain readable after the key changed
+	PreviousKeyPaths []string `yaml:"previousKeyPaths,omitempty"`
+}
+
+// LoadKeys reads the configured keys. The first key is the one new logs are encrypted with.
+func (cfg LogEncryption) LoadKeys() ([][]byte, error) {
+	if cfg.KeyPath == "" {
+		if len(cfg.PreviousKeyPaths) > 0 {
+			return nil, xerrors.Errorf("previous log encryption keys require a current key")
+		}
+		return nil, nil
+	}
+
+	var keys [][]byte
+	for _, fn := range append([]string{cfg.KeyPath}, cfg.PreviousKeyPaths...) {
+		raw, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read log encryption key: %w", err)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return nil, xerrors.Errorf("log encryption key %s is not base64 encoded: %w", fn, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// NewEncryptedLogs produces a log store which encrypts logs with AES-GCM before they reach the underlying store.
+// New logs are encrypted with the first key, any of the keys decrypts them. Logs written before encryption was
+// enabled are read as they are.
+//
+// Each write to a log becomes a record of its own, s.t. logs can be read while they are being written:
+// the length of the record (4 bytes), the ID of the key (4 bytes), the nonce and the sealed data.
+// The log ID and the sequence number of the record are authenticated with each record, hence records cannot be moved
+// between logs, nor reordered, repeated or dropped within a log. Only dropping the records at the end of a log goes
+// unnoticed, which is indistinguishable from a log that is still being written.
+func NewEncryptedLogs(logs Logs, keys [][]byte) (Logs, error) {
+	if len(keys) == 0 {
+		return nil, xerrors.Errorf("log encryption needs at least one key")
+	}
+
+	res := &encryptedLogs{
+		Logs:    logs,
+		keys:    make(map[[logKeyIDSize]byte]cipher.AEAD, len(keys)),
+		writers: make(map[string]*encryptedLogWriter),
+	}
+	for i, key := range keys {
+		if len(key) != logKeySize {
+			return nil, xerrors.Errorf("log encryption keys must be %d bytes long, key %d has %d bytes", logKeySize, i, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		id := logKeyID(key)
+		if _, exists := res.keys[id]; exists {
+			continue
+		}
+		res.keys[id] = aead
+		if i == 0 {
+			res.current, res.currentID = aead, id
+		}
+	}
+	return res, nil
+}
+
+// recordAAD produces the additional data we authenticate a record with: the log ID and, unless the log predates
+// sequence numbers, the number of the record within the log
+func recordAAD(id string, seq uint64, sequenced bool) []byte {
+	aad := []byte(id)
+	if !sequenced {
+		return aad
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], seq)
+	return append(aad, buf[:]...)
+}
+
+// logKeyID identifies a key without revealing it
+func logKeyID(key []byte) (id [logKeyIDSize]byte) {
+	h := sha256.Sum256(key)
+	copy(id[:], h[:])
+	return id
+}
+
+type encryptedLogs struct {
+	Logs
+
+	current   cipher.AEAD
+	currentID [logKeyIDSize]byte
+	keys      map[[logKeyIDSize]byte]cipher.AEAD
+
+	mu      sync.Mutex
+	writers map[string]*encryptedLogWriter
+}
+
+// Open places a logfile in this store.
+func (el *encryptedLogs) Open(id string) (io.WriteCloser, error) {
+	out, err := el.Logs.Open(id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = out.Write(encryptedLogMagic)
+	if err != nil {
+		out.Close()
+		return nil, err
+	}
+
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	w := &encryptedLogWriter{
+		parent:    el,
+		id:        id,
+		out:       out,
+		closer:    out,
+		sequenced: true,
+	}
+	el.writers[id] = w
+	return w, nil
+}
+
+// Write writes to a previously placed logfile.
+func (el *encryptedLogs) Write(id string) (io.Writer, error) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	if w, ok := el.writers[id]; ok {
+		return w, nil
+	}
+
+	out, err := el.Logs.Write(id)
+	if err != nil {
+		return nil, err
+	}
+	sequenced, seq, err := el.countRecords(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// later writes must continue the sequence of this writer, hence we keep it until the log is opened again
+	w := &encryptedLogWriter{parent: el, id: id, out: out, sequenced: sequenced, seq: seq}
+	el.writers[id] = w
+	return w, nil
+}
+
+// countRecords finds out how to continue a log we did not open: whether its records are numbered and how many
+// records it has
+func (el *encryptedLogs) countRecords(id string) (sequenced bool, n uint64, err error) {
+	in, err := el.Logs.Read(id)
+	if err != nil {
+		return false, 0, err
+	}
+	defer in.Close()
+
+	hdr := make([]byte, len(encryptedLogMagic))
+	_, err = io.ReadFull(in, hdr)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	if bytes.Equal(hdr, unsequencedLogMagic) {
+		return false, 0, nil
+	}
+	if !bytes.Equal(hdr, encryptedLogMagic) {
+		return true, 0, nil
+	}
+
+	var lenbuf [4]byte
+	for {
+		_, err = io.ReadFull(in, lenbuf[:])
+		if err == io.EOF {
+			return true, n, nil
+		}
+		if err != nil {
+			return false, 0, err
+		}
+		size := binary.BigEndian.Uint32(lenbuf[:])
+		if size > maxLogRecordSize {
+			return false, 0, xerrors.Errorf("log %s is corrupt: invalid record size %d", id, size)
+		}
+		_, err = io.CopyN(ioutil.Discard, in, int64(size))
+		if err != nil {
+			return false, 0, err
+		}
+		n++
+	}
+}
+
+// Read retrieves a log file from this store and decrypts it.
+func (el *encryptedLogs) Read(id string) (io.ReadCloser, error) {
+	in, err := el.Logs.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedLogReader{parent: el, id: id, in: in}, nil
+}
+
+// encryptedLogWriter seals every write into a record
+type encryptedLogWriter struct {
+	parent *encryptedLogs
+	id     string
+	out    io.Writer
+	closer io.Closer
+
+	mu sync.Mutex
+	// sequenced is false for logs which predate sequence numbers. seq is the number of the next record.
+	sequenced bool
+	seq       uint64
+}
+
+func (w *encryptedLogWriter) Write(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	aead := w.parent.current
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return 0, err
+	}
+
+	rec := make([]byte, 4, 4+logKeyIDSize+len(nonce)+len(p)+aead.Overhead())
+	rec = append(rec, w.parent.currentID[:]...)
+	rec = append(rec, nonce...)
+	rec = aead.Seal(rec, nonce, p, recordAAD(w.id, w.seq, w.sequenced))
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(rec)-4))
+
+	// the record must reach the underlying store in one write, s.t. readers never see parts of it interleaved with others
+	_, err = w.out.Write(rec)
+	if err != nil {
+		return 0, err
+	}
+	w.seq++
+	return len(p), nil
+}
+
+func (w *encryptedLogWriter) Close() error {
+	w.parent.mu.Lock()
+	if w.parent.writers[w.id] == w {
+		delete(w.parent.writers, w.id)
+	}
+	w.parent.mu.Unlock()
+
+	if w.closer == nil {
+		return nil
+	}
+	return w.closer.Close()
+}
+
+// encryptedLogReader decrypts the records of a log as they become available
+type encryptedLogReader struct {
+	parent *encryptedLogs
+	id     string
+	in     io.ReadCloser
+
+	started   bool
+	plaintext bool
+	sequenced bool
+	seq       uint64
+	buf       []byte
+}
+
+func (r *encryptedLogReader) Read(p []byte) (n int, err error) {
+	if !r.started {
+		r.started = true
+
+		hdr := make([]byte, len(encryptedLogMagic))
+		n, err := io.ReadFull(r.in, hdr)
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			r.plaintext = true
+			r.buf = hdr[:n]
+		case err != nil:
+			return 0, err
+		case bytes.Equal(hdr, encryptedLogMagic):
+			r.sequenced = true
+		case bytes.Equal(hdr, unsequencedLogMagic):
+		default:
+			// the log was written before encryption was enabled
+			r.plaintext = true
+			r.buf = hdr[:n]
+		}
+	}
+
+	for len(r.buf) == 0 {
+		if r.plaintext {
+			return r.in.Read(p)
+		}
+		r.buf, err = r.nextRecord()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// nextRecord reads and decrypts the next record of the log
+func (r *encryptedLogReader) nextRecord() ([]byte, error) {
+	var lenbuf [4]byte
+	_, err := io.ReadFull(r.in, lenbuf[:])
+	if err != nil {
+		// io.EOF means the log ends between two records, which is fine
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(lenbuf[:])
+	if size < logKeyIDSize || size > maxLogRecordSize {
+		return nil, xerrors.Errorf("log %s is corrupt: invalid record size %d", r.id, size)
+	}
+
+	rec := make([]byte, size)
+	_, err = io.ReadFull(r.in, rec)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keyID [logKeyIDSize]byte
+	copy(keyID[:], rec)
+	aead, ok := r.parent.keys[keyID]
+	if !ok {
+		return nil, xerrors.Errorf("log %s was encrypted with an unknown key", r.id)
+	}
+	rec = rec[logKeyIDSize:]
+	if len(rec) < aead.NonceSize() {
+		return nil, xerrors.Errorf("log %s is corrupt: record too short", r.id)
+	}
+	nonce, sealed := rec[:aead.NonceSize()], rec[aead.NonceSize():]
+	data, err := aead.Open(sealed[:0], nonce, sealed, recordAAD(r.id, r.seq, r.sequenced))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decrypt log %s at record %d: %w", r.id, r.seq, err)
+	}
+	r.seq++
+	return data, nil
+}
+
+func (r *encryptedLogReader) Close() error {
+	return r.in.Close()
+}
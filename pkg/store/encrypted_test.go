@@ -0,0 +1,291 @@
+package store_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+func TestEncryptedLogs(t *testing.T) {
+	var (
+		keyA = bytes.Repeat([]byte{'a'}, 32)
+		keyB = bytes.Repeat([]byte{'b'}, 32)
+	)
+
+	tests := []struct {
+		Name      string
+		WriteKeys [][]byte
+		ReadKeys  [][]byte
+		Plaintext bool
+		Tamper    func(b []byte)
+		Truncate  int
+		// Records rearranges the records of the log, e.g. 1, 0 swaps the first two
+		Records     []int
+		ReadID      string
+		Expectation string
+		Error       string
+	}{
+		{
+			Name:        "round trip",
+			WriteKeys:   [][]byte{keyA},
+			ReadKeys:    [][]byte{keyA},
+			Expectation: "hello world\nsecond line\n",
+		},
+		{
+			Name:        "previous key",
+			WriteKeys:   [][]byte{keyA},
+			ReadKeys:    [][]byte{keyB, keyA},
+			Expectation: "hello world\nsecond line\n",
+		},
+		{
+			Name:      "unknown key",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyB},
+			Error:     "log foo was encrypted with an unknown key",
+		},
+		{
+			Name:        "written before encryption",
+			ReadKeys:    [][]byte{keyA},
+			Plaintext:   true,
+			Expectation: "hello world\nsecond line\n",
+		},
+		{
+			Name:      "tampered",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			Tamper:    func(b []byte) { b[len(b)-1] ^= 0xff },
+			Error:     "cannot decrypt log foo",
+		},
+		{
+			Name:      "truncated",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			Truncate:  3,
+			Error:     "unexpected EOF",
+		},
+		{
+			Name:      "reordered",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			Records:   []int{1, 0},
+			Error:     "cannot decrypt log foo at record 0",
+		},
+		{
+			Name:      "repeated",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			Records:   []int{0, 0, 1},
+			Error:     "cannot decrypt log foo at record 1",
+		},
+		{
+			Name:      "dropped",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			Records:   []int{1},
+			Error:     "cannot decrypt log foo at record 0",
+		},
+		{
+			Name:      "moved to another log",
+			WriteKeys: [][]byte{keyA},
+			ReadKeys:  [][]byte{keyA},
+			ReadID:    "bar",
+			Error:     "cannot decrypt log bar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			backend := &bufferLogs{}
+			var writer store.Logs = backend
+			if !test.Plaintext {
+				var err error
+				writer, err = store.NewEncryptedLogs(backend, test.WriteKeys)
+				if err != nil {
+					t.Fatalf("cannot create store: %v", err)
+				}
+			}
+
+			w, err := writer.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot open log: %v", err)
+			}
+			_, err = io.WriteString(w, "hello world\n")
+			if err != nil {
+				t.Fatalf("cannot write log: %v", err)
+			}
+			// writes to a log which is already open must end up in the same log
+			w2, err := writer.Write("foo")
+			if err != nil {
+				t.Fatalf("cannot write log: %v", err)
+			}
+			_, err = io.WriteString(w2, "second line\n")
+			if err != nil {
+				t.Fatalf("cannot write log: %v", err)
+			}
+			w.Close()
+
+			if !test.Plaintext && strings.Contains(backend.buf.String(), "hello") {
+				t.Errorf("log is stored in plain text")
+			}
+			if test.Tamper != nil {
+				b := backend.buf.Bytes()
+				test.Tamper(b)
+			}
+			if test.Truncate > 0 {
+				backend.buf.Truncate(backend.buf.Len() - test.Truncate)
+			}
+			if test.Records != nil {
+				b := rearrangeRecords(t, backend.buf.Bytes(), test.Records)
+				backend.buf.Reset()
+				backend.buf.Write(b)
+			}
+
+			reader, err := store.NewEncryptedLogs(backend, test.ReadKeys)
+			if err != nil {
+				t.Fatalf("cannot create store: %v", err)
+			}
+			id := test.ReadID
+			if id == "" {
+				id = "foo"
+			}
+			r, err := reader.Read(id)
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			defer r.Close()
+			content, err := ioutil.ReadAll(r)
+			if test.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Error) {
+					t.Errorf("expected error containing \"%s\", got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			if string(content) != test.Expectation {
+				t.Errorf("unexpected log content: %q, expected %q", content, test.Expectation)
+			}
+		})
+	}
+}
+
+// rearrangeRecords puts the records of an encrypted log together in the order of their indices
+func rearrangeRecords(t *testing.T, log []byte, order []int) []byte {
+	const magicSize = len("werftenc2\n")
+
+	var recs [][]byte
+	for rest := log[magicSize:]; len(rest) > 0; {
+		size := 4 + int(binary.BigEndian.Uint32(rest))
+		if size > len(rest) {
+			t.Fatalf("log is corrupt")
+		}
+		recs = append(recs, rest[:size])
+		rest = rest[size:]
+	}
+
+	res := append([]byte(nil), log[:magicSize]...)
+	for _, i := range order {
+		res = append(res, recs[i]...)
+	}
+	return res
+}
+
+func TestEncryptedLogsContinue(t *testing.T) {
+	key := bytes.Repeat([]byte{'a'}, 32)
+	backend := &bufferLogs{}
+	s, err := store.NewEncryptedLogs(backend, [][]byte{key})
+	if err != nil {
+		t.Fatalf("cannot create store: %v", err)
+	}
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	io.WriteString(w, "hello world\n")
+	w.Close()
+
+	// another instance, e.g. after a restart, must continue the sequence of the log
+	s, err = store.NewEncryptedLogs(backend, [][]byte{key})
+	if err != nil {
+		t.Fatalf("cannot create store: %v", err)
+	}
+	for _, line := range []string{"second line\n", "third line\n"} {
+		w, err := s.Write("foo")
+		if err != nil {
+			t.Fatalf("cannot write log: %v", err)
+		}
+		io.WriteString(w, line)
+	}
+
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	if exp := "hello world\nsecond line\nthird line\n"; string(content) != exp {
+		t.Errorf("unexpected log content: %q, expected %q", content, exp)
+	}
+}
+
+func TestEncryptedLogsUnsequenced(t *testing.T) {
+	// logs encrypted before records were numbered authenticate the log ID only
+	key := bytes.Repeat([]byte{'a'}, 32)
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	keyID := sha256.Sum256(key)
+
+	backend := &bufferLogs{}
+	backend.buf.WriteString("werftenc1\n")
+	for _, line := range []string{"hello world\n", "second line\n"} {
+		nonce := make([]byte, aead.NonceSize())
+		rand.Read(nonce)
+		rec := make([]byte, 4, 4+4+len(nonce)+len(line)+aead.Overhead())
+		rec = append(rec, keyID[:4]...)
+		rec = append(rec, nonce...)
+		rec = aead.Seal(rec, nonce, []byte(line), []byte("foo"))
+		binary.BigEndian.PutUint32(rec, uint32(len(rec)-4))
+		backend.buf.Write(rec)
+	}
+
+	s, err := store.NewEncryptedLogs(backend, [][]byte{key})
+	if err != nil {
+		t.Fatalf("cannot create store: %v", err)
+	}
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	if exp := "hello world\nsecond line\n"; string(content) != exp {
+		t.Errorf("unexpected log content: %q, expected %q", content, exp)
+	}
+}
+
+func TestEncryptedLogsKeySize(t *testing.T) {
+	_, err := store.NewEncryptedLogs(&bufferLogs{}, [][]byte{[]byte("too short")})
+	if err == nil {
+		t.Errorf("expected an error for a short key")
+	}
+	_, err = store.NewEncryptedLogs(&bufferLogs{}, nil)
+	if err == nil {
+		t.Errorf("expected an error without keys")
+	}
+}
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+// NewJobs combines stores for the status, job specs and results of jobs which live in different backends, e.g. statuses
+// in the database and job specs in object storage. If results is nil, the results are stored as part of the status.
+func NewJobs(statuses StatusStore, specs SpecStore, results ResultStore) Jobs {
+	return &compositeJobs{
+		StatusStore: statuses,
+		SpecStore:   specs,
+		results:     results,
+	}
+}
+
+type compositeJobs struct {
+	StatusStore
+	SpecStore
+
+	results ResultStore
+}
+
+// Store stores the status of a job and, if they live elsewhere, its results
+func (c *compositeJobs) Store(ctx context.Context, job v1.JobStatus) error {
+	if c.results == nil {
+		return c.StatusStore.Store(ctx, job)
+	}
+
+	// Results never go away once a job produced them, hence there's nothing to store for jobs without results
+	if len(job.Results) > 0 {
+		err := c.results.StoreResults(ctx, job.Name, job.Results)
+		if err != nil {
+			return err
+		}
+	}
+	job.Results = nil
+	return c.StatusStore.Store(ctx, job)
+}
+
+// Get retrieves the status of a job including its results
+func (c *compositeJobs) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
+	job, err := c.StatusStore.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	err = c.addResults(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Find searches for jobs and adds their results. Filters cannot match the results if they live elsewhere.
+func (c *compositeJobs) Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error) {
+	slice, total, err = c.StatusStore.Find(ctx, filter, order, start, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range slice {
+		err = c.addResults(ctx, &slice[i])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return slice, total, nil
+}
+
+func (c *compositeJobs) addResults(ctx context.Context, job *v1.JobStatus) error {
+	if c.results == nil || job == nil {
+		return nil
+	}
+
+	res, err := c.results.GetResults(ctx, job.Name)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	job.Results = res
+	return nil
+}
+
+// Delete removes a job including its job spec and results from all stores
+func (c *compositeJobs) Delete(ctx context.Context, name string) error {
+	err := c.StatusStore.Delete(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// The status store may have removed the job spec already if both live in the same backend
+	err = c.SpecStore.DeleteJobSpec(ctx, name)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if c.results != nil {
+		err = c.results.DeleteResults(ctx, name)
+		if err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewArchiveSpecStore produces a job spec store which keeps job specs in an archive, e.g. a mounted object storage bucket.
+// Unlike the other stores it does not share job spec data between jobs, as storage is cheap there. Its names do not clash
+// with those of archived jobs, hence it can use the same archive.
+func NewArchiveSpecStore(archive Archive) SpecStore {
+	return &archiveSpecStore{Archive: archive}
+}
+
+type archiveSpecStore struct {
+	Archive Archive
+}
+
+func (s *archiveSpecStore) StoreJobSpec(ctx context.Context, name string, data []byte) error {
+	return s.Archive.Put(name+".jobspec.yaml", bytes.NewReader(data))
+}
+
+func (s *archiveSpecStore) GetJobSpec(ctx context.Context, name string) (data []byte, err error) {
+	return getArchived(s.Archive, name+".jobspec.yaml")
+}
+
+// StoreRenderedJobSpec stores the job spec of a job as it was rendered from its template
+func (s *archiveSpecStore) StoreRenderedJobSpec(ctx context.Context, name string, data []byte) error {
+	_, err := s.GetJobSpec(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.Archive.Put(name+".jobspec.rendered.yaml", bytes.NewReader(data))
+}
+
+// GetRenderedJobSpec retrieves the rendered job spec of a job
+func (s *archiveSpecStore) GetRenderedJobSpec(ctx context.Context, name string) (data []byte, err error) {
+	return getArchived(s.Archive, name+".jobspec.rendered.yaml")
+}
+
+// DeleteJobSpec removes the job spec of a job
+func (s *archiveSpecStore) DeleteJobSpec(ctx context.Context, name string) error {
+	err := s.Archive.Delete(name + ".jobspec.yaml")
+	if err != nil {
+		return err
+	}
+	err = s.Archive.Delete(name + ".jobspec.rendered.yaml")
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// CollectJobSpecs has nothing to collect because job specs are removed with their job
+func (s *archiveSpecStore) CollectJobSpecs(ctx context.Context) (collected int, err error) {
+	return 0, nil
+}
+
+// NewArchiveResultStore produces a result store which keeps job results in an archive, e.g. a mounted object storage bucket
+func NewArchiveResultStore(archive Archive) ResultStore {
+	return &archiveResultStore{Archive: archive}
+}
+
+type archiveResultStore struct {
+	Archive Archive
+}
+
+// StoreResults stores the results of a job
+func (s *archiveResultStore) StoreResults(ctx context.Context, name string, results []*v1.JobResult) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return s.Archive.Put(name+".results.json", bytes.NewReader(data))
+}
+
+// GetResults retrieves the results of a job
+func (s *archiveResultStore) GetResults(ctx context.Context, name string) ([]*v1.JobResult, error) {
+	data, err := getArchived(s.Archive, name+".results.json")
+	if err != nil {
+		return nil, err
+	}
+	var res []*v1.JobResult
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteResults removes the results of a job
+func (s *archiveResultStore) DeleteResults(ctx context.Context, name string) error {
+	return s.Archive.Delete(name + ".results.json")
+}
+
+func getArchived(archive Archive, name string) ([]byte, error) {
+	r, err := archive.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
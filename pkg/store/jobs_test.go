@@ -0,0 +1,91 @@
+package store_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+)
+
+func TestCompositeJobs(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "werft-jobs")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+	defer os.RemoveAll(base)
+	archive, err := store.NewFileArchive(base)
+	if err != nil {
+		t.Fatalf("cannot create archive: %v", err)
+	}
+
+	statuses := store.NewInMemoryJobStore()
+	results := store.NewArchiveResultStore(archive)
+	s := store.NewJobs(statuses, store.NewArchiveSpecStore(archive), results)
+
+	err = s.Store(ctx, v1.JobStatus{Name: "foo", Results: []*v1.JobResult{{Type: "url", Payload: "https://example.com"}}})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	err = s.StoreJobSpec(ctx, "foo", []byte("pod: {}"))
+	if err != nil {
+		t.Fatalf("cannot store job spec: %v", err)
+	}
+	err = s.StoreRenderedJobSpec(ctx, "bar", []byte("pod: {}"))
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound storing the rendered job spec of a job without job spec, got %v", err)
+	}
+
+	// results live in the result store only
+	if job, err := statuses.Get(ctx, "foo"); err != nil || len(job.Results) != 0 {
+		t.Errorf("expected the status store to hold no results, got %v (%v)", job, err)
+	}
+	job, err := s.Get(ctx, "foo")
+	if err != nil {
+		t.Fatalf("cannot get job: %v", err)
+	}
+	if len(job.Results) != 1 || job.Results[0].Payload != "https://example.com" {
+		t.Errorf("unexpected results: %v", job.Results)
+	}
+	jobs, _, err := s.Find(ctx, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("cannot find jobs: %v", err)
+	}
+	if len(jobs) != 1 || len(jobs[0].Results) != 1 {
+		t.Errorf("expected the job to be found with its results, got %v", jobs)
+	}
+
+	// storing a status without results keeps the results stored before
+	err = s.Store(ctx, v1.JobStatus{Name: "foo"})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	if job, err := s.Get(ctx, "foo"); err != nil || len(job.Results) != 1 {
+		t.Errorf("expected the results to be kept, got %v (%v)", job, err)
+	}
+
+	spec, err := s.GetJobSpec(ctx, "foo")
+	if err != nil || string(spec) != "pod: {}" {
+		t.Errorf("unexpected job spec %q (%v)", spec, err)
+	}
+	if _, err := statuses.GetJobSpec(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected the status store to hold no job spec, got %v", err)
+	}
+
+	err = s.Delete(ctx, "foo")
+	if err != nil {
+		t.Fatalf("cannot delete job: %v", err)
+	}
+	if _, err := s.GetJobSpec(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected the job spec to be deleted, got %v", err)
+	}
+	if _, err := results.GetResults(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected the results to be deleted, got %v", err)
+	}
+	if err := s.Delete(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound deleting an unknown job, got %v", err)
+	}
+}
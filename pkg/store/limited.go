@@ -0,0 +1,180 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LogLimits configures the size limits of a single job log
+type LogLimits struct {
+	// MaxSizeMB is the size in megabytes after which we stop persisting the log. Zero disables the limit.
+	MaxSizeMB int64 `yaml:"maxSizeMB,omitempty"`
+	// TailSizeKB is the size in kilobytes of the end of the log we keep once a log got truncated.
+	// The tail is kept in memory and added to the log once the job is done. Listeners do not see it before then,
+	// and it is lost if werft restarts while the job runs.
+	TailSizeKB int64 `yaml:"tailSizeKB,omitempty"`
+}
+
+// NewLimitedLogs produces a log store which stops persisting a log once it exceeds the configured maximum size.
+// The last TailSizeKB kilobytes of the log are kept in memory and written once the log is closed. They are not
+// persisted before then, hence a restart loses them. The log says so as soon as it is truncated, so that readers
+// know what to expect while the job still runs. When a log is truncated for the first time, onTruncate is called with its ID.
+func NewLimitedLogs(logs Logs, limits LogLimits, onTruncate func(id string)) Logs {
+	if limits.MaxSizeMB <= 0 {
+		return logs
+	}
+
+	return &limitedLogs{
+		Logs:       logs,
+		head:       limits.MaxSizeMB * 1024 * 1024,
+		tail:       limits.TailSizeKB * 1024,
+		onTruncate: onTruncate,
+		logs:       make(map[string]*limitedLog),
+	}
+}
+
+type limitedLogs struct {
+	Logs
+
+	head       int64
+	tail       int64
+	onTruncate func(id string)
+
+	mu   sync.Mutex
+	logs map[string]*limitedLog
+}
+
+// Open places a logfile in this store.
+func (ll *limitedLogs) Open(id string) (io.WriteCloser, error) {
+	out, err := ll.Logs.Open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	l := &limitedLog{
+		parent: ll,
+		id:     id,
+		out:    out,
+		closer: out,
+	}
+	ll.logs[id] = l
+	return l, nil
+}
+
+// Write writes to a previously placed logfile.
+func (ll *limitedLogs) Write(id string) (io.Writer, error) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	if l, ok := ll.logs[id]; ok {
+		return l, nil
+	}
+
+	// The log was not opened through this store (e.g. prior to a restart), hence we don't know
+	// its size and cannot enforce a limit.
+	return ll.Logs.Write(id)
+}
+
+// limitedLog writes up to the head limit to out and keeps a rolling tail buffer afterwards
+type limitedLog struct {
+	parent *limitedLogs
+	id     string
+	out    io.Writer
+	closer io.Closer
+
+	mu       sync.Mutex
+	written  int64
+	omitted  int64
+	lastByte byte
+	tail     []byte
+	// tailCut is the byte which preceded the tail buffer when it was last trimmed
+	tailCut byte
+}
+
+func (l *limitedLog) Write(p []byte) (n int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	head := p
+	if remaining := l.parent.head - l.written; int64(len(head)) > remaining {
+		head = p[:remaining]
+	}
+	if len(head) > 0 {
+		n, err = l.out.Write(head)
+		l.written += int64(n)
+		if n > 0 {
+			l.lastByte = head[n-1]
+		}
+		if err != nil {
+			return n, err
+		}
+		if len(head) == len(p) {
+			return len(p), nil
+		}
+	}
+
+	if l.omitted == 0 {
+		l.tailCut = l.lastByte
+
+		var nl string
+		if l.lastByte != '\n' {
+			nl = "\n"
+		}
+		if l.parent.tail > 0 {
+			fmt.Fprintf(l.out, "%s[werft:truncated] log exceeds %d bytes - the last %d bytes follow once the job is done unless werft restarts\n", nl, l.parent.head, l.parent.tail)
+		} else {
+			fmt.Fprintf(l.out, "%s[werft:truncated] log exceeds %d bytes - omitting the rest\n", nl, l.parent.head)
+		}
+		if l.parent.onTruncate != nil {
+			go l.parent.onTruncate(l.id)
+		}
+	}
+
+	rest := p[len(head):]
+	l.omitted += int64(len(rest))
+	if l.parent.tail > 0 {
+		l.tail = append(l.tail, rest...)
+		if excess := int64(len(l.tail)) - l.parent.tail; excess > 0 {
+			l.tailCut = l.tail[excess-1]
+			l.tail = append(l.tail[:0], l.tail[excess:]...)
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes the retained tail of the log and closes the underlying log
+func (l *limitedLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.parent.mu.Lock()
+	delete(l.parent.logs, l.id)
+	l.parent.mu.Unlock()
+
+	if l.omitted > 0 {
+		tail := l.tail
+		// don't start the tail in the middle of a line, lest the rest of that line reads like a slice marker.
+		// If the tail is part of a single line we drop it altogether.
+		if l.tailCut != '\n' {
+			if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+				tail = tail[i+1:]
+			} else {
+				tail = nil
+			}
+		}
+
+		fmt.Fprintf(l.out, "[werft:truncated] omitted %d bytes of log output\n", l.omitted-int64(len(tail)))
+		_, err := l.out.Write(tail)
+		if err != nil {
+			l.closer.Close()
+			return err
+		}
+	}
+
+	return l.closer.Close()
+}
@@ -0,0 +1,102 @@
+package store_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+func TestLimitedLogs(t *testing.T) {
+	var (
+		backend   = &bufferLogs{}
+		truncated = make(chan string, 1)
+		once      sync.Once
+	)
+	s := store.NewLimitedLogs(backend, store.LogLimits{MaxSizeMB: 1, TailSizeKB: 1}, func(id string) {
+		once.Do(func() { truncated <- id })
+	})
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+
+	// 4096 lines of 512 bytes each make 2MB of log
+	line := strings.Repeat("x", 505)
+	for i := 0; i < 4096; i++ {
+		_, err := fmt.Fprintf(w, "%04d %s\n", i, line)
+		if err != nil {
+			t.Fatalf("cannot write log: %v", err)
+		}
+	}
+	// readers must learn about the truncation while the job still runs, not once the tail is written
+	if !strings.Contains(backend.buf.String(), "[werft:truncated] log exceeds 1048576 bytes") {
+		t.Errorf("log does not announce the truncation before it is closed")
+	}
+	if strings.Contains(backend.buf.String(), "4095 ") {
+		t.Errorf("tail was written before the log was closed")
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("cannot close log: %v", err)
+	}
+
+	if id := <-truncated; id != "foo" {
+		t.Errorf("expected truncation of foo, got %s", id)
+	}
+
+	content := backend.buf.String()
+	if len(content) > 1024*1024+1024+200 {
+		t.Errorf("log is too large: %d bytes", len(content))
+	}
+	if !strings.Contains(content, "[werft:truncated] omitted ") {
+		t.Errorf("log does not contain truncation marker")
+	}
+	if !strings.HasPrefix(content, "0000 ") {
+		t.Errorf("log does not start with the head")
+	}
+	if !strings.HasSuffix(content, "4095 "+line+"\n") {
+		t.Errorf("log does not end with the tail")
+	}
+	if !strings.Contains(content, "\n4094 ") {
+		t.Errorf("tail does not contain the last kilobyte")
+	}
+	if strings.Contains(content, "4093 ") {
+		t.Errorf("tail contains more than the last kilobyte")
+	}
+}
+
+func TestLimitedLogsPartialTail(t *testing.T) {
+	backend := &bufferLogs{}
+	s := store.NewLimitedLogs(backend, store.LogLimits{MaxSizeMB: 1, TailSizeKB: 1}, nil)
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+
+	// the log ends in a line much longer than the tail, hence the tail would start in the middle of that line
+	head := strings.Repeat("x", 1024*1024-1) + "\n"
+	long := "[build] " + strings.Repeat("y", 4096) + "[build|DONE]"
+	for _, l := range []string{head, long} {
+		_, err := w.Write([]byte(l))
+		if err != nil {
+			t.Fatalf("cannot write log: %v", err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("cannot close log: %v", err)
+	}
+
+	content := backend.buf.String()
+	expectedEnd := fmt.Sprintf("[werft:truncated] omitted %d bytes of log output\n", len(long))
+	if !strings.HasSuffix(content, expectedEnd) {
+		t.Errorf("log does not end with the truncation marker: %q", content[len(content)-200:])
+	}
+	if strings.Contains(content, "[build|DONE]") {
+		t.Errorf("log contains the partial line")
+	}
+}
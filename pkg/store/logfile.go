@@ -160,6 +160,27 @@ func (fs *FileLogStore) Read(id string) (io.ReadCloser, error) {
 	return &fileReader{f: f, fp: fp}, nil
 }
 
+// Delete removes a log file from this store
+func (fs *FileLogStore) Delete(id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if f, ok := fs.files[id]; ok && !f.Closed() {
+		return fmt.Errorf("log %s is still being written", id)
+	}
+
+	err := os.Remove(filepath.Join(fs.Base, fmt.Sprintf("%s.log", id)))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	delete(fs.files, id)
+
+	return nil
+}
+
 type fileReader struct {
 	f  *file
 	fp io.ReadCloser
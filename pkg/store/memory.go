@@ -1,14 +1,16 @@
 package store
 
 import (
-	"bytes"
 	"context"
 	"io"
-	"io/ioutil"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
 	"golang.org/x/xerrors"
 )
 
@@ -25,102 +27,117 @@ type inMemoryLogStore struct {
 	mu   sync.RWMutex
 }
 
+// logSession is a log in memory. Like a log file, it can be read while it's being written.
 type logSession struct {
-	Data   *bytes.Buffer
-	Reader map[chan []byte]struct{}
-	Mu     sync.RWMutex
+	data   []byte
+	closed bool
+	cond   *sync.Cond
 }
 
 func (l *logSession) Write(p []byte) (n int, err error) {
-	l.Mu.Lock()
-	defer l.Mu.Unlock()
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
 
-	n, err = l.Data.Write(p)
-	if n > 0 {
-
-		for r := range l.Reader {
-			r <- p[:n]
-		}
+	if l.closed {
+		return 0, io.ErrClosedPipe
 	}
-	if err != nil {
-		return n, err
+
+	l.data = append(l.data, p...)
+	if len(p) > 0 {
+		l.cond.Broadcast()
 	}
-	return
+	return len(p), nil
 }
 
 func (l *logSession) Close() error {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	if l.closed {
+		return io.ErrClosedPipe
+	}
+	l.closed = true
+	l.cond.Broadcast()
 	return nil
 }
 
+func (l *logSession) Closed() bool {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	return l.closed
+}
+
 type logSessionReader struct {
-	Log       *logSession
-	Pos       int
-	R         chan []byte
-	remainder []byte
-	closed    bool
+	Log    *logSession
+	Pos    int
+	closed bool
 }
 
+// Read reads the log from its start, waiting for more data until the log is closed
 func (lr *logSessionReader) Read(p []byte) (n int, err error) {
+	l := lr.Log
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for !lr.closed && !l.closed && lr.Pos >= len(l.data) {
+		l.cond.Wait()
+	}
 	if lr.closed {
 		return 0, io.ErrClosedPipe
 	}
-
-	if len(lr.remainder) > 0 {
-		n = copy(p, lr.remainder)
-		lr.remainder = lr.remainder[:n]
-		lr.Pos += n
-		return
-	}
-
-	lr.Log.Mu.RLock()
-	if lr.Pos >= lr.Log.Data.Len() {
-		lr.Log.Mu.RUnlock()
-		inc := <-lr.R
-
-		n = copy(p, inc)
-		lr.remainder = inc[:n]
-		lr.Pos += n
-		return
+	if lr.Pos >= len(l.data) {
+		return 0, io.EOF
 	}
 
-	n = copy(p, lr.Log.Data.Bytes()[lr.Pos:])
+	n = copy(p, l.data[lr.Pos:])
 	lr.Pos += n
-
-	lr.Log.Mu.RUnlock()
-	return 0, nil
+	return n, nil
 }
 
 func (lr *logSessionReader) Close() error {
-	lr.Log.Mu.Lock()
-	defer lr.Log.Mu.Unlock()
+	l := lr.Log
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
 
-	delete(lr.Log.Reader, lr.R)
 	lr.closed = true
-
+	l.cond.Broadcast()
 	return nil
 }
 
-// Place writes to this store
+// Open places a log in this store. Logs which were closed before are opened for appending.
 func (s *inMemoryLogStore) Open(id string) (io.WriteCloser, error) {
 	s.mu.Lock()
-	if _, ok := s.logs[id]; ok {
-		s.mu.Unlock()
-		return nil, ErrAlreadyExists
+	defer s.mu.Unlock()
+
+	if l, ok := s.logs[id]; ok {
+		l.cond.L.Lock()
+		defer l.cond.L.Unlock()
+
+		if !l.closed {
+			return nil, ErrAlreadyExists
+		}
+		l.closed = false
+		return l, nil
 	}
 
 	lg := &logSession{
-		Data:   bytes.NewBuffer(nil),
-		Reader: make(map[chan []byte]struct{}),
+		cond: sync.NewCond(&sync.Mutex{}),
 	}
-
 	s.logs[id] = lg
-	s.mu.Unlock()
-
 	return lg, nil
 }
 
+// Write provides write access to a previously placed log
 func (s *inMemoryLogStore) Write(id string) (io.Writer, error) {
-	return nil, xerrors.Errorf("not supported")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l, ok := s.logs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return l, nil
 }
 
 // Read reads from this store
@@ -132,29 +149,42 @@ func (s *inMemoryLogStore) Read(id string) (io.ReadCloser, error) {
 	if !ok {
 		return nil, ErrNotFound
 	}
+	return &logSessionReader{Log: l}, nil
+}
 
-	ch := make(chan []byte)
-	l.Mu.Lock()
-	l.Reader[ch] = struct{}{}
-	l.Mu.Unlock()
-	return ioutil.NopCloser(&logSessionReader{
-		Log: l,
-		R:   ch,
-	}), nil
+// Delete removes a log from this store
+func (s *inMemoryLogStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.logs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if !l.Closed() {
+		return xerrors.Errorf("log %s is still being written", id)
+	}
+	delete(s.logs, id)
+	return nil
 }
 
 // NewInMemoryJobStore creates a new in-memory job store
 func NewInMemoryJobStore() Jobs {
 	return &inMemoryJobStore{
-		jobs:  make(map[string]v1.JobStatus),
-		specs: make(map[string][]byte),
+		jobs:      make(map[string]v1.JobStatus),
+		specs:     make(map[string]string),
+		rendered:  make(map[string]string),
+		specBlobs: make(map[string][]byte),
 	}
 }
 
 type inMemoryJobStore struct {
-	jobs  map[string]v1.JobStatus
-	specs map[string][]byte
-	mu    sync.RWMutex
+	jobs map[string]v1.JobStatus
+	// specs and rendered map job names to the hash of their spec and rendered spec, specBlobs maps the hashes to the spec data
+	specs     map[string]string
+	rendered  map[string]string
+	specBlobs map[string][]byte
+	mu        sync.RWMutex
 }
 
 // Store stores job information in the store.
@@ -194,24 +224,910 @@ func (s *inMemoryJobStore) Find(ctx context.Context, filter []*v1.FilterExpressi
 		}
 		res = append(res, js)
 	}
-	return res, len(res), nil
+	for _, o := range order {
+		if _, ok := jobOrderFields[o.Field]; !ok {
+			return nil, 0, xerrors.Errorf("unknown field %s", o.Field)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		for _, o := range order {
+			c := jobOrderFields[o.Field](&res[i], &res[j])
+			if c == 0 {
+				continue
+			}
+			return (c < 0) == o.Ascending
+		}
+		return false
+	})
+
+	total = len(res)
+	if start > total {
+		start = total
+	}
+	res = res[start:]
+	if limit > 0 && limit < len(res) {
+		res = res[:limit]
+	}
+	return res, total, nil
+}
+
+// jobOrderFields compares jobs by the fields jobs can be ordered by. The comparison functions return a negative
+// number if a comes before b, zero if they are equal and a positive number otherwise.
+var jobOrderFields = map[string]func(a, b *v1.JobStatus) int{
+	"name":  func(a, b *v1.JobStatus) int { return strings.Compare(a.Name, b.Name) },
+	"owner": func(a, b *v1.JobStatus) int { return strings.Compare(a.Metadata.GetOwner(), b.Metadata.GetOwner()) },
+	"phase": func(a, b *v1.JobStatus) int { return int(a.Phase) - int(b.Phase) },
+	"repo.owner": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.Metadata.GetRepository().GetOwner(), b.Metadata.GetRepository().GetOwner())
+	},
+	"repo.repo": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.Metadata.GetRepository().GetRepo(), b.Metadata.GetRepository().GetRepo())
+	},
+	"repo.host": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.Metadata.GetRepository().GetHost(), b.Metadata.GetRepository().GetHost())
+	},
+	"repo.ref": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.Metadata.GetRepository().GetRef(), b.Metadata.GetRepository().GetRef())
+	},
+	"trigger": func(a, b *v1.JobStatus) int { return int(a.Metadata.GetTrigger()) - int(b.Metadata.GetTrigger()) },
+	"success": func(a, b *v1.JobStatus) int {
+		var sa, sb int
+		if a.Conditions.GetSuccess() {
+			sa = 1
+		}
+		if b.Conditions.GetSuccess() {
+			sb = 1
+		}
+		return sa - sb
+	},
+	"created": func(a, b *v1.JobStatus) int {
+		ta, _ := ptypes.Timestamp(a.Metadata.GetCreated())
+		tb, _ := ptypes.Timestamp(b.Metadata.GetCreated())
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		default:
+			return 0
+		}
+	},
+}
+
+// Delete removes a job from this store
+func (s *inMemoryJobStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.jobs, name)
+	delete(s.specs, name)
+	delete(s.rendered, name)
+	return nil
 }
 
-func (s *inMemoryJobStore) StoreJobSpec(name string, data []byte) error {
+func (s *inMemoryJobStore) StoreJobSpec(ctx context.Context, name string, data []byte) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.specs[name] = data
+	hash := JobSpecHash(data)
+	if _, ok := s.specBlobs[hash]; !ok {
+		s.specBlobs[hash] = data
+	}
+	s.specs[name] = hash
 	return nil
 }
 
-func (s *inMemoryJobStore) GetJobSpec(name string) (data []byte, err error) {
+func (s *inMemoryJobStore) GetJobSpec(ctx context.Context, name string) (data []byte, err error) {
 	s.mu.RLock()
-	s.mu.RUnlock()
+	defer s.mu.RUnlock()
+
+	hash, ok := s.specs[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.specBlobs[hash], nil
+}
+
+// StoreRenderedJobSpec stores the job spec of a job as it was rendered from its template
+func (s *inMemoryJobStore) StoreRenderedJobSpec(ctx context.Context, name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.specs[name]; !ok {
+		return ErrNotFound
+	}
+	hash := JobSpecHash(data)
+	if _, ok := s.specBlobs[hash]; !ok {
+		s.specBlobs[hash] = data
+	}
+	s.rendered[name] = hash
+	return nil
+}
+
+// GetRenderedJobSpec retrieves the rendered job spec of a job
+func (s *inMemoryJobStore) GetRenderedJobSpec(ctx context.Context, name string) (data []byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hash, ok := s.rendered[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.specBlobs[hash], nil
+}
+
+// DeleteJobSpec removes the job spec of a job
+func (s *inMemoryJobStore) DeleteJobSpec(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.specs[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.specs, name)
+	delete(s.rendered, name)
+	return nil
+}
+
+// CollectJobSpecs removes the job spec data no job refers to any more
+func (s *inMemoryJobStore) CollectJobSpecs(ctx context.Context) (collected int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	referenced := make(map[string]struct{}, len(s.specs)+len(s.rendered))
+	for _, hash := range s.specs {
+		referenced[hash] = struct{}{}
+	}
+	for _, hash := range s.rendered {
+		referenced[hash] = struct{}{}
+	}
+	for hash := range s.specBlobs {
+		if _, ok := referenced[hash]; ok {
+			continue
+		}
+		delete(s.specBlobs, hash)
+		collected++
+	}
+	return collected, nil
+}
+
+// NewInMemoryNumberGroup creates a new in-memory number group store
+func NewInMemoryNumberGroup() NumberGroup {
+	return &inMemoryNumberGroup{
+		groups: make(map[string]int),
+	}
+}
+
+type inMemoryNumberGroup struct {
+	groups map[string]int
+	mu     sync.Mutex
+}
+
+// Latest returns the latest number of a particular number group
+func (n *inMemoryNumberGroup) Latest(ctx context.Context, group string) (nr int, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nr, ok := n.groups[group]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return nr, nil
+}
+
+// Next returns the next number in the group. Like the postgres store, a new group starts at zero.
+func (n *inMemoryNumberGroup) Next(ctx context.Context, group string) (nr int, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nr, ok := n.groups[group]
+	if ok {
+		nr++
+	}
+	n.groups[group] = nr
+	return nr, nil
+}
+
+// List returns all groups and their latest number ordered by name
+func (n *inMemoryNumberGroup) List(ctx context.Context) ([]*v1.NumberGroup, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	res := make([]*v1.NumberGroup, 0, len(n.groups))
+	for name, nr := range n.groups {
+		res = append(res, &v1.NumberGroup{Name: name, Latest: int64(nr)})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// Reset sets the latest number of a group
+func (n *inMemoryNumberGroup) Reset(ctx context.Context, group string, latest int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.groups[group]; !ok {
+		return ErrNotFound
+	}
+	n.groups[group] = latest
+	return nil
+}
+
+// Delete removes a group
+func (n *inMemoryNumberGroup) Delete(ctx context.Context, group string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.groups[group]; !ok {
+		return ErrNotFound
+	}
+	delete(n.groups, group)
+	return nil
+}
+
+// NewInMemoryPreferences creates a new in-memory preferences store
+func NewInMemoryPreferences() Preferences {
+	return &inMemoryPreferences{
+		starred:  make(map[string][]string),
+		searches: make(map[string]map[string]*v1.SavedSearch),
+	}
+}
+
+type inMemoryPreferences struct {
+	// starred holds the starred jobs of each user, most recently starred last
+	starred  map[string][]string
+	searches map[string]map[string]*v1.SavedSearch
+	mu       sync.RWMutex
+}
+
+// Star adds a job to the starred jobs of a user
+func (p *inMemoryPreferences) Star(ctx context.Context, user, job string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, j := range p.starred[user] {
+		if j == job {
+			return nil
+		}
+	}
+	p.starred[user] = append(p.starred[user], job)
+	return nil
+}
+
+// Unstar removes a job from the starred jobs of a user
+func (p *inMemoryPreferences) Unstar(ctx context.Context, user, job string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	starred := p.starred[user]
+	for i, j := range starred {
+		if j == job {
+			p.starred[user] = append(starred[:i], starred[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Starred returns the names of the jobs a user starred
+func (p *inMemoryPreferences) Starred(ctx context.Context, user string) ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	starred := p.starred[user]
+	res := make([]string, len(starred))
+	for i, j := range starred {
+		res[len(starred)-1-i] = j
+	}
+	return res, nil
+}
+
+// SaveSearch stores a search of a user
+func (p *inMemoryPreferences) SaveSearch(ctx context.Context, user string, search *v1.SavedSearch) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.searches[user]; !ok {
+		p.searches[user] = make(map[string]*v1.SavedSearch)
+	}
+	p.searches[user][search.Name] = search
+	return nil
+}
+
+// DeleteSearch removes a saved search of a user
+func (p *inMemoryPreferences) DeleteSearch(ctx context.Context, user, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.searches[user][name]; !ok {
+		return ErrNotFound
+	}
+	delete(p.searches[user], name)
+	return nil
+}
+
+// Searches returns the saved searches of a user
+func (p *inMemoryPreferences) Searches(ctx context.Context, user string) ([]*v1.SavedSearch, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	res := make([]*v1.SavedSearch, 0, len(p.searches[user]))
+	for _, s := range p.searches[user] {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// NewInMemoryDeployments creates a new in-memory deployment store
+func NewInMemoryDeployments() Deployments {
+	return &inMemoryDeployments{
+		deployments: make(map[string][]*v1.Deployment),
+	}
+}
+
+type inMemoryDeployments struct {
+	// deployments holds the deployments of each environment, most recent last
+	deployments map[string][]*v1.Deployment
+	mu          sync.RWMutex
+}
+
+// Record adds a deployment
+func (d *inMemoryDeployments) Record(ctx context.Context, deployment *v1.Deployment) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deployments[deployment.Environment] = append(d.deployments[deployment.Environment], deployment)
+	return nil
+}
+
+// Current returns the current deployment of each environment
+func (d *inMemoryDeployments) Current(ctx context.Context) ([]*v1.Deployment, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	res := make([]*v1.Deployment, 0, len(d.deployments))
+	for _, ds := range d.deployments {
+		res = append(res, ds[len(ds)-1])
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Environment < res[j].Environment })
+	return res, nil
+}
+
+// History returns the deployments to an environment, most recent first
+func (d *inMemoryDeployments) History(ctx context.Context, environment string, limit int) ([]*v1.Deployment, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ds := d.deployments[environment]
+	if limit == 0 || limit > len(ds) {
+		limit = len(ds)
+	}
+	res := make([]*v1.Deployment, limit)
+	for i := range res {
+		res[i] = ds[len(ds)-1-i]
+	}
+	return res, nil
+}
+
+// NewInMemoryMaintenance creates a new in-memory maintenance store
+func NewInMemoryMaintenance() Maintenance {
+	return &inMemoryMaintenance{}
+}
+
+type inMemoryMaintenance struct {
+	mode  *v1.MaintenanceMode
+	queue []*v1.StartGitHubJobRequest
+	mu    sync.RWMutex
+}
+
+// Get returns the current maintenance mode
+func (m *inMemoryMaintenance) Get(ctx context.Context) (*v1.MaintenanceMode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.mode, nil
+}
+
+// Set stores the maintenance mode
+func (m *inMemoryMaintenance) Set(ctx context.Context, mode *v1.MaintenanceMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mode = mode
+	return nil
+}
+
+// Enqueue adds a job to the queue
+func (m *inMemoryMaintenance) Enqueue(ctx context.Context, req *v1.StartGitHubJobRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queue = append(m.queue, req)
+	return nil
+}
+
+// Queued returns the number of queued jobs
+func (m *inMemoryMaintenance) Queued(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.queue), nil
+}
+
+// Dequeue removes all jobs from the queue
+func (m *inMemoryMaintenance) Dequeue(ctx context.Context) ([]*v1.StartGitHubJobRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := m.queue
+	m.queue = nil
+	return res, nil
+}
+
+// NewInMemoryRepositories creates a new in-memory repository settings store
+func NewInMemoryRepositories() Repositories {
+	return &inMemoryRepositories{
+		repos: make(map[string]*v1.RepositorySettings),
+	}
+}
+
+type inMemoryRepositories struct {
+	repos map[string]*v1.RepositorySettings
+	mu    sync.RWMutex
+}
 
-	data, ok := s.specs[name]
+// Get returns the settings of a repository
+func (r *inMemoryRepositories) Get(ctx context.Context, owner, repo string) (*v1.RepositorySettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res, ok := r.repos[owner+"/"+repo]
 	if !ok {
 		return nil, ErrNotFound
 	}
-	return data, nil
+	return res, nil
+}
+
+// Set registers a repository
+func (r *inMemoryRepositories) Set(ctx context.Context, settings *v1.RepositorySettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.repos[settings.Owner+"/"+settings.Repo] = settings
+	return nil
+}
+
+// Delete removes the settings of a repository
+func (r *inMemoryRepositories) Delete(ctx context.Context, owner, repo string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := owner + "/" + repo
+	if _, ok := r.repos[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.repos, key)
+	return nil
+}
+
+// List returns the settings of all registered repositories
+func (r *inMemoryRepositories) List(ctx context.Context) ([]*v1.RepositorySettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*v1.RepositorySettings, 0, len(r.repos))
+	for _, s := range r.repos {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Owner == res[j].Owner {
+			return res[i].Repo < res[j].Repo
+		}
+		return res[i].Owner < res[j].Owner
+	})
+	return res, nil
+}
+
+// NewInMemoryAttestations creates a new in-memory attestation store
+func NewInMemoryAttestations() Attestations {
+	return &inMemoryAttestations{
+		attestations: make(map[string][]byte),
+	}
+}
+
+type inMemoryAttestations struct {
+	attestations map[string][]byte
+	mu           sync.RWMutex
+}
+
+// Store stores the attestation of a job
+func (a *inMemoryAttestations) Store(ctx context.Context, job string, attestation []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.attestations[job] = attestation
+	return nil
+}
+
+// Get returns the attestation of a job
+func (a *inMemoryAttestations) Get(ctx context.Context, job string) ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	res, ok := a.attestations[job]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return res, nil
+}
+
+// Delete removes the attestation of a job
+func (a *inMemoryAttestations) Delete(ctx context.Context, job string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.attestations[job]; !ok {
+		return ErrNotFound
+	}
+	delete(a.attestations, job)
+	return nil
+}
+
+// NewInMemoryEvents creates a new in-memory event store which keeps the most recent events up to the limit
+func NewInMemoryEvents(limit int) Events {
+	return &inMemoryEvents{limit: limit}
+}
+
+type inMemoryEvents struct {
+	events []*v1.TraceEvent
+	limit  int
+	mu     sync.RWMutex
+}
+
+// Add records an event
+func (e *inMemoryEvents) Add(ctx context.Context, event *v1.TraceEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.events = append(e.events, event)
+	if e.limit > 0 && len(e.events) > e.limit {
+		e.events = e.events[len(e.events)-e.limit:]
+	}
+	return nil
+}
+
+// Find returns the events of a job within a time range
+func (e *inMemoryEvents) Find(ctx context.Context, name string, since, until time.Time, limit int) ([]*v1.TraceEvent, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var res []*v1.TraceEvent
+	for _, evt := range e.events {
+		if name != "" && evt.Name != name {
+			continue
+		}
+		t, err := ptypes.Timestamp(evt.Time)
+		if err != nil {
+			continue
+		}
+		if (!since.IsZero() && t.Before(since)) || (!until.IsZero() && t.After(until)) {
+			continue
+		}
+		res = append(res, evt)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		ti, _ := ptypes.Timestamp(res[i].Time)
+		tj, _ := ptypes.Timestamp(res[j].Time)
+		return ti.Before(tj)
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// Delete removes all events of a job
+func (e *inMemoryEvents) Delete(ctx context.Context, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	events := e.events[:0]
+	for _, evt := range e.events {
+		if evt.Name != name {
+			events = append(events, evt)
+		}
+	}
+	e.events = events
+	return nil
+}
+
+// NewInMemoryStats creates a new in-memory stats store
+func NewInMemoryStats() Stats {
+	return &inMemoryStats{days: make(map[inMemoryStatsKey]map[string]int64)}
+}
+
+// statsDayFormat formats days s.t. they sort in chronological order
+const statsDayFormat = "2006-01-02"
+
+type inMemoryStatsKey struct {
+	Owner string
+	Repo  string
+	Ref   string
+	Day   string
+}
+
+type inMemoryStats struct {
+	days map[inMemoryStatsKey]map[string]int64
+	mu   sync.RWMutex
+}
+
+// Add adds to the counters of a repository and ref on a day
+func (s *inMemoryStats) Add(ctx context.Context, owner, repo, ref string, t time.Time, counters map[string]int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := inMemoryStatsKey{Owner: owner, Repo: repo, Ref: ref, Day: t.UTC().Format(statsDayFormat)}
+	day, ok := s.days[key]
+	if !ok {
+		day = make(map[string]int64)
+		s.days[key] = day
+	}
+	for c, v := range counters {
+		day[c] += v
+	}
+	return nil
+}
+
+// Sum sums up the counters of each repository and ref within a time range
+func (s *inMemoryStats) Sum(ctx context.Context, owner, repo, ref string, since, until time.Time) ([]*StatsSum, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := since.UTC().Format(statsDayFormat), until.UTC().Format(statsDayFormat)
+	sums := make(map[inMemoryStatsKey]*StatsSum)
+	for key, counters := range s.days {
+		if key.Owner != owner || (repo != "" && key.Repo != repo) || (ref != "" && key.Ref != ref) {
+			continue
+		}
+		if key.Day < from || key.Day > to {
+			continue
+		}
+
+		sk := inMemoryStatsKey{Owner: key.Owner, Repo: key.Repo, Ref: key.Ref}
+		sum, ok := sums[sk]
+		if !ok {
+			sum = &StatsSum{Owner: key.Owner, Repo: key.Repo, Ref: key.Ref, Counters: make(map[string]int64)}
+			sums[sk] = sum
+		}
+		for c, v := range counters {
+			sum.Counters[c] += v
+		}
+	}
+
+	res := make([]*StatsSum, 0, len(sums))
+	for _, sum := range sums {
+		res = append(res, sum)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Owner+"/"+res[i].Repo+"@"+res[i].Ref < res[j].Owner+"/"+res[j].Repo+"@"+res[j].Ref
+	})
+	return res, nil
+}
+
+// NewInMemoryServiceAccountTokens creates a new in-memory service account token store
+func NewInMemoryServiceAccountTokens() ServiceAccountTokens {
+	return &inMemoryServiceAccountTokens{
+		tokens: make(map[string]*ServiceAccountToken),
+	}
+}
+
+type inMemoryServiceAccountTokens struct {
+	tokens map[string]*ServiceAccountToken
+	mu     sync.RWMutex
+}
+
+// Create stores a new token
+func (s *inMemoryServiceAccountTokens) Create(ctx context.Context, token ServiceAccountToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tokens[token.ID]; exists {
+		return xerrors.Errorf("token %s exists already", token.ID)
+	}
+	token.Scopes = append([]string(nil), token.Scopes...)
+	s.tokens[token.ID] = &token
+	return nil
+}
+
+// Get returns a token by its ID
+func (s *inMemoryServiceAccountTokens) Get(ctx context.Context, id string) (*ServiceAccountToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	res := *t
+	return &res, nil
+}
+
+// GetByHash returns the token with the hash
+func (s *inMemoryServiceAccountTokens) GetByHash(ctx context.Context, hash string) (*ServiceAccountToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.tokens {
+		if t.Hash == hash {
+			res := *t
+			return &res, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// List returns the tokens of a service account
+func (s *inMemoryServiceAccountTokens) List(ctx context.Context, serviceAccount string) ([]*ServiceAccountToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []*ServiceAccountToken
+	for _, t := range s.tokens {
+		if serviceAccount != "" && t.ServiceAccount != serviceAccount {
+			continue
+		}
+		c := *t
+		res = append(res, &c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Created.Equal(res[j].Created) {
+			return res[i].ID < res[j].ID
+		}
+		return res[i].Created.Before(res[j].Created)
+	})
+	return res, nil
+}
+
+// Expire sets the time a token stops working
+func (s *inMemoryServiceAccountTokens) Expire(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		return ErrNotFound
+	}
+	t.Expires = at
+	return nil
+}
+
+// Touch records the time a token was last used
+func (s *inMemoryServiceAccountTokens) Touch(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		return ErrNotFound
+	}
+	t.LastUsed = at
+	return nil
+}
+
+// Delete removes a token
+func (s *inMemoryServiceAccountTokens) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tokens[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.tokens, id)
+	return nil
+}
+
+// Prune removes all tokens which expired before the time
+func (s *inMemoryServiceAccountTokens) Prune(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for id, t := range s.tokens {
+		if t.Expires.IsZero() || !t.Expires.Before(before) {
+			continue
+		}
+		delete(s.tokens, id)
+		n++
+	}
+	return n, nil
+}
+
+// NewInMemoryWebhookDeliveries creates a new in-memory webhook delivery store
+func NewInMemoryWebhookDeliveries() WebhookDeliveries {
+	return &inMemoryWebhookDeliveries{
+		deliveries: make(map[string]*WebhookDelivery),
+	}
+}
+
+type inMemoryWebhookDeliveries struct {
+	deliveries map[string]*WebhookDelivery
+	mu         sync.RWMutex
+}
+
+// Add stores a delivery
+func (s *inMemoryWebhookDeliveries) Add(ctx context.Context, delivery WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.deliveries[delivery.ID]; exists {
+		return xerrors.Errorf("delivery %s exists already", delivery.ID)
+	}
+	delivery.Jobs = append([]string(nil), delivery.Jobs...)
+	delivery.Payload = append([]byte(nil), delivery.Payload...)
+	headers := make(map[string]string, len(delivery.Headers))
+	for k, v := range delivery.Headers {
+		headers[k] = v
+	}
+	delivery.Headers = headers
+	s.deliveries[delivery.ID] = &delivery
+	return nil
+}
+
+// Get returns a delivery including its payload
+func (s *inMemoryWebhookDeliveries) Get(ctx context.Context, id string) (*WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	res := *d
+	return &res, nil
+}
+
+// List returns the deliveries the filter selects, newest first
+func (s *inMemoryWebhookDeliveries) List(ctx context.Context, filter WebhookDeliveryFilter, limit int) ([]*WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []*WebhookDelivery
+	for _, d := range s.deliveries {
+		if !filter.Matches(d) {
+			continue
+		}
+		c := *d
+		c.Payload = nil
+		res = append(res, &c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Received.Equal(res[j].Received) {
+			return res[i].ID > res[j].ID
+		}
+		return res[i].Received.After(res[j].Received)
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// Prune removes all deliveries received before the time
+func (s *inMemoryWebhookDeliveries) Prune(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for id, d := range s.deliveries {
+		if !d.Received.Before(before) {
+			continue
+		}
+		delete(s.deliveries, id)
+		n++
+	}
+	return n, nil
 }
@@ -0,0 +1,485 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestInMemoryJobStoreFind(t *testing.T) {
+	s := store.NewInMemoryJobStore()
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, owner := range []string{"b", "a", "b", "a", "c"} {
+		created, _ := ptypes.TimestampProto(base.Add(time.Duration(i) * time.Minute))
+		err := s.Store(context.Background(), v1.JobStatus{
+			Name:     fmt.Sprintf("job-%d", i),
+			Metadata: &v1.JobMetadata{Owner: owner, Created: created},
+		})
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+
+	tests := []struct {
+		Name   string
+		Filter []*v1.FilterExpression
+		Order  []*v1.OrderExpression
+		Start  int
+		Limit  int
+		Result string
+		Total  int
+		Error  string
+	}{
+		{
+			Name:   "created ascending",
+			Order:  []*v1.OrderExpression{{Field: "created", Ascending: true}},
+			Result: "job-0 job-1 job-2 job-3 job-4",
+			Total:  5,
+		},
+		{
+			Name:   "owner then created descending",
+			Order:  []*v1.OrderExpression{{Field: "owner", Ascending: true}, {Field: "created"}},
+			Result: "job-3 job-1 job-2 job-0 job-4",
+			Total:  5,
+		},
+		{
+			Name:   "paged",
+			Order:  []*v1.OrderExpression{{Field: "created"}},
+			Start:  1,
+			Limit:  2,
+			Result: "job-3 job-2",
+			Total:  5,
+		},
+		{
+			Name:   "start beyond results",
+			Order:  []*v1.OrderExpression{{Field: "created"}},
+			Start:  10,
+			Result: "",
+			Total:  5,
+		},
+		{
+			Name:   "filtered",
+			Filter: []*v1.FilterExpression{{Terms: []*v1.FilterTerm{{Field: "owner", Value: "a"}}}},
+			Order:  []*v1.OrderExpression{{Field: "name", Ascending: true}},
+			Limit:  1,
+			Result: "job-1",
+			Total:  2,
+		},
+		{
+			Name:  "unknown field",
+			Order: []*v1.OrderExpression{{Field: "foo"}},
+			Error: "unknown field foo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, total, err := s.Find(context.Background(), test.Filter, test.Order, test.Start, test.Limit)
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Errorf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			names := make([]string, len(res))
+			for i, js := range res {
+				names[i] = js.Name
+			}
+			if act := strings.Join(names, " "); act != test.Result {
+				t.Errorf("expected %q, got %q", test.Result, act)
+			}
+			if total != test.Total {
+				t.Errorf("expected total of %d, got %d", test.Total, total)
+			}
+		})
+	}
+}
+
+func TestInMemoryJobStoreSpecs(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryJobStore()
+	specs := map[string]string{
+		"a": "pod: {}",
+		"b": "pod: {}",
+		"c": "pod: {containers: []}",
+	}
+	for name, spec := range specs {
+		err := s.Store(ctx, v1.JobStatus{Name: name})
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+		err = s.StoreJobSpec(ctx, name, []byte(spec))
+		if err != nil {
+			t.Fatalf("cannot store job spec: %v", err)
+		}
+	}
+
+	tests := []struct {
+		Delete    string
+		SpecOnly  bool
+		Collected int
+	}{
+		// b still refers to the spec of a
+		{"a", false, 0},
+		{"b", false, 1},
+		// archived jobs keep their status but lose their spec
+		{"c", true, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.Delete, func(t *testing.T) {
+			if test.SpecOnly {
+				err := s.DeleteJobSpec(ctx, test.Delete)
+				if err != nil {
+					t.Fatalf("cannot delete job spec: %v", err)
+				}
+				if _, err := s.Get(ctx, test.Delete); err != nil {
+					t.Errorf("expected job to remain after deleting its spec, got %v", err)
+				}
+			} else {
+				err := s.Delete(ctx, test.Delete)
+				if err != nil {
+					t.Fatalf("cannot delete job: %v", err)
+				}
+			}
+			if _, err := s.GetJobSpec(ctx, test.Delete); err != store.ErrNotFound {
+				t.Errorf("expected ErrNotFound for the spec of a deleted job, got %v", err)
+			}
+
+			collected, err := s.CollectJobSpecs(ctx)
+			if err != nil {
+				t.Fatalf("cannot collect job specs: %v", err)
+			}
+			if collected != test.Collected {
+				t.Errorf("expected %d collected job specs, got %d", test.Collected, collected)
+			}
+
+			for name, spec := range specs {
+				data, err := s.GetJobSpec(ctx, name)
+				if err == store.ErrNotFound {
+					continue
+				}
+				if string(data) != spec {
+					t.Errorf("expected spec of %s to be %q, got %q (err: %v)", name, spec, string(data), err)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryJobStoreRenderedSpecs(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryJobStore()
+	if err := s.StoreRenderedJobSpec(ctx, "a", []byte("pod: {}")); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for a job without spec, got %v", err)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		err := s.Store(ctx, v1.JobStatus{Name: name})
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+		err = s.StoreJobSpec(ctx, name, []byte("pod: {{ .Name }}"))
+		if err != nil {
+			t.Fatalf("cannot store job spec: %v", err)
+		}
+		err = s.StoreRenderedJobSpec(ctx, name, []byte("pod: "+name))
+		if err != nil {
+			t.Fatalf("cannot store rendered job spec: %v", err)
+		}
+	}
+
+	if data, err := s.GetRenderedJobSpec(ctx, "a"); err != nil || string(data) != "pod: a" {
+		t.Errorf("expected rendered spec \"pod: a\", got %q (err: %v)", string(data), err)
+	}
+	if collected, err := s.CollectJobSpecs(ctx); err != nil || collected != 0 {
+		t.Errorf("expected rendered specs to be kept, got %d collected (err: %v)", collected, err)
+	}
+
+	if err := s.DeleteJobSpec(ctx, "a"); err != nil {
+		t.Fatalf("cannot delete job spec: %v", err)
+	}
+	if _, err := s.GetRenderedJobSpec(ctx, "a"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for the rendered spec of a job without spec, got %v", err)
+	}
+	if collected, err := s.CollectJobSpecs(ctx); err != nil || collected != 1 {
+		t.Errorf("expected the rendered spec of a to be collected, got %d collected (err: %v)", collected, err)
+	}
+	if data, err := s.GetRenderedJobSpec(ctx, "b"); err != nil || string(data) != "pod: b" {
+		t.Errorf("expected rendered spec \"pod: b\", got %q (err: %v)", string(data), err)
+	}
+}
+
+func TestInMemoryNumberGroup(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryNumberGroup()
+	if _, err := s.Latest(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for unknown group, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		nr, err := s.Next(ctx, "foo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if nr != i {
+			t.Errorf("expected %d, got %d", i, nr)
+		}
+	}
+	if nr, _ := s.Latest(ctx, "foo"); nr != 2 {
+		t.Errorf("expected latest number 2, got %d", nr)
+	}
+	if nr, _ := s.Next(ctx, "bar"); nr != 0 {
+		t.Errorf("expected new group to start at 0, got %d", nr)
+	}
+}
+
+func TestInMemoryNumberGroupMaintenance(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryNumberGroup()
+	s.Next(ctx, "foo")
+	s.Next(ctx, "foo")
+	s.Next(ctx, "bar")
+
+	groups, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []*v1.NumberGroup{{Name: "bar", Latest: 0}, {Name: "foo", Latest: 1}}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, got %v", expected, groups)
+	}
+
+	if err := s.Reset(ctx, "foo", 41); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nr, _ := s.Next(ctx, "foo"); nr != 42 {
+		t.Errorf("expected 42 after reset, got %d", nr)
+	}
+	if err := s.Reset(ctx, "baz", 1); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound when resetting an unknown group, got %v", err)
+	}
+
+	if err := s.Delete(ctx, "foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Delete(ctx, "foo"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound when deleting a deleted group, got %v", err)
+	}
+	if nr, _ := s.Next(ctx, "foo"); nr != 0 {
+		t.Errorf("expected deleted group to start at 0 again, got %d", nr)
+	}
+}
+
+func TestInMemoryLogStore(t *testing.T) {
+	s := store.NewInMemoryLogStore()
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	if _, err := s.Open("foo"); err != store.ErrAlreadyExists {
+		t.Errorf("expected ErrAlreadyExists when opening an open log, got %v", err)
+	}
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+
+	read := make(chan string)
+	go func() {
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("cannot read log: %v", err)
+		}
+		read <- string(content)
+	}()
+
+	lines := []string{"hello world\n", "this is a test\n", "line by line"}
+	for _, l := range lines {
+		_, err := w.Write([]byte(l))
+		if err != nil {
+			t.Fatalf("cannot write log: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := s.Delete("foo"); err == nil {
+		t.Errorf("expected an error when deleting a log which is being written")
+	}
+	w.Close()
+
+	select {
+	case content := <-read:
+		if exp := strings.Join(lines, ""); content != exp {
+			t.Errorf("expected %q, got %q", exp, content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("reader did not finish after the log was closed")
+	}
+
+	if err := s.Delete("foo"); err != nil {
+		t.Errorf("cannot delete log: %v", err)
+	}
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for a deleted log, got %v", err)
+	}
+}
+
+func TestInMemoryServiceAccountTokens(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryServiceAccountTokens()
+	created := time.Date(2020, 4, 11, 9, 0, 0, 0, time.UTC)
+	tokens := []store.ServiceAccountToken{
+		{ID: "a", ServiceAccount: "deploy-bot", Hash: "hash-a", Scopes: []string{"trigger:repo/foo"}, Created: created},
+		{ID: "b", ServiceAccount: "deploy-bot", Hash: "hash-b", Scopes: []string{"trigger"}, Created: created.Add(time.Hour)},
+		{ID: "c", ServiceAccount: "ci", Hash: "hash-c", Scopes: []string{"admin"}, Created: created},
+	}
+	for _, tkn := range tokens {
+		if err := s.Create(ctx, tkn); err != nil {
+			t.Fatalf("cannot create token: %v", err)
+		}
+	}
+	if err := s.Create(ctx, tokens[0]); err == nil {
+		t.Errorf("expected an error when creating a token twice")
+	}
+
+	tests := []struct {
+		ServiceAccount string
+		Expectation    []string
+	}{
+		{"", []string{"a", "c", "b"}},
+		{"deploy-bot", []string{"a", "b"}},
+		{"unknown", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.ServiceAccount, func(t *testing.T) {
+			res, err := s.List(ctx, test.ServiceAccount)
+			if err != nil {
+				t.Fatalf("cannot list tokens: %v", err)
+			}
+			var ids []string
+			for _, tkn := range res {
+				ids = append(ids, tkn.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, ids)
+			}
+		})
+	}
+
+	tkn, err := s.GetByHash(ctx, "hash-b")
+	if err != nil || tkn.ID != "b" {
+		t.Fatalf("expected token b, got %v (err: %v)", tkn, err)
+	}
+	used := created.Add(2 * time.Hour)
+	if err := s.Touch(ctx, "b", used); err != nil {
+		t.Fatalf("cannot touch token: %v", err)
+	}
+	if err := s.Expire(ctx, "b", used.Add(time.Hour)); err != nil {
+		t.Fatalf("cannot expire token: %v", err)
+	}
+	tkn, _ = s.Get(ctx, "b")
+	if !tkn.LastUsed.Equal(used) {
+		t.Errorf("expected token to be last used at %v, got %v", used, tkn.LastUsed)
+	}
+	if tkn.Expired(used) || !tkn.Expired(used.Add(time.Hour)) {
+		t.Errorf("expected token to expire at %v, got %v", used.Add(time.Hour), tkn.Expires)
+	}
+
+	if err := s.Delete(ctx, "b"); err != nil {
+		t.Fatalf("cannot delete token: %v", err)
+	}
+	if _, err := s.GetByHash(ctx, "hash-b"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for a deleted token, got %v", err)
+	}
+	if err := s.Touch(ctx, "b", used); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound when touching a deleted token, got %v", err)
+	}
+
+	// tokens are pruned once they expired, while those which never expire remain
+	if err := s.Expire(ctx, "a", used); err != nil {
+		t.Fatalf("cannot expire token: %v", err)
+	}
+	if n, err := s.Prune(ctx, used); err != nil || n != 0 {
+		t.Errorf("expected no token to be pruned before it expired, got %d (err: %v)", n, err)
+	}
+	if n, err := s.Prune(ctx, used.Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("expected one pruned token, got %d (err: %v)", n, err)
+	}
+	if _, err := s.Get(ctx, "c"); err != nil {
+		t.Errorf("expected token which never expires to remain, got %v", err)
+	}
+}
+
+func TestInMemoryWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryWebhookDeliveries()
+	received := time.Date(2020, 5, 2, 9, 0, 0, 0, time.UTC)
+	deliveries := []store.WebhookDelivery{
+		{ID: "a", Repository: "32leaves/werft", Received: received, Payload: []byte("{}"), Jobs: []string{"werft-build-master.1"}},
+		{ID: "b", Repository: "32leaves/werft", Received: received.Add(time.Hour), Payload: []byte("{}"), SkipReason: "skipped"},
+		{ID: "c", Repository: "32leaves/other", Received: received.Add(2 * time.Hour), Payload: []byte("{}")},
+	}
+	for _, d := range deliveries {
+		if err := s.Add(ctx, d); err != nil {
+			t.Fatalf("cannot add delivery: %v", err)
+		}
+	}
+	if err := s.Add(ctx, deliveries[0]); err == nil {
+		t.Errorf("expected an error when adding a delivery twice")
+	}
+
+	tests := []struct {
+		Name        string
+		Filter      store.WebhookDeliveryFilter
+		Limit       int
+		Expectation []string
+	}{
+		{"all", store.WebhookDeliveryFilter{}, 0, []string{"c", "b", "a"}},
+		{"limit", store.WebhookDeliveryFilter{}, 2, []string{"c", "b"}},
+		{"repository", store.WebhookDeliveryFilter{Repository: "32leaves/werft"}, 0, []string{"b", "a"}},
+		{"without jobs", store.WebhookDeliveryFilter{Repository: "32leaves/werft", WithoutJobs: true}, 0, []string{"b"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := s.List(ctx, test.Filter, test.Limit)
+			if err != nil {
+				t.Fatalf("cannot list deliveries: %v", err)
+			}
+			var ids []string
+			for _, d := range res {
+				ids = append(ids, d.ID)
+				if d.Payload != nil {
+					t.Errorf("expected delivery %s to be listed without payload", d.ID)
+				}
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, ids)
+			}
+		})
+	}
+
+	d, err := s.Get(ctx, "b")
+	if err != nil || string(d.Payload) != "{}" || d.SkipReason != "skipped" {
+		t.Fatalf("expected delivery b with payload, got %v (err: %v)", d, err)
+	}
+	if _, err := s.Get(ctx, "unknown"); err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for an unknown delivery, got %v", err)
+	}
+
+	if n, err := s.Prune(ctx, received.Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("expected one pruned delivery, got %d (err: %v)", n, err)
+	}
+	if _, err := s.Get(ctx, "a"); err != store.ErrNotFound {
+		t.Errorf("expected delivery a to be pruned, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+// Attestations stores job attestations in a Postgres database
+type Attestations struct {
+	DB *sql.DB
+}
+
+// NewAttestations creates a new SQL attestation store
+func NewAttestations(db *sql.DB) (*Attestations, error) {
+	return &Attestations{DB: db}, nil
+}
+
+// Store stores the attestation of a job
+func (a *Attestations) Store(ctx context.Context, job string, attestation []byte) error {
+	_, err := retryExec(ctx, a.DB, `
+		INSERT
+		INTO   attestation (job_name, data)
+		VALUES             ($1,       $2  )
+		ON CONFLICT (job_name) DO UPDATE
+			SET data = $2`,
+		job, attestation,
+	)
+	return err
+}
+
+// Get returns the attestation of a job
+func (a *Attestations) Get(ctx context.Context, job string) ([]byte, error) {
+	var data []byte
+	err := retry(ctx, func() error {
+		return a.DB.QueryRowContext(ctx, "SELECT data FROM attestation WHERE job_name = $1", job).Scan(&data)
+	})
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete removes the attestation of a job
+func (a *Attestations) Delete(ctx context.Context, job string) error {
+	res, err := retryExec(ctx, a.DB, "DELETE FROM attestation WHERE job_name = $1", job)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"time"
+
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultMaxOpenConns limits the connections to the database unless configured otherwise
+	defaultMaxOpenConns = 20
+	// defaultMaxIdleConns is the number of idle connections we keep unless configured otherwise
+	defaultMaxIdleConns = 5
+	// defaultConnMaxLifetime is how long we use a connection unless configured otherwise
+	defaultConnMaxLifetime = 30 * time.Minute
+	// defaultConnectTimeout is how long we wait for the database on start-up unless configured otherwise
+	defaultConnectTimeout = 30 * time.Second
+
+	// retryAttempts is how often we run an operation which fails with a transient error
+	retryAttempts = 4
+	// retryBackoff is how long we wait before the first retry. The wait doubles with every retry.
+	retryBackoff = 100 * time.Millisecond
+)
+
+// PoolConfig configures the pool of connections to the database
+type PoolConfig struct {
+	// MaxOpenConns limits the number of open connections. Defaults to 20.
+	MaxOpenConns int `yaml:"maxOpenConns,omitempty"`
+	// MaxIdleConns is the number of idle connections we keep open. Defaults to 5.
+	MaxIdleConns int `yaml:"maxIdleConns,omitempty"`
+	// ConnMaxLifetime is how long we use a connection before we replace it, e.g. 30m. Defaults to 30m.
+	ConnMaxLifetime string `yaml:"connMaxLifetime,omitempty"`
+	// ConnectTimeout is how long we wait for the database to become available on start-up, e.g. 1m. Defaults to 30s.
+	ConnectTimeout string `yaml:"connectTimeout,omitempty"`
+}
+
+// Validate checks the pool config for mistakes
+func (c PoolConfig) Validate() error {
+	if c.MaxOpenConns < 0 {
+		return xerrors.Errorf("maxOpenConns: must not be negative")
+	}
+	if c.MaxIdleConns < 0 {
+		return xerrors.Errorf("maxIdleConns: must not be negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return xerrors.Errorf("maxIdleConns: must not exceed maxOpenConns")
+	}
+	if _, err := parseDuration(c.ConnMaxLifetime, defaultConnMaxLifetime); err != nil {
+		return xerrors.Errorf("connMaxLifetime: %w", err)
+	}
+	if _, err := parseDuration(c.ConnectTimeout, defaultConnectTimeout); err != nil {
+		return xerrors.Errorf("connectTimeout: %w", err)
+	}
+	return nil
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, xerrors.Errorf("\"%s\" is not a positive duration, e.g. 30s", s)
+	}
+	return d, nil
+}
+
+// Open connects to the database and configures its connection pool. If the database is not reachable yet,
+// e.g. because it starts alongside werft, Open waits for it until the connect timeout.
+func Open(connectionString string, cfg PoolConfig) (*sql.DB, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	maxLifetime, _ := parseDuration(cfg.ConnMaxLifetime, defaultConnMaxLifetime)
+	connectTimeout, _ := parseDuration(cfg.ConnectTimeout, defaultConnectTimeout)
+
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+	maxOpen, maxIdle := cfg.MaxOpenConns, cfg.MaxIdleConns
+	if maxOpen == 0 {
+		maxOpen = defaultMaxOpenConns
+	}
+	if maxIdle == 0 {
+		maxIdle = defaultMaxIdleConns
+		if maxIdle > maxOpen {
+			maxIdle = maxOpen
+		}
+	}
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(maxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	for {
+		err = db.PingContext(ctx)
+		if err == nil {
+			return db, nil
+		}
+		if !isTransient(err) {
+			db.Close()
+			return nil, err
+		}
+
+		log.WithError(err).Info("waiting for the database")
+		select {
+		case <-ctx.Done():
+			db.Close()
+			return nil, xerrors.Errorf("database did not become available within %s: %w", connectTimeout, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// transientErrorCodes are the Postgres errors which guarantee that a statement had no effect,
+// and which may not happen again if we retry the statement.
+var transientErrorCodes = map[pq.ErrorCode]struct{}{
+	"40001": {}, // serialization_failure
+	"40P01": {}, // deadlock_detected
+	"53300": {}, // too_many_connections
+	"57P03": {}, // cannot_connect_now, e.g. while the database starts
+	"08001": {}, // sqlclient_unable_to_establish_sqlconnection
+	"08004": {}, // sqlserver_rejected_establishment_of_sqlconnection
+}
+
+// isTransient returns true if an operation failed without effect and might succeed when retried.
+// We are conservative here: errors which leave it unclear whether a write happened are not transient.
+func isTransient(err error) bool {
+	if xerrors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if xerrors.As(err, &pqErr) {
+		_, ok := transientErrorCodes[pqErr.Code]
+		return ok
+	}
+
+	// we cannot have sent anything if we could not connect in the first place
+	var opErr *net.OpError
+	if xerrors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+	return false
+}
+
+// retry runs op until it succeeds, fails with an error which is not transient, or failed retryAttempts times.
+// Because only errors which guarantee that op had no effect are transient, retrying writes is safe.
+func retry(ctx context.Context, op func() error) (err error) {
+	backoff := retryBackoff
+	for i := 0; ; i++ {
+		err = op()
+		if err == nil || !isTransient(err) || i == retryAttempts-1 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryTx runs op in a transaction which it commits if op succeeds, and retries the whole transaction on transient errors
+func retryTx(ctx context.Context, db *sql.DB, op func(tx *sql.Tx) error) error {
+	return retry(ctx, func() error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = op(tx)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// retryExec runs a statement and retries it on transient errors
+func retryExec(ctx context.Context, db *sql.DB, query string, args ...interface{}) (res sql.Result, err error) {
+	err = retry(ctx, func() error {
+		res, err = db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+// retryQuery runs a query and retries it on transient errors. Callers must close the rows.
+func retryQuery(ctx context.Context, db *sql.DB, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = retry(ctx, func() error {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// Health describes the connection to the database
+type Health struct {
+	// Error is why the database is not reachable, or empty if it is
+	Error string `json:"error,omitempty"`
+
+	OpenConnections int    `json:"openConnections"`
+	InUse           int    `json:"inUse"`
+	Idle            int    `json:"idle"`
+	WaitCount       int64  `json:"waitCount"`
+	WaitDuration    string `json:"waitDuration"`
+}
+
+// CheckHealth pings the database and reports the state of the connection pool.
+// We don't retry the ping: the health reflects whether the database is reachable right now.
+func CheckHealth(ctx context.Context, db *sql.DB) Health {
+	stats := db.Stats()
+	res := Health{
+		OpenConnections: stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		WaitCount:       stats.WaitCount,
+		WaitDuration:    stats.WaitDuration.String(),
+	}
+	err := db.PingContext(ctx)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
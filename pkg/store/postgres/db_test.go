@@ -0,0 +1,129 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store/postgres"
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Err         error
+		Expectation bool
+	}{
+		{"no error", nil, false},
+		{"bad connection", driver.ErrBadConn, true},
+		{"wrapped bad connection", xerrors.Errorf("cannot store job: %w", driver.ErrBadConn), true},
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"deadlock", &pq.Error{Code: "40P01"}, true},
+		{"too many connections", &pq.Error{Code: "53300"}, true},
+		{"database starting", &pq.Error{Code: "57P03"}, true},
+		{"unable to establish connection", &pq.Error{Code: "08001"}, true},
+		{"connection rejected", &pq.Error{Code: "08004"}, true},
+		{"wrapped serialization failure", xerrors.Errorf("cannot store job: %w", &pq.Error{Code: "40001"}), true},
+		// the connection broke while the statement ran - it may or may not have been committed
+		{"connection failure", &pq.Error{Code: "08006"}, false},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"syntax error", &pq.Error{Code: "42601"}, false},
+		{"dial", &net.OpError{Op: "dial", Err: xerrors.Errorf("connection refused")}, true},
+		{"read", &net.OpError{Op: "read", Err: xerrors.Errorf("connection reset by peer")}, false},
+		{"no rows", sql.ErrNoRows, false},
+		{"canceled", context.Canceled, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := postgres.IsTransient(test.Err)
+			if act != test.Expectation {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var (
+		transient = &pq.Error{Code: "40001"}
+		permanent = &pq.Error{Code: "23505"}
+	)
+	tests := []struct {
+		Name     string
+		Errs     []error
+		Attempts int
+		Err      error
+	}{
+		{"success", []error{nil}, 1, nil},
+		{"transient then success", []error{transient, driver.ErrBadConn, nil}, 3, nil},
+		{"permanent", []error{permanent, nil}, 1, permanent},
+		{"transient then permanent", []error{transient, permanent, nil}, 2, permanent},
+		{"gives up", []error{transient, transient, transient, transient, nil}, postgres.RetryAttempts, transient},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var attempts int
+			err := postgres.Retry(context.Background(), func() error {
+				err := test.Errs[attempts]
+				attempts++
+				return err
+			})
+			if err != test.Err {
+				t.Errorf("expected error %v, actual %v", test.Err, err)
+			}
+			if attempts != test.Attempts {
+				t.Errorf("expected %d attempts, actual %d", test.Attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var attempts int
+	err := postgres.Retry(ctx, func() error {
+		attempts++
+		return driver.ErrBadConn
+	})
+	if err != driver.ErrBadConn {
+		t.Errorf("expected error %v, actual %v", driver.ErrBadConn, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, actual %d", attempts)
+	}
+}
+
+func TestPoolConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config postgres.PoolConfig
+		Valid  bool
+	}{
+		{"defaults", postgres.PoolConfig{}, true},
+		{"complete", postgres.PoolConfig{MaxOpenConns: 10, MaxIdleConns: 2, ConnMaxLifetime: "1h", ConnectTimeout: "1m"}, true},
+		{"idle without open", postgres.PoolConfig{MaxIdleConns: 50}, true},
+		{"negative open", postgres.PoolConfig{MaxOpenConns: -1}, false},
+		{"negative idle", postgres.PoolConfig{MaxIdleConns: -1}, false},
+		{"more idle than open", postgres.PoolConfig{MaxOpenConns: 2, MaxIdleConns: 3}, false},
+		{"invalid lifetime", postgres.PoolConfig{ConnMaxLifetime: "forever"}, false},
+		{"zero lifetime", postgres.PoolConfig{ConnMaxLifetime: "0s"}, false},
+		{"negative connect timeout", postgres.PoolConfig{ConnectTimeout: "-1m"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if valid := err == nil; valid != test.Valid {
+				t.Errorf("expected valid %v, actual error %v", test.Valid, err)
+			}
+		})
+	}
+}
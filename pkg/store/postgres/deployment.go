@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Deployments stores deployments in a Postgres database
+type Deployments struct {
+	DB *sql.DB
+}
+
+// NewDeployments creates a new SQL deployment store
+func NewDeployments(db *sql.DB) (*Deployments, error) {
+	return &Deployments{DB: db}, nil
+}
+
+// Record adds a deployment
+func (d *Deployments) Record(ctx context.Context, deployment *v1.Deployment) error {
+	data, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(deployment)
+	if err != nil {
+		return err
+	}
+
+	deployed := time.Now()
+	if deployment.Deployed != nil {
+		deployed, err = ptypes.Timestamp(deployment.Deployed)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = retryExec(ctx, d.DB, `
+		INSERT
+		INTO   deployment (environment, deployed, data)
+		VALUES            ($1         , $2      , $3  )`,
+		deployment.Environment, deployed.Unix(), data,
+	)
+	return err
+}
+
+// Current returns the current deployment of each environment
+func (d *Deployments) Current(ctx context.Context) ([]*v1.Deployment, error) {
+	rows, err := retryQuery(ctx, d.DB, `
+		SELECT DISTINCT ON (environment) data
+		FROM   deployment
+		ORDER BY environment ASC, id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeployments(rows)
+}
+
+// History returns the deployments to an environment, most recent first
+func (d *Deployments) History(ctx context.Context, environment string, limit int) ([]*v1.Deployment, error) {
+	// LIMIT NULL is the same as omitting the limit
+	lim := sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+	rows, err := retryQuery(ctx, d.DB, "SELECT data FROM deployment WHERE environment = $1 ORDER BY id DESC LIMIT $2", environment, lim)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeployments(rows)
+}
+
+func scanDeployments(rows *sql.Rows) ([]*v1.Deployment, error) {
+	defer rows.Close()
+
+	var res []*v1.Deployment
+	for rows.Next() {
+		var data string
+		err := rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		var deployment v1.Deployment
+		err = jsonpb.UnmarshalString(data, &deployment)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &deployment)
+	}
+	return res, rows.Err()
+}
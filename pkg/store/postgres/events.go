@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Events stores the event trace in a Postgres database
+type Events struct {
+	DB *sql.DB
+}
+
+// NewEvents creates a new SQL event store
+func NewEvents(db *sql.DB) (*Events, error) {
+	return &Events{DB: db}, nil
+}
+
+// Add records an event
+func (e *Events) Add(ctx context.Context, event *v1.TraceEvent) error {
+	status, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(event.Status)
+	if err != nil {
+		return err
+	}
+	t, err := ptypes.Timestamp(event.Time)
+	if err != nil {
+		return err
+	}
+
+	_, err = retryExec(ctx, e.DB, `
+		INSERT
+		INTO   event_trace (job_name, time, status, pod)
+		VALUES             ($1      , $2  , $3    , $4 )`,
+		event.Name, t.UnixNano(), status, event.Pod,
+	)
+	return err
+}
+
+// Find returns the events of a job within a time range, oldest first
+func (e *Events) Find(ctx context.Context, name string, since, until time.Time, limit int) ([]*v1.TraceEvent, error) {
+	var (
+		from int64 = math.MinInt64
+		to   int64 = math.MaxInt64
+	)
+	if !since.IsZero() {
+		from = since.UnixNano()
+	}
+	if !until.IsZero() {
+		to = until.UnixNano()
+	}
+	// LIMIT NULL is the same as omitting the limit
+	lim := sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+
+	rows, err := retryQuery(ctx, e.DB, `
+		SELECT   job_name, time, status, pod
+		FROM     event_trace
+		WHERE    ($1 = '' OR job_name = $1) AND time >= $2 AND time <= $3
+		ORDER BY time ASC, id ASC
+		LIMIT    $4`,
+		name, from, to, lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*v1.TraceEvent
+	for rows.Next() {
+		var (
+			evt    v1.TraceEvent
+			t      int64
+			status string
+		)
+		err := rows.Scan(&evt.Name, &t, &status, &evt.Pod)
+		if err != nil {
+			return nil, err
+		}
+		evt.Time, err = ptypes.TimestampProto(time.Unix(0, t))
+		if err != nil {
+			return nil, err
+		}
+		var s v1.JobStatus
+		err = jsonpb.UnmarshalString(status, &s)
+		if err != nil {
+			return nil, err
+		}
+		evt.Status = &s
+		res = append(res, &evt)
+	}
+	return res, rows.Err()
+}
+
+// Delete removes all events of a job
+func (e *Events) Delete(ctx context.Context, name string) error {
+	_, err := retryExec(ctx, e.DB, "DELETE FROM event_trace WHERE job_name = $1", name)
+	return err
+}
@@ -0,0 +1,16 @@
+package postgres
+
+import "context"
+
+// IsTransient exposes isTransient to tests
+func IsTransient(err error) bool {
+	return isTransient(err)
+}
+
+// Retry exposes retry to tests
+func Retry(ctx context.Context, op func() error) error {
+	return retry(ctx, op)
+}
+
+// RetryAttempts exposes retryAttempts to tests
+const RetryAttempts = retryAttempts
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
@@ -37,61 +38,59 @@ func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) error {
 	if job.Conditions.Success {
 		success = 1
 	}
-
-	tx, err := s.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
+	var pullRequest sql.NullString
+	if pr := job.Metadata.GetEvent().GetPullRequest(); pr > 0 {
+		pullRequest = sql.NullString{String: strconv.Itoa(int(pr)), Valid: true}
 	}
-	var jobID int
-	err = tx.QueryRow(`
-		INSERT
-		INTO   job_status (name, data, owner, phase, repo_owner, repo_repo, repo_host, repo_ref, trigger_src, success, created)
-		VALUES            ($1  , $2  , $3   , $4   , $5        , $6       , $7       , $8      , $9         , $10,     $11    ) 
-		ON CONFLICT (name) DO UPDATE 
-			SET data = $2, owner = $3, phase = $4, repo_owner = $5, repo_repo = $6, repo_host = $7, repo_ref = $8, trigger_src = $9, success = $10, created = $11
-		RETURNING id`,
-		job.Name,
-		serializedJob,
-		job.Metadata.Owner,
-		strings.ToLower(strings.TrimPrefix(job.Phase.String(), "PHASE_")),
-		job.Metadata.Repository.Owner,
-		job.Metadata.Repository.Repo,
-		job.Metadata.Repository.Host,
-		job.Metadata.Repository.Ref,
-		strings.ToLower(strings.TrimPrefix("TRIGGER_", job.Metadata.Trigger.String())),
-		success,
-		job.Metadata.Created.Seconds,
-	).Scan(&jobID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	for _, annotation := range job.Metadata.Annotations {
-		_, err := tx.Exec(`
-		INSERT
-		INTO   annotations (job_id, name, value)
-		VALUES             ($1    , $2  , $3   )
-		ON CONFLICT ON CONSTRAINT job_annotation DO UPDATE
-			SET value = $3
-		`, jobID, annotation.Key, annotation.Value)
+
+	return retryTx(ctx, s.DB, func(tx *sql.Tx) error {
+		var jobID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT
+			INTO   job_status (name, data, owner, phase, repo_owner, repo_repo, repo_host, repo_ref, trigger_src, success, created, repo_rev, pull_request)
+			VALUES            ($1  , $2  , $3   , $4   , $5        , $6       , $7       , $8      , $9         , $10,     $11    , $12     , $13         ) 
+			ON CONFLICT (name) DO UPDATE 
+				SET data = $2, owner = $3, phase = $4, repo_owner = $5, repo_repo = $6, repo_host = $7, repo_ref = $8, trigger_src = $9, success = $10, created = $11, repo_rev = $12, pull_request = $13
+			RETURNING id`,
+			job.Name,
+			serializedJob,
+			job.Metadata.Owner,
+			strings.ToLower(strings.TrimPrefix(job.Phase.String(), "PHASE_")),
+			job.Metadata.Repository.Owner,
+			job.Metadata.Repository.Repo,
+			job.Metadata.Repository.Host,
+			job.Metadata.Repository.Ref,
+			strings.ToLower(strings.TrimPrefix("TRIGGER_", job.Metadata.Trigger.String())),
+			success,
+			job.Metadata.Created.Seconds,
+			job.Metadata.Repository.Revision,
+			pullRequest,
+		).Scan(&jobID)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return err
-	}
-
-	return nil
+		for _, annotation := range job.Metadata.Annotations {
+			_, err := tx.ExecContext(ctx, `
+			INSERT
+			INTO   annotations (job_id, name, value)
+			VALUES             ($1    , $2  , $3   )
+			ON CONFLICT ON CONSTRAINT job_annotation DO UPDATE
+				SET value = $3
+			`, jobID, annotation.Key, annotation.Value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // Get retrieves a particular job bassd on its name.
 func (s *JobStore) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
 	var data string
-	err := s.DB.QueryRow("SELECT data FROM job_status WHERE name = $1", name).Scan(&data)
+	err := retry(ctx, func() error {
+		return s.DB.QueryRowContext(ctx, "SELECT data FROM job_status WHERE name = $1", name).Scan(&data)
+	})
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
@@ -118,6 +117,8 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 		"repo.repo":  "repo_repo",
 		"repo.host":  "repo_host",
 		"repo.ref":   "repo_ref",
+		"repo.rev":   "repo_rev",
+		"pr":         "pull_request",
 		"trigger":    "trigger",
 		"success":    "success",
 		"created":    "created",
@@ -202,17 +203,20 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 
 	countQuery := fmt.Sprintf("SELECT COUNT(1) FROM job_status %s", whereExp)
 	log.WithField("query", countQuery).Debug("running query")
-	err = s.DB.QueryRow(countQuery, args...).Scan(&total)
+	err = retry(ctx, func() error {
+		return s.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := fmt.Sprintf("SELECT data FROM job_status %s %s LIMIT %s OFFSET %d", whereExp, orderExp, limitExp, start)
 	log.WithField("query", query).Debug("running query")
-	rows, err := s.DB.Query(query, args...)
+	rows, err := retryQuery(ctx, s.DB, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
+	defer rows.Close()
 
 	var result []v1.JobStatus
 	for rows.Next() {
@@ -230,36 +234,77 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 
 		result = append(result, res)
 	}
-	if rows.Err() != nil {
+	if err := rows.Err(); err != nil {
 		return nil, 0, err
 	}
 
 	return result, total, nil
 }
 
-// StoreJobSpec stores job information in the store.
-func (s *JobStore) StoreJobSpec(name string, data []byte) error {
-	_, err := s.DB.Query(`
-		INSERT
-		INTO   job_spec (name, data)
-		VALUES          ($1  , $2  ) 
-		ON CONFLICT (name) DO UPDATE 
-			SET data = $2
-		`,
-		name,
-		data,
-	)
-	if err != nil {
+// Delete removes a job, including its annotations and job spec, from the store.
+func (s *JobStore) Delete(ctx context.Context, name string) error {
+	return retryTx(ctx, s.DB, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM annotations WHERE job_id IN (SELECT id FROM job_status WHERE name = $1)", name)
+		if err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM job_status WHERE name = $1", name)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return store.ErrNotFound
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM job_spec WHERE name = $1", name)
 		return err
-	}
+	})
+}
 
-	return nil
+// StoreJobSpec stores job YAML data. The data lives in job_spec_blob under its hash, s.t. jobs with the same YAML share it.
+func (s *JobStore) StoreJobSpec(ctx context.Context, name string, data []byte) error {
+	hash := store.JobSpecHash(data)
+	return retryTx(ctx, s.DB, func(tx *sql.Tx) error {
+		// We update existing blobs rather than leaving them alone to lock them until we commit.
+		// Otherwise CollectJobSpecs could remove them before our job spec refers to them.
+		_, err := tx.ExecContext(ctx, `
+			INSERT
+			INTO   job_spec_blob (hash, data)
+			VALUES               ($1  , $2  )
+			ON CONFLICT (hash) DO UPDATE
+				SET hash = EXCLUDED.hash
+			`,
+			hash,
+			data,
+		)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT
+			INTO   job_spec (name, hash)
+			VALUES          ($1  , $2  )
+			ON CONFLICT (name) DO UPDATE
+				SET hash = $2
+			`,
+			name,
+			hash,
+		)
+		return err
+	})
 }
 
 // GetJobSpec retrieves a particular job bassd on its name.
-func (s *JobStore) GetJobSpec(name string) ([]byte, error) {
+func (s *JobStore) GetJobSpec(ctx context.Context, name string) ([]byte, error) {
 	var data []byte
-	err := s.DB.QueryRow("SELECT data FROM job_spec WHERE name = $1", name).Scan(&data)
+	err := retry(ctx, func() error {
+		return s.DB.QueryRowContext(ctx, `
+			SELECT job_spec_blob.data
+			FROM   job_spec
+			JOIN   job_spec_blob ON job_spec_blob.hash = job_spec.hash
+			WHERE  job_spec.name = $1`,
+			name,
+		).Scan(&data)
+	})
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
@@ -269,3 +314,88 @@ func (s *JobStore) GetJobSpec(name string) ([]byte, error) {
 
 	return data, nil
 }
+
+// StoreRenderedJobSpec stores the job spec of a job as it was rendered from its template. Like job specs, the data
+// lives in job_spec_blob.
+func (s *JobStore) StoreRenderedJobSpec(ctx context.Context, name string, data []byte) error {
+	hash := store.JobSpecHash(data)
+	return retryTx(ctx, s.DB, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT
+			INTO   job_spec_blob (hash, data)
+			VALUES               ($1  , $2  )
+			ON CONFLICT (hash) DO UPDATE
+				SET hash = EXCLUDED.hash
+			`,
+			hash,
+			data,
+		)
+		if err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `UPDATE job_spec SET rendered_hash = $2 WHERE name = $1`, name, hash)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return store.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// GetRenderedJobSpec retrieves the rendered job spec of a job
+func (s *JobStore) GetRenderedJobSpec(ctx context.Context, name string) ([]byte, error) {
+	var data []byte
+	err := retry(ctx, func() error {
+		return s.DB.QueryRowContext(ctx, `
+			SELECT job_spec_blob.data
+			FROM   job_spec
+			JOIN   job_spec_blob ON job_spec_blob.hash = job_spec.rendered_hash
+			WHERE  job_spec.name = $1`,
+			name,
+		).Scan(&data)
+	})
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteJobSpec removes the job spec of a job
+func (s *JobStore) DeleteJobSpec(ctx context.Context, name string) error {
+	res, err := retryExec(ctx, s.DB, `DELETE FROM job_spec WHERE name = $1`, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// CollectJobSpecs removes the job spec data no job refers to any more
+func (s *JobStore) CollectJobSpecs(ctx context.Context) (collected int, err error) {
+	res, err := retryExec(ctx, s.DB, `
+		DELETE
+		FROM   job_spec_blob
+		WHERE  NOT EXISTS (SELECT 1 FROM job_spec WHERE job_spec.hash = job_spec_blob.hash)
+		AND    NOT EXISTS (SELECT 1 FROM job_spec WHERE job_spec.rendered_hash = job_spec_blob.hash)`,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
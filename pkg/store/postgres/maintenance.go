@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+// maintenanceModeID is the ID of the only row in the maintenance table
+const maintenanceModeID = 1
+
+// Maintenance stores the maintenance mode in a Postgres database
+type Maintenance struct {
+	DB *sql.DB
+}
+
+// NewMaintenance creates a new SQL maintenance store
+func NewMaintenance(db *sql.DB) (*Maintenance, error) {
+	return &Maintenance{DB: db}, nil
+}
+
+// Get returns the current maintenance mode
+func (m *Maintenance) Get(ctx context.Context) (*v1.MaintenanceMode, error) {
+	var data string
+	err := retry(ctx, func() error {
+		return m.DB.QueryRowContext(ctx, "SELECT data FROM maintenance WHERE id = $1", maintenanceModeID).Scan(&data)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var mode v1.MaintenanceMode
+	err = jsonpb.UnmarshalString(data, &mode)
+	if err != nil {
+		return nil, err
+	}
+	return &mode, nil
+}
+
+// Set stores the maintenance mode
+func (m *Maintenance) Set(ctx context.Context, mode *v1.MaintenanceMode) error {
+	if mode == nil {
+		_, err := retryExec(ctx, m.DB, "DELETE FROM maintenance WHERE id = $1", maintenanceModeID)
+		return err
+	}
+
+	data, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(mode)
+	if err != nil {
+		return err
+	}
+	_, err = retryExec(ctx, m.DB, `
+		INSERT
+		INTO   maintenance (id, data)
+		VALUES             ($1, $2  )
+		ON CONFLICT (id) DO UPDATE
+			SET data = $2`,
+		maintenanceModeID, data,
+	)
+	return err
+}
+
+// Enqueue adds a job to the queue
+func (m *Maintenance) Enqueue(ctx context.Context, req *v1.StartGitHubJobRequest) error {
+	data, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(req)
+	if err != nil {
+		return err
+	}
+	_, err = retryExec(ctx, m.DB, "INSERT INTO queued_job (data) VALUES ($1)", data)
+	return err
+}
+
+// Queued returns the number of queued jobs
+func (m *Maintenance) Queued(ctx context.Context) (int, error) {
+	var n int
+	err := retry(ctx, func() error {
+		return m.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM queued_job").Scan(&n)
+	})
+	return n, err
+}
+
+// Dequeue removes all jobs from the queue
+func (m *Maintenance) Dequeue(ctx context.Context) ([]*v1.StartGitHubJobRequest, error) {
+	rows, err := retryQuery(ctx, m.DB, "DELETE FROM queued_job RETURNING id, data")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type queued struct {
+		ID  int
+		Req *v1.StartGitHubJobRequest
+	}
+	var qs []queued
+	for rows.Next() {
+		var (
+			id   int
+			data string
+		)
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		var req v1.StartGitHubJobRequest
+		err = jsonpb.UnmarshalString(data, &req)
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, queued{id, &req})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// DELETE ... RETURNING does not guarantee any order
+	sort.Slice(qs, func(i, j int) bool { return qs[i].ID < qs[j].ID })
+	res := make([]*v1.StartGitHubJobRequest, len(qs))
+	for i, q := range qs {
+		res[i] = q.Req
+	}
+	return res, nil
+}
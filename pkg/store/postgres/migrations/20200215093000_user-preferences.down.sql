@@ -0,0 +1,2 @@
+DROP TABLE starred_job;
+DROP TABLE saved_search;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS starred_job (
+	user_name varchar(255) NOT NULL,
+	job_name varchar(255) NOT NULL,
+	created int NOT NULL,
+	PRIMARY KEY (user_name, job_name)
+);
+
+CREATE TABLE IF NOT EXISTS saved_search (
+	user_name varchar(255) NOT NULL,
+	name varchar(255) NOT NULL,
+	data text NOT NULL,
+	PRIMARY KEY (user_name, name)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS deployment (
+	id SERIAL PRIMARY KEY,
+	environment varchar(255) NOT NULL,
+	deployed int NOT NULL,
+	data text NOT NULL
+);
+
+CREATE INDEX idx_deployment_environment ON deployment (environment, id);
@@ -0,0 +1,2 @@
+DROP TABLE maintenance;
+DROP TABLE queued_job;
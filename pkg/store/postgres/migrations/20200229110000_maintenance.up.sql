@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS maintenance (
+	id int PRIMARY KEY,
+	data text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS queued_job (
+	id SERIAL PRIMARY KEY,
+	data text NOT NULL
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS repository_settings (
+	owner varchar(255) NOT NULL,
+	repo varchar(255) NOT NULL,
+	data text NOT NULL,
+	PRIMARY KEY (owner, repo)
+);
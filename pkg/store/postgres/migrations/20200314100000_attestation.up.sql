@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS attestation (
+	job_name varchar(255) PRIMARY KEY,
+	data bytea NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS event_trace (
+	id SERIAL PRIMARY KEY,
+	job_name varchar(255) NOT NULL,
+	-- time is in nanoseconds since the epoch
+	time bigint NOT NULL,
+	status text NOT NULL,
+	pod bytea
+);
+
+CREATE INDEX idx_event_trace_job_name ON event_trace (job_name, time);
+CREATE INDEX idx_event_trace_time ON event_trace (time);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS job_stats (
+	repo_owner varchar(255) NOT NULL,
+	repo_repo varchar(255) NOT NULL,
+	repo_ref varchar(255) NOT NULL,
+	day date NOT NULL,
+	counter varchar(255) NOT NULL,
+	value bigint NOT NULL,
+	PRIMARY KEY (repo_owner, repo_repo, repo_ref, day, counter)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE job_spec ADD COLUMN data bytea;
+UPDATE job_spec SET data = job_spec_blob.data FROM job_spec_blob WHERE job_spec_blob.hash = job_spec.hash;
+ALTER TABLE job_spec ALTER COLUMN data SET NOT NULL;
+DROP INDEX job_spec_hash;
+ALTER TABLE job_spec DROP COLUMN hash;
+DROP TABLE job_spec_blob;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS job_spec_blob (
+	hash varchar(64) NOT NULL PRIMARY KEY,
+	data bytea NOT NULL
+);
+
+INSERT INTO job_spec_blob (hash, data)
+	SELECT DISTINCT ON (hash) hash, data
+	FROM   (SELECT encode(sha256(data), 'hex') AS hash, data FROM job_spec) AS specs;
+
+ALTER TABLE job_spec ADD COLUMN hash varchar(64);
+UPDATE job_spec SET hash = encode(sha256(data), 'hex');
+ALTER TABLE job_spec ALTER COLUMN hash SET NOT NULL;
+ALTER TABLE job_spec ADD CONSTRAINT job_spec_hash_fkey FOREIGN KEY (hash) REFERENCES job_spec_blob (hash);
+ALTER TABLE job_spec DROP COLUMN data;
+CREATE INDEX job_spec_hash ON job_spec (hash);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS service_account_token (
+	id varchar(32) NOT NULL PRIMARY KEY,
+	service_account varchar(255) NOT NULL,
+	hash char(64) NOT NULL UNIQUE,
+	scopes text[] NOT NULL,
+	created bigint NOT NULL,
+	expires bigint NOT NULL DEFAULT 0,
+	last_used bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS service_account_token_account ON service_account_token (service_account);
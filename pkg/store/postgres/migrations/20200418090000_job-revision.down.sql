@@ -0,0 +1,4 @@
+DROP INDEX idx_job_status_pull_request;
+DROP INDEX idx_job_status_repo_rev;
+ALTER TABLE job_status DROP COLUMN pull_request;
+ALTER TABLE job_status DROP COLUMN repo_rev;
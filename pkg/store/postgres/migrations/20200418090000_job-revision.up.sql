@@ -0,0 +1,9 @@
+ALTER TABLE job_status ADD COLUMN repo_rev varchar(255) NULL;
+ALTER TABLE job_status ADD COLUMN pull_request varchar(255) NULL;
+
+UPDATE job_status SET
+	repo_rev = data::jsonb->'metadata'->'repository'->>'revision',
+	pull_request = data::jsonb->'metadata'->'event'->>'pullRequest';
+
+CREATE INDEX idx_job_status_repo_rev ON job_status(repo_rev);
+CREATE INDEX idx_job_status_pull_request ON job_status(pull_request);
@@ -0,0 +1,2 @@
+DROP INDEX job_spec_rendered_hash;
+ALTER TABLE job_spec DROP COLUMN rendered_hash;
@@ -0,0 +1,2 @@
+ALTER TABLE job_spec ADD COLUMN rendered_hash varchar(64) REFERENCES job_spec_blob (hash);
+CREATE INDEX job_spec_rendered_hash ON job_spec (rendered_hash);
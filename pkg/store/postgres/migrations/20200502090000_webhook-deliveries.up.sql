@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+	id varchar(32) NOT NULL PRIMARY KEY,
+	delivery_id varchar(255) NOT NULL,
+	received bigint NOT NULL,
+	event varchar(255) NOT NULL,
+	repository varchar(255) NOT NULL,
+	ref varchar(255) NOT NULL,
+	headers text NOT NULL,
+	digest char(64) NOT NULL,
+	payload bytea NOT NULL,
+	payload_size integer NOT NULL,
+	verified boolean NOT NULL,
+	jobs text[] NOT NULL,
+	skip_reason text NOT NULL,
+	error text NOT NULL,
+	redelivery_of varchar(32) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_received ON webhook_delivery (received);
+CREATE INDEX IF NOT EXISTS webhook_delivery_repository ON webhook_delivery (repository);
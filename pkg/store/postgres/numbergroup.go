@@ -1,8 +1,10 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 
+	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/store"
 )
 
@@ -17,13 +19,15 @@ func NewNumberGroup(db *sql.DB) (*NumberGroup, error) {
 }
 
 // Latest returns the latest number of a particular number group.
-func (ngrp *NumberGroup) Latest(group string) (nr int, err error) {
-	err = ngrp.DB.QueryRow(`
-		SELECT val
-		FROM   number_group
-		WHERE  name = $1`,
-		group,
-	).Scan(&nr)
+func (ngrp *NumberGroup) Latest(ctx context.Context, group string) (nr int, err error) {
+	err = retry(ctx, func() error {
+		return ngrp.DB.QueryRowContext(ctx, `
+			SELECT val
+			FROM   number_group
+			WHERE  name = $1`,
+			group,
+		).Scan(&nr)
+	})
 	if err == sql.ErrNoRows {
 		return 0, store.ErrNotFound
 	}
@@ -31,15 +35,61 @@ func (ngrp *NumberGroup) Latest(group string) (nr int, err error) {
 }
 
 // Next returns the next number in the group.
-func (ngrp *NumberGroup) Next(group string) (nr int, err error) {
-	err = ngrp.DB.QueryRow(`
-		INSERT
-		INTO   number_group (name, val)
-		VALUES              ($1  , 0  )
-		ON CONFLICT (name) DO UPDATE 
-			SET val = number_group.val + 1
-		RETURNING val`,
-		group,
-	).Scan(&nr)
+func (ngrp *NumberGroup) Next(ctx context.Context, group string) (nr int, err error) {
+	err = retry(ctx, func() error {
+		return ngrp.DB.QueryRowContext(ctx, `
+			INSERT
+			INTO   number_group (name, val)
+			VALUES              ($1  , 0  )
+			ON CONFLICT (name) DO UPDATE 
+				SET val = number_group.val + 1
+			RETURNING val`,
+			group,
+		).Scan(&nr)
+	})
 	return
 }
+
+// List returns all groups and their latest number ordered by name
+func (ngrp *NumberGroup) List(ctx context.Context) ([]*v1.NumberGroup, error) {
+	rows, err := retryQuery(ctx, ngrp.DB, "SELECT name, val FROM number_group ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*v1.NumberGroup
+	for rows.Next() {
+		var g v1.NumberGroup
+		err = rows.Scan(&g.Name, &g.Latest)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &g)
+	}
+	return res, rows.Err()
+}
+
+// Reset sets the latest number of a group
+func (ngrp *NumberGroup) Reset(ctx context.Context, group string, latest int) error {
+	res, err := retryExec(ctx, ngrp.DB, "UPDATE number_group SET val = $2 WHERE name = $1", group, latest)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a group
+func (ngrp *NumberGroup) Delete(ctx context.Context, group string) error {
+	res, err := retryExec(ctx, ngrp.DB, "DELETE FROM number_group WHERE name = $1", group)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
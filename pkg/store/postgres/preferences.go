@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+// Preferences stores user preferences in a Postgres database
+type Preferences struct {
+	DB *sql.DB
+}
+
+// NewPreferences creates a new SQL preferences store
+func NewPreferences(db *sql.DB) (*Preferences, error) {
+	return &Preferences{DB: db}, nil
+}
+
+// Star adds a job to the starred jobs of a user
+func (p *Preferences) Star(ctx context.Context, user, job string) error {
+	_, err := retryExec(ctx, p.DB, `
+		INSERT
+		INTO   starred_job (user_name, job_name, created)
+		VALUES             ($1       , $2      , $3     )
+		ON CONFLICT DO NOTHING`,
+		user, job, time.Now().Unix(),
+	)
+	return err
+}
+
+// Unstar removes a job from the starred jobs of a user
+func (p *Preferences) Unstar(ctx context.Context, user, job string) error {
+	res, err := retryExec(ctx, p.DB, "DELETE FROM starred_job WHERE user_name = $1 AND job_name = $2", user, job)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// Starred returns the names of the jobs a user starred
+func (p *Preferences) Starred(ctx context.Context, user string) ([]string, error) {
+	rows, err := retryQuery(ctx, p.DB, "SELECT job_name FROM starred_job WHERE user_name = $1 ORDER BY created DESC", user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	return res, rows.Err()
+}
+
+// SaveSearch stores a search of a user
+func (p *Preferences) SaveSearch(ctx context.Context, user string, search *v1.SavedSearch) error {
+	data, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(search)
+	if err != nil {
+		return err
+	}
+
+	_, err = retryExec(ctx, p.DB, `
+		INSERT
+		INTO   saved_search (user_name, name, data)
+		VALUES              ($1       , $2  , $3  )
+		ON CONFLICT (user_name, name) DO UPDATE
+			SET data = $3`,
+		user, search.Name, data,
+	)
+	return err
+}
+
+// DeleteSearch removes a saved search of a user
+func (p *Preferences) DeleteSearch(ctx context.Context, user, name string) error {
+	res, err := retryExec(ctx, p.DB, "DELETE FROM saved_search WHERE user_name = $1 AND name = $2", user, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// Searches returns the saved searches of a user
+func (p *Preferences) Searches(ctx context.Context, user string) ([]*v1.SavedSearch, error) {
+	rows, err := retryQuery(ctx, p.DB, "SELECT data FROM saved_search WHERE user_name = $1 ORDER BY name ASC", user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*v1.SavedSearch
+	for rows.Next() {
+		var data string
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		var search v1.SavedSearch
+		err = jsonpb.UnmarshalString(data, &search)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &search)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+// Repositories stores repository settings in a Postgres database
+type Repositories struct {
+	DB *sql.DB
+}
+
+// NewRepositories creates a new SQL repository settings store
+func NewRepositories(db *sql.DB) (*Repositories, error) {
+	return &Repositories{DB: db}, nil
+}
+
+// Get returns the settings of a repository
+func (r *Repositories) Get(ctx context.Context, owner, repo string) (*v1.RepositorySettings, error) {
+	var data string
+	err := retry(ctx, func() error {
+		return r.DB.QueryRowContext(ctx, "SELECT data FROM repository_settings WHERE owner = $1 AND repo = $2", owner, repo).Scan(&data)
+	})
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res v1.RepositorySettings
+	err = jsonpb.UnmarshalString(data, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Set registers a repository or replaces its settings
+func (r *Repositories) Set(ctx context.Context, settings *v1.RepositorySettings) error {
+	data, err := (&jsonpb.Marshaler{EnumsAsInts: true}).MarshalToString(settings)
+	if err != nil {
+		return err
+	}
+	_, err = retryExec(ctx, r.DB, `
+		INSERT
+		INTO   repository_settings (owner, repo, data)
+		VALUES                     ($1,    $2,   $3  )
+		ON CONFLICT (owner, repo) DO UPDATE
+			SET data = $3`,
+		settings.Owner, settings.Repo, data,
+	)
+	return err
+}
+
+// Delete removes the settings of a repository
+func (r *Repositories) Delete(ctx context.Context, owner, repo string) error {
+	res, err := retryExec(ctx, r.DB, "DELETE FROM repository_settings WHERE owner = $1 AND repo = $2", owner, repo)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// List returns the settings of all registered repositories
+func (r *Repositories) List(ctx context.Context) ([]*v1.RepositorySettings, error) {
+	rows, err := retryQuery(ctx, r.DB, "SELECT data FROM repository_settings ORDER BY owner, repo")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*v1.RepositorySettings
+	for rows.Next() {
+		var data string
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		var s v1.RepositorySettings
+		err = jsonpb.UnmarshalString(data, &s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &s)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/lib/pq"
+)
+
+// ServiceAccountTokens stores the tokens of service accounts in a Postgres database
+type ServiceAccountTokens struct {
+	DB *sql.DB
+}
+
+// NewServiceAccountTokens creates a new SQL service account token store
+func NewServiceAccountTokens(db *sql.DB) (*ServiceAccountTokens, error) {
+	return &ServiceAccountTokens{DB: db}, nil
+}
+
+// unixTime stores times as seconds since the epoch, where 0 is the zero time
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromUnixTime(s int64) time.Time {
+	if s == 0 {
+		return time.Time{}
+	}
+	return time.Unix(s, 0)
+}
+
+const serviceAccountTokenColumns = "id, service_account, hash, scopes, created, expires, last_used"
+
+func scanServiceAccountToken(row interface{ Scan(...interface{}) error }) (*store.ServiceAccountToken, error) {
+	var (
+		res                       store.ServiceAccountToken
+		created, expires, lastUse int64
+	)
+	err := row.Scan(&res.ID, &res.ServiceAccount, &res.Hash, pq.Array(&res.Scopes), &created, &expires, &lastUse)
+	if err != nil {
+		return nil, err
+	}
+	res.Created, res.Expires, res.LastUsed = fromUnixTime(created), fromUnixTime(expires), fromUnixTime(lastUse)
+	return &res, nil
+}
+
+// Create stores a new token
+func (s *ServiceAccountTokens) Create(ctx context.Context, token store.ServiceAccountToken) error {
+	_, err := retryExec(ctx, s.DB, `
+		INSERT
+		INTO   service_account_token (`+serviceAccountTokenColumns+`)
+		VALUES                       ($1, $2, $3, $4, $5, $6, $7)`,
+		token.ID, token.ServiceAccount, token.Hash, pq.Array(token.Scopes),
+		unixTime(token.Created), unixTime(token.Expires), unixTime(token.LastUsed),
+	)
+	return err
+}
+
+// Get returns a token by its ID
+func (s *ServiceAccountTokens) Get(ctx context.Context, id string) (*store.ServiceAccountToken, error) {
+	return s.getWhere(ctx, "id = $1", id)
+}
+
+// GetByHash returns the token with the hash
+func (s *ServiceAccountTokens) GetByHash(ctx context.Context, hash string) (*store.ServiceAccountToken, error) {
+	return s.getWhere(ctx, "hash = $1", hash)
+}
+
+func (s *ServiceAccountTokens) getWhere(ctx context.Context, where string, arg interface{}) (res *store.ServiceAccountToken, err error) {
+	err = retry(ctx, func() (err error) {
+		res, err = scanServiceAccountToken(s.DB.QueryRowContext(ctx, "SELECT "+serviceAccountTokenColumns+" FROM service_account_token WHERE "+where, arg))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// List returns the tokens of a service account
+func (s *ServiceAccountTokens) List(ctx context.Context, serviceAccount string) ([]*store.ServiceAccountToken, error) {
+	rows, err := retryQuery(ctx, s.DB, `
+		SELECT   `+serviceAccountTokenColumns+`
+		FROM     service_account_token
+		WHERE    $1 = '' OR service_account = $1
+		ORDER BY created ASC, id ASC`,
+		serviceAccount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*store.ServiceAccountToken
+	for rows.Next() {
+		t, err := scanServiceAccountToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// Expire sets the time a token stops working
+func (s *ServiceAccountTokens) Expire(ctx context.Context, id string, at time.Time) error {
+	return s.update(ctx, "UPDATE service_account_token SET expires = $2 WHERE id = $1", id, unixTime(at))
+}
+
+// Touch records the time a token was last used
+func (s *ServiceAccountTokens) Touch(ctx context.Context, id string, at time.Time) error {
+	return s.update(ctx, "UPDATE service_account_token SET last_used = $2 WHERE id = $1", id, unixTime(at))
+}
+
+// Delete removes a token
+func (s *ServiceAccountTokens) Delete(ctx context.Context, id string) error {
+	return s.update(ctx, "DELETE FROM service_account_token WHERE id = $1", id)
+}
+
+// Prune removes all tokens which expired before the time
+func (s *ServiceAccountTokens) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := retryExec(ctx, s.DB, "DELETE FROM service_account_token WHERE expires > 0 AND expires < $1", before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (s *ServiceAccountTokens) update(ctx context.Context, query string, args ...interface{}) error {
+	res, err := retryExec(ctx, s.DB, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
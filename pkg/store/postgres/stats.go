@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+// Stats stores job statistics in a Postgres database
+type Stats struct {
+	DB *sql.DB
+}
+
+// NewStats creates a new SQL stats store
+func NewStats(db *sql.DB) (*Stats, error) {
+	return &Stats{DB: db}, nil
+}
+
+// Add adds to the counters of a repository and ref on a day
+func (s *Stats) Add(ctx context.Context, owner, repo, ref string, t time.Time, counters map[string]int64) error {
+	day := t.UTC().Format("2006-01-02")
+	return retryTx(ctx, s.DB, func(tx *sql.Tx) error {
+		for counter, value := range counters {
+			_, err := tx.ExecContext(ctx, `
+				INSERT
+				INTO   job_stats (repo_owner, repo_repo, repo_ref, day, counter, value)
+				VALUES           ($1        , $2       , $3      , $4 , $5     , $6   )
+				ON CONFLICT (repo_owner, repo_repo, repo_ref, day, counter) DO UPDATE
+					SET value = job_stats.value + $6`,
+				owner, repo, ref, day, counter, value,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Sum sums up the counters of each repository and ref within a time range
+func (s *Stats) Sum(ctx context.Context, owner, repo, ref string, since, until time.Time) ([]*store.StatsSum, error) {
+	rows, err := retryQuery(ctx, s.DB, `
+		SELECT   repo_owner, repo_repo, repo_ref, counter, SUM(value)
+		FROM     job_stats
+		WHERE    repo_owner = $1 AND ($2 = '' OR repo_repo = $2) AND ($3 = '' OR repo_ref = $3) AND day >= $4 AND day <= $5
+		GROUP BY repo_owner, repo_repo, repo_ref, counter
+		ORDER BY repo_owner, repo_repo, repo_ref`,
+		owner, repo, ref, since.UTC().Format("2006-01-02"), until.UTC().Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		res  []*store.StatsSum
+		last *store.StatsSum
+	)
+	for rows.Next() {
+		var (
+			o, r, rf, counter string
+			value             int64
+		)
+		err := rows.Scan(&o, &r, &rf, &counter, &value)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil || last.Owner != o || last.Repo != r || last.Ref != rf {
+			last = &store.StatsSum{Owner: o, Repo: r, Ref: rf, Counters: make(map[string]int64)}
+			res = append(res, last)
+		}
+		last.Counters[counter] = value
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/lib/pq"
+)
+
+// WebhookDeliveries stores the webhooks werft received in a Postgres database
+type WebhookDeliveries struct {
+	DB *sql.DB
+}
+
+// NewWebhookDeliveries creates a new SQL webhook delivery store
+func NewWebhookDeliveries(db *sql.DB) (*WebhookDeliveries, error) {
+	return &WebhookDeliveries{DB: db}, nil
+}
+
+// webhookDeliveryColumns are all columns but the payload, which we read only when asked for a single delivery
+const webhookDeliveryColumns = "id, delivery_id, received, event, repository, ref, headers, digest, payload_size, verified, jobs, skip_reason, error, redelivery_of"
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*store.WebhookDelivery, error) {
+	var (
+		res      store.WebhookDelivery
+		received int64
+		headers  string
+	)
+	dst := append([]interface{}{
+		&res.ID, &res.DeliveryID, &received, &res.Event, &res.Repository, &res.Ref, &headers, &res.Digest, &res.PayloadSize, &res.Verified,
+		pq.Array(&res.Jobs), &res.SkipReason, &res.Error, &res.RedeliveryOf,
+	}, extra...)
+	err := row.Scan(dst...)
+	if err != nil {
+		return nil, err
+	}
+	res.Received = time.Unix(0, received)
+	err = json.Unmarshal([]byte(headers), &res.Headers)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Add stores a delivery
+func (s *WebhookDeliveries) Add(ctx context.Context, delivery store.WebhookDelivery) error {
+	headers, err := json.Marshal(delivery.Headers)
+	if err != nil {
+		return err
+	}
+	payload := delivery.Payload
+	if payload == nil {
+		payload = []byte{}
+	}
+	jobs := delivery.Jobs
+	if jobs == nil {
+		jobs = []string{}
+	}
+
+	_, err = retryExec(ctx, s.DB, `
+		INSERT
+		INTO   webhook_delivery (`+webhookDeliveryColumns+`, payload)
+		VALUES                  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		delivery.ID, delivery.DeliveryID, delivery.Received.UnixNano(), delivery.Event, delivery.Repository, delivery.Ref,
+		string(headers), delivery.Digest, delivery.PayloadSize, delivery.Verified, pq.Array(jobs), delivery.SkipReason, delivery.Error,
+		delivery.RedeliveryOf, payload,
+	)
+	return err
+}
+
+// Get returns a delivery including its payload
+func (s *WebhookDeliveries) Get(ctx context.Context, id string) (res *store.WebhookDelivery, err error) {
+	err = retry(ctx, func() (err error) {
+		var payload []byte
+		res, err = scanWebhookDelivery(s.DB.QueryRowContext(ctx, "SELECT "+webhookDeliveryColumns+", payload FROM webhook_delivery WHERE id = $1", id), &payload)
+		if err != nil {
+			return err
+		}
+		res.Payload = payload
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// List returns the deliveries the filter selects, newest first
+func (s *WebhookDeliveries) List(ctx context.Context, filter store.WebhookDeliveryFilter, limit int) ([]*store.WebhookDelivery, error) {
+	// LIMIT NULL is the same as omitting the limit
+	lim := sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+
+	rows, err := retryQuery(ctx, s.DB, `
+		SELECT   `+webhookDeliveryColumns+`
+		FROM     webhook_delivery
+		WHERE    ($1 = '' OR repository = $1) AND (NOT $2 OR cardinality(jobs) = 0)
+		ORDER BY received DESC, id DESC
+		LIMIT    $3`,
+		filter.Repository, filter.WithoutJobs, lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*store.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+// Prune removes all deliveries received before the time
+func (s *WebhookDeliveries) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := retryExec(ctx, s.DB, "DELETE FROM webhook_delivery WHERE received < $1", before.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
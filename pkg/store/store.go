@@ -2,8 +2,11 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 )
@@ -32,28 +35,82 @@ type Logs interface {
 	// Callers are supposed to close the reader once done.
 	// Reading from logs currently being written is supported.
 	Read(id string) (io.ReadCloser, error)
+
+	// Delete removes a log file from this store.
+	// Returns ErrNotFound if the log file isn't found. Logs currently being written cannot be deleted.
+	Delete(id string) error
 }
 
-// Jobs provides access to past jobs
+// Jobs provides access to past jobs. It combines the stores for their status, job specs and results, which can live in
+// different backends - see NewJobs.
 type Jobs interface {
+	StatusStore
+	SpecStore
+}
+
+// StatusStore stores the status of jobs
+type StatusStore interface {
 	// Store stores job information in the store.
 	// Storing a job whose name we already have in store will override the previously
 	// stored job.
 	Store(ctx context.Context, job v1.JobStatus) error
 
-	// StoreJobSpec stores job YAML data.
-	StoreJobSpec(name string, data []byte) error
-
 	// Retrieves a particular job bassd on its name.
 	// If the job is unknown we'll return ErrNotFound.
 	Get(ctx context.Context, name string) (*v1.JobStatus, error)
 
-	// Get retrieves previously stored job spec data
-	GetJobSpec(name string) (data []byte, err error)
-
 	// Searches for jobs based on their annotations. If filter is empty no filter is applied.
 	// If limit is 0, no limit is applied.
 	Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error)
+
+	// Delete removes a job, including its annotations and job spec, from the store.
+	// If the job is unknown we'll return ErrNotFound.
+	Delete(ctx context.Context, name string) error
+}
+
+// SpecStore stores the job specs of jobs
+type SpecStore interface {
+	// StoreJobSpec stores job YAML data. Jobs with the same YAML share its data.
+	StoreJobSpec(ctx context.Context, name string, data []byte) error
+
+	// Get retrieves previously stored job spec data
+	GetJobSpec(ctx context.Context, name string) (data []byte, err error)
+
+	// StoreRenderedJobSpec stores the job spec of a job as it was rendered from its YAML, s.t. the job can be replayed
+	// exactly. It shares its data with job specs. If the job has no job spec we'll return ErrNotFound.
+	StoreRenderedJobSpec(ctx context.Context, name string, data []byte) error
+
+	// GetRenderedJobSpec retrieves the rendered job spec of a job.
+	// If the job has no rendered job spec we'll return ErrNotFound.
+	GetRenderedJobSpec(ctx context.Context, name string) (data []byte, err error)
+
+	// DeleteJobSpec removes the job spec of a job including its rendered job spec, e.g. once it was archived,
+	// but keeps the job itself. If the job has no job spec we'll return ErrNotFound.
+	DeleteJobSpec(ctx context.Context, name string) error
+
+	// CollectJobSpecs removes the job spec data no job refers to any more, e.g. after jobs were deleted.
+	// It returns the number of job specs it removed.
+	CollectJobSpecs(ctx context.Context) (collected int, err error)
+}
+
+// ResultStore stores the results of jobs
+type ResultStore interface {
+	// StoreResults stores the results of a job, replacing those stored before.
+	StoreResults(ctx context.Context, name string, results []*v1.JobResult) error
+
+	// GetResults retrieves the results of a job.
+	// If the job has no results we'll return ErrNotFound.
+	GetResults(ctx context.Context, name string) ([]*v1.JobResult, error)
+
+	// DeleteResults removes the results of a job.
+	// If the job has no results we'll return ErrNotFound.
+	DeleteResults(ctx context.Context, name string) error
+}
+
+// JobSpecHash returns the hash job spec data is stored under
+func JobSpecHash(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
 }
 
 // NumberGroup enables to atomic generation and storage of numbers.
@@ -62,9 +119,256 @@ type NumberGroup interface {
 	// Latest returns the latest number of a particular number group.
 	// Returns ErrNotFound if the group does not exist. A zero result is a valid
 	// number in a group and does not indicate its non-existence.
-	Latest(group string) (nr int, err error)
+	Latest(ctx context.Context, group string) (nr int, err error)
 
 	// Next returns the next number in the group. If the group did not exist prior
 	// to this call it is created. This function is thread-safe and atomic.
-	Next(group string) (nr int, err error)
+	Next(ctx context.Context, group string) (nr int, err error)
+
+	// List returns all groups and their latest number ordered by name.
+	List(ctx context.Context) ([]*v1.NumberGroup, error)
+
+	// Reset sets the latest number of a group, s.t. Next continues from there.
+	// Returns ErrNotFound if the group does not exist.
+	Reset(ctx context.Context, group string, latest int) error
+
+	// Delete removes a group. If it's used again afterwards it starts from zero.
+	// Returns ErrNotFound if the group does not exist.
+	Delete(ctx context.Context, group string) error
+}
+
+// Preferences stores per-user preferences, i.e. starred jobs and saved searches
+type Preferences interface {
+	// Star adds a job to the starred jobs of a user. Starring a job twice is not an error.
+	Star(ctx context.Context, user, job string) error
+
+	// Unstar removes a job from the starred jobs of a user.
+	// If the job wasn't starred we'll return ErrNotFound.
+	Unstar(ctx context.Context, user, job string) error
+
+	// Starred returns the names of the jobs a user starred, most recently starred first.
+	Starred(ctx context.Context, user string) ([]string, error)
+
+	// SaveSearch stores a search of a user. A search with the same name is replaced.
+	SaveSearch(ctx context.Context, user string, search *v1.SavedSearch) error
+
+	// DeleteSearch removes a saved search of a user.
+	// If the search is unknown we'll return ErrNotFound.
+	DeleteSearch(ctx context.Context, user, name string) error
+
+	// Searches returns the saved searches of a user ordered by name.
+	Searches(ctx context.Context, user string) ([]*v1.SavedSearch, error)
+}
+
+// Deployments records what jobs deployed to which environment
+type Deployments interface {
+	// Record adds a deployment. The most recently recorded deployment of an environment is its current one.
+	Record(ctx context.Context, deployment *v1.Deployment) error
+
+	// Current returns the current deployment of each environment ordered by environment name.
+	Current(ctx context.Context) ([]*v1.Deployment, error)
+
+	// History returns the deployments to an environment, most recent first.
+	// If limit is 0, no limit is applied.
+	History(ctx context.Context, environment string, limit int) ([]*v1.Deployment, error)
+}
+
+// Maintenance persists the maintenance mode and the jobs queued during maintenance
+type Maintenance interface {
+	// Get returns the current maintenance mode, or nil if werft is not in maintenance.
+	Get(ctx context.Context) (*v1.MaintenanceMode, error)
+
+	// Set stores the maintenance mode. Setting nil ends maintenance.
+	Set(ctx context.Context, mode *v1.MaintenanceMode) error
+
+	// Enqueue adds a job to the queue of jobs waiting for maintenance to end.
+	Enqueue(ctx context.Context, req *v1.StartGitHubJobRequest) error
+
+	// Queued returns the number of jobs waiting for maintenance to end.
+	Queued(ctx context.Context) (int, error)
+
+	// Dequeue removes all jobs from the queue and returns them in the order they were enqueued.
+	Dequeue(ctx context.Context) ([]*v1.StartGitHubJobRequest, error)
+}
+
+// Repositories stores the settings of registered repositories
+type Repositories interface {
+	// Get returns the settings of a repository.
+	// If the repository is not registered we'll return ErrNotFound.
+	Get(ctx context.Context, owner, repo string) (*v1.RepositorySettings, error)
+
+	// Set registers a repository or replaces its settings.
+	Set(ctx context.Context, settings *v1.RepositorySettings) error
+
+	// Delete removes the settings of a repository.
+	// If the repository is not registered we'll return ErrNotFound.
+	Delete(ctx context.Context, owner, repo string) error
+
+	// List returns the settings of all registered repositories ordered by owner and repo.
+	List(ctx context.Context) ([]*v1.RepositorySettings, error)
+}
+
+// Attestations stores signed attestations about jobs, e.g. their provenance
+type Attestations interface {
+	// Store stores the attestation of a job, replacing a previously stored one.
+	Store(ctx context.Context, job string, attestation []byte) error
+
+	// Get returns the attestation of a job.
+	// If the job has no attestation we'll return ErrNotFound.
+	Get(ctx context.Context, job string) ([]byte, error)
+
+	// Delete removes the attestation of a job.
+	// If the job has no attestation we'll return ErrNotFound.
+	Delete(ctx context.Context, job string) error
+}
+
+// Events stores the event trace, i.e. the status updates werft saw for jobs
+type Events interface {
+	// Add records an event.
+	Add(ctx context.Context, event *v1.TraceEvent) error
+
+	// Find returns the events of a job (or all jobs if name is empty) within a time range, oldest first.
+	// Zero times leave the range open. If limit is 0, no limit is applied.
+	Find(ctx context.Context, name string, since, until time.Time, limit int) ([]*v1.TraceEvent, error)
+
+	// Delete removes all events of a job.
+	Delete(ctx context.Context, name string) error
+}
+
+// Stats stores statistics of finished jobs, aggregated per repository, ref and day
+type Stats interface {
+	// Add adds to the counters of a repository and ref on the day t falls on in UTC.
+	Add(ctx context.Context, owner, repo, ref string, t time.Time, counters map[string]int64) error
+
+	// Sum sums up the counters of each repository and ref over the days within a time range.
+	// If repo or ref are empty, all repositories of the owner or all refs are considered.
+	Sum(ctx context.Context, owner, repo, ref string, since, until time.Time) ([]*StatsSum, error)
+}
+
+// StatsSum contains the summed up counters of a repository and ref
+type StatsSum struct {
+	Owner    string
+	Repo     string
+	Ref      string
+	Counters map[string]int64
+}
+
+// ServiceAccountTokens stores the API tokens of service accounts
+type ServiceAccountTokens interface {
+	// Create stores a new token.
+	Create(ctx context.Context, token ServiceAccountToken) error
+
+	// Get returns a token by its ID.
+	// If there is no such token we'll return ErrNotFound.
+	Get(ctx context.Context, id string) (*ServiceAccountToken, error)
+
+	// GetByHash returns the token with the hash.
+	// If there is no such token we'll return ErrNotFound.
+	GetByHash(ctx context.Context, hash string) (*ServiceAccountToken, error)
+
+	// List returns the tokens of a service account, or of all service accounts if serviceAccount is empty, oldest first.
+	List(ctx context.Context, serviceAccount string) ([]*ServiceAccountToken, error)
+
+	// Expire sets the time a token stops working.
+	// If there is no such token we'll return ErrNotFound.
+	Expire(ctx context.Context, id string, at time.Time) error
+
+	// Touch records the time a token was last used.
+	// If there is no such token we'll return ErrNotFound.
+	Touch(ctx context.Context, id string, at time.Time) error
+
+	// Delete removes a token.
+	// If there is no such token we'll return ErrNotFound.
+	Delete(ctx context.Context, id string) error
+
+	// Prune removes all tokens which expired before the time and returns how many it removed.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// ServiceAccountToken is a long-lived API token which belongs to a service account, e.g. a deployment bot, rather than a person
+type ServiceAccountToken struct {
+	// ID identifies the token without revealing it
+	ID             string
+	ServiceAccount string
+	// Hash is the hex encoded SHA-256 hash of the token. We never store the token itself.
+	Hash    string
+	Scopes  []string
+	Created time.Time
+	// Expires is when the token stops working, or the zero time if it never does
+	Expires time.Time
+	// LastUsed is when the token was last used, or the zero time if it never was
+	LastUsed time.Time
+}
+
+// Expired returns true if the token does not work any more at the time
+func (t *ServiceAccountToken) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
+
+// WebhookDeliveries stores the webhooks werft received and what became of them
+type WebhookDeliveries interface {
+	// Add stores a delivery.
+	Add(ctx context.Context, delivery WebhookDelivery) error
+
+	// Get returns a delivery including its payload.
+	// If there is no such delivery we'll return ErrNotFound.
+	Get(ctx context.Context, id string) (*WebhookDelivery, error)
+
+	// List returns the deliveries the filter selects without their payload, newest first.
+	// If limit is 0, no limit is applied.
+	List(ctx context.Context, filter WebhookDeliveryFilter, limit int) ([]*WebhookDelivery, error)
+
+	// Prune removes all deliveries received before the time and returns how many it removed.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// WebhookDelivery is a webhook werft received, e.g. a push GitHub told us about
+type WebhookDelivery struct {
+	// ID identifies the delivery within werft
+	ID string
+	// DeliveryID is the ID the sender assigned to the delivery
+	DeliveryID string
+	Received   time.Time
+	// Event is the type of event, e.g. push
+	Event string
+	// Repository is the repository the event is about, e.g. 32leaves/werft
+	Repository string
+	// Ref is the ref a push is about, e.g. refs/heads/master
+	Ref     string
+	Headers map[string]string
+	// Digest is the hex encoded SHA-256 hash of the payload
+	Digest  string
+	Payload []byte
+	// PayloadSize is the size of the payload in bytes. Unlike the payload itself, it is always returned.
+	PayloadSize int
+	// Verified is true if the payload carried a valid signature
+	Verified bool
+
+	// Jobs are the jobs the delivery started
+	Jobs []string
+	// SkipReason explains why the delivery started no job
+	SkipReason string
+	// Error is why werft failed to process the delivery
+	Error string
+	// RedeliveryOf is the ID of the delivery this one redelivered
+	RedeliveryOf string
+}
+
+// WebhookDeliveryFilter selects webhook deliveries
+type WebhookDeliveryFilter struct {
+	// Repository selects the deliveries of a repository. If empty, the deliveries of all repositories are selected.
+	Repository string
+	// WithoutJobs selects only the deliveries which started no job
+	WithoutJobs bool
+}
+
+// Matches returns true if the filter selects the delivery
+func (f WebhookDeliveryFilter) Matches(d *WebhookDelivery) bool {
+	if f.Repository != "" && d.Repository != f.Repository {
+		return false
+	}
+	if f.WithoutJobs && len(d.Jobs) > 0 {
+		return false
+	}
+	return true
 }
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// TimestampFormat is the format of the timestamp prepended to each log line
+	TimestampFormat = time.RFC3339Nano
+
+	// TimestampMarker starts the timestamp prepended to each log line. It tells our timestamps apart from lines
+	// which a job printed with a timestamp of its own. Jobs do not print the ASCII record separator by accident.
+	TimestampMarker = "\x1e"
+)
+
+// NewTimestampedLogs produces a log store which prepends the time of writing to each line written
+// to the underlying log store. The timestamp starts with TimestampMarker and is separated from the line content
+// by a single space.
+func NewTimestampedLogs(logs Logs) Logs {
+	return &timestampedLogs{
+		Logs:    logs,
+		writers: make(map[string]*timestampWriter),
+	}
+}
+
+type timestampedLogs struct {
+	Logs
+
+	mu      sync.Mutex
+	writers map[string]*timestampWriter
+}
+
+// Open places a logfile in this store.
+func (tl *timestampedLogs) Open(id string) (io.WriteCloser, error) {
+	out, err := tl.Logs.Open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	w := &timestampWriter{
+		out:         out,
+		lineStart:   true,
+		closeWriter: out.Close,
+	}
+	w.onClose = func() {
+		tl.mu.Lock()
+		delete(tl.writers, id)
+		tl.mu.Unlock()
+	}
+	tl.writers[id] = w
+	return w, nil
+}
+
+// Write writes to a previously placed logfile.
+func (tl *timestampedLogs) Write(id string) (io.Writer, error) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if w, ok := tl.writers[id]; ok {
+		return w, nil
+	}
+
+	out, err := tl.Logs.Write(id)
+	if err != nil {
+		return nil, err
+	}
+	return &timestampWriter{out: out, lineStart: true}, nil
+}
+
+// timestampWriter prefixes each line with the time it was written
+type timestampWriter struct {
+	out         io.Writer
+	closeWriter func() error
+	onClose     func()
+
+	mu        sync.Mutex
+	lineStart bool
+}
+
+func (w *timestampWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var (
+		buf    bytes.Buffer
+		prefix = []byte(TimestampMarker + time.Now().UTC().Format(TimestampFormat) + " ")
+	)
+	for _, c := range p {
+		if w.lineStart {
+			buf.Write(prefix)
+			w.lineStart = false
+		}
+		buf.WriteByte(c)
+		if c == '\n' {
+			w.lineStart = true
+		}
+	}
+
+	_, err = w.out.Write(buf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *timestampWriter) Close() error {
+	if w.onClose != nil {
+		w.onClose()
+	}
+	if w.closeWriter == nil {
+		return nil
+	}
+	return w.closeWriter()
+}
@@ -0,0 +1,68 @@
+package store_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+type bufferLogs struct {
+	buf bytes.Buffer
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (b *bufferLogs) Open(id string) (io.WriteCloser, error) { return nopWriteCloser{&b.buf}, nil }
+func (b *bufferLogs) Write(id string) (io.Writer, error)     { return &b.buf, nil }
+func (b *bufferLogs) Read(id string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(b.buf.Bytes())), nil
+}
+func (b *bufferLogs) Delete(id string) error { b.buf.Reset(); return nil }
+
+func TestTimestampedLogs(t *testing.T) {
+	backend := &bufferLogs{}
+	s := store.NewTimestampedLogs(backend)
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	defer w.Close()
+
+	// partial lines must only be timestamped once
+	for _, chunk := range []string{"hello ", "world\nsecond", " line\n"} {
+		_, err = io.WriteString(w, chunk)
+		if err != nil {
+			t.Fatalf("cannot write log: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(backend.buf.String()), "\n")
+	expected := []string{"hello world", "second line"}
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected log content: %q", lines)
+	}
+	for i, l := range lines {
+		segs := strings.SplitN(l, " ", 2)
+		if len(segs) != 2 {
+			t.Errorf("line %d has no timestamp: %q", i, l)
+			continue
+		}
+		if !strings.HasPrefix(segs[0], store.TimestampMarker) {
+			t.Errorf("line %d has no timestamp marker: %q", i, l)
+			continue
+		}
+		if _, err := time.Parse(store.TimestampFormat, strings.TrimPrefix(segs[0], store.TimestampMarker)); err != nil {
+			t.Errorf("line %d has invalid timestamp: %v", i, err)
+		}
+		if segs[1] != expected[i] {
+			t.Errorf("line %d: expected %q, actual %q", i, expected[i], segs[1])
+		}
+	}
+}
@@ -13,6 +13,7 @@ import { CssBaseline, createMuiTheme, createStyles } from '@material-ui/core';
 import { GithubPage } from './GithubPage';
 import { StartJob } from './StartJob';
 import { WerftUIClient } from './api/werft-ui_pb_service';
+import { MaintenanceBanner } from './components/MaintenanceBanner';
 
 export interface AppProps extends WithStyles<typeof styles> { }
 
@@ -33,6 +34,7 @@ const AppImpl: React.SFC<AppProps> = (props) => {
         <div className={classes.root}>
             <CssBaseline />
             <div className={classes.app}>
+                <MaintenanceBanner />
                 <Router>
                     <Switch>
                         <Route path="/job/:name/raw">
@@ -640,6 +640,9 @@ export class JobResult extends jspb.Message {
   setChannelsList(value: Array<string>): void;
   addChannels(value: string, index?: number): string;
 
+  getSection(): string;
+  setSection(value: string): void;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): JobResult.AsObject;
   static toObject(includeInstance: boolean, msg: JobResult): JobResult.AsObject;
@@ -656,6 +659,7 @@ export namespace JobResult {
     payload: string,
     description: string,
     channelsList: Array<string>,
+    section: string,
   }
 }
 
@@ -747,6 +751,7 @@ export interface JobTriggerMap {
   TRIGGER_MANUAL: 1;
   TRIGGER_PUSH: 2;
   TRIGGER_DELETED: 3;
+  TRIGGER_UPSTREAM: 4;
 }
 
 export const JobTrigger: JobTriggerMap;
@@ -4776,7 +4776,8 @@ proto.v1.JobResult.toObject = function(includeInstance, msg) {
     type: jspb.Message.getFieldWithDefault(msg, 1, ""),
     payload: jspb.Message.getFieldWithDefault(msg, 2, ""),
     description: jspb.Message.getFieldWithDefault(msg, 3, ""),
-    channelsList: jspb.Message.getRepeatedField(msg, 4)
+    channelsList: jspb.Message.getRepeatedField(msg, 4),
+    section: jspb.Message.getFieldWithDefault(msg, 5, "")
   };
 
   if (includeInstance) {
@@ -4829,6 +4830,10 @@ proto.v1.JobResult.deserializeBinaryFromReader = function(msg, reader) {
       var value = /** @type {string} */ (reader.readString());
       msg.addChannels(value);
       break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSection(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4886,6 +4891,13 @@ proto.v1.JobResult.serializeBinaryToWriter = function(message, writer) {
       f
     );
   }
+  f = message.getSection();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -4966,6 +4978,21 @@ proto.v1.JobResult.prototype.clearChannelsList = function() {
 };
 
 
+/**
+ * optional string section = 5;
+ * @return {string}
+ */
+proto.v1.JobResult.prototype.getSection = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/** @param {string} value */
+proto.v1.JobResult.prototype.setSection = function(value) {
+  jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
 
 
 
@@ -5397,7 +5424,8 @@ proto.v1.JobTrigger = {
   TRIGGER_UNKNOWN: 0,
   TRIGGER_MANUAL: 1,
   TRIGGER_PUSH: 2,
-  TRIGGER_DELETED: 3
+  TRIGGER_DELETED: 3,
+  TRIGGER_UPSTREAM: 4
 };
 
 /**
@@ -67,6 +67,7 @@ function isPhase(c: Chunk): c is Phase {
 
 class LogViewImpl extends React.Component<LogViewProps, LogViewState> {
     protected readonly chunks: Map<string, Chunk>;
+    protected scrolledToAnchor = false;
 
     constructor(props: LogViewProps) {
         super(props);
@@ -151,6 +152,36 @@ class LogViewImpl extends React.Component<LogViewProps, LogViewState> {
         });
     }
 
+    componentDidMount() {
+        this.scrollToAnchor();
+    }
+
+    componentDidUpdate() {
+        this.scrollToAnchor();
+    }
+
+    // scrollToAnchor scrolls to the slice or phase the URL points to, e.g. #build:test, once it has been rendered
+    protected scrollToAnchor() {
+        if (this.scrolledToAnchor || !window.location.hash) {
+            return;
+        }
+
+        const elem = document.getElementById(this.anchor());
+        if (!elem) {
+            return;
+        }
+        elem.scrollIntoView();
+        this.scrolledToAnchor = true;
+    }
+
+    protected anchor() {
+        return decodeURIComponent(window.location.hash.substring(1));
+    }
+
+    protected isAnchor(id: string) {
+        return !!window.location.hash && this.anchor() === id;
+    }
+
     renderRaw() {
         return <React.Fragment>
             <Grid container>
@@ -192,7 +223,7 @@ class LogViewImpl extends React.Component<LogViewProps, LogViewState> {
             { chunks.map((kv, i) => {
                 const chunk = kv[1];
                 if (isContent(chunk) && !chunk.name.startsWith("werft:")) { return (
-                    <ExpansionPanel key={kv[0]} defaultExpanded={chunk.status === "failed"}>
+                    <ExpansionPanel key={kv[0]} id={kv[0]} defaultExpanded={chunk.status === "failed" || this.isAnchor(kv[0])}>
                         <ExpansionPanelSummary className={classes.sectionHeader} style={chunk.status === "failed" ? { color: ColorFailure} : {}}>
                             { chunk.status === "done" && <DoneIcon /> }
                             { chunk.status === "failed" && <ErrorIcon /> }
@@ -0,0 +1,75 @@
+import * as React from "react";
+import { Theme, createStyles, WithStyles } from "@material-ui/core";
+import { withStyles } from "@material-ui/styles";
+import { ColorWarning } from './colors';
+
+export const styles = (theme: Theme) =>
+    createStyles({
+        banner: {
+            padding: theme.spacing(1),
+            background: ColorWarning,
+            textAlign: 'center',
+        },
+    });
+
+interface MaintenanceMode {
+    enabled?: boolean;
+    message?: string;
+    queueTriggers?: boolean;
+    queued?: number;
+}
+
+export interface MaintenanceBannerProps extends WithStyles<typeof styles> {
+    // how often we check the maintenance mode in milliseconds
+    interval?: number;
+}
+
+interface MaintenanceBannerState {
+    mode?: MaintenanceMode;
+}
+
+class MaintenanceBannerImpl extends React.Component<MaintenanceBannerProps, MaintenanceBannerState> {
+    protected timer: number | undefined;
+
+    constructor(p: MaintenanceBannerProps) {
+        super(p);
+        this.state = {};
+    }
+
+    async componentDidMount() {
+        this.timer = window.setInterval(() => this.update(), this.props.interval || 30000);
+        await this.update();
+    }
+
+    componentWillUnmount() {
+        window.clearInterval(this.timer);
+    }
+
+    protected async update() {
+        try {
+            const resp = await fetch("/api/v1/maintenance");
+            const mode = await resp.json() as MaintenanceMode;
+            this.setState({ mode });
+        } catch (err) {
+            console.warn("cannot get maintenance mode", err);
+        }
+    }
+
+    render() {
+        const mode = this.state.mode;
+        if (!mode || !mode.enabled) {
+            return null;
+        }
+
+        let text = "werft is in maintenance and does not start new jobs";
+        if (mode.message) {
+            text += `: ${mode.message}`;
+        }
+        if (mode.queueTriggers) {
+            text += ` (${mode.queued || 0} jobs queued)`;
+        }
+        return <div className={this.props.classes.banner}>{text}</div>;
+    }
+}
+
+export const MaintenanceBanner = withStyles(styles)(MaintenanceBannerImpl);
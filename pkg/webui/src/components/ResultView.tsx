@@ -1,5 +1,5 @@
-import { Theme, createStyles, WithStyles, List, ListItem, ListItemText, Link, ListItemAvatar } from "@material-ui/core";
-import { JobStatus } from '../api/werft_pb';
+import { Theme, createStyles, WithStyles, List, ListItem, ListItemText, Link, ListItemAvatar, ListSubheader } from "@material-ui/core";
+import { JobStatus, JobResult } from '../api/werft_pb';
 import * as React from 'react';
 import { withStyles } from "@material-ui/styles";
 import ReceiptIcon from '@material-ui/icons/ReceiptOutlined';
@@ -21,12 +21,24 @@ const ResultViewImpl: React.SFC<ResultViewProps> = (props) => {
         return <React.Fragment />;
     }
 
+    // results routed to a UI section are grouped under that section, all others come first
+    const sections = new Map<string, JobResult.AsObject[]>();
+    props.status.resultsList.forEach(r => {
+        const section = r.section || "";
+        sections.set(section, [...(sections.get(section) || []), r]);
+    });
+
     return <List>
-        { props.status.resultsList.map((r, i) => (
-            <ListItem key={i}>
-                {renderIcon(r.type)}
-                <ListItemText primary={renderPayload(r.type, r.payload)} secondary={r.description} />
-            </ListItem>
+        { Array.from(sections.entries()).sort(([a], [b]) => a.localeCompare(b)).map(([section, results]) => (
+            <React.Fragment key={section}>
+                { section && <ListSubheader disableSticky={true}>{section}</ListSubheader> }
+                { results.map((r, i) => (
+                    <ListItem key={i}>
+                        {renderIcon(r.type)}
+                        <ListItemText primary={renderPayload(r.type, r.payload)} secondary={r.description} />
+                    </ListItem>
+                )) }
+            </React.Fragment>
         )) }
     </List>;
 };
@@ -0,0 +1,315 @@
+package werft
+
+import (
+	"context"
+	"net"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PluginHost runs the plugins of this werft installation
+type PluginHost interface {
+	// Status returns the status of all configured plugins
+	Status() []*v1.PluginStatus
+
+	// ContentProvider returns the content-provider plugin registered for a repository host
+	ContentProvider(host string) (v1.ContentProviderPluginClient, bool)
+}
+
+// Draining returns true if this service does not accept new jobs
+func (srv *Service) Draining() bool {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+
+	return srv.maintenance != nil && srv.maintenance.Enabled
+}
+
+// checkAcceptsJobs returns an error if this service must not start new jobs. Unlike checkRateLimit this applies to
+// all jobs, including those werft starts itself.
+func (srv *Service) checkAcceptsJobs(ctx context.Context, md *v1.JobMetadata) error {
+	srv.mu.RLock()
+	mode := srv.maintenance
+	srv.mu.RUnlock()
+	if mode != nil && mode.Enabled {
+		msg := "werft is in maintenance and does not accept new jobs"
+		if mode.Message != "" {
+			msg += ": " + mode.Message
+		}
+		return status.Error(codes.Unavailable, msg)
+	}
+	return nil
+}
+
+// checkRateLimit returns an error if the caller started too many jobs recently. Only the start RPCs check the rate
+// limit: jobs werft starts itself, e.g. for webhooks, retries or downstream jobs, have no caller and must not use up
+// the budget of their repository.
+func (srv *Service) checkRateLimit(ctx context.Context, md *v1.JobMetadata) error {
+	if err := srv.jobLimiter.Allow(srv.rateLimitCaller(ctx), md.GetRepository()); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil
+}
+
+// rateLimitCaller identifies who starts a job for rate limiting. The job owner is no good for that, because callers
+// can claim any owner. Hence we use the name of their token, or their address if they present none. Requests which
+// have neither, e.g. those over an unnamed unix socket, are only limited per repository.
+func (srv *Service) rateLimitCaller(ctx context.Context) string {
+	tkn, err := srv.authenticate(ctx)
+	if err == nil && tkn != nil {
+		return "token " + tkn.Name
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil && p.Addr.String() != "" {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		return "address " + addr
+	}
+	return ""
+}
+
+// SetDrain makes werft stop (or resume) accepting new jobs
+func (srv *Service) SetDrain(ctx context.Context, req *v1.SetDrainRequest) (*v1.SetDrainResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	resp, err := srv.SetMaintenance(ctx, &v1.SetMaintenanceRequest{Enabled: req.Drain})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SetDrainResponse{Draining: resp.Mode != nil && resp.Mode.Enabled}, nil
+}
+
+// RequeueStuckJobs marks jobs which are not done but have no pod anymore as failed and starts them again
+func (srv *Service) RequeueStuckJobs(ctx context.Context, req *v1.RequeueStuckJobsRequest) (*v1.RequeueStuckJobsResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	cutoff, err := cutoffTime(req.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun && srv.Draining() {
+		return nil, status.Error(codes.FailedPrecondition, "cannot requeue jobs while draining")
+	}
+
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done", Negate: true}}},
+	}, nil, 0, 0)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &v1.RequeueStuckJobsResponse{}
+	for _, job := range jobs {
+		job := job
+		if job.Metadata == nil || job.Metadata.Created == nil {
+			continue
+		}
+		created, err := ptypes.Timestamp(job.Metadata.Created)
+		if err != nil || created.After(cutoff) {
+			continue
+		}
+		running, err := srv.Executor.IsRunning(job.Name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if running {
+			continue
+		}
+
+		res.Stuck = append(res.Stuck, &job)
+		if req.DryRun {
+			continue
+		}
+
+		srv.failStuckJob(ctx, &job)
+		if job.Conditions == nil || !job.Conditions.CanReplay {
+			continue
+		}
+		started, err := srv.StartFromPreviousJob(ctx, &v1.StartFromPreviousJobRequest{PreviousJob: job.Name})
+		if err != nil {
+			log.WithError(err).WithField("name", job.Name).Warn("cannot restart stuck job")
+			continue
+		}
+		res.Restarted = append(res.Restarted, started.Status.Name)
+	}
+
+	return res, nil
+}
+
+// failStuckJob marks a job as failed and stops listening for its logs
+func (srv *Service) failStuckJob(ctx context.Context, s *v1.JobStatus) {
+	s.Phase = v1.JobPhase_PHASE_DONE
+	if s.Conditions == nil {
+		s.Conditions = &v1.JobConditions{}
+	}
+	s.Conditions.Success = false
+	s.Conditions.FailureCount++
+	s.Details = "job was stuck and has been requeued by an administrator"
+	s.Metadata.Finished = ptypes.TimestampNow()
+	if s.Timestamps == nil {
+		s.Timestamps = &v1.JobTimestamps{Queued: s.Metadata.Created}
+	}
+	s.Timestamps.Finished = s.Metadata.Finished
+
+	srv.mu.Lock()
+	if jl, ok := srv.logListener[s.Name]; ok {
+		if jl.CancelExecutorListener != nil {
+			jl.CancelExecutorListener()
+		}
+		if jl.LogStore != nil {
+			jl.LogStore.Close()
+		}
+		delete(srv.logListener, s.Name)
+	}
+	delete(srv.durationEstimates, s.Name)
+	srv.mu.Unlock()
+
+	err := srv.Jobs.Store(ctx, *s)
+	if err != nil {
+		log.WithError(err).WithField("name", s.Name).Warn("cannot store job")
+	}
+	<-srv.events.Emit("job", s)
+}
+
+// Prune removes finished jobs and their logs, except for pinned ones
+func (srv *Service) Prune(ctx context.Context, req *v1.PruneRequest) (*v1.PruneResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	cutoff, err := cutoffTime(req.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done"}}},
+	}, []*v1.OrderExpression{{Field: "created", Ascending: true}}, 0, 0)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &v1.PruneResponse{}
+	for _, job := range jobs {
+		if job.Metadata == nil {
+			continue
+		}
+		ts := job.Metadata.Finished
+		if ts == nil {
+			ts = job.Metadata.Created
+		}
+		finished, err := ptypes.Timestamp(ts)
+		if err != nil || finished.After(cutoff) {
+			continue
+		}
+		if job.Conditions != nil && job.Conditions.Pinned {
+			res.Pinned = append(res.Pinned, job.Name)
+			continue
+		}
+
+		if !req.DryRun {
+			err = srv.Logs.Delete(job.Name)
+			if err != nil && err != store.ErrNotFound {
+				log.WithError(err).WithField("name", job.Name).Warn("cannot delete job log")
+				continue
+			}
+			err = srv.deleteArchived(job.Name)
+			if err != nil {
+				log.WithError(err).WithField("name", job.Name).Warn("cannot delete archived job")
+				continue
+			}
+			err = srv.Attestations.Delete(ctx, job.Name)
+			if err != nil && err != store.ErrNotFound {
+				log.WithError(err).WithField("name", job.Name).Warn("cannot delete job provenance")
+			}
+			if srv.Events != nil {
+				err = srv.Events.Delete(ctx, job.Name)
+				if err != nil {
+					log.WithError(err).WithField("name", job.Name).Warn("cannot delete job events")
+				}
+			}
+			err = srv.Jobs.Delete(ctx, job.Name)
+			if err != nil && err != store.ErrNotFound {
+				return res, status.Error(codes.Internal, err.Error())
+			}
+		}
+		res.Names = append(res.Names, job.Name)
+	}
+
+	if !req.DryRun {
+		log.WithField("count", len(res.Names)).Info("pruned jobs")
+
+		// jobs with the same spec share its data, hence we can only remove the data once no job refers to it
+		collected, err := srv.Jobs.CollectJobSpecs(ctx)
+		if err != nil {
+			log.WithError(err).Warn("cannot remove unused job specs")
+		} else {
+			log.WithField("count", collected).Info("removed unused job specs")
+		}
+	}
+	return res, nil
+}
+
+// ListTokens lists the configured API tokens without revealing their secret
+func (srv *Service) ListTokens(ctx context.Context, req *v1.ListTokensRequest) (*v1.ListTokensResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	res := &v1.ListTokensResponse{}
+	for _, t := range srv.config().Tokens {
+		scopes := make([]string, len(t.Scopes))
+		for i, s := range t.Scopes {
+			scopes[i] = string(s)
+		}
+		res.Tokens = append(res.Tokens, &v1.TokenInfo{
+			Name:   t.Name,
+			Scopes: scopes,
+		})
+	}
+	return res, nil
+}
+
+// GetPluginStatus returns the status of all configured plugins
+func (srv *Service) GetPluginStatus(ctx context.Context, req *v1.GetPluginStatusRequest) (*v1.GetPluginStatusResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	res := &v1.GetPluginStatusResponse{}
+	if plugins := srv.pluginHost(); plugins != nil {
+		res.Plugins = plugins.Status()
+	}
+	return res, nil
+}
+
+// DumpConfig returns the server configuration with all secrets redacted
+func (srv *Service) DumpConfig(ctx context.Context, req *v1.DumpConfigRequest) (*v1.DumpConfigResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	return &v1.DumpConfigResponse{Yaml: string(srv.Info.Config)}, nil
+}
+
+// cutoffTime computes the point in time before which jobs are considered by an admin operation
+func cutoffTime(olderThan *duration.Duration) (time.Time, error) {
+	if olderThan == nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, "older_than is required")
+	}
+	d, err := ptypes.Duration(olderThan)
+	if err != nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return time.Now().Add(-d), nil
+}
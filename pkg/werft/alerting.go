@@ -0,0 +1,247 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// queueTimeCheckInterval is how often we look for jobs which wait too long to start
+const queueTimeCheckInterval = 30 * time.Second
+
+// AlertingConfig configures alerts on job failure patterns
+type AlertingConfig struct {
+	Rules []*AlertRule `yaml:"rules,omitempty"`
+}
+
+// AlertRule fires when jobs matching its filter show a failure pattern. Each rule watches for exactly one pattern.
+type AlertRule struct {
+	Name string
+	Expr []*v1.FilterExpression
+
+	// ConsecutiveFailures fires when this many jobs of the same repository and ref failed in a row
+	ConsecutiveFailures int
+	// QueueTime fires when a job waits longer than this to start running
+	QueueTime time.Duration
+	// CleanupFailures fires when cleaning up workspaces failed this many times in a row on the same node
+	CleanupFailures int
+
+	Notify []*AlertTarget
+}
+
+// AlertTarget is where an alert is sent to
+type AlertTarget struct {
+	Slack   *repoconfig.SlackResultRoute   `yaml:"slack,omitempty"`
+	Webhook *repoconfig.WebhookResultRoute `yaml:"webhook,omitempty"`
+}
+
+// UnmarshalYAML unmarshals the filter expressions and validates the rule
+func (r *AlertRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawAlertRule struct {
+		Name                string                      `yaml:"name"`
+		Expr                []repoconfig.JobStartRuleOr `yaml:"matchesAll"`
+		ConsecutiveFailures int                         `yaml:"consecutiveFailures"`
+		QueueTime           string                      `yaml:"queueTime"`
+		CleanupFailures     int                         `yaml:"cleanupFailures"`
+		Notify              []*AlertTarget              `yaml:"notify"`
+	}
+	err := unmarshal(&rawAlertRule)
+	if err != nil {
+		return err
+	}
+	if rawAlertRule.Name == "" {
+		return xerrors.Errorf("alert rule has no name")
+	}
+	if rawAlertRule.ConsecutiveFailures < 0 {
+		return xerrors.Errorf("alert rule %s: consecutiveFailures must not be negative", rawAlertRule.Name)
+	}
+	if rawAlertRule.CleanupFailures < 0 {
+		return xerrors.Errorf("alert rule %s: cleanupFailures must not be negative", rawAlertRule.Name)
+	}
+	var patterns int
+	for _, set := range []bool{rawAlertRule.ConsecutiveFailures > 0, rawAlertRule.QueueTime != "", rawAlertRule.CleanupFailures > 0} {
+		if set {
+			patterns++
+		}
+	}
+	if patterns != 1 {
+		return xerrors.Errorf("alert rule %s must set exactly one of consecutiveFailures, queueTime or cleanupFailures", rawAlertRule.Name)
+	}
+	if rawAlertRule.QueueTime != "" {
+		r.QueueTime, err = time.ParseDuration(rawAlertRule.QueueTime)
+		if err != nil || r.QueueTime <= 0 {
+			return xerrors.Errorf("alert rule %s: queueTime \"%s\" is not a positive duration, e.g. 10m", rawAlertRule.Name, rawAlertRule.QueueTime)
+		}
+	}
+
+	r.Name = rawAlertRule.Name
+	r.ConsecutiveFailures = rawAlertRule.ConsecutiveFailures
+	r.CleanupFailures = rawAlertRule.CleanupFailures
+	r.Notify = rawAlertRule.Notify
+	for _, expr := range rawAlertRule.Expr {
+		terms, err := filterexpr.Parse(expr.Or)
+		if err != nil {
+			return err
+		}
+		r.Expr = append(r.Expr, &v1.FilterExpression{Terms: terms})
+	}
+	return nil
+}
+
+// checkFailureAlerts fires the consecutive failure alerts a finished job completes
+func (srv *Service) checkFailureAlerts(job *v1.JobStatus) {
+	if job.Conditions == nil || job.Conditions.Success || job.Metadata == nil || job.Metadata.Repository == nil {
+		return
+	}
+
+	repo := job.Metadata.Repository
+	for _, rule := range srv.config().Alerting.Rules {
+		if rule.ConsecutiveFailures == 0 || !filterexpr.MatchesFilter(job, rule.Expr) {
+			continue
+		}
+
+		filter := append([]*v1.FilterExpression{
+			{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done"}}},
+			{Terms: []*v1.FilterTerm{{Field: "repo.owner", Value: repo.Owner}}},
+			{Terms: []*v1.FilterTerm{{Field: "repo.repo", Value: repo.Repo}}},
+			{Terms: []*v1.FilterTerm{{Field: "repo.ref", Value: repo.Ref}}},
+		}, rule.Expr...)
+		previous, _, err := srv.Jobs.Find(context.Background(), filter, []*v1.OrderExpression{{Field: "created", Ascending: false}}, 0, rule.ConsecutiveFailures+1)
+		if err != nil {
+			log.WithError(err).WithField("rule", rule.Name).Warn("cannot check alert rule")
+			continue
+		}
+
+		// we count the job itself separately in case its final status has not been stored yet
+		failures := 1
+		for _, p := range previous {
+			if p.Name == job.Name {
+				continue
+			}
+			if p.Conditions == nil || p.Conditions.Success {
+				break
+			}
+			failures++
+		}
+		// we alert once when the failures reach the threshold, not on every failure thereafter
+		if failures != rule.ConsecutiveFailures {
+			continue
+		}
+
+		srv.fireAlert(rule, job, fmt.Sprintf("%s/%s failed %d times in a row on %s, most recently in %s/job/%s", repo.Owner, repo.Repo, failures, repo.Ref, srv.config().BaseURL, job.Name))
+	}
+}
+
+// checkCleanupAlerts fires the cleanup failure alerts a failed cleanup of a job's workspace completes
+func (srv *Service) checkCleanupAlerts(job *v1.JobStatus, node string, failures int, details string) {
+	for _, rule := range srv.config().Alerting.Rules {
+		// we alert once when the failures reach the threshold, not on every failure thereafter
+		if rule.CleanupFailures == 0 || failures != rule.CleanupFailures || !filterexpr.MatchesFilter(job, rule.Expr) {
+			continue
+		}
+		srv.fireAlert(rule, job, fmt.Sprintf("cleaning up workspaces failed %d times in a row on node %s, most recently for %s/job/%s: %s", failures, node, srv.config().BaseURL, job.Name, details))
+	}
+}
+
+// checkQueueTimeAlerts periodically fires queue time alerts for jobs which wait too long to start.
+// The rules can change while the server runs, hence we look for them on every check.
+func (srv *Service) checkQueueTimeAlerts() {
+	// alerted remembers the rule/job pairs we have alerted about already
+	alerted := make(map[string]struct{})
+	tick := time.NewTicker(queueTimeCheckInterval)
+	defer tick.Stop()
+	for range tick.C {
+		var rules []*AlertRule
+		for _, rule := range srv.config().Alerting.Rules {
+			if rule.QueueTime > 0 {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+
+		jobs, _, err := srv.Jobs.Find(context.Background(), []*v1.FilterExpression{
+			{Terms: []*v1.FilterTerm{{Field: "phase", Value: "preparing"}, {Field: "phase", Value: "starting"}}},
+		}, nil, 0, 0)
+		if err != nil {
+			log.WithError(err).Warn("cannot check queue time alerts")
+			continue
+		}
+
+		queued := make(map[string]struct{}, len(jobs))
+		for i := range jobs {
+			job := &jobs[i]
+			if job.Metadata == nil || job.Metadata.Created == nil {
+				continue
+			}
+			created, err := ptypes.Timestamp(job.Metadata.Created)
+			if err != nil {
+				continue
+			}
+			for _, rule := range rules {
+				if !filterexpr.MatchesFilter(job, rule.Expr) {
+					continue
+				}
+				wait := time.Since(created)
+				if wait < rule.QueueTime {
+					continue
+				}
+
+				key := rule.Name + "/" + job.Name
+				queued[key] = struct{}{}
+				if _, ok := alerted[key]; ok {
+					continue
+				}
+				srv.fireAlert(rule, job, fmt.Sprintf("%s/job/%s has been waiting to start for %s", srv.config().BaseURL, job.Name, wait.Truncate(time.Second)))
+			}
+		}
+		// jobs which started are forgotten, so that we do not remember them forever
+		alerted = queued
+	}
+}
+
+// fireAlert sends an alert to all targets of its rule
+func (srv *Service) fireAlert(rule *AlertRule, job *v1.JobStatus, message string) {
+	log.WithField("rule", rule.Name).WithField("name", job.Name).Warn("alert: " + message)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, target := range rule.Notify {
+		if target.Slack != nil {
+			err := postJSON(ctx, target.Slack.URL, struct {
+				Channel string `json:"channel,omitempty"`
+				Text    string `json:"text"`
+			}{
+				Channel: target.Slack.Channel,
+				Text:    fmt.Sprintf("*%s*: %s", rule.Name, message),
+			})
+			if err != nil {
+				log.WithError(err).WithField("rule", rule.Name).Warn("cannot send alert to Slack")
+			}
+		}
+		if target.Webhook != nil {
+			err := postJSON(ctx, target.Webhook.URL, struct {
+				Alert   string         `json:"alert"`
+				Message string         `json:"message"`
+				Job     string         `json:"job"`
+				Repo    *v1.Repository `json:"repository,omitempty"`
+			}{
+				Alert:   rule.Name,
+				Message: message,
+				Job:     job.Name,
+				Repo:    job.Metadata.Repository,
+			})
+			if err != nil {
+				log.WithError(err).WithField("rule", rule.Name).Warn("cannot send alert to webhook")
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package werft_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalAlertRule(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Source string
+		Error  bool
+	}{
+		{Name: "consecutive failures", Source: "name: main is broken\nconsecutiveFailures: 3\nmatchesAll:\n- or: [\"repo.ref==refs/heads/main\"]"},
+		{Name: "queue time", Source: "name: slow queue\nqueueTime: 10m"},
+		{Name: "cleanup failures", Source: "name: cleanup\ncleanupFailures: 2"},
+		{Name: "no name", Source: "consecutiveFailures: 3", Error: true},
+		{Name: "no pattern", Source: "name: nothing", Error: true},
+		{Name: "several patterns", Source: "name: both\nconsecutiveFailures: 3\nqueueTime: 10m", Error: true},
+		{Name: "negative failures", Source: "name: negative\nconsecutiveFailures: -1", Error: true},
+		{Name: "negative cleanup failures", Source: "name: negative\ncleanupFailures: -1", Error: true},
+		{Name: "invalid queue time", Source: "name: invalid\nqueueTime: soon", Error: true},
+		{Name: "negative queue time", Source: "name: negative\nqueueTime: -10m", Error: true},
+		{Name: "invalid filter", Source: "name: invalid\nconsecutiveFailures: 3\nmatchesAll:\n- or: [\"repo.ref\"]", Error: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var rule werft.AlertRule
+			err := yaml.Unmarshal([]byte(test.Source), &rule)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+// alertReceiver records the alerts posted to its webhook URL
+type alertReceiver struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	alerts []string
+}
+
+func newAlertReceiver() *alertReceiver {
+	r := &alertReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var alert struct {
+			Alert string `json:"alert"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&alert)
+
+		r.mu.Lock()
+		r.alerts = append(r.alerts, alert.Alert)
+		r.mu.Unlock()
+	}))
+	return r
+}
+
+func (r *alertReceiver) Alerts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.alerts
+}
+
+func TestCheckFailureAlerts(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := func(name, ref string, offset time.Duration, success bool) v1.JobStatus {
+		created, _ := ptypes.TimestampProto(t0.Add(offset))
+		return v1.JobStatus{
+			Name:  name,
+			Phase: v1.JobPhase_PHASE_DONE,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: ref},
+				Created:    created,
+			},
+			Conditions: &v1.JobConditions{Success: success},
+		}
+	}
+	// history produces the previous jobs on main, oldest first
+	history := func(success ...bool) []v1.JobStatus {
+		res := make([]v1.JobStatus, len(success))
+		for i, s := range success {
+			res[i] = job(fmt.Sprintf("werft-build-main.%d", i), "refs/heads/main", time.Duration(i)*time.Minute, s)
+		}
+		return res
+	}
+	failed := job("werft-build-main.99", "refs/heads/main", time.Hour, false)
+
+	tests := []struct {
+		Name    string
+		History []v1.JobStatus
+		Job     v1.JobStatus
+		// Stored is true if the job's final status is in the store already
+		Stored bool
+		Expr   []string
+		Alerts int
+	}{
+		{Name: "threshold reached", History: history(true, false, false), Job: failed, Alerts: 1},
+		{Name: "threshold reached with job stored", History: history(true, false, false), Job: failed, Stored: true, Alerts: 1},
+		{Name: "below threshold", History: history(false, true, false), Job: failed},
+		{Name: "first jobs", History: history(false, false), Job: failed, Alerts: 1},
+		{Name: "alerts once", History: history(false, false, false), Job: failed},
+		{Name: "success", History: history(true, false, false), Job: job("werft-build-main.99", "refs/heads/main", time.Hour, true)},
+		{
+			Name:    "failures on other refs",
+			History: append(history(true, false), job("werft-build-other.1", "refs/heads/other", 10*time.Minute, false)),
+			Job:     failed,
+		},
+		{Name: "filter does not match", History: history(true, false, false), Job: failed, Expr: []string{"repo.repo==leeway"}},
+		{Name: "filter matches", History: history(true, false, false), Job: failed, Expr: []string{"repo.repo==werft"}, Alerts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receiver := newAlertReceiver()
+			defer receiver.Close()
+
+			srv := testService(werft.AnonymousFull)
+			srv.Jobs = store.NewInMemoryJobStore()
+			rule := &werft.AlertRule{
+				Name:                "main is broken",
+				ConsecutiveFailures: 3,
+				Notify:              []*werft.AlertTarget{{Webhook: &repoconfig.WebhookResultRoute{URL: receiver.URL}}},
+			}
+			if len(test.Expr) > 0 {
+				terms, err := filterexpr.Parse(test.Expr)
+				if err != nil {
+					t.Fatalf("cannot parse filter: %v", err)
+				}
+				rule.Expr = []*v1.FilterExpression{{Terms: terms}}
+			}
+			srv.Config.Alerting.Rules = []*werft.AlertRule{rule}
+
+			jobs := test.History
+			if test.Stored {
+				jobs = append(jobs, test.Job)
+			}
+			for _, j := range jobs {
+				if err := srv.Jobs.Store(context.Background(), j); err != nil {
+					t.Fatalf("cannot store job: %v", err)
+				}
+			}
+
+			srv.CheckFailureAlerts(&test.Job)
+			if alerts := receiver.Alerts(); len(alerts) != test.Alerts {
+				t.Errorf("expected %d alerts, got %v", test.Alerts, alerts)
+			}
+		})
+	}
+}
+
+func TestCheckCleanupAlerts(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Failures int
+		Alerts   int
+	}{
+		{Name: "below threshold", Failures: 1},
+		{Name: "threshold reached", Failures: 2, Alerts: 1},
+		{Name: "alerts once", Failures: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receiver := newAlertReceiver()
+			defer receiver.Close()
+
+			srv := testService(werft.AnonymousFull)
+			srv.Config.Alerting.Rules = []*werft.AlertRule{
+				{
+					Name:            "cleanup",
+					CleanupFailures: 2,
+					Notify:          []*werft.AlertTarget{{Webhook: &repoconfig.WebhookResultRoute{URL: receiver.URL}}},
+				},
+				// rules watching for other patterns do not fire on failed cleanups
+				{
+					Name:                "main is broken",
+					ConsecutiveFailures: 2,
+					Notify:              []*werft.AlertTarget{{Webhook: &repoconfig.WebhookResultRoute{URL: receiver.URL}}},
+				},
+			}
+
+			job := &v1.JobStatus{Name: "werft-build-main.1", Metadata: &v1.JobMetadata{Repository: testRepo()}}
+			srv.CheckCleanupAlerts(job, "node-1", test.Failures, "permission denied")
+			if alerts := receiver.Alerts(); len(alerts) != test.Alerts {
+				t.Errorf("expected %d alerts, got %v", test.Alerts, alerts)
+			}
+		})
+	}
+}
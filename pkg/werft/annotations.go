@@ -0,0 +1,60 @@
+package werft
+
+import (
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// werftAnnotations are the annotations werft sets on the jobs it starts itself, e.g. for webhooks or retries.
+// The annotation policy of a repository does not apply to them.
+var werftAnnotations = map[string]struct{}{
+	annotationStatusUpdate:       {},
+	annotationPullRequest:        {},
+	annotationPreviewEnvironment: {},
+	annotationLabel:              {},
+	annotationRetryOf:            {},
+	annotationUpstreamJob:        {},
+	annotationUpstreamRepo:       {},
+	annotationUpstreamRevision:   {},
+	annotationTriggerChain:       {},
+	annotationCleanupJob:         {},
+	annotationCleanupAttempt:     {},
+	annotationPriority:           {},
+	annotationPromotion:          {},
+	annotationPromotedJob:        {},
+	annotationPromotedImages:     {},
+}
+
+func isWerftAnnotation(key string) bool {
+	if _, ok := werftAnnotations[key]; ok {
+		return true
+	}
+	return strings.HasPrefix(key, annotationRetriesPrefix)
+}
+
+// applyAnnotationPolicy enforces the annotation policy of a repository on the annotations of a job which is about to start.
+// It rejects or strips the annotations the repository does not allow and adds the defaults of those not set.
+func applyAnnotationPolicy(md *v1.JobMetadata, cfg *repoconfig.C) error {
+	if cfg == nil || cfg.Annotations == nil {
+		return nil
+	}
+
+	var own, user []*v1.Annotation
+	for _, a := range md.Annotations {
+		if isWerftAnnotation(a.Key) {
+			own = append(own, a)
+		} else {
+			user = append(user, a)
+		}
+	}
+	user, err := cfg.Annotations.Apply(user)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	md.Annotations = append(own, user...)
+	return nil
+}
@@ -0,0 +1,202 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// archiveInterval is how often we look for jobs to archive
+const archiveInterval = 1 * time.Hour
+
+// ArchiveConfig configures the archival of old jobs
+type ArchiveConfig struct {
+	// AfterDays is the number of days after which the log and job spec of a finished job move to the archive.
+	// If zero, jobs are not archived.
+	AfterDays int `yaml:"afterDays,omitempty"`
+}
+
+// archiveLogName is the name under which the log of a job is archived
+func archiveLogName(job string) string { return job + ".log" }
+
+// archiveSpecName is the name under which the job spec of a job is archived
+func archiveSpecName(job string) string { return job + ".spec.yaml" }
+
+// archiveRenderedSpecName is the name under which the rendered job spec of a job is archived
+func archiveRenderedSpecName(job string) string { return job + ".rendered.yaml" }
+
+// archiveJobs periodically moves the logs and job specs of old jobs to the archive
+func (srv *Service) archiveJobs() {
+	tick := time.NewTicker(archiveInterval)
+	defer tick.Stop()
+	for {
+		days := srv.config().Archive.AfterDays
+		if days > 0 {
+			archived, err := srv.archiveJobsOlderThan(context.Background(), time.Now().AddDate(0, 0, -days))
+			if err != nil {
+				log.WithError(err).Warn("cannot archive jobs")
+			}
+			if archived > 0 {
+				log.WithField("count", archived).Info("archived jobs")
+			}
+		}
+		<-tick.C
+	}
+}
+
+// archiveJobsOlderThan archives all jobs which finished before the cutoff
+func (srv *Service) archiveJobsOlderThan(ctx context.Context, cutoff time.Time) (archived int, err error) {
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done"}}},
+	}, []*v1.OrderExpression{{Field: "created", Ascending: true}}, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		job := job
+		if job.Metadata == nil || (job.Conditions != nil && job.Conditions.Archived) {
+			continue
+		}
+		ts := job.Metadata.Finished
+		if ts == nil {
+			ts = job.Metadata.Created
+		}
+		finished, err := ptypes.Timestamp(ts)
+		if err != nil || finished.After(cutoff) {
+			continue
+		}
+
+		err = srv.archiveJob(ctx, &job)
+		if err != nil {
+			log.WithError(err).WithField("name", job.Name).Warn("cannot archive job")
+			continue
+		}
+		archived++
+	}
+	if archived == 0 {
+		return 0, nil
+	}
+
+	// the job specs we archived may have been the last ones which referred to their data
+	_, err = srv.Jobs.CollectJobSpecs(ctx)
+	if err != nil {
+		log.WithError(err).Warn("cannot remove unused job specs")
+	}
+	return archived, nil
+}
+
+// archiveJob moves the log and job spec of a job to the archive and marks the job as archived
+func (srv *Service) archiveJob(ctx context.Context, job *v1.JobStatus) error {
+	rd, err := srv.Logs.Read(job.Name)
+	if err == nil {
+		err = srv.Archive.Put(archiveLogName(job.Name), rd)
+		rd.Close()
+		if err != nil {
+			return xerrors.Errorf("cannot archive log: %w", err)
+		}
+	} else if err != store.ErrNotFound {
+		return xerrors.Errorf("cannot read log: %w", err)
+	}
+
+	spec, err := srv.Jobs.GetJobSpec(ctx, job.Name)
+	if err == nil {
+		err = srv.Archive.Put(archiveSpecName(job.Name), bytes.NewReader(spec))
+		if err != nil {
+			return xerrors.Errorf("cannot archive job spec: %w", err)
+		}
+	} else if err != store.ErrNotFound {
+		return xerrors.Errorf("cannot read job spec: %w", err)
+	}
+
+	rendered, err := srv.Jobs.GetRenderedJobSpec(ctx, job.Name)
+	if err == nil {
+		err = srv.Archive.Put(archiveRenderedSpecName(job.Name), bytes.NewReader(rendered))
+		if err != nil {
+			return xerrors.Errorf("cannot archive rendered job spec: %w", err)
+		}
+	} else if err != store.ErrNotFound {
+		return xerrors.Errorf("cannot read rendered job spec: %w", err)
+	}
+
+	// We mark the job as archived before we remove anything, s.t. we never lose track of where its data is.
+	if job.Conditions == nil {
+		job.Conditions = &v1.JobConditions{}
+	}
+	job.Conditions.Archived = true
+	err = srv.Jobs.Store(ctx, *job)
+	if err != nil {
+		return err
+	}
+
+	err = srv.Logs.Delete(job.Name)
+	if err != nil && err != store.ErrNotFound {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot delete archived job log")
+	}
+	err = srv.Jobs.DeleteJobSpec(ctx, job.Name)
+	if err != nil && err != store.ErrNotFound {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot delete archived job spec")
+	}
+	return nil
+}
+
+// readLog reads the log of a job, from the archive if the job was archived
+func (srv *Service) readLog(name string) (io.ReadCloser, error) {
+	rd, err := srv.Logs.Read(name)
+	if err != store.ErrNotFound || srv.Archive == nil {
+		return rd, err
+	}
+	return srv.Archive.Get(archiveLogName(name))
+}
+
+// getJobSpec retrieves the job spec of a job, from the archive if the job was archived
+func (srv *Service) getJobSpec(ctx context.Context, name string) ([]byte, error) {
+	data, err := srv.Jobs.GetJobSpec(ctx, name)
+	if err != store.ErrNotFound || srv.Archive == nil {
+		return data, err
+	}
+
+	rd, err := srv.Archive.Get(archiveSpecName(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return ioutil.ReadAll(rd)
+}
+
+// getRenderedJobSpec retrieves the rendered job spec of a job, from the archive if the job was archived
+func (srv *Service) getRenderedJobSpec(ctx context.Context, name string) ([]byte, error) {
+	data, err := srv.Jobs.GetRenderedJobSpec(ctx, name)
+	if err != store.ErrNotFound || srv.Archive == nil {
+		return data, err
+	}
+
+	rd, err := srv.Archive.Get(archiveRenderedSpecName(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return ioutil.ReadAll(rd)
+}
+
+// deleteArchived removes the archived data of a job
+func (srv *Service) deleteArchived(name string) error {
+	if srv.Archive == nil {
+		return nil
+	}
+	for _, fn := range []string{archiveLogName(name), archiveSpecName(name), archiveRenderedSpecName(name)} {
+		err := srv.Archive.Delete(fn)
+		if err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package werft
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scope limits what a token can be used for
+type Scope string
+
+const (
+	// ScopeAdmin grants access to the admin API
+	ScopeAdmin Scope = "admin"
+	// ScopeTrigger grants starting, stopping and annotating jobs. It can be limited to a repository
+	// using trigger:<owner>/<repo>, or all repositories of an owner using trigger:<owner>/*.
+	// Tokens without a trigger scope may change the jobs of all repositories.
+	ScopeTrigger Scope = "trigger"
+	// ScopeImpersonate grants starting jobs on behalf of another user, e.g. for bots which start jobs for the person who asked them to
+	ScopeImpersonate Scope = "impersonate"
+	// ScopePriority grants starting jobs with high priority. Without it only the priority rules can make a job jump the queue.
+	ScopePriority Scope = "priority"
+)
+
+// ValidateScope checks if a scope is known
+func ValidateScope(s Scope) error {
+	if s == ScopeAdmin || s == ScopeTrigger || s == ScopeImpersonate || s == ScopePriority {
+		return nil
+	}
+	if repo := strings.TrimPrefix(string(s), string(ScopeTrigger)+":"); repo != string(s) {
+		segs := strings.Split(repo, "/")
+		if len(segs) != 2 || segs[0] == "" || segs[0] == "*" || segs[1] == "" {
+			return xerrors.Errorf("scope \"%s\" must name a repository, e.g. %s:owner/repo or %s:owner/*", s, ScopeTrigger, ScopeTrigger)
+		}
+		return nil
+	}
+	return xerrors.Errorf("unknown scope \"%s\" - must be %s, %s, %s:owner/repo, %s or %s", s, ScopeAdmin, ScopeTrigger, ScopeTrigger, ScopeImpersonate, ScopePriority)
+}
+
+// AnonymousAccess controls what requests without a token may do
+type AnonymousAccess string
+
+const (
+	// AnonymousFull lets requests without a token do anything but administer werft
+	AnonymousFull AnonymousAccess = "full"
+	// AnonymousReadOnly lets requests without a token list jobs and view their logs, but not start, stop or annotate jobs
+	AnonymousReadOnly AnonymousAccess = "read-only"
+)
+
+// TokenConfig configures a static API token
+type TokenConfig struct {
+	// Name identifies the token, e.g. in logs or when listing tokens
+	Name string `yaml:"name"`
+	// Token is the secret clients present as bearer token
+	Token string `yaml:"token"`
+	// Scopes lists what this token may be used for
+	Scopes []Scope `yaml:"scopes"`
+
+	// serviceAccount is the service account of service account tokens, and empty for those from the config
+	serviceAccount string
+}
+
+// HasScope returns true if the token was granted the scope
+func (t *TokenConfig) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// MayChange returns true if the token may change the jobs of a repository
+func (t *TokenConfig) MayChange(repo *v1.Repository) bool {
+	var limited bool
+	for _, s := range t.Scopes {
+		if s == ScopeAdmin || s == ScopeTrigger {
+			return true
+		}
+		r := strings.TrimPrefix(string(s), string(ScopeTrigger)+":")
+		if r == string(s) {
+			continue
+		}
+		limited = true
+		if repo == nil {
+			continue
+		}
+		segs := strings.Split(r, "/")
+		if len(segs) == 2 && segs[0] == repo.Owner && (segs[1] == "*" || segs[1] == repo.Repo) {
+			return true
+		}
+	}
+	return !limited
+}
+
+// internalCallerKey is the context key of the identity of in-process callers
+type internalCallerKey struct{}
+
+// WithInternalCaller marks a context as belonging to an in-process caller, e.g. the operator. Such callers are
+// authenticated like a token of that name which may trigger jobs on behalf of anyone. They need no token because
+// whatever they act upon was authorized elsewhere, e.g. by Kubernetes. Requests we receive over the network cannot
+// carry this mark.
+func WithInternalCaller(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, internalCallerKey{}, &TokenConfig{
+		Name:   name,
+		Scopes: []Scope{ScopeTrigger, ScopeImpersonate, ScopePriority},
+	})
+}
+
+// authenticate finds the token presented in the request metadata, be it one from the config or a service account token.
+// If the request carries no token we return nil.
+func (srv *Service) authenticate(ctx context.Context) (*TokenConfig, error) {
+	if tkn, ok := ctx.Value(internalCallerKey{}).(*TokenConfig); ok {
+		return tkn, nil
+	}
+
+	tkn, err := srv.authenticateRequest(ctx)
+	recordCallIdentity(ctx, tkn, err)
+	return tkn, err
+}
+
+// authenticateRequest finds the token the request carries in its metadata
+func (srv *Service) authenticateRequest(ctx context.Context) (*TokenConfig, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	vals := md.Get("authorization")
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(vals[0], prefix) {
+		return nil, status.Error(codes.Unauthenticated, "unsupported authorization scheme")
+	}
+	presented := strings.TrimPrefix(vals[0], prefix)
+	tokens := srv.config().Tokens
+	for i, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(t.Token)) == 1 {
+			return &tokens[i], nil
+		}
+	}
+
+	tkn, err := srv.authenticateServiceAccount(ctx, presented)
+	if err != nil {
+		return nil, err
+	}
+	if tkn != nil {
+		return tkn, nil
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "invalid token")
+}
+
+// requireToken authenticates the request and fails if it does not carry a token
+func (srv *Service) requireToken(ctx context.Context) (*TokenConfig, error) {
+	tkn, err := srv.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tkn == nil {
+		return nil, status.Error(codes.Unauthenticated, "this call requires a token")
+	}
+	return tkn, nil
+}
+
+// authorizeWrite makes sure the request may change the jobs of a repository, e.g. start or stop them. Requests with a
+// valid token may unless its trigger scopes exclude the repository, and so may requests without a token unless anonymous
+// access is read-only.
+func (srv *Service) authorizeWrite(ctx context.Context, repo *v1.Repository) error {
+	tkn, err := srv.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	return srv.mayWrite(tkn, repo)
+}
+
+// anonymousIdentity is the identity of requests without a token, e.g. the owner of the jobs they start
+const anonymousIdentity = "anonymous"
+
+// identity returns who a token (nil for requests without one) belongs to
+func identity(tkn *TokenConfig) string {
+	if tkn == nil {
+		return anonymousIdentity
+	}
+	return tkn.Name
+}
+
+// mayImpersonate returns true if a token (nil for requests without one) may start jobs on behalf of someone else
+func mayImpersonate(tkn *TokenConfig) bool {
+	return tkn != nil && (tkn.HasScope(ScopeAdmin) || tkn.HasScope(ScopeImpersonate))
+}
+
+// mayPrioritize returns an error if a token (nil for requests without one) must not start the job with its priority.
+// High priority needs the priority scope, lest everyone who can start jobs skips the queue.
+func mayPrioritize(tkn *TokenConfig, md *v1.JobMetadata) error {
+	if jobPriority(md) != PriorityHigh {
+		return nil
+	}
+	if tkn != nil && (tkn.HasScope(ScopeAdmin) || tkn.HasScope(ScopePriority)) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "starting jobs with %s priority requires the %s scope", PriorityHigh, ScopePriority)
+}
+
+// authorizeStart makes sure the request may start a job with the metadata. On top of what authorizeWrite checks, the
+// job may only ask for high priority if mayPrioritize permits, and the owner of a job started with a token must be the
+// name of that token. Jobs without owner are owned by whoever makes
+// the request. Starting jobs on behalf of someone else needs a token with the impersonate scope. We set triggered_by
+// to the name of that token, s.t. the job records who actually started it.
+//
+// Requests without a token can only start jobs if anonymous access is full. We cannot tell who makes them, hence they
+// keep the owner they claim, e.g. the Git user older clients send.
+func (srv *Service) authorizeStart(ctx context.Context, md *v1.JobMetadata) error {
+	tkn, err := srv.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	err = srv.mayWrite(tkn, md.Repository)
+	if err != nil {
+		return err
+	}
+	err = mayPrioritize(tkn, md)
+	if err != nil {
+		return err
+	}
+
+	caller := identity(tkn)
+	if md.Owner == "" {
+		md.Owner = caller
+	}
+	if md.Owner == caller || tkn == nil {
+		md.TriggeredBy = ""
+		return nil
+	}
+
+	logger := log.WithFields(log.Fields{
+		"audit":   "impersonation",
+		"owner":   md.Owner,
+		"claimed": md.TriggeredBy,
+		"caller":  caller,
+	})
+	if md.Repository != nil {
+		logger = logger.WithField("repo", md.Repository.Owner+"/"+md.Repository.Repo)
+	}
+	if !mayImpersonate(tkn) {
+		logger.Warn("rejected request to start a job on behalf of someone else")
+		return status.Errorf(codes.PermissionDenied, "token %s may only start jobs as %s - starting jobs on behalf of %s requires the %s scope", tkn.Name, tkn.Name, md.Owner, ScopeImpersonate)
+	}
+	logger.Info("starting job on behalf of its owner")
+	md.TriggeredBy = tkn.Name
+	return nil
+}
+
+// authorizeReplay makes sure the request may start a job from the metadata of a previous one, which keeps the owner of
+// the previous job. triggered_by is set to whoever makes the request, unless that is the owner anyways.
+func (srv *Service) authorizeReplay(ctx context.Context, md *v1.JobMetadata) error {
+	tkn, err := srv.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	err = srv.mayWrite(tkn, md.Repository)
+	if err != nil {
+		return err
+	}
+
+	md.TriggeredBy = ""
+	if caller := identity(tkn); caller != md.Owner {
+		md.TriggeredBy = caller
+	}
+	return nil
+}
+
+// mayWrite checks if a token (nil for requests without one) may change the jobs of a repository
+func (srv *Service) mayWrite(tkn *TokenConfig, repo *v1.Repository) error {
+	if tkn == nil {
+		if srv.config().AnonymousAccess == AnonymousReadOnly {
+			return status.Error(codes.Unauthenticated, "this werft installation is read-only without a token")
+		}
+		return nil
+	}
+	if !tkn.MayChange(repo) {
+		if repo == nil {
+			return status.Errorf(codes.PermissionDenied, "token %s may only change jobs of particular repositories", tkn.Name)
+		}
+		return status.Errorf(codes.PermissionDenied, "token %s may not change jobs of %s/%s", tkn.Name, repo.Owner, repo.Repo)
+	}
+	return nil
+}
+
+// authorize makes sure the request carries a token with the required scope
+func (srv *Service) authorize(ctx context.Context, scope Scope) error {
+	tkn, err := srv.requireToken(ctx)
+	if err != nil {
+		return err
+	}
+	if !tkn.HasScope(scope) {
+		return status.Errorf(codes.PermissionDenied, "token %s lacks the %s scope", tkn.Name, scope)
+	}
+	return nil
+}
@@ -0,0 +1,421 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/operator"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testTokens = []werft.TokenConfig{
+	{Name: "admin", Token: "admin-secret", Scopes: []werft.Scope{werft.ScopeAdmin}},
+	{Name: "bot", Token: "bot-secret", Scopes: []werft.Scope{werft.ScopeTrigger, werft.ScopeImpersonate}},
+	{Name: "ci", Token: "ci-secret", Scopes: []werft.Scope{werft.ScopeTrigger}},
+	{Name: "werft-ci", Token: "werft-ci-secret", Scopes: []werft.Scope{"trigger:32leaves/werft"}},
+	{Name: "32leaves-ci", Token: "32leaves-ci-secret", Scopes: []werft.Scope{"trigger:32leaves/*"}},
+	{Name: "reader", Token: "reader-secret"},
+	{Name: "release", Token: "release-secret", Scopes: []werft.Scope{werft.ScopeTrigger, werft.ScopePriority}},
+}
+
+func testService(access werft.AnonymousAccess) *werft.Service {
+	return &werft.Service{Config: werft.Config{Tokens: testTokens, AnonymousAccess: access}}
+}
+
+// withAuthorization produces a context which carries the authorization header of an incoming request. An empty header
+// produces a context without authorization.
+func withAuthorization(header string) context.Context {
+	if header == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", header))
+}
+
+func bearer(token string) context.Context {
+	if token == "" {
+		return context.Background()
+	}
+	return withAuthorization("Bearer " + token)
+}
+
+func testRepo() *v1.Repository {
+	return &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}
+}
+
+func TestAuthorizeStart(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Token       string
+		Owner       string
+		TriggeredBy string
+		Priority    string
+		Code        codes.Code
+		// ExpOwner and ExpTriggeredBy are the metadata after authorization
+		ExpOwner       string
+		ExpTriggeredBy string
+	}{
+		{Name: "anonymous without owner", ExpOwner: "anonymous"},
+		{Name: "anonymous as anonymous", Owner: "anonymous", ExpOwner: "anonymous"},
+		{Name: "anonymous as someone else", Owner: "alice", ExpOwner: "alice"},
+		{Name: "anonymous drops claimed triggered_by", Owner: "alice", TriggeredBy: "bot", ExpOwner: "alice"},
+		{Name: "token without owner", Token: "ci-secret", ExpOwner: "ci"},
+		{Name: "token as itself", Token: "ci-secret", Owner: "ci", ExpOwner: "ci"},
+		{Name: "token drops claimed triggered_by", Token: "ci-secret", Owner: "ci", TriggeredBy: "bot", ExpOwner: "ci"},
+		{Name: "token as someone else", Token: "ci-secret", Owner: "alice", Code: codes.PermissionDenied},
+		{Name: "impersonate", Token: "bot-secret", Owner: "alice", ExpOwner: "alice", ExpTriggeredBy: "bot"},
+		{Name: "impersonate replaces claimed triggered_by", Token: "bot-secret", Owner: "alice", TriggeredBy: "mallory", ExpOwner: "alice", ExpTriggeredBy: "bot"},
+		{Name: "admin impersonates", Token: "admin-secret", Owner: "alice", ExpOwner: "alice", ExpTriggeredBy: "admin"},
+		{Name: "invalid token", Token: "guess", Owner: "alice", Code: codes.Unauthenticated},
+		{Name: "token for other repository", Token: "werft-ci-secret", Owner: "werft-ci", ExpOwner: "werft-ci"},
+		{Name: "anonymous with low priority", Priority: werft.PriorityLow, ExpOwner: "anonymous"},
+		{Name: "anonymous with high priority", Priority: werft.PriorityHigh, Code: codes.PermissionDenied},
+		{Name: "token with high priority", Token: "ci-secret", Priority: werft.PriorityHigh, Code: codes.PermissionDenied},
+		{Name: "priority scope with high priority", Token: "release-secret", Priority: werft.PriorityHigh, ExpOwner: "release"},
+		{Name: "admin with high priority", Token: "admin-secret", Priority: werft.PriorityHigh, ExpOwner: "admin"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			md := &v1.JobMetadata{Owner: test.Owner, TriggeredBy: test.TriggeredBy, Repository: testRepo()}
+			if test.Priority != "" {
+				md.Annotations = append(md.Annotations, &v1.Annotation{Key: "werft.sh/priority", Value: test.Priority})
+			}
+			err := testService(werft.AnonymousFull).AuthorizeStart(bearer(test.Token), md)
+			if code := status.Code(err); code != test.Code {
+				t.Fatalf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+			if md.Owner != test.ExpOwner || md.TriggeredBy != test.ExpTriggeredBy {
+				t.Errorf("unexpected owner %q triggered by %q, expected %q triggered by %q", md.Owner, md.TriggeredBy, test.ExpOwner, test.ExpTriggeredBy)
+			}
+		})
+	}
+}
+
+func TestAuthorizeStartOperator(t *testing.T) {
+	spec := operator.JobSpec{Repository: "32leaves/werft", Ref: "refs/heads/master"}
+	req, err := spec.Request("default/build")
+	if err != nil {
+		t.Fatalf("cannot produce request: %v", err)
+	}
+	ctx := werft.WithInternalCaller(context.Background(), "operator")
+
+	for _, access := range []werft.AnonymousAccess{werft.AnonymousFull, werft.AnonymousReadOnly} {
+		t.Run(string(access), func(t *testing.T) {
+			md := proto.Clone(req.Metadata).(*v1.JobMetadata)
+			err := testService(access).AuthorizeStart(ctx, md)
+			if err != nil {
+				t.Fatalf("operator cannot start jobs: %v", err)
+			}
+			if md.Owner != "kubernetes" || md.TriggeredBy != "operator" {
+				t.Errorf("unexpected owner %q triggered by %q, expected \"kubernetes\" triggered by \"operator\"", md.Owner, md.TriggeredBy)
+			}
+		})
+	}
+
+	// the mark must not leak to requests we receive, which carry their own authorization
+	md := proto.Clone(req.Metadata).(*v1.JobMetadata)
+	err = testService(werft.AnonymousReadOnly).AuthorizeStart(context.Background(), md)
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("unexpected code %v for anonymous request, expected %v: %v", code, codes.Unauthenticated, err)
+	}
+}
+
+func TestAuthorizeReplay(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Token          string
+		Owner          string
+		TriggeredBy    string
+		ExpTriggeredBy string
+	}{
+		{Name: "owner replays", Token: "ci-secret", Owner: "ci"},
+		{Name: "someone else replays", Token: "ci-secret", Owner: "alice", ExpTriggeredBy: "ci"},
+		{Name: "anonymous replays", Owner: "alice", ExpTriggeredBy: "anonymous"},
+		{Name: "replay of impersonated job", Token: "ci-secret", Owner: "alice", TriggeredBy: "bot", ExpTriggeredBy: "ci"},
+		{Name: "owner replays impersonated job", Token: "ci-secret", Owner: "ci", TriggeredBy: "bot"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			md := &v1.JobMetadata{Owner: test.Owner, TriggeredBy: test.TriggeredBy, Repository: testRepo()}
+			err := testService(werft.AnonymousFull).AuthorizeReplay(bearer(test.Token), md)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if md.Owner != test.Owner {
+				t.Errorf("replay changed the owner to %q", md.Owner)
+			}
+			if md.TriggeredBy != test.ExpTriggeredBy {
+				t.Errorf("unexpected triggered by %q, expected %q", md.TriggeredBy, test.ExpTriggeredBy)
+			}
+		})
+	}
+}
+
+func TestValidateScope(t *testing.T) {
+	tests := []struct {
+		Scope werft.Scope
+		Valid bool
+	}{
+		{werft.ScopeAdmin, true},
+		{werft.ScopeTrigger, true},
+		{werft.ScopeImpersonate, true},
+		{werft.ScopePriority, true},
+		{"trigger:32leaves/werft", true},
+		{"trigger:32leaves/*", true},
+		{"trigger:*/*", false},
+		{"trigger:32leaves", false},
+		{"trigger:32leaves/", false},
+		{"trigger:/werft", false},
+		{"trigger:32leaves/werft/extra", false},
+		{"trigger:", false},
+		{"", false},
+		{"root", false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.Scope), func(t *testing.T) {
+			err := werft.ValidateScope(test.Scope)
+			if (err == nil) != test.Valid {
+				t.Errorf("unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestMayChange(t *testing.T) {
+	var (
+		werftRepo = &v1.Repository{Owner: "32leaves", Repo: "werft"}
+		otherRepo = &v1.Repository{Owner: "32leaves", Repo: "other"}
+		foreign   = &v1.Repository{Owner: "someone", Repo: "werft"}
+	)
+	tests := []struct {
+		Name   string
+		Scopes []werft.Scope
+		Repo   *v1.Repository
+		Expect bool
+	}{
+		{"no scopes", nil, werftRepo, true},
+		{"unrelated scopes", []werft.Scope{werft.ScopeImpersonate}, werftRepo, true},
+		{"admin", []werft.Scope{werft.ScopeAdmin}, foreign, true},
+		{"trigger", []werft.Scope{werft.ScopeTrigger}, foreign, true},
+		{"repository", []werft.Scope{"trigger:32leaves/werft"}, werftRepo, true},
+		{"other repository", []werft.Scope{"trigger:32leaves/werft"}, otherRepo, false},
+		{"owner wildcard", []werft.Scope{"trigger:32leaves/*"}, otherRepo, true},
+		{"owner wildcard of other owner", []werft.Scope{"trigger:32leaves/*"}, foreign, false},
+		{"one of several repositories", []werft.Scope{"trigger:32leaves/other", "trigger:32leaves/werft"}, werftRepo, true},
+		{"limited without repository", []werft.Scope{"trigger:32leaves/werft"}, nil, false},
+		{"unlimited without repository", []werft.Scope{werft.ScopeTrigger}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tkn := &werft.TokenConfig{Name: "test", Scopes: test.Scopes}
+			if act := tkn.MayChange(test.Repo); act != test.Expect {
+				t.Errorf("unexpected result %v, expected %v", act, test.Expect)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Authorization string
+		Scope         werft.Scope
+		Code          codes.Code
+	}{
+		{"admin", "Bearer admin-secret", werft.ScopeAdmin, codes.OK},
+		{"trigger", "Bearer ci-secret", werft.ScopeTrigger, codes.OK},
+		{"lacks admin", "Bearer ci-secret", werft.ScopeAdmin, codes.PermissionDenied},
+		{"no scopes", "Bearer reader-secret", werft.ScopeAdmin, codes.PermissionDenied},
+		{"repository scope is no trigger scope", "Bearer werft-ci-secret", werft.ScopeTrigger, codes.PermissionDenied},
+		{"missing token", "", werft.ScopeAdmin, codes.Unauthenticated},
+		{"invalid token", "Bearer guess", werft.ScopeAdmin, codes.Unauthenticated},
+		{"bad scheme", "Basic YWRtaW46YWRtaW4tc2VjcmV0", werft.ScopeAdmin, codes.Unauthenticated},
+		{"token without scheme", "admin-secret", werft.ScopeAdmin, codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := testService(werft.AnonymousFull).Authorize(withAuthorization(test.Authorization), test.Scope)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWriteScopes(t *testing.T) {
+	var (
+		werftRepo = testRepo()
+		otherRepo = &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "other"}
+		foreign   = &v1.Repository{Host: "github.com", Owner: "someone", Repo: "werft"}
+	)
+	tests := []struct {
+		Name          string
+		Authorization string
+		Repo          *v1.Repository
+		Code          codes.Code
+	}{
+		{"admin", "Bearer admin-secret", foreign, codes.OK},
+		{"trigger", "Bearer ci-secret", foreign, codes.OK},
+		{"repository", "Bearer werft-ci-secret", werftRepo, codes.OK},
+		{"other repository", "Bearer werft-ci-secret", otherRepo, codes.PermissionDenied},
+		{"owner wildcard", "Bearer 32leaves-ci-secret", otherRepo, codes.OK},
+		{"owner wildcard of other owner", "Bearer 32leaves-ci-secret", foreign, codes.PermissionDenied},
+		{"limited without repository", "Bearer werft-ci-secret", nil, codes.PermissionDenied},
+		{"invalid token", "Bearer guess", werftRepo, codes.Unauthenticated},
+		{"bad scheme", "Basic Y2ktc2VjcmV0", werftRepo, codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := testService(werft.AnonymousFull).AuthorizeWrite(withAuthorization(test.Authorization), test.Repo)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+		})
+	}
+}
+
+func TestAdminRequiresAdminScope(t *testing.T) {
+	calls := map[string]func(srv *werft.Service, ctx context.Context) error{
+		"RequeueStuckJobs": func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.RequeueStuckJobs(ctx, &v1.RequeueStuckJobsRequest{})
+			return err
+		},
+		"Prune": func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.Prune(ctx, &v1.PruneRequest{})
+			return err
+		},
+		"SetDrain": func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.SetDrain(ctx, &v1.SetDrainRequest{Drain: true})
+			return err
+		},
+		"ListTokens": func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.ListTokens(ctx, &v1.ListTokensRequest{})
+			return err
+		},
+	}
+	callers := []struct {
+		Name  string
+		Token string
+		Code  codes.Code
+	}{
+		{"anonymous", "", codes.Unauthenticated},
+		{"invalid token", "guess", codes.Unauthenticated},
+		{"trigger token", "ci-secret", codes.PermissionDenied},
+		{"impersonate token", "bot-secret", codes.PermissionDenied},
+		{"token without scopes", "reader-secret", codes.PermissionDenied},
+	}
+
+	for name, call := range calls {
+		for _, caller := range callers {
+			t.Run(name+"/"+caller.Name, func(t *testing.T) {
+				srv := testService(werft.AnonymousFull)
+				err := call(srv, bearer(caller.Token))
+				if code := status.Code(err); code != caller.Code {
+					t.Errorf("unexpected code %v, expected %v: %v", code, caller.Code, err)
+				}
+				if srv.Draining() {
+					t.Errorf("rejected call changed the service")
+				}
+			})
+		}
+	}
+
+	_, err := testService(werft.AnonymousFull).ListTokens(bearer("admin-secret"), &v1.ListTokensRequest{})
+	if err != nil {
+		t.Errorf("admin token was rejected: %v", err)
+	}
+}
+
+func TestAnonymousAccess(t *testing.T) {
+	const jobName = "werft-build-master.1"
+	newService := func(access werft.AnonymousAccess) *werft.Service {
+		jobs := store.NewInMemoryJobStore()
+		err := jobs.Store(context.Background(), v1.JobStatus{
+			Name:     jobName,
+			Metadata: &v1.JobMetadata{Owner: "alice", Repository: testRepo()},
+			Phase:    v1.JobPhase_PHASE_DONE,
+		})
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+		srv := testService(access)
+		srv.Jobs = jobs
+		return srv
+	}
+
+	calls := []struct {
+		Name  string
+		Write bool
+		// Code is what the call returns once it's authorized
+		Code codes.Code
+		Call func(srv *werft.Service, ctx context.Context) error
+	}{
+		{"StartGitHubJob", true, codes.OK, func(srv *werft.Service, ctx context.Context) error {
+			// StartGitHubJob would go on to talk to GitHub, hence we check its authorization only
+			return srv.AuthorizeStart(ctx, &v1.JobMetadata{Repository: testRepo()})
+		}},
+		{"StartFromPreviousJob", true, codes.NotFound, func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.StartFromPreviousJob(ctx, &v1.StartFromPreviousJobRequest{PreviousJob: jobName})
+			return err
+		}},
+		{"StopJob", true, codes.FailedPrecondition, func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.StopJob(ctx, &v1.StopJobRequest{Name: jobName})
+			return err
+		}},
+		{"AnnotateJob", true, codes.OK, func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.AnnotateJob(ctx, &v1.AnnotateJobRequest{Name: jobName, Annotations: []*v1.Annotation{{Key: "foo", Value: "bar"}}})
+			return err
+		}},
+		{"ListJobs", false, codes.OK, func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.ListJobs(ctx, &v1.ListJobsRequest{})
+			return err
+		}},
+		{"GetJob", false, codes.OK, func(srv *werft.Service, ctx context.Context) error {
+			_, err := srv.GetJob(ctx, &v1.GetJobRequest{Name: jobName})
+			return err
+		}},
+	}
+	callers := []struct {
+		Name   string
+		Access werft.AnonymousAccess
+		Token  string
+		// Denied is the code of write calls the caller may not make, OK if it may make them
+		Denied codes.Code
+	}{
+		{"anonymous with default access", "", "", codes.OK},
+		{"anonymous with full access", werft.AnonymousFull, "", codes.OK},
+		{"anonymous with read-only access", werft.AnonymousReadOnly, "", codes.Unauthenticated},
+		{"token with read-only access", werft.AnonymousReadOnly, "ci-secret", codes.OK},
+		{"limited token with read-only access", werft.AnonymousReadOnly, "werft-ci-secret", codes.OK},
+		{"invalid token with read-only access", werft.AnonymousReadOnly, "guess", codes.Unauthenticated},
+	}
+
+	for _, call := range calls {
+		for _, caller := range callers {
+			t.Run(call.Name+"/"+caller.Name, func(t *testing.T) {
+				exp := call.Code
+				if call.Write && caller.Denied != codes.OK {
+					exp = caller.Denied
+				}
+				err := call.Call(newService(caller.Access), bearer(caller.Token))
+				if code := status.Code(err); code != exp {
+					t.Errorf("unexpected code %v, expected %v: %v", code, exp, err)
+				}
+			})
+		}
+	}
+}
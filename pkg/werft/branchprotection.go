@@ -0,0 +1,59 @@
+package werft
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+)
+
+// changesWerftConfig returns true if a push modifies the werft config of a repository
+func changesWerftConfig(event *github.PushEvent) bool {
+	for _, c := range event.Commits {
+		for _, files := range [][]string{c.Added, c.Modified} {
+			for _, f := range files {
+				if f == PathWerftConfig {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// syncBranchProtection makes the werft status contexts required checks of the protected branches listed in the repo config
+func (srv *Service) syncBranchProtection(ctx context.Context, owner, repo string, cfg *repoconfig.BranchProtection) {
+	if srv.GitHub.Client == nil {
+		return
+	}
+
+	for _, branch := range cfg.Branches {
+		l := log.WithField("repo", owner+"/"+repo).WithField("branch", branch)
+
+		checks, resp, err := srv.GitHub.Client.Repositories.GetRequiredStatusChecks(ctx, owner, repo, branch)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			l.Warn("cannot sync branch protection: the branch is not protected or does not require status checks")
+			continue
+		}
+		if err != nil {
+			l.WithError(err).Warn("cannot sync branch protection")
+			continue
+		}
+
+		contexts, changed := cfg.RequiredContexts(checks.Contexts, werftGithubContext)
+		if !changed {
+			continue
+		}
+		_, _, err = srv.GitHub.Client.Repositories.UpdateRequiredStatusChecks(ctx, owner, repo, branch, &github.RequiredStatusChecksRequest{
+			Strict:   &checks.Strict,
+			Contexts: contexts,
+		})
+		if err != nil {
+			l.WithError(err).Warn("cannot sync branch protection")
+			continue
+		}
+		l.WithField("contexts", contexts).Info("updated required status checks")
+	}
+}
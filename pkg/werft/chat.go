@@ -0,0 +1,333 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/32leaves/werft/pkg/analytics"
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// previousRunLookback is the number of recent runs we look at to find the previous run of a job
+const previousRunLookback = 10
+
+// telegramAPI is the base URL of the Telegram Bot API
+const telegramAPI = "https://api.telegram.org"
+
+// matrixTxnCounter makes the transaction IDs of Matrix messages unique
+var matrixTxnCounter uint64
+
+// defaultChatTemplate produces the body of chat webhook messages if the route does not configure a template
+const defaultChatTemplate = `{"text": {{ json .Text }}}`
+
+// chatMessage is a message about a job we post to chat tools
+type chatMessage struct {
+	Job *v1.JobStatus
+	// URL links to the job on the werft UI
+	URL string
+	// Summary says what happened to the job, e.g. "failed"
+	Summary string
+	// Details add to the summary, e.g. the reason the job failed. Can be empty.
+	Details string
+}
+
+// newChatMessage produces a message about a job
+func (srv *Service) newChatMessage(job *v1.JobStatus, summary, details string) *chatMessage {
+	return &chatMessage{
+		Job:     job,
+		URL:     srv.jobURL(job),
+		Summary: summary,
+		Details: details,
+	}
+}
+
+// Text renders the message as plain text
+func (m *chatMessage) Text() string {
+	res := fmt.Sprintf("%s %s: %s", m.Job.Name, m.Summary, m.URL)
+	if m.Details != "" {
+		res += "\n" + m.Details
+	}
+	return res
+}
+
+// HTML renders the message as HTML
+func (m *chatMessage) HTML() string {
+	res := fmt.Sprintf(`<a href="%s">%s</a> %s`, html.EscapeString(m.URL), html.EscapeString(m.Job.Name), html.EscapeString(m.Summary))
+	if m.Details != "" {
+		res += "<br>" + html.EscapeString(m.Details)
+	}
+	return res
+}
+
+// postSlackMessage posts a message to a Slack incoming webhook
+func postSlackMessage(ctx context.Context, route *repoconfig.SlackResultRoute, m *chatMessage) error {
+	text := fmt.Sprintf("<%s|%s> %s", m.URL, m.Job.Name, m.Summary)
+	if m.Details != "" {
+		text += "\n" + m.Details
+	}
+	msg := struct {
+		Channel string `json:"channel,omitempty"`
+		Text    string `json:"text"`
+	}{
+		Channel: route.Channel,
+		Text:    text,
+	}
+	return postJSON(ctx, route.URL, msg)
+}
+
+// postTeamsMessage posts a message card to a Microsoft Teams incoming webhook
+func postTeamsMessage(ctx context.Context, route *repoconfig.TeamsResultRoute, m *chatMessage) error {
+	type target struct {
+		OS  string `json:"os"`
+		URI string `json:"uri"`
+	}
+	type action struct {
+		Type    string   `json:"@type"`
+		Name    string   `json:"name"`
+		Targets []target `json:"targets"`
+	}
+	title := m.Job.Name + " " + m.Summary
+	msg := struct {
+		Type            string   `json:"@type"`
+		Context         string   `json:"@context"`
+		Summary         string   `json:"summary"`
+		Title           string   `json:"title"`
+		Text            string   `json:"text,omitempty"`
+		PotentialAction []action `json:"potentialAction"`
+	}{
+		Type:    "MessageCard",
+		Context: "https://schema.org/extensions",
+		Summary: title,
+		Title:   title,
+		Text:    m.Details,
+		PotentialAction: []action{
+			{Type: "OpenUri", Name: "Open job", Targets: []target{{OS: "default", URI: m.URL}}},
+		},
+	}
+	return postJSON(ctx, route.URL, msg)
+}
+
+// postChatMessage posts a message to a generic chat webhook, using the route's template to produce the message body
+func postChatMessage(ctx context.Context, route *repoconfig.ChatResultRoute, m *chatMessage) error {
+	src := route.Template
+	if src == "" {
+		src = defaultChatTemplate
+	}
+	tpl, err := template.New("chat").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(src)
+	if err != nil {
+		return xerrors.Errorf("invalid chat template: %w", err)
+	}
+	buf := bytes.NewBuffer(nil)
+	err = tpl.Execute(buf, m)
+	if err != nil {
+		return xerrors.Errorf("invalid chat template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return xerrors.Errorf("chat template did not produce JSON: %s", buf.String())
+	}
+	return postBody(ctx, route.URL, buf.Bytes())
+}
+
+// postTelegramMessage sends a message to a Telegram chat using the Bot API
+func postTelegramMessage(ctx context.Context, route *repoconfig.TelegramResultRoute, m *chatMessage) error {
+	if route.BotToken == "" || route.ChatID == "" {
+		return xerrors.Errorf("Telegram route needs a botToken and a chatID")
+	}
+	msg := struct {
+		ChatID                string `json:"chat_id"`
+		Text                  string `json:"text"`
+		DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	}{
+		ChatID:                route.ChatID,
+		Text:                  m.Text(),
+		DisableWebPagePreview: true,
+	}
+	err := postJSON(ctx, fmt.Sprintf("%s/bot%s/sendMessage", telegramAPI, route.BotToken), msg)
+	if err != nil {
+		// the bot token is part of the URL, which the error may contain
+		return xerrors.New(strings.ReplaceAll(err.Error(), route.BotToken, "<bot-token>"))
+	}
+	return nil
+}
+
+// postMatrixMessage sends a notice to a Matrix room using the client-server API
+func postMatrixMessage(ctx context.Context, route *repoconfig.MatrixResultRoute, m *chatMessage) error {
+	if route.Homeserver == "" || route.RoomID == "" || route.AccessToken == "" {
+		return xerrors.Errorf("Matrix route needs a homeserver, a roomID and an accessToken")
+	}
+	body, err := json.Marshal(struct {
+		MsgType       string `json:"msgtype"`
+		Body          string `json:"body"`
+		Format        string `json:"format"`
+		FormattedBody string `json:"formatted_body"`
+	}{
+		MsgType:       "m.notice",
+		Body:          m.Text(),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: m.HTML(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Matrix deduplicates messages by their transaction ID, hence each message needs its own
+	txnID := fmt.Sprintf("werft-%d-%d", time.Now().UnixNano(), atomic.AddUint64(&matrixTxnCounter, 1))
+	url := fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message/%s", strings.TrimSuffix(route.Homeserver, "/"), neturl.PathEscape(route.RoomID), txnID)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+route.AccessToken)
+	return sendBody(ctx, http.MethodPut, url, header, body)
+}
+
+// notifyJobEvent sends the notifications which the rules of the server and of the job's repository configure for events of a job.
+// The events are ordered from most to least specific, e.g. fixed before succeeded, and each rule notifies about the first
+// event it matches only. Result events pass the result.
+func (srv *Service) notifyJobEvent(cfg *repoconfig.C, job *v1.JobStatus, events []string, res *v1.JobResult) {
+	rules := srv.config().Notifications
+	if cfg != nil {
+		rules = append(append([]*repoconfig.Notification{}, rules...), cfg.Notifications...)
+	}
+	if len(rules) == 0 {
+		return
+	}
+	if snooze := srv.snoozedBy(job); snooze != nil {
+		log.WithField("name", job.Name).WithField("snooze", snooze.Id).Debug("notifications are snoozed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resultDeliveryTimeout)
+	defer cancel()
+	for _, n := range rules {
+		var event string
+		for _, e := range events {
+			if n.Matches(job, e, res) {
+				event = e
+				break
+			}
+		}
+		if event == "" {
+			continue
+		}
+
+		msg := srv.eventMessage(job, event, res)
+		logger := log.WithField("name", job.Name).WithField("event", event)
+		if n.Slack != nil {
+			if err := postSlackMessage(ctx, n.Slack, msg); err != nil {
+				logger.WithError(err).Warn("cannot send Slack notification")
+			}
+		}
+		if n.Teams != nil {
+			if err := postTeamsMessage(ctx, n.Teams, msg); err != nil {
+				logger.WithError(err).Warn("cannot send Teams notification")
+			}
+		}
+		if n.Chat != nil {
+			if err := postChatMessage(ctx, n.Chat, msg); err != nil {
+				logger.WithError(err).Warn("cannot send chat notification")
+			}
+		}
+		if n.Telegram != nil {
+			if err := postTelegramMessage(ctx, n.Telegram, msg); err != nil {
+				logger.WithError(err).Warn("cannot send Telegram notification")
+			}
+		}
+		if n.Matrix != nil {
+			if err := postMatrixMessage(ctx, n.Matrix, msg); err != nil {
+				logger.WithError(err).Warn("cannot send Matrix notification")
+			}
+		}
+	}
+}
+
+// eventMessage produces the chat message about an event of a job
+func (srv *Service) eventMessage(job *v1.JobStatus, event string, res *v1.JobResult) *chatMessage {
+	switch event {
+	case repoconfig.NotifyStarted:
+		if repo := job.Metadata.Repository; repo != nil {
+			return srv.newChatMessage(job, fmt.Sprintf("started on %s/%s@%s", repo.Owner, repo.Repo, repo.Ref), "")
+		}
+		return srv.newChatMessage(job, "started", "")
+	case repoconfig.NotifyFixed:
+		return srv.newChatMessage(job, "succeeded after the previous run failed", "")
+	case repoconfig.NotifyBroken:
+		return srv.newChatMessage(job, "failed after the previous run succeeded", job.Details)
+	case repoconfig.NotifyFailed:
+		return srv.newChatMessage(job, "failed", job.Details)
+	case repoconfig.NotifyResult:
+		if res != nil {
+			return srv.resultMessage(job, res)
+		}
+	}
+	return srv.newChatMessage(job, event, "")
+}
+
+// finishedJobEvents returns the events of a finished job, most specific first
+func (srv *Service) finishedJobEvents(job *v1.JobStatus) []string {
+	success := job.Conditions != nil && job.Conditions.Success
+
+	var events []string
+	if prev := srv.previousRun(job); prev != nil {
+		prevSuccess := prev.Conditions != nil && prev.Conditions.Success
+		if success && !prevSuccess {
+			events = append(events, repoconfig.NotifyFixed)
+		} else if !success && prevSuccess {
+			events = append(events, repoconfig.NotifyBroken)
+		}
+	}
+	if success {
+		events = append(events, repoconfig.NotifySucceeded)
+	} else {
+		events = append(events, repoconfig.NotifyFailed)
+	}
+	return events
+}
+
+// previousRun returns the finished run of the same job on the same ref which was started before a job, or nil if there is none
+func (srv *Service) previousRun(job *v1.JobStatus) *v1.JobStatus {
+	repo := job.Metadata.GetRepository()
+	if repo == nil {
+		return nil
+	}
+
+	runs, _, err := srv.Jobs.Find(context.Background(), []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done"}}},
+		{Terms: []*v1.FilterTerm{{Field: "repo.owner", Value: repo.Owner}}},
+		{Terms: []*v1.FilterTerm{{Field: "repo.repo", Value: repo.Repo}}},
+		{Terms: []*v1.FilterTerm{{Field: "repo.ref", Value: repo.Ref}}},
+		{Terms: []*v1.FilterTerm{{Field: "name", Value: analytics.JobName(job.Name) + ".", Operation: v1.FilterOp_OP_STARTS_WITH}}},
+	}, []*v1.OrderExpression{{Field: "created", Ascending: false}}, 0, previousRunLookback)
+	if err != nil {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot find previous run")
+		return nil
+	}
+
+	created, _ := ptypes.Timestamp(job.Metadata.Created)
+	for i := range runs {
+		run := &runs[i]
+		if run.Name == job.Name || analytics.JobName(run.Name) != analytics.JobName(job.Name) {
+			continue
+		}
+		if c, err := ptypes.Timestamp(run.Metadata.GetCreated()); err != nil || !c.Before(created) {
+			continue
+		}
+		return run
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// annotationCleanupAttempt is set on cleanup jobs and counts the attempts to clean up the workspace of a job
+	annotationCleanupAttempt = "cleanupAttempt"
+
+	// cleanupMaxAttempts is the number of cleanup jobs we start for a workspace before we give up
+	cleanupMaxAttempts = 5
+
+	// cleanupBackoff is the time we wait before the second attempt to clean up a workspace. It doubles with every attempt thereafter.
+	cleanupBackoff = 30 * time.Second
+)
+
+// cleanupJobWorkspace starts a cleanup job for a previously run job on the node its workspace lives on
+func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus, node string) {
+	srv.startCleanupJob(s, node, 1)
+}
+
+// startCleanupJob starts an attempt to clean up the workspace of a job. If the job cannot be started,
+// the attempt counts as failed.
+func (srv *Service) startCleanupJob(s *v1.JobStatus, node string, attempt int) {
+	name := s.Name
+	md := v1.JobMetadata{
+		Owner:      s.Metadata.Owner,
+		Repository: s.Metadata.Repository,
+		Trigger:    v1.JobTrigger_TRIGGER_UNKNOWN,
+		Created:    ptypes.TimestampNow(),
+		Annotations: []*v1.Annotation{
+			&v1.Annotation{
+				Key:   annotationCleanupJob,
+				Value: name,
+			},
+			&v1.Annotation{
+				Key:   annotationCleanupAttempt,
+				Value: strconv.Itoa(attempt),
+			},
+		},
+	}
+	// the cleanup job measures the workspace usage in kilobytes before removing the workspace and reports it as termination message
+	podspec := corev1.PodSpec{
+		NodeName: node,
+		Volumes: []corev1.Volume{
+			nodeWorkspaceVolume(srv.config().WorkspaceNodePathPrefix, name),
+		},
+		Containers: []corev1.Container{
+			corev1.Container{
+				Name:       "cleanup",
+				Image:      "alpine:latest",
+				Command:    []string{"sh", "-c", "du -sk . | cut -f1 > /dev/termination-log; rm -rf *"},
+				WorkingDir: "/workspace",
+				VolumeMounts: []corev1.VolumeMount{
+					corev1.VolumeMount{
+						Name:      executor.VolumeWorkspace,
+						MountPath: "/workspace",
+					},
+				},
+			},
+		},
+		RestartPolicy: corev1.RestartPolicyOnFailure,
+	}
+	podname := cleanupJobPrefix + name
+	if attempt > 1 {
+		// the pod of the previous attempt may still be around
+		podname = fmt.Sprintf("%s-%d", podname, attempt)
+	}
+
+	srv.updateJobCleanup(name, func(job *v1.JobStatus) {
+		job.Cleanup.State = v1.CleanupState_CLEANUP_PENDING
+		job.Cleanup.Attempts = int32(attempt)
+		job.Cleanup.Node = node
+	})
+
+	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithBackoff(3), executor.WithName(podname))
+	if err != nil {
+		log.WithError(err).WithField("name", name).Error("cannot start cleanup job")
+		srv.cleanupFailed(s, node, attempt, fmt.Sprintf("cannot start cleanup job: %v", err))
+	}
+}
+
+// cleanupJobFinished returns true the first time we see a cleanup job finish, s.t. we handle its outcome only once
+func (srv *Service) cleanupJobFinished(pod *corev1.Pod, s *v1.JobStatus) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if s.Phase == v1.JobPhase_PHASE_CLEANUP {
+		// the pod is on its way out and won't finish again
+		delete(srv.finishedCleanups, pod.Name)
+		return false
+	}
+	if s.Phase != v1.JobPhase_PHASE_DONE {
+		return false
+	}
+	if _, seen := srv.finishedCleanups[pod.Name]; seen {
+		return false
+	}
+	srv.finishedCleanups[pod.Name] = struct{}{}
+	return true
+}
+
+// recordCleanupOutcome stores the outcome of a cleanup job on the job it cleaned up after, and retries failed cleanups.
+// The cleanup job reports the workspace usage in kilobytes as its termination message.
+func (srv *Service) recordCleanupOutcome(pod *corev1.Pod, s *v1.JobStatus) {
+	if pod == nil || !strings.HasPrefix(pod.Name, cleanupJobPrefix) {
+		return
+	}
+
+	// cleanup jobs started by previous versions of werft name the job they clean up after in their pod name only
+	name := strings.TrimPrefix(pod.Name, cleanupJobPrefix)
+	attempt := 1
+	for _, a := range s.Metadata.Annotations {
+		switch a.Key {
+		case annotationCleanupJob:
+			if a.Value != "true" {
+				name = a.Value
+			}
+		case annotationCleanupAttempt:
+			if n, err := strconv.Atoi(a.Value); err == nil {
+				attempt = n
+			}
+		}
+	}
+
+	node := pod.Spec.NodeName
+	if s.Conditions == nil || !s.Conditions.Success {
+		details := s.Details
+		for _, cs := range pod.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil && t.ExitCode != 0 && details == "" {
+				details = fmt.Sprintf("cleanup job exited with code %d", t.ExitCode)
+			}
+		}
+		if details == "" {
+			details = "cleanup job failed"
+		}
+
+		job, err := srv.Jobs.Get(context.Background(), name)
+		if err != nil {
+			log.WithError(err).WithField("name", name).Warn("cannot retry cleanup job")
+			return
+		}
+		srv.cleanupFailed(job, node, attempt, details)
+		return
+	}
+
+	var usage int64
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil && t.ExitCode == 0 {
+			usage, _ = strconv.ParseInt(strings.TrimSpace(t.Message), 10, 64)
+		}
+	}
+
+	srv.mu.Lock()
+	delete(srv.cleanupFailures, node)
+	srv.mu.Unlock()
+
+	srv.updateJobCleanup(name, func(job *v1.JobStatus) {
+		job.Cleanup.State = v1.CleanupState_CLEANUP_DONE
+		job.Cleanup.Attempts = int32(attempt)
+		job.Cleanup.Node = node
+		job.Cleanup.Details = ""
+		if usage > 0 {
+			job.WorkspaceUsageBytes = usage * 1024
+		}
+	})
+}
+
+// cleanupFailed records a failed attempt to clean up the workspace of a job and retries with backoff until
+// we run out of attempts. It also fires the cleanup failure alerts of the node.
+func (srv *Service) cleanupFailed(job *v1.JobStatus, node string, attempt int, details string) {
+	log.WithField("name", job.Name).WithField("node", node).WithField("attempt", attempt).Warn("cleanup failed: " + details)
+
+	srv.mu.Lock()
+	srv.cleanupFailures[node]++
+	failures := srv.cleanupFailures[node]
+	srv.mu.Unlock()
+	srv.checkCleanupAlerts(job, node, failures, details)
+
+	state := v1.CleanupState_CLEANUP_PENDING
+	if attempt >= cleanupMaxAttempts {
+		state = v1.CleanupState_CLEANUP_FAILED
+	}
+	srv.updateJobCleanup(job.Name, func(job *v1.JobStatus) {
+		job.Cleanup.State = state
+		job.Cleanup.Attempts = int32(attempt)
+		job.Cleanup.Node = node
+		job.Cleanup.Details = details
+	})
+	if state == v1.CleanupState_CLEANUP_FAILED {
+		return
+	}
+
+	backoff := cleanupBackoff << uint(attempt-1)
+	time.AfterFunc(backoff, func() {
+		srv.startCleanupJob(job, node, attempt+1)
+	})
+}
+
+// updateJobCleanup changes the cleanup outcome stored on a job. The job passed to update always has a cleanup outcome.
+func (srv *Service) updateJobCleanup(name string, update func(job *v1.JobStatus)) {
+	ctx := context.Background()
+	job, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot record cleanup outcome")
+		return
+	}
+	if job.Cleanup == nil {
+		job.Cleanup = &v1.JobCleanup{}
+	}
+	update(job)
+	err = srv.Jobs.Store(ctx, *job)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot record cleanup outcome")
+		return
+	}
+	<-srv.events.Emit("job", job)
+}
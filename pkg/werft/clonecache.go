@@ -0,0 +1,57 @@
+package werft
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// cloneCacheVolume is the name of the clone cache volume in job pods
+	cloneCacheVolume = "werft-clone-cache"
+
+	// cloneCacheMountPath is where the checkout init container finds the clone cache
+	cloneCacheMountPath = "/werft-clone-cache"
+)
+
+// CloneCacheConfig configures the clone cache. The cache holds a mirror of every repository werft checks out,
+// which the checkout uses as reference so that only objects which aren't in the cache yet are downloaded.
+type CloneCacheConfig struct {
+	// Enabled makes the checkout of GitHub jobs use the clone cache
+	Enabled bool `yaml:"enabled"`
+
+	// HostPath is the directory on each node in which we keep the cache. Defaults to .clone-cache in the workspace node path prefix.
+	HostPath string `yaml:"hostPath,omitempty"`
+
+	// PersistentVolumeClaim is the name of a claim to keep the cache in instead of the node, e.g. to share it between nodes.
+	// The volume must support ReadWriteMany.
+	PersistentVolumeClaim string `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+// cloneCacheVolume produces the volume which holds the clone cache
+func (srv *Service) cloneCacheVolume() corev1.Volume {
+	cfg := srv.config().CloneCache
+	if cfg.PersistentVolumeClaim != "" {
+		return corev1.Volume{
+			Name: cloneCacheVolume,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cfg.PersistentVolumeClaim},
+			},
+		}
+	}
+
+	path := cfg.HostPath
+	if path == "" {
+		path = filepath.Join(srv.config().WorkspaceNodePathPrefix, ".clone-cache")
+	}
+	httype := corev1.HostPathDirectoryOrCreate
+	return corev1.Volume{
+		Name: cloneCacheVolume,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: path,
+				Type: &httype,
+			},
+		},
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"time"
 
+	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/google/go-github/github"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
@@ -62,6 +65,11 @@ func (lcp *LocalContentProvider) InitContainer() (*corev1.Container, error) {
 
 // Serve provides additional services required during initialization.
 func (lcp *LocalContentProvider) Serve(jobName string) error {
+	if lcp.Kubeconfig == nil {
+		// without a cluster, e.g. with the fake executor, there is no pod to copy the content to
+		return nil
+	}
+
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
@@ -161,6 +169,9 @@ type GitHubContentProvider struct {
 	Client   *github.Client
 	Auth     GitCredentialHelper
 	Sideload *GitHubContentProviderSideload
+
+	// CloneCache is the path of the clone cache in the init container. If set, the checkout uses and updates the cache.
+	CloneCache string
 }
 
 // GitHubContentProviderSideload enables side-loading of files after a Git clone
@@ -193,11 +204,27 @@ func (gcp *GitHubContentProvider) InitContainer() (*corev1.Container, error) {
 		}
 	}
 
-	cloneCmd := "git clone"
+	var credHelper string
 	if user != "" || pass != "" {
-		cloneCmd = fmt.Sprintf("git clone -c \"credential.helper=/bin/sh -c 'echo username=$GHUSER_SECRET; echo password=$GHPASS_SECRET'\"")
+		credHelper = "-c \"credential.helper=/bin/sh -c 'echo username=$GHUSER_SECRET; echo password=$GHPASS_SECRET'\""
+	}
+	cloneURL := fmt.Sprintf("https://github.com/%s/%s.git", gcp.Owner, gcp.Repo)
+
+	var cloneCmd string
+	if gcp.CloneCache != "" {
+		// We update the mirror in the cache before we clone with it as reference. Jobs on the same node may do this at the same time,
+		// hence the lock. Garbage collection is disabled in the mirror because it would remove objects the workspaces of running jobs
+		// still use. Failing to update the cache must not fail the checkout.
+		cache := fmt.Sprintf("%s/%s/%s.git", gcp.CloneCache, gcp.Owner, gcp.Repo)
+		cloneCmd = fmt.Sprintf("mkdir -p %[1]s && (flock 9; if [ -f %[1]s/HEAD ]; then git %[2]s -C %[1]s fetch --prune origin; else git %[2]s clone --mirror %[3]s %[1]s; fi; git -C %[1]s config gc.auto 0) 9>%[1]s.lock || echo cannot update clone cache; ", cache, credHelper, cloneURL)
+		cloneCmd += fmt.Sprintf("git clone --reference-if-able %s", cache)
+	} else {
+		cloneCmd = "git clone"
 	}
-	cloneCmd = fmt.Sprintf("%s https://github.com/%s/%s.git .; git checkout %s", cloneCmd, gcp.Owner, gcp.Repo, gcp.Revision)
+	if credHelper != "" {
+		cloneCmd += " " + credHelper
+	}
+	cloneCmd = fmt.Sprintf("%s %s .; git checkout %s", cloneCmd, cloneURL, gcp.Revision)
 	if gcp.Sideload != nil {
 		cloneCmd += "; touch /workspace/.cloned; echo waiting for sideload; while [ ! -f /workspace/.ready ]; do [ -f /workspace/.failed ] && exit 1; sleep 1; done"
 	}
@@ -224,7 +251,8 @@ func (gcp *GitHubContentProvider) InitContainer() (*corev1.Container, error) {
 
 // Serve provides additional services required during initialization.
 func (gcp *GitHubContentProvider) Serve(jobName string) error {
-	if gcp.Sideload == nil {
+	if gcp.Sideload == nil || gcp.Sideload.Kubeconfig == nil {
+		// without a cluster, e.g. with the fake executor, there is no pod to sideload into
 		return nil
 	}
 
@@ -280,3 +308,72 @@ func (gcp *GitHubContentProvider) sideload(name string) error {
 
 	return nil
 }
+
+// PluginContentProvider provides access to content served by a content-provider plugin
+type PluginContentProvider struct {
+	Client   v1.ContentProviderPluginClient
+	Metadata *v1.JobMetadata
+}
+
+// pluginContentProvider returns a content provider if a content-provider plugin is registered for the job's repository host
+func (srv *Service) pluginContentProvider(md *v1.JobMetadata) (*PluginContentProvider, bool) {
+	plugins := srv.pluginHost()
+	if plugins == nil || md == nil || md.Repository == nil {
+		return nil, false
+	}
+	client, ok := plugins.ContentProvider(md.Repository.Host)
+	if !ok {
+		return nil, false
+	}
+	return &PluginContentProvider{Client: client, Metadata: md}, true
+}
+
+// Download provides access to a single file
+func (pcp *PluginContentProvider) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	stream, err := pcp.Client.Download(ctx, &v1.ContentDownloadRequest{
+		Repository: pcp.Metadata.Repository,
+		Path:       path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(msg.Data)
+	}
+	return ioutil.NopCloser(&buf), nil
+}
+
+// InitContainer builds the container that will initialize the job content.
+func (pcp *PluginContentProvider) InitContainer() (*corev1.Container, error) {
+	resp, err := pcp.Client.InitContainer(context.Background(), &v1.ContentInitContainerRequest{
+		Metadata: pcp.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res corev1.Container
+	err = json.Unmarshal(resp.Container, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("content-provider plugin produced an invalid init container: %w", err)
+	}
+	return &res, nil
+}
+
+// Serve provides additional services required during initialization.
+func (pcp *PluginContentProvider) Serve(jobName string) error {
+	_, err := pcp.Client.Serve(context.Background(), &v1.ContentServeRequest{
+		Name:     jobName,
+		Metadata: pcp.Metadata,
+	})
+	return err
+}
@@ -0,0 +1,73 @@
+package werft
+
+import (
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CostConfig configures the rates we compute the cost of jobs with
+type CostConfig struct {
+	// CPUHour is the cost of one CPU for one hour
+	CPUHour float64 `yaml:"cpuHour,omitempty"`
+	// MemoryGBHour is the cost of one GB of memory for one hour
+	MemoryGBHour float64 `yaml:"memoryGBHour,omitempty"`
+	// Currency is the currency the rates are in, e.g. USD
+	Currency string `yaml:"currency,omitempty"`
+}
+
+// addCost sets the approximate cost of a finished job based on the resources its pod requested and its runtime
+func (srv *Service) addCost(pod *corev1.Pod, s *v1.JobStatus) {
+	if s.Phase != v1.JobPhase_PHASE_DONE || s.Metadata == nil || pod == nil {
+		return
+	}
+	created, err := ptypes.Timestamp(s.Metadata.Created)
+	if err != nil {
+		return
+	}
+	finished, err := ptypes.Timestamp(s.Metadata.Finished)
+	if err != nil {
+		return
+	}
+	runtime := finished.Sub(created)
+	if runtime < 0 {
+		return
+	}
+
+	cpu, memory := podRequests(&pod.Spec)
+	s.Cost = computeCost(cpu, memory, runtime, srv.config().Cost)
+}
+
+// computeCost computes the cost of running with the requested CPUs and memory (in GB) for the runtime
+func computeCost(cpu, memory float64, runtime time.Duration, rates CostConfig) *v1.JobCost {
+	hours := runtime.Hours()
+	res := &v1.JobCost{
+		CpuHours:      cpu * hours,
+		MemoryGbHours: memory * hours,
+		Currency:      rates.Currency,
+	}
+	res.Amount = res.CpuHours*rates.CPUHour + res.MemoryGbHours*rates.MemoryGBHour
+	return res
+}
+
+// podRequests returns the CPUs and memory in GB a pod requests. Like the Kubernetes scheduler we consider the larger of
+// the sum of all containers and the largest init container, as init containers run one after another.
+func podRequests(spec *corev1.PodSpec) (cpu, memory float64) {
+	const gb = 1 << 30
+
+	for _, c := range spec.Containers {
+		cpu += float64(c.Resources.Requests.Cpu().MilliValue()) / 1000
+		memory += float64(c.Resources.Requests.Memory().Value()) / gb
+	}
+	for _, c := range spec.InitContainers {
+		if ic := float64(c.Resources.Requests.Cpu().MilliValue()) / 1000; ic > cpu {
+			cpu = ic
+		}
+		if im := float64(c.Resources.Requests.Memory().Value()) / gb; im > memory {
+			memory = im
+		}
+	}
+	return
+}
@@ -0,0 +1,108 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// resultTypeDeployment is the type of results which record a deployment
+	resultTypeDeployment = "deployment"
+
+	// defaultDeploymentHistoryLimit is the number of deployments we return from the history if the request sets no limit
+	defaultDeploymentHistoryLimit = 50
+)
+
+// parseDeployment parses the payload of a deployment or preview result. The payload is either a JSON object with
+// environment, url and version fields, or just the name of the environment.
+func parseDeployment(res *v1.JobResult) (*v1.Deployment, error) {
+	payload := strings.TrimSpace(res.Payload)
+
+	var d v1.Deployment
+	if strings.HasPrefix(payload, "{") {
+		var body struct {
+			Environment string `json:"environment"`
+			URL         string `json:"url"`
+			Version     string `json:"version"`
+		}
+		err := json.Unmarshal([]byte(payload), &body)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid deployment: %w", err)
+		}
+		d.Environment, d.Url, d.Version = body.Environment, body.URL, body.Version
+	} else {
+		d.Environment = payload
+	}
+	if d.Environment == "" {
+		return nil, xerrors.Errorf("deployment has no environment")
+	}
+	return &d, nil
+}
+
+// recordDeployment stores the deployment a job reported in a result
+func (srv *Service) recordDeployment(ctx context.Context, job *v1.JobStatus, res *v1.JobResult) {
+	if srv.Deployments == nil || res.Type != resultTypeDeployment {
+		return
+	}
+
+	d, err := parseDeployment(res)
+	if err != nil {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot record deployment")
+		return
+	}
+	d.Job = job.Name
+	d.Deployed = ptypes.TimestampNow()
+	if job.Metadata != nil {
+		d.Repository = job.Metadata.Repository
+	}
+
+	err = srv.Deployments.Record(ctx, d)
+	if err != nil {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot record deployment")
+	}
+}
+
+// ListDeployments returns what's currently deployed to each environment, or the deployment history of a single environment
+func (srv *Service) ListDeployments(ctx context.Context, req *v1.ListDeploymentsRequest) (*v1.ListDeploymentsResponse, error) {
+	if srv.Deployments == nil {
+		return nil, status.Error(codes.Unimplemented, "this werft installation does not track deployments")
+	}
+
+	if req.History {
+		if req.Environment == "" {
+			return nil, status.Error(codes.InvalidArgument, "history requires an environment")
+		}
+		limit := int(req.Limit)
+		if limit <= 0 {
+			limit = defaultDeploymentHistoryLimit
+		}
+		res, err := srv.Deployments.History(ctx, req.Environment, limit)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &v1.ListDeploymentsResponse{Deployments: res}, nil
+	}
+
+	current, err := srv.Deployments.Current(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := current
+	if req.Environment != "" {
+		res = nil
+		for _, d := range current {
+			if d.Environment == req.Environment {
+				res = append(res, d)
+			}
+		}
+	}
+	return &v1.ListDeploymentsResponse{Deployments: res}, nil
+}
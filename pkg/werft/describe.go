@@ -0,0 +1,168 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// DescribeJob returns everything there is to know about a job: its status, phase timeline, Kubernetes events and links
+func (srv *Service) DescribeJob(ctx context.Context, req *v1.DescribeJobRequest) (*v1.DescribeJobResponse, error) {
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound || (err == nil && job == nil) {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	timeline, err := srv.jobTimeline(ctx, job)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	events, err := srv.jobKubernetesEvents(job.Name)
+	if err != nil {
+		// the job is still worth describing without the events
+		log.WithError(err).WithField("name", job.Name).Warn("cannot list Kubernetes events of job")
+	}
+
+	return &v1.DescribeJobResponse{
+		Status:   job,
+		Timeline: timeline,
+		Events:   events,
+		Links:    srv.jobLinks(job),
+	}, nil
+}
+
+// jobTimeline lists the phases a job went through. We derive it from the event trace if there is one, because it
+// knows every phase change and its details. Otherwise the phase timestamps of the job have to do.
+func (srv *Service) jobTimeline(ctx context.Context, job *v1.JobStatus) ([]*v1.JobTimelineEntry, error) {
+	if srv.Events != nil {
+		trace, err := srv.Events.Find(ctx, job.Name, time.Time{}, time.Time{}, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var res []*v1.JobTimelineEntry
+		for _, evt := range trace {
+			s := evt.Status
+			if s == nil {
+				continue
+			}
+			if len(res) > 0 {
+				last := res[len(res)-1]
+				if last.Phase == s.Phase && last.Details == s.Details {
+					continue
+				}
+			}
+			res = append(res, &v1.JobTimelineEntry{Time: evt.Time, Phase: s.Phase, Details: s.Details})
+		}
+		if len(res) > 0 {
+			return res, nil
+		}
+	}
+
+	ts := job.Timestamps
+	if ts == nil {
+		return nil, nil
+	}
+	var res []*v1.JobTimelineEntry
+	for _, e := range []struct {
+		Time  *tspb.Timestamp
+		Phase v1.JobPhase
+	}{
+		{ts.Queued, v1.JobPhase_PHASE_PREPARING},
+		{ts.Preparing, v1.JobPhase_PHASE_STARTING},
+		{ts.Running, v1.JobPhase_PHASE_RUNNING},
+		{ts.Finished, v1.JobPhase_PHASE_DONE},
+	} {
+		if e.Time == nil {
+			continue
+		}
+		res = append(res, &v1.JobTimelineEntry{Time: e.Time, Phase: e.Phase})
+	}
+	return res, nil
+}
+
+// jobKubernetesEvents lists the Kubernetes events of the pod of a job, oldest first
+func (srv *Service) jobKubernetesEvents(name string) ([]*v1.KubernetesEvent, error) {
+	if srv.Executor == nil || srv.Executor.Client == nil {
+		return nil, nil
+	}
+
+	list, err := srv.Executor.Client.CoreV1().Events(srv.Executor.Config.Namespace).List(metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.Items
+	sort.SliceStable(items, func(i, j int) bool { return eventTime(&items[i]).Before(eventTime(&items[j])) })
+	res := make([]*v1.KubernetesEvent, 0, len(items))
+	for i := range items {
+		e := &items[i]
+		t, _ := ptypes.TimestampProto(eventTime(e))
+		res = append(res, &v1.KubernetesEvent{
+			Time:    t,
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+			Source:  e.Source.Component,
+		})
+	}
+	return res, nil
+}
+
+// eventTime returns the last time a Kubernetes event occurred
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+// jobLinks produces the links of a job: its page in the UI, the commit it ran on and the results which are URLs
+func (srv *Service) jobLinks(job *v1.JobStatus) []*v1.JobLink {
+	var res []*v1.JobLink
+	if base := srv.config().BaseURL; base != "" {
+		res = append(res, &v1.JobLink{Name: "job", Url: fmt.Sprintf("%s/job/%s", strings.TrimSuffix(base, "/"), job.Name)})
+		if anchor := failureAnchor(job); anchor != "" && job.Phase == v1.JobPhase_PHASE_DONE && !job.Conditions.GetSuccess() {
+			res = append(res, &v1.JobLink{Name: "failure", Url: fmt.Sprintf("%s/job/%s#%s", strings.TrimSuffix(base, "/"), job.Name, url.PathEscape(anchor))})
+		}
+	}
+	if repo := job.Metadata.GetRepository(); repo != nil && repo.Host == "github.com" && repo.Revision != "" {
+		res = append(res, &v1.JobLink{Name: "commit", Url: fmt.Sprintf("https://github.com/%s/%s/commit/%s", repo.Owner, repo.Repo, repo.Revision)})
+	}
+	for _, r := range job.Results {
+		if !strings.HasPrefix(r.Payload, "http://") && !strings.HasPrefix(r.Payload, "https://") {
+			continue
+		}
+		name := r.Description
+		if name == "" {
+			name = r.Type
+		}
+		res = append(res, &v1.JobLink{Name: name, Url: r.Payload})
+	}
+	return res
+}
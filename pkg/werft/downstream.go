@@ -0,0 +1,275 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// annotationUpstreamJob is set on downstream jobs and names the job which triggered them
+	annotationUpstreamJob = "upstreamJob"
+	// annotationUpstreamRepo is set on downstream jobs and contains the owner/repo of the job which triggered them
+	annotationUpstreamRepo = "upstreamRepo"
+	// annotationUpstreamRevision is set on downstream jobs and contains the revision the job which triggered them ran on
+	annotationUpstreamRevision = "upstreamRevision"
+	// annotationTriggerChain is set on downstream jobs and lists all jobs which led to them as comma separated owner/repo:job entries
+	annotationTriggerChain = "triggerChain"
+
+	// maxTriggerChainLength is the maximum number of jobs which can trigger each other in a row
+	maxTriggerChainLength = 10
+)
+
+// triggerDownstream starts the jobs a successful job declared in its onSuccess section
+func (srv *Service) triggerDownstream(upstream *v1.JobStatus, downstreamJSON string) {
+	logger := log.WithField("name", upstream.Name)
+
+	var downstream []repoconfig.DownstreamJob
+	err := json.Unmarshal([]byte(downstreamJSON), &downstream)
+	if err != nil {
+		logger.WithError(err).Warn("cannot trigger downstream jobs")
+		return
+	}
+
+	ctx := context.Background()
+	chain := triggerChain(upstream.Metadata)
+	for _, d := range downstream {
+		md, err := srv.downstreamMetadata(upstream, chain, d)
+		if err != nil {
+			logger.WithError(err).WithField("downstream", d.Repo).Warn("cannot trigger downstream job")
+			continue
+		}
+		err = srv.authorizeDownstream(ctx, upstream.Metadata, md)
+		if err != nil {
+			logger.WithError(err).WithField("downstream", d.Repo).Warn("cannot trigger downstream job")
+			continue
+		}
+
+		resp, err := srv.startTriggeredJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: md,
+			JobPath:  d.Job,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("downstream", d.Repo).Warn("cannot trigger downstream job")
+			continue
+		}
+		if resp.Status == nil {
+			// the job was queued during maintenance
+			continue
+		}
+		logger.WithField("downstream", resp.Status.Name).Info("triggered downstream job")
+	}
+}
+
+// triggerChain returns the chain of jobs which led to a job including the job itself
+func triggerChain(md *v1.JobMetadata) []string {
+	if md.Repository == nil {
+		return nil
+	}
+	self := triggerChainEntry(md.Repository.Owner+"/"+md.Repository.Repo, md.JobSpec)
+
+	var chain []string
+	for _, a := range md.Annotations {
+		if a.Key == annotationTriggerChain && a.Value != "" {
+			chain = strings.Split(a.Value, ",")
+			break
+		}
+	}
+	if len(chain) == 0 {
+		// this job is where the chain starts
+		return []string{self}
+	}
+	// the upstream job added us to the chain before it knew which job spec we'd run, e.g. because it left that to our default job
+	chain[len(chain)-1] = self
+	return chain
+}
+
+// triggerChainEntry identifies a job in a trigger chain by its repository and job spec, e.g. 32leaves/werft:build
+func triggerChainEntry(repo, jobSpec string) string {
+	return fmt.Sprintf("%s:%s", repo, jobSpec)
+}
+
+// jobSpecName returns the name of the job spec at a path, e.g. build for .werft/build.yaml
+func jobSpecName(path string) string {
+	if path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// inTriggerChain returns true if the chain contains the job. A job without job spec, i.e. the default job of its
+// repository, could be any job of its repository.
+func inTriggerChain(chain []string, repo, jobSpec string) bool {
+	for _, c := range chain {
+		if c == triggerChainEntry(repo, jobSpec) || (jobSpec == "" && strings.HasPrefix(c, repo+":")) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeDownstream makes sure an upstream job may start a downstream job as if whoever started the upstream job
+// started the downstream one directly: if they used a token, it must be allowed to change the downstream repository
+// and to start jobs on behalf of their owner. Jobs no token started, e.g. those of webhooks, were authorized otherwise.
+// Either way the downstream repository has to accept the upstream one, which startGitHubJob checks once it read the
+// werft config of the downstream repository.
+func (srv *Service) authorizeDownstream(ctx context.Context, upstream, md *v1.JobMetadata) error {
+	starter := upstream.TriggeredBy
+	if starter == "" {
+		starter = upstream.Owner
+	}
+	tkns, err := srv.tokensNamed(ctx, starter)
+	if err != nil {
+		return err
+	}
+
+	// we cannot tell which token of a service account started the job, hence all of them must allow the downstream job
+	for _, tkn := range tkns {
+		err = srv.mayWrite(tkn, md.Repository)
+		if err != nil {
+			return err
+		}
+		if md.Owner != tkn.Name && !mayImpersonate(tkn) {
+			return status.Errorf(codes.PermissionDenied, "token %s may only start jobs as %s - starting jobs on behalf of %s requires the %s scope", tkn.Name, tkn.Name, md.Owner, ScopeImpersonate)
+		}
+	}
+	return nil
+}
+
+// tokensNamed returns the tokens which identify as name: the token of that name in the config, or the unexpired tokens of
+// the service account of that name
+func (srv *Service) tokensNamed(ctx context.Context, name string) ([]*TokenConfig, error) {
+	tokens := srv.config().Tokens
+	for i, t := range tokens {
+		if t.Name == name {
+			return []*TokenConfig{&tokens[i]}, nil
+		}
+	}
+	if srv.ServiceAccountTokens == nil || name == "" {
+		return nil, nil
+	}
+
+	sats, err := srv.ServiceAccountTokens.List(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var res []*TokenConfig
+	now := time.Now()
+	for _, t := range sats {
+		if t.Expired(now) {
+			continue
+		}
+		scopes := make([]Scope, len(t.Scopes))
+		for i, s := range t.Scopes {
+			scopes[i] = Scope(s)
+		}
+		res = append(res, &TokenConfig{Name: t.ServiceAccount, Scopes: scopes})
+	}
+	if len(sats) > 0 && len(res) == 0 {
+		return nil, status.Errorf(codes.PermissionDenied, "all tokens of service account %s expired", name)
+	}
+	return res, nil
+}
+
+// checkUpstream makes sure the repository of a job which another job triggered accepts jobs triggered by the repository of that upstream job
+func checkUpstream(md *v1.JobMetadata, cfg *repoconfig.C) error {
+	if md.Trigger != v1.JobTrigger_TRIGGER_UPSTREAM {
+		return nil
+	}
+
+	var upstream string
+	for _, a := range md.Annotations {
+		if a.Key == annotationUpstreamRepo {
+			upstream = a.Value
+			break
+		}
+	}
+	segs := strings.Split(upstream, "/")
+	if len(segs) != 2 {
+		return status.Error(codes.InvalidArgument, "jobs triggered by an upstream job must name its repository")
+	}
+	if segs[0] == md.Repository.Owner && segs[1] == md.Repository.Repo {
+		return nil
+	}
+	if cfg == nil || !cfg.AcceptsUpstream(segs[0], segs[1]) {
+		return status.Errorf(codes.PermissionDenied, "%s/%s does not list %s as upstream in its werft config", md.Repository.Owner, md.Repository.Repo, upstream)
+	}
+	return nil
+}
+
+// downstreamMetadata produces the metadata of a downstream job. Downstream jobs inherit the owner of their upstream job
+// and learn about it through annotations.
+func (srv *Service) downstreamMetadata(upstream *v1.JobStatus, chain []string, d repoconfig.DownstreamJob) (*v1.JobMetadata, error) {
+	segs := strings.Split(d.Repo, "/")
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		return nil, xerrors.Errorf("repo \"%s\" is not in the form of owner/repo", d.Repo)
+	}
+	owner, repo := segs[0], segs[1]
+
+	entry := triggerChainEntry(d.Repo, jobSpecName(d.Job))
+	if inTriggerChain(chain, d.Repo, jobSpecName(d.Job)) {
+		return nil, xerrors.Errorf("trigger loop: %s was triggered before (%s)", entry, strings.Join(chain, " -> "))
+	}
+	if len(chain) >= maxTriggerChainLength {
+		return nil, xerrors.Errorf("trigger chain is longer than %d jobs (%s)", maxTriggerChainLength, strings.Join(chain, " -> "))
+	}
+
+	ref := d.Ref
+	if ref == "" {
+		if srv.GitHub.Client == nil {
+			return nil, xerrors.Errorf("GitHub is not configured")
+		}
+		r, _, err := srv.GitHub.Client.Repositories.Get(context.Background(), owner, repo)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot determine default branch of %s: %w", d.Repo, err)
+		}
+		ref = "refs/heads/" + r.GetDefaultBranch()
+	}
+
+	annotations := []*v1.Annotation{
+		{Key: annotationStatusUpdate, Value: "true"},
+		{Key: annotationUpstreamJob, Value: upstream.Name},
+		{Key: annotationTriggerChain, Value: strings.Join(append(chain, entry), ",")},
+	}
+	if r := upstream.Metadata.Repository; r != nil {
+		annotations = append(annotations,
+			&v1.Annotation{Key: annotationUpstreamRepo, Value: r.Owner + "/" + r.Repo},
+			&v1.Annotation{Key: annotationUpstreamRevision, Value: r.Revision},
+		)
+	}
+	keys := make([]string, 0, len(d.Annotations))
+	for k := range d.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if isWerftAnnotation(k) {
+			return nil, xerrors.Errorf("annotation %s is reserved for werft", k)
+		}
+		annotations = append(annotations, &v1.Annotation{Key: k, Value: d.Annotations[k]})
+	}
+
+	return &v1.JobMetadata{
+		Owner:       upstream.Metadata.Owner,
+		TriggeredBy: upstream.Metadata.TriggeredBy,
+		Repository: &v1.Repository{
+			Host:  "github.com",
+			Owner: owner,
+			Repo:  repo,
+			Ref:   ref,
+		},
+		Trigger:     v1.JobTrigger_TRIGGER_UPSTREAM,
+		Annotations: annotations,
+	}, nil
+}
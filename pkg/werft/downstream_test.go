@@ -0,0 +1,238 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTriggerChain(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Metadata    *v1.JobMetadata
+		Expectation []string
+	}{
+		{"start of chain", &v1.JobMetadata{Repository: testRepo(), JobSpec: "build"}, []string{"32leaves/werft:build"}},
+		{
+			"downstream job",
+			&v1.JobMetadata{
+				Repository:  testRepo(),
+				JobSpec:     "deploy",
+				Annotations: []*v1.Annotation{{Key: "triggerChain", Value: "32leaves/leeway:build,32leaves/werft:deploy"}},
+			},
+			[]string{"32leaves/leeway:build", "32leaves/werft:deploy"},
+		},
+		{
+			"downstream default job",
+			&v1.JobMetadata{
+				Repository:  testRepo(),
+				JobSpec:     "build",
+				Annotations: []*v1.Annotation{{Key: "triggerChain", Value: "32leaves/leeway:build,32leaves/werft:"}},
+			},
+			[]string{"32leaves/leeway:build", "32leaves/werft:build"},
+		},
+		{"without repository", &v1.JobMetadata{JobSpec: "build"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := werft.TriggerChain(test.Metadata)
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestDownstreamMetadata(t *testing.T) {
+	upstream := &v1.JobStatus{
+		Name: "werft-build-master.1",
+		Metadata: &v1.JobMetadata{
+			Owner:      "alice",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Revision: "abc"},
+			JobSpec:    "build",
+		},
+	}
+	var longChain []string
+	for i := 0; i < 10; i++ {
+		longChain = append(longChain, fmt.Sprintf("32leaves/repo%d:build", i))
+	}
+
+	tests := []struct {
+		Name       string
+		Chain      []string
+		Downstream repoconfig.DownstreamJob
+		// Chain is the trigger chain of the downstream job, or empty if it must not start
+		ExpChain string
+		Error    string
+	}{
+		{
+			Name:       "other repository",
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/leeway", Ref: "refs/heads/master", Job: ".werft/deploy.yaml"},
+			ExpChain:   "32leaves/werft:build,32leaves/leeway:deploy",
+		},
+		{
+			Name:       "other job of the same repository",
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/werft", Ref: "refs/heads/master", Job: ".werft/release.yaml"},
+			ExpChain:   "32leaves/werft:build,32leaves/werft:release",
+		},
+		{
+			Name:       "same job",
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/werft", Ref: "refs/heads/master", Job: ".werft/build.yaml"},
+			Error:      "trigger loop",
+		},
+		{
+			Name:       "default job of the same repository",
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/werft", Ref: "refs/heads/master"},
+			Error:      "trigger loop",
+		},
+		{
+			Name:       "loop via another repository",
+			Chain:      []string{"32leaves/leeway:deploy", "32leaves/werft:build"},
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/leeway", Ref: "refs/heads/master", Job: ".werft/deploy.yaml"},
+			Error:      "trigger loop",
+		},
+		{
+			Name:       "chain too long",
+			Chain:      longChain,
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/leeway", Ref: "refs/heads/master", Job: ".werft/deploy.yaml"},
+			Error:      "longer than",
+		},
+		{
+			Name:       "reserved annotation",
+			Downstream: repoconfig.DownstreamJob{Repo: "32leaves/leeway", Ref: "refs/heads/master", Annotations: map[string]string{"cleanupJob": "true"}},
+			Error:      "reserved",
+		},
+		{
+			Name:       "invalid repository",
+			Downstream: repoconfig.DownstreamJob{Repo: "leeway", Ref: "refs/heads/master"},
+			Error:      "owner/repo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			chain := test.Chain
+			if chain == nil {
+				chain = werft.TriggerChain(upstream.Metadata)
+			}
+			md, err := testService(werft.AnonymousFull).DownstreamMetadata(upstream, chain, test.Downstream)
+			if test.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Error) {
+					t.Fatalf("expected error containing %q, actual %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			annotations := make(map[string]string)
+			for _, a := range md.Annotations {
+				annotations[a.Key] = a.Value
+			}
+			if act := annotations["triggerChain"]; act != test.ExpChain {
+				t.Errorf("expected trigger chain %q, actual %q", test.ExpChain, act)
+			}
+			if md.Owner != "alice" || annotations["upstreamJob"] != upstream.Name || annotations["upstreamRepo"] != "32leaves/werft" {
+				t.Errorf("downstream job does not refer to its upstream job: %v", md)
+			}
+		})
+	}
+}
+
+func TestAuthorizeDownstream(t *testing.T) {
+	sats := store.NewInMemoryServiceAccountTokens()
+	for i, tkn := range []store.ServiceAccountToken{
+		{ServiceAccount: "deployer", Scopes: []string{"trigger:32leaves/*"}},
+		{ServiceAccount: "narrow", Scopes: []string{"trigger:32leaves/*"}},
+		{ServiceAccount: "narrow", Scopes: []string{"trigger:32leaves/werft"}},
+		{ServiceAccount: "retired", Scopes: []string{"trigger"}, Expires: time.Now().Add(-time.Hour)},
+	} {
+		tkn.ID = fmt.Sprintf("token-%d", i)
+		tkn.Hash = tkn.ID
+		tkn.Created = time.Now().Add(-24 * time.Hour)
+		err := sats.Create(context.Background(), tkn)
+		if err != nil {
+			t.Fatalf("cannot create service account token: %v", err)
+		}
+	}
+	srv := testService(werft.AnonymousFull)
+	srv.ServiceAccountTokens = sats
+
+	leeway := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "leeway"}
+	other := &v1.Repository{Host: "github.com", Owner: "someone-else", Repo: "deploy"}
+	tests := []struct {
+		Name        string
+		Owner       string
+		TriggeredBy string
+		Repo        *v1.Repository
+		Code        codes.Code
+	}{
+		{Name: "not started by a token", Owner: "alice", Repo: other},
+		{Name: "token", Owner: "ci", Repo: other},
+		{Name: "token for the upstream repository only", Owner: "werft-ci", Repo: leeway, Code: codes.PermissionDenied},
+		{Name: "token for the owner", Owner: "32leaves-ci", Repo: leeway},
+		{Name: "token for the owner to other owner", Owner: "32leaves-ci", Repo: other, Code: codes.PermissionDenied},
+		{Name: "impersonating token", Owner: "alice", TriggeredBy: "bot", Repo: other},
+		{Name: "token which cannot impersonate", Owner: "alice", TriggeredBy: "ci", Repo: other, Code: codes.PermissionDenied},
+		{Name: "service account", Owner: "deployer", Repo: leeway},
+		{Name: "service account with a narrower token", Owner: "narrow", Repo: leeway, Code: codes.PermissionDenied},
+		{Name: "service account with expired tokens", Owner: "retired", Repo: leeway, Code: codes.PermissionDenied},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			upstream := &v1.JobMetadata{Owner: test.Owner, TriggeredBy: test.TriggeredBy, Repository: testRepo()}
+			md := &v1.JobMetadata{Owner: test.Owner, TriggeredBy: test.TriggeredBy, Repository: test.Repo, Trigger: v1.JobTrigger_TRIGGER_UPSTREAM}
+			err := srv.AuthorizeDownstream(context.Background(), upstream, md)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+		})
+	}
+}
+
+func TestCheckUpstream(t *testing.T) {
+	downstream := func(trigger v1.JobTrigger, upstream string) *v1.JobMetadata {
+		md := &v1.JobMetadata{Repository: &v1.Repository{Owner: "32leaves", Repo: "leeway"}, Trigger: trigger}
+		if upstream != "" {
+			md.Annotations = []*v1.Annotation{{Key: "upstreamRepo", Value: upstream}}
+		}
+		return md
+	}
+
+	tests := []struct {
+		Name     string
+		Metadata *v1.JobMetadata
+		Config   *repoconfig.C
+		Code     codes.Code
+	}{
+		{"not triggered by upstream", downstream(v1.JobTrigger_TRIGGER_MANUAL, ""), nil, codes.OK},
+		{"same repository", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, "32leaves/leeway"), nil, codes.OK},
+		{"without config", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, "32leaves/werft"), nil, codes.PermissionDenied},
+		{"not listed", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, "32leaves/werft"), &repoconfig.C{Upstreams: []string{"32leaves/other"}}, codes.PermissionDenied},
+		{"listed", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, "32leaves/werft"), &repoconfig.C{Upstreams: []string{"32leaves/werft"}}, codes.OK},
+		{"listed by owner", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, "32leaves/werft"), &repoconfig.C{Upstreams: []string{"32leaves/*"}}, codes.OK},
+		{"without upstream repository", downstream(v1.JobTrigger_TRIGGER_UPSTREAM, ""), &repoconfig.C{Upstreams: []string{"32leaves/*"}}, codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := werft.CheckUpstream(test.Metadata, test.Config)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+		})
+	}
+}
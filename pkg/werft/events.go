@@ -0,0 +1,71 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordEvent adds a job status update to the event trace
+func (srv *Service) recordEvent(pod *corev1.Pod, s *v1.JobStatus) {
+	if srv.Events == nil {
+		return
+	}
+
+	rawPod, err := json.Marshal(pod)
+	if err != nil {
+		log.WithError(err).WithField("name", s.Name).Warn("cannot record event")
+		return
+	}
+	err = srv.Events.Add(context.Background(), &v1.TraceEvent{
+		Time:   ptypes.TimestampNow(),
+		Name:   s.Name,
+		Status: s,
+		Pod:    rawPod,
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", s.Name).Warn("cannot record event")
+	}
+}
+
+// ListEvents returns the recorded event trace, oldest event first
+func (srv *Service) ListEvents(ctx context.Context, req *v1.ListEventsRequest) (*v1.ListEventsResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if srv.Events == nil {
+		return nil, status.Error(codes.FailedPrecondition, "event trace storage is not configured")
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	var since, until time.Time
+	if req.Since != nil {
+		t, err := ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		since = t
+	}
+	if req.Until != nil {
+		t, err := ptypes.Timestamp(req.Until)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		until = t
+	}
+
+	events, err := srv.Events.Find(ctx, req.Name, since, until, int(req.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ListEventsResponse{Events: events}, nil
+}
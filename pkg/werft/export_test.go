@@ -0,0 +1,133 @@
+package werft
+
+import (
+	"context"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+// AuthorizeStart exposes authorizeStart to tests
+func (srv *Service) AuthorizeStart(ctx context.Context, md *v1.JobMetadata) error {
+	return srv.authorizeStart(ctx, md)
+}
+
+// AuthorizeReplay exposes authorizeReplay to tests
+func (srv *Service) AuthorizeReplay(ctx context.Context, md *v1.JobMetadata) error {
+	return srv.authorizeReplay(ctx, md)
+}
+
+// Authorize exposes authorize to tests
+func (srv *Service) Authorize(ctx context.Context, scope Scope) error {
+	return srv.authorize(ctx, scope)
+}
+
+// AuthorizeWrite exposes authorizeWrite to tests
+func (srv *Service) AuthorizeWrite(ctx context.Context, repo *v1.Repository) error {
+	return srv.authorizeWrite(ctx, repo)
+}
+
+// RateLimitCaller exposes rateLimitCaller to tests
+func (srv *Service) RateLimitCaller(ctx context.Context) string {
+	return srv.rateLimitCaller(ctx)
+}
+
+// JobRateLimiter exposes jobRateLimiter to tests
+type JobRateLimiter = jobRateLimiter
+
+// AllowAt exposes allowAt to tests
+func (l *jobRateLimiter) AllowAt(now time.Time, caller string, repo *v1.Repository) error {
+	return l.allowAt(now, caller, repo)
+}
+
+// Len returns the number of limiters kept
+func (l *jobRateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+// DeliveryDeduplicator exposes deliveryDeduplicator to tests
+type DeliveryDeduplicator = deliveryDeduplicator
+
+// SeenAt exposes seenAt to tests
+func (d *deliveryDeduplicator) SeenAt(now time.Time, id string) bool {
+	return d.seenAt(now, id)
+}
+
+// IdempotencyKeys exposes idempotencyKeys to tests
+type IdempotencyKeys = idempotencyKeys
+
+// BeginAt exposes beginAt to tests
+func (k *idempotencyKeys) BeginAt(now time.Time, key string) (string, error) {
+	return k.beginAt(now, key)
+}
+
+// DoneAt exposes doneAt to tests
+func (k *idempotencyKeys) DoneAt(now time.Time, key, job string) {
+	k.doneAt(now, key, job)
+}
+
+// SetIdempotencyKeyTTL sets how long the service remembers idempotency keys, which Start does otherwise
+func (srv *Service) SetIdempotencyKeyTTL(ttl time.Duration) {
+	srv.idempotency.TTL = ttl
+}
+
+// StartIdempotent exposes startIdempotent to tests
+func (srv *Service) StartIdempotent(ctx context.Context, key string, start func() (*v1.StartJobResponse, error)) (*v1.StartJobResponse, error) {
+	return srv.startIdempotent(ctx, key, start)
+}
+
+// ProgressThrottle exposes progressThrottle to tests
+type ProgressThrottle = progressThrottle
+
+// EmitJob tells all subscribers about a job update
+func (srv *Service) EmitJob(job *v1.JobStatus) {
+	<-srv.events.Emit("job", job)
+}
+
+// TriggerChain exposes triggerChain to tests
+func TriggerChain(md *v1.JobMetadata) []string {
+	return triggerChain(md)
+}
+
+// DownstreamMetadata exposes downstreamMetadata to tests
+func (srv *Service) DownstreamMetadata(upstream *v1.JobStatus, chain []string, d repoconfig.DownstreamJob) (*v1.JobMetadata, error) {
+	return srv.downstreamMetadata(upstream, chain, d)
+}
+
+// AuthorizeDownstream exposes authorizeDownstream to tests
+func (srv *Service) AuthorizeDownstream(ctx context.Context, upstream, md *v1.JobMetadata) error {
+	return srv.authorizeDownstream(ctx, upstream, md)
+}
+
+// CheckUpstream exposes checkUpstream to tests
+func CheckUpstream(md *v1.JobMetadata, cfg *repoconfig.C) error {
+	return checkUpstream(md, cfg)
+}
+
+// DirectiveAnnotations exposes directiveAnnotations to tests
+func DirectiveAnnotations(run *repoconfig.RunDirective) ([]*v1.Annotation, error) {
+	return directiveAnnotations(run)
+}
+
+// NextRetry exposes nextRetry to tests
+func NextRetry(failed *v1.JobStatus, policy *repoconfig.RetryPolicy) (rule *repoconfig.RetryRule, key string, attempt int) {
+	return nextRetry(failed, policy)
+}
+
+// RetryOrigin exposes retryOrigin to tests
+func RetryOrigin(failed *v1.JobStatus) string {
+	return retryOrigin(failed)
+}
+
+// CheckFailureAlerts exposes checkFailureAlerts to tests
+func (srv *Service) CheckFailureAlerts(job *v1.JobStatus) {
+	srv.checkFailureAlerts(job)
+}
+
+// CheckCleanupAlerts exposes checkCleanupAlerts to tests
+func (srv *Service) CheckCleanupAlerts(job *v1.JobStatus, node string, failures int, details string) {
+	srv.checkCleanupAlerts(job, node, failures, details)
+}
@@ -1,15 +1,22 @@
 package werft
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/go-github/github"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,55 +29,71 @@ var (
 	annotationStatusUpdate = "updateGitHubStatus"
 )
 
+// webhookDeliveryTTL is the time we remember webhook deliveries for to ignore redeliveries
+const webhookDeliveryTTL = 1 * time.Hour
+
+// idempotencyKeyTTL is the time we remember the jobs started with an idempotency key
+const idempotencyKeyTTL = 1 * time.Hour
+
 func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
-	var wantsUpdate bool
-	for _, a := range job.Metadata.Annotations {
-		if a.Key == annotationStatusUpdate {
-			wantsUpdate = true
-			break
-		}
-	}
-	if !wantsUpdate {
+	if !wantsGitHubStatus(job) {
 		return nil
 	}
 
 	var (
-		state string
-		desc  string
+		ctx       = context.Background()
+		repo      = job.Metadata.Repository
+		statusCfg = srv.jobRepoConfig(ctx, job).Status
+		url       = srv.jobURL(job)
 	)
-	switch job.Phase {
-	case v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING:
-		state = "pending"
-		desc = "build is " + strings.TrimPrefix(strings.ToLower(job.Phase.String()), "phase_")
-	default:
-		if job.Conditions.Success {
-			state = "success"
-			desc = "The build succeeded!"
-		} else {
-			state = "failure"
-			desc = "The build failed!"
+	state, desc := githubJobState(job)
+	if statusCfg != nil && statusCfg.PerJob && job.Metadata.JobSpec != "" {
+		ghcontext := werftGithubContext + "/" + job.Metadata.JobSpec
+		_, _, err := srv.GitHub.Client.Repositories.CreateStatus(ctx, repo.Owner, repo.Repo, repo.Revision, &github.RepoStatus{
+			State:       &state,
+			Description: &desc,
+			Context:     &ghcontext,
+			TargetURL:   &url,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	werftURL := url
+	if statusCfg != nil && statusCfg.Aggregate {
+		agg, err := srv.revisionJobs(ctx, job)
+		if err != nil {
+			return err
+		}
+		var culprit *v1.JobStatus
+		state, desc, culprit = aggregateGitHubState(agg)
+		if culprit != nil {
+			werftURL = srv.jobURL(culprit)
 		}
 	}
-	url := fmt.Sprintf("%s/job/%s", srv.Config.BaseURL, job.Name)
+
 	ghstatus := &github.RepoStatus{
 		State:       &state,
 		Description: &desc,
 		Context:     &werftGithubContext,
-		TargetURL:   &url,
+		TargetURL:   &werftURL,
 	}
 	log.WithField("status", ghstatus).Debugf("updating GitHub status for %s", job.Name)
-	ctx := context.Background()
-	_, _, err := srv.GitHub.Client.Repositories.CreateStatus(ctx, job.Metadata.Repository.Owner, job.Metadata.Repository.Repo, job.Metadata.Repository.Revision, ghstatus)
+	_, _, err := srv.GitHub.Client.Repositories.CreateStatus(ctx, repo.Owner, repo.Repo, repo.Revision, ghstatus)
 	if err != nil {
 		return err
 	}
 
-	// update all result statuses
+	// update all result statuses, unless the repo routes the github channel itself
+	if _, routed := srv.jobRepoConfig(ctx, job).Channels[resultChannelGitHub]; routed {
+		return nil
+	}
 	var idx int
 	for _, r := range job.Results {
 		var ok bool
 		for _, c := range r.Channels {
-			if c == "github" {
+			if c == resultChannelGitHub {
 				ok = true
 				break
 			}
@@ -104,9 +127,100 @@ func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 	return nil
 }
 
-// HandleGithubWebhook handles incoming Github events
+// wantsGitHubStatus returns true if a job reports its status on GitHub
+func wantsGitHubStatus(job *v1.JobStatus) bool {
+	for _, a := range job.Metadata.Annotations {
+		if a.Key == annotationStatusUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// githubJobState returns the GitHub status state and description of a job
+func githubJobState(job *v1.JobStatus) (state, desc string) {
+	switch job.Phase {
+	case v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING:
+		return "pending", "build is " + strings.TrimPrefix(strings.ToLower(job.Phase.String()), "phase_")
+	default:
+		if job.Conditions.Success {
+			return "success", "The build succeeded!"
+		}
+		return "failure", "The build failed!"
+	}
+}
+
+// revisionJobs returns the latest job of each job spec which reports its status on the revision of a job. Jobs which
+// brought their own job spec count on their own.
+func (srv *Service) revisionJobs(ctx context.Context, job *v1.JobStatus) ([]*v1.JobStatus, error) {
+	repo := job.Metadata.Repository
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "repo.owner", Value: repo.Owner}}},
+		{Terms: []*v1.FilterTerm{{Field: "repo.repo", Value: repo.Repo}}},
+		{Terms: []*v1.FilterTerm{{Field: "repo.rev", Value: repo.Revision}}},
+	}, []*v1.OrderExpression{{Field: "created", Ascending: false}}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res  []*v1.JobStatus
+		seen = make(map[string]bool)
+	)
+	// the job we are reporting might not be stored yet
+	candidates := []*v1.JobStatus{job}
+	for i := range jobs {
+		candidates = append(candidates, &jobs[i])
+	}
+	for _, j := range candidates {
+		key := j.Metadata.JobSpec
+		if key == "" {
+			key = "name:" + j.Name
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if !wantsGitHubStatus(j) {
+			continue
+		}
+		res = append(res, j)
+	}
+	return res, nil
+}
+
+// aggregateGitHubState combines the states of several jobs into one. The culprit is the job which determines the
+// state, e.g. the one that failed, or nil if all jobs succeeded.
+func aggregateGitHubState(jobs []*v1.JobStatus) (state, desc string, culprit *v1.JobStatus) {
+	var failed, pending []*v1.JobStatus
+	for _, j := range jobs {
+		switch s, _ := githubJobState(j); s {
+		case "failure":
+			failed = append(failed, j)
+		case "pending":
+			pending = append(pending, j)
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return "failure", fmt.Sprintf("%d of %d builds failed", len(failed), len(jobs)), failed[0]
+	case len(pending) > 0:
+		return "pending", fmt.Sprintf("%d of %d builds are still running", len(pending), len(jobs)), pending[0]
+	default:
+		return "success", fmt.Sprintf("All %d builds succeeded!", len(jobs)), nil
+	}
+}
+
+// HandleGithubWebhook handles incoming Github events. Every delivery is recorded together with what became of it,
+// though we keep the headers and payload of those with a valid signature only.
 func (srv *Service) HandleGithubWebhook(w http.ResponseWriter, r *http.Request) {
-	var err error
+	var (
+		err      error
+		delivery *store.WebhookDelivery
+		outcome  webhookOutcome
+	)
 	defer func(err *error) {
 		if *err == nil {
 			return
@@ -114,37 +228,82 @@ func (srv *Service) HandleGithubWebhook(w http.ResponseWriter, r *http.Request)
 
 		log.WithError(*err).Warn("GitHub webhook error")
 		http.Error(w, (*err).Error(), http.StatusInternalServerError)
+
+		// GitHub may redeliver failed webhooks which we must not ignore
+		srv.deliveries.Forget(github.DeliveryID(r))
 	}(&err)
+	defer func() {
+		if delivery != nil {
+			srv.recordWebhookDelivery(delivery, outcome, err)
+		}
+	}()
 
 	if r.Method == "GET" {
 		http.Redirect(w, r, "/github?"+r.URL.Query().Encode(), 301)
 		return
 	}
 
+	// we might have to validate the payload twice, hence must be able to read the body again
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		return
+	}
+	delivery = newWebhookDelivery(r, body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
 	payload, err := github.ValidatePayload(r, srv.GitHub.WebhookSecret)
+	if err != nil && !strings.Contains(err.Error(), "unknown X-Github-Event") {
+		// repositories registered with their own webhook secret sign their webhooks with that secret
+		if secret, ok := srv.repositoryWebhookSecret(r.Context(), body); ok {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			payload, err = github.ValidatePayload(r, secret)
+		}
+	}
 	if err != nil && strings.Contains(err.Error(), "unknown X-Github-Event") {
 		err = nil
+		outcome = skipWebhook("unknown event type")
 		return
 	}
 	if err != nil {
 		return
 	}
+	delivery.Verified = true
+	setWebhookHeaders(delivery, r)
+	// form encoded webhooks carry the JSON payload in a form field
+	setWebhookPayload(delivery, payload)
+
+	if id := github.DeliveryID(r); srv.deliveries.Seen(id) {
+		log.WithField("delivery", id).Info("ignoring GitHub webhook redelivery")
+		outcome = skipWebhook("GitHub redelivered a delivery werft has processed already")
+		return
+	}
 	event, err := github.ParseWebHook(github.WebHookType(r), payload)
 	if err != nil {
 		return
 	}
+	outcome, handled := srv.processWebhook(event, payload, github.DeliveryID(r))
+	if !handled {
+		log.WithField("event", event).Debug("unhandled GitHub event")
+		http.Error(w, "unhandled event", http.StatusInternalServerError)
+	}
+}
+
+// processWebhook acts on a GitHub event. The delivery ID tells the jobs it starts apart from those of other deliveries.
+// handled is false if werft does not act on events of this type.
+func (srv *Service) processWebhook(event interface{}, payload []byte, deliveryID string) (outcome webhookOutcome, handled bool) {
 	switch event := event.(type) {
 	case *github.PushEvent:
-		srv.processPushEvent(event)
+		return srv.processPushEvent(event, deliveryID), true
 	case *github.InstallationEvent:
 		srv.processInstallationEvent(event)
+		return skipWebhook("installation events start no jobs"), true
+	case *github.PullRequestEvent:
+		return srv.processPullRequestEvent(event, payload), true
 	default:
-		log.WithField("event", event).Debug("unhandled GitHub event")
-		http.Error(w, "unhandled event", http.StatusInternalServerError)
+		return skipWebhook("werft does not handle this event type"), false
 	}
 }
 
-func (srv *Service) processPushEvent(event *github.PushEvent) {
+func (srv *Service) processPushEvent(event *github.PushEvent, deliveryID string) (outcome webhookOutcome) {
 	ctx := context.Background()
 	rev := *event.After
 
@@ -174,6 +333,7 @@ func (srv *Service) processPushEvent(event *github.PushEvent) {
 			Revision: rev,
 		},
 		Trigger: trigger,
+		Event:   srv.pushJobEvent(ctx, event),
 		Annotations: []*v1.Annotation{
 			&v1.Annotation{
 				Key:   annotationStatusUpdate,
@@ -191,20 +351,93 @@ func (srv *Service) processPushEvent(event *github.PushEvent) {
 	repoCfg, err := getRepoCfg(ctx, cp)
 	if err != nil {
 		log.WithError(err).WithField("name", flatname).Error("cannot start job")
-		return
+		return webhookOutcome{Errors: []string{fmt.Sprintf("cannot read repo config: %v", err)}}
 	}
 
-	// check if we need to build/do anything
-	if !repoCfg.ShouldRun(&metadata) {
-		return
+	if bp := repoCfg.BranchProtection; bp != nil && trigger == v1.JobTrigger_TRIGGER_PUSH &&
+		*event.Ref == "refs/heads/"+event.Repo.GetDefaultBranch() && changesWerftConfig(event) {
+		go srv.syncBranchProtection(context.Background(), metadata.Repository.Owner, metadata.Repository.Repo, bp)
 	}
 
-	_, err = srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
-		Metadata: &metadata,
-	})
+	directives, err := repoconfig.ParseCommitDirectives(event.GetHeadCommit().GetMessage())
 	if err != nil {
-		log.WithError(err).Warn("GitHub webhook error")
+		log.WithError(err).WithField("name", flatname).Warn("ignoring invalid commit directives")
+	}
+	if directives.Skip {
+		log.WithField("name", flatname).WithField("revision", rev).Info("commit message asks to skip CI - not starting a job")
+		return skipWebhook("commit message asks to skip CI")
+	}
+
+	var idempotencyKey string
+	if deliveryID != "" {
+		idempotencyKey = "delivery/" + deliveryID
+	}
+	if len(directives.Runs) == 0 {
+		// check if we need to build/do anything
+		if !repoCfg.ShouldRun(&metadata) {
+			return skipWebhook("the repo config does not run jobs for this push")
+		}
+
+		resp, err := srv.startTriggeredJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata:       &metadata,
+			IdempotencyKey: idempotencyKey,
+		})
+		if err != nil {
+			log.WithError(err).Warn("GitHub webhook error")
+		}
+		outcome.Started(resp, err)
+		return outcome
+	}
+
+	// the commit message selects the jobs to run
+	for i, run := range directives.Runs {
+		annotations, err := directiveAnnotations(run)
+		if err != nil {
+			log.WithError(err).WithField("job", run.Job).Warn("ignoring invalid commit directive")
+			outcome.Started(nil, err)
+			continue
+		}
+		md := proto.Clone(&metadata).(*v1.JobMetadata)
+		md.Annotations = append(md.Annotations, annotations...)
+		if run.Job == "" && !repoCfg.ShouldRun(md) {
+			outcome.Add(skipWebhook("the repo config does not run jobs for the runs the commit message asks for"))
+			continue
+		}
+
+		var key string
+		if idempotencyKey != "" {
+			key = fmt.Sprintf("%s/%d", idempotencyKey, i)
+		}
+		resp, err := srv.startTriggeredJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata:       md,
+			JobPath:        run.JobPath(),
+			IdempotencyKey: key,
+		})
+		if err != nil {
+			log.WithError(err).WithField("job", run.Job).Warn("GitHub webhook error")
+		}
+		outcome.Started(resp, err)
+	}
+	return outcome
+}
+
+// directiveAnnotations returns the annotations a run directive sets, ordered by their key. Commit messages are written
+// by anyone who can push, hence directives must not set the annotations werft uses to tell its own jobs apart.
+func directiveAnnotations(run *repoconfig.RunDirective) ([]*v1.Annotation, error) {
+	keys := make([]string, 0, len(run.Annotations))
+	for k := range run.Annotations {
+		if isWerftAnnotation(k) {
+			return nil, xerrors.Errorf("commit directives must not set the werft annotation \"%s\"", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([]*v1.Annotation, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, &v1.Annotation{Key: k, Value: run.Annotations[k]})
 	}
+	return res, nil
 }
 
 func getRepoCfg(ctx context.Context, fp FileProvider) (*repoconfig.C, error) {
@@ -0,0 +1,56 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestDirectiveAnnotations(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Expectation []string
+		Error       bool
+	}{
+		{
+			Name:        "no annotations",
+			Expectation: []string{},
+		},
+		{
+			Name:        "ordered by key",
+			Annotations: map[string]string{"version": "1.2", "debug": "true"},
+			Expectation: []string{"debug=true", "version=1.2"},
+		},
+		{Name: "cleanup job", Annotations: map[string]string{"cleanupJob": "true"}, Error: true},
+		{Name: "retry", Annotations: map[string]string{"debug": "true", "retryOf": "werft-build-main.1"}, Error: true},
+		{Name: "retry budget", Annotations: map[string]string{"retries.integration": "3"}, Error: true},
+		{Name: "trigger chain", Annotations: map[string]string{"triggerChain": ""}, Error: true},
+		{Name: "promoted job", Annotations: map[string]string{"promotedJob": "werft-build-main.1"}, Error: true},
+		{Name: "GitHub status", Annotations: map[string]string{"updateGitHubStatus": "32leaves/werft"}, Error: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := werft.DirectiveAnnotations(&repoconfig.RunDirective{Annotations: test.Annotations})
+			if test.Error {
+				if err == nil {
+					t.Errorf("expected an error, got annotations %v", act)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			annotations := make([]string, 0, len(act))
+			for _, a := range act {
+				annotations = append(annotations, a.Key+"="+a.Value)
+			}
+			if !reflect.DeepEqual(annotations, test.Expectation) {
+				t.Errorf("expected %q, actual %q", test.Expectation, annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package werft
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// jobGraph assembles the stages of a job from its log slice events
+type jobGraph struct {
+	// live attributes events without timestamp to the time they were added.
+	// This only makes sense while following the log of a running job.
+	live bool
+
+	mu     sync.Mutex
+	stages []*v1.JobStage
+	open   map[string]*v1.LogSliceSummary
+}
+
+func newJobGraph(live bool) *jobGraph {
+	return &jobGraph{
+		live: live,
+		open: make(map[string]*v1.LogSliceSummary),
+	}
+}
+
+// Add updates the graph with a log slice event
+func (g *jobGraph) Add(evt *v1.LogSliceEvent) {
+	if evt == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ts := evt.Time
+	if ts == nil && g.live {
+		ts = ptypes.TimestampNow()
+	}
+	// events without a valid cursor, e.g. those made up from job steps, leave the offsets at zero
+	cursor, _ := logcutter.ParseCursor(evt.Cursor)
+	offset := cursor.Offset
+
+	switch evt.Type {
+	case v1.LogSliceType_SLICE_PHASE:
+		stage := &v1.JobStage{
+			Name:        evt.Name,
+			Description: strings.TrimSpace(evt.Payload),
+			Started:     ts,
+		}
+		if len(g.stages) > 0 {
+			prev := g.stages[len(g.stages)-1]
+			prev.Finished = ts
+			stage.DependsOn = []string{prev.Name}
+
+			// unmarked log output goes to a slice named after the phase, which ends with the phase
+			if sl, ok := g.open[prev.Name]; ok {
+				sl.Status = v1.StageStatus_STAGE_SUCCESS
+				sl.Finished = ts
+				delete(g.open, prev.Name)
+			}
+		}
+		g.stages = append(g.stages, stage)
+	case v1.LogSliceType_SLICE_START:
+		g.startSlice(evt.Name, ts, offset)
+	case v1.LogSliceType_SLICE_CONTENT:
+		sl, ok := g.open[evt.Name]
+		if !ok {
+			sl = g.startSlice(evt.Name, ts, offset)
+		}
+		sl.Lines++
+		sl.EndOffset = offset
+	case v1.LogSliceType_SLICE_DONE, v1.LogSliceType_SLICE_FAIL, v1.LogSliceType_SLICE_ABANDONED:
+		sl, ok := g.open[evt.Name]
+		if !ok && evt.Type == v1.LogSliceType_SLICE_ABANDONED {
+			// we ended the slice already, e.g. because its phase ended
+			return
+		}
+		if !ok {
+			sl = g.startSlice(evt.Name, ts, offset)
+		}
+		delete(g.open, evt.Name)
+
+		sl.Finished = ts
+		if evt.Type != v1.LogSliceType_SLICE_ABANDONED {
+			sl.EndOffset = offset
+		}
+		switch evt.Type {
+		case v1.LogSliceType_SLICE_DONE:
+			sl.Status = v1.StageStatus_STAGE_SUCCESS
+		case v1.LogSliceType_SLICE_FAIL:
+			sl.Status = v1.StageStatus_STAGE_FAILED
+			sl.Failure = strings.TrimSpace(evt.Payload)
+		default:
+			if g.isStage(evt.Name) {
+				// the log ended while in this phase, which is the regular end of a phase
+				sl.Status = v1.StageStatus_STAGE_SUCCESS
+			} else {
+				sl.Status = v1.StageStatus_STAGE_ABANDONED
+			}
+		}
+	}
+}
+
+// isStage returns true if there's a stage with that name. Must be called with mu held.
+func (g *jobGraph) isStage(name string) bool {
+	for _, s := range g.stages {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// startSlice adds a running slice starting at offset to the current stage. Must be called with mu held.
+func (g *jobGraph) startSlice(name string, ts *tspb.Timestamp, offset int64) *v1.LogSliceSummary {
+	if len(g.stages) == 0 {
+		g.stages = append(g.stages, &v1.JobStage{Name: logcutter.DefaultSlice, Started: ts})
+	}
+	stage := g.stages[len(g.stages)-1]
+
+	sl := &v1.LogSliceSummary{
+		Name:        name,
+		Status:      v1.StageStatus_STAGE_RUNNING,
+		Started:     ts,
+		Phase:       stage.Name,
+		StartOffset: offset,
+		EndOffset:   offset,
+	}
+	stage.Slices = append(stage.Slices, sl)
+	g.open[name] = sl
+	return sl
+}
+
+// Stages returns a copy of the stages with their status and duration computed for the current state of the job
+func (g *jobGraph) Stages(job *v1.JobStatus) []*v1.JobStage {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var (
+		done     = job.Phase == v1.JobPhase_PHASE_DONE || job.Phase == v1.JobPhase_PHASE_CLEANUP
+		success  = job.Conditions != nil && job.Conditions.Success
+		finished *tspb.Timestamp
+		now      = time.Now()
+	)
+	if done {
+		// durations of finished jobs must not depend on when we're asked
+		now = time.Time{}
+		if job.Metadata != nil {
+			finished = job.Metadata.Finished
+		}
+	}
+	res := make([]*v1.JobStage, len(g.stages))
+	for i, s := range g.stages {
+		stage := proto.Clone(s).(*v1.JobStage)
+		last := i == len(g.stages)-1
+
+		var failed bool
+		for _, sl := range stage.Slices {
+			if sl.Status == v1.StageStatus_STAGE_RUNNING && done {
+				sl.Status = v1.StageStatus_STAGE_ABANDONED
+			}
+			if sl.Finished == nil {
+				sl.Finished = finished
+			}
+			failed = failed || sl.Status == v1.StageStatus_STAGE_FAILED
+			sl.Duration = durationBetween(sl.Started, sl.Finished, now)
+		}
+
+		switch {
+		case last && !done:
+			stage.Status = v1.StageStatus_STAGE_RUNNING
+		case failed || (last && !success):
+			stage.Status = v1.StageStatus_STAGE_FAILED
+		default:
+			stage.Status = v1.StageStatus_STAGE_SUCCESS
+		}
+		if last && stage.Finished == nil {
+			stage.Finished = finished
+		}
+		stage.Duration = durationBetween(stage.Started, stage.Finished, now)
+
+		res[i] = stage
+	}
+	return res
+}
+
+// Slices returns the log slices of all stages in the order they started, with their status and duration computed
+// for the current state of the job
+func (g *jobGraph) Slices(job *v1.JobStatus) []*v1.LogSliceSummary {
+	var res []*v1.LogSliceSummary
+	for _, stage := range g.Stages(job) {
+		res = append(res, stage.Slices...)
+	}
+	return res
+}
+
+// durationBetween computes the duration from start to end, or to now if end is nil.
+// Returns nil if start is unknown, or end is unknown and now is zero.
+func durationBetween(start, end *tspb.Timestamp, now time.Time) *duration.Duration {
+	if start == nil {
+		return nil
+	}
+	s, err := ptypes.Timestamp(start)
+	if err != nil {
+		return nil
+	}
+	e := now
+	if end == nil && now.IsZero() {
+		return nil
+	}
+	if end != nil {
+		e, err = ptypes.Timestamp(end)
+		if err != nil {
+			return nil
+		}
+	}
+	if e.Before(s) {
+		e = s
+	}
+	return ptypes.DurationProto(e.Sub(s))
+}
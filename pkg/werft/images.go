@@ -0,0 +1,173 @@
+package werft
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// kanikoImage builds images without privileges
+	kanikoImage = "gcr.io/kaniko-project/executor:v1.3.0"
+
+	// buildkitImage builds images using a BuildKit daemon which lives as long as the build
+	buildkitImage = "moby/buildkit:v0.8.0"
+
+	// registryConfigPath is where image builds find the Docker config which holds the registry credentials
+	registryConfigPath = "/.werft/registry"
+
+	// resultTypeImage is the type of the results image builds register, which is what imageBuildScript prints
+	resultTypeImage = "docker"
+)
+
+// imageBuildScript runs the builder passed as arguments and registers the digest of the image it pushed
+// to each of the destinations in WERFT_IMAGE_DESTINATIONS as result of the job. WERFT_IMAGE_DIGEST is the
+// command which prints the digest once the build is done.
+const imageBuildScript = `"$@" || exit $?
+digest=$(eval "$WERFT_IMAGE_DIGEST")
+if [ -z "$digest" ]; then
+  echo "cannot find the digest of the image built by $WERFT_STEP"
+  exit 1
+fi
+for d in $WERFT_IMAGE_DESTINATIONS; do
+  echo "[docker|RESULT] $d@$digest built by $WERFT_STEP"
+done`
+
+// imageBuildSteps expands the image builds of a job into a parallel group of steps, together with the volumes those steps need.
+// Registry credentials must come from a secret which is bound to the repository, s.t. jobs cannot push using secrets they
+// were not granted.
+func imageBuildSteps(builds []*repoconfig.ImageBuild, settings *v1.RepositorySettings) (*repoconfig.StepSpec, []corev1.Volume, error) {
+	var (
+		group   = &repoconfig.StepSpec{Parallel: true}
+		volumes []corev1.Volume
+	)
+	for _, b := range builds {
+		if b == nil {
+			continue
+		}
+		if len(b.Destinations) == 0 {
+			return nil, nil, xerrors.Errorf("image build %s has no destinations", b.Name)
+		}
+
+		c, err := imageBuildContainer(b)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if b.RegistrySecret != "" {
+			if !isSecretBound(settings, b.RegistrySecret) {
+				return nil, nil, xerrors.Errorf("image build %s: registry secret %s is not bound to the repository", b.Name, b.RegistrySecret)
+			}
+
+			volume := "werft-registry-" + b.Name
+			volumes = append(volumes, corev1.Volume{
+				Name: volume,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: b.RegistrySecret,
+						Items: []corev1.KeyToPath{
+							{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+						},
+					},
+				},
+			})
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				Name:      volume,
+				ReadOnly:  true,
+				MountPath: registryConfigPath,
+			})
+			c.Env = append(c.Env, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: registryConfigPath})
+		}
+
+		group.Steps = append(group.Steps, &repoconfig.StepSpec{Container: c})
+	}
+	return group, volumes, nil
+}
+
+// imageBuildContainer produces the container which builds and pushes an image using the builder of the image build
+func imageBuildContainer(b *repoconfig.ImageBuild) (corev1.Container, error) {
+	var (
+		buildContext = "/workspace/" + b.ContextPath()
+		dockerfile   = "/workspace/" + b.DockerfilePath()
+		digestFile   = fmt.Sprintf("%s/%s.digest", executor.StepsMountPath, b.Name)
+
+		buildArgs []string
+	)
+	for k := range b.BuildArgs {
+		buildArgs = append(buildArgs, k)
+	}
+	sort.Strings(buildArgs)
+
+	var (
+		image      string
+		builder    []string
+		digest     string
+		privileged bool
+	)
+	switch b.GetBuilder() {
+	case repoconfig.ImageBuilderKaniko:
+		image = kanikoImage
+		builder = []string{"/kaniko/executor", "--context=dir://" + buildContext, "--dockerfile=" + dockerfile, "--digest-file=" + digestFile}
+		for _, d := range b.Destinations {
+			builder = append(builder, "--destination="+d)
+		}
+		for _, k := range buildArgs {
+			builder = append(builder, fmt.Sprintf("--build-arg=%s=%s", k, b.BuildArgs[k]))
+		}
+		digest = fmt.Sprintf("%s cat %s", executor.StepRunner, digestFile)
+	case repoconfig.ImageBuilderBuildKit:
+		dfdir, dfname := path.Split(dockerfile)
+		image = buildkitImage
+		builder = []string{"buildctl-daemonless.sh", "build",
+			"--frontend=dockerfile.v0",
+			"--local", "context=" + buildContext,
+			"--local", "dockerfile=" + dfdir,
+			"--opt", "filename=" + dfname,
+			"--output", fmt.Sprintf("type=image,\"name=%s\",push=true", strings.Join(b.Destinations, ",")),
+			"--metadata-file", digestFile,
+		}
+		for _, k := range buildArgs {
+			builder = append(builder, "--opt", fmt.Sprintf("build-arg:%s=%s", k, b.BuildArgs[k]))
+		}
+		digest = fmt.Sprintf(`%s sed -n 's/.*"containerimage.digest": *"\([^"]*\)".*/\1/p' %s`, executor.StepRunner, digestFile)
+		// the BuildKit daemon needs privileges to run the build
+		privileged = true
+	default:
+		return corev1.Container{}, xerrors.Errorf("image build %s: unknown image builder %s", b.Name, b.Builder)
+	}
+
+	c := corev1.Container{
+		Name:       b.Name,
+		Image:      image,
+		Command:    append([]string{executor.StepRunner, "sh", "-c", imageBuildScript, b.Name}, builder...),
+		WorkingDir: "/workspace",
+		Env: []corev1.EnvVar{
+			{Name: "WERFT_IMAGE_DESTINATIONS", Value: strings.Join(b.Destinations, " ")},
+			{Name: "WERFT_IMAGE_DIGEST", Value: digest},
+		},
+	}
+	if privileged {
+		c.SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	}
+	return c, nil
+}
+
+// isSecretBound returns true if the secret is bound to the repository
+func isSecretBound(settings *v1.RepositorySettings, secret string) bool {
+	if settings == nil {
+		return false
+	}
+	for _, b := range settings.Secrets {
+		if b.Secret == secret {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package werft
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// Features lists the optional features of the werft API. Clients and plugins can use GetServerInfo to detect those a
+// server supports, which depend on its setup (see supportedFeatures). Add a feature whenever you add an RPC or a field
+// clients have to know the server supports.
+var Features = []string{
+	"log-cursors",
+	"log-plain",
+	"log-timestamps",
+	"structured-logs",
+	"progress",
+	"stats",
+	"duration-estimates",
+	"listen-filter",
+	"sse",
+	"annotate",
+	"job-graph",
+	"preferences",
+	"deployments",
+	"provenance",
+	"tarball-jobs",
+	"repo-stats",
+	"idempotency-keys",
+	"exact-replay",
+	"status-snapshots",
+	"pr-filter",
+	"pin",
+	"promote",
+	"describe",
+	"log-download",
+	"log-slices",
+	"admin",
+	"maintenance",
+	"service-account-tokens",
+	"own-tokens",
+	"notification-snoozes",
+	"number-groups",
+	"webhook-deliveries",
+}
+
+// supportedFeatures returns the features this server supports. Features which need something the server was set up
+// without, e.g. a store for webhook deliveries or a provenance signing key, are left out.
+func (srv *Service) supportedFeatures() []string {
+	unavailable := map[string]bool{
+		"deployments":            srv.Deployments == nil,
+		"preferences":            srv.Preferences == nil,
+		"provenance":             srv.provenanceKey == nil,
+		"service-account-tokens": srv.ServiceAccountTokens == nil,
+		"own-tokens":             srv.ServiceAccountTokens == nil,
+		"webhook-deliveries":     srv.WebhookDeliveries == nil,
+	}
+
+	res := make([]string, 0, len(Features))
+	for _, f := range Features {
+		if !unavailable[f] {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// ServerInfo describes the setup of this server as returned by GetServerInfo
+type ServerInfo struct {
+	Version       string
+	Plugins       []string
+	JobStore      string
+	LogStore      string
+	AuthProviders []string
+
+	// Config is the server configuration with all secrets redacted, as returned by DumpConfig
+	Config []byte
+}
+
+// GetServerInfo returns the version and capabilities of this server
+func (srv *Service) GetServerInfo(ctx context.Context, req *v1.GetServerInfoRequest) (*v1.GetServerInfoResponse, error) {
+	res := &v1.GetServerInfoResponse{
+		Version:       srv.Info.Version,
+		ApiVersion:    v1.APIVersion,
+		Features:      srv.supportedFeatures(),
+		Plugins:       srv.Info.Plugins,
+		JobStore:      srv.Info.JobStore,
+		LogStore:      srv.Info.LogStore,
+		AuthProviders: srv.Info.AuthProviders,
+		BaseUrl:       srv.config().BaseURL,
+		Maintenance:   srv.maintenanceMode(ctx),
+
+		AnonymousReadOnly: srv.config().AnonymousAccess == AnonymousReadOnly,
+	}
+	return proto.Clone(res).(*v1.GetServerInfoResponse), nil
+}
@@ -0,0 +1,145 @@
+package werft_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+// rpcFeatures maps each RPC to the feature clients detect it by. RPCs which predate GetServerInfo need no feature.
+var rpcFeatures = map[string]string{
+	"StartLocalJob":        "",
+	"StartGitHubJob":       "",
+	"StartFromPreviousJob": "",
+	"StartTarballJob":      "tarball-jobs",
+	"ListJobs":             "",
+	"Subscribe":            "",
+	"GetJob":               "",
+	"Listen":               "",
+	"StopJob":              "",
+	"GetStats":             "stats",
+	"GetRepoStats":         "repo-stats",
+	"GetServerInfo":        "",
+	"AnnotateJob":          "annotate",
+	"PinJob":               "pin",
+	"PromoteJob":           "promote",
+	"GetJobGraph":          "job-graph",
+	"StarJob":              "preferences",
+	"UnstarJob":            "preferences",
+	"ListStarredJobs":      "preferences",
+	"SaveSearch":           "preferences",
+	"DeleteSearch":         "preferences",
+	"ListSearches":         "preferences",
+	"ListDeployments":      "deployments",
+	"GetJobProvenance":     "provenance",
+	"DescribeJob":          "describe",
+	"DownloadLog":          "log-download",
+	"ListLogSlices":        "log-slices",
+	"ListOwnTokens":        "own-tokens",
+	"RevokeOwnToken":       "own-tokens",
+
+	"SetDrain":                  "admin",
+	"SetMaintenance":            "maintenance",
+	"RequeueStuckJobs":          "admin",
+	"Prune":                     "admin",
+	"ListTokens":                "admin",
+	"GetPluginStatus":           "admin",
+	"DumpConfig":                "admin",
+	"ListRepositories":          "admin",
+	"ListEvents":                "admin",
+	"SnoozeNotifications":       "notification-snoozes",
+	"ListSnoozes":               "notification-snoozes",
+	"DeleteSnooze":              "notification-snoozes",
+	"CreateServiceAccountToken": "service-account-tokens",
+	"ListServiceAccountTokens":  "service-account-tokens",
+	"RotateServiceAccountToken": "service-account-tokens",
+	"RevokeServiceAccountToken": "service-account-tokens",
+	"ListNumberGroups":          "number-groups",
+	"ResetNumberGroup":          "number-groups",
+	"DeleteNumberGroups":        "number-groups",
+	"ListWebhookDeliveries":     "webhook-deliveries",
+	"GetWebhookDelivery":        "webhook-deliveries",
+	"RedeliverWebhook":          "webhook-deliveries",
+}
+
+// TestFeaturesCoverRPCs makes sure we advertise a feature for every RPC we add
+func TestFeaturesCoverRPCs(t *testing.T) {
+	features := make(map[string]bool)
+	for _, f := range werft.Features {
+		if features[f] {
+			t.Errorf("feature %s is listed twice", f)
+		}
+		features[f] = true
+	}
+
+	for _, srv := range []reflect.Type{
+		reflect.TypeOf((*v1.WerftServiceServer)(nil)).Elem(),
+		reflect.TypeOf((*v1.WerftAdminServer)(nil)).Elem(),
+	} {
+		for i := 0; i < srv.NumMethod(); i++ {
+			rpc := srv.Method(i).Name
+			f, ok := rpcFeatures[rpc]
+			if !ok {
+				t.Errorf("%s.%s has no feature - add one to werft.Features and rpcFeatures", srv.Name(), rpc)
+				continue
+			}
+			if f != "" && !features[f] {
+				t.Errorf("%s.%s belongs to feature %s, which werft.Features does not list", srv.Name(), rpc, f)
+			}
+		}
+	}
+}
+
+func TestServerInfoFeatures(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Service     *werft.Service
+		Supported   []string
+		Unsupported []string
+	}{
+		{
+			Name:        "without stores",
+			Service:     &werft.Service{},
+			Supported:   []string{"log-slices", "admin"},
+			Unsupported: []string{"deployments", "preferences", "provenance", "service-account-tokens", "own-tokens", "webhook-deliveries"},
+		},
+		{
+			Name: "with stores",
+			Service: &werft.Service{
+				Deployments:          store.NewInMemoryDeployments(),
+				Preferences:          store.NewInMemoryPreferences(),
+				ServiceAccountTokens: store.NewInMemoryServiceAccountTokens(),
+				WebhookDeliveries:    store.NewInMemoryWebhookDeliveries(),
+			},
+			Supported:   []string{"deployments", "preferences", "service-account-tokens", "own-tokens", "webhook-deliveries"},
+			Unsupported: []string{"provenance"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := test.Service.GetServerInfo(context.Background(), &v1.GetServerInfoRequest{})
+			if err != nil {
+				t.Fatalf("cannot get server info: %v", err)
+			}
+			features := make(map[string]bool)
+			for _, f := range resp.Features {
+				features[f] = true
+			}
+			for _, f := range test.Supported {
+				if !features[f] {
+					t.Errorf("expected feature %s", f)
+				}
+			}
+			for _, f := range test.Unsupported {
+				if features[f] {
+					t.Errorf("unexpected feature %s", f)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/technosophos/moniker"
+	"golang.org/x/xerrors"
+)
+
+// Job naming schemes
+const (
+	// JobNamesDefault names jobs <repo>-<job spec>-<ref>.<n>
+	JobNamesDefault = "default"
+	// JobNamesSequential names jobs <repo>-<branch>-<n>, counting the jobs of a branch regardless of their job spec
+	JobNamesSequential = "sequential"
+)
+
+const (
+	// defaultNameSeparator separates the number from the rest of a job name in the default scheme
+	defaultNameSeparator = "."
+	// sequentialNameSeparator separates the number from the rest of a job name in the sequential scheme
+	sequentialNameSeparator = "-"
+
+	// maxJobNameAttempts is how often we draw a number before we give up finding a job name which is not taken
+	maxJobNameAttempts = 10
+)
+
+// refSlug turns a Git ref into something we can use in job names, e.g. refs/heads/feature/foo becomes feature-foo
+func refSlug(ref string) string {
+	res := ref
+	res = strings.TrimPrefix(res, "refs/heads/")
+	res = strings.TrimPrefix(res, "refs/tags/")
+	res = strings.ReplaceAll(res, "/", "-")
+	res = strings.ReplaceAll(res, "_", "-")
+	res = strings.ReplaceAll(res, "@", "-")
+	return strings.ToLower(res)
+}
+
+// newJobName produces the name of a new job in the naming scheme of this installation
+func (srv *Service) newJobName(ctx context.Context, md *v1.JobMetadata, jobSpecName string) (string, error) {
+	refname := refSlug(md.Repository.Ref)
+	if srv.config().JobNames == JobNamesSequential {
+		group := md.Repository.Repo
+		if refname != "" {
+			group += "-" + refname
+		}
+		return srv.nextJobName(ctx, group, sequentialNameSeparator)
+	}
+
+	if refname == "" {
+		// we did not compute a sensible refname - use moniker
+		refname = moniker.New().NameSep("-")
+	}
+	return srv.nextJobName(ctx, fmt.Sprintf("%s-%s-%s", md.Repository.Repo, jobSpecName, refname), defaultNameSeparator)
+}
+
+// nextJobName draws numbers from a group until the job name they make is not taken. Names of different groups can
+// clash, e.g. those of the repo foo-bar and the branch bar of the repo foo.
+func (srv *Service) nextJobName(ctx context.Context, group, sep string) (string, error) {
+	for i := 0; i < maxJobNameAttempts; i++ {
+		nr, err := srv.Groups.Next(ctx, group)
+		if err != nil {
+			return "", err
+		}
+
+		name := fmt.Sprintf("%s%s%d", group, sep, nr)
+		job, err := srv.Jobs.Get(ctx, name)
+		if err == store.ErrNotFound || (err == nil && job == nil) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", xerrors.Errorf("cannot find a job name in %s which is not taken", group)
+}
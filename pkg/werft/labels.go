@@ -0,0 +1,94 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+)
+
+// annotationLabel is set on jobs started by adding a label to a pull request and names the label
+const annotationLabel = "label"
+
+// processPullRequestLabel starts the jobs the repo config ties to a label once someone adds it to a pull request.
+// Only users with enough permission on the repository can start jobs that way.
+func (srv *Service) processPullRequestLabel(event *github.PullRequestEvent, jobEvent *v1.JobEvent) (outcome webhookOutcome) {
+	var (
+		ctx    = context.Background()
+		pr     = event.GetPullRequest()
+		base   = pr.GetBase()
+		repo   = base.GetRepo()
+		label  = event.GetLabel().GetName()
+		sender = event.GetSender().GetLogin()
+	)
+	if pr.GetState() != "open" || repo == nil || label == "" {
+		return skipWebhook("labels start jobs on open pull requests only")
+	}
+	var (
+		owner  = repo.GetOwner().GetLogin()
+		name   = repo.GetName()
+		logger = log.WithField("pr", pr.GetHTMLURL()).WithField("label", label)
+	)
+
+	// we read the config of the base branch, s.t. pull requests cannot add label triggers for themselves
+	repoCfg, err := getRepoCfg(ctx, &GitHubContentProvider{
+		Client:   srv.GitHub.Client,
+		Owner:    owner,
+		Repo:     name,
+		Revision: base.GetSHA(),
+	})
+	if err != nil {
+		logger.WithError(err).Debug("cannot read repo config - ignoring label")
+		return webhookOutcome{Errors: []string{fmt.Sprintf("cannot read repo config: %v", err)}}
+	}
+	triggers := repoCfg.LabelTriggers(label)
+	if len(triggers) == 0 {
+		return skipWebhook(fmt.Sprintf("the repo config ties no jobs to the label %s", label))
+	}
+
+	perm, _, err := srv.GitHub.Client.Repositories.GetPermissionLevel(ctx, owner, name, sender)
+	if err != nil {
+		logger.WithError(err).WithField("user", sender).Warn("cannot check the permission of the user who added the label")
+		return webhookOutcome{Errors: []string{fmt.Sprintf("cannot check the permission of %s: %v", sender, err)}}
+	}
+	for _, t := range triggers {
+		if !t.Allows(perm.GetPermission()) {
+			logger.WithField("user", sender).WithField("permission", perm.GetPermission()).Info("user lacks the permission to start jobs using this label")
+			outcome.Add(skipWebhook(fmt.Sprintf("%s lacks the permission to start jobs using this label", sender)))
+			continue
+		}
+
+		// the pull request ref exists in the base repository even if the head lives in a fork
+		md := v1.JobMetadata{
+			Owner: sender,
+			Repository: &v1.Repository{
+				Host:     "github.com",
+				Owner:    owner,
+				Repo:     name,
+				Ref:      fmt.Sprintf("refs/pull/%d/head", pr.GetNumber()),
+				Revision: pr.GetHead().GetSHA(),
+			},
+			Trigger: v1.JobTrigger_TRIGGER_MANUAL,
+			Event:   jobEvent,
+			Annotations: []*v1.Annotation{
+				{Key: annotationPullRequest, Value: strconv.Itoa(pr.GetNumber())},
+				{Key: annotationLabel, Value: label},
+				{Key: annotationStatusUpdate, Value: "true"},
+			},
+		}
+		resp, err := srv.startTriggeredJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: &md,
+			JobPath:  t.Job,
+		})
+		outcome.Started(resp, err)
+		if err != nil {
+			logger.WithError(err).WithField("job", t.Job).Warn("cannot start job for label")
+			continue
+		}
+		logger.WithField("user", sender).WithField("job", t.Job).Info("started job for label")
+	}
+	return outcome
+}
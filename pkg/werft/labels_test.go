@@ -0,0 +1,219 @@
+package werft_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+)
+
+const testLabelRepoConfig = `defaultJob: .werft/build.yaml
+labels:
+- label: needs-benchmark
+  job: .werft/benchmark.yaml
+- label: deploy
+  job: .werft/deploy.yaml
+  permission: admin
+`
+
+// newLabelGitHub fakes the GitHub API calls made when someone labels a pull request. It serves the werft config of the
+// base revision and the permissions users have on the repository. Looking up the permission of unknown users fails.
+func newLabelGitHub(baseRevision string, permissions map[string]string) *httptest.Server {
+	var hs *httptest.Server
+	hs = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/32leaves/werft/contents/.werft":
+			if r.URL.Query().Get("ref") != baseRevision {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode([]map[string]string{
+				{"type": "file", "name": "config.yaml", "path": ".werft/config.yaml", "download_url": hs.URL + "/raw/config.yaml"},
+			})
+		case r.URL.Path == "/raw/config.yaml":
+			fmt.Fprint(w, testLabelRepoConfig)
+		case strings.HasPrefix(r.URL.Path, "/repos/32leaves/werft/collaborators/"):
+			user := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/32leaves/werft/collaborators/"), "/permission")
+			perm, ok := permissions[user]
+			if !ok {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"permission": perm})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return hs
+}
+
+// testLabelPayload produces the payload of a pull_request webhook for a label someone added
+func testLabelPayload(state, label, sender, baseRevision string) string {
+	return fmt.Sprintf(`{
+		"action": "labeled",
+		"number": 1,
+		"label": {"name": %q},
+		"sender": {"login": %q},
+		"pull_request": {
+			"number": 1,
+			"state": %q,
+			"html_url": "https://github.com/32leaves/werft/pull/1",
+			"head": {"ref": "feature", "sha": "head-revision"},
+			"base": {"ref": "main", "sha": %q, "repo": {"name": "werft", "full_name": "32leaves/werft", "owner": {"login": "32leaves"}}}
+		},
+		"repository": {"name": "werft", "full_name": "32leaves/werft", "owner": {"login": "32leaves"}}
+	}`, label, sender, state, baseRevision)
+}
+
+func TestProcessPullRequestLabel(t *testing.T) {
+	permissions := map[string]string{"maintainer": "admin", "contributor": "write", "visitor": "read"}
+
+	tests := []struct {
+		Name         string
+		State        string
+		Label        string
+		Sender       string
+		BaseRevision string
+		SkipReason   string
+		Error        string
+		// Queued is the job started for the label. werft is in maintenance, s.t. it queues the job instead.
+		Queued string
+	}{
+		{
+			Name:       "closed pull request",
+			State:      "closed",
+			Label:      "needs-benchmark",
+			Sender:     "contributor",
+			SkipReason: "labels start jobs on open pull requests only",
+		},
+		{
+			Name:       "label without jobs",
+			Label:      "wontfix",
+			Sender:     "contributor",
+			SkipReason: "the repo config ties no jobs to the label wontfix",
+		},
+		{
+			Name:       "default permission",
+			Label:      "needs-benchmark",
+			Sender:     "visitor",
+			SkipReason: "visitor lacks the permission to start jobs using this label",
+		},
+		{
+			Name:       "required permission",
+			Label:      "deploy",
+			Sender:     "contributor",
+			SkipReason: "contributor lacks the permission to start jobs using this label",
+		},
+		{
+			Name:       "default permission granted",
+			Label:      "needs-benchmark",
+			Sender:     "contributor",
+			SkipReason: "queued because werft is in maintenance",
+			Queued:     ".werft/benchmark.yaml",
+		},
+		{
+			Name:       "required permission granted",
+			Label:      "deploy",
+			Sender:     "maintainer",
+			SkipReason: "queued because werft is in maintenance",
+			Queued:     ".werft/deploy.yaml",
+		},
+		{
+			Name:   "unknown permission",
+			Label:  "needs-benchmark",
+			Sender: "stranger",
+			Error:  "cannot check the permission of stranger",
+		},
+		{
+			// we read the config of the base revision, s.t. pull requests cannot add label triggers for themselves
+			Name:         "config of the head revision",
+			Label:        "needs-benchmark",
+			Sender:       "contributor",
+			BaseRevision: "head-revision",
+			Error:        "cannot read repo config",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gh := newLabelGitHub("base-revision", permissions)
+			defer gh.Close()
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(gh.URL + "/")
+
+			deliveries := store.NewInMemoryWebhookDeliveries()
+			srv := testService(werft.AnonymousFull)
+			srv.Repositories = store.NewInMemoryRepositories()
+			srv.WebhookDeliveries = deliveries
+			srv.GitHub = werft.GitHubSetup{WebhookSecret: []byte("secret"), Client: client}
+			srv.Jobs = store.NewInMemoryJobStore()
+			srv.Maintenance = store.NewInMemoryMaintenance()
+			_, err := srv.SetMaintenance(bearer("admin-secret"), &v1.SetMaintenanceRequest{Enabled: true, QueueTriggers: true})
+			if err != nil {
+				t.Fatalf("cannot enter maintenance: %v", err)
+			}
+
+			state, baseRevision := test.State, test.BaseRevision
+			if state == "" {
+				state = "open"
+			}
+			if baseRevision == "" {
+				baseRevision = "base-revision"
+			}
+			rec := httptest.NewRecorder()
+			srv.HandleGithubWebhook(rec, testWebhook("pull_request", testLabelPayload(state, test.Label, test.Sender, baseRevision), "secret"))
+
+			recorded, err := deliveries.List(context.Background(), store.WebhookDeliveryFilter{}, 0)
+			if err != nil || len(recorded) != 1 {
+				t.Fatalf("cannot list deliveries: %v", err)
+			}
+			d := recorded[0]
+			if len(d.Jobs) != 0 {
+				t.Errorf("unexpected jobs: %v", d.Jobs)
+			}
+			if d.SkipReason != test.SkipReason {
+				t.Errorf("unexpected skip reason: expected %q, got %q", test.SkipReason, d.SkipReason)
+			}
+			if (test.Error == "") != (d.Error == "") || !strings.Contains(d.Error, test.Error) {
+				t.Errorf("unexpected error: expected %q, got %q", test.Error, d.Error)
+			}
+
+			queued, err := srv.Maintenance.Dequeue(context.Background())
+			if err != nil {
+				t.Fatalf("cannot dequeue jobs: %v", err)
+			}
+			if test.Queued == "" {
+				if len(queued) != 0 {
+					t.Errorf("unexpected queued jobs: %v", queued)
+				}
+				return
+			}
+			if len(queued) != 1 {
+				t.Fatalf("expected one queued job, got %v", queued)
+			}
+			req := queued[0]
+			md := req.Metadata
+			if req.JobPath != test.Queued || md.Owner != test.Sender || md.Repository.Ref != "refs/pull/1/head" || md.Repository.Revision != "head-revision" {
+				t.Errorf("unexpected queued job: %v", req)
+			}
+			var label string
+			for _, a := range md.Annotations {
+				if a.Key == "label" {
+					label = a.Value
+				}
+			}
+			if label != test.Label {
+				t.Errorf("expected label annotation %q, got %q", test.Label, label)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package werft
+
+import (
+	"fmt"
+	"net/url"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+// jobURL returns the URL of a job in the UI. Links to failed jobs point to where they failed: the first log slice
+// which failed, or the phase the job was in when it failed.
+func (srv *Service) jobURL(job *v1.JobStatus) string {
+	res := fmt.Sprintf("%s/job/%s", srv.config().BaseURL, job.Name)
+	if job.Phase != v1.JobPhase_PHASE_DONE || job.Conditions == nil || job.Conditions.Success {
+		return res
+	}
+	if anchor := failureAnchor(job); anchor != "" {
+		res += "#" + url.PathEscape(anchor)
+	}
+	return res
+}
+
+// failureAnchor returns the anchor of where a job failed in the job view, or an empty string if we don't know
+func failureAnchor(job *v1.JobStatus) string {
+	if len(job.FailedSlices) > 0 {
+		s := job.FailedSlices[0]
+		return s.Phase + ":" + s.Slice
+	}
+
+	// The phase a job is in never finishes. Steps which run in their own container have an outcome once they're done.
+	for i := len(job.Steps) - 1; i >= 0; i-- {
+		s := job.Steps[i]
+		if s.Outcome == v1.StepOutcome_STEP_UNKNOWN && s.Started != nil && s.Finished == nil {
+			return "phase:" + s.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package werft
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logarchive"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+)
+
+// HandleLogArchive serves the complete log of a job as compressed archive, e.g. for attaching it to a bug report.
+// The job name is the last segment of the path, e.g. /api/v1/logs/werft-build-master.42. The format query parameter
+// is gzip (default) or zstd. With split=true the archive is a tarball with a file per log slice next to the complete log.
+// The log of a running job is sent as it's written - split archives are sent once the job is done.
+func (srv *Service) HandleLogArchive(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	if name == "" {
+		http.Error(w, "missing job name", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	format, err := logarchive.ParseFormat(q.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var split bool
+	if s := q.Get("split"); s != "" {
+		split, err = strconv.ParseBool(s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid split parameter: %s", s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	job, err := srv.Jobs.Get(r.Context(), name)
+	if err == store.ErrNotFound || (err == nil && job == nil) {
+		http.Error(w, fmt.Sprintf("%s not found", name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rd, err := srv.readLog(name)
+	if err == store.ErrNotFound {
+		http.Error(w, fmt.Sprintf("log of %s not found", name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rd.Close()
+
+	modTime := time.Now()
+	if job.Phase == v1.JobPhase_PHASE_DONE {
+		if t, err := ptypes.Timestamp(job.Metadata.GetFinished()); err == nil {
+			modTime = t
+		}
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s%s\"", name, format.Extension(split)))
+	err = logarchive.Write(w, name, rd, logarchive.Options{
+		Format:  format,
+		Split:   split,
+		Cutter:  srv.Cutter,
+		ModTime: modTime,
+	})
+	if err != nil {
+		// we've started sending the archive already, hence all we can do is log the error
+		log.WithError(err).WithField("name", name).Warn("cannot send log archive")
+	}
+}
@@ -0,0 +1,153 @@
+package werft
+
+import (
+	"context"
+	"net/http"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetMaintenance starts or ends maintenance
+func (srv *Service) SetMaintenance(ctx context.Context, req *v1.SetMaintenanceRequest) (*v1.SetMaintenanceResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	var mode *v1.MaintenanceMode
+	if req.Enabled {
+		mode = &v1.MaintenanceMode{
+			Enabled:       true,
+			Message:       req.Message,
+			QueueTriggers: req.QueueTriggers,
+			Since:         ptypes.TimestampNow(),
+		}
+	}
+	err := srv.Maintenance.Set(ctx, mode)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	srv.mu.Lock()
+	srv.maintenance = mode
+	srv.mu.Unlock()
+	log.WithField("enabled", req.Enabled).WithField("message", req.Message).Info("changed maintenance mode")
+
+	var resp v1.SetMaintenanceResponse
+	if req.Enabled && req.StopRunning {
+		running, err := srv.runningJobs(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, name := range running {
+			err := srv.Executor.Stop(name, "werft is going into maintenance")
+			if err != nil {
+				log.WithError(err).WithField("name", name).Warn("cannot stop job for maintenance")
+				continue
+			}
+			resp.Stopped = append(resp.Stopped, name)
+		}
+	}
+	if !req.Enabled {
+		queued, err := srv.Maintenance.Dequeue(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		sortByPriority(queued)
+		for _, q := range queued {
+			r, err := srv.startGitHubJob(ctx, q)
+			if err != nil {
+				log.WithError(err).WithField("repo", q.Metadata.Repository).Warn("cannot start queued job")
+				continue
+			}
+			resp.Started = append(resp.Started, r.Status.Name)
+		}
+	}
+
+	resp.Running, err = srv.runningJobs(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp.Mode = srv.maintenanceMode(ctx)
+	return &resp, nil
+}
+
+// startTriggeredJob starts a job which was triggered by a webhook or another job. During maintenance such jobs are
+// queued if the maintenance mode says so.
+func (srv *Service) startTriggeredJob(ctx context.Context, req *v1.StartGitHubJobRequest) (*v1.StartJobResponse, error) {
+	srv.mu.RLock()
+	mode := srv.maintenance
+	srv.mu.RUnlock()
+	if mode == nil || !mode.Enabled || !mode.QueueTriggers {
+		return srv.startGitHubJob(ctx, req)
+	}
+
+	// the queue is ordered by priority, hence queued jobs need one
+	if req.Metadata != nil {
+		err := srv.assignPriority(req.Metadata)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	err := srv.Maintenance.Enqueue(ctx, req)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("repo", req.Metadata.Repository).Info("werft is in maintenance - queued job")
+	return &v1.StartJobResponse{}, nil
+}
+
+// maintenanceMode returns the current maintenance mode including the number of queued jobs, or nil if werft is not in maintenance
+func (srv *Service) maintenanceMode(ctx context.Context) *v1.MaintenanceMode {
+	srv.mu.RLock()
+	mode := srv.maintenance
+	srv.mu.RUnlock()
+	if mode == nil {
+		return nil
+	}
+
+	res := proto.Clone(mode).(*v1.MaintenanceMode)
+	queued, err := srv.Maintenance.Queued(ctx)
+	if err != nil {
+		log.WithError(err).Warn("cannot count queued jobs")
+	}
+	res.Queued = int32(queued)
+	return res
+}
+
+// runningJobs returns the names of all jobs which are not done yet
+func (srv *Service) runningJobs(ctx context.Context) ([]string, error) {
+	jobs, _, err := srv.Jobs.Find(ctx,
+		[]*v1.FilterExpression{{Terms: []*v1.FilterTerm{{Field: "phase", Value: "done", Negate: true}}}},
+		nil, 0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		res = append(res, j.Name)
+	}
+	return res, nil
+}
+
+// HandleMaintenance serves the maintenance mode as JSON, e.g. for the UI to show a banner during maintenance.
+// Outside of maintenance the response is an empty object.
+func (srv *Service) HandleMaintenance(w http.ResponseWriter, r *http.Request) {
+	mode := srv.maintenanceMode(r.Context())
+	if mode == nil {
+		mode = &v1.MaintenanceMode{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	err := (&jsonpb.Marshaler{}).Marshal(w, mode)
+	if err != nil {
+		log.WithError(err).Warn("cannot serve maintenance mode")
+	}
+}
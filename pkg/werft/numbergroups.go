@@ -0,0 +1,208 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// repoNumberGroupPrefix starts the number groups which count the jobs of a repository. Job names cannot contain
+	// a colon, hence those groups never clash with the groups job names are numbered from.
+	repoNumberGroupPrefix = "repo:"
+
+	// numberGroupGCInterval is how often we look for number groups no job refers to anymore
+	numberGroupGCInterval = 24 * time.Hour
+)
+
+// repoNumberGroup returns the name of the number group which counts the jobs of a repository
+func repoNumberGroup(repo *v1.Repository) string {
+	return fmt.Sprintf("%s%s/%s/%s", repoNumberGroupPrefix, repo.Host, repo.Owner, repo.Repo)
+}
+
+// jobNumberGroup splits a job name into the number group it was numbered from, the separator of its naming scheme and
+// its number. Returns false if the name does not end in a number.
+func jobNumberGroup(name string) (group, sep string, nr int, ok bool) {
+	for _, sep := range []string{defaultNameSeparator, sequentialNameSeparator} {
+		idx := strings.LastIndex(name, sep)
+		if idx < 0 {
+			continue
+		}
+		nr, err := strconv.Atoi(name[idx+1:])
+		if err != nil {
+			continue
+		}
+		return name[:idx], sep, nr, true
+	}
+	return "", "", 0, false
+}
+
+// assignNumber gives a job the next build number of its repository
+func (srv *Service) assignNumber(ctx context.Context, md *v1.JobMetadata) error {
+	if md.Repository == nil || md.Repository.Owner == "" || md.Repository.Repo == "" {
+		return nil
+	}
+
+	nr, err := srv.Groups.Next(ctx, repoNumberGroup(md.Repository))
+	if err != nil {
+		return err
+	}
+	// number groups start at zero, but build numbers are for humans
+	md.Number = int32(nr + 1)
+	return nil
+}
+
+// numberGroupUsage returns the highest number the known jobs use from each number group
+func (srv *Service) numberGroupUsage(ctx context.Context) (map[string]int, error) {
+	jobs, _, err := srv.Jobs.Find(ctx, nil, nil, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int)
+	use := func(group string, nr int) {
+		if cur, ok := res[group]; !ok || nr > cur {
+			res[group] = nr
+		}
+	}
+	for _, job := range jobs {
+		if group, _, nr, ok := jobNumberGroup(job.Name); ok {
+			use(group, nr)
+		}
+		if repo := job.Metadata.GetRepository(); repo != nil && job.Metadata.Number > 0 {
+			use(repoNumberGroup(repo), int(job.Metadata.Number)-1)
+		}
+	}
+	return res, nil
+}
+
+// ListNumberGroups lists all number groups and their latest number
+func (srv *Service) ListNumberGroups(ctx context.Context, req *v1.ListNumberGroupsRequest) (*v1.ListNumberGroupsResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	groups, err := srv.Groups.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ListNumberGroupsResponse{Groups: groups}, nil
+}
+
+// ResetNumberGroup sets the latest number of a group
+func (srv *Service) ResetNumberGroup(ctx context.Context, req *v1.ResetNumberGroupRequest) (*v1.ResetNumberGroupResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if req.Latest < -1 {
+		return nil, status.Error(codes.InvalidArgument, "latest must be at least -1")
+	}
+
+	if !req.Force {
+		// Going back would make the next jobs reuse the names of existing ones and overwrite them
+		usage, err := srv.numberGroupUsage(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if used, ok := usage[req.Name]; ok && int64(used) > req.Latest {
+			return nil, status.Errorf(codes.FailedPrecondition, "there are jobs up to number %d in %s - use force to reset anyway", used, req.Name)
+		}
+	}
+
+	err := srv.Groups.Reset(ctx, req.Name, int(req.Latest))
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "number group %s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ResetNumberGroupResponse{Group: &v1.NumberGroup{Name: req.Name, Latest: req.Latest}}, nil
+}
+
+// DeleteNumberGroups removes number groups, by default those no job refers to anymore
+func (srv *Service) DeleteNumberGroups(ctx context.Context, req *v1.DeleteNumberGroupsRequest) (*v1.DeleteNumberGroupsResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	names := req.Names
+	if len(names) == 0 {
+		var err error
+		names, err = srv.orphanedNumberGroups(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	res := &v1.DeleteNumberGroupsResponse{}
+	for _, name := range names {
+		if !req.DryRun {
+			err := srv.Groups.Delete(ctx, name)
+			if err == store.ErrNotFound && len(req.Names) > 0 {
+				return nil, status.Errorf(codes.NotFound, "number group %s not found", name)
+			}
+			if err != nil && err != store.ErrNotFound {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		res.Names = append(res.Names, name)
+	}
+	return res, nil
+}
+
+// orphanedNumberGroups returns the number groups no job refers to anymore, e.g. because their jobs were pruned or their
+// repository was removed. Deleting them is safe because no job name can clash when they start from zero again.
+func (srv *Service) orphanedNumberGroups(ctx context.Context) ([]string, error) {
+	groups, err := srv.Groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := srv.numberGroupUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, g := range groups {
+		if _, ok := usage[g.Name]; ok {
+			continue
+		}
+		res = append(res, g.Name)
+	}
+	return res, nil
+}
+
+// collectNumberGroups periodically removes the number groups no job refers to anymore
+func (srv *Service) collectNumberGroups() {
+	tick := time.NewTicker(numberGroupGCInterval)
+	defer tick.Stop()
+	for {
+		if srv.config().NumberGroupGC {
+			names, err := srv.orphanedNumberGroups(context.Background())
+			if err != nil {
+				log.WithError(err).Warn("cannot collect number groups")
+			}
+			for _, name := range names {
+				err = srv.Groups.Delete(context.Background(), name)
+				if err != nil && err != store.ErrNotFound {
+					log.WithError(err).WithField("group", name).Warn("cannot delete number group")
+				}
+			}
+			if len(names) > 0 {
+				log.WithField("count", len(names)).Info("removed number groups no job refers to")
+			}
+		}
+		<-tick.C
+	}
+}
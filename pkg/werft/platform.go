@@ -0,0 +1,75 @@
+package werft
+
+import (
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// WindowsConfig configures how werft runs jobs on Windows nodes
+type WindowsConfig struct {
+	// CheckoutImage is the image the checkout of Windows jobs runs in. It needs git and a POSIX shell, e.g. an image
+	// which contains Git for Windows. Without it werft does not run jobs on Windows.
+	CheckoutImage string `yaml:"checkoutImage,omitempty"`
+}
+
+// platformNodeSelector selects the nodes of a platform using the labels the kubelet sets
+func platformNodeSelector(p *repoconfig.Platform) map[string]string {
+	res := map[string]string{corev1.LabelOSStable: p.OS}
+	if p.Arch != "" {
+		res[corev1.LabelArchStable] = p.Arch
+	}
+	return res
+}
+
+// platformTolerations tolerates the taints cloud providers put on the nodes of a platform to keep other pods away,
+// e.g. node.kubernetes.io/os=windows on Windows nodes or kubernetes.io/arch=arm64 on arm nodes
+func platformTolerations(p *repoconfig.Platform) []corev1.Toleration {
+	var res []corev1.Toleration
+	if p.OS == repoconfig.OSWindows {
+		res = append(res, corev1.Toleration{
+			Key:      "node.kubernetes.io/os",
+			Operator: corev1.TolerationOpEqual,
+			Value:    p.OS,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	if p.Arch != "" && p.Arch != "amd64" {
+		res = append(res, corev1.Toleration{
+			Key:      corev1.LabelArchStable,
+			Operator: corev1.TolerationOpEqual,
+			Value:    p.Arch,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return res
+}
+
+// applyPlatform schedules a pod on the nodes of a platform
+func applyPlatform(podspec *corev1.PodSpec, p *repoconfig.Platform) {
+	if podspec.NodeSelector == nil {
+		podspec.NodeSelector = make(map[string]string)
+	}
+	for k, v := range platformNodeSelector(p) {
+		podspec.NodeSelector[k] = v
+	}
+	podspec.Tolerations = append(podspec.Tolerations, platformTolerations(p)...)
+}
+
+// checkPlatformNodes returns an error if the cluster has no ready node of the platform. Jobs would wait for such a node forever.
+func (srv *Service) checkPlatformNodes(p *repoconfig.Platform) error {
+	nodes, err := srv.Executor.Client.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(platformNodeSelector(p)).String(),
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		if isNodeReady(&node) {
+			return nil
+		}
+	}
+	return xerrors.Errorf("the cluster has no ready %s node", p)
+}
@@ -0,0 +1,143 @@
+package werft
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// preferencesUser returns the user whose preferences a request refers to, i.e. the name of the token it carries
+func (srv *Service) preferencesUser(ctx context.Context) (string, error) {
+	if srv.Preferences == nil {
+		return "", status.Error(codes.Unimplemented, "this werft installation does not store preferences")
+	}
+	tkn, err := srv.requireToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tkn.Name, nil
+}
+
+// StarJob adds a job to the starred jobs of the authenticated user
+func (srv *Service) StarJob(ctx context.Context, req *v1.StarJobRequest) (*v1.StarJobResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = srv.Preferences.Star(ctx, user, req.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.StarJobResponse{}, nil
+}
+
+// UnstarJob removes a job from the starred jobs of the authenticated user
+func (srv *Service) UnstarJob(ctx context.Context, req *v1.UnstarJobRequest) (*v1.UnstarJobResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.Preferences.Unstar(ctx, user, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s is not starred", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.UnstarJobResponse{}, nil
+}
+
+// ListStarredJobs returns the starred jobs of the authenticated user
+func (srv *Service) ListStarredJobs(ctx context.Context, req *v1.ListStarredJobsRequest) (*v1.ListStarredJobsResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := srv.Preferences.Starred(ctx, user)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &v1.ListStarredJobsResponse{}
+	for _, name := range names {
+		job, err := srv.Jobs.Get(ctx, name)
+		if err == store.ErrNotFound {
+			// the job was removed since it was starred, e.g. by werft admin prune
+			log.WithField("name", name).WithField("user", user).Debug("starred job does not exist anymore")
+			continue
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res.Result = append(res.Result, job)
+	}
+	return res, nil
+}
+
+// SaveSearch stores a search of the authenticated user
+func (srv *Service) SaveSearch(ctx context.Context, req *v1.SaveSearchRequest) (*v1.SaveSearchResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Search == nil || req.Search.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "search name is required")
+	}
+	err = filterexpr.Validate(req.Search.Filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = srv.Preferences.SaveSearch(ctx, user, req.Search)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.SaveSearchResponse{}, nil
+}
+
+// DeleteSearch removes a saved search of the authenticated user
+func (srv *Service) DeleteSearch(ctx context.Context, req *v1.DeleteSearchRequest) (*v1.DeleteSearchResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.Preferences.DeleteSearch(ctx, user, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "search %s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.DeleteSearchResponse{}, nil
+}
+
+// ListSearches returns the saved searches of the authenticated user
+func (srv *Service) ListSearches(ctx context.Context, req *v1.ListSearchesRequest) (*v1.ListSearchesResponse, error) {
+	user, err := srv.preferencesUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	searches, err := srv.Preferences.Searches(ctx, user)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ListSearchesResponse{Searches: searches}, nil
+}
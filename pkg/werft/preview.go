@@ -0,0 +1,150 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// resultTypePreview is the type of results which register a preview environment
+	resultTypePreview = "preview"
+
+	// annotationPreviewEnvironment is set on teardown jobs and names the preview environment to remove
+	annotationPreviewEnvironment = "previewEnvironment"
+	// annotationPullRequest is set on teardown jobs and contains the number of the closed pull request
+	annotationPullRequest = "pullRequest"
+
+	// previewJobSearchLimit is the number of most recent jobs of a branch we look at for preview environments
+	previewJobSearchLimit = 100
+)
+
+// processPullRequestEvent tears down the preview environments of a branch once its pull request is closed or merged,
+// and starts the jobs of labels added to pull requests
+func (srv *Service) processPullRequestEvent(event *github.PullRequestEvent, payload []byte) (outcome webhookOutcome) {
+	if event.GetAction() != "labeled" && event.GetAction() != "closed" {
+		return skipWebhook(fmt.Sprintf("werft does not act on pull requests being %s", event.GetAction()))
+	}
+	details, err := parseEventPullRequest(payload)
+	if err != nil || details == nil {
+		log.WithError(err).Warn("cannot parse pull request event")
+		return webhookOutcome{Errors: []string{"cannot parse pull request event"}}
+	}
+	jobEvent := details.JobEvent(eventTypePullRequest)
+	if event.GetAction() == "labeled" {
+		return srv.processPullRequestLabel(event, jobEvent)
+	}
+
+	var (
+		ctx  = context.Background()
+		pr   = event.GetPullRequest()
+		head = pr.GetHead()
+		repo = head.GetRepo()
+	)
+	if repo == nil {
+		// the head repository was deleted - we cannot have built anything from it
+		return skipWebhook("the head repository of the pull request was deleted")
+	}
+	md := v1.JobMetadata{
+		Owner: event.GetSender().GetLogin(),
+		Repository: &v1.Repository{
+			Host:     "github.com",
+			Owner:    repo.GetOwner().GetLogin(),
+			Repo:     repo.GetName(),
+			Ref:      "refs/heads/" + head.GetRef(),
+			Revision: head.GetSHA(),
+		},
+		Trigger: v1.JobTrigger_TRIGGER_DELETED,
+		Event:   jobEvent,
+	}
+	if pr.GetMerged() && pr.GetMergeCommitSHA() != "" {
+		// the branch is likely deleted once the PR is merged, in which case we could no longer check out its head
+		md.Repository.Revision = pr.GetMergeCommitSHA()
+	}
+	logger := log.WithField("pr", pr.GetHTMLURL())
+
+	envs, err := srv.findPreviewEnvironments(ctx, md.Repository)
+	if err != nil {
+		logger.WithError(err).Warn("cannot find preview environments")
+		return webhookOutcome{Errors: []string{fmt.Sprintf("cannot find preview environments: %v", err)}}
+	}
+	if len(envs) == 0 {
+		return skipWebhook("the branch has no preview environments")
+	}
+
+	repoCfg, err := getRepoCfg(ctx, &GitHubContentProvider{
+		Client:   srv.GitHub.Client,
+		Owner:    md.Repository.Owner,
+		Repo:     md.Repository.Repo,
+		Revision: md.Repository.Revision,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("cannot tear down preview environments")
+		return webhookOutcome{Errors: []string{fmt.Sprintf("cannot read repo config: %v", err)}}
+	}
+	if repoCfg.Preview == nil || repoCfg.Preview.Teardown == "" {
+		logger.WithField("environments", envs).Info("pull request closed but repo configures no preview teardown job")
+		return skipWebhook("the repo config has no preview teardown job")
+	}
+
+	for _, env := range envs {
+		jmd := md
+		jmd.Annotations = []*v1.Annotation{
+			{Key: annotationPreviewEnvironment, Value: env},
+			{Key: annotationPullRequest, Value: strconv.Itoa(pr.GetNumber())},
+			{Key: annotationStatusUpdate, Value: "true"},
+		}
+		resp, err := srv.startTriggeredJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: &jmd,
+			JobPath:  repoCfg.Preview.Teardown,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("environment", env).Warn("cannot start preview teardown job")
+		}
+		outcome.Started(resp, err)
+	}
+	return outcome
+}
+
+// findPreviewEnvironments returns the preview environments jobs on a branch registered
+func (srv *Service) findPreviewEnvironments(ctx context.Context, repo *v1.Repository) ([]string, error) {
+	jobs, _, err := srv.Jobs.Find(ctx,
+		[]*v1.FilterExpression{
+			{Terms: []*v1.FilterTerm{{Field: "repo.owner", Value: repo.Owner}}},
+			{Terms: []*v1.FilterTerm{{Field: "repo.repo", Value: repo.Repo}}},
+			{Terms: []*v1.FilterTerm{{Field: "repo.ref", Value: repo.Ref}}},
+		},
+		[]*v1.OrderExpression{{Field: "created", Ascending: false}},
+		0, previewJobSearchLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res  []string
+		seen = make(map[string]struct{})
+	)
+	for _, job := range jobs {
+		for _, r := range job.Results {
+			if r.Type != resultTypePreview {
+				continue
+			}
+			d, err := parseDeployment(r)
+			if err != nil {
+				log.WithError(err).WithField("name", job.Name).Debug("ignoring invalid preview result")
+				continue
+			}
+			if _, ok := seen[d.Environment]; ok {
+				continue
+			}
+			seen[d.Environment] = struct{}{}
+			res = append(res, d.Environment)
+		}
+	}
+	return res, nil
+}
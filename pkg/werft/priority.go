@@ -0,0 +1,121 @@
+package werft
+
+import (
+	"sort"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// annotationPriority sets the priority of a job. Triggers set it explicitly, otherwise the priority rules decide.
+	// Asking for high priority requires the priority scope, see mayPrioritize.
+	annotationPriority = "werft.sh/priority"
+
+	// PriorityLow is for jobs which can wait, e.g. nightly builds
+	PriorityLow = "low"
+	// PriorityNormal is the priority of jobs which have none set
+	PriorityNormal = "normal"
+	// PriorityHigh is for jobs which should run first, e.g. main branch pushes
+	PriorityHigh = "high"
+)
+
+// priorityRank orders the priorities. Higher ranks run first.
+var priorityRank = map[string]int{
+	PriorityLow:    -1,
+	PriorityNormal: 0,
+	PriorityHigh:   1,
+}
+
+// PriorityConfig configures job priorities
+type PriorityConfig struct {
+	// Rules assign priorities to jobs which have none set. The first matching rule wins.
+	Rules []*PriorityRule `yaml:"rules,omitempty"`
+
+	// Classes maps priorities to Kubernetes priority classes, e.g. high: werft-high.
+	// Jobs whose priority has no class, or which set a priority class themselves, run as they are.
+	Classes map[string]string `yaml:"classes,omitempty"`
+}
+
+// PriorityRule assigns a priority to jobs matching its filter
+type PriorityRule struct {
+	Priority string
+	Expr     []*v1.FilterExpression
+}
+
+// UnmarshalYAML unmarshals the filter expressions
+func (r *PriorityRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawPriorityRule struct {
+		Priority string                      `yaml:"priority"`
+		Expr     []repoconfig.JobStartRuleOr `yaml:"matchesAll"`
+	}
+	err := unmarshal(&rawPriorityRule)
+	if err != nil {
+		return err
+	}
+	if _, ok := priorityRank[rawPriorityRule.Priority]; !ok {
+		return xerrors.Errorf("unknown priority \"%s\"", rawPriorityRule.Priority)
+	}
+
+	r.Priority = rawPriorityRule.Priority
+	for _, expr := range rawPriorityRule.Expr {
+		terms, err := filterexpr.Parse(expr.Or)
+		if err != nil {
+			return err
+		}
+		r.Expr = append(r.Expr, &v1.FilterExpression{Terms: terms})
+	}
+	return nil
+}
+
+// assignPriority makes sure a job has a valid priority annotation
+func (srv *Service) assignPriority(md *v1.JobMetadata) error {
+	for _, a := range md.Annotations {
+		if a.Key != annotationPriority {
+			continue
+		}
+		if _, ok := priorityRank[a.Value]; !ok {
+			return xerrors.Errorf("unknown priority \"%s\" - must be %s, %s or %s", a.Value, PriorityLow, PriorityNormal, PriorityHigh)
+		}
+		return nil
+	}
+
+	prio := PriorityNormal
+	js := &v1.JobStatus{Metadata: md}
+	for _, rule := range srv.config().Priority.Rules {
+		if filterexpr.MatchesFilter(js, rule.Expr) {
+			prio = rule.Priority
+			break
+		}
+	}
+	md.Annotations = append(md.Annotations, &v1.Annotation{Key: annotationPriority, Value: prio})
+	return nil
+}
+
+// jobPriority returns the priority of a job
+func jobPriority(md *v1.JobMetadata) string {
+	for _, a := range md.GetAnnotations() {
+		if a.Key == annotationPriority {
+			return a.Value
+		}
+	}
+	return PriorityNormal
+}
+
+// applyPriorityClass sets the priority class of a job's pod
+func (srv *Service) applyPriorityClass(podspec *corev1.PodSpec, md *v1.JobMetadata) {
+	if podspec.PriorityClassName != "" {
+		return
+	}
+	podspec.PriorityClassName = srv.config().Priority.Classes[jobPriority(md)]
+}
+
+// sortByPriority orders queued jobs s.t. jobs with higher priority come first. Jobs of the same priority keep their order.
+func sortByPriority(queued []*v1.StartGitHubJobRequest) {
+	sort.SliceStable(queued, func(i, j int) bool {
+		return priorityRank[jobPriority(queued[i].Metadata)] > priorityRank[jobPriority(queued[j].Metadata)]
+	})
+}
@@ -0,0 +1,58 @@
+package werft
+
+import (
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// progressInterval is how often we record the progress of a job at most. Each time we do, we patch the job pod.
+const progressInterval = 5 * time.Second
+
+// progressThrottle coalesces the progress a job reports, s.t. we record it at most once per interval
+type progressThrottle struct {
+	Interval time.Duration
+
+	recorded     *v1.JobProgress
+	recordedTime time.Time
+	pending      *v1.JobProgress
+}
+
+// Offer takes the progress a job reported. It returns the progress to record now, or nil if there is nothing to
+// record yet. Progress which arrives too soon after the last one recorded is kept until it is Due.
+func (t *progressThrottle) Offer(now time.Time, progress *v1.JobProgress) *v1.JobProgress {
+	// jobs tend to report the same progress repeatedly - we don't want to update the job every time
+	if proto.Equal(progress, t.recorded) {
+		t.pending = nil
+		return nil
+	}
+	if now.Sub(t.recordedTime) < t.Interval {
+		t.pending = progress
+		return nil
+	}
+	t.pending = nil
+	t.recorded = progress
+	t.recordedTime = now
+	return progress
+}
+
+// Due returns when the pending progress should be recorded, and false if there is none
+func (t *progressThrottle) Due() (time.Time, bool) {
+	if t.pending == nil {
+		return time.Time{}, false
+	}
+	return t.recordedTime.Add(t.Interval), true
+}
+
+// Flush returns the pending progress to record it, or nil if there is none
+func (t *progressThrottle) Flush(now time.Time) *v1.JobProgress {
+	progress := t.pending
+	if progress == nil {
+		return nil
+	}
+	t.pending = nil
+	t.recorded = progress
+	t.recordedTime = now
+	return progress
+}
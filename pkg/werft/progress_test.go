@@ -0,0 +1,113 @@
+package werft_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestProgressThrottle(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	progress := func(step int32) *v1.JobProgress { return &v1.JobProgress{Step: step, TotalSteps: 10} }
+
+	type step struct {
+		Offset time.Duration
+		// Progress is offered to the throttle. If nil, the throttle is flushed instead.
+		Progress *v1.JobProgress
+		Recorded *v1.JobProgress
+		Pending  bool
+	}
+	tests := []struct {
+		Name  string
+		Steps []step
+	}{
+		{
+			Name: "first progress is recorded right away",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+			},
+		},
+		{
+			Name: "repeated progress is not recorded",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+				{10 * time.Second, progress(1), nil, false},
+			},
+		},
+		{
+			Name: "coalesced within the interval",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+				{time.Second, progress(2), nil, true},
+				{2 * time.Second, progress(3), nil, true},
+				{5 * time.Second, nil, progress(3), false},
+				{6 * time.Second, progress(4), nil, true},
+			},
+		},
+		{
+			Name: "recorded after the interval",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+				{time.Second, progress(2), nil, true},
+				{6 * time.Second, progress(3), progress(3), false},
+				{7 * time.Second, nil, nil, false},
+			},
+		},
+		{
+			Name: "back to the recorded progress",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+				{time.Second, progress(2), nil, true},
+				{2 * time.Second, progress(1), nil, false},
+				{5 * time.Second, nil, nil, false},
+			},
+		},
+		{
+			Name: "final flush",
+			Steps: []step{
+				{0, progress(1), progress(1), false},
+				{time.Second, progress(10), nil, true},
+				{time.Second, nil, progress(10), false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pt := &werft.ProgressThrottle{Interval: 5 * time.Second}
+			for i, s := range test.Steps {
+				now := t0.Add(s.Offset)
+				var act *v1.JobProgress
+				if s.Progress == nil {
+					act = pt.Flush(now)
+				} else {
+					act = pt.Offer(now, s.Progress)
+				}
+				if !proto.Equal(act, s.Recorded) {
+					t.Errorf("step %d: expected to record %v, actual %v", i, s.Recorded, act)
+				}
+				if _, pending := pt.Due(); pending != s.Pending {
+					t.Errorf("step %d: expected pending %v, actual %v", i, s.Pending, pending)
+				}
+			}
+		})
+	}
+}
+
+func TestProgressThrottleDue(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pt := &werft.ProgressThrottle{Interval: 5 * time.Second}
+	pt.Offer(t0, &v1.JobProgress{Percent: 1})
+	pt.Offer(t0.Add(time.Second), &v1.JobProgress{Percent: 2})
+
+	due, ok := pt.Due()
+	if !ok {
+		t.Fatal("expected pending progress")
+	}
+	if exp := t0.Add(5 * time.Second); !due.Equal(exp) {
+		t.Errorf("expected progress to be due at %v, actual %v", exp, due)
+	}
+}
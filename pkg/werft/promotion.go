@@ -0,0 +1,112 @@
+package werft
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// annotationPromotion is set on promotion jobs and names the promotion they perform, e.g. production
+	annotationPromotion = "promotion"
+	// annotationPromotedJob is set on promotion jobs and names the job they promote
+	annotationPromotedJob = "promotedJob"
+	// annotationPromotedImages is set on promotion jobs and lists the images the promoted job built, separated by spaces
+	annotationPromotedImages = "promotedImages"
+)
+
+// PromoteJob releases what a successful job built by starting the promotion job configured in its repository
+func (srv *Service) PromoteJob(ctx context.Context, req *v1.PromoteJobRequest) (*v1.StartJobResponse, error) {
+	if req.Promotion == "" {
+		return nil, status.Error(codes.InvalidArgument, "promotion is required")
+	}
+	for _, a := range req.Annotations {
+		if isWerftAnnotation(a.Key) {
+			return nil, status.Errorf(codes.InvalidArgument, "annotation %s is reserved", a.Key)
+		}
+	}
+
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	repo := job.Metadata.GetRepository()
+	if err := srv.authorizeWrite(ctx, repo); err != nil {
+		return nil, err
+	}
+	if repo == nil || repo.Owner == "" || repo.Revision == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s did not run on a GitHub repository", req.Name)
+	}
+
+	// the promotion comes from the revision the job ran on, just like the promotion job itself
+	promotion := srv.downloadJobRepoConfig(ctx, job).Promotion(req.Promotion)
+	if promotion == nil {
+		return nil, status.Errorf(codes.NotFound, "%s/%s has no promotion %s at %s", repo.Owner, repo.Repo, req.Promotion, repo.Revision)
+	}
+	if !promotion.Accepts(job) {
+		if job.Phase != v1.JobPhase_PHASE_DONE || job.Conditions == nil || !job.Conditions.Success {
+			return nil, status.Errorf(codes.FailedPrecondition, "only successful jobs can be promoted")
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "promotion %s only promotes jobs of %s", req.Promotion, strings.Join(promotion.From, ", "))
+	}
+
+	annotations := []*v1.Annotation{
+		{Key: annotationPromotion, Value: promotion.Name},
+		{Key: annotationPromotedJob, Value: job.Name},
+	}
+	if images := promotedImages(job); len(images) > 0 {
+		annotations = append(annotations, &v1.Annotation{Key: annotationPromotedImages, Value: strings.Join(images, " ")})
+	}
+	annotations = append(annotations, req.Annotations...)
+
+	// promotions belong to whoever promotes, which is the owner of the promoted job unless we know better
+	owner := job.Metadata.Owner
+	if tkn, err := srv.authenticate(ctx); err == nil && tkn != nil {
+		owner = tkn.Name
+	}
+	md := &v1.JobMetadata{
+		Owner: owner,
+		Repository: &v1.Repository{
+			Host:     repo.Host,
+			Owner:    repo.Owner,
+			Repo:     repo.Repo,
+			Ref:      repo.Ref,
+			Revision: repo.Revision,
+		},
+		Trigger:     v1.JobTrigger_TRIGGER_PROMOTION,
+		Annotations: annotations,
+	}
+	if err := srv.checkRateLimit(ctx, md); err != nil {
+		return nil, err
+	}
+
+	resp, err := srv.startGitHubJob(ctx, &v1.StartGitHubJobRequest{
+		Metadata: md,
+		JobPath:  promotion.Job,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("name", job.Name).WithField("promotion", promotion.Name).WithField("job", resp.Status.GetName()).Info("promoted job")
+	return resp, nil
+}
+
+// promotedImages returns the images a job built, i.e. the payloads of its image results
+func promotedImages(job *v1.JobStatus) []string {
+	var res []string
+	for _, r := range job.Results {
+		if r.Type != resultTypeImage {
+			continue
+		}
+		res = append(res, r.Payload)
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProvenanceConfig configures the provenance attestations werft produces for successful jobs
+type ProvenanceConfig struct {
+	// SigningKeyPath is the path to the PEM encoded ECDSA or RSA key we sign attestations with.
+	// If empty, werft does not produce attestations.
+	SigningKeyPath string `yaml:"signingKeyPath,omitempty"`
+
+	// BuilderID identifies this werft installation in attestations. Defaults to the base URL.
+	BuilderID string `yaml:"builderID,omitempty"`
+}
+
+// recordProvenance produces and stores the signed provenance attestation of a successful job
+func (srv *Service) recordProvenance(job *v1.JobStatus) {
+	if srv.provenanceKey == nil || job.Conditions == nil || !job.Conditions.Success {
+		return
+	}
+	logger := log.WithField("name", job.Name)
+	ctx := context.Background()
+
+	jobSpec, err := srv.Jobs.GetJobSpec(ctx, job.Name)
+	if err != nil && err != store.ErrNotFound {
+		logger.WithError(err).Warn("cannot get job spec for provenance")
+	}
+
+	builderID := srv.config().Provenance.BuilderID
+	if builderID == "" {
+		builderID = srv.config().BaseURL
+	}
+	keyID, err := provenance.KeyID(srv.provenanceKey.Public())
+	if err != nil {
+		logger.WithError(err).Warn("cannot produce provenance")
+		return
+	}
+	env, err := provenance.Sign(provenance.NewStatement(job, builderID, jobSpec), srv.provenanceKey, keyID)
+	if err != nil {
+		logger.WithError(err).Warn("cannot produce provenance")
+		return
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		logger.WithError(err).Warn("cannot produce provenance")
+		return
+	}
+
+	err = srv.Attestations.Store(ctx, job.Name, data)
+	if err != nil {
+		logger.WithError(err).Warn("cannot store provenance")
+	}
+}
+
+// GetJobProvenance returns the signed provenance attestation of a successful job
+func (srv *Service) GetJobProvenance(ctx context.Context, req *v1.GetJobProvenanceRequest) (*v1.GetJobProvenanceResponse, error) {
+	if srv.provenanceKey == nil {
+		return nil, status.Error(codes.Unimplemented, "this werft installation does not produce provenance attestations")
+	}
+
+	env, err := srv.Attestations.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "job %s has no provenance - only successful jobs do", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	key, err := provenance.MarshalPublicKey(srv.provenanceKey.Public())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &v1.GetJobProvenanceResponse{
+		Envelope:  env,
+		PublicKey: key,
+	}, nil
+}
+
+// HandleProvenance serves the provenance attestation of a job, e.g. for download by supply-chain tooling.
+// The job name is the last segment of the path.
+func (srv *Service) HandleProvenance(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	resp, err := srv.GetJobProvenance(r.Context(), &v1.GetJobProvenanceRequest{Name: name})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, status.Convert(err).Message(), http.StatusNotFound)
+		case codes.Unimplemented:
+			http.Error(w, status.Convert(err).Message(), http.StatusNotImplemented)
+		default:
+			http.Error(w, status.Convert(err).Message(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp.Envelope)
+}
@@ -0,0 +1,228 @@
+package werft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig configures how frequently jobs can be started
+type RateLimitConfig struct {
+	// JobsPerMinute is the number of jobs which can be started per repository and per caller each minute.
+	// Callers are told apart by the token they present, or by their address if they present none.
+	// Jobs werft starts itself, e.g. for webhooks or retries, are not limited.
+	// Zero disables rate limiting.
+	JobsPerMinute float64 `yaml:"jobsPerMinute,omitempty"`
+
+	// Burst is the number of jobs which can be started at once, exceeding JobsPerMinute. Defaults to 1.
+	Burst int `yaml:"burst,omitempty"`
+}
+
+// jobRateLimiter limits how frequently jobs can be started per repository and caller
+type jobRateLimiter struct {
+	Config RateLimitConfig
+
+	mu        sync.Mutex
+	limiters  map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+// limiterEntry is the limiter of a caller or repository and when it was last used
+type limiterEntry struct {
+	*rate.Limiter
+	LastUsed time.Time
+}
+
+// Allow returns nil if the caller may start a job on the repository now. If they may not, the error describes
+// which limit was exceeded. An empty caller is only limited per repository.
+func (l *jobRateLimiter) Allow(caller string, repo *v1.Repository) error {
+	return l.allowAt(time.Now(), caller, repo)
+}
+
+func (l *jobRateLimiter) allowAt(now time.Time, caller string, repo *v1.Repository) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Config.JobsPerMinute <= 0 {
+		return nil
+	}
+	var keys []string
+	if caller != "" {
+		keys = append(keys, caller)
+	}
+	if repo != nil {
+		keys = append(keys, fmt.Sprintf("repository %s/%s/%s", repo.Host, repo.Owner, repo.Repo))
+	}
+
+	burst := l.Config.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	if l.limiters == nil {
+		l.limiters = make(map[string]*limiterEntry)
+	}
+	l.evictIdle(now, burst)
+
+	var reservations []*rate.Reservation
+	for _, key := range keys {
+		lim, ok := l.limiters[key]
+		if !ok {
+			lim = &limiterEntry{Limiter: rate.NewLimiter(rate.Limit(l.Config.JobsPerMinute/60), burst)}
+			l.limiters[key] = lim
+		}
+		lim.LastUsed = now
+
+		r := lim.ReserveN(now, 1)
+		if !r.OK() || r.DelayFrom(now) > 0 {
+			// we must not consume the budget of the other limits if this one denies the job
+			r.CancelAt(now)
+			for _, pr := range reservations {
+				pr.CancelAt(now)
+			}
+			return fmt.Errorf("too many jobs started for %s - please try again later", key)
+		}
+		reservations = append(reservations, r)
+	}
+	return nil
+}
+
+// evictIdle removes the limiters which have not been used for long enough to refill their whole burst. Such a
+// limiter does not differ from a new one, and without eviction we would keep one for every address ever seen.
+// Callers must hold l.mu.
+func (l *jobRateLimiter) evictIdle(now time.Time, burst int) {
+	idle := time.Duration(float64(burst) / l.Config.JobsPerMinute * float64(time.Minute))
+	if now.Sub(l.lastSweep) < idle {
+		return
+	}
+	l.lastSweep = now
+
+	for key, lim := range l.limiters {
+		if now.Sub(lim.LastUsed) >= idle {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// SetConfig changes the rate limits. If they differ from the current ones, everyone starts with a full budget.
+func (l *jobRateLimiter) SetConfig(cfg RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Config == cfg {
+		return
+	}
+	l.Config = cfg
+	l.limiters = nil
+}
+
+// deliveryDeduplicator remembers recently seen webhook delivery IDs
+type deliveryDeduplicator struct {
+	TTL time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Seen returns true if the delivery ID has been seen within the TTL, and records it otherwise.
+func (d *deliveryDeduplicator) Seen(id string) bool {
+	return d.seenAt(time.Now(), id)
+}
+
+func (d *deliveryDeduplicator) seenAt(now time.Time, id string) bool {
+	if id == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen == nil {
+		d.seen = make(map[string]time.Time)
+	}
+	for k, t := range d.seen {
+		if now.Sub(t) > d.TTL {
+			delete(d.seen, k)
+		}
+	}
+
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	d.seen[id] = now
+	return false
+}
+
+// Forget removes a delivery ID, e.g. because processing the delivery failed
+func (d *deliveryDeduplicator) Forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, id)
+}
+
+// idempotencyKeys remembers which job was started for an idempotency key
+type idempotencyKeys struct {
+	TTL time.Duration
+
+	mu   sync.Mutex
+	keys map[string]*idempotentStart
+}
+
+type idempotentStart struct {
+	// Job is the name of the job started for the key, or empty while the job is starting
+	Job     string
+	Created time.Time
+}
+
+// Begin reserves a key for a job that's about to start. If a job was started with the key within the TTL, Begin
+// returns its name and the caller must not start another one. Callers have to call Done once the job started.
+func (k *idempotencyKeys) Begin(key string) (existing string, err error) {
+	return k.beginAt(time.Now(), key)
+}
+
+func (k *idempotencyKeys) beginAt(now time.Time, key string) (existing string, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.keys == nil {
+		k.keys = make(map[string]*idempotentStart)
+	}
+	for id, s := range k.keys {
+		if s.Job != "" && now.Sub(s.Created) > k.TTL {
+			delete(k.keys, id)
+		}
+	}
+
+	s, ok := k.keys[key]
+	if !ok {
+		k.keys[key] = &idempotentStart{Created: now}
+		return "", nil
+	}
+	if s.Job == "" {
+		return "", fmt.Errorf("a job with this idempotency key is currently starting - please try again later")
+	}
+	return s.Job, nil
+}
+
+// Done records the job started for a key. If job is empty, e.g. because starting the job failed, the key is released.
+func (k *idempotencyKeys) Done(key, job string) {
+	k.doneAt(time.Now(), key, job)
+}
+
+func (k *idempotencyKeys) doneAt(now time.Time, key, job string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if job == "" {
+		delete(k.keys, key)
+		return
+	}
+	k.keys[key] = &idempotentStart{Job: job, Created: now}
+}
@@ -0,0 +1,403 @@
+package werft_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestJobRateLimiter(t *testing.T) {
+	var (
+		t0    = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		repo  = testRepo()
+		other = &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "other"}
+	)
+	type start struct {
+		Offset  time.Duration
+		Caller  string
+		Repo    *v1.Repository
+		Allowed bool
+	}
+	tests := []struct {
+		Name   string
+		Config werft.RateLimitConfig
+		Starts []start
+	}{
+		{
+			Name:   "disabled",
+			Config: werft.RateLimitConfig{},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, true},
+			},
+		},
+		{
+			Name:   "burst defaults to one",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, false},
+			},
+		},
+		{
+			Name:   "burst",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1, Burst: 3},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, true},
+				{0, "token ci", repo, false},
+			},
+		},
+		{
+			Name:   "window",
+			Config: werft.RateLimitConfig{JobsPerMinute: 2},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{10 * time.Second, "token ci", repo, false},
+				{30 * time.Second, "token ci", repo, true},
+				{50 * time.Second, "token ci", repo, false},
+				{2 * time.Minute, "token ci", repo, true},
+			},
+		},
+		{
+			Name:   "per caller",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token ci", other, false},
+				{0, "address 10.0.0.1", other, true},
+				{0, "address 10.0.0.2", other, false},
+			},
+		},
+		{
+			Name:   "per repository",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token bot", repo, false},
+				{0, "", repo, false},
+				{0, "", other, true},
+			},
+		},
+		{
+			Name:   "denied jobs consume no budget",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1},
+			Starts: []start{
+				{0, "token ci", repo, true},
+				{0, "token bot", repo, false},
+				{0, "token bot", other, true},
+			},
+		},
+		{
+			Name:   "without repository",
+			Config: werft.RateLimitConfig{JobsPerMinute: 1},
+			Starts: []start{
+				{0, "token ci", nil, true},
+				{0, "token ci", nil, false},
+				{0, "", nil, true},
+				{0, "", nil, true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			lim := &werft.JobRateLimiter{Config: test.Config}
+			for i, s := range test.Starts {
+				err := lim.AllowAt(t0.Add(s.Offset), s.Caller, s.Repo)
+				if allowed := err == nil; allowed != s.Allowed {
+					t.Errorf("start %d: expected allowed %v, actual error %v", i, s.Allowed, err)
+				}
+			}
+		})
+	}
+}
+
+func TestJobRateLimiterSetConfig(t *testing.T) {
+	now := time.Now()
+	lim := &werft.JobRateLimiter{Config: werft.RateLimitConfig{JobsPerMinute: 1}}
+	if err := lim.AllowAt(now, "token ci", nil); err != nil {
+		t.Fatalf("first start was denied: %v", err)
+	}
+
+	lim.SetConfig(werft.RateLimitConfig{JobsPerMinute: 1})
+	if err := lim.AllowAt(now, "token ci", nil); err == nil {
+		t.Errorf("setting the same config reset the budget")
+	}
+
+	lim.SetConfig(werft.RateLimitConfig{JobsPerMinute: 2})
+	if err := lim.AllowAt(now, "token ci", nil); err != nil {
+		t.Errorf("changing the config did not reset the budget: %v", err)
+	}
+}
+
+func TestJobRateLimiterEvictsIdle(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}
+	lim := &werft.JobRateLimiter{Config: werft.RateLimitConfig{JobsPerMinute: 1, Burst: 2}}
+
+	if err := lim.AllowAt(t0, "address 10.0.0.1", repo); err != nil {
+		t.Fatalf("first start was denied: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := lim.AllowAt(t0.Add(1*time.Minute), "address 10.0.0.2", nil); err != nil {
+			t.Fatalf("start %d of the second caller was denied: %v", i, err)
+		}
+	}
+	if n := lim.Len(); n != 3 {
+		t.Fatalf("expected 3 limiters, got %d", n)
+	}
+
+	// after two minutes the limiters of the first start have refilled their burst, but that of the second caller has not
+	if err := lim.AllowAt(t0.Add(2*time.Minute), "address 10.0.0.3", nil); err != nil {
+		t.Fatalf("third start was denied: %v", err)
+	}
+	if n := lim.Len(); n != 2 {
+		t.Errorf("expected idle limiters to be evicted, got %d limiters", n)
+	}
+	if err := lim.AllowAt(t0.Add(2*time.Minute), "address 10.0.0.2", nil); err != nil {
+		t.Errorf("start of busy caller was denied: %v", err)
+	}
+	if err := lim.AllowAt(t0.Add(2*time.Minute), "address 10.0.0.2", nil); err == nil {
+		t.Errorf("evicting idle limiters reset the budget of a busy caller")
+	}
+}
+
+func TestRateLimitCaller(t *testing.T) {
+	withPeer := func(ctx context.Context, addr net.Addr) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	tcp := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4242}
+
+	tests := []struct {
+		Name        string
+		Ctx         context.Context
+		Expectation string
+	}{
+		{"token", bearer("ci-secret"), "token ci"},
+		{"token wins over address", withPeer(bearer("ci-secret"), tcp), "token ci"},
+		{"anonymous", withPeer(context.Background(), tcp), "address 10.0.0.1"},
+		{"invalid token", withPeer(bearer("wrong"), tcp), "address 10.0.0.1"},
+		{"unnamed unix socket", withPeer(context.Background(), &net.UnixAddr{Net: "unix"}), ""},
+		{"no request", context.Background(), ""},
+	}
+
+	srv := testService(werft.AnonymousFull)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := srv.RateLimitCaller(test.Ctx)
+			if act != test.Expectation {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestDeliveryDeduplicator(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	type delivery struct {
+		Offset time.Duration
+		ID     string
+		Forget bool
+		Seen   bool
+	}
+	tests := []struct {
+		Name       string
+		Deliveries []delivery
+	}{
+		{
+			Name: "redelivery",
+			Deliveries: []delivery{
+				{Offset: 0, ID: "a"},
+				{Offset: 30 * time.Minute, ID: "a", Seen: true},
+				{Offset: 30 * time.Minute, ID: "b"},
+			},
+		},
+		{
+			Name: "expired",
+			Deliveries: []delivery{
+				{Offset: 0, ID: "a"},
+				{Offset: 2 * time.Hour, ID: "a"},
+				{Offset: 2*time.Hour + time.Minute, ID: "a", Seen: true},
+			},
+		},
+		{
+			Name: "forgotten",
+			Deliveries: []delivery{
+				{Offset: 0, ID: "a"},
+				{Offset: 0, ID: "a", Forget: true},
+				{Offset: time.Minute, ID: "a"},
+			},
+		},
+		{
+			Name: "without ID",
+			Deliveries: []delivery{
+				{Offset: 0, ID: ""},
+				{Offset: 0, ID: ""},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			d := &werft.DeliveryDeduplicator{TTL: time.Hour}
+			for i, dl := range test.Deliveries {
+				if dl.Forget {
+					d.Forget(dl.ID)
+					continue
+				}
+				if seen := d.SeenAt(t0.Add(dl.Offset), dl.ID); seen != dl.Seen {
+					t.Errorf("delivery %d: expected seen %v, actual %v", i, dl.Seen, seen)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	type step struct {
+		Offset time.Duration
+		Key    string
+		// Done finishes the start of the key with Job instead of beginning one
+		Done bool
+		Job  string
+
+		Existing string
+		Error    bool
+	}
+	tests := []struct {
+		Name  string
+		Steps []step
+	}{
+		{
+			Name: "started before",
+			Steps: []step{
+				{Offset: 0, Key: "a"},
+				{Offset: 0, Key: "a", Done: true, Job: "werft-build-main.1"},
+				{Offset: 30 * time.Minute, Key: "a", Existing: "werft-build-main.1"},
+				{Offset: 30 * time.Minute, Key: "b"},
+			},
+		},
+		{
+			Name: "currently starting",
+			Steps: []step{
+				{Offset: 0, Key: "a"},
+				{Offset: time.Second, Key: "a", Error: true},
+			},
+		},
+		{
+			Name: "failed start releases the key",
+			Steps: []step{
+				{Offset: 0, Key: "a"},
+				{Offset: 0, Key: "a", Done: true},
+				{Offset: time.Second, Key: "a"},
+			},
+		},
+		{
+			Name: "expired",
+			Steps: []step{
+				{Offset: 0, Key: "a"},
+				{Offset: 0, Key: "a", Done: true, Job: "werft-build-main.1"},
+				{Offset: 2 * time.Hour, Key: "a"},
+			},
+		},
+		{
+			// the TTL counts from when the job started, not from when its start began
+			Name: "TTL counts from the start",
+			Steps: []step{
+				{Offset: 0, Key: "a"},
+				{Offset: 30 * time.Minute, Key: "a", Done: true, Job: "werft-build-main.1"},
+				{Offset: time.Hour + time.Minute, Key: "a", Existing: "werft-build-main.1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k := &werft.IdempotencyKeys{TTL: time.Hour}
+			for i, s := range test.Steps {
+				if s.Done {
+					k.DoneAt(t0.Add(s.Offset), s.Key, s.Job)
+					continue
+				}
+				existing, err := k.BeginAt(t0.Add(s.Offset), s.Key)
+				if (err != nil) != s.Error {
+					t.Errorf("step %d: expected error %v, actual %v", i, s.Error, err)
+				}
+				if existing != s.Existing {
+					t.Errorf("step %d: expected existing job %q, actual %q", i, s.Existing, existing)
+				}
+			}
+		})
+	}
+}
+
+func TestStartIdempotent(t *testing.T) {
+	tests := []struct {
+		Name string
+		// Starts are the results of the start functions of consecutive calls with the same key. An empty name
+		// fails the start.
+		Starts []string
+		// Expectation are the jobs the calls return, and Started how often a start function was called
+		Expectation []string
+		Started     int
+	}{
+		{
+			Name:        "started once",
+			Starts:      []string{"werft-build-main.1", "werft-build-main.2"},
+			Expectation: []string{"werft-build-main.1", "werft-build-main.1"},
+			Started:     1,
+		},
+		{
+			Name:        "retry after failure",
+			Starts:      []string{"", "werft-build-main.2", "werft-build-main.3"},
+			Expectation: []string{"", "werft-build-main.2", "werft-build-main.2"},
+			Started:     2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := testService(werft.AnonymousFull)
+			srv.Jobs = store.NewInMemoryJobStore()
+			srv.SetIdempotencyKeyTTL(time.Hour)
+
+			var started int
+			for i, name := range test.Starts {
+				resp, err := srv.StartIdempotent(context.Background(), "github/key", func() (*v1.StartJobResponse, error) {
+					started++
+					if name == "" {
+						return nil, status.Error(codes.Internal, "cannot start job")
+					}
+					job := v1.JobStatus{Name: name}
+					if err := srv.Jobs.Store(context.Background(), job); err != nil {
+						return nil, err
+					}
+					return &v1.StartJobResponse{Status: &job}, nil
+				})
+
+				var act string
+				if err == nil {
+					act = resp.Status.Name
+				}
+				if act != test.Expectation[i] {
+					t.Errorf("call %d: expected job %q, actual %q (%v)", i, test.Expectation[i], act, err)
+				}
+			}
+			if started != test.Started {
+				t.Errorf("expected %d starts, actual %d", test.Started, started)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package werft
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// registrySecretPrefix is prepended to the name of the secrets werft manages for the registry credentials of a repository
+	registrySecretPrefix = "werft-registry-"
+
+	// labelRegistrySecret marks the secrets werft manages for the registry credentials of a repository
+	labelRegistrySecret = "werft.sh/registrySecret"
+
+	// annotationRegistryRepository names the repository a registry secret belongs to
+	annotationRegistryRepository = "werft.sh/repository"
+
+	// registriesVolume is the name of the volume which holds the registry credentials of the repository
+	registriesVolume = "werft-registries"
+)
+
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// registrySecretName returns the name of the secret werft manages for the registry credentials of a repository
+func registrySecretName(owner, repo string) string {
+	name := invalidSecretNameChars.ReplaceAllString(strings.ToLower(owner+"-"+repo), "-")
+	name = registrySecretPrefix + strings.Trim(name, "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return name
+}
+
+// dockerConfig produces the Docker config which grants access to the registries
+func dockerConfig(creds []*v1.RegistryCredential) ([]byte, error) {
+	type auth struct {
+		Username string `json:"username,omitempty"`
+		Password string `json:"password,omitempty"`
+		Auth     string `json:"auth"`
+	}
+	auths := make(map[string]auth, len(creds))
+	for _, c := range creds {
+		auths[c.Registry] = auth{
+			Username: c.Username,
+			Password: c.Password,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password)),
+		}
+	}
+	return json.Marshal(map[string]interface{}{"auths": auths})
+}
+
+// ensureRegistrySecret creates or updates the secret which holds the registry credentials of a repository
+// in the namespace jobs run in, and returns its name
+func (srv *Service) ensureRegistrySecret(settings *v1.RepositorySettings) (string, error) {
+	cfg, err := dockerConfig(settings.Registries)
+	if err != nil {
+		return "", err
+	}
+
+	name := registrySecretName(settings.Owner, settings.Repo)
+	client := srv.Executor.Client.CoreV1().Secrets(srv.Executor.Config.Namespace)
+	existing, err := client.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{labelRegistrySecret: "true"},
+				Annotations: map[string]string{annotationRegistryRepository: settings.Owner + "/" + settings.Repo},
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: cfg},
+		})
+		if err != nil {
+			return "", xerrors.Errorf("cannot create registry secret: %w", err)
+		}
+		return name, nil
+	}
+	if err != nil {
+		return "", xerrors.Errorf("cannot get registry secret: %w", err)
+	}
+	if existing.Labels[labelRegistrySecret] != "true" {
+		return "", xerrors.Errorf("secret %s exists but is not managed by werft", name)
+	}
+	if bytes.Equal(existing.Data[corev1.DockerConfigJsonKey], cfg) {
+		return name, nil
+	}
+
+	existing.Data = map[string][]byte{corev1.DockerConfigJsonKey: cfg}
+	_, err = client.Update(existing)
+	if err != nil {
+		return "", xerrors.Errorf("cannot update registry secret: %w", err)
+	}
+	return name, nil
+}
+
+// bindRegistries makes the registry credentials of a repository available to a job. The pod pulls its images using them,
+// and all containers find them as Docker config, s.t. they can push images. Containers which bring their own Docker config keep it.
+func bindRegistries(podspec *corev1.PodSpec, secret string) {
+	podspec.ImagePullSecrets = append(podspec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+		Name: registriesVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secret,
+				Items: []corev1.KeyToPath{
+					{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+				},
+			},
+		},
+	})
+
+	for ci, c := range podspec.Containers {
+		if hasEnv(c, "DOCKER_CONFIG") {
+			continue
+		}
+		podspec.Containers[ci].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      registriesVolume,
+			ReadOnly:  true,
+			MountPath: registryConfigPath,
+		})
+		podspec.Containers[ci].Env = append(c.Env, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: registryConfigPath})
+	}
+}
+
+// hasEnv returns true if the container sets the environment variable
+func hasEnv(c corev1.Container, name string) bool {
+	for _, e := range c.Env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
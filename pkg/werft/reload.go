@@ -0,0 +1,168 @@
+package werft
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/32leaves/werft/pkg/provenance"
+	"golang.org/x/xerrors"
+)
+
+// Validate checks the config for mistakes which would otherwise only show once the server runs.
+// The error names the offending field, e.g. tokens[1].name.
+func (c Config) Validate() error {
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return xerrors.Errorf("baseURL: \"%s\" is not an absolute URL, e.g. https://werft.example.com", c.BaseURL)
+		}
+	}
+
+	if err := c.DevProxy.Validate(); err != nil {
+		return xerrors.Errorf("devProxy.%s", err.Error())
+	}
+
+	if c.RateLimit.JobsPerMinute < 0 {
+		return xerrors.Errorf("rateLimit.jobsPerMinute: must not be negative")
+	}
+	if c.RateLimit.Burst < 0 {
+		return xerrors.Errorf("rateLimit.burst: must not be negative")
+	}
+
+	if c.RequestLog.SampleRate < 0 || c.RequestLog.SampleRate > 1 {
+		return xerrors.Errorf("requestLog.sampleRate: must be between 0 and 1")
+	}
+	if s := c.RequestLog.SlowCall; s != "" {
+		if d, err := time.ParseDuration(s); err != nil || d <= 0 {
+			return xerrors.Errorf("requestLog.slowCall: \"%s\" is not a positive duration, e.g. 5s", s)
+		}
+	}
+
+	names := make(map[string]struct{}, len(c.Tokens))
+	for i, t := range c.Tokens {
+		if t.Name == "" {
+			return xerrors.Errorf("tokens[%d].name: must not be empty", i)
+		}
+		if _, exists := names[t.Name]; exists {
+			return xerrors.Errorf("tokens[%d].name: there is another token named \"%s\"", i, t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if t.Token == "" {
+			return xerrors.Errorf("tokens[%d].token: must not be empty", i)
+		}
+		for j, s := range t.Scopes {
+			if err := ValidateScope(s); err != nil {
+				return xerrors.Errorf("tokens[%d].scopes[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	switch c.AnonymousAccess {
+	case "", AnonymousFull, AnonymousReadOnly:
+	default:
+		return xerrors.Errorf("anonymousAccess: unknown access \"%s\" - must be %s or %s", c.AnonymousAccess, AnonymousFull, AnonymousReadOnly)
+	}
+
+	if c.Cost.CPUHour < 0 {
+		return xerrors.Errorf("cost.cpuHour: must not be negative")
+	}
+	if c.Cost.MemoryGBHour < 0 {
+		return xerrors.Errorf("cost.memoryGBHour: must not be negative")
+	}
+
+	for prio := range c.Priority.Classes {
+		if _, ok := priorityRank[prio]; !ok {
+			return xerrors.Errorf("priority.classes.%s: unknown priority - must be %s, %s or %s", prio, PriorityLow, PriorityNormal, PriorityHigh)
+		}
+	}
+
+	if c.Archive.AfterDays < 0 {
+		return xerrors.Errorf("archive.afterDays: must not be negative")
+	}
+
+	if c.WorkspaceGC.AfterHours < 0 {
+		return xerrors.Errorf("workspaceGC.afterHours: must not be negative")
+	}
+
+	if c.WebhookDeliveries.RetentionHours < 0 {
+		return xerrors.Errorf("webhookDeliveries.retentionHours: must not be negative")
+	}
+
+	switch c.JobNames {
+	case "", JobNamesDefault, JobNamesSequential:
+	default:
+		return xerrors.Errorf("jobNames: unknown naming scheme \"%s\" - must be %s or %s", c.JobNames, JobNamesDefault, JobNamesSequential)
+	}
+
+	if _, err := parseWorkspaceSizeLimit(c.Workspace); err != nil {
+		return xerrors.Errorf("workspace.sizeLimit: %w", err)
+	}
+
+	if fn := c.Provenance.SigningKeyPath; fn != "" {
+		if _, err := provenance.LoadSigningKey(fn); err != nil {
+			return xerrors.Errorf("provenance.signingKeyPath: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// config returns the current config of the service
+func (srv *Service) config() Config {
+	srv.configMu.RLock()
+	defer srv.configMu.RUnlock()
+
+	return srv.Config
+}
+
+// pluginHost returns the plugins of the service, or nil if there are none
+func (srv *Service) pluginHost() PluginHost {
+	srv.configMu.RLock()
+	defer srv.configMu.RUnlock()
+
+	return srv.Plugins
+}
+
+// SetPlugins replaces the plugins of a running service, e.g. once they were restarted with a new config
+func (srv *Service) SetPlugins(plugins PluginHost) {
+	srv.configMu.Lock()
+	defer srv.configMu.Unlock()
+
+	srv.Plugins = plugins
+}
+
+// ReloadConfig applies a new config to the running service. Most of the config takes effect right away, e.g. notifications,
+// alerts, priorities, rate limits and tokens. The parts which only take effect after a restart keep their current value -
+// ReloadConfig returns their names s.t. the caller can point them out. Invalid configs are rejected as a whole.
+func (srv *Service) ReloadConfig(cfg Config) (needRestart []string, err error) {
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	srv.configMu.Lock()
+	defer srv.configMu.Unlock()
+
+	cur := srv.Config
+	if cfg.WorkspaceNodePathPrefix != cur.WorkspaceNodePathPrefix {
+		// the cleanup of running jobs depends on the prefix they were started with
+		needRestart = append(needRestart, "workspaceNodePathPrefix")
+		cfg.WorkspaceNodePathPrefix = cur.WorkspaceNodePathPrefix
+	}
+	if cfg.Workspace != cur.Workspace {
+		needRestart = append(needRestart, "workspace")
+		cfg.Workspace = cur.Workspace
+	}
+	if cfg.Provenance.SigningKeyPath != cur.Provenance.SigningKeyPath {
+		needRestart = append(needRestart, "provenance.signingKeyPath")
+		cfg.Provenance.SigningKeyPath = cur.Provenance.SigningKeyPath
+	}
+	// the dev proxy can be set on the command line and is set up once when the server starts
+	cfg.DevProxy = cur.DevProxy
+
+	srv.Config = cfg
+	if srv.jobLimiter != nil {
+		srv.jobLimiter.SetConfig(cfg.RateLimit)
+	}
+	return needRestart, nil
+}
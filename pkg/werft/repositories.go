@@ -0,0 +1,144 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ListRepositories lists the settings of all registered repositories without revealing their webhook secret or registry passwords
+func (srv *Service) ListRepositories(ctx context.Context, req *v1.ListRepositoriesRequest) (*v1.ListRepositoriesResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	repos, err := srv.Repositories.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := make([]*v1.RepositorySettings, len(repos))
+	for i, r := range repos {
+		r = proto.Clone(r).(*v1.RepositorySettings)
+		if r.WebhookSecret != "" {
+			r.WebhookSecret = "<redacted>"
+		}
+		for _, c := range r.Registries {
+			c.Password = "<redacted>"
+		}
+		res[i] = r
+	}
+	return &v1.ListRepositoriesResponse{Repositories: res}, nil
+}
+
+// repositorySettings returns the settings of the repository a job runs on, or nil if the repository is not registered
+func (srv *Service) repositorySettings(ctx context.Context, md *v1.JobMetadata) *v1.RepositorySettings {
+	if md == nil || md.Repository == nil {
+		return nil
+	}
+
+	res, err := srv.Repositories.Get(ctx, md.Repository.Owner, md.Repository.Repo)
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		log.WithError(err).WithField("repo", md.Repository).Warn("cannot get repository settings")
+		return nil
+	}
+	return res
+}
+
+// checkRepositoryPolicy returns an error if the policy of the job's repository does not allow it to run
+func (srv *Service) checkRepositoryPolicy(ctx context.Context, md *v1.JobMetadata) error {
+	settings := srv.repositorySettings(ctx, md)
+	if settings == nil || settings.Policy == nil {
+		return nil
+	}
+	policy := settings.Policy
+
+	if len(policy.AllowedTriggers) > 0 {
+		var allowed bool
+		for _, t := range policy.AllowedTriggers {
+			if t == md.Trigger {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return status.Errorf(codes.PermissionDenied, "%s/%s does not allow jobs triggered by %s", settings.Owner, settings.Repo, strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")))
+		}
+	}
+	if len(policy.AllowedRefs) > 0 {
+		var allowed bool
+		for _, p := range policy.AllowedRefs {
+			if ok, _ := path.Match(p, md.Repository.Ref); ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return status.Errorf(codes.PermissionDenied, "%s/%s does not allow jobs on %s", settings.Owner, settings.Repo, md.Repository.Ref)
+		}
+	}
+	return nil
+}
+
+// bindSecrets makes the secrets bound to a repository available to all containers of a job
+func bindSecrets(podspec *corev1.PodSpec, settings *v1.RepositorySettings) {
+	for i, b := range settings.Secrets {
+		if b.MountPath == "" {
+			for ci, c := range podspec.Containers {
+				podspec.Containers[ci].EnvFrom = append(c.EnvFrom, corev1.EnvFromSource{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: b.Secret}},
+				})
+			}
+			continue
+		}
+
+		volume := fmt.Sprintf("werft-secret-%d", i)
+		podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+			Name: volume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: b.Secret},
+			},
+		})
+		for ci, c := range podspec.Containers {
+			podspec.Containers[ci].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				Name:      volume,
+				ReadOnly:  true,
+				MountPath: b.MountPath,
+			})
+		}
+	}
+}
+
+// repositoryWebhookSecret returns the webhook secret of the repository a GitHub webhook payload is about
+func (srv *Service) repositoryWebhookSecret(ctx context.Context, payload []byte) (secret []byte, ok bool) {
+	var body struct {
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	err := json.Unmarshal(payload, &body)
+	if err != nil || body.Repository.Name == "" {
+		return nil, false
+	}
+
+	settings, err := srv.Repositories.Get(ctx, body.Repository.Owner.Login, body.Repository.Name)
+	if err != nil || settings.WebhookSecret == "" {
+		return nil, false
+	}
+	return []byte(settings.WebhookSecret), true
+}
@@ -0,0 +1,79 @@
+package werft
+
+import (
+	"context"
+	"time"
+
+	"github.com/32leaves/werft/pkg/analytics"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultRepoStatsWindow is the time window GetRepoStats considers if the request does not say otherwise
+const defaultRepoStatsWindow = 30 * 24 * time.Hour
+
+// recordStats adds a finished job to the statistics of its repository and ref
+func (srv *Service) recordStats(name string) {
+	ctx := context.Background()
+	job, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot record job statistics")
+		return
+	}
+	counters := analytics.StatsCounters(job)
+	if len(counters) == 0 || job.Metadata.Repository == nil {
+		return
+	}
+	finished, err := ptypes.Timestamp(job.Metadata.Finished)
+	if err != nil {
+		finished = time.Now()
+	}
+
+	repo := job.Metadata.Repository
+	err = srv.Stats.Add(ctx, repo.Owner, repo.Repo, repo.Ref, finished, counters)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot record job statistics")
+	}
+}
+
+// GetRepoStats returns the success rate, duration and queue latency percentiles and failure causes of each ref of
+// a repository, or all repositories of an owner, over a time window
+func (srv *Service) GetRepoStats(ctx context.Context, req *v1.GetRepoStatsRequest) (*v1.GetRepoStatsResponse, error) {
+	if req.RepoOwner == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository owner is required")
+	}
+
+	until := time.Now()
+	if req.Until != nil {
+		t, err := ptypes.Timestamp(req.Until)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		until = t
+	}
+	since := until.Add(-defaultRepoStatsWindow)
+	if req.Since != nil {
+		t, err := ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		since = t
+	}
+	if since.After(until) {
+		return nil, status.Error(codes.InvalidArgument, "since must not be after until")
+	}
+
+	sums, err := srv.Stats.Sum(ctx, req.RepoOwner, req.RepoRepo, req.Ref, since, until)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &v1.GetRepoStatsResponse{}
+	for _, sum := range sums {
+		res.Stats = append(res.Stats, analytics.ComputeRepoStats(sum.Owner+"/"+sum.Repo, sum.Ref, sum.Counters))
+	}
+	return res, nil
+}
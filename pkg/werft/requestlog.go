@@ -0,0 +1,154 @@
+package werft
+
+import (
+	"context"
+	"expvar"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slowCalls counts the slow API calls per method. The server exposes them at /debug/vars.
+var slowCalls = expvar.NewMap("slowCalls")
+
+// RequestLogConfig configures the logging of API calls
+type RequestLogConfig struct {
+	// SampleRate is the fraction of successful calls we log, between 0 and 1. Failed and slow calls are always logged.
+	SampleRate float64 `yaml:"sampleRate,omitempty"`
+
+	// SlowCall is how long a unary call may take before we log a warning, e.g. 5s. Streaming calls are never slow
+	// as they last as long as the client listens.
+	SlowCall string `yaml:"slowCall,omitempty"`
+}
+
+// slowCallThreshold returns the duration after which a call is slow, or zero if there is none
+func (c RequestLogConfig) slowCallThreshold() time.Duration {
+	if c.SlowCall == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(c.SlowCall)
+	return d
+}
+
+// LogUnaryCall is a gRPC interceptor which logs the method, user, duration and result of unary API calls
+func (srv *Service) LogUnaryCall(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx = withCallIdentity(ctx)
+	resp, err := handler(ctx, req)
+
+	cfg := srv.config().RequestLog
+	duration := time.Since(start)
+	slow := cfg.slowCallThreshold()
+	if slow > 0 && duration >= slow {
+		slowCalls.Add(info.FullMethod, 1)
+		srv.callLog(ctx, info.FullMethod, duration, err).WithField("threshold", slow.String()).Warn("slow API call")
+		return resp, err
+	}
+	if shouldLogCall(cfg, err) {
+		srv.callLog(ctx, info.FullMethod, duration, err).Info("API call")
+	}
+	return resp, err
+}
+
+// LogStreamCall is a gRPC interceptor which logs the method, user, duration and result of streaming API calls
+func (srv *Service) LogStreamCall(s interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ss = &identityServerStream{ServerStream: ss, ctx: withCallIdentity(ss.Context())}
+	err := handler(s, ss)
+
+	if shouldLogCall(srv.config().RequestLog, err) {
+		srv.callLog(ss.Context(), info.FullMethod, time.Since(start), err).Info("API call")
+	}
+	return err
+}
+
+// shouldLogCall decides if a call which is neither slow nor a panic makes it into the log
+func shouldLogCall(cfg RequestLogConfig, err error) bool {
+	if err != nil && status.Code(err) != codes.Canceled {
+		return true
+	}
+	return cfg.SampleRate > 0 && rand.Float64() < cfg.SampleRate
+}
+
+// callIdentityKey is the context key of the identity a call authenticated as
+type callIdentityKey struct{}
+
+// callIdentity remembers who a call authenticated as, s.t. the request log need not authenticate it again
+type callIdentity struct {
+	mu       sync.Mutex
+	resolved bool
+	user     string
+}
+
+// withCallIdentity prepares a call's context to remember who the call authenticated as
+func withCallIdentity(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callIdentityKey{}, &callIdentity{})
+}
+
+// recordCallIdentity remembers the first outcome of authenticating a call, if the call is logged
+func recordCallIdentity(ctx context.Context, tkn *TokenConfig, err error) {
+	id, ok := ctx.Value(callIdentityKey{}).(*callIdentity)
+	if !ok {
+		return
+	}
+
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	if id.resolved {
+		return
+	}
+	id.resolved = true
+	if err != nil {
+		id.user = "unauthenticated"
+	} else {
+		id.user = identity(tkn)
+	}
+}
+
+// get returns who the call authenticated as, and false if it never authenticated
+func (id *callIdentity) get() (user string, resolved bool) {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	return id.user, id.resolved
+}
+
+// identityServerStream replaces the context of a stream with one that remembers who the call authenticated as
+type identityServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream
+func (s *identityServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// callLog produces the log entry of an API call. We reuse the identity the call authenticated as. Only calls which
+// never authenticated, e.g. because they need no token, are authenticated once we know the call is logged, s.t.
+// sampled out calls do not pay for looking up service account tokens.
+func (srv *Service) callLog(ctx context.Context, method string, duration time.Duration, err error) *log.Entry {
+	id, ok := ctx.Value(callIdentityKey{}).(*callIdentity)
+	if !ok {
+		ctx = withCallIdentity(ctx)
+		id = ctx.Value(callIdentityKey{}).(*callIdentity)
+	}
+	user, resolved := id.get()
+	if !resolved {
+		_, _ = srv.authenticate(ctx)
+		user, _ = id.get()
+	}
+
+	entry := log.WithField("method", method).
+		WithField("user", user).
+		WithField("duration", duration.String()).
+		WithField("code", status.Code(err).String())
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	return entry
+}
@@ -0,0 +1,77 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+)
+
+func TestLogUnaryCallUser(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	srv.Config.RequestLog = werft.RequestLogConfig{SampleRate: 1}
+	srv.ServiceAccountTokens = store.NewInMemoryServiceAccountTokens()
+	sa, err := srv.CreateServiceAccountToken(bearer("admin-secret"), &v1.CreateServiceAccountTokenRequest{
+		ServiceAccount: "deploy-bot",
+		Scopes:         []string{string(werft.ScopeTrigger)},
+	})
+	if err != nil {
+		t.Fatalf("cannot create token: %v", err)
+	}
+
+	tests := []struct {
+		Name    string
+		Token   string
+		Handler grpc.UnaryHandler
+		User    string
+	}{
+		{Name: "anonymous", Handler: authenticatingHandler(srv), User: "anonymous"},
+		{Name: "config token", Token: "ci-secret", Handler: authenticatingHandler(srv), User: "ci"},
+		{Name: "invalid token", Token: "not-a-token", Handler: authenticatingHandler(srv), User: "unauthenticated"},
+		{
+			Name:  "handler without authentication",
+			Token: "ci-secret",
+			Handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+			User: "ci",
+		},
+		{
+			// the token no longer authenticates once the call revoked it, hence the log must reuse the identity
+			// the call authenticated as
+			Name:  "revoking own token",
+			Token: sa.Secret,
+			Handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.RevokeOwnToken(ctx, &v1.RevokeOwnTokenRequest{Id: sa.Token.Id})
+			},
+			User: "deploy-bot",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hook := logtest.NewGlobal()
+			defer hook.Reset()
+
+			srv.LogUnaryCall(bearer(test.Token), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, test.Handler)
+
+			entry := hook.LastEntry()
+			if entry == nil {
+				t.Fatal("call was not logged")
+			}
+			if user := entry.Data["user"]; user != test.User {
+				t.Errorf("unexpected user: expected %q, got %q", test.User, user)
+			}
+		})
+	}
+}
+
+// authenticatingHandler produces a handler which authenticates the call like most API calls do
+func authenticatingHandler(srv *werft.Service) grpc.UnaryHandler {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.AuthorizeWrite(ctx, testRepo())
+	}
+}
@@ -0,0 +1,229 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// resultChannelGitHub is the channel results are published to GitHub with if the repo does not configure it otherwise
+	resultChannelGitHub = "github"
+
+	// resultDeliveryTimeout is the time we give a result handler to deliver a single result
+	resultDeliveryTimeout = 10 * time.Second
+)
+
+// routeResult delivers a result to the handlers configured for its channels and sends result notifications. Handlers
+// which change the result itself, e.g. the UI section, are applied before this function returns - all others deliver
+// the result in the background.
+func (srv *Service) routeResult(ctx context.Context, job *v1.JobStatus, res *v1.JobResult) {
+	cfg := srv.jobRepoConfig(ctx, job)
+	go srv.notifyJobEvent(cfg, job, []string{repoconfig.NotifyResult}, res)
+	if len(res.Channels) == 0 {
+		return
+	}
+
+	for _, c := range res.Channels {
+		route, ok := cfg.Channels[c]
+		if !ok || route == nil {
+			continue
+		}
+
+		if route.UI != nil {
+			res.Section = route.UI.Section
+		}
+		if route.GitHub != nil {
+			go srv.deliverResult(job.Name, c, "github", func(ctx context.Context) error {
+				return srv.publishGitHubResult(ctx, job, res, route.GitHub.Context)
+			})
+		}
+		if route.Slack != nil {
+			go srv.deliverResult(job.Name, c, "slack", func(ctx context.Context) error {
+				return postSlackMessage(ctx, route.Slack, srv.resultMessage(job, res))
+			})
+		}
+		if route.Teams != nil {
+			go srv.deliverResult(job.Name, c, "teams", func(ctx context.Context) error {
+				return postTeamsMessage(ctx, route.Teams, srv.resultMessage(job, res))
+			})
+		}
+		if route.Chat != nil {
+			go srv.deliverResult(job.Name, c, "chat", func(ctx context.Context) error {
+				return postChatMessage(ctx, route.Chat, srv.resultMessage(job, res))
+			})
+		}
+		if route.Telegram != nil {
+			go srv.deliverResult(job.Name, c, "telegram", func(ctx context.Context) error {
+				return postTelegramMessage(ctx, route.Telegram, srv.resultMessage(job, res))
+			})
+		}
+		if route.Matrix != nil {
+			go srv.deliverResult(job.Name, c, "matrix", func(ctx context.Context) error {
+				return postMatrixMessage(ctx, route.Matrix, srv.resultMessage(job, res))
+			})
+		}
+		if route.Webhook != nil {
+			go srv.deliverResult(job.Name, c, "webhook", func(ctx context.Context) error {
+				return postResultWebhook(ctx, job, res, route.Webhook)
+			})
+		}
+	}
+}
+
+func (srv *Service) deliverResult(jobName, channel, handler string, deliver func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resultDeliveryTimeout)
+	defer cancel()
+
+	err := deliver(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"name": jobName, "channel": channel, "handler": handler}).Warn("cannot deliver job result")
+	}
+}
+
+// hasResult returns true if the job has the result already. We compare results regardless of the section
+// we might have added when routing them.
+func hasResult(job *v1.JobStatus, res *v1.JobResult) bool {
+	for _, r := range job.Results {
+		rc := proto.Clone(r).(*v1.JobResult)
+		rc.Section = res.Section
+		if proto.Equal(rc, res) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobRepoConfig returns the repo config, e.g. the result channels and notifications, of the repo a job was started from.
+// We download the repo config only once per job. Jobs which don't come from a GitHub repo have an empty config.
+func (srv *Service) jobRepoConfig(ctx context.Context, job *v1.JobStatus) *repoconfig.C {
+	srv.mu.RLock()
+	cfg, ok := srv.repoConfigs[job.Name]
+	srv.mu.RUnlock()
+	if ok {
+		return cfg
+	}
+
+	cfg = srv.downloadJobRepoConfig(ctx, job)
+
+	srv.mu.Lock()
+	srv.repoConfigs[job.Name] = cfg
+	srv.mu.Unlock()
+
+	return cfg
+}
+
+// downloadJobRepoConfig downloads the repo config of the revision a job runs on without caching it
+func (srv *Service) downloadJobRepoConfig(ctx context.Context, job *v1.JobStatus) *repoconfig.C {
+	cfg := &repoconfig.C{}
+	repo := job.Metadata.Repository
+	if srv.GitHub.Client != nil && repo != nil && repo.Owner != "" && repo.Revision != "" {
+		fp := &GitHubContentProvider{
+			Owner:    repo.Owner,
+			Repo:     repo.Repo,
+			Revision: repo.Revision,
+			Client:   srv.GitHub.Client,
+		}
+		c, err := getRepoCfg(ctx, fp)
+		if err != nil {
+			log.WithError(err).WithField("name", job.Name).Debug("cannot download repo config - not routing results or sending notifications")
+		} else {
+			cfg = c
+		}
+	}
+	return cfg
+}
+
+func (srv *Service) publishGitHubResult(ctx context.Context, job *v1.JobStatus, res *v1.JobResult, ghcontext string) error {
+	if srv.GitHub.Client == nil {
+		return xerrors.Errorf("GitHub is not configured")
+	}
+	repo := job.Metadata.Repository
+	if repo == nil || repo.Revision == "" {
+		return xerrors.Errorf("job does not come from a GitHub repo")
+	}
+
+	if ghcontext == "" {
+		ghcontext = fmt.Sprintf("%s/%s", werftResultGithubContext, res.Type)
+	}
+	targetURL := fmt.Sprintf("%s/job/%s", srv.config().BaseURL, job.Name)
+	if res.Type == "url" {
+		targetURL = res.Payload
+	}
+	success := "success"
+	_, _, err := srv.GitHub.Client.Repositories.CreateStatus(ctx, repo.Owner, repo.Repo, repo.Revision, &github.RepoStatus{
+		State:       &success,
+		TargetURL:   &targetURL,
+		Description: &res.Description,
+		Context:     &ghcontext,
+	})
+	return err
+}
+
+// resultMessage produces the chat message about a result
+func (srv *Service) resultMessage(job *v1.JobStatus, res *v1.JobResult) *chatMessage {
+	return srv.newChatMessage(job, fmt.Sprintf("produced a %s result: %s", res.Type, res.Payload), res.Description)
+}
+
+func postResultWebhook(ctx context.Context, job *v1.JobStatus, res *v1.JobResult, route *repoconfig.WebhookResultRoute) error {
+	msg := struct {
+		Job    string         `json:"job"`
+		Repo   *v1.Repository `json:"repository,omitempty"`
+		Result *v1.JobResult  `json:"result"`
+	}{
+		Job:    job.Name,
+		Repo:   job.Metadata.Repository,
+		Result: res,
+	}
+	return postJSON(ctx, route.URL, msg)
+}
+
+func postJSON(ctx context.Context, url string, msg interface{}) error {
+	if url == "" {
+		return xerrors.Errorf("no URL configured")
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return postBody(ctx, url, body)
+}
+
+// postBody posts a JSON body to a URL
+func postBody(ctx context.Context, url string, body []byte) error {
+	return sendBody(ctx, http.MethodPost, url, nil, body)
+}
+
+// sendBody sends a JSON body to a URL using the given method and additional headers
+func sendBody(ctx context.Context, method, url string, header http.Header, body []byte) error {
+	if url == "" {
+		return xerrors.Errorf("no URL configured")
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return xerrors.Errorf("%s responded with %s", url, resp.Status)
+	}
+	return nil
+}
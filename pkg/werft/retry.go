@@ -0,0 +1,111 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// annotationRetryOf is set on retries and names the job which failed first
+	annotationRetryOf = "retryOf"
+	// annotationRetriesPrefix prefixes the annotations which count the retries of a job per failure class, e.g. retries.infrastructure
+	annotationRetriesPrefix = "retries."
+)
+
+// retryJob starts a failed job again if its retry policy says so. Retries which wait for their delay
+// are lost when werft restarts.
+func (srv *Service) retryJob(failed *v1.JobStatus, policyJSON string) {
+	logger := log.WithField("name", failed.Name)
+
+	var policy repoconfig.RetryPolicy
+	err := json.Unmarshal([]byte(policyJSON), &policy)
+	if err != nil {
+		logger.WithError(err).Warn("cannot retry job")
+		return
+	}
+
+	class := failed.GetConditions().GetFailureClass()
+	rule, key, attempt := nextRetry(failed, &policy)
+	if rule == nil {
+		return
+	}
+	if attempt > rule.Attempts {
+		logger.WithField("class", class.String()).Info("job failed and has no retries left")
+		return
+	}
+	delay, err := rule.DelayDuration()
+	if err != nil {
+		logger.WithError(err).Warn("cannot retry job")
+		return
+	}
+	logger.WithField("class", class.String()).WithField("attempt", attempt).WithField("delay", delay.String()).Info("retrying failed job")
+	time.Sleep(delay)
+
+	origin := retryOrigin(failed)
+	resp, err := srv.replayJob(context.Background(), failed.Name, "", false, func(md *v1.JobMetadata) {
+		setAnnotation(md, annotationRetryOf, origin)
+		setAnnotation(md, key, fmt.Sprintf("%d", attempt))
+	})
+	if err != nil {
+		logger.WithError(err).Warn("cannot retry job")
+		return
+	}
+	if resp.Status == nil {
+		// the job was queued during maintenance
+		return
+	}
+	logger.WithField("retry", resp.Status.Name).Info("started retry of failed job")
+}
+
+// nextRetry returns the rule which retries a failed job, the annotation which counts its retries and the attempt
+// a retry would be. The rule is nil if the policy does not retry the failure of the job.
+func nextRetry(failed *v1.JobStatus, policy *repoconfig.RetryPolicy) (rule *repoconfig.RetryRule, key string, attempt int) {
+	class := failed.GetConditions().GetFailureClass()
+	rule = policy.Rule(class)
+	if rule == nil {
+		return nil, "", 0
+	}
+	key = annotationRetriesPrefix + strings.ToLower(strings.TrimPrefix(class.String(), "FAILURE_"))
+	return rule, key, retryCount(failed.Metadata, key) + 1
+}
+
+// retryOrigin returns the name of the job which failed first, s.t. retries of retries refer to the same job
+func retryOrigin(failed *v1.JobStatus) string {
+	for _, a := range failed.GetMetadata().GetAnnotations() {
+		if a.Key == annotationRetryOf && a.Value != "" {
+			return a.Value
+		}
+	}
+	return failed.Name
+}
+
+// retryCount returns how often a job was retried because of a class of failures
+func retryCount(md *v1.JobMetadata, key string) int {
+	for _, a := range md.GetAnnotations() {
+		if a.Key != key {
+			continue
+		}
+		n, _ := strconv.Atoi(a.Value)
+		return n
+	}
+	return 0
+}
+
+// setAnnotation sets or replaces an annotation of a job
+func setAnnotation(md *v1.JobMetadata, key, value string) {
+	for _, a := range md.Annotations {
+		if a.Key == key {
+			a.Value = value
+			return
+		}
+	}
+	md.Annotations = append(md.Annotations, &v1.Annotation{Key: key, Value: value})
+}
@@ -0,0 +1,95 @@
+package werft_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestNextRetry(t *testing.T) {
+	policy := &repoconfig.RetryPolicy{
+		Infrastructure: &repoconfig.RetryRule{Attempts: 2, Delay: "30s"},
+	}
+	failed := func(class v1.JobFailureClass, annotations ...*v1.Annotation) *v1.JobStatus {
+		return &v1.JobStatus{
+			Name:       "werft-build-main.2",
+			Metadata:   &v1.JobMetadata{Annotations: annotations},
+			Conditions: &v1.JobConditions{FailureClass: class},
+		}
+	}
+
+	tests := []struct {
+		Name    string
+		Policy  *repoconfig.RetryPolicy
+		Failed  *v1.JobStatus
+		Retried bool
+		Key     string
+		Attempt int
+	}{
+		{
+			Name:    "first retry",
+			Policy:  policy,
+			Failed:  failed(v1.JobFailureClass_FAILURE_INFRASTRUCTURE),
+			Retried: true,
+			Key:     "retries.infrastructure",
+			Attempt: 1,
+		},
+		{
+			Name:    "counts retries per class",
+			Policy:  policy,
+			Failed:  failed(v1.JobFailureClass_FAILURE_INFRASTRUCTURE, &v1.Annotation{Key: "retries.test", Value: "3"}, &v1.Annotation{Key: "retries.infrastructure", Value: "1"}),
+			Retried: true,
+			Key:     "retries.infrastructure",
+			Attempt: 2,
+		},
+		{
+			// whether there are retries left is up to the caller, which logs that there are none
+			Name:    "no retries left",
+			Policy:  policy,
+			Failed:  failed(v1.JobFailureClass_FAILURE_INFRASTRUCTURE, &v1.Annotation{Key: "retries.infrastructure", Value: "2"}),
+			Retried: true,
+			Key:     "retries.infrastructure",
+			Attempt: 3,
+		},
+		{Name: "class without rule", Policy: policy, Failed: failed(v1.JobFailureClass_FAILURE_TEST)},
+		{Name: "unclassified failure", Policy: policy, Failed: failed(v1.JobFailureClass_FAILURE_UNCLASSIFIED)},
+		{Name: "checkout failure", Policy: policy, Failed: failed(v1.JobFailureClass_FAILURE_CHECKOUT)},
+		{Name: "no policy", Failed: failed(v1.JobFailureClass_FAILURE_INFRASTRUCTURE)},
+		{Name: "no conditions", Policy: policy, Failed: &v1.JobStatus{Name: "werft-build-main.2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rule, key, attempt := werft.NextRetry(test.Failed, test.Policy)
+			if retried := rule != nil; retried != test.Retried {
+				t.Fatalf("expected retried %v, actual %v", test.Retried, retried)
+			}
+			if key != test.Key || attempt != test.Attempt {
+				t.Errorf("expected attempt %d counted by %q, actual %d counted by %q", test.Attempt, test.Key, attempt, key)
+			}
+		})
+	}
+}
+
+func TestRetryOrigin(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations []*v1.Annotation
+		Expectation string
+	}{
+		{"first failure", nil, "werft-build-main.2"},
+		{"retry", []*v1.Annotation{{Key: "retryOf", Value: "werft-build-main.1"}}, "werft-build-main.1"},
+		{"empty annotation", []*v1.Annotation{{Key: "retryOf"}}, "werft-build-main.2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := werft.RetryOrigin(&v1.JobStatus{Name: "werft-build-main.2", Metadata: &v1.JobMetadata{Annotations: test.Annotations}})
+			if act != test.Expectation {
+				t.Errorf("expected %q, actual %q", test.Expectation, act)
+			}
+		})
+	}
+}
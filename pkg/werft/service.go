@@ -6,22 +6,30 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/32leaves/werft/pkg/analytics"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
 	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/ghretry"
 	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/recovery"
 	"github.com/32leaves/werft/pkg/store"
 	termtohtml "github.com/buildkite/terminal-to-html"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/go-github/github"
+	"github.com/olebedev/emitter"
 	log "github.com/sirupsen/logrus"
 	"github.com/technosophos/moniker"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // StartLocalJob starts a job whoose content is uploaded
@@ -35,6 +43,18 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 	}
 	md := *req.GetMetadata()
 	log.WithField("name", md).Debug("StartLocalJob - received metadata")
+	if err := srv.authorizeStart(inc.Context(), &md); err != nil {
+		return err
+	}
+	if err := srv.checkRateLimit(inc.Context(), &md); err != nil {
+		return err
+	}
+	if err := srv.checkAcceptsJobs(inc.Context(), &md); err != nil {
+		return err
+	}
+	if err := srv.checkRepositoryPolicy(inc.Context(), &md); err != nil {
+		return err
+	}
 
 	dfs, err := ioutil.TempFile(os.TempDir(), "werft-lcp")
 	if err != nil {
@@ -135,6 +155,28 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 
 // StartGitHubJob starts a job on a Git context, possibly with a custom job.
 func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRequest) (resp *v1.StartJobResponse, err error) {
+	if req.Metadata == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	if err := srv.authorizeStart(ctx, req.Metadata); err != nil {
+		return nil, err
+	}
+	if err := srv.checkRateLimit(ctx, req.Metadata); err != nil {
+		return nil, err
+	}
+	return srv.startGitHubJob(ctx, req)
+}
+
+// startGitHubJob starts a job on a Git context without authorizing the request, e.g. for webhooks which are authorized by their secret
+func (srv *Service) startGitHubJob(ctx context.Context, req *v1.StartGitHubJobRequest) (resp *v1.StartJobResponse, err error) {
+	if req.IdempotencyKey != "" {
+		return srv.startIdempotent(ctx, "github/"+req.IdempotencyKey, func() (*v1.StartJobResponse, error) {
+			r := *req
+			r.IdempotencyKey = ""
+			return srv.startGitHubJob(ctx, &r)
+		})
+	}
+
 	var (
 		ghclient = srv.GitHub.Client
 		gitauth  = srv.GitHub.Auth
@@ -143,39 +185,60 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: req.GithubToken},
 		)
-		tc := oauth2.NewClient(ctx, ts)
-		ghclient = github.NewClient(tc)
+		ghclient = github.NewClient(&http.Client{Transport: &oauth2.Transport{
+			Source: ts,
+			Base:   ghretry.NewTransport(nil),
+		}})
 		gitauth = fixedOAuthTokenGitCreds(req.GithubToken)
 	}
 
 	md := req.Metadata
-	if md.Repository.Revision == "" && md.Repository.Ref != "" {
-		md.Repository.Revision, _, err = ghclient.Repositories.GetCommitSHA1(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Ref, "")
-		if err != nil {
-			return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
-		}
+	if err := srv.checkAcceptsJobs(ctx, md); err != nil {
+		return nil, err
 	}
-
-	_, _, err = ghclient.Repositories.GetCommit(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Revision)
-	if err != nil {
-		return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
+	if err := srv.checkRepositoryPolicy(ctx, md); err != nil {
+		return nil, err
 	}
 
-	var cp = &GitHubContentProvider{
-		Owner:    md.Repository.Owner,
-		Repo:     md.Repository.Repo,
-		Revision: md.Repository.Revision,
-		Client:   ghclient,
-		Auth:     gitauth,
+	var cp interface {
+		ContentProvider
+		FileProvider
 	}
+	if pcp, ok := srv.pluginContentProvider(md); ok {
+		// the plugin is responsible for resolving refs of the repositories it serves
+		if len(req.Sideload) > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "cannot sideload into jobs on %s", md.Repository.Host)
+		}
+		cp = pcp
+	} else {
+		if md.Repository.Revision == "" && md.Repository.Ref != "" {
+			md.Repository.Revision, _, err = ghclient.Repositories.GetCommitSHA1(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Ref, "")
+			if err != nil {
+				return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
+			}
+		}
 
-	if len(req.Sideload) > 0 {
-		cp.Sideload = &GitHubContentProviderSideload{
-			TarStream:  bytes.NewReader(req.Sideload),
-			Namespace:  srv.Executor.Config.Namespace,
-			Kubeconfig: srv.Executor.KubeConfig,
-			Clientset:  srv.Executor.Client,
+		_, _, err = ghclient.Repositories.GetCommit(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Revision)
+		if err != nil {
+			return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
 		}
+
+		gcp := &GitHubContentProvider{
+			Owner:    md.Repository.Owner,
+			Repo:     md.Repository.Repo,
+			Revision: md.Repository.Revision,
+			Client:   ghclient,
+			Auth:     gitauth,
+		}
+		if len(req.Sideload) > 0 {
+			gcp.Sideload = &GitHubContentProviderSideload{
+				TarStream:  bytes.NewReader(req.Sideload),
+				Namespace:  srv.Executor.Config.Namespace,
+				Kubeconfig: srv.Executor.KubeConfig,
+				Clientset:  srv.Executor.Client,
+			}
+		}
+		cp = gcp
 	}
 
 	var (
@@ -183,12 +246,26 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 		tplpath     = req.JobPath
 		jobSpecName = "custom"
 	)
+	repoCfg, err := getRepoCfg(ctx, cp)
+	if err != nil && jobYAML == nil && tplpath == "" {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err != nil {
+		// jobs which bring their own job spec can run on repos without werft config
+		log.WithError(err).WithField("repo", md.Repository).Debug("cannot read repo config - not checking annotations")
+		repoCfg = nil
+	}
+	err = applyAnnotationPolicy(md, repoCfg)
+	if err != nil {
+		return nil, err
+	}
+	err = checkUpstream(md, repoCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	if jobYAML == nil {
 		if tplpath == "" {
-			repoCfg, err := getRepoCfg(ctx, cp)
-			if err != nil {
-				return nil, status.Error(codes.Internal, err.Error())
-			}
 			tplpath = repoCfg.TemplatePath(req.Metadata)
 		}
 
@@ -204,29 +281,12 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 	}
 	if tplpath != "" {
 		jobSpecName = strings.TrimSuffix(filepath.Base(tplpath), filepath.Ext(tplpath))
+		md.JobSpec = jobSpecName
 	}
 
-	// build job name
-	refname := md.Repository.Ref
-	refname = strings.TrimPrefix(refname, "refs/heads/")
-	refname = strings.TrimPrefix(refname, "refs/tags/")
-	refname = strings.ReplaceAll(refname, "/", "-")
-	refname = strings.ReplaceAll(refname, "_", "-")
-	refname = strings.ReplaceAll(refname, "@", "-")
-	refname = strings.ToLower(refname)
-	if refname == "" {
-		// we did not compute a sensible refname - use moniker
-		refname = moniker.New().NameSep("-")
-	}
-	name := fmt.Sprintf("%s-%s-%s", md.Repository.Repo, jobSpecName, refname)
-	if refname != "" {
-		// we have a valid refname, hence need to acquire job number
-		t, err := srv.Groups.Next(name)
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
-		}
-
-		name = fmt.Sprintf("%s.%d", name, t)
+	name, err := srv.newJobName(ctx, md, jobSpecName)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	// We do not store the GitHub token of the request and hence can only restart those with default auth
@@ -243,6 +303,30 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 	}, nil
 }
 
+// startIdempotent returns the job previously started with an idempotency key, or starts a new one
+func (srv *Service) startIdempotent(ctx context.Context, key string, start func() (*v1.StartJobResponse, error)) (*v1.StartJobResponse, error) {
+	existing, err := srv.idempotency.Begin(key)
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if existing != "" {
+		job, err := srv.Jobs.Get(ctx, existing)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		log.WithField("name", existing).Info("job was started with this idempotency key before - not starting it again")
+		return &v1.StartJobResponse{Status: job}, nil
+	}
+
+	resp, err := start()
+	var name string
+	if err == nil && resp.Status != nil {
+		name = resp.Status.Name
+	}
+	srv.idempotency.Done(key, name)
+	return resp, err
+}
+
 func translateGitHubToGRPCError(err error, rev, ref string) error {
 	if gherr, ok := err.(*github.ErrorResponse); ok && gherr.Response.StatusCode == 422 {
 		msg := fmt.Sprintf("revision %s", rev)
@@ -257,55 +341,110 @@ func translateGitHubToGRPCError(err error, rev, ref string) error {
 
 // StartFromPreviousJob starts a new job based on an old one
 func (srv *Service) StartFromPreviousJob(ctx context.Context, req *v1.StartFromPreviousJobRequest) (*v1.StartJobResponse, error) {
-	oldJobStatus, err := srv.Jobs.Get(ctx, req.PreviousJob)
+	previous, err := srv.Jobs.Get(ctx, req.PreviousJob)
 	if err == store.ErrNotFound {
 		return nil, status.Error(codes.NotFound, "job spec not found")
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	jobYAML, err := srv.Jobs.GetJobSpec(req.PreviousJob)
+	// whoever replays the job triggers the new one, not whoever triggered the previous one
+	replayed := proto.Clone(previous.Metadata).(*v1.JobMetadata)
+	if err := srv.authorizeReplay(ctx, replayed); err != nil {
+		return nil, err
+	}
+	if req.IdempotencyKey != "" {
+		return srv.startIdempotent(ctx, "previous/"+req.IdempotencyKey, func() (*v1.StartJobResponse, error) {
+			r := *req
+			r.IdempotencyKey = ""
+			return srv.StartFromPreviousJob(ctx, &r)
+		})
+	}
+	if err := srv.checkRateLimit(ctx, replayed); err != nil {
+		return nil, err
+	}
+
+	return srv.replayJob(ctx, req.PreviousJob, req.GithubToken, req.Exact, func(md *v1.JobMetadata) {
+		md.TriggeredBy = replayed.TriggeredBy
+	})
+}
+
+// replayJob starts a new job on the revision and job spec of a previous one. Exact replays run the job spec as it was
+// rendered for the previous job. If modifyMetadata is not nil, it can change a copy of the previous job's metadata
+// before the new job starts.
+func (srv *Service) replayJob(ctx context.Context, previousJob, githubToken string, exact bool, modifyMetadata func(*v1.JobMetadata)) (*v1.StartJobResponse, error) {
+	oldJobStatus, err := srv.Jobs.Get(ctx, previousJob)
 	if err == store.ErrNotFound {
 		return nil, status.Error(codes.NotFound, "job spec not found")
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	jobYAML, err := srv.getJobSpec(ctx, previousJob)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "job spec not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var rendered []byte
+	if exact {
+		rendered, err = srv.getRenderedJobSpec(ctx, previousJob)
+		if err == store.ErrNotFound {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s has no rendered job spec and cannot be replayed exactly", previousJob)
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := srv.checkAcceptsJobs(ctx, oldJobStatus.Metadata); err != nil {
+		return nil, err
+	}
+	if err := srv.checkRepositoryPolicy(ctx, oldJobStatus.Metadata); err != nil {
+		return nil, err
+	}
 
-	name := req.PreviousJob
-	if strings.Contains(name, ".") {
-		segs := strings.Split(name, ".")
-		name = strings.Join(segs[0:len(segs)-1], ".")
+	// the new job continues the group of the previous one, in the naming scheme of the previous one
+	group, sep := previousJob, defaultNameSeparator
+	if g, s, _, ok := jobNumberGroup(previousJob); ok {
+		group, sep = g, s
 	}
-	nr, err := srv.Groups.Next(name)
+	name, err := srv.nextJobName(ctx, group, sep)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	name = fmt.Sprintf("%s.%d", name, nr)
 
 	gitauth := srv.GitHub.Auth
-	if req.GithubToken != "" {
-		gitauth = fixedOAuthTokenGitCreds(req.GithubToken)
+	if githubToken != "" {
+		gitauth = fixedOAuthTokenGitCreds(githubToken)
 	}
 
-	md := oldJobStatus.Metadata
-	cp := &GitHubContentProvider{
-		Owner:    md.Repository.Owner,
-		Repo:     md.Repository.Repo,
-		Revision: md.Repository.Revision,
-		Client:   srv.GitHub.Client,
-		Auth:     gitauth,
+	md := proto.Clone(oldJobStatus.Metadata).(*v1.JobMetadata)
+	if modifyMetadata != nil {
+		modifyMetadata(md)
+	}
+	var cp ContentProvider
+	if pcp, ok := srv.pluginContentProvider(md); ok {
+		cp = pcp
+	} else {
+		cp = &GitHubContentProvider{
+			Owner:    md.Repository.Owner,
+			Repo:     md.Repository.Repo,
+			Revision: md.Repository.Revision,
+			Client:   srv.GitHub.Client,
+			Auth:     gitauth,
+		}
 	}
 
 	// We do not store the GitHub token of the request and hence can only restart those with default auth
-	canReplay := req.GithubToken == ""
+	canReplay := githubToken == ""
 
-	jobStatus, err := srv.RunJob(ctx, name, *oldJobStatus.Metadata, cp, jobYAML, canReplay)
+	jobStatus, err := srv.runJob(ctx, name, *md, cp, jobYAML, rendered, canReplay)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	log.WithField("name", req.PreviousJob).WithField("old-name", name).Info(("started new job from an old one"))
+	log.WithField("name", previousJob).WithField("old-name", name).WithField("exact", exact).Info(("started new job from an old one"))
 	return &v1.StartJobResponse{
 		Status: jobStatus,
 	}, nil
@@ -370,18 +509,38 @@ func (srv *Service) ListJobs(ctx context.Context, req *v1.ListJobsRequest) (resp
 
 // Subscribe listens to job updates
 func (srv *Service) Subscribe(req *v1.SubscribeRequest, resp v1.WerftService_SubscribeServer) (err error) {
+	err = filterexpr.Validate(req.Filter)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	evts := srv.events.On("job")
-	for evt := range evts {
-		job := evt.Args[0].(*v1.JobStatus)
-		if !filterexpr.MatchesFilter(job, req.Filter) {
-			continue
-		}
+	defer srv.events.Off("job", evts)
 
-		resp.Send(&v1.SubscribeResponse{
-			Result: job,
-		})
+	for {
+		select {
+		case evt, ok := <-evts:
+			if !ok || len(evt.Args) == 0 {
+				return nil
+			}
+			job, ok := evt.Args[0].(*v1.JobStatus)
+			if !ok {
+				continue
+			}
+			if !filterexpr.MatchesFilter(job, req.Filter) {
+				continue
+			}
+
+			err = resp.Send(&v1.SubscribeResponse{
+				Result: job,
+			})
+			if err != nil {
+				return err
+			}
+		case <-resp.Context().Done():
+			return status.Error(codes.Canceled, resp.Context().Err().Error())
+		}
 	}
-	return nil
 }
 
 // GetJob returns the information about a particular job
@@ -399,6 +558,110 @@ func (srv *Service) GetJob(ctx context.Context, req *v1.GetJobRequest) (resp *v1
 	}, nil
 }
 
+// GetJobGraph returns the stages of a job with their dependencies, status, durations and log slices
+func (srv *Service) GetJobGraph(ctx context.Context, req *v1.GetJobGraphRequest) (*v1.GetJobGraphResponse, error) {
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	graph, _, err := srv.jobGraph(job)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &v1.GetJobGraphResponse{
+		Status: job,
+		Stages: graph.Stages(job),
+	}, nil
+}
+
+// ListLogSlices returns the slice index of a job's log without the log content
+func (srv *Service) ListLogSlices(ctx context.Context, req *v1.ListLogSlicesRequest) (*v1.ListLogSlicesResponse, error) {
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound || (err == nil && job == nil) {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	graph, indexed, err := srv.jobGraph(job)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &v1.ListLogSlicesResponse{
+		Status:  job,
+		Slices:  graph.Slices(job),
+		Indexed: indexed,
+	}, nil
+}
+
+// jobGraph produces the graph of a job. fromLog is false if the graph stems from the job steps rather than its log.
+func (srv *Service) jobGraph(job *v1.JobStatus) (graph *jobGraph, fromLog bool, err error) {
+	// while we're listening to a job's log we build its graph as we go
+	srv.mu.RLock()
+	if jl, ok := srv.logListener[job.Name]; ok {
+		graph = jl.Graph
+	}
+	srv.mu.RUnlock()
+	if graph != nil {
+		return graph, true, nil
+	}
+
+	graph = newJobGraph(false)
+	if job.Phase == v1.JobPhase_PHASE_DONE {
+		err = srv.replayJobGraph(graph, job.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		return graph, true, nil
+	}
+
+	// reading the log of an unfinished job would block until the job is done, hence we resort to its steps
+	for _, step := range job.Steps {
+		if step.Started == nil {
+			// steps of the job spec which have not started yet
+			continue
+		}
+		graph.Add(&v1.LogSliceEvent{Name: step.Name, Type: v1.LogSliceType_SLICE_PHASE, Time: step.Started})
+	}
+	return graph, false, nil
+}
+
+// replayJobGraph builds the graph of a finished job from its stored log
+func (srv *Service) replayJobGraph(graph *jobGraph, name string) error {
+	rd, err := srv.readLog(name)
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	evts, errchan := srv.Cutter.Slice(rd)
+	for evts != nil {
+		select {
+		case evt, ok := <-evts:
+			if !ok {
+				evts = nil
+				continue
+			}
+			graph.Add(evt)
+		case err := <-errchan:
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Listen listens to logs
 func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServer) error {
 	// TOOD: if one of the listeners fails, all have to fail
@@ -406,6 +669,10 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 	if err == store.ErrNotFound {
 		return status.Errorf(codes.NotFound, "%s not found", req.Name)
 	}
+	err = filterexpr.Validate(req.Filter)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	var (
 		wg      sync.WaitGroup
@@ -413,10 +680,15 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 		errchan = make(chan error)
 	)
 	if req.Logs != v1.ListenRequestLogs_LOGS_DISABLED {
-		wg.Add(1)
-		logwg.Add(1)
+		cursor, err := logcutter.ParseCursor(req.Cursor)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if req.Level != "" && logcutter.NormalizeLevel(req.Level) == "" {
+			return status.Errorf(codes.InvalidArgument, "unknown log level: %s", req.Level)
+		}
 
-		rd, err := srv.Logs.Read(req.Name)
+		rd, err := srv.readLog(req.Name)
 		if err != nil {
 			if err == store.ErrNotFound {
 				return status.Error(codes.NotFound, "not found")
@@ -425,7 +697,23 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 			return status.Error(codes.Internal, err.Error())
 		}
 
+		// subscribe before loading the snapshots recorded so far, s.t. we do not miss any in between
+		var live <-chan emitter.Event
+		if job != nil && job.Phase != v1.JobPhase_PHASE_DONE && req.Status != v1.ListenRequestStatus_STATUS_DISABLED {
+			evts := srv.events.On("job")
+			defer srv.events.Off("job", evts)
+			live = evts
+		}
+		snapshots, err := srv.newStatusReplay(ls.Context(), req, cursor.Offset > 0, ls.Send)
+		if err != nil {
+			rd.Close()
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		wg.Add(1)
+		logwg.Add(1)
 		go func() {
+			defer recovery.Recover("listen")
 			defer rd.Close()
 			defer wg.Done()
 			defer logwg.Done()
@@ -435,26 +723,50 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 				cutter = logcutter.NoCutter
 			}
 
-			evts, echan := cutter.Slice(rd)
+			evts, echan := cutter.Resume(rd, cursor)
 			for {
 				select {
 				case evt := <-evts:
 					if evt == nil {
+						// the log is complete - whatever status snapshots are left belong to its end
+						err = snapshots.Rest(ls.Context())
 						return
 					}
-					if req.Logs == v1.ListenRequestLogs_LOGS_HTML {
+					if req.Level != "" && evt.Type == v1.LogSliceType_SLICE_CONTENT && !logcutter.LevelAtLeast(evt.Level, req.Level) {
+						continue
+					}
+					switch req.Logs {
+					case v1.ListenRequestLogs_LOGS_HTML:
 						evt.Payload = string(termtohtml.Render([]byte(evt.Payload)))
+					case v1.ListenRequestLogs_LOGS_PLAIN:
+						evt.Payload = logcutter.StripANSI(evt.Payload)
 					}
 
+					err = snapshots.Until(evt.Time)
+					if err != nil {
+						continue
+					}
 					err = ls.Send(&v1.ListenResponse{
 						Content: &v1.ListenResponse_Slice{
 							Slice: evt,
 						},
 					})
+				case e := <-live:
+					if len(e.Args) == 0 {
+						live = nil
+						continue
+					}
+					if s, ok := e.Args[0].(*v1.JobStatus); ok && s.Name == req.Name {
+						err = snapshots.Live(s)
+					}
 				case err = <-echan:
 					if err == nil {
 						return
 					}
+					if err == logcutter.ErrCursorOutOfRange {
+						errchan <- status.Error(codes.OutOfRange, err.Error())
+						return
+					}
 
 					errchan <- status.Error(codes.Internal, err.Error())
 					return
@@ -470,6 +782,7 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 		wg.Add(1)
 
 		go func() {
+			defer recovery.Recover("listen")
 			defer wg.Done()
 
 			if job.Phase == v1.JobPhase_PHASE_DONE {
@@ -477,6 +790,9 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 				// we first have to dump out all the logs and then send the one final status update.
 				logwg.Wait()
 
+				if !filterexpr.MatchesFilter(job, req.Filter) {
+					return
+				}
 				ls.Send(&v1.ListenResponse{
 					Content: &v1.ListenResponse_Update{
 						Update: job,
@@ -486,7 +802,15 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 			}
 
 			evts := srv.events.On("job")
-			for evt := range evts {
+			defer srv.events.Off("job", evts)
+			for {
+				var evt emitter.Event
+				select {
+				case evt = <-evts:
+				case <-ls.Context().Done():
+					return
+				}
+
 				if len(evt.Args) == 0 {
 					return
 				}
@@ -497,6 +821,9 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 				if job.Name != req.Name {
 					continue
 				}
+				if !filterexpr.MatchesFilter(job, req.Filter) {
+					continue
+				}
 
 				ls.Send(&v1.ListenResponse{
 					Content: &v1.ListenResponse_Update{
@@ -516,6 +843,60 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 	return err
 }
 
+// downloadChunkSize is the maximum number of log bytes we send in a single DownloadLog message
+const downloadChunkSize = 32 * 1024
+
+// DownloadLog streams the complete stored log of a job. Unlike Listen it sends the bytes as they are stored, s.t. clients
+// can resume an interrupted download from the number of bytes they've received so far.
+func (srv *Service) DownloadLog(req *v1.DownloadLogRequest, ds v1.WerftService_DownloadLogServer) error {
+	if req.Offset < 0 {
+		return status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+
+	rd, err := srv.readLog(req.Name)
+	if err == store.ErrNotFound {
+		return status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer rd.Close()
+
+	if req.Offset > 0 {
+		_, err = io.CopyN(ioutil.Discard, rd, req.Offset)
+		if err == io.EOF {
+			return status.Error(codes.OutOfRange, "offset points beyond the end of the log")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	var (
+		offset = req.Offset
+		buf    = make([]byte, downloadChunkSize)
+	)
+	for {
+		n, err := rd.Read(buf)
+		if n > 0 {
+			serr := ds.Send(&v1.DownloadLogResponse{Offset: offset, Data: buf[:n]})
+			if serr != nil {
+				return serr
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if ds.Context().Err() != nil {
+			return status.Error(codes.Aborted, ds.Context().Err().Error())
+		}
+	}
+}
+
 // StopJob stops a running job
 func (srv *Service) StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.StopJobResponse, error) {
 	job, err := srv.Jobs.Get(ctx, req.Name)
@@ -525,6 +906,9 @@ func (srv *Service) StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.St
 	if job == nil {
 		return nil, status.Error(codes.NotFound, "not found")
 	}
+	if err := srv.authorizeWrite(ctx, job.Metadata.GetRepository()); err != nil {
+		return nil, err
+	}
 
 	if job.Phase != v1.JobPhase_PHASE_PREPARING && job.Phase != v1.JobPhase_PHASE_STARTING && job.Phase != v1.JobPhase_PHASE_RUNNING {
 		return nil, status.Error(codes.FailedPrecondition, "job is unstoppable phase")
@@ -538,6 +922,128 @@ func (srv *Service) StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.St
 	return &v1.StopJobResponse{}, nil
 }
 
+// AnnotateJob adds or updates annotations of a job
+func (srv *Service) AnnotateJob(ctx context.Context, req *v1.AnnotateJobRequest) (*v1.AnnotateJobResponse, error) {
+	if len(req.Annotations) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "annotations are required")
+	}
+	annotations := make(map[string]string, len(req.Annotations))
+	for _, a := range req.Annotations {
+		if a.Key == annotationCleanupJob {
+			return nil, status.Errorf(codes.InvalidArgument, "annotation %s is reserved", a.Key)
+		}
+		if errs := validation.IsQualifiedName(fmt.Sprintf("%s/%s", executor.UserDataAnnotationPrefix, a.Key)); len(errs) > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid annotation %s: %s", a.Key, strings.Join(errs, "; "))
+		}
+		annotations[a.Key] = a.Value
+	}
+
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := srv.authorizeWrite(ctx, job.Metadata.GetRepository()); err != nil {
+		return nil, err
+	}
+
+	for _, a := range req.Annotations {
+		job.Metadata.SetAnnotation(a.Key, a.Value)
+	}
+
+	if job.Phase == v1.JobPhase_PHASE_DONE {
+		// the job's pod might be gone already, hence we update the job store directly
+		err = srv.Jobs.Store(ctx, *job)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		<-srv.events.Emit("job", job)
+	} else {
+		// the executor picks up the annotations with the next status update of the job
+		err = srv.Executor.AddUserAnnotations(req.Name, annotations)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	log.WithField("name", req.Name).WithField("annotations", annotations).Debug("annotated job")
+	return &v1.AnnotateJobResponse{Status: job}, nil
+}
+
+// PinJob pins or unpins a job. Pruning never removes pinned jobs.
+func (srv *Service) PinJob(ctx context.Context, req *v1.PinJobRequest) (*v1.PinJobResponse, error) {
+	job, err := srv.Jobs.Get(ctx, req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := srv.authorizeWrite(ctx, job.Metadata.GetRepository()); err != nil {
+		return nil, err
+	}
+
+	if job.Conditions == nil {
+		job.Conditions = &v1.JobConditions{}
+	}
+	job.Conditions.Pinned = req.Pinned
+
+	if job.Phase == v1.JobPhase_PHASE_DONE {
+		// the job's pod might be gone already, hence we update the job store directly
+		err = srv.Jobs.Store(ctx, *job)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		<-srv.events.Emit("job", job)
+	} else {
+		// the executor picks up the pin with the next status update of the job
+		err = srv.Executor.SetPinned(req.Name, req.Pinned)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	log.WithField("name", req.Name).WithField("pinned", req.Pinned).Info("pinned job")
+	return &v1.PinJobResponse{Status: job}, nil
+}
+
+// GetStats aggregates durations, failure rates and cost of past jobs of a repository, or all repositories of an owner
+func (srv *Service) GetStats(ctx context.Context, req *v1.GetStatsRequest) (*v1.GetStatsResponse, error) {
+	if req.RepoOwner == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository owner is required")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 500
+	}
+	top := int(req.Top)
+	if top <= 0 {
+		top = 10
+	}
+
+	filter := []*v1.FilterExpression{
+		{Terms: []*v1.FilterTerm{{Field: "repo.owner", Value: req.RepoOwner}}},
+	}
+	if req.RepoRepo != "" {
+		filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: "repo.repo", Value: req.RepoRepo}}})
+	}
+	jobs, _, err := srv.Jobs.Find(ctx,
+		filter,
+		[]*v1.OrderExpression{{Field: "created", Ascending: false}},
+		0, limit,
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := analytics.Compute(jobs, top)
+	res.Costs = analytics.ComputeCosts(jobs)
+	return res, nil
+}
+
 func fixedOAuthTokenGitCreds(tkn string) GitCredentialHelper {
 	return func(ctx context.Context) (user string, pass string, err error) {
 		return tkn, "x-oauth-basic", nil
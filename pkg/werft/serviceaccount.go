@@ -0,0 +1,356 @@
+package werft
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// serviceAccountTokenPrefix starts every service account token, s.t. they are easy to recognise, e.g. by secret scanners
+	serviceAccountTokenPrefix = "werft_sa_"
+
+	// tokenUsageInterval is how often we record that a token was used at most. Recording every use would
+	// write to the store on every request.
+	tokenUsageInterval = 1 * time.Minute
+
+	// tokenPruneInterval is how often we remove expired service account tokens
+	tokenPruneInterval = 1 * time.Hour
+	// expiredTokenRetention is how long expired tokens remain listed, e.g. to find out which automation still
+	// used a token after it was rotated
+	expiredTokenRetention = 7 * 24 * time.Hour
+)
+
+// hashToken produces the hash under which we store a token
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// newServiceAccountToken produces a new token and its ID
+func newServiceAccountToken() (id, secret string, err error) {
+	var buf [8 + 32]byte
+	_, err = rand.Read(buf[:])
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:8]), serviceAccountTokenPrefix + base64.RawURLEncoding.EncodeToString(buf[8:]), nil
+}
+
+// tokenUsage keeps track of when we last recorded the use of a token
+type tokenUsage struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// due returns true if the use of a token should be recorded
+func (u *tokenUsage) due(id string, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.last == nil {
+		u.last = make(map[string]time.Time)
+	}
+	if now.Sub(u.last[id]) < tokenUsageInterval {
+		return false
+	}
+	u.last[id] = now
+	return true
+}
+
+// authenticateServiceAccount finds the service account token presented in a request. If there is none we return nil.
+func (srv *Service) authenticateServiceAccount(ctx context.Context, presented string) (*TokenConfig, error) {
+	if srv.ServiceAccountTokens == nil || !strings.HasPrefix(presented, serviceAccountTokenPrefix) {
+		return nil, nil
+	}
+
+	tkn, err := srv.ServiceAccountTokens.GetByHash(ctx, hashToken(presented))
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		log.WithError(err).Warn("cannot look up service account token")
+		return nil, status.Error(codes.Unavailable, "cannot check token")
+	}
+	now := time.Now()
+	if tkn.Expired(now) {
+		return nil, status.Error(codes.Unauthenticated, "token has expired")
+	}
+
+	if srv.tokenUsage.due(tkn.ID, now) {
+		err = srv.ServiceAccountTokens.Touch(ctx, tkn.ID, now)
+		if err != nil {
+			log.WithError(err).WithField("id", tkn.ID).Debug("cannot record token use")
+		}
+	}
+
+	scopes := make([]Scope, len(tkn.Scopes))
+	for i, s := range tkn.Scopes {
+		scopes[i] = Scope(s)
+	}
+	return &TokenConfig{Name: tkn.ServiceAccount, Scopes: scopes, serviceAccount: tkn.ServiceAccount}, nil
+}
+
+// pruneServiceAccountTokens periodically removes service account tokens which expired a while ago
+func (srv *Service) pruneServiceAccountTokens() {
+	tick := time.NewTicker(tokenPruneInterval)
+	defer tick.Stop()
+	for {
+		pruned, err := srv.ServiceAccountTokens.Prune(context.Background(), time.Now().Add(-expiredTokenRetention))
+		if err != nil {
+			log.WithError(err).Warn("cannot prune expired service account tokens")
+		}
+		if pruned > 0 {
+			log.WithField("count", pruned).Info("pruned expired service account tokens")
+		}
+		<-tick.C
+	}
+}
+
+// serviceAccountTokens returns the service account token store or an error if there is none
+func (srv *Service) serviceAccountTokens(ctx context.Context) (store.ServiceAccountTokens, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if srv.ServiceAccountTokens == nil {
+		return nil, status.Error(codes.Unimplemented, "this werft installation does not support service accounts")
+	}
+	return srv.ServiceAccountTokens, nil
+}
+
+// CreateServiceAccountToken creates a long-lived token for a service account
+func (srv *Service) CreateServiceAccountToken(ctx context.Context, req *v1.CreateServiceAccountTokenRequest) (*v1.CreateServiceAccountTokenResponse, error) {
+	tokens, err := srv.serviceAccountTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.ServiceAccount == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_account is required")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "scopes are required")
+	}
+	for _, s := range req.Scopes {
+		if err := ValidateScope(Scope(s)); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	now := time.Now()
+	var expires time.Time
+	if req.ExpiresIn != nil {
+		d, err := ptypes.Duration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "expires_in must be positive")
+		}
+		expires = now.Add(d)
+	}
+
+	tkn, secret, err := srv.createServiceAccountToken(ctx, tokens, req.ServiceAccount, req.Scopes, now, expires)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("serviceAccount", tkn.ServiceAccount).WithField("id", tkn.ID).Info("created service account token")
+	return &v1.CreateServiceAccountTokenResponse{Token: serviceAccountTokenToProto(tkn), Secret: secret}, nil
+}
+
+func (srv *Service) createServiceAccountToken(ctx context.Context, tokens store.ServiceAccountTokens, serviceAccount string, scopes []string, now, expires time.Time) (*store.ServiceAccountToken, string, error) {
+	id, secret, err := newServiceAccountToken()
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, err.Error())
+	}
+	tkn := store.ServiceAccountToken{
+		ID:             id,
+		ServiceAccount: serviceAccount,
+		Hash:           hashToken(secret),
+		Scopes:         scopes,
+		Created:        now,
+		Expires:        expires,
+	}
+	err = tokens.Create(ctx, tkn)
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, err.Error())
+	}
+	return &tkn, secret, nil
+}
+
+// ListServiceAccountTokens lists the tokens of service accounts without revealing them
+func (srv *Service) ListServiceAccountTokens(ctx context.Context, req *v1.ListServiceAccountTokensRequest) (*v1.ListServiceAccountTokensResponse, error) {
+	tokens, err := srv.serviceAccountTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tkns, err := tokens.List(ctx, req.ServiceAccount)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &v1.ListServiceAccountTokensResponse{}
+	for _, t := range tkns {
+		res.Tokens = append(res.Tokens, serviceAccountTokenToProto(t))
+	}
+	return res, nil
+}
+
+// RotateServiceAccountToken replaces a token with a new one of the same service account and scopes
+func (srv *Service) RotateServiceAccountToken(ctx context.Context, req *v1.RotateServiceAccountTokenRequest) (*v1.RotateServiceAccountTokenResponse, error) {
+	tokens, err := srv.serviceAccountTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var grace time.Duration
+	if req.GracePeriod != nil {
+		grace, err = ptypes.Duration(req.GracePeriod)
+		if err != nil || grace < 0 {
+			return nil, status.Error(codes.InvalidArgument, "grace_period must not be negative")
+		}
+	}
+
+	old, err := tokens.Get(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "token %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	now := time.Now()
+	if old.Expired(now) {
+		return nil, status.Errorf(codes.FailedPrecondition, "token %s has expired already", req.Id)
+	}
+
+	// the new token works as long as the old one would have, s.t. rotation does not extend the life of expiring tokens
+	var expires time.Time
+	if !old.Expires.IsZero() {
+		expires = now.Add(old.Expires.Sub(old.Created))
+	}
+	tkn, secret, err := srv.createServiceAccountToken(ctx, tokens, old.ServiceAccount, old.Scopes, now, expires)
+	if err != nil {
+		return nil, err
+	}
+
+	oldExpires := now.Add(grace)
+	if !old.Expires.IsZero() && old.Expires.Before(oldExpires) {
+		oldExpires = old.Expires
+	}
+	err = tokens.Expire(ctx, old.ID, oldExpires)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	old.Expires = oldExpires
+
+	log.WithField("serviceAccount", tkn.ServiceAccount).WithField("id", tkn.ID).WithField("previous", old.ID).Info("rotated service account token")
+	return &v1.RotateServiceAccountTokenResponse{
+		Token:    serviceAccountTokenToProto(tkn),
+		Secret:   secret,
+		Previous: serviceAccountTokenToProto(old),
+	}, nil
+}
+
+// RevokeServiceAccountToken makes a token stop working immediately
+func (srv *Service) RevokeServiceAccountToken(ctx context.Context, req *v1.RevokeServiceAccountTokenRequest) (*v1.RevokeServiceAccountTokenResponse, error) {
+	tokens, err := srv.serviceAccountTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tokens.Delete(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "token %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("id", req.Id).Info("revoked service account token")
+	return &v1.RevokeServiceAccountTokenResponse{}, nil
+}
+
+// ownServiceAccount returns the service account whose token authenticates the request. Tokens from the config belong
+// to no service account and are managed in the config.
+func (srv *Service) ownServiceAccount(ctx context.Context) (string, error) {
+	tkn, err := srv.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+	if tkn == nil {
+		return "", status.Error(codes.Unauthenticated, "only service account tokens have tokens of their own")
+	}
+	if tkn.serviceAccount == "" {
+		return "", status.Errorf(codes.FailedPrecondition, "token %s is part of the server config and has no tokens of its own", tkn.Name)
+	}
+	return tkn.serviceAccount, nil
+}
+
+// ListOwnTokens lists the tokens of the caller's service account without revealing them
+func (srv *Service) ListOwnTokens(ctx context.Context, req *v1.ListOwnTokensRequest) (*v1.ListOwnTokensResponse, error) {
+	serviceAccount, err := srv.ownServiceAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tkns, err := srv.ServiceAccountTokens.List(ctx, serviceAccount)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &v1.ListOwnTokensResponse{}
+	for _, t := range tkns {
+		res.Tokens = append(res.Tokens, serviceAccountTokenToProto(t))
+	}
+	return res, nil
+}
+
+// RevokeOwnToken makes a token of the caller's service account stop working immediately
+func (srv *Service) RevokeOwnToken(ctx context.Context, req *v1.RevokeOwnTokenRequest) (*v1.RevokeOwnTokenResponse, error) {
+	serviceAccount, err := srv.ownServiceAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokens of other service accounts are none of the caller's business, not even whether they exist
+	tkn, err := srv.ServiceAccountTokens.Get(ctx, req.Id)
+	if err == store.ErrNotFound || (err == nil && tkn.ServiceAccount != serviceAccount) {
+		return nil, status.Errorf(codes.NotFound, "token %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = srv.ServiceAccountTokens.Delete(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "token %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("serviceAccount", serviceAccount).WithField("id", req.Id).Info("service account revoked its own token")
+	return &v1.RevokeOwnTokenResponse{}, nil
+}
+
+func serviceAccountTokenToProto(t *store.ServiceAccountToken) *v1.ServiceAccountToken {
+	ts := func(t time.Time) *timestamp.Timestamp {
+		if t.IsZero() {
+			return nil
+		}
+		res, _ := ptypes.TimestampProto(t)
+		return res
+	}
+	return &v1.ServiceAccountToken{
+		Id:             t.ID,
+		ServiceAccount: t.ServiceAccount,
+		Scopes:         t.Scopes,
+		Created:        ts(t.Created),
+		Expires:        ts(t.Expires),
+		LastUsed:       ts(t.LastUsed),
+	}
+}
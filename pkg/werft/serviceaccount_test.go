@@ -0,0 +1,86 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOwnTokens(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	srv.ServiceAccountTokens = store.NewInMemoryServiceAccountTokens()
+
+	create := func(serviceAccount string) *v1.CreateServiceAccountTokenResponse {
+		resp, err := srv.CreateServiceAccountToken(bearer("admin-secret"), &v1.CreateServiceAccountTokenRequest{
+			ServiceAccount: serviceAccount,
+			Scopes:         []string{string(werft.ScopeTrigger)},
+		})
+		if err != nil {
+			t.Fatalf("cannot create token: %v", err)
+		}
+		return resp
+	}
+	deploy, deployToo, ci := create("deploy-bot"), create("deploy-bot"), create("ci")
+
+	tests := []struct {
+		Name  string
+		Token string
+		Code  codes.Code
+		// Listed are the tokens ListOwnTokens returns
+		Listed []string
+	}{
+		{Name: "service account", Token: deploy.Secret, Listed: []string{deploy.Token.Id, deployToo.Token.Id}},
+		{Name: "other service account", Token: ci.Secret, Listed: []string{ci.Token.Id}},
+		{Name: "config token", Token: "ci-secret", Code: codes.FailedPrecondition},
+		{Name: "anonymous", Code: codes.Unauthenticated},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.ListOwnTokens(bearer(test.Token), &v1.ListOwnTokensRequest{})
+			if code := status.Code(err); code != test.Code {
+				t.Fatalf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+			var ids []string
+			for _, tkn := range resp.Tokens {
+				ids = append(ids, tkn.Id)
+			}
+			if len(ids) != len(test.Listed) {
+				t.Fatalf("expected tokens %v, got %v", test.Listed, ids)
+			}
+			for _, id := range test.Listed {
+				var found bool
+				for _, act := range ids {
+					found = found || act == id
+				}
+				if !found {
+					t.Errorf("expected tokens %v, got %v", test.Listed, ids)
+				}
+			}
+		})
+	}
+
+	// service accounts cannot revoke the tokens of others, nor find out that they exist
+	_, err := srv.RevokeOwnToken(bearer(deploy.Secret), &v1.RevokeOwnTokenRequest{Id: ci.Token.Id})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("unexpected code %v when revoking the token of another service account: %v", code, err)
+	}
+	if _, err := srv.ServiceAccountTokens.Get(context.Background(), ci.Token.Id); err != nil {
+		t.Errorf("revoked the token of another service account: %v", err)
+	}
+
+	_, err = srv.RevokeOwnToken(bearer(deploy.Secret), &v1.RevokeOwnTokenRequest{Id: deployToo.Token.Id})
+	if err != nil {
+		t.Fatalf("cannot revoke own token: %v", err)
+	}
+	if _, err := srv.ListOwnTokens(bearer(deployToo.Secret), &v1.ListOwnTokensRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("revoked token still authenticates: %v", err)
+	}
+}
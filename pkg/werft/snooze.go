@@ -0,0 +1,112 @@
+package werft
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// snoozedBy returns the snooze which silences the notifications about a job, or nil if there is none
+func (srv *Service) snoozedBy(job *v1.JobStatus) *v1.NotificationSnooze {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+
+	now := time.Now()
+	for _, s := range srv.snoozes {
+		until, err := ptypes.Timestamp(s.Until)
+		if err != nil || until.Before(now) {
+			continue
+		}
+		if filterexpr.MatchesFilter(job, s.Filter) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SnoozeNotifications silences the notifications about jobs matching a filter for a while
+func (srv *Service) SnoozeNotifications(ctx context.Context, req *v1.SnoozeNotificationsRequest) (*v1.SnoozeNotificationsResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if req.Duration == nil {
+		return nil, status.Error(codes.InvalidArgument, "duration is required")
+	}
+	d, err := ptypes.Duration(req.Duration)
+	if err != nil || d <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "duration must be positive")
+	}
+	if err := filterexpr.Validate(req.Filter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	until, err := ptypes.TimestampProto(time.Now().Add(d))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.expireSnoozes()
+	srv.snoozeID++
+	snooze := &v1.NotificationSnooze{
+		Id:     strconv.Itoa(srv.snoozeID),
+		Filter: req.Filter,
+		Until:  until,
+		Reason: req.Reason,
+	}
+	srv.snoozes = append(srv.snoozes, snooze)
+	return &v1.SnoozeNotificationsResponse{Snooze: snooze}, nil
+}
+
+// ListSnoozes lists the notification snoozes which have not expired yet
+func (srv *Service) ListSnoozes(ctx context.Context, req *v1.ListSnoozesRequest) (*v1.ListSnoozesResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.expireSnoozes()
+	return &v1.ListSnoozesResponse{Snoozes: srv.snoozes}, nil
+}
+
+// DeleteSnooze ends a notification snooze before it expires
+func (srv *Service) DeleteSnooze(ctx context.Context, req *v1.DeleteSnoozeRequest) (*v1.DeleteSnoozeResponse, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for i, s := range srv.snoozes {
+		if s.Id != req.Id {
+			continue
+		}
+		srv.snoozes = append(srv.snoozes[:i:i], srv.snoozes[i+1:]...)
+		return &v1.DeleteSnoozeResponse{}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "snooze %s not found", req.Id)
+}
+
+// expireSnoozes removes expired snoozes. Callers must hold the lock.
+func (srv *Service) expireSnoozes() {
+	now := time.Now()
+	var res []*v1.NotificationSnooze
+	for _, s := range srv.snoozes {
+		until, err := ptypes.Timestamp(s.Until)
+		if err != nil || until.Before(now) {
+			continue
+		}
+		res = append(res, s)
+	}
+	srv.snoozes = res
+}
@@ -0,0 +1,219 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HandleSSESubscribe streams job updates as server-sent events. Filters can be passed using the
+// filter query parameter, e.g. /api/v1/events?filter=phase==running&filter=repo.owner==32leaves.
+// Several filter parameters have to match all, alternatives within one filter parameter are separated by comma.
+func (srv *Service) HandleSSESubscribe(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSSEFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ss, err := newSSEStream(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = srv.Subscribe(&v1.SubscribeRequest{Filter: filter}, &sseSubscribeServer{ss})
+	ss.Close(err)
+}
+
+// HandleSSEListen streams the updates and log output of a single job as server-sent events.
+// The job name is the last segment of the path, e.g. /api/v1/listen/werft-build-master.42.
+// Supported query parameters are logs (one of disabled, unsliced, raw, html, plain - defaults to raw), updates (defaults to true),
+// status (one of log, disabled, structured - defaults to log), level and filter. Log slice events carry their cursor as event ID, so that browsers resume where they left off on reconnect.
+func (srv *Service) HandleSSEListen(w http.ResponseWriter, r *http.Request) {
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	name := segs[len(segs)-1]
+	if name == "" || name == "listen" {
+		http.Error(w, "missing job name", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	logs := v1.ListenRequestLogs_LOGS_RAW
+	if l := q.Get("logs"); l != "" {
+		lv, ok := v1.ListenRequestLogs_value["LOGS_"+strings.ToUpper(l)]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown logs mode: %s", l), http.StatusBadRequest)
+			return
+		}
+		logs = v1.ListenRequestLogs(lv)
+	}
+	var st v1.ListenRequestStatus
+	if sv := q.Get("status"); sv != "" {
+		v, ok := v1.ListenRequestStatus_value["STATUS_"+strings.ToUpper(sv)]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown status mode: %s", sv), http.StatusBadRequest)
+			return
+		}
+		st = v1.ListenRequestStatus(v)
+	}
+	filter, err := parseSSEFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cursor := q.Get("cursor")
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		cursor = id
+	}
+
+	ss, err := newSSEStream(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = srv.Listen(&v1.ListenRequest{
+		Name:    name,
+		Logs:    logs,
+		Updates: q.Get("updates") != "false",
+		Cursor:  cursor,
+		Level:   q.Get("level"),
+		Filter:  filter,
+		Status:  st,
+	}, &sseListenServer{ss})
+	ss.Close(err)
+}
+
+func parseSSEFilter(r *http.Request) ([]*v1.FilterExpression, error) {
+	var res []*v1.FilterExpression
+	for _, f := range r.URL.Query()["filter"] {
+		terms, err := filterexpr.Parse(strings.Split(f, ","))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &v1.FilterExpression{Terms: terms})
+	}
+	return res, nil
+}
+
+// sseStream writes protobuf messages as server-sent events. It implements the parts of grpc.ServerStream
+// the streaming RPCs of the service use, so that we can serve those RPCs over SSE as well.
+type sseStream struct {
+	w   http.ResponseWriter
+	f   http.Flusher
+	ctx context.Context
+	m   jsonpb.Marshaler
+
+	// Listen sends log slices and job updates concurrently
+	mu      sync.Mutex
+	started bool
+}
+
+func newSSEStream(w http.ResponseWriter, r *http.Request) (*sseStream, error) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported")
+	}
+	return &sseStream{w: w, f: f, ctx: r.Context()}, nil
+}
+
+// Send writes a single event
+func (s *sseStream) Send(event, id string, msg proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.Header().Set("Connection", "keep-alive")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	data, err := s.m.MarshalToString(msg)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		fmt.Fprintf(s.w, "id: %s\n", id)
+	}
+	_, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data)
+	if err != nil {
+		return err
+	}
+	s.f.Flush()
+	return nil
+}
+
+// Close ends the stream. If the stream ended because of an error, that error is reported as HTTP status
+// if no event was sent yet, and as error event otherwise.
+func (s *sseStream) Close(err error) {
+	if err == nil || s.ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, _ := status.FromError(err)
+	if !s.started {
+		code := http.StatusInternalServerError
+		switch st.Code() {
+		case codes.InvalidArgument, codes.OutOfRange:
+			code = http.StatusBadRequest
+		case codes.NotFound:
+			code = http.StatusNotFound
+		}
+		http.Error(s.w, st.Message(), code)
+		return
+	}
+
+	fmt.Fprintf(s.w, "event: error\ndata: %s\n\n", strings.ReplaceAll(st.Message(), "\n", " "))
+	s.f.Flush()
+	log.WithError(err).Debug("server-sent event stream failed")
+}
+
+func (s *sseStream) SetHeader(metadata.MD) error  { return nil }
+func (s *sseStream) SendHeader(metadata.MD) error { return nil }
+func (s *sseStream) SetTrailer(metadata.MD)       {}
+func (s *sseStream) Context() context.Context     { return s.ctx }
+func (s *sseStream) SendMsg(m interface{}) error {
+	return fmt.Errorf("not supported")
+}
+func (s *sseStream) RecvMsg(m interface{}) error {
+	return fmt.Errorf("not supported")
+}
+
+type sseSubscribeServer struct {
+	*sseStream
+}
+
+func (s *sseSubscribeServer) Send(resp *v1.SubscribeResponse) error {
+	return s.sseStream.Send("job", "", resp.Result)
+}
+
+type sseListenServer struct {
+	*sseStream
+}
+
+func (s *sseListenServer) Send(resp *v1.ListenResponse) error {
+	switch c := resp.Content.(type) {
+	case *v1.ListenResponse_Update:
+		return s.sseStream.Send("job", "", c.Update)
+	case *v1.ListenResponse_Slice:
+		return s.sseStream.Send("slice", c.Slice.Cursor, c.Slice)
+	case *v1.ListenResponse_Status:
+		return s.sseStream.Send("status", "", c.Status)
+	}
+	return nil
+}
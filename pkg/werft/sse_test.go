@@ -0,0 +1,226 @@
+package werft_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+// sseEvent is a single server-sent event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readSSEEvents parses server-sent events until the stream ends or n events were read
+func readSSEEvents(t *testing.T, r io.Reader, n int) []sseEvent {
+	var (
+		res     []sseEvent
+		current sseEvent
+		scanner = bufio.NewScanner(r)
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			res = append(res, current)
+			current = sseEvent{}
+			if len(res) == n {
+				break
+			}
+			continue
+		}
+		segs := strings.SplitN(line, ": ", 2)
+		if len(segs) != 2 {
+			t.Fatalf("invalid server-sent event line: %q", line)
+		}
+		switch segs[0] {
+		case "id":
+			current.ID = segs[1]
+		case "event":
+			current.Event = segs[1]
+		case "data":
+			current.Data = segs[1]
+		default:
+			t.Fatalf("unknown server-sent event field: %q", line)
+		}
+	}
+	if current != (sseEvent{}) {
+		t.Errorf("stream ended within an event: %+v", current)
+	}
+	return res
+}
+
+func TestHandleSSEListen(t *testing.T) {
+	const jobName = "werft-build-master.1"
+	jobs := store.NewInMemoryJobStore()
+	err := jobs.Store(context.Background(), v1.JobStatus{
+		Name:     jobName,
+		Metadata: &v1.JobMetadata{Owner: "alice", Repository: testRepo()},
+		Phase:    v1.JobPhase_PHASE_DONE,
+	})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	logs := store.NewInMemoryLogStore()
+	w, err := logs.Open(jobName)
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	_, _ = io.WriteString(w, "[build|PHASE] Building\n[foo] hello\n[foo|DONE]\n")
+	w.Close()
+
+	srv := testService(werft.AnonymousFull)
+	srv.Jobs = jobs
+	srv.Logs = logs
+
+	const job = `{"name":"werft-build-master.1","metadata":{"owner":"alice","repository":{"host":"github.com","owner":"32leaves","repo":"werft"}},"phase":"PHASE_DONE"}`
+	tests := []struct {
+		Name        string
+		Query       string
+		Status      int
+		Expectation []sseEvent
+	}{
+		{
+			Name:   "log and update",
+			Status: http.StatusOK,
+			Expectation: []sseEvent{
+				{"23:build", "slice", `{"name":"build","type":"SLICE_PHASE","payload":"Building","cursor":"23:build"}`},
+				{"23:build", "slice", `{"name":"foo","type":"SLICE_START","cursor":"23:build"}`},
+				{"35:build", "slice", `{"name":"foo","type":"SLICE_CONTENT","payload":"hello","cursor":"35:build"}`},
+				{"46:build", "slice", `{"name":"foo","type":"SLICE_DONE","cursor":"46:build"}`},
+				{"", "job", job},
+			},
+		},
+		{
+			Name:   "resume and no updates",
+			Query:  "cursor=35:build&updates=false",
+			Status: http.StatusOK,
+			Expectation: []sseEvent{
+				{"46:build", "slice", `{"name":"foo","type":"SLICE_DONE","cursor":"46:build"}`},
+			},
+		},
+		{
+			Name:   "matching filter",
+			Query:  "logs=disabled&filter=phase==done&filter=repo.owner==32leaves,repo.owner==someone-else",
+			Status: http.StatusOK,
+			Expectation: []sseEvent{
+				{"", "job", job},
+			},
+		},
+		{
+			Name:   "filter which does not match",
+			Query:  "logs=disabled&filter=phase==done&filter=repo.owner==someone-else",
+			Status: http.StatusOK,
+		},
+		{
+			Name:   "invalid filter",
+			Query:  "filter=phase",
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "unknown logs mode",
+			Query:  "logs=colorful",
+			Status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/listen/"+jobName+"?"+test.Query, nil)
+			rec := httptest.NewRecorder()
+			srv.HandleSSEListen(rec, req)
+
+			if rec.Code != test.Status {
+				t.Fatalf("expected status %d, actual %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			if test.Status != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); len(test.Expectation) > 0 && ct != "text/event-stream" {
+				t.Errorf("expected content type text/event-stream, actual %q", ct)
+			}
+
+			act := readSSEEvents(t, rec.Body, -1)
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %+v, actual %+v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestHandleSSEListenUnknownJob(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	srv.Jobs = store.NewInMemoryJobStore()
+	srv.Logs = store.NewInMemoryLogStore()
+
+	rec := httptest.NewRecorder()
+	srv.HandleSSEListen(rec, httptest.NewRequest("GET", "/api/v1/listen/does-not-exist.1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, actual %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.HandleSSEListen(rec, httptest.NewRequest("GET", "/api/v1/listen/", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, actual %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandleSSESubscribe(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	hs := httptest.NewServer(http.HandlerFunc(srv.HandleSSESubscribe))
+	defer hs.Close()
+
+	// we cannot tell when the subscription is in place, hence we keep sending jobs - each time one the filter rejects first.
+	// The response headers only arrive with the first event, so we must start sending before we subscribe.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			srv.EmitJob(&v1.JobStatus{Name: "other.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Repository: &v1.Repository{Repo: "other"}}})
+			srv.EmitJob(&v1.JobStatus{Name: "werft.1", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{Repository: testRepo()}})
+			srv.EmitJob(&v1.JobStatus{Name: "werft.2", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Repository: testRepo()}})
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	resp, err := http.Get(hs.URL + "/api/v1/events?filter=phase==running&filter=repo.repo==werft,repo.repo==leeway")
+	if err != nil {
+		t.Fatalf("cannot subscribe: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected content type text/event-stream, actual %q", ct)
+	}
+	evts := readSSEEvents(t, resp.Body, 2)
+	for _, evt := range evts {
+		if evt.Event != "job" || !strings.Contains(evt.Data, `"name":"werft.2"`) {
+			t.Errorf("unexpected event: %+v", evt)
+		}
+	}
+}
+
+func TestHandleSSESubscribeInvalidFilter(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	rec := httptest.NewRecorder()
+	srv.HandleSSESubscribe(rec, httptest.NewRequest("GET", "/api/v1/events?filter=phase", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, actual %d", http.StatusBadRequest, rec.Code)
+	}
+}
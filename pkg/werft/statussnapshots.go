@@ -0,0 +1,168 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/golang/protobuf/ptypes"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	// statusSlice is the name of the log slices status snapshots are rendered as for listeners which do not want them structured
+	statusSlice = "werft:status"
+)
+
+// statusReplay sends the status snapshots of a job to a listener, interleaved with its log. The snapshots come from
+// the event trace rather than the log itself, s.t. the log holds nothing but what the job wrote.
+type statusReplay struct {
+	srv  *Service
+	req  *v1.ListenRequest
+	send func(*v1.ListenResponse) error
+
+	pending []*v1.JobStatusSnapshot
+	last    time.Time
+	// resumed skips the snapshots which precede the first log line sent, because the listener has seen them before
+	resumed bool
+}
+
+// newStatusReplay loads the status snapshots of a job recorded so far. Returns nil if the listener does not want them.
+func (srv *Service) newStatusReplay(ctx context.Context, req *v1.ListenRequest, resumed bool, send func(*v1.ListenResponse) error) (*statusReplay, error) {
+	if req.Status == v1.ListenRequestStatus_STATUS_DISABLED {
+		return nil, nil
+	}
+
+	r := &statusReplay{srv: srv, req: req, send: send, resumed: resumed}
+	err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load appends the snapshots recorded after the last one sent or pending to the pending ones
+func (r *statusReplay) load(ctx context.Context) error {
+	if r.srv.Events == nil {
+		return nil
+	}
+
+	after := r.last
+	if len(r.pending) > 0 {
+		after, _ = ptypes.Timestamp(r.pending[len(r.pending)-1].Time)
+	}
+	trace, err := r.srv.Events.Find(ctx, r.req.Name, after, time.Time{}, 0)
+	if err != nil {
+		return err
+	}
+	for _, evt := range trace {
+		if evt.Status == nil {
+			continue
+		}
+		if t, err := ptypes.Timestamp(evt.Time); err != nil || !t.After(after) {
+			continue
+		}
+		r.pending = append(r.pending, &v1.JobStatusSnapshot{Time: evt.Time, Status: evt.Status})
+	}
+	return nil
+}
+
+// Until sends the pending snapshots taken no later than a log line written at t
+func (r *statusReplay) Until(t *tspb.Timestamp) error {
+	if r == nil || t == nil {
+		return nil
+	}
+	until, err := ptypes.Timestamp(t)
+	if err != nil {
+		return nil
+	}
+
+	var i int
+	for ; i < len(r.pending); i++ {
+		st, _ := ptypes.Timestamp(r.pending[i].Time)
+		if st.After(until) {
+			break
+		}
+		if r.resumed {
+			continue
+		}
+		err = r.sendSnapshot(r.pending[i])
+		if err != nil {
+			return err
+		}
+	}
+	r.pending = r.pending[i:]
+	r.resumed = false
+	return nil
+}
+
+// Live sends the pending snapshots, followed by the current status of the job
+func (r *statusReplay) Live(s *v1.JobStatus) error {
+	if r == nil {
+		return nil
+	}
+
+	err := r.flush()
+	if err != nil {
+		return err
+	}
+	return r.sendSnapshot(&v1.JobStatusSnapshot{Time: ptypes.TimestampNow(), Status: s})
+}
+
+// Rest sends all snapshots not sent yet, including those recorded since the replay started
+func (r *statusReplay) Rest(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	return r.flush()
+}
+
+func (r *statusReplay) flush() error {
+	for len(r.pending) > 0 {
+		err := r.sendSnapshot(r.pending[0])
+		if err != nil {
+			return err
+		}
+		r.pending = r.pending[1:]
+	}
+	return nil
+}
+
+func (r *statusReplay) sendSnapshot(snapshot *v1.JobStatusSnapshot) error {
+	if t, err := ptypes.Timestamp(snapshot.Time); err == nil && t.After(r.last) {
+		r.last = t
+	}
+
+	if r.req.Status == v1.ListenRequestStatus_STATUS_STRUCTURED {
+		return r.send(&v1.ListenResponse{
+			Content: &v1.ListenResponse_Status{Status: snapshot},
+		})
+	}
+
+	// Listeners which do not know about snapshots get the status as part of the log, the way werft used to write it
+	// there. Those slices carry no cursor as they have no place in the log.
+	payload, err := json.Marshal(snapshot.Status)
+	if err != nil {
+		return err
+	}
+	evt := &v1.LogSliceEvent{
+		Name:    statusSlice,
+		Type:    v1.LogSliceType_SLICE_CONTENT,
+		Payload: string(payload),
+		Time:    snapshot.Time,
+	}
+	if r.req.Logs == v1.ListenRequestLogs_LOGS_UNSLICED {
+		evt.Name = logcutter.DefaultSlice
+		evt.Payload = "[" + statusSlice + "] " + evt.Payload + "\n"
+	}
+	return r.send(&v1.ListenResponse{
+		Content: &v1.ListenResponse_Slice{Slice: evt},
+	})
+}
@@ -0,0 +1,464 @@
+package werft
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// tarballInitImage downloads and unpacks the tarball in the init container of a job
+	tarballInitImage = "alpine:3.12.1"
+
+	// tarballTimeout is how long we wait for a tarball to download
+	tarballTimeout = 5 * time.Minute
+
+	// defaultMaxTarballSize limits the size of the tarballs we download unless configured otherwise
+	defaultMaxTarballSize = 512 << 20
+
+	// maxTarballFileSize limits the size of the files we read from a tarball, e.g. its werft config
+	maxTarballFileSize = 10 << 20
+
+	// tarballSecretPrefix is prepended to the name of the job whose tarball URL the secret holds
+	tarballSecretPrefix = "werft-tarball-"
+
+	// tarballSecretKey is the key of the tarball URL in its secret
+	tarballSecretKey = "url"
+)
+
+// tarballClient downloads tarballs. Whoever starts a job chooses what we download, hence we must not connect to
+// what only werft can reach, e.g. the metadata service of the cloud provider or other services in the cluster.
+// We check the address when we connect rather than the URL, s.t. neither redirects nor DNS can get around that.
+var tarballClient = &http.Client{
+	Timeout: tarballTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+			Control: refuseNonPublicAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+// nonPublicNetworks are the networks tarballClient refuses to connect to
+var nonPublicNetworks = func() []*net.IPNet {
+	var res []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"224.0.0.0/3",
+		"::/128",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, n)
+	}
+	return res
+}()
+
+// refuseNonPublicAddress fails if a connection is about to be made to an address which is not public
+func refuseNonPublicAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return xerrors.Errorf("cannot connect to %s: not an IP address", host)
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return xerrors.Errorf("cannot connect to %s: address is not public", ip)
+		}
+	}
+	return nil
+}
+
+// tarballSecretName returns the name of the secret which holds the tarball URL of a job
+func tarballSecretName(jobName string) string {
+	name := tarballSecretPrefix + strings.Trim(invalidSecretNameChars.ReplaceAllString(jobName, "-"), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return name
+}
+
+// TarballContentProvider provides access to the content of a gzipped tarball available via HTTP(S)
+type TarballContentProvider struct {
+	URL             string
+	SHA256          string
+	StripComponents int
+
+	// Secret names the secret the init container gets the URL from. URLs are often presigned, hence we must not
+	// put them in the pod spec.
+	Secret string
+
+	// Client downloads the tarball. Defaults to a client which only connects to public addresses.
+	Client *http.Client
+	// MaxSize limits the size of the tarball in bytes. Defaults to 512 MiB.
+	MaxSize int64
+
+	mu         sync.Mutex
+	downloaded *os.File
+	size       int64
+}
+
+// tarballURL validates the URL of a tarball and turns s3://bucket/key URLs into HTTPS ones
+func tarballURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return u.String(), nil
+	case "s3":
+		if u.Host == "" {
+			return "", xerrors.Errorf("s3 URL has no bucket")
+		}
+		return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", u.Host, strings.TrimPrefix(u.Path, "/")), nil
+	default:
+		return "", xerrors.Errorf("unsupported URL scheme \"%s\"", u.Scheme)
+	}
+}
+
+// InitContainer builds the container that will initialize the job content.
+func (tcp *TarballContentProvider) InitContainer() (*corev1.Container, error) {
+	if tcp.Secret == "" {
+		return nil, xerrors.Errorf("tarball jobs need a secret for the URL")
+	}
+
+	cmd := "set -e; wget -q -O /tmp/content.tar.gz \"$TARBALL_URL_SECRET\"; "
+	if tcp.SHA256 != "" {
+		cmd += fmt.Sprintf("echo \"%s  /tmp/content.tar.gz\" | sha256sum -c -; ", tcp.SHA256)
+	}
+	cmd += "tar xzf /tmp/content.tar.gz"
+	if tcp.StripComponents > 0 {
+		cmd += fmt.Sprintf(" --strip-components %d", tcp.StripComponents)
+	}
+
+	return &corev1.Container{
+		Image:   tarballInitImage,
+		Command: []string{"sh", "-c", cmd},
+		Env: []corev1.EnvVar{
+			{
+				Name: "TARBALL_URL_SECRET",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: tcp.Secret},
+						Key:                  tarballSecretKey,
+					},
+				},
+			},
+		},
+		WorkingDir: "/workspace",
+	}, nil
+}
+
+// Serve provides additional services required during initialization.
+func (tcp *TarballContentProvider) Serve(jobName string) error {
+	return nil
+}
+
+// fetch downloads the tarball and verifies its checksum. We download it only once, no matter how many files we read from it.
+func (tcp *TarballContentProvider) fetch(ctx context.Context) (io.ReaderAt, int64, error) {
+	tcp.mu.Lock()
+	defer tcp.mu.Unlock()
+	if tcp.downloaded != nil {
+		return tcp.downloaded, tcp.size, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, tcp.URL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	client := tcp.Client
+	if client == nil {
+		client = tarballClient
+	}
+	maxSize := tcp.MaxSize
+	if maxSize == 0 {
+		maxSize = defaultMaxTarballSize
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, xerrors.Errorf("cannot download tarball: %s", resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, 0, xerrors.Errorf("tarball exceeds %d bytes", maxSize)
+	}
+
+	f, err := ioutil.TempFile("", "werft-tarball-")
+	if err != nil {
+		return nil, 0, err
+	}
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(resp.Body, maxSize+1))
+	if err == nil && n > maxSize {
+		err = xerrors.Errorf("tarball exceeds %d bytes", maxSize)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); err == nil && tcp.SHA256 != "" && !strings.EqualFold(sum, tcp.SHA256) {
+		err = xerrors.Errorf("tarball checksum mismatch: expected %s, got %s", tcp.SHA256, sum)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, 0, err
+	}
+
+	tcp.downloaded, tcp.size = f, n
+	return f, n, nil
+}
+
+// Download provides access to a single file. We have to download and verify the whole tarball for this.
+func (tcp *TarballContentProvider) Download(ctx context.Context, fn string) (io.ReadCloser, error) {
+	tarball, size, err := tcp.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(io.NewSectionReader(tarball, 0, size))
+	if err != nil {
+		return nil, xerrors.Errorf("tarball is not gzipped: %w", err)
+	}
+
+	fn = path.Clean(strings.TrimPrefix(fn, "/"))
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, xerrors.Errorf("%s not found in tarball", fn)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("invalid tarball: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		segs := strings.Split(path.Clean(strings.TrimPrefix(hdr.Name, "./")), "/")
+		if len(segs) <= tcp.StripComponents || path.Join(segs[tcp.StripComponents:]...) != fn {
+			continue
+		}
+		// the size in the header is whatever the tarball claims, hence we limit what we read regardless
+		content, err := ioutil.ReadAll(io.LimitReader(tr, maxTarballFileSize+1))
+		if err != nil {
+			return nil, xerrors.Errorf("invalid tarball: %w", err)
+		}
+		if len(content) > maxTarballFileSize {
+			return nil, xerrors.Errorf("%s exceeds %d bytes", fn, maxTarballFileSize)
+		}
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+}
+
+// Close removes the downloaded tarball
+func (tcp *TarballContentProvider) Close() error {
+	tcp.mu.Lock()
+	defer tcp.mu.Unlock()
+	if tcp.downloaded == nil {
+		return nil
+	}
+
+	f := tcp.downloaded
+	tcp.downloaded = nil
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// createTarballSecret creates the secret which holds the tarball URL of a job
+func (srv *Service) createTarballSecret(jobName, u string) (string, error) {
+	name := tarballSecretName(jobName)
+	_, err := srv.Executor.Client.CoreV1().Secrets(srv.Executor.Config.Namespace).Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{executor.LabelJobName: jobName},
+		},
+		StringData: map[string]string{tarballSecretKey: u},
+	})
+	if err != nil {
+		return "", xerrors.Errorf("cannot create tarball secret: %w", err)
+	}
+	return name, nil
+}
+
+// ownTarballSecret makes the pod of a job own the secret which holds its tarball URL, s.t. Kubernetes deletes the
+// secret along with the pod
+func (srv *Service) ownTarballSecret(jobName, secret string) error {
+	ns := srv.Executor.Config.Namespace
+	pod, err := srv.Executor.Client.CoreV1().Pods(ns).Get(jobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	client := srv.Executor.Client.CoreV1().Secrets(ns)
+	sec, err := client.Get(secret, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	sec.OwnerReferences = append(sec.OwnerReferences, metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	})
+	_, err = client.Update(sec)
+	return err
+}
+
+// StartTarballJob starts a job on the content of a gzipped tarball
+func (srv *Service) StartTarballJob(ctx context.Context, req *v1.StartTarballJobRequest) (*v1.StartJobResponse, error) {
+	if req.IdempotencyKey != "" {
+		return srv.startIdempotent(ctx, "tarball/"+req.IdempotencyKey, func() (*v1.StartJobResponse, error) {
+			r := *req
+			r.IdempotencyKey = ""
+			return srv.StartTarballJob(ctx, &r)
+		})
+	}
+
+	md := req.Metadata
+	if md == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	u, err := tarballURL(req.Url)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid URL: %v", err)
+	}
+	if _, err := hex.DecodeString(req.Sha256); err != nil || (req.Sha256 != "" && len(req.Sha256) != 2*sha256.Size) {
+		return nil, status.Error(codes.InvalidArgument, "sha256 must be a hex encoded SHA-256 checksum")
+	}
+	if req.StripComponents < 0 {
+		return nil, status.Error(codes.InvalidArgument, "strip components must not be negative")
+	}
+	if md.Repository == nil {
+		// the tarball is the repository
+		pu, _ := url.Parse(u)
+		md.Repository = &v1.Repository{
+			Host: pu.Host,
+			Repo: strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(path.Base(pu.Path), ".tgz"), ".gz"), ".tar"),
+		}
+	}
+	if err := srv.authorizeStart(ctx, md); err != nil {
+		return nil, err
+	}
+	if err := srv.checkRateLimit(ctx, md); err != nil {
+		return nil, err
+	}
+	if err := srv.checkAcceptsJobs(ctx, md); err != nil {
+		return nil, err
+	}
+	if err := srv.checkRepositoryPolicy(ctx, md); err != nil {
+		return nil, err
+	}
+
+	cp := &TarballContentProvider{
+		URL:             u,
+		SHA256:          req.Sha256,
+		StripComponents: int(req.StripComponents),
+	}
+	defer cp.Close()
+
+	var (
+		jobYAML     = req.JobYaml
+		tplpath     = req.JobPath
+		jobSpecName = "custom"
+	)
+	if jobYAML == nil {
+		if tplpath == "" {
+			repoCfg, err := getRepoCfg(ctx, cp)
+			if err != nil {
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+			tplpath = repoCfg.TemplatePath(md)
+		}
+
+		in, err := cp.Download(ctx, tplpath)
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		jobYAML, err = ioutil.ReadAll(in)
+		in.Close()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if tplpath != "" {
+		jobSpecName = strings.TrimSuffix(filepath.Base(tplpath), filepath.Ext(tplpath))
+		md.JobSpec = jobSpecName
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(fmt.Sprintf("%s-%s-tarball", md.Repository.Repo, jobSpecName)))
+	nr, err := srv.Groups.Next(ctx, name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	name = fmt.Sprintf("%s.%d", name, nr)
+
+	cp.Secret, err = srv.createTarballSecret(name, u)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// We cannot replay tarball jobs because StartFromPreviousJob restarts jobs from GitHub
+	jobStatus, err := srv.RunJob(ctx, name, *md, cp, jobYAML, false)
+	if err != nil {
+		derr := srv.Executor.Client.CoreV1().Secrets(srv.Executor.Config.Namespace).Delete(cp.Secret, &metav1.DeleteOptions{})
+		if derr != nil {
+			log.WithError(derr).WithField("name", name).Warn("cannot delete tarball secret")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = srv.ownTarballSecret(name, cp.Secret)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot attach tarball secret to its job - it will not be deleted with the job")
+	}
+
+	log.WithField("status", jobStatus).Info(("started new tarball job"))
+	return &v1.StartJobResponse{
+		Status: jobStatus,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package werft_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+// testTarball produces a gzipped tarball of the files
+func testTarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatalf("cannot produce tarball: %v", err)
+		}
+		_, err = tw.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("cannot produce tarball: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("cannot produce tarball: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("cannot produce tarball: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTarballContentProviderDownload(t *testing.T) {
+	tarball := testTarball(t, map[string]string{
+		"werft-main/.werft/config.yaml": "defaultJob: build",
+		"werft-main/.werft/build.yaml":  "pod: {}",
+		"werft-main/huge.txt":           strings.Repeat("x", 11<<20),
+	})
+	sum := sha256.Sum256(tarball)
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		Name            string
+		SHA256          string
+		StripComponents int
+		MaxSize         int64
+		Files           []string
+		Expectation     []string
+		Error           string
+	}{
+		{Name: "files", StripComponents: 1, Files: []string{".werft/config.yaml", "/.werft/build.yaml"}, Expectation: []string{"defaultJob: build", "pod: {}"}},
+		{Name: "without strip components", Files: []string{"werft-main/.werft/config.yaml"}, Expectation: []string{"defaultJob: build"}},
+		{Name: "checksum", SHA256: strings.ToUpper(checksum), StripComponents: 1, Files: []string{".werft/config.yaml"}, Expectation: []string{"defaultJob: build"}},
+		{Name: "checksum mismatch", SHA256: strings.Repeat("0", 64), StripComponents: 1, Files: []string{".werft/config.yaml"}, Error: "checksum mismatch"},
+		{Name: "oversized tarball", MaxSize: int64(len(tarball) - 1), StripComponents: 1, Files: []string{".werft/config.yaml"}, Error: "tarball exceeds"},
+		{Name: "tarball of max size", MaxSize: int64(len(tarball)), StripComponents: 1, Files: []string{".werft/config.yaml"}, Expectation: []string{"defaultJob: build"}},
+		{Name: "oversized file", StripComponents: 1, Files: []string{"huge.txt"}, Error: "huge.txt exceeds"},
+		{Name: "missing file", StripComponents: 1, Files: []string{".werft/other.yaml"}, Error: "not found"},
+	}
+
+	for _, test := range tests {
+		for _, chunked := range []bool{false, true} {
+			name := test.Name
+			if chunked {
+				// without content length we only learn about the size of the tarball while we download it
+				name += " without content length"
+			}
+			t.Run(name, func(t *testing.T) {
+				var requests int32
+				hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&requests, 1)
+					if chunked {
+						w.Write(tarball[:10])
+						w.(http.Flusher).Flush()
+						w.Write(tarball[10:])
+						return
+					}
+					http.ServeContent(w, r, "content.tar.gz", time.Time{}, bytes.NewReader(tarball))
+				}))
+				defer hs.Close()
+
+				cp := &werft.TarballContentProvider{
+					URL:             hs.URL + "/content.tar.gz",
+					SHA256:          test.SHA256,
+					StripComponents: test.StripComponents,
+					MaxSize:         test.MaxSize,
+					Client:          hs.Client(),
+				}
+				defer cp.Close()
+
+				var act []string
+				for _, fn := range test.Files {
+					r, err := cp.Download(context.Background(), fn)
+					if err != nil {
+						if test.Error == "" || !strings.Contains(err.Error(), test.Error) {
+							t.Fatalf("unexpected error: %v", err)
+						}
+						return
+					}
+					content, _ := ioutil.ReadAll(r)
+					r.Close()
+					act = append(act, string(content))
+				}
+				if test.Error != "" {
+					t.Fatalf("expected error containing %q", test.Error)
+				}
+				if strings.Join(act, "\n") != strings.Join(test.Expectation, "\n") {
+					t.Errorf("expected %q, actual %q", test.Expectation, act)
+				}
+				if requests != 1 {
+					t.Errorf("downloaded the tarball %d times", requests)
+				}
+			})
+		}
+	}
+}
+
+func TestTarballContentProviderRefusesPrivateAddresses(t *testing.T) {
+	var requests int32
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer hs.Close()
+
+	// the test server listens on a loopback address
+	cp := &werft.TarballContentProvider{URL: hs.URL + "/content.tar.gz"}
+	defer cp.Close()
+	_, err := cp.Download(context.Background(), ".werft/config.yaml")
+	if err == nil || !strings.Contains(err.Error(), "not public") {
+		t.Errorf("expected the download to be refused, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("the tarball was requested from a private address")
+	}
+}
+
+func TestTarballContentProviderInitContainer(t *testing.T) {
+	const u = "https://example.com/content.tar.gz?X-Amz-Signature=secret"
+	cp := &werft.TarballContentProvider{URL: u, Secret: "werft-tarball-foo.1"}
+	c, err := cp.InitContainer()
+	if err != nil {
+		t.Fatalf("cannot build init container: %v", err)
+	}
+	if strings.HasSuffix(c.Image, ":latest") || !strings.Contains(c.Image, ":") {
+		t.Errorf("init container image %s is not pinned", c.Image)
+	}
+	for _, e := range c.Env {
+		if strings.Contains(e.Value, u) {
+			t.Errorf("init container passes the URL in plain text as %s", e.Name)
+		}
+		if e.Name == "TARBALL_URL_SECRET" && (e.ValueFrom == nil || e.ValueFrom.SecretKeyRef == nil || e.ValueFrom.SecretKeyRef.Name != cp.Secret) {
+			t.Errorf("init container does not take the URL from the secret: %+v", e)
+		}
+	}
+
+	_, err = (&werft.TarballContentProvider{URL: u}).InitContainer()
+	if err == nil {
+		t.Errorf("init container without secret did not fail")
+	}
+}
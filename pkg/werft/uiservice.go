@@ -142,3 +142,12 @@ func (uis *UIService) ListJobSpecs(req *v1.ListJobSpecsRequest, srv v1.WerftUI_L
 
 	return nil
 }
+
+// SetRepos changes the repositories whose job specs the UI offers and updates the job specs
+func (uis *UIService) SetRepos(repos []string) error {
+	uis.mu.Lock()
+	uis.Repos = repos
+	uis.mu.Unlock()
+
+	return uis.updateJobSpecs()
+}
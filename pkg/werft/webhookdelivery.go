@@ -0,0 +1,309 @@
+package werft
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultWebhookDeliveryRetention is how long we keep webhook deliveries if the config does not say otherwise
+	defaultWebhookDeliveryRetention = 72 * time.Hour
+
+	// webhookDeliveryPruneInterval is how often we remove webhook deliveries which left the retention window
+	webhookDeliveryPruneInterval = 1 * time.Hour
+
+	// maxWebhookPayloadSize is the largest webhook we accept. GitHub caps payloads at 25 MB.
+	maxWebhookPayloadSize = 25 << 20
+
+	// rejectedDeliveriesPerMinute and rejectedDeliveriesBurst limit how many deliveries without a valid signature we
+	// record, s.t. anyone who can reach the webhook endpoint cannot fill the store
+	rejectedDeliveriesPerMinute = 10
+	rejectedDeliveriesBurst     = 10
+)
+
+// WebhookDeliveriesConfig configures how long werft keeps the webhooks it received
+type WebhookDeliveriesConfig struct {
+	// RetentionHours is how long deliveries are kept in hours. Defaults to 72.
+	RetentionHours int `yaml:"retentionHours,omitempty"`
+}
+
+// Retention returns how long deliveries are kept
+func (c WebhookDeliveriesConfig) Retention() time.Duration {
+	if c.RetentionHours == 0 {
+		return defaultWebhookDeliveryRetention
+	}
+	return time.Duration(c.RetentionHours) * time.Hour
+}
+
+// webhookOutcome is what became of a webhook: the jobs it started, why it started none, or what went wrong
+type webhookOutcome struct {
+	Jobs       []string
+	SkipReason string
+	Errors     []string
+}
+
+// skipWebhook produces the outcome of a webhook which does not start a job
+func skipWebhook(reason string) webhookOutcome {
+	return webhookOutcome{SkipReason: reason}
+}
+
+// Add merges the outcome of another part of the same webhook, e.g. one of several jobs a push starts
+func (o *webhookOutcome) Add(other webhookOutcome) {
+	o.Jobs = append(o.Jobs, other.Jobs...)
+	o.Errors = append(o.Errors, other.Errors...)
+	if o.SkipReason == "" {
+		o.SkipReason = other.SkipReason
+	}
+}
+
+// Started records the outcome of starting a job
+func (o *webhookOutcome) Started(resp *v1.StartJobResponse, err error) {
+	switch {
+	case err != nil:
+		o.Errors = append(o.Errors, err.Error())
+	case resp == nil || resp.Status == nil:
+		// the job was queued because werft is in maintenance
+		o.SkipReason = "queued because werft is in maintenance"
+	default:
+		o.Jobs = append(o.Jobs, resp.Status.Name)
+	}
+}
+
+// newWebhookDelivery describes an incoming webhook by its metadata only. Until its signature is verified anyone could
+// have sent it, hence its headers and payload are added using setWebhookHeaders and setWebhookPayload once it is.
+func newWebhookDelivery(r *http.Request, body []byte) *store.WebhookDelivery {
+	h := sha256.Sum256(body)
+	return &store.WebhookDelivery{
+		ID:          newWebhookDeliveryID(),
+		DeliveryID:  github.DeliveryID(r),
+		Received:    time.Now(),
+		Event:       github.WebHookType(r),
+		PayloadSize: len(body),
+		Digest:      hex.EncodeToString(h[:]),
+	}
+}
+
+// setWebhookHeaders stores the headers of a delivery
+func setWebhookHeaders(d *store.WebhookDelivery, r *http.Request) {
+	headers := make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+	d.Headers = headers
+}
+
+// newWebhookDeliveryID produces a random ID for a delivery
+func newWebhookDeliveryID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// setWebhookPayload stores the payload of a delivery and the repository and ref it is about
+func setWebhookPayload(d *store.WebhookDelivery, payload []byte) {
+	d.Payload = payload
+	d.PayloadSize = len(payload)
+	h := sha256.Sum256(payload)
+	d.Digest = hex.EncodeToString(h[:])
+
+	var p struct {
+		Ref        string `json:"ref"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if json.Unmarshal(payload, &p) == nil {
+		d.Repository = p.Repository.FullName
+		d.Ref = p.Ref
+	}
+}
+
+// recordWebhookDelivery stores a delivery together with its outcome. Deliveries without a valid signature are recorded
+// only as long as they stay within the rejected deliveries limit.
+func (srv *Service) recordWebhookDelivery(d *store.WebhookDelivery, outcome webhookOutcome, err error) {
+	if srv.WebhookDeliveries == nil {
+		return
+	}
+	if !d.Verified && !srv.mayRecordRejectedDelivery() {
+		log.WithField("delivery", d.DeliveryID).Debug("not recording webhook delivery without valid signature: too many of them")
+		return
+	}
+
+	d.Jobs = outcome.Jobs
+	d.SkipReason = outcome.SkipReason
+	errs := outcome.Errors
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	d.Error = strings.Join(errs, "; ")
+	if len(d.Jobs) == 0 && d.SkipReason == "" && d.Error == "" {
+		d.SkipReason = "no job was started"
+	}
+
+	err = srv.WebhookDeliveries.Add(context.Background(), *d)
+	if err != nil {
+		log.WithError(err).WithField("delivery", d.DeliveryID).Warn("cannot record webhook delivery")
+	}
+}
+
+// mayRecordRejectedDelivery returns true if another delivery without a valid signature may be recorded
+func (srv *Service) mayRecordRejectedDelivery() bool {
+	srv.mu.Lock()
+	if srv.rejectedDeliveries == nil {
+		srv.rejectedDeliveries = rate.NewLimiter(rate.Every(time.Minute/rejectedDeliveriesPerMinute), rejectedDeliveriesBurst)
+	}
+	lim := srv.rejectedDeliveries
+	srv.mu.Unlock()
+
+	return lim.Allow()
+}
+
+// pruneWebhookDeliveries periodically removes webhook deliveries which left the retention window
+func (srv *Service) pruneWebhookDeliveries() {
+	tick := time.NewTicker(webhookDeliveryPruneInterval)
+	defer tick.Stop()
+	for {
+		retention := srv.config().WebhookDeliveries.Retention()
+		pruned, err := srv.WebhookDeliveries.Prune(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.WithError(err).Warn("cannot prune webhook deliveries")
+		}
+		if pruned > 0 {
+			log.WithField("count", pruned).Debug("pruned webhook deliveries")
+		}
+		<-tick.C
+	}
+}
+
+// webhookDeliveries returns the webhook delivery store or an error if there is none
+func (srv *Service) webhookDeliveries(ctx context.Context) (store.WebhookDeliveries, error) {
+	if err := srv.authorize(ctx, ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if srv.WebhookDeliveries == nil {
+		return nil, status.Error(codes.Unimplemented, "this werft installation does not record webhook deliveries")
+	}
+	return srv.WebhookDeliveries, nil
+}
+
+// ListWebhookDeliveries lists the webhooks werft received, newest first
+func (srv *Service) ListWebhookDeliveries(ctx context.Context, req *v1.ListWebhookDeliveriesRequest) (*v1.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := srv.webhookDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	ds, err := deliveries.List(ctx, store.WebhookDeliveryFilter{
+		Repository:  req.Repository,
+		WithoutJobs: req.WithoutJobs,
+	}, int(req.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := make([]*v1.WebhookDelivery, len(ds))
+	for i, d := range ds {
+		res[i] = webhookDeliveryToProto(d)
+	}
+	return &v1.ListWebhookDeliveriesResponse{Deliveries: res}, nil
+}
+
+// GetWebhookDelivery returns a webhook delivery including its payload
+func (srv *Service) GetWebhookDelivery(ctx context.Context, req *v1.GetWebhookDeliveryRequest) (*v1.GetWebhookDeliveryResponse, error) {
+	deliveries, err := srv.webhookDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := deliveries.Get(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "webhook delivery not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetWebhookDeliveryResponse{
+		Delivery: webhookDeliveryToProto(d),
+		Payload:  d.Payload,
+	}, nil
+}
+
+// RedeliverWebhook processes the payload of a webhook delivery again, e.g. because werft failed to process it or the
+// repo config was broken at the time. Unlike redeliveries from GitHub, this always starts new jobs.
+func (srv *Service) RedeliverWebhook(ctx context.Context, req *v1.RedeliverWebhookRequest) (*v1.RedeliverWebhookResponse, error) {
+	deliveries, err := srv.webhookDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orig, err := deliveries.Get(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "webhook delivery not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	// we must not act on payloads anyone could have sent us
+	if !orig.Verified {
+		return nil, status.Error(codes.FailedPrecondition, "only deliveries with a valid signature can be redelivered")
+	}
+	event, err := github.ParseWebHook(orig.Event, orig.Payload)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot parse delivery: %v", err)
+	}
+
+	d := &store.WebhookDelivery{
+		ID:           newWebhookDeliveryID(),
+		DeliveryID:   orig.DeliveryID,
+		Received:     time.Now(),
+		Event:        orig.Event,
+		Headers:      orig.Headers,
+		Verified:     true,
+		RedeliveryOf: orig.ID,
+	}
+	setWebhookPayload(d, orig.Payload)
+
+	// the ID of the redelivery keeps the jobs it starts apart from those of the original delivery
+	outcome, _ := srv.processWebhook(event, orig.Payload, d.ID)
+	srv.recordWebhookDelivery(d, outcome, nil)
+	log.WithField("delivery", orig.ID).WithField("redelivery", d.ID).WithField("jobs", d.Jobs).Info("redelivered webhook")
+
+	return &v1.RedeliverWebhookResponse{Delivery: webhookDeliveryToProto(d)}, nil
+}
+
+func webhookDeliveryToProto(d *store.WebhookDelivery) *v1.WebhookDelivery {
+	received, _ := ptypes.TimestampProto(d.Received)
+	return &v1.WebhookDelivery{
+		Id:           d.ID,
+		DeliveryId:   d.DeliveryID,
+		Received:     received,
+		Event:        d.Event,
+		Repository:   d.Repository,
+		Ref:          d.Ref,
+		Headers:      d.Headers,
+		Digest:       d.Digest,
+		PayloadSize:  int64(d.PayloadSize),
+		Verified:     d.Verified,
+		Jobs:         d.Jobs,
+		SkipReason:   d.SkipReason,
+		Error:        d.Error,
+		RedeliveryOf: d.RedeliveryOf,
+	}
+}
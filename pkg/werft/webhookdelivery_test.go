@@ -0,0 +1,201 @@
+package werft_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testWebhookPayload = `{"zen":"Keep it logically awesome.","repository":{"name":"werft","full_name":"32leaves/werft","owner":{"login":"32leaves"}}}`
+
+// testWebhook produces a webhook request of an event signed with the secret
+func testWebhook(event, body, secret string) *http.Request {
+	req := httptest.NewRequest("POST", "/plugins/github", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "delivery-id")
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(body))
+	req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestHandleGithubWebhookRecordsDeliveries(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Body         string
+		Secret       string
+		Deliveries   int
+		Recorded     int
+		Verified     bool
+		ExpectStatus int
+	}{
+		{
+			Name:         "verified delivery",
+			Body:         testWebhookPayload,
+			Secret:       "secret",
+			Deliveries:   1,
+			Recorded:     1,
+			Verified:     true,
+			ExpectStatus: http.StatusInternalServerError,
+		},
+		{
+			Name:         "invalid signature",
+			Body:         testWebhookPayload,
+			Secret:       "not-the-secret",
+			Deliveries:   1,
+			Recorded:     1,
+			ExpectStatus: http.StatusInternalServerError,
+		},
+		{
+			Name:         "invalid signatures are rate limited",
+			Body:         testWebhookPayload,
+			Secret:       "not-the-secret",
+			Deliveries:   25,
+			Recorded:     10,
+			ExpectStatus: http.StatusInternalServerError,
+		},
+		{
+			Name:         "oversized payload",
+			Body:         fmt.Sprintf(`{"zen":"%s"}`, bytes.Repeat([]byte("a"), 26<<20)),
+			Secret:       "secret",
+			Deliveries:   1,
+			Recorded:     0,
+			ExpectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			deliveries := store.NewInMemoryWebhookDeliveries()
+			srv := &werft.Service{
+				Repositories:      store.NewInMemoryRepositories(),
+				WebhookDeliveries: deliveries,
+				GitHub:            werft.GitHubSetup{WebhookSecret: []byte("secret")},
+			}
+
+			var status int
+			for i := 0; i < test.Deliveries; i++ {
+				rec := httptest.NewRecorder()
+				srv.HandleGithubWebhook(rec, testWebhook("ping", test.Body, test.Secret))
+				status = rec.Code
+			}
+			if status != test.ExpectStatus {
+				t.Errorf("unexpected status: expected %d, got %d", test.ExpectStatus, status)
+			}
+
+			recorded, err := deliveries.List(context.Background(), store.WebhookDeliveryFilter{}, 0)
+			if err != nil {
+				t.Fatalf("cannot list deliveries: %v", err)
+			}
+			if len(recorded) != test.Recorded {
+				t.Fatalf("unexpected number of recorded deliveries: expected %d, got %d", test.Recorded, len(recorded))
+			}
+			for _, d := range recorded {
+				// listing deliveries omits their payload
+				d, err := deliveries.Get(context.Background(), d.ID)
+				if err != nil {
+					t.Fatalf("cannot get delivery: %v", err)
+				}
+				if d.Verified != test.Verified {
+					t.Errorf("unexpected verified: expected %v, got %v", test.Verified, d.Verified)
+				}
+				if d.PayloadSize != len(test.Body) || d.Digest == "" {
+					t.Errorf("delivery lacks metadata: size %d, digest %q", d.PayloadSize, d.Digest)
+				}
+				if d.Verified {
+					if string(d.Payload) != test.Body || d.Headers["X-Github-Event"] != "ping" || d.Repository != "32leaves/werft" {
+						t.Errorf("verified delivery lacks payload or headers: %+v", d)
+					}
+					continue
+				}
+				if len(d.Payload) != 0 || len(d.Headers) != 0 || d.Repository != "" {
+					t.Errorf("unverified delivery must not keep payload or headers: %+v", d)
+				}
+			}
+		})
+	}
+}
+
+const testPullRequestPayload = `{"action":"opened","number":1,"pull_request":{"number":1,"state":"open"},"repository":{"name":"werft","full_name":"32leaves/werft","owner":{"login":"32leaves"}}}`
+
+func TestRedeliverWebhook(t *testing.T) {
+	srv := testService(werft.AnonymousFull)
+	srv.Repositories = store.NewInMemoryRepositories()
+	srv.WebhookDeliveries = store.NewInMemoryWebhookDeliveries()
+	srv.GitHub = werft.GitHubSetup{WebhookSecret: []byte("secret")}
+
+	// record deliveries to redeliver: one with and one without a valid signature
+	deliver := func(secret string) *v1.WebhookDelivery {
+		rec := httptest.NewRecorder()
+		srv.HandleGithubWebhook(rec, testWebhook("pull_request", testPullRequestPayload, secret))
+		resp, err := srv.ListWebhookDeliveries(bearer("admin-secret"), &v1.ListWebhookDeliveriesRequest{Limit: 1})
+		if err != nil || len(resp.Deliveries) != 1 {
+			t.Fatalf("cannot list deliveries: %v", err)
+		}
+		return resp.Deliveries[0]
+	}
+	verified := deliver("secret")
+	unverified := deliver("not-the-secret")
+	if !verified.Verified || unverified.Verified {
+		t.Fatalf("unexpected deliveries: %v, %v", verified, unverified)
+	}
+
+	tests := []struct {
+		Name  string
+		Token string
+		ID    string
+		Code  codes.Code
+	}{
+		{Name: "verified delivery", Token: "admin-secret", ID: verified.Id},
+		// unlike redeliveries from GitHub, those through the API are never deduplicated
+		{Name: "repeated redelivery", Token: "admin-secret", ID: verified.Id},
+		{Name: "unverified delivery", Token: "admin-secret", ID: unverified.Id, Code: codes.FailedPrecondition},
+		{Name: "unknown delivery", Token: "admin-secret", ID: "does-not-exist", Code: codes.NotFound},
+		{Name: "not an admin", Token: "ci-secret", ID: verified.Id, Code: codes.PermissionDenied},
+		{Name: "anonymous", ID: verified.Id, Code: codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.RedeliverWebhook(bearer(test.Token), &v1.RedeliverWebhookRequest{Id: test.ID})
+			if code := status.Code(err); code != test.Code {
+				t.Fatalf("unexpected code %v, expected %v: %v", code, test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+
+			d := resp.Delivery
+			if d.Id == verified.Id || d.RedeliveryOf != verified.Id || d.DeliveryId != verified.DeliveryId {
+				t.Errorf("redelivery does not refer to the original delivery: %v", d)
+			}
+			if !d.Verified || d.Repository != "32leaves/werft" || d.SkipReason != verified.SkipReason {
+				t.Errorf("redelivery differs from the original delivery: %v, original %v", d, verified)
+			}
+
+			// the redelivery is recorded alongside the original delivery and keeps its payload
+			stored, err := srv.GetWebhookDelivery(bearer("admin-secret"), &v1.GetWebhookDeliveryRequest{Id: d.Id})
+			if err != nil {
+				t.Fatalf("redelivery was not recorded: %v", err)
+			}
+			if string(stored.Payload) != testPullRequestPayload {
+				t.Errorf("redelivery does not keep the payload: %s", stored.Payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	eventTypePush        = "push"
+	eventTypePullRequest = "pull_request"
+)
+
+// eventPullRequest are the fields of a pull request job specs can use. We decode them ourselves rather than using
+// go-github, because the version we use does not know about draft pull requests.
+type eventPullRequest struct {
+	Number int  `json:"number"`
+	Draft  bool `json:"draft"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// parseEventPullRequest decodes the pull request of a pull_request webhook payload
+func parseEventPullRequest(payload []byte) (*eventPullRequest, error) {
+	var evt struct {
+		PullRequest *eventPullRequest `json:"pull_request"`
+	}
+	err := json.Unmarshal(payload, &evt)
+	if err != nil {
+		return nil, err
+	}
+	return evt.PullRequest, nil
+}
+
+// JobEvent produces the event of a job started for the pull request
+func (pr *eventPullRequest) JobEvent(typ string) *v1.JobEvent {
+	res := &v1.JobEvent{
+		Type:        typ,
+		PullRequest: int32(pr.Number),
+		Draft:       pr.Draft,
+		BaseBranch:  pr.Base.Ref,
+	}
+	for _, l := range pr.Labels {
+		res.Labels = append(res.Labels, l.Name)
+	}
+	return res
+}
+
+// pushJobEvent produces the event of a job started for a push. Pushes to the branch of a pull request carry the details
+// of that pull request.
+func (srv *Service) pushJobEvent(ctx context.Context, event *github.PushEvent) *v1.JobEvent {
+	res := &v1.JobEvent{Type: eventTypePush}
+	branch := strings.TrimPrefix(event.GetRef(), "refs/heads/")
+	if !event.GetDeleted() && branch != event.GetRef() {
+		pr, err := srv.findPullRequest(ctx, event.GetRepo().GetOwner().GetName(), event.GetRepo().GetName(), branch)
+		if err != nil {
+			log.WithError(err).WithField("ref", event.GetRef()).Debug("cannot find pull request of branch")
+		} else if pr != nil {
+			res = pr.JobEvent(eventTypePush)
+		}
+	}
+	res.CommitMessage = event.GetHeadCommit().GetMessage()
+	return res
+}
+
+// findPullRequest returns the open pull request of a branch, or nil if there is none
+func (srv *Service) findPullRequest(ctx context.Context, owner, repo, branch string) (*eventPullRequest, error) {
+	q := url.Values{"head": []string{owner + ":" + branch}, "state": []string{"open"}}
+	req, err := srv.GitHub.Client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/pulls?%s", owner, repo, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []*eventPullRequest
+	_, err = srv.GitHub.Client.Do(ctx, req, &prs)
+	if err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0], nil
+}
@@ -3,34 +3,40 @@ package werft
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"io"
-	"path/filepath"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
+	"github.com/32leaves/werft/pkg/analytics"
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/devproxy"
 	"github.com/32leaves/werft/pkg/executor"
 	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/32leaves/werft/pkg/recovery"
 	"github.com/32leaves/werft/pkg/store"
-	sprig "github.com/Masterminds/sprig/v3"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-github/github"
 	"github.com/olebedev/emitter"
 	"github.com/segmentio/textio"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8syaml "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
 var (
-	// annotationCleanupJob is set on jobs which cleanup after an actual user-started job.
+	// annotationCleanupJob is set on jobs which cleanup after an actual user-started job. Its value is the name of that job.
 	// These kind of jobs are not stored in the database and do not propagate through the system.
 	annotationCleanupJob = "cleanupJob"
 )
@@ -43,28 +49,127 @@ type Config struct {
 	// WorkspaceNodePathPrefix is the location on the node where we place the builds
 	WorkspaceNodePathPrefix string `yaml:"workspaceNodePathPrefix,omitempty"`
 
-	// Enables the webui debug proxy pointing to this address
-	DebugProxy string
+	// DevProxy serves the web UI from a development server instead of the one built into werft, s.t. frontend changes
+	// can be tried against a live werft
+	DevProxy devproxy.Config `yaml:"devProxy,omitempty"`
+
+	// RateLimit limits how frequently jobs can be started
+	RateLimit RateLimitConfig `yaml:"rateLimit,omitempty"`
+
+	// RequestLog configures the logging of API calls
+	RequestLog RequestLogConfig `yaml:"requestLog,omitempty"`
+
+	// Tokens are static API tokens, e.g. to access the admin API
+	Tokens []TokenConfig `yaml:"tokens,omitempty"`
+
+	// AnonymousAccess is what requests without a token may do: full or read-only. Defaults to full.
+	AnonymousAccess AnonymousAccess `yaml:"anonymousAccess,omitempty"`
+
+	// Cost configures the rates we compute the cost of jobs with
+	Cost CostConfig `yaml:"cost,omitempty"`
+
+	// Provenance configures the signed provenance attestations of successful jobs
+	Provenance ProvenanceConfig `yaml:"provenance,omitempty"`
+
+	// CloneCache configures the cache which speeds up the checkout of big repositories
+	CloneCache CloneCacheConfig `yaml:"cloneCache,omitempty"`
+
+	// Priority configures job priorities and the Kubernetes priority classes they map to
+	Priority PriorityConfig `yaml:"priority,omitempty"`
+
+	// Workspace configures the workspaces of jobs
+	Workspace WorkspaceConfig `yaml:"workspace,omitempty"`
+
+	// Alerting configures alerts on job failure patterns, e.g. a main branch which keeps failing
+	Alerting AlertingConfig `yaml:"alerting,omitempty"`
+
+	// Archive configures the archival of old jobs. It requires an archive to be set on the service.
+	Archive ArchiveConfig `yaml:"archive,omitempty"`
+
+	// WorkspaceGC configures the removal of workspaces which were left behind on the nodes
+	WorkspaceGC WorkspaceGCConfig `yaml:"workspaceGC,omitempty"`
+
+	// WebhookDeliveries configures how long werft keeps the webhooks it received, e.g. to find out why a push did
+	// not start a job
+	WebhookDeliveries WebhookDeliveriesConfig `yaml:"webhookDeliveries,omitempty"`
+
+	// JobNames is the naming scheme of new jobs: default (<repo>-<job spec>-<ref>.<n>) or sequential (<repo>-<branch>-<n>).
+	// Changing it does not rename existing jobs, and jobs started from a previous job keep the scheme of that job.
+	JobNames string `yaml:"jobNames,omitempty"`
+
+	// NumberGroupGC periodically removes the number groups no job refers to anymore, e.g. because their jobs were pruned.
+	// The next job of such a group starts from zero again.
+	NumberGroupGC bool `yaml:"numberGroupGC,omitempty"`
+
+	// Windows configures how jobs run on Windows nodes
+	Windows WindowsConfig `yaml:"windows,omitempty"`
+
+	// StoreRenderedJobSpecs stores the job spec of replayable jobs as it was rendered from its template, next to the
+	// template itself. Replaying a job exactly reuses the rendered job spec, which makes replays reproducible even if
+	// the template depends on the time or other things that change.
+	StoreRenderedJobSpecs bool `yaml:"storeRenderedJobSpecs,omitempty"`
+
+	// UnrestrictedTemplates lets job specs use all sprig functions, including those which read the environment of the
+	// werft server (env, expandenv) or make network requests. Only enable it if everyone who can start jobs is trusted.
+	UnrestrictedTemplates bool `yaml:"unrestrictedTemplates,omitempty"`
+
+	// Notifications are rules which send messages about the jobs of all repositories to chat tools.
+	// Repositories can configure their own notifications on top.
+	Notifications []*repoconfig.Notification `yaml:"notifications,omitempty"`
 }
 
 type jobLog struct {
 	CancelExecutorListener context.CancelFunc
 	LogStore               io.Closer
+	Graph                  *jobGraph
 }
 
 // Service ties everything together
 type Service struct {
-	Logs     store.Logs
-	Jobs     store.Jobs
-	Groups   store.NumberGroup
-	Executor *executor.Executor
-	Cutter   logcutter.Cutter
-	GitHub   GitHubSetup
+	Logs                 store.Logs
+	Jobs                 store.Jobs
+	Groups               store.NumberGroup
+	Preferences          store.Preferences
+	Deployments          store.Deployments
+	Maintenance          store.Maintenance
+	Repositories         store.Repositories
+	Attestations         store.Attestations
+	Events               store.Events
+	Stats                store.Stats
+	Archive              store.Archive
+	ServiceAccountTokens store.ServiceAccountTokens
+	WebhookDeliveries    store.WebhookDeliveries
+	Executor             *executor.Executor
+	Cutter               logcutter.Cutter
+	GitHub               GitHubSetup
+	Plugins              PluginHost
 
 	Config Config
-
-	mu          sync.RWMutex
-	logListener map[string]*jobLog
+	Info   ServerInfo
+
+	// configMu guards Config and Plugins which can change while the service runs, see ReloadConfig
+	configMu sync.RWMutex
+
+	mu                sync.RWMutex
+	logListener       map[string]*jobLog
+	durationEstimates map[string]*time.Duration
+	repoConfigs       map[string]*repoconfig.C
+	finishedCleanups  map[string]struct{}
+	// cleanupFailures counts the consecutive cleanup failures per node
+	cleanupFailures map[string]int
+
+	jobLimiter *jobRateLimiter
+	deliveries deliveryDeduplicator
+	// rejectedDeliveries limits how many webhook deliveries without a valid signature we record
+	rejectedDeliveries *rate.Limiter
+	idempotency        idempotencyKeys
+	maintenance        *v1.MaintenanceMode
+	snoozes            []*v1.NotificationSnooze
+	snoozeID           int
+	tokenUsage         tokenUsage
+
+	provenanceKey      crypto.Signer
+	workspaceSizeLimit *resource.Quantity
 
 	events emitter.Emitter
 }
@@ -84,6 +189,70 @@ func (srv *Service) Start() {
 	if srv.logListener == nil {
 		srv.logListener = make(map[string]*jobLog)
 	}
+	if srv.durationEstimates == nil {
+		srv.durationEstimates = make(map[string]*time.Duration)
+	}
+	if srv.repoConfigs == nil {
+		srv.repoConfigs = make(map[string]*repoconfig.C)
+	}
+	if srv.finishedCleanups == nil {
+		srv.finishedCleanups = make(map[string]struct{})
+	}
+	if srv.cleanupFailures == nil {
+		srv.cleanupFailures = make(map[string]int)
+	}
+	srv.jobLimiter = &jobRateLimiter{Config: srv.config().RateLimit}
+	srv.deliveries.TTL = webhookDeliveryTTL
+	srv.idempotency.TTL = idempotencyKeyTTL
+	if srv.Repositories == nil {
+		srv.Repositories = store.NewInMemoryRepositories()
+	}
+
+	if srv.Maintenance == nil {
+		srv.Maintenance = store.NewInMemoryMaintenance()
+	}
+	mode, err := srv.Maintenance.Get(context.Background())
+	if err != nil {
+		log.WithError(err).Error("cannot restore maintenance mode")
+	}
+	if mode != nil {
+		log.WithField("message", mode.Message).Warn("werft is in maintenance and does not accept new jobs")
+	}
+	srv.maintenance = mode
+
+	if srv.Attestations == nil {
+		srv.Attestations = store.NewInMemoryAttestations()
+	}
+	if srv.Stats == nil {
+		srv.Stats = store.NewInMemoryStats()
+	}
+	if srv.ServiceAccountTokens == nil {
+		srv.ServiceAccountTokens = store.NewInMemoryServiceAccountTokens()
+	}
+	if fn := srv.config().Provenance.SigningKeyPath; fn != "" {
+		srv.provenanceKey, err = provenance.LoadSigningKey(fn)
+		if err != nil {
+			log.WithError(err).Error("cannot load provenance signing key - werft will not produce provenance attestations")
+		}
+	}
+
+	srv.workspaceSizeLimit, err = parseWorkspaceSizeLimit(srv.config().Workspace)
+	if err != nil {
+		log.WithError(err).Error("invalid workspace size limit - workspaces will not be limited")
+	}
+	if srv.workspaceSizeLimit != nil {
+		go srv.measureWorkspaceUsage()
+	}
+	go srv.checkQueueTimeAlerts()
+	if srv.Archive != nil {
+		go srv.archiveJobs()
+	}
+	go srv.pruneServiceAccountTokens()
+	if srv.WebhookDeliveries != nil {
+		go srv.pruneWebhookDeliveries()
+	}
+	go srv.collectOrphanedWorkspaces()
+	go srv.collectNumberGroups()
 
 	srv.Executor.OnUpdate = func(pod *corev1.Pod, s *v1.JobStatus) {
 		var isCleanupJob bool
@@ -94,10 +263,17 @@ func (srv *Service) Start() {
 			}
 		}
 		// We ignore all status updates from cleanup jobs - they are not user triggered and we do not want them polluting the system.
+		// All we care about is whether they succeeded, the workspace usage they measured and the space they reclaimed.
 		if isCleanupJob {
+			if srv.cleanupJobFinished(pod, s) {
+				recovery.Go("cleanup", func() { srv.recordCleanupOutcome(pod, s) })
+				recovery.Go("workspace-gc", func() { srv.recordWorkspaceGC(pod) })
+			}
 			return
 		}
 
+		srv.recordEvent(pod, s)
+
 		// ensure we have logging, e.g. reestablish joblog for unknown jobs (i.e. after restart)
 		srv.ensureLogging(s)
 
@@ -106,9 +282,6 @@ func (srv *Service) Start() {
 			pw := textio.NewPrefixWriter(out, "[werft:kubernetes] ")
 			k8syaml.NewSerializer(k8syaml.DefaultMetaFactory, scheme.Scheme, nil, false).Encode(pod, pw)
 			pw.Flush()
-
-			jsonStatus, _ := json.Marshal(s)
-			fmt.Fprintf(out, "[werft:status] %s\n", jsonStatus)
 		}
 
 		// TODO make sure this runs only once, e.g. by improving the status computation s.t. we pass through starting
@@ -120,22 +293,12 @@ func (srv *Service) Start() {
 		// }
 
 		if s.Phase == v1.JobPhase_PHASE_CLEANUP {
-			srv.mu.Lock()
-			if jl, ok := srv.logListener[s.Name]; ok {
-				if jl.CancelExecutorListener != nil {
-					jl.CancelExecutorListener()
-				}
-				if jl.LogStore != nil {
-					jl.LogStore.Close()
-				}
-				srv.cleanupJobWorkspace(s)
-
-				delete(srv.logListener, s.Name)
-			}
-			srv.mu.Unlock()
-
+			srv.finishJob(pod, s)
 			return
 		}
+		srv.addEstimatedFinish(s)
+		srv.addCost(pod, s)
+
 		err = srv.Jobs.Store(context.Background(), *s)
 		if err != nil {
 			log.WithError(err).WithField("name", s.Name).Warn("cannot store job")
@@ -151,6 +314,134 @@ func (srv *Service) Start() {
 	}
 }
 
+// finishJob stops listening to the logs of a job which is done and starts everything that happens once a job is done
+func (srv *Service) finishJob(pod *corev1.Pod, s *v1.JobStatus) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	defer delete(srv.repoConfigs, s.Name)
+	defer delete(srv.durationEstimates, s.Name)
+
+	jl, ok := srv.logListener[s.Name]
+	if !ok {
+		return
+	}
+	if jl.CancelExecutorListener != nil {
+		jl.CancelExecutorListener()
+	}
+	if jl.LogStore != nil {
+		jl.LogStore.Close()
+	}
+	if hasNodeWorkspace(pod) {
+		recovery.Go("cleanup", func() { srv.cleanupJobWorkspace(s, pod.Spec.NodeName) })
+	}
+
+	if downstream, ok := pod.Annotations[executor.AnnotationDownstream]; ok && s.Conditions != nil && s.Conditions.Success {
+		recovery.Go("downstream", func() { srv.triggerDownstream(s, downstream) })
+	}
+	if s.Conditions != nil && s.Conditions.Success {
+		recovery.Go("provenance", func() { srv.recordProvenance(s) })
+	}
+	if policy, ok := pod.Annotations[executor.AnnotationRetryPolicy]; ok && s.Conditions != nil && !s.Conditions.Success {
+		recovery.Go("retry", func() { srv.retryJob(s, policy) })
+	}
+	recovery.Go("alerting", func() { srv.checkFailureAlerts(s) })
+	recovery.Go("stats", func() { srv.recordStats(s.Name) })
+
+	// the repo config is gone from the cache once we're done here
+	cfg := srv.repoConfigs[s.Name]
+	recovery.Go("notifications", func() {
+		if cfg == nil {
+			cfg = srv.downloadJobRepoConfig(context.Background(), s)
+		}
+		srv.notifyJobEvent(cfg, s, srv.finishedJobEvents(s), nil)
+	})
+
+	delete(srv.logListener, s.Name)
+}
+
+// JobUpdates returns a channel which receives every job status update until the context is canceled
+func (srv *Service) JobUpdates(ctx context.Context) <-chan *v1.JobStatus {
+	res := make(chan *v1.JobStatus)
+	go func() {
+		defer close(res)
+
+		evts := srv.events.On("job")
+		defer srv.events.Off("job", evts)
+		for {
+			select {
+			case evt, ok := <-evts:
+				if !ok {
+					return
+				}
+				if len(evt.Args) == 0 {
+					continue
+				}
+				job, ok := evt.Args[0].(*v1.JobStatus)
+				if !ok {
+					continue
+				}
+				select {
+				case res <- job:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return res
+}
+
+// addEstimatedFinish sets the estimated finish time of an unfinished job based on previous runs of the same job
+func (srv *Service) addEstimatedFinish(s *v1.JobStatus) {
+	if s.Phase == v1.JobPhase_PHASE_DONE || s.Metadata == nil || s.Metadata.Created == nil {
+		return
+	}
+
+	srv.mu.RLock()
+	estimate, ok := srv.durationEstimates[s.Name]
+	srv.mu.RUnlock()
+
+	if !ok {
+		// this is the first time we see this job - let's look at its predecessors
+		job := analytics.JobName(s.Name)
+		runs, _, err := srv.Jobs.Find(context.Background(),
+			[]*v1.FilterExpression{{Terms: []*v1.FilterTerm{{Field: "name", Value: job + ".", Operation: v1.FilterOp_OP_STARTS_WITH}}}},
+			[]*v1.OrderExpression{{Field: "created", Ascending: false}},
+			0, 20,
+		)
+		if err != nil {
+			log.WithError(err).WithField("name", s.Name).Debug("cannot estimate job duration")
+		}
+
+		var previous []v1.JobStatus
+		for _, r := range runs {
+			if r.Name != s.Name && analytics.JobName(r.Name) == job {
+				previous = append(previous, r)
+			}
+		}
+		if dur, ok := analytics.EstimateDuration(previous); ok {
+			estimate = &dur
+		}
+
+		// we remember that there is no estimate as well, so that we don't search for previous runs on every update
+		srv.mu.Lock()
+		srv.durationEstimates[s.Name] = estimate
+		srv.mu.Unlock()
+	}
+	if estimate == nil {
+		return
+	}
+
+	created, err := ptypes.Timestamp(s.Metadata.Created)
+	if err != nil {
+		return
+	}
+	s.EstimatedFinish, _ = ptypes.TimestampProto(created.Add(*estimate))
+}
+
 func (srv *Service) ensureLogging(s *v1.JobStatus) {
 	if s.Phase > v1.JobPhase_PHASE_DONE {
 		return
@@ -199,17 +490,20 @@ func (srv *Service) ensureLogging(s *v1.JobStatus) {
 	if jl.CancelExecutorListener == nil {
 		ctx, cancel := context.WithCancel(context.Background())
 		jl.CancelExecutorListener = cancel
-		go func() {
-			err := srv.listenToLogs(ctx, s.Name, srv.Executor.Logs(s.Name))
+		// the executor log starts from the beginning, hence so does the graph
+		graph := newJobGraph(true)
+		jl.Graph = graph
+		recovery.Go("logs", func() {
+			err := srv.listenToLogs(ctx, s.Name, srv.Executor.Logs(s.Name), graph)
 			if err != nil && err != context.Canceled {
 				log.WithError(err).WithField("name", s.Name).Error("cannot listen to job logs")
 				jl.CancelExecutorListener = nil
 			}
-		}()
+		})
 	}
 }
 
-func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader) error {
+func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader, graph *jobGraph) error {
 	out, err := srv.Logs.Write(name)
 	if err != nil {
 		return err
@@ -230,12 +524,64 @@ func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader
 		close(errchan)
 	}()
 
+	var (
+		progress      = &progressThrottle{Interval: progressInterval}
+		flushProgress <-chan time.Time
+		phase         = logcutter.DefaultSlice
+	)
+	// logErr becomes nil once the log ended, s.t. we stop receiving from the closed channel
+	var logErr <-chan error = errchan
+	recordProgress := func(p *v1.JobProgress) {
+		if p == nil {
+			return
+		}
+		err := srv.Executor.RegisterProgress(name, p)
+		if err != nil {
+			log.WithError(err).WithField("name", name).Warn("cannot record job progress")
+		}
+	}
 	for {
 		select {
+		case <-flushProgress:
+			flushProgress = nil
+			recordProgress(progress.Flush(time.Now()))
 		case err := <-cerrchan:
 			log.WithError(err).WithField("name", name).Warn("listening for build results failed")
 			continue
 		case evt := <-evtchan:
+			graph.Add(evt)
+			if evt.Type == v1.LogSliceType_SLICE_PHASE {
+				phase = evt.Name
+				started, err := ptypes.Timestamp(evt.Time)
+				if err != nil {
+					// the log line carries no timestamp
+					started = time.Now()
+				}
+				err = srv.Executor.RegisterStep(name, evt.Name, started)
+				if err != nil {
+					log.WithError(err).WithField("name", name).Warn("cannot record job step")
+				}
+				continue
+			}
+			if evt.Type == v1.LogSliceType_SLICE_FAIL {
+				err := srv.Executor.RegisterFailedSlice(name, &v1.LogAnchor{Phase: phase, Slice: evt.Name})
+				if err != nil {
+					log.WithError(err).WithField("name", name).Warn("cannot record failed log slice")
+				}
+				continue
+			}
+			if evt.Type == v1.LogSliceType_SLICE_PROGRESS {
+				p, err := logcutter.ParseProgress(evt)
+				if err != nil {
+					log.WithError(err).WithField("name", name).Debug("cannot parse job progress")
+					continue
+				}
+				recordProgress(progress.Offer(time.Now(), p))
+				if due, ok := progress.Due(); ok && flushProgress == nil {
+					flushProgress = time.After(time.Until(due))
+				}
+				continue
+			}
 			if evt.Type != v1.LogSliceType_SLICE_RESULT {
 				continue
 			}
@@ -263,16 +609,30 @@ func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader
 				}
 			}
 
-			err := srv.Executor.RegisterResult(name, res)
+			job, err := srv.Jobs.Get(ctx, name)
+			if err != nil {
+				log.WithError(err).WithField("name", name).Warn("cannot process job result")
+			} else if !hasResult(job, res) {
+				// the executor log starts from the beginning when we re-establish logging after a restart.
+				// We must not act on results we've seen before a second time.
+				srv.routeResult(ctx, job, res)
+				srv.recordDeployment(ctx, job, res)
+			}
+
+			err = srv.Executor.RegisterResult(name, res)
 			if err != nil {
 				log.WithError(err).WithField("name", name).WithField("res", res).Warn("cannot record job result")
 			}
-		case err := <-errchan:
+		case err := <-logErr:
 			if err != nil {
 				srv.Executor.Stop(name, fmt.Sprintf("log infrastructure failure: %s", err.Error()))
 				return xerrors.Errorf("writing logs for %s: %v", name, err)
 			}
+			// the log ended - make sure we record the progress the job reported last
+			logErr = nil
+			recordProgress(progress.Flush(time.Now()))
 		case <-ctx.Done():
+			recordProgress(progress.Flush(time.Now()))
 			return ctx.Err()
 		}
 	}
@@ -280,6 +640,12 @@ func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader
 
 // RunJob starts a build job from some context
 func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMetadata, cp ContentProvider, jobYAML []byte, canReplay bool) (status *v1.JobStatus, err error) {
+	return srv.runJob(ctx, name, metadata, cp, jobYAML, nil, canReplay)
+}
+
+// runJob starts a build job. If rendered is not nil, the job runs the rendered job spec of a previous job rather than
+// rendering its YAML template again.
+func (srv *Service) runJob(ctx context.Context, name string, metadata v1.JobMetadata, cp ContentProvider, jobYAML, rendered []byte, canReplay bool) (status *v1.JobStatus, err error) {
 	var logs io.WriteCloser
 	defer func(perr *error) {
 		if *perr == nil {
@@ -307,9 +673,18 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		<-srv.events.Emit("job", &s)
 	}(&err)
 
+	err = srv.assignPriority(&metadata)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	err = srv.assignNumber(ctx, &metadata)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot number job %s: %w", name, err)
+	}
+
 	if canReplay {
 		// save job yaml
-		err = srv.Jobs.StoreJobSpec(name, jobYAML)
+		err = srv.Jobs.StoreJobSpec(ctx, name, jobYAML)
 		if err != nil {
 			log.WithError(err).Warn("cannot store job YAML - job will not be replayable")
 		}
@@ -325,15 +700,28 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 
 	fmt.Fprintln(logs, "[preparing|PHASE] job preparation")
 
-	jobTpl, err := template.New("job").Funcs(sprig.TxtFuncMap()).Parse(string(jobYAML))
-	if err != nil {
-		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
-	}
-
 	buf := bytes.NewBuffer(nil)
-	err = jobTpl.Execute(buf, newTemplateObj(name, &metadata))
-	if err != nil {
-		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	if rendered != nil {
+		buf.Write(rendered)
+	} else {
+		var jobTpl *template.Template
+		jobTpl, err = template.New("job").Funcs(repoconfig.TemplateFuncs(srv.config().UnrestrictedTemplates)).Parse(string(jobYAML))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+
+		var out []byte
+		out, err = repoconfig.ExecuteTemplate(jobTpl, repoconfig.NewTemplateObj(name, &metadata))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		buf.Write(out)
+	}
+	if canReplay && srv.config().StoreRenderedJobSpecs {
+		serr := srv.Jobs.StoreRenderedJobSpec(ctx, name, buf.Bytes())
+		if serr != nil {
+			log.WithError(serr).Warn("cannot store rendered job spec - job will not be replayable exactly")
+		}
 	}
 
 	// we have to use the Kubernetes YAML decoder to decode the podspec
@@ -343,44 +731,132 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
 
+	gcp, fromGitHub := cp.(*GitHubContentProvider)
+	var settings *v1.RepositorySettings
+	if fromGitHub {
+		settings = srv.repositorySettings(ctx, &metadata)
+	}
+
 	podspec := jobspec.Pod
+	if podspec == nil && (len(jobspec.Steps) > 0 || len(jobspec.BuildImages) > 0) {
+		podspec = &corev1.PodSpec{}
+	}
 	if podspec == nil {
 		return nil, xerrors.Errorf("cannot handle job for %s: no podspec present", name)
 	}
 
-	nodePath := filepath.Join(srv.Config.WorkspaceNodePathPrefix, name)
-	httype := corev1.HostPathDirectoryOrCreate
-	podspec.Volumes = append(podspec.Volumes, corev1.Volume{
-		Name: "werft-workspace",
-		VolumeSource: corev1.VolumeSource{
-			HostPath: &corev1.HostPathVolumeSource{
-				Path: nodePath,
-				Type: &httype,
-			},
-		},
-	})
+	platform, err := jobspec.GetPlatform()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	// werft's own containers, e.g. the step runner, are linux containers
+	linux := platform == nil || platform.OS == repoconfig.OSLinux
+	if !linux {
+		if len(jobspec.Steps) > 0 || len(jobspec.BuildImages) > 0 {
+			return nil, xerrors.Errorf("cannot handle job for %s: jobs on %s cannot have steps or build images", name, platform)
+		}
+		if srv.config().Windows.CheckoutImage == "" {
+			return nil, xerrors.Errorf("cannot handle job for %s: no checkout image for %s is configured", name, platform)
+		}
+	}
+	if platform != nil {
+		err = srv.checkPlatformNodes(platform)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		applyPlatform(podspec, platform)
+	}
+
+	jobSteps := jobspec.Steps
+	if len(jobspec.BuildImages) > 0 {
+		// images are built once all other steps succeeded
+		builds, volumes, err := imageBuildSteps(jobspec.BuildImages, settings)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		jobSteps = append(jobSteps, builds)
+		podspec.Volumes = append(podspec.Volumes, volumes...)
+	}
+
+	var steps []executor.StepContainer
+	for _, step := range repoconfig.PlanSteps(jobSteps) {
+		c := step.Container
+		if c.WorkingDir == "" {
+			// steps work on the same workspace
+			c.WorkingDir = "/workspace"
+		}
+		podspec.Containers = append(podspec.Containers, executor.WrapStep(c, step.After))
+		steps = append(steps, executor.StepContainer{Name: c.Name, After: step.After})
+	}
+	if len(steps) > 0 {
+		podspec.Volumes = append(podspec.Volumes, executor.StepsVolume())
+		podspec.InitContainers = append(podspec.InitContainers, executor.StepRunnerContainer())
+		// a failed step must not run again - the steps which wait for it have been skipped already
+		podspec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	podspec.Volumes = append(podspec.Volumes, srv.workspaceVolume(name, linux))
+
+	// the clone cache lives on the node and is guarded using flock, which requires linux
+	if fromGitHub && srv.config().CloneCache.Enabled && linux {
+		gcp.CloneCache = cloneCacheMountPath
+		podspec.Volumes = append(podspec.Volumes, srv.cloneCacheVolume())
+	}
 
 	initcontainer, err := cp.InitContainer()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot produce init container: %w", err)
 	}
 	cpinit := *initcontainer
-	cpinit.Name = "werft-checkout"
+	cpinit.Name = executor.ContainerCheckout
+	if !linux {
+		cpinit.Image = srv.config().Windows.CheckoutImage
+	}
 	cpinit.ImagePullPolicy = corev1.PullIfNotPresent
+	// the end of the checkout output becomes the termination message which explains why the checkout failed
+	cpinit.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
 	cpinit.VolumeMounts = append(cpinit.VolumeMounts, corev1.VolumeMount{
-		Name:      "werft-workspace",
+		Name:      executor.VolumeWorkspace,
 		ReadOnly:  false,
 		MountPath: "/workspace",
 	})
+	if fromGitHub && gcp.CloneCache != "" {
+		cpinit.VolumeMounts = append(cpinit.VolumeMounts, corev1.VolumeMount{
+			Name:      cloneCacheVolume,
+			MountPath: gcp.CloneCache,
+		})
+		// the workspace borrows objects from the cache, hence all containers need it to use git
+		for i, c := range podspec.Containers {
+			podspec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				Name:      cloneCacheVolume,
+				ReadOnly:  true,
+				MountPath: gcp.CloneCache,
+			})
+		}
+	}
 	podspec.InitContainers = append(podspec.InitContainers, cpinit)
 	for i, c := range podspec.Containers {
 		podspec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
-			Name:      "werft-workspace",
+			Name:      executor.VolumeWorkspace,
 			ReadOnly:  false,
 			MountPath: "/workspace",
 		})
 	}
 
+	srv.applyPriorityClass(podspec, &metadata)
+
+	if settings != nil {
+		bindSecrets(podspec, settings)
+
+		if len(settings.Registries) > 0 {
+			secret, err := srv.ensureRegistrySecret(settings)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+			}
+			bindRegistries(podspec, secret)
+		}
+	}
+
 	// dump podspec into logs
 	pw := textio.NewPrefixWriter(logs, "[werft:template] ")
 	redactedSpec := podspec.DeepCopy()
@@ -400,7 +876,29 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 	pw.Flush()
 
 	// schedule/start job
-	status, err = srv.Executor.Start(*podspec, metadata, executor.WithName(name), executor.WithCanReplay(canReplay))
+	opts := []executor.StartOpt{executor.WithName(name), executor.WithCanReplay(canReplay)}
+	if len(steps) > 0 {
+		opts = append(opts, executor.WithSteps(steps))
+	}
+	if jobspec.OnSuccess != nil && len(jobspec.OnSuccess.Trigger) > 0 {
+		downstream, err := json.Marshal(jobspec.OnSuccess.Trigger)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		opts = append(opts, executor.WithDownstream(string(downstream)))
+	}
+	if jobspec.Retry != nil {
+		if canReplay {
+			policy, err := json.Marshal(jobspec.Retry)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+			}
+			opts = append(opts, executor.WithRetryPolicy(string(policy)))
+		} else {
+			fmt.Fprintln(logs, "[werft] this job cannot be replayed and will not be retried when it fails")
+		}
+	}
+	status, err = srv.Executor.Start(*podspec, metadata, opts...)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
@@ -416,79 +914,9 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		log.WithError(err).WithField("name", name).Warn("cannot store job status")
 	}
 
-	return status, nil
-}
-
-// cleanupWorkspace starts a cleanup job for a previously run job
-func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus) {
-	name := s.Name
-	md := v1.JobMetadata{
-		Owner:      s.Metadata.Owner,
-		Repository: s.Metadata.Repository,
-		Trigger:    v1.JobTrigger_TRIGGER_UNKNOWN,
-		Created:    ptypes.TimestampNow(),
-		Annotations: []*v1.Annotation{
-			&v1.Annotation{
-				Key:   annotationCleanupJob,
-				Value: "true",
-			},
-		},
-	}
-	nodePath := filepath.Join(srv.Config.WorkspaceNodePathPrefix, name)
-	httype := corev1.HostPathDirectoryOrCreate
-	podspec := corev1.PodSpec{
-		Volumes: []corev1.Volume{
-			corev1.Volume{
-				Name: "werft-workspace",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: nodePath,
-						Type: &httype,
-					},
-				},
-			},
-		},
-		Containers: []corev1.Container{
-			corev1.Container{
-				Name:       "cleanup",
-				Image:      "alpine:latest",
-				Command:    []string{"sh", "-c", "rm -rf *"},
-				WorkingDir: "/workspace",
-				VolumeMounts: []corev1.VolumeMount{
-					corev1.VolumeMount{
-						Name:      "werft-workspace",
-						MountPath: "/workspace",
-					},
-				},
-			},
-		},
-		RestartPolicy: corev1.RestartPolicyOnFailure,
-	}
-	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithBackoff(3), executor.WithName(fmt.Sprintf("cleanup-%s", name)))
-	if err != nil {
-		log.WithError(err).WithField("name", name).Error("cannot start cleanup job")
-	}
-}
-
-type templateObj struct {
-	Name        string
-	Owner       string
-	Repository  v1.Repository
-	Trigger     string
-	Annotations map[string]string
-}
-
-func newTemplateObj(name string, md *v1.JobMetadata) templateObj {
-	annotations := make(map[string]string)
-	for _, a := range md.Annotations {
-		annotations[a.Key] = a.Value
-	}
+	recovery.Go("notifications", func() {
+		srv.notifyJobEvent(srv.jobRepoConfig(context.Background(), status), status, []string{repoconfig.NotifyStarted}, nil)
+	})
 
-	return templateObj{
-		Name:        name,
-		Owner:       md.Owner,
-		Repository:  *md.Repository,
-		Trigger:     strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")),
-		Annotations: annotations,
-	}
+	return status, nil
 }
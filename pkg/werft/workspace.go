@@ -0,0 +1,168 @@
+package werft
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// cleanupJobPrefix is prepended to the name of a job to name the job which cleans up its workspace
+	cleanupJobPrefix = "cleanup-"
+
+	// workspaceUsageInterval is how often we measure the disk usage of size limited workspaces
+	workspaceUsageInterval = 1 * time.Minute
+)
+
+// WorkspaceConfig configures the workspaces of jobs
+type WorkspaceConfig struct {
+	// SizeLimit is the disk space a workspace may use, e.g. 10Gi. Jobs which exceed it fail.
+	// Size limited workspaces are emptyDir volumes rather than directories in the workspace node path prefix,
+	// because Kubernetes can enforce the limit on them.
+	SizeLimit string `yaml:"sizeLimit,omitempty"`
+}
+
+// workspaceVolume produces the volume which contains the workspace of a job. Workspaces on the node are removed by cleanup jobs
+// which run on linux, hence jobs which do not run on linux nodes keep their workspace in an emptyDir volume.
+func (srv *Service) workspaceVolume(name string, linux bool) corev1.Volume {
+	if srv.workspaceSizeLimit != nil || !linux {
+		return corev1.Volume{
+			Name: executor.VolumeWorkspace,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: srv.workspaceSizeLimit,
+				},
+			},
+		}
+	}
+
+	return nodeWorkspaceVolume(srv.config().WorkspaceNodePathPrefix, name)
+}
+
+// nodeWorkspaceVolume produces the volume of a workspace which lives in a directory on the node
+func nodeWorkspaceVolume(prefix, name string) corev1.Volume {
+	httype := corev1.HostPathDirectoryOrCreate
+	return corev1.Volume{
+		Name: executor.VolumeWorkspace,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: filepath.Join(prefix, name),
+				Type: &httype,
+			},
+		},
+	}
+}
+
+// hasNodeWorkspace returns true if the workspace of a job lives on the node and needs cleaning up
+func hasNodeWorkspace(pod *corev1.Pod) bool {
+	if pod == nil {
+		return true
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == executor.VolumeWorkspace {
+			return v.HostPath != nil
+		}
+	}
+	return false
+}
+
+// measureWorkspaceUsage periodically reads the disk usage of size limited workspaces from the kubelets
+// of the nodes the jobs run on. Kubernetes enforces the size limit, we just report the usage.
+func (srv *Service) measureWorkspaceUsage() {
+	tick := time.NewTicker(workspaceUsageInterval)
+	defer tick.Stop()
+	for range tick.C {
+		pods, err := srv.Executor.Client.CoreV1().Pods(srv.Executor.Config.Namespace).List(metav1.ListOptions{
+			LabelSelector: executor.LabelWerftMarker + "=true",
+		})
+		if err != nil {
+			log.WithError(err).Warn("cannot measure workspace usage")
+			continue
+		}
+
+		nodes := make(map[string]struct{})
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodRunning && pod.Spec.NodeName != "" && !hasNodeWorkspace(&pod) {
+				nodes[pod.Spec.NodeName] = struct{}{}
+			}
+		}
+		for node := range nodes {
+			usage, err := srv.nodeWorkspaceUsage(node)
+			if err != nil {
+				log.WithError(err).WithField("node", node).Warn("cannot measure workspace usage")
+				continue
+			}
+			for _, pod := range pods.Items {
+				u, ok := usage[pod.Name]
+				if !ok || pod.Annotations[executor.AnnotationWorkspaceUsage] == strconv.FormatInt(u, 10) {
+					continue
+				}
+				err := srv.Executor.RecordWorkspaceUsage(pod.Name, u)
+				if err != nil {
+					log.WithError(err).WithField("name", pod.Name).Warn("cannot record workspace usage")
+				}
+			}
+		}
+	}
+}
+
+// kubeletStatsSummary is the part of the kubelet's stats summary we care about
+type kubeletStatsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Volumes []struct {
+			Name      string `json:"name"`
+			UsedBytes *int64 `json:"usedBytes"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// nodeWorkspaceUsage returns the workspace usage in bytes of all jobs on a node by pod name
+func (srv *Service) nodeWorkspaceUsage(node string) (map[string]int64, error) {
+	raw, err := srv.Executor.Client.CoreV1().RESTClient().Get().
+		Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	var summary kubeletStatsSummary
+	err = json.Unmarshal(raw, &summary)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int64)
+	for _, p := range summary.Pods {
+		if p.PodRef.Namespace != srv.Executor.Config.Namespace {
+			continue
+		}
+		for _, v := range p.Volumes {
+			if v.Name == executor.VolumeWorkspace && v.UsedBytes != nil {
+				res[p.PodRef.Name] = *v.UsedBytes
+			}
+		}
+	}
+	return res, nil
+}
+
+// parseWorkspaceSizeLimit parses the configured workspace size limit, returning nil if there is none
+func parseWorkspaceSizeLimit(cfg WorkspaceConfig) (*resource.Quantity, error) {
+	if cfg.SizeLimit == "" {
+		return nil, nil
+	}
+	q, err := resource.ParseQuantity(cfg.SizeLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
@@ -0,0 +1,187 @@
+package werft
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// workspaceGCJobPrefix is prepended to the name of the jobs which remove orphaned workspaces from a node
+	workspaceGCJobPrefix = "workspace-gc-"
+
+	// workspaceGCInterval is how often we look for orphaned workspaces
+	workspaceGCInterval = 1 * time.Hour
+
+	// workspaceGCVolume is the name of the volume which contains all workspaces of a node
+	workspaceGCVolume = "werft-workspaces"
+)
+
+// workspaceGCScript removes all workspaces which are older than WERFT_MIN_AGE minutes and do not belong to a job in WERFT_KEEP.
+// Directories starting with a dot, e.g. the clone cache, are no workspaces. The script reports the reclaimed kilobytes as termination message.
+const workspaceGCScript = `total=0
+for dir in $(find . -mindepth 1 -maxdepth 1 -type d ! -name '.*' -mmin +$WERFT_MIN_AGE); do
+  name=${dir#./}
+  case " $WERFT_KEEP " in *" $name "*) continue ;; esac
+  kb=$(du -sk "$name" | cut -f1)
+  rm -rf "$name" && total=$((total + kb))
+done
+echo $total > /dev/termination-log`
+
+// WorkspaceGCConfig configures the removal of orphaned workspaces, e.g. those of jobs whose cleanup job never ran
+// because werft crashed. It applies to workspaces in the workspace node path prefix only.
+type WorkspaceGCConfig struct {
+	// AfterHours is the age in hours after which workspaces which belong to no job are removed from the nodes.
+	// If zero, orphaned workspaces are not removed.
+	AfterHours int `yaml:"afterHours,omitempty"`
+}
+
+// collectOrphanedWorkspaces periodically starts a job on every node which removes the workspaces no job uses anymore
+func (srv *Service) collectOrphanedWorkspaces() {
+	tick := time.NewTicker(workspaceGCInterval)
+	defer tick.Stop()
+	for {
+		hours := srv.config().WorkspaceGC.AfterHours
+		if hours > 0 && srv.config().WorkspaceNodePathPrefix != "" {
+			err := srv.startWorkspaceGC(time.Duration(hours) * time.Hour)
+			if err != nil {
+				log.WithError(err).Warn("cannot collect orphaned workspaces")
+			}
+		}
+		<-tick.C
+	}
+}
+
+// startWorkspaceGC starts a job on every ready node which removes the workspaces that are older than minAge
+// and belong to no job which still has a pod
+func (srv *Service) startWorkspaceGC(minAge time.Duration) error {
+	pods, err := srv.Executor.Client.CoreV1().Pods(srv.Executor.Config.Namespace).List(metav1.ListOptions{
+		LabelSelector: executor.LabelWerftMarker + "=true",
+	})
+	if err != nil {
+		return err
+	}
+	var keep []string
+	for _, pod := range pods.Items {
+		if strings.HasPrefix(pod.Name, workspaceGCJobPrefix) {
+			// a collection is still underway on some node - we try again next time
+			return nil
+		}
+		keep = append(keep, strings.TrimPrefix(pod.Name, cleanupJobPrefix))
+	}
+	sort.Strings(keep)
+
+	nodes, err := srv.Executor.Client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes.Items {
+		if !isNodeReady(&node) {
+			continue
+		}
+		err := srv.startNodeWorkspaceGC(node.Name, keep, minAge)
+		if err != nil {
+			log.WithError(err).WithField("node", node.Name).Warn("cannot collect orphaned workspaces")
+		}
+	}
+	return nil
+}
+
+// startNodeWorkspaceGC starts a job on a node which removes its orphaned workspaces
+func (srv *Service) startNodeWorkspaceGC(node string, keep []string, minAge time.Duration) error {
+	httype := corev1.HostPathDirectoryOrCreate
+	podspec := corev1.PodSpec{
+		NodeName: node,
+		Volumes: []corev1.Volume{
+			{
+				Name: workspaceGCVolume,
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{
+						Path: srv.config().WorkspaceNodePathPrefix,
+						Type: &httype,
+					},
+				},
+			},
+		},
+		Containers: []corev1.Container{
+			{
+				Name:       "gc",
+				Image:      "alpine:latest",
+				Command:    []string{"sh", "-c", workspaceGCScript},
+				WorkingDir: "/workspaces",
+				Env: []corev1.EnvVar{
+					{Name: "WERFT_KEEP", Value: strings.Join(keep, " ")},
+					{Name: "WERFT_MIN_AGE", Value: strconv.Itoa(int(minAge.Minutes()))},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      workspaceGCVolume,
+						MountPath: "/workspaces",
+					},
+				},
+			},
+		},
+		RestartPolicy: corev1.RestartPolicyNever,
+	}
+	md := v1.JobMetadata{
+		Owner:      "werft",
+		Repository: &v1.Repository{},
+		Trigger:    v1.JobTrigger_TRIGGER_UNKNOWN,
+		Created:    ptypes.TimestampNow(),
+		Annotations: []*v1.Annotation{
+			{Key: annotationCleanupJob, Value: "true"},
+		},
+	}
+	// node names can be too long for the job name label, hence we use their hash
+	hash := sha256.Sum256([]byte(node))
+	name := fmt.Sprintf("%s%x-%d", workspaceGCJobPrefix, hash[:4], time.Now().Unix())
+
+	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithName(name))
+	return err
+}
+
+// recordWorkspaceGC logs the disk space a workspace GC job reclaimed on its node.
+// The job reports the reclaimed space in kilobytes as its termination message.
+func (srv *Service) recordWorkspaceGC(pod *corev1.Pod) {
+	if pod == nil || !strings.HasPrefix(pod.Name, workspaceGCJobPrefix) {
+		return
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		t := cs.State.Terminated
+		if t == nil {
+			continue
+		}
+		if t.ExitCode != 0 {
+			log.WithField("node", pod.Spec.NodeName).WithField("exitCode", t.ExitCode).Warn("cannot collect orphaned workspaces")
+			continue
+		}
+		reclaimed, _ := strconv.ParseInt(strings.TrimSpace(t.Message), 10, 64)
+		if reclaimed == 0 {
+			continue
+		}
+		log.WithField("node", pod.Spec.NodeName).WithField("reclaimed", reclaimed*1024).Info("removed orphaned workspaces")
+	}
+}
+
+// isNodeReady returns true if the node is ready to run pods
+func isNodeReady(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}